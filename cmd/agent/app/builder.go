@@ -16,6 +16,7 @@ package app
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -29,6 +30,7 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/agent/app/processors"
 	"github.com/jaegertracing/jaeger/cmd/agent/app/reporter"
 	"github.com/jaegertracing/jaeger/cmd/agent/app/reporter/grpc"
+	"github.com/jaegertracing/jaeger/cmd/agent/app/reporter/spool"
 	"github.com/jaegertracing/jaeger/cmd/agent/app/reporter/tchannel"
 	"github.com/jaegertracing/jaeger/cmd/agent/app/servers"
 	"github.com/jaegertracing/jaeger/cmd/agent/app/servers/thriftudp"
@@ -237,3 +239,40 @@ func CreateCollectorProxy(
 		return nil, errors.New(fmt.Sprintf("unknown reporter type %s", string(opts.ReporterType)))
 	}
 }
+
+// WrapWithSpool wraps the reporter of the collector proxy so that batches the collectors
+// cannot accept are spooled to disk and replayed later. The proxy is returned unchanged
+// when no spool directory is configured.
+func WrapWithSpool(
+	proxy CollectorProxy,
+	opts *spool.Options,
+	logger *zap.Logger,
+	mFactory metrics.Factory,
+) (CollectorProxy, error) {
+	if opts.Directory == "" {
+		return proxy, nil
+	}
+	r, err := spool.NewReporter(proxy.GetReporter(), *opts, logger, mFactory)
+	if err != nil {
+		return nil, err
+	}
+	return &spooledCollectorProxy{CollectorProxy: proxy, reporter: r}, nil
+}
+
+type spooledCollectorProxy struct {
+	CollectorProxy
+	reporter *spool.Reporter
+}
+
+func (p *spooledCollectorProxy) GetReporter() reporter.Reporter {
+	return p.reporter
+}
+
+// Close stops the spool and closes the wrapped proxy.
+func (p *spooledCollectorProxy) Close() error {
+	p.reporter.Close()
+	if closer, ok := p.CollectorProxy.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -18,6 +18,9 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -34,6 +37,7 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/agent/app/configmanager"
 	"github.com/jaegertracing/jaeger/cmd/agent/app/reporter"
 	"github.com/jaegertracing/jaeger/cmd/agent/app/reporter/grpc"
+	"github.com/jaegertracing/jaeger/cmd/agent/app/reporter/spool"
 	"github.com/jaegertracing/jaeger/cmd/agent/app/reporter/tchannel"
 	"github.com/jaegertracing/jaeger/thrift-gen/baggage"
 	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
@@ -266,3 +270,22 @@ func TestCreateCollectorProxy_UnknownReporter(t *testing.T) {
 	assert.Nil(t, proxy)
 	assert.EqualError(t, err, "unknown reporter type ")
 }
+
+func TestWrapWithSpool(t *testing.T) {
+	proxy := fakeCollectorProxy{}
+	wrapped, err := WrapWithSpool(proxy, &spool.Options{}, zap.NewNop(), metrics.NullFactory)
+	require.NoError(t, err)
+	assert.Equal(t, proxy, wrapped)
+
+	dir, err := ioutil.TempDir("", "spool")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	wrapped, err = WrapWithSpool(proxy, &spool.Options{Directory: dir}, zap.NewNop(), metrics.NullFactory)
+	require.NoError(t, err)
+	assert.IsType(t, &spool.Reporter{}, wrapped.GetReporter())
+	assert.Equal(t, proxy.GetManager(), wrapped.GetManager())
+	require.NoError(t, wrapped.(io.Closer).Close())
+
+	_, err = WrapWithSpool(proxy, &spool.Options{Directory: "/dev/null/spool"}, zap.NewNop(), metrics.NullFactory)
+	assert.Error(t, err)
+}
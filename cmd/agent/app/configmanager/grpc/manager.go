@@ -16,7 +16,6 @@ package grpc
 
 import (
 	"context"
-	"errors"
 
 	"google.golang.org/grpc"
 
@@ -28,13 +27,15 @@ import (
 
 // SamplingManager returns sampling decisions from collector over gRPC.
 type SamplingManager struct {
-	client api_v2.SamplingManagerClient
+	client        api_v2.SamplingManagerClient
+	baggageClient api_v2.BaggageRestrictionManagerClient
 }
 
 // NewConfigManager creates gRPC sampling manager.
 func NewConfigManager(conn *grpc.ClientConn) *SamplingManager {
 	return &SamplingManager{
-		client: api_v2.NewSamplingManagerClient(conn),
+		client:        api_v2.NewSamplingManagerClient(conn),
+		baggageClient: api_v2.NewBaggageRestrictionManagerClient(conn),
 	}
 }
 
@@ -49,5 +50,9 @@ func (s *SamplingManager) GetSamplingStrategy(serviceName string) (*sampling.Sam
 
 // GetBaggageRestrictions returns baggage restrictions from collector.
 func (s *SamplingManager) GetBaggageRestrictions(serviceName string) ([]*baggage.BaggageRestriction, error) {
-	return nil, errors.New("baggage not implemented")
+	r, err := s.baggageClient.GetBaggageRestrictions(context.Background(), &api_v2.BaggageRestrictionParameters{ServiceName: serviceName})
+	if err != nil {
+		return nil, err
+	}
+	return jaeger.ConvertBaggageRestrictionsFromDomain(r), nil
 }
@@ -24,6 +24,7 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+	"github.com/jaegertracing/jaeger/thrift-gen/baggage"
 	"github.com/jaegertracing/jaeger/thrift-gen/sampling"
 )
 
@@ -54,10 +55,29 @@ func TestSamplingManager_GetSamplingStrategy_error(t *testing.T) {
 }
 
 func TestSamplingManager_GetBaggageRestrictions(t *testing.T) {
-	manager := NewConfigManager(nil)
+	s, addr := initializeGRPCTestServer(t, func(s *grpc.Server) {
+		api_v2.RegisterBaggageRestrictionManagerServer(s, &mockBaggageHandler{})
+	})
+	conn, err := grpc.Dial(addr.String(), grpc.WithInsecure())
+	//lint:ignore SA5001 don't care about errors
+	defer conn.Close()
+	require.NoError(t, err)
+	defer s.GracefulStop()
+	manager := NewConfigManager(conn)
+	rest, err := manager.GetBaggageRestrictions("foo")
+	require.NoError(t, err)
+	assert.Equal(t, []*baggage.BaggageRestriction{{BaggageKey: "foo", MaxValueLength: 10}}, rest)
+}
+
+func TestSamplingManager_GetBaggageRestrictions_error(t *testing.T) {
+	conn, err := grpc.Dial("foo", grpc.WithInsecure())
+	//lint:ignore SA5001 don't care about errors
+	defer conn.Close()
+	require.NoError(t, err)
+	manager := NewConfigManager(conn)
 	rest, err := manager.GetBaggageRestrictions("foo")
 	require.Nil(t, rest)
-	assert.EqualError(t, err, "baggage not implemented")
+	assert.Error(t, err)
 }
 
 type mockSamplingHandler struct {
@@ -67,6 +87,15 @@ func (*mockSamplingHandler) GetSamplingStrategy(context.Context, *api_v2.Samplin
 	return &api_v2.SamplingStrategyResponse{StrategyType: api_v2.SamplingStrategyType_PROBABILISTIC}, nil
 }
 
+type mockBaggageHandler struct {
+}
+
+func (*mockBaggageHandler) GetBaggageRestrictions(_ context.Context, params *api_v2.BaggageRestrictionParameters) (*api_v2.BaggageRestrictionResponse, error) {
+	return &api_v2.BaggageRestrictionResponse{
+		BaggageRestrictions: []*api_v2.BaggageRestriction{{BaggageKey: params.ServiceName, MaxValueLength: 10}},
+	}, nil
+}
+
 func initializeGRPCTestServer(t *testing.T, beforeServe func(server *grpc.Server)) (*grpc.Server, net.Addr) {
 	server := grpc.NewServer()
 	lis, err := net.Listen("tcp", "localhost:0")
@@ -0,0 +1,69 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spool
+
+import (
+	"flag"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	spoolPrefix           = "reporter.spool."
+	spoolDir              = spoolPrefix + "dir"
+	spoolMaxBytes         = spoolPrefix + "max-bytes"
+	spoolMaxBatches       = spoolPrefix + "max-batches"
+	spoolRetryInterval    = spoolPrefix + "retry-interval"
+	spoolMaxRetryInterval = spoolPrefix + "max-retry-interval"
+
+	defaultMaxBytes         = 100 * 1024 * 1024
+	defaultMaxBatches       = 10000
+	defaultRetryInterval    = time.Second
+	defaultMaxRetryInterval = time.Minute
+)
+
+// Options holds the configuration of the on-disk spool.
+type Options struct {
+	// Directory where undelivered batches are kept; the spool is disabled when empty.
+	Directory string
+	// MaxBytes is the maximum total size of the spooled batches, 0 means unlimited.
+	MaxBytes int64
+	// MaxBatches is the maximum number of spooled batches, 0 means unlimited.
+	MaxBatches int
+	// RetryInterval is the initial delay between replay attempts.
+	RetryInterval time.Duration
+	// MaxRetryInterval caps the exponential backoff between replay attempts.
+	MaxRetryInterval time.Duration
+}
+
+// AddFlags adds flags for Options.
+func AddFlags(flags *flag.FlagSet) {
+	flags.String(spoolDir, "", "Directory where batches that could not be delivered to the collectors are spooled until they can be replayed. The spool is disabled if empty.")
+	flags.Int64(spoolMaxBytes, defaultMaxBytes, "Maximum total size in bytes of the spooled batches; the oldest batches are evicted first (0 means unlimited).")
+	flags.Int(spoolMaxBatches, defaultMaxBatches, "Maximum number of spooled batches; the oldest batches are evicted first (0 means unlimited).")
+	flags.Duration(spoolRetryInterval, defaultRetryInterval, "Initial interval between attempts to replay spooled batches.")
+	flags.Duration(spoolMaxRetryInterval, defaultMaxRetryInterval, "Maximum interval between attempts to replay spooled batches.")
+}
+
+// InitFromViper initializes Options with properties retrieved from Viper.
+func (o *Options) InitFromViper(v *viper.Viper) *Options {
+	o.Directory = v.GetString(spoolDir)
+	o.MaxBytes = v.GetInt64(spoolMaxBytes)
+	o.MaxBatches = v.GetInt(spoolMaxBatches)
+	o.RetryInterval = v.GetDuration(spoolRetryInterval)
+	o.MaxRetryInterval = v.GetDuration(spoolMaxRetryInterval)
+	return o
+}
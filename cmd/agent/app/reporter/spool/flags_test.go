@@ -0,0 +1,51 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spool
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindFlags(t *testing.T) {
+	v := viper.New()
+	command := cobra.Command{}
+	flags := &flag.FlagSet{}
+	AddFlags(flags)
+	command.PersistentFlags().AddGoFlagSet(flags)
+	v.BindPFlags(command.PersistentFlags())
+
+	err := command.ParseFlags([]string{
+		"--reporter.spool.dir=/var/spool/jaeger",
+		"--reporter.spool.max-bytes=1024",
+		"--reporter.spool.max-retry-interval=30s",
+	})
+	require.NoError(t, err)
+
+	opts := new(Options).InitFromViper(v)
+	assert.Equal(t, Options{
+		Directory:        "/var/spool/jaeger",
+		MaxBytes:         1024,
+		MaxBatches:       defaultMaxBatches,
+		RetryInterval:    time.Second,
+		MaxRetryInterval: 30 * time.Second,
+	}, *opts)
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spool
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const batchFileSuffix = ".batch"
+
+var errBatchTooLarge = errors.New("batch exceeds the spool size limit")
+
+// diskQueue is a bounded FIFO of serialized batches stored one file per batch.
+// File names carry a monotonically increasing sequence number, so the queue
+// survives restarts of the agent.
+type diskQueue struct {
+	sync.Mutex
+	dir        string
+	maxBytes   int64
+	maxBatches int
+
+	entries []queueEntry // oldest first
+	bytes   int64
+	nextSeq uint64
+}
+
+type queueEntry struct {
+	seq  uint64
+	size int64
+}
+
+// openDiskQueue opens the queue in dir, creating the directory if needed and
+// picking up batches spooled by a previous run.
+func openDiskQueue(dir string, maxBytes int64, maxBatches int) (*diskQueue, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrap(err, "cannot create spool directory")
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read spool directory")
+	}
+	q := &diskQueue{dir: dir, maxBytes: maxBytes, maxBatches: maxBatches}
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || !strings.HasSuffix(name, batchFileSuffix) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, batchFileSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		q.entries = append(q.entries, queueEntry{seq: seq, size: f.Size()})
+		q.bytes += f.Size()
+	}
+	sort.Slice(q.entries, func(i, j int) bool { return q.entries[i].seq < q.entries[j].seq })
+	if len(q.entries) > 0 {
+		q.nextSeq = q.entries[len(q.entries)-1].seq + 1
+	}
+	return q, nil
+}
+
+// push appends a batch to the queue, evicting the oldest batches as needed to
+// stay within the limits. It returns the number of evicted batches.
+func (q *diskQueue) push(data []byte) (int, error) {
+	size := int64(len(data))
+	if q.maxBytes > 0 && size > q.maxBytes {
+		return 0, errBatchTooLarge
+	}
+	q.Lock()
+	defer q.Unlock()
+	evicted := 0
+	for len(q.entries) > 0 && q.full(size) {
+		if err := q.removeHead(); err != nil {
+			return evicted, err
+		}
+		evicted++
+	}
+	seq := q.nextSeq
+	path := q.path(seq)
+	// write to a temporary file first so that a crash never leaves a truncated batch behind
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0644); err != nil {
+		os.Remove(tmpPath)
+		return evicted, errors.Wrap(err, "cannot write spooled batch")
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return evicted, errors.Wrap(err, "cannot write spooled batch")
+	}
+	q.nextSeq++
+	q.entries = append(q.entries, queueEntry{seq: seq, size: size})
+	q.bytes += size
+	return evicted, nil
+}
+
+// peek returns the oldest batch without removing it from the queue. The last
+// return value is false when the queue is empty. A batch that cannot be read
+// is dropped from the queue and reported as an error.
+func (q *diskQueue) peek() (uint64, []byte, bool, error) {
+	q.Lock()
+	defer q.Unlock()
+	if len(q.entries) == 0 {
+		return 0, nil, false, nil
+	}
+	seq := q.entries[0].seq
+	data, err := ioutil.ReadFile(q.path(seq))
+	if err != nil {
+		q.removeHead()
+		return seq, nil, true, errors.Wrap(err, "cannot read spooled batch")
+	}
+	return seq, data, true, nil
+}
+
+// remove deletes the batch with the given sequence number if it is still the
+// oldest one; it may have been evicted since it was peeked.
+func (q *diskQueue) remove(seq uint64) error {
+	q.Lock()
+	defer q.Unlock()
+	if len(q.entries) == 0 || q.entries[0].seq != seq {
+		return nil
+	}
+	return q.removeHead()
+}
+
+// stats returns the number and total size of the spooled batches.
+func (q *diskQueue) stats() (int, int64) {
+	q.Lock()
+	defer q.Unlock()
+	return len(q.entries), q.bytes
+}
+
+func (q *diskQueue) full(size int64) bool {
+	if q.maxBatches > 0 && len(q.entries) >= q.maxBatches {
+		return true
+	}
+	return q.maxBytes > 0 && q.bytes+size > q.maxBytes
+}
+
+func (q *diskQueue) removeHead() error {
+	head := q.entries[0]
+	q.entries = q.entries[1:]
+	q.bytes -= head.size
+	if err := os.Remove(q.path(head.seq)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "cannot remove spooled batch")
+	}
+	return nil
+}
+
+func (q *diskQueue) path(seq uint64) string {
+	return filepath.Join(q.dir, fmt.Sprintf("%020d%s", seq, batchFileSuffix))
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spool
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "spool")
+	require.NoError(t, err)
+	return dir
+}
+
+func pop(t *testing.T, q *diskQueue) string {
+	seq, data, ok, err := q.peek()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NoError(t, q.remove(seq))
+	return string(data)
+}
+
+func TestDiskQueueFIFO(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	q, err := openDiskQueue(dir, 0, 0)
+	require.NoError(t, err)
+	for _, s := range []string{"a", "bb", "ccc"} {
+		evicted, err := q.push([]byte(s))
+		require.NoError(t, err)
+		assert.Equal(t, 0, evicted)
+	}
+	length, size := q.stats()
+	assert.Equal(t, 3, length)
+	assert.EqualValues(t, 6, size)
+
+	assert.Equal(t, "a", pop(t, q))
+	assert.Equal(t, "bb", pop(t, q))
+	assert.Equal(t, "ccc", pop(t, q))
+	_, _, ok, err := q.peek()
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 0)
+}
+
+func TestDiskQueueEviction(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxBytes   int64
+		maxBatches int
+		expected   []string
+		evicted    int
+	}{
+		{name: "max batches", maxBatches: 2, expected: []string{"bb", "ccc"}, evicted: 1},
+		{name: "max bytes", maxBytes: 4, expected: []string{"ccc"}, evicted: 2},
+		{name: "unlimited", expected: []string{"a", "bb", "ccc"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := tempDir(t)
+			defer os.RemoveAll(dir)
+
+			q, err := openDiskQueue(dir, test.maxBytes, test.maxBatches)
+			require.NoError(t, err)
+			evicted := 0
+			for _, s := range []string{"a", "bb", "ccc"} {
+				n, err := q.push([]byte(s))
+				require.NoError(t, err)
+				evicted += n
+			}
+			assert.Equal(t, test.evicted, evicted)
+			for _, s := range test.expected {
+				assert.Equal(t, s, pop(t, q))
+			}
+			length, size := q.stats()
+			assert.Equal(t, 0, length)
+			assert.EqualValues(t, 0, size)
+		})
+	}
+}
+
+func TestDiskQueueBatchTooLarge(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	q, err := openDiskQueue(dir, 2, 0)
+	require.NoError(t, err)
+	_, err = q.push([]byte("abc"))
+	assert.Equal(t, errBatchTooLarge, err)
+}
+
+func TestDiskQueueReopen(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	q, err := openDiskQueue(dir, 0, 0)
+	require.NoError(t, err)
+	for _, s := range []string{"a", "bb"} {
+		_, err := q.push([]byte(s))
+		require.NoError(t, err)
+	}
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "unrelated.txt"), []byte("x"), 0644))
+
+	q, err = openDiskQueue(dir, 0, 0)
+	require.NoError(t, err)
+	length, size := q.stats()
+	assert.Equal(t, 2, length)
+	assert.EqualValues(t, 3, size)
+	_, err = q.push([]byte("ccc"))
+	require.NoError(t, err)
+	assert.Equal(t, "a", pop(t, q))
+	assert.Equal(t, "bb", pop(t, q))
+	assert.Equal(t, "ccc", pop(t, q))
+}
+
+func TestDiskQueueUnreadableBatch(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	q, err := openDiskQueue(dir, 0, 0)
+	require.NoError(t, err)
+	_, err = q.push([]byte("a"))
+	require.NoError(t, err)
+	_, err = q.push([]byte("bb"))
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(q.path(0)))
+
+	_, _, ok, err := q.peek()
+	assert.True(t, ok)
+	assert.Error(t, err)
+	assert.Equal(t, "bb", pop(t, q))
+}
+
+func TestDiskQueueRemoveEvicted(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	q, err := openDiskQueue(dir, 0, 1)
+	require.NoError(t, err)
+	_, err = q.push([]byte("a"))
+	require.NoError(t, err)
+	seq, _, _, err := q.peek()
+	require.NoError(t, err)
+	_, err = q.push([]byte("bb"))
+	require.NoError(t, err)
+
+	// removing a batch that was evicted meanwhile must not drop the newer one
+	require.NoError(t, q.remove(seq))
+	assert.Equal(t, "bb", pop(t, q))
+}
+
+func TestOpenDiskQueueError(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "file")
+	require.NoError(t, ioutil.WriteFile(file, []byte("x"), 0644))
+	_, err := openDiskQueue(file, 0, 0)
+	assert.Error(t, err)
+}
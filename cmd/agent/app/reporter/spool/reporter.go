@@ -0,0 +1,200 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spool
+
+import (
+	"sync"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/agent/app/reporter"
+	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
+	"github.com/jaegertracing/jaeger/thrift-gen/zipkincore"
+)
+
+type spoolMetrics struct {
+	// Number of batches written to the spool after a failed submission
+	BatchesSpooled metrics.Counter `metric:"batches.spooled"`
+
+	// Number of spooled batches successfully submitted to collector
+	BatchesReplayed metrics.Counter `metric:"batches.replayed"`
+
+	// Number of spooled batches evicted to make room for newer ones
+	BatchesEvicted metrics.Counter `metric:"batches.evicted"`
+
+	// Number of batches lost because they could not be written to or read from the spool
+	BatchesDropped metrics.Counter `metric:"batches.dropped"`
+
+	// Number of batches currently in the spool
+	QueueLength metrics.Gauge `metric:"queue_length"`
+
+	// Total size in bytes of the batches currently in the spool
+	QueueBytes metrics.Gauge `metric:"queue_bytes"`
+}
+
+// Reporter is a reporter.Reporter decorator which writes Jaeger batches that
+// the wrapped reporter fails to submit to a bounded on-disk queue, and replays
+// them in the background, with exponential backoff, until the collector accepts them.
+// Zipkin batches are passed through as is.
+type Reporter struct {
+	wrapped          reporter.Reporter
+	queue            *diskQueue
+	logger           *zap.Logger
+	metrics          spoolMetrics
+	retryInterval    time.Duration
+	maxRetryInterval time.Duration
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewReporter creates a spooling Reporter and starts replaying the batches
+// left in the spool directory by a previous run.
+func NewReporter(wrapped reporter.Reporter, opts Options, logger *zap.Logger, mFactory metrics.Factory) (*Reporter, error) {
+	queue, err := openDiskQueue(opts.Directory, opts.MaxBytes, opts.MaxBatches)
+	if err != nil {
+		return nil, err
+	}
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = defaultRetryInterval
+	}
+	if opts.MaxRetryInterval < opts.RetryInterval {
+		opts.MaxRetryInterval = opts.RetryInterval
+	}
+	r := &Reporter{
+		wrapped:          wrapped,
+		queue:            queue,
+		logger:           logger,
+		retryInterval:    opts.RetryInterval,
+		maxRetryInterval: opts.MaxRetryInterval,
+		stopCh:           make(chan struct{}),
+	}
+	metrics.Init(&r.metrics, mFactory.Namespace(metrics.NSOptions{Name: "reporter"}).Namespace(metrics.NSOptions{Name: "spool"}), nil)
+	r.updateQueueMetrics()
+	r.wg.Add(1)
+	go r.replayLoop()
+	return r, nil
+}
+
+// EmitZipkinBatch implements EmitZipkinBatch() of Reporter
+func (r *Reporter) EmitZipkinBatch(spans []*zipkincore.Span) error {
+	return r.wrapped.EmitZipkinBatch(spans)
+}
+
+// EmitBatch implements EmitBatch() of Reporter. A batch the wrapped reporter
+// fails to submit is spooled, and the error is only returned if spooling fails as well.
+func (r *Reporter) EmitBatch(batch *jaeger.Batch) error {
+	err := r.wrapped.EmitBatch(batch)
+	if err == nil || batch == nil {
+		return err
+	}
+	if spoolErr := r.spool(batch); spoolErr != nil {
+		r.logger.Error("Could not spool batch", zap.Error(spoolErr))
+		r.metrics.BatchesDropped.Inc(1)
+		return err
+	}
+	return nil
+}
+
+// Close stops replaying the spooled batches. Batches still in the spool are
+// kept on disk and replayed by the next instance.
+func (r *Reporter) Close() error {
+	close(r.stopCh)
+	r.wg.Wait()
+	return nil
+}
+
+func (r *Reporter) spool(batch *jaeger.Batch) error {
+	data, err := thrift.NewTSerializer().Write(batch)
+	if err != nil {
+		return err
+	}
+	evicted, err := r.queue.push(data)
+	r.metrics.BatchesEvicted.Inc(int64(evicted))
+	if err == nil {
+		r.metrics.BatchesSpooled.Inc(1)
+	}
+	r.updateQueueMetrics()
+	return err
+}
+
+func (r *Reporter) replayLoop() {
+	defer r.wg.Done()
+	backoff := r.retryInterval
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	for {
+		select {
+		case <-r.stopCh:
+			return
+		case <-timer.C:
+		}
+		if err := r.replay(); err != nil {
+			backoff *= 2
+			if backoff > r.maxRetryInterval {
+				backoff = r.maxRetryInterval
+			}
+			r.logger.Debug("Could not replay spooled batches", zap.Duration("retry-in", backoff), zap.Error(err))
+		} else {
+			backoff = r.retryInterval
+		}
+		timer.Reset(backoff)
+	}
+}
+
+// replay submits the spooled batches oldest first until the spool is drained
+// or a submission fails.
+func (r *Reporter) replay() error {
+	defer r.updateQueueMetrics()
+	for {
+		select {
+		case <-r.stopCh:
+			return nil
+		default:
+		}
+		seq, data, ok, err := r.queue.peek()
+		if !ok {
+			return nil
+		}
+		if err != nil {
+			r.logger.Error("Dropping spooled batch", zap.Error(err))
+			r.metrics.BatchesDropped.Inc(1)
+			continue
+		}
+		batch := &jaeger.Batch{}
+		if err := thrift.NewTDeserializer().Read(batch, data); err != nil {
+			r.logger.Error("Dropping corrupted spooled batch", zap.Error(err))
+			r.metrics.BatchesDropped.Inc(1)
+			r.queue.remove(seq)
+			continue
+		}
+		if err := r.wrapped.EmitBatch(batch); err != nil {
+			return err
+		}
+		r.metrics.BatchesReplayed.Inc(1)
+		if err := r.queue.remove(seq); err != nil {
+			r.logger.Error("Could not remove replayed batch from spool", zap.Error(err))
+		}
+	}
+}
+
+func (r *Reporter) updateQueueMetrics() {
+	length, size := r.queue.stats()
+	r.metrics.QueueLength.Update(int64(length))
+	r.metrics.QueueBytes.Update(size)
+}
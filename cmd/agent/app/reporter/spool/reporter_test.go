@@ -0,0 +1,186 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spool
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
+	"github.com/jaegertracing/jaeger/thrift-gen/zipkincore"
+)
+
+type fakeReporter struct {
+	sync.Mutex
+	err     error
+	batches []*jaeger.Batch
+	zipkin  int
+}
+
+func (r *fakeReporter) EmitZipkinBatch(spans []*zipkincore.Span) error {
+	r.Lock()
+	defer r.Unlock()
+	r.zipkin++
+	return r.err
+}
+
+func (r *fakeReporter) EmitBatch(batch *jaeger.Batch) error {
+	r.Lock()
+	defer r.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.batches = append(r.batches, batch)
+	return nil
+}
+
+func (r *fakeReporter) setErr(err error) {
+	r.Lock()
+	defer r.Unlock()
+	r.err = err
+}
+
+func (r *fakeReporter) getBatches() []*jaeger.Batch {
+	r.Lock()
+	defer r.Unlock()
+	return r.batches
+}
+
+func makeBatch(service string) *jaeger.Batch {
+	return &jaeger.Batch{
+		Process: &jaeger.Process{ServiceName: service},
+		Spans:   []*jaeger.Span{{OperationName: "op"}},
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	for i := 0; i < 1000 && !cond(); i++ {
+		time.Sleep(time.Millisecond)
+	}
+	require.True(t, cond())
+}
+
+func TestReporterSpoolsAndReplays(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	wrapped := &fakeReporter{err: errors.New("collector unavailable")}
+	mFactory := metricstest.NewFactory(0)
+	r, err := NewReporter(wrapped, Options{
+		Directory:        dir,
+		MaxBatches:       2,
+		RetryInterval:    time.Millisecond,
+		MaxRetryInterval: 5 * time.Millisecond,
+	}, zap.NewNop(), mFactory)
+	require.NoError(t, err)
+	defer r.Close()
+
+	for _, service := range []string{"a", "b", "c"} {
+		assert.NoError(t, r.EmitBatch(makeBatch(service)))
+	}
+	mFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "reporter.spool.batches.spooled", Value: 3},
+		metricstest.ExpectedMetric{Name: "reporter.spool.batches.evicted", Value: 1},
+	)
+	assert.Empty(t, wrapped.getBatches())
+
+	wrapped.setErr(nil)
+	waitFor(t, func() bool { return len(wrapped.getBatches()) == 2 })
+	batches := wrapped.getBatches()
+	assert.Equal(t, "b", batches[0].Process.ServiceName)
+	assert.Equal(t, "c", batches[1].Process.ServiceName)
+	assert.Equal(t, "op", batches[0].Spans[0].OperationName)
+	waitFor(t, func() bool {
+		length, _ := r.queue.stats()
+		return length == 0
+	})
+	mFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "reporter.spool.batches.replayed", Value: 2},
+	)
+	mFactory.AssertGaugeMetrics(t,
+		metricstest.ExpectedMetric{Name: "reporter.spool.queue_length", Value: 0},
+		metricstest.ExpectedMetric{Name: "reporter.spool.queue_bytes", Value: 0},
+	)
+
+	assert.NoError(t, r.EmitBatch(makeBatch("d")))
+	assert.Len(t, wrapped.getBatches(), 3)
+}
+
+func TestReporterReplaysPreviousRun(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	wrapped := &fakeReporter{err: errors.New("collector unavailable")}
+	opts := Options{Directory: dir, RetryInterval: time.Hour}
+	r, err := NewReporter(wrapped, opts, zap.NewNop(), metrics.NullFactory)
+	require.NoError(t, err)
+	assert.NoError(t, r.EmitBatch(makeBatch("a")))
+	require.NoError(t, r.Close())
+
+	wrapped.setErr(nil)
+	opts.RetryInterval = time.Millisecond
+	r, err = NewReporter(wrapped, opts, zap.NewNop(), metrics.NullFactory)
+	require.NoError(t, err)
+	defer r.Close()
+	waitFor(t, func() bool { return len(wrapped.getBatches()) == 1 })
+	assert.Equal(t, "a", wrapped.getBatches()[0].Process.ServiceName)
+}
+
+func TestReporterSpoolError(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	sendErr := errors.New("collector unavailable")
+	wrapped := &fakeReporter{err: sendErr}
+	mFactory := metricstest.NewFactory(0)
+	r, err := NewReporter(wrapped, Options{Directory: dir, MaxBytes: 1, RetryInterval: time.Hour}, zap.NewNop(), mFactory)
+	require.NoError(t, err)
+	defer r.Close()
+
+	assert.Equal(t, sendErr, r.EmitBatch(makeBatch("a")))
+	mFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "reporter.spool.batches.dropped", Value: 1},
+	)
+}
+
+func TestReporterZipkinPassThrough(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	sendErr := errors.New("collector unavailable")
+	wrapped := &fakeReporter{err: sendErr}
+	r, err := NewReporter(wrapped, Options{Directory: dir}, zap.NewNop(), metrics.NullFactory)
+	require.NoError(t, err)
+	defer r.Close()
+
+	assert.Equal(t, sendErr, r.EmitZipkinBatch(nil))
+	assert.Equal(t, 1, wrapped.zipkin)
+	length, _ := r.queue.stats()
+	assert.Equal(t, 0, length)
+}
+
+func TestNewReporterError(t *testing.T) {
+	_, err := NewReporter(&fakeReporter{}, Options{Directory: "/dev/null/spool"}, zap.NewNop(), metrics.NullFactory)
+	assert.Error(t, err)
+}
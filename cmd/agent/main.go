@@ -29,6 +29,7 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/agent/app"
 	"github.com/jaegertracing/jaeger/cmd/agent/app/reporter"
 	"github.com/jaegertracing/jaeger/cmd/agent/app/reporter/grpc"
+	"github.com/jaegertracing/jaeger/cmd/agent/app/reporter/spool"
 	"github.com/jaegertracing/jaeger/cmd/agent/app/reporter/tchannel"
 	"github.com/jaegertracing/jaeger/cmd/docs"
 	"github.com/jaegertracing/jaeger/cmd/flags"
@@ -62,6 +63,10 @@ func main() {
 			if err != nil {
 				logger.Fatal("Could not create collector proxy", zap.Error(err))
 			}
+			cp, err = app.WrapWithSpool(cp, new(spool.Options).InitFromViper(v), logger, mFactory)
+			if err != nil {
+				logger.Fatal("Could not create reporter spool", zap.Error(err))
+			}
 
 			// TODO illustrate discovery service wiring
 
@@ -95,6 +100,7 @@ func main() {
 		reporter.AddFlags,
 		tchannel.AddFlags,
 		grpc.AddFlags,
+		spool.AddFlags,
 	)
 
 	if err := command.Execute(); err != nil {
@@ -39,13 +39,19 @@ import (
 	agentApp "github.com/jaegertracing/jaeger/cmd/agent/app"
 	agentRep "github.com/jaegertracing/jaeger/cmd/agent/app/reporter"
 	agentGrpcRep "github.com/jaegertracing/jaeger/cmd/agent/app/reporter/grpc"
+	agentSpool "github.com/jaegertracing/jaeger/cmd/agent/app/reporter/spool"
 	agentTchanRep "github.com/jaegertracing/jaeger/cmd/agent/app/reporter/tchannel"
 	basic "github.com/jaegertracing/jaeger/cmd/builder"
 	collectorApp "github.com/jaegertracing/jaeger/cmd/collector/app"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/baggage"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/baggage/restrictionstore"
 	collector "github.com/jaegertracing/jaeger/cmd/collector/app/builder"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/grpcserver"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/ratelimit"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/tailsampling"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/zipkin"
 	"github.com/jaegertracing/jaeger/cmd/docs"
 	"github.com/jaegertracing/jaeger/cmd/env"
@@ -55,6 +61,7 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
 	"github.com/jaegertracing/jaeger/pkg/recoveryhandler"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/pkg/version"
 	ss "github.com/jaegertracing/jaeger/plugin/sampling/strategystore"
 	"github.com/jaegertracing/jaeger/plugin/storage"
@@ -63,6 +70,7 @@ import (
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 	storageMetrics "github.com/jaegertracing/jaeger/storage/spanstore/metrics"
+	bc "github.com/jaegertracing/jaeger/thrift-gen/baggage"
 	jc "github.com/jaegertracing/jaeger/thrift-gen/jaeger"
 	sc "github.com/jaegertracing/jaeger/thrift-gen/sampling"
 	zc "github.com/jaegertracing/jaeger/thrift-gen/zipkincore"
@@ -117,19 +125,58 @@ by default uses only in-memory database.`,
 			}
 
 			strategyStoreFactory.InitFromViper(v)
-			strategyStore := initSamplingStrategyStore(strategyStoreFactory, metricsFactory, logger)
+			strategyStore, aggregator := initSamplingStrategyStore(strategyStoreFactory, metricsFactory, storageFactory, logger)
+			baggageRestrictionStore := initBaggageRestrictionStore(new(restrictionstore.Options).InitFromViper(v), metricsFactory, logger)
 
 			aOpts := new(agentApp.Builder).InitFromViper(v)
 			repOpts := new(agentRep.Options).InitFromViper(v)
 			tchanBuilder := agentTchanRep.NewBuilder().InitFromViper(v, logger)
 			grpcBuilder := agentGrpcRep.NewConnBuilder().InitFromViper(v)
+			spoolOpts := new(agentSpool.Options).InitFromViper(v)
 			cOpts := new(collector.CollectorOptions).InitFromViper(v)
 			qOpts := new(queryApp.QueryOptions).InitFromViper(v)
+			tenancyOpts := tenancy.Options{}.InitFromViper(v)
+			tenancyMgr, err := tenancy.NewManager(&tenancyOpts)
+			if err != nil {
+				logger.Fatal("Failed to init multi-tenancy", zap.Error(err))
+			}
+			var queryServiceOptions *querysvc.QueryServiceOptions
+			var tenantFactory istorage.TenantFactory
+			if tenancyMgr.Enabled {
+				if err := storageFactory.CheckMultiTenancy(); err != nil {
+					logger.Fatal("The storage cannot be used with multi-tenancy", zap.Error(err))
+				}
+				// archived traces are not kept apart per tenant, so the archive storage is not used
+				tenantFactory = storageFactory
+				queryServiceOptions = &querysvc.QueryServiceOptions{TenantFactory: tenantFactory}
+			} else {
+				queryServiceOptions = archiveOptions(storageFactory, logger)
+				queryServiceOptions.InitSpanDeleter(storageFactory, logger)
+			}
+
+			tailSamplingOpts := tailsampling.Options{}.InitFromViper(v)
+			var tailSampler *tailsampling.Sampler
+			if tailSamplingOpts.Enabled {
+				tailSampler = tailsampling.NewSampler(tailSamplingOpts, tailsampling.NewPolicies(tailSamplingOpts), metricsFactory, logger)
+			}
+			rateLimitOpts := new(ratelimit.Options).InitFromViper(v)
+			var rateLimiter *ratelimit.Limiter
+			if rateLimitOpts.QuotaFile != "" {
+				rateLimiter, err = ratelimit.NewLimiter(*rateLimitOpts, metricsFactory, logger)
+				if err != nil {
+					logger.Fatal("Failed to load ingestion quotas", zap.Error(err))
+				}
+			}
+			sanitizerOpts := new(sanitizer.Options).InitFromViper(v)
+			var spanSanitizer sanitizer.SanitizeSpan
+			if sanitizerOpts.RedactionRulesFile != "" {
+				spanSanitizer = initSanitizer(sanitizerOpts, logger)
+			}
 
-			startAgent(aOpts, repOpts, tchanBuilder, grpcBuilder, cOpts, logger, metricsFactory)
-			collectorSrv := startCollector(cOpts, spanWriter, logger, metricsFactory, strategyStore, svc.HC())
+			startAgent(aOpts, repOpts, tchanBuilder, grpcBuilder, spoolOpts, cOpts, logger, metricsFactory)
+			collectorSrv, spanProcessor := startCollector(cOpts, spanWriter, tenancyMgr, tenantFactory, tailSampler, rateLimiter, spanSanitizer, logger, metricsFactory, strategyStore, baggageRestrictionStore, aggregator, svc.HC())
 			querySrv := startQuery(
-				svc, qOpts, archiveOptions(storageFactory, logger),
+				svc, qOpts, queryServiceOptions, tenancyMgr,
 				spanReader, dependencyReader,
 				rootMetricsFactory, metricsFactory,
 			)
@@ -137,12 +184,42 @@ by default uses only in-memory database.`,
 			svc.RunAndThen(func() {
 				collectorSrv.GracefulStop()
 				querySrv.Close()
+				if rateLimiter != nil {
+					if err := rateLimiter.Close(); err != nil {
+						logger.Error("Failed to close rate limiter", zap.Error(err))
+					}
+				}
+				if tailSampler != nil {
+					if err := tailSampler.Close(); err != nil {
+						logger.Error("Failed to close tail sampler", zap.Error(err))
+					}
+				}
+				// the tail sampler flushes its buffered traces into the queue, which is drained last
+				spanProcessor.Stop()
+				if aggregator != nil {
+					if err := aggregator.Close(); err != nil {
+						logger.Error("Failed to close throughput aggregator", zap.Error(err))
+					}
+				}
+				if closer, ok := baggageRestrictionStore.(io.Closer); ok {
+					if err := closer.Close(); err != nil {
+						logger.Error("Failed to close baggage restriction store", zap.Error(err))
+					}
+				}
+				if closer, ok := strategyStore.(io.Closer); ok {
+					if err := closer.Close(); err != nil {
+						logger.Error("Failed to close sampling strategy store", zap.Error(err))
+					}
+				}
 				if closer, ok := spanWriter.(io.Closer); ok {
 					err := closer.Close()
 					if err != nil {
 						logger.Error("Failed to close span writer", zap.Error(err))
 					}
 				}
+				if err := storageFactory.Close(); err != nil {
+					logger.Error("Failed to close storage factory", zap.Error(err))
+				}
 				tracerCloser.Close()
 			})
 			return nil
@@ -162,9 +239,15 @@ by default uses only in-memory database.`,
 		agentRep.AddFlags,
 		agentTchanRep.AddFlags,
 		agentGrpcRep.AddFlags,
+		agentSpool.AddFlags,
 		collector.AddFlags,
+		restrictionstore.AddFlags,
 		queryApp.AddFlags,
 		strategyStoreFactory.AddFlags,
+		tailsampling.AddFlags,
+		ratelimit.AddFlags,
+		sanitizer.AddFlags,
+		tenancy.AddFlags,
 	)
 
 	if err := command.Execute(); err != nil {
@@ -178,6 +261,7 @@ func startAgent(
 	repOpts *agentRep.Options,
 	tchanBuilder *agentTchanRep.Builder,
 	grpcBuilder *agentGrpcRep.ConnBuilder,
+	spoolOpts *agentSpool.Options,
 	cOpts *collector.CollectorOptions,
 	logger *zap.Logger,
 	baseFactory metrics.Factory,
@@ -189,6 +273,10 @@ func startAgent(
 	if err != nil {
 		logger.Fatal("Could not create collector proxy", zap.Error(err))
 	}
+	cp, err = agentApp.WrapWithSpool(cp, spoolOpts, logger, metricsFactory)
+	if err != nil {
+		logger.Fatal("Could not create reporter spool", zap.Error(err))
+	}
 
 	agent, err := b.CreateAgent(cp, logger, baseFactory)
 	if err != nil {
@@ -204,11 +292,18 @@ func startAgent(
 func startCollector(
 	cOpts *collector.CollectorOptions,
 	spanWriter spanstore.Writer,
+	tenancyMgr *tenancy.Manager,
+	tenantFactory istorage.TenantFactory,
+	tailSampler *tailsampling.Sampler,
+	rateLimiter *ratelimit.Limiter,
+	spanSanitizer sanitizer.SanitizeSpan,
 	logger *zap.Logger,
 	baseFactory metrics.Factory,
 	strategyStore strategystore.StrategyStore,
+	baggageRestrictionStore restrictionstore.RestrictionStore,
+	aggregator strategystore.Aggregator,
 	hc *healthcheck.HealthCheck,
-) *grpc.Server {
+) (*grpc.Server, collectorApp.SpanProcessor) {
 	metricsFactory := baseFactory.Namespace(metrics.NSOptions{Name: "collector", Tags: nil})
 
 	spanBuilder, err := collector.NewSpanHandlerBuilder(
@@ -220,8 +315,24 @@ func startCollector(
 	if err != nil {
 		logger.Fatal("Unable to set up builder", zap.Error(err))
 	}
+	if tenancyMgr.Enabled {
+		spanBuilder.WithTenancy(tenancyMgr, tenantFactory.CreateTenantSpanWriter)
+	}
+	if tailSampler != nil {
+		spanBuilder.WithTailSampler(tailSampler)
+	}
+	if rateLimiter != nil {
+		spanBuilder.WithRateLimiter(rateLimiter)
+	}
+	if spanSanitizer != nil {
+		spanBuilder.WithSanitizer(spanSanitizer)
+	}
 
-	zipkinSpansHandler, jaegerBatchesHandler, grpcHandler := spanBuilder.BuildHandlers()
+	var preSave []collectorApp.ProcessSpan
+	if aggregator != nil {
+		preSave = append(preSave, collectorApp.HandleRootSpan(aggregator, logger))
+	}
+	zipkinSpansHandler, jaegerBatchesHandler, grpcHandler, importHandler, spanProcessor := spanBuilder.BuildHandlers(preSave...)
 
 	{
 		ch, err := tchannel.NewChannel("jaeger-collector", &tchannel.ChannelOptions{})
@@ -233,6 +344,9 @@ func startCollector(
 		server.Register(jc.NewTChanCollectorServer(batchHandler))
 		server.Register(zc.NewTChanZipkinCollectorServer(batchHandler))
 		server.Register(sc.NewTChanSamplingManagerServer(sampling.NewHandler(strategyStore)))
+		if baggageRestrictionStore != nil {
+			server.Register(bc.NewTChanBaggageRestrictionManagerServer(baggage.NewHandler(baggageRestrictionStore)))
+		}
 		portStr := ":" + strconv.Itoa(cOpts.CollectorPort)
 		listener, err := net.Listen("tcp", portStr)
 		if err != nil {
@@ -242,7 +356,7 @@ func startCollector(
 		ch.Serve(listener)
 	}
 
-	server, err := startGRPCServer(cOpts.CollectorGRPCPort, grpcHandler, strategyStore, logger)
+	server, err := startGRPCServer(cOpts.CollectorGRPCPort, grpcHandler, strategyStore, baggageRestrictionStore, logger)
 	if err != nil {
 		logger.Fatal("Could not start gRPC collector", zap.Error(err))
 	}
@@ -251,30 +365,32 @@ func startCollector(
 		r := mux.NewRouter()
 		apiHandler := collectorApp.NewAPIHandler(jaegerBatchesHandler)
 		apiHandler.RegisterRoutes(r)
+		importHandler.RegisterRoutes(r)
 		httpPortStr := ":" + strconv.Itoa(cOpts.CollectorHTTPPort)
 		recoveryHandler := recoveryhandler.NewRecoveryHandler(logger, true)
 
-		go startZipkinHTTPAPI(logger, cOpts.CollectorZipkinHTTPPort, zipkinSpansHandler, recoveryHandler)
+		go startZipkinHTTPAPI(logger, cOpts.CollectorZipkinHTTPPort, zipkinSpansHandler, tenancyMgr, recoveryHandler)
 
 		logger.Info("Starting jaeger-collector HTTP server", zap.Int("http-port", cOpts.CollectorHTTPPort))
 		go func() {
-			if err := http.ListenAndServe(httpPortStr, recoveryHandler(r)); err != nil {
+			if err := http.ListenAndServe(httpPortStr, recoveryHandler(tenancy.ExtractTenantHTTPHandler(tenancyMgr, r))); err != nil {
 				logger.Fatal("Could not launch jaeger-collector HTTP server", zap.Error(err))
 			}
 			hc.Set(healthcheck.Unavailable)
 		}()
 	}
-	return server
+	return server, spanProcessor
 }
 
 func startGRPCServer(
 	port int,
 	handler *collectorApp.GRPCHandler,
 	samplingStore strategystore.StrategyStore,
+	baggageRestrictionStore restrictionstore.RestrictionStore,
 	logger *zap.Logger,
 ) (*grpc.Server, error) {
 	server := grpc.NewServer()
-	_, err := grpcserver.StartGRPCCollector(port, server, handler, samplingStore, logger, func(err error) {
+	_, err := grpcserver.StartGRPCCollector(port, server, handler, samplingStore, baggageRestrictionStore, logger, func(err error) {
 		logger.Fatal("gRPC collector failed", zap.Error(err))
 	})
 	if err != nil {
@@ -287,6 +403,7 @@ func startZipkinHTTPAPI(
 	logger *zap.Logger,
 	zipkinPort int,
 	zipkinSpansHandler collectorApp.ZipkinSpansHandler,
+	tenancyMgr *tenancy.Manager,
 	recoveryHandler func(http.Handler) http.Handler,
 ) {
 	if zipkinPort != 0 {
@@ -296,7 +413,7 @@ func startZipkinHTTPAPI(
 		httpPortStr := ":" + strconv.Itoa(zipkinPort)
 		logger.Info("Listening for Zipkin HTTP traffic", zap.Int("zipkin.http-port", zipkinPort))
 
-		if err := http.ListenAndServe(httpPortStr, recoveryHandler(r)); err != nil {
+		if err := http.ListenAndServe(httpPortStr, recoveryHandler(tenancy.ExtractTenantHTTPHandler(tenancyMgr, r))); err != nil {
 			logger.Fatal("Could not launch service", zap.Error(err))
 		}
 	}
@@ -306,6 +423,7 @@ func startQuery(
 	svc *flags.Service,
 	qOpts *queryApp.QueryOptions,
 	queryOpts *querysvc.QueryServiceOptions,
+	tenancyMgr *tenancy.Manager,
 	spanReader spanstore.Reader,
 	depReader dependencystore.Reader,
 	rootFactory metrics.Factory,
@@ -313,7 +431,10 @@ func startQuery(
 ) *queryApp.Server {
 	spanReader = storageMetrics.NewReadMetricsDecorator(spanReader, baseFactory.Namespace(metrics.NSOptions{Name: "query"}))
 	qs := querysvc.NewQueryService(spanReader, depReader, *queryOpts)
-	server := queryApp.NewServer(svc, qs, qOpts, opentracing.GlobalTracer())
+	server, err := queryApp.NewServer(svc, qs, qOpts, tenancyMgr, opentracing.GlobalTracer())
+	if err != nil {
+		svc.Logger.Fatal("Could not create jaeger-query service", zap.Error(err))
+	}
 	if err := server.Start(); err != nil {
 		svc.Logger.Fatal("Could not start jaeger-query service", zap.Error(err))
 	}
@@ -323,16 +444,17 @@ func startQuery(
 func initSamplingStrategyStore(
 	samplingStrategyStoreFactory *ss.Factory,
 	metricsFactory metrics.Factory,
+	samplingStoreFactory istorage.SamplingStoreFactory,
 	logger *zap.Logger,
-) strategystore.StrategyStore {
-	if err := samplingStrategyStoreFactory.Initialize(metricsFactory, logger); err != nil {
+) (strategystore.StrategyStore, strategystore.Aggregator) {
+	if err := samplingStrategyStoreFactory.Initialize(metricsFactory, samplingStoreFactory, logger); err != nil {
 		logger.Fatal("Failed to init sampling strategy store factory", zap.Error(err))
 	}
-	strategyStore, err := samplingStrategyStoreFactory.CreateStrategyStore()
+	strategyStore, aggregator, err := samplingStrategyStoreFactory.CreateStrategyStore()
 	if err != nil {
 		logger.Fatal("Failed to create sampling strategy store", zap.Error(err))
 	}
-	return strategyStore
+	return strategyStore, aggregator
 }
 
 func archiveOptions(storageFactory istorage.Factory, logger *zap.Logger) *querysvc.QueryServiceOptions {
@@ -361,3 +483,37 @@ func initTracer(metricsFactory metrics.Factory, logger *zap.Logger) io.Closer {
 	opentracing.SetGlobalTracer(tracer)
 	return closer
 }
+
+func initBaggageRestrictionStore(
+	opts *restrictionstore.Options,
+	metricsFactory metrics.Factory,
+	logger *zap.Logger,
+) restrictionstore.RestrictionStore {
+	if opts.RestrictionsFile == "" {
+		return nil
+	}
+	store, err := restrictionstore.NewFileStore(*opts, metricsFactory, logger)
+	if err != nil {
+		logger.Fatal("Failed to create baggage restriction store", zap.Error(err))
+	}
+	return store
+}
+
+func initSanitizer(opts *sanitizer.Options, logger *zap.Logger) sanitizer.SanitizeSpan {
+	rules, err := sanitizer.LoadRedactionRules(opts.RedactionRulesFile)
+	if err != nil {
+		logger.Fatal("Failed to load redaction rules", zap.Error(err))
+	}
+	var hashKey []byte
+	if opts.RedactionHashKeyFile != "" {
+		hashKey, err = sanitizer.LoadRedactionHashKey(opts.RedactionHashKeyFile)
+		if err != nil {
+			logger.Fatal("Failed to load redaction hash key", zap.Error(err))
+		}
+	}
+	redactionSanitizer, err := sanitizer.NewRedactionSanitizer(rules, hashKey)
+	if err != nil {
+		logger.Fatal("Failed to create redaction sanitizer", zap.Error(err))
+	}
+	return sanitizer.NewChainedSanitizer(redactionSanitizer)
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package baggage
+
+import (
+	"context"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/baggage/restrictionstore"
+	"github.com/jaegertracing/jaeger/model/converter/thrift/jaeger"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+)
+
+// GRPCHandler is baggage restriction handler for gRPC.
+type GRPCHandler struct {
+	store restrictionstore.RestrictionStore
+}
+
+// NewGRPCHandler creates a handler that controls baggage restrictions for services.
+func NewGRPCHandler(store restrictionstore.RestrictionStore) GRPCHandler {
+	return GRPCHandler{
+		store: store,
+	}
+}
+
+// GetBaggageRestrictions returns the baggage restrictions from store.
+func (s GRPCHandler) GetBaggageRestrictions(c context.Context, param *api_v2.BaggageRestrictionParameters) (*api_v2.BaggageRestrictionResponse, error) {
+	r, err := s.store.GetBaggageRestrictions(param.GetServiceName())
+	if err != nil {
+		return nil, err
+	}
+	return jaeger.ConvertBaggageRestrictionsToDomain(r), nil
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package baggage
+
+import (
+	"github.com/uber/tchannel-go/thrift"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/baggage/restrictionstore"
+	"github.com/jaegertracing/jaeger/thrift-gen/baggage"
+)
+
+// Handler returns baggage restrictions for specified services
+type Handler interface {
+	// GetBaggageRestrictions returns the baggage restrictions for a given service name.
+	GetBaggageRestrictions(ctx thrift.Context, serviceName string) ([]*baggage.BaggageRestriction, error)
+}
+
+type handler struct {
+	store restrictionstore.RestrictionStore
+}
+
+// NewHandler creates a handler that controls baggage restrictions for services.
+func NewHandler(store restrictionstore.RestrictionStore) Handler {
+	return &handler{
+		store: store,
+	}
+}
+
+// GetBaggageRestrictions returns the baggage restrictions for a given service name.
+func (h *handler) GetBaggageRestrictions(ctx thrift.Context, serviceName string) ([]*baggage.BaggageRestriction, error) {
+	return h.store.GetBaggageRestrictions(serviceName)
+}
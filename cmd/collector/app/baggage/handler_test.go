@@ -0,0 +1,36 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package baggage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/thrift-gen/baggage"
+)
+
+func TestHandler(t *testing.T) {
+	handler := NewHandler(mockStore{})
+	r, err := handler.GetBaggageRestrictions(nil, "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, []*baggage.BaggageRestriction{{BaggageKey: "key", MaxValueLength: 10}}, r)
+}
+
+type mockStore struct{}
+
+func (s mockStore) GetBaggageRestrictions(serviceName string) ([]*baggage.BaggageRestriction, error) {
+	return []*baggage.BaggageRestriction{{BaggageKey: "key", MaxValueLength: 10}}, nil
+}
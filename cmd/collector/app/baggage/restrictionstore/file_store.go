@@ -0,0 +1,162 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restrictionstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/pkg/reload"
+	"github.com/jaegertracing/jaeger/thrift-gen/baggage"
+)
+
+type fileStore struct {
+	// storedRestrictions holds a *storedRestrictions, replaced by every successful reload
+	storedRestrictions atomic.Value
+
+	reloader *reload.Reloader
+}
+
+type storedRestrictions struct {
+	defaultRestrictions []*baggage.BaggageRestriction
+	serviceRestrictions map[string][]*baggage.BaggageRestriction
+}
+
+// NewFileStore creates a restriction store that holds the baggage restrictions read from
+// options.RestrictionsFile. If options.ReloadInterval is positive, the file is periodically
+// checked for changes and the new restrictions are swapped in once they pass validation.
+func NewFileStore(options Options, metricsFactory metrics.Factory, logger *zap.Logger) (RestrictionStore, error) {
+	s := &fileStore{}
+	reloadOptions := reload.Options{
+		Name:   "baggage restrictions",
+		Source: options.RestrictionsFile,
+		Read: func() ([]byte, error) {
+			return readRestrictionsFile(options.RestrictionsFile)
+		},
+		Apply:    s.applyRestrictions,
+		Interval: options.ReloadInterval,
+		Logger:   logger,
+	}
+	metrics.Init(&reloadOptions.Metrics, metricsFactory.Namespace(metrics.NSOptions{Name: "baggage_restrictions"}), nil)
+	reloader, err := reload.New(reloadOptions)
+	if err != nil {
+		return nil, err
+	}
+	s.reloader = reloader
+	return s, nil
+}
+
+// GetBaggageRestrictions implements RestrictionStore#GetBaggageRestrictions.
+func (s *fileStore) GetBaggageRestrictions(serviceName string) ([]*baggage.BaggageRestriction, error) {
+	stored := s.storedRestrictions.Load().(*storedRestrictions)
+	if restrictions, ok := stored.serviceRestrictions[serviceName]; ok {
+		return restrictions, nil
+	}
+	return stored.defaultRestrictions, nil
+}
+
+// Close stops the periodic reloading of the restrictions.
+func (s *fileStore) Close() error {
+	return s.reloader.Close()
+}
+
+// applyRestrictions validates the content of the restrictions file and replaces the current restrictions with it.
+func (s *fileStore) applyRestrictions(content []byte) error {
+	restrictions, err := loadRestrictions(content)
+	if err != nil {
+		return err
+	}
+	s.storedRestrictions.Store(parseRestrictions(restrictions))
+	return nil
+}
+
+func readRestrictionsFile(restrictionsFile string) ([]byte, error) {
+	bytes, err := ioutil.ReadFile(restrictionsFile) /* nolint #nosec , this comes from an admin, not user */
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to open baggage restrictions file")
+	}
+	return bytes, nil
+}
+
+func loadRestrictions(content []byte) (*restrictions, error) {
+	var restrictions restrictions
+	if err := json.Unmarshal(content, &restrictions); err != nil {
+		return nil, errors.Wrap(err, "Failed to unmarshal baggage restrictions")
+	}
+	if err := validateRestrictions(&restrictions); err != nil {
+		return nil, errors.Wrap(err, "Invalid baggage restrictions")
+	}
+	return &restrictions, nil
+}
+
+func validateRestrictions(r *restrictions) error {
+	if err := validateRestrictionList(r.DefaultRestrictions); err != nil {
+		return errors.Wrap(err, "default restrictions")
+	}
+	services := make(map[string]struct{})
+	for _, sr := range r.ServiceRestrictions {
+		if sr.Service == "" {
+			return errors.New("service name must not be empty")
+		}
+		if _, ok := services[sr.Service]; ok {
+			return fmt.Errorf("duplicate restrictions for service %s", sr.Service)
+		}
+		services[sr.Service] = struct{}{}
+		if err := validateRestrictionList(sr.Restrictions); err != nil {
+			return errors.Wrapf(err, "service %s", sr.Service)
+		}
+	}
+	return nil
+}
+
+func validateRestrictionList(restrictions []*restriction) error {
+	for _, r := range restrictions {
+		if r.Key == "" {
+			return errors.New("baggage key must not be empty")
+		}
+		if r.MaxValueLength <= 0 {
+			return fmt.Errorf("max value length %d of baggage key %s must be positive", r.MaxValueLength, r.Key)
+		}
+	}
+	return nil
+}
+
+func parseRestrictions(r *restrictions) *storedRestrictions {
+	stored := &storedRestrictions{
+		defaultRestrictions: toThrift(r.DefaultRestrictions),
+		serviceRestrictions: make(map[string][]*baggage.BaggageRestriction),
+	}
+	for _, sr := range r.ServiceRestrictions {
+		stored.serviceRestrictions[sr.Service] = toThrift(sr.Restrictions)
+	}
+	return stored
+}
+
+func toThrift(restrictions []*restriction) []*baggage.BaggageRestriction {
+	result := make([]*baggage.BaggageRestriction, len(restrictions))
+	for i, r := range restrictions {
+		result[i] = &baggage.BaggageRestriction{
+			BaggageKey:     r.Key,
+			MaxValueLength: r.MaxValueLength,
+		}
+	}
+	return result
+}
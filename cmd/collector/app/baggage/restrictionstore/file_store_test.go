@@ -0,0 +1,150 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restrictionstore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/thrift-gen/baggage"
+)
+
+func TestFileStore(t *testing.T) {
+	_, err := NewFileStore(Options{RestrictionsFile: "fileNotFound.json"}, metrics.NullFactory, zap.NewNop())
+	assert.EqualError(t, err, "Failed to open baggage restrictions file: open fileNotFound.json: no such file or directory")
+
+	_, err = NewFileStore(Options{RestrictionsFile: "fixtures/invalid_restrictions.json"}, metrics.NullFactory, zap.NewNop())
+	assert.EqualError(t, err, "Invalid baggage restrictions: service frontend: max value length 0 of baggage key customer must be positive")
+
+	store, err := NewFileStore(Options{RestrictionsFile: "fixtures/restrictions.json"}, metrics.NullFactory, zap.NewNop())
+	require.NoError(t, err)
+	defer store.(*fileStore).Close()
+
+	r, err := store.GetBaggageRestrictions("frontend")
+	require.NoError(t, err)
+	assert.Equal(t, []*baggage.BaggageRestriction{
+		{BaggageKey: "request-id", MaxValueLength: 36},
+		{BaggageKey: "customer", MaxValueLength: 64},
+	}, r)
+
+	// an empty list of service restrictions replaces the default ones
+	r, err = store.GetBaggageRestrictions("batch")
+	require.NoError(t, err)
+	assert.Empty(t, r)
+
+	r, err = store.GetBaggageRestrictions("default")
+	require.NoError(t, err)
+	assert.Equal(t, []*baggage.BaggageRestriction{{BaggageKey: "request-id", MaxValueLength: 36}}, r)
+}
+
+func TestValidateRestrictions(t *testing.T) {
+	tests := []struct {
+		content string
+		err     string
+	}{
+		{content: `{}`},
+		{content: `[]`, err: "Failed to unmarshal baggage restrictions: json: cannot unmarshal array into Go value of type restrictionstore.restrictions"},
+		{
+			content: `{"default_restrictions": [{"key": "", "max_value_length": 1}]}`,
+			err:     "Invalid baggage restrictions: default restrictions: baggage key must not be empty",
+		},
+		{
+			content: `{"service_restrictions": [{"service": "", "restrictions": []}]}`,
+			err:     "Invalid baggage restrictions: service name must not be empty",
+		},
+		{
+			content: `{"service_restrictions": [{"service": "foo", "restrictions": []}, {"service": "foo", "restrictions": []}]}`,
+			err:     "Invalid baggage restrictions: duplicate restrictions for service foo",
+		},
+	}
+	for _, test := range tests {
+		_, err := loadRestrictions([]byte(test.content))
+		if test.err == "" {
+			assert.NoError(t, err, test.content)
+		} else {
+			assert.EqualError(t, err, test.err, test.content)
+		}
+	}
+}
+
+func TestReloadRestrictions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restrictions")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "restrictions.json")
+	writeRestrictions := func(content string) {
+		require.NoError(t, ioutil.WriteFile(file, []byte(content), 0644))
+	}
+	writeRestrictions(`{"default_restrictions": [{"key": "a", "max_value_length": 10}]}`)
+
+	metricsFactory := metricstest.NewFactory(0)
+	store, err := NewFileStore(Options{RestrictionsFile: file, ReloadInterval: time.Hour}, metricsFactory, zap.NewNop())
+	require.NoError(t, err)
+	s := store.(*fileStore)
+	defer s.Close()
+
+	r, err := store.GetBaggageRestrictions("foo")
+	require.NoError(t, err)
+	assert.Equal(t, []*baggage.BaggageRestriction{{BaggageKey: "a", MaxValueLength: 10}}, r)
+
+	writeRestrictions(`{"default_restrictions": [{"key": "b", "max_value_length": 20}]}`)
+	s.reloader.Reload()
+	r, err = store.GetBaggageRestrictions("foo")
+	require.NoError(t, err)
+	assert.Equal(t, []*baggage.BaggageRestriction{{BaggageKey: "b", MaxValueLength: 20}}, r)
+
+	// the last good restrictions are kept when the new content is invalid
+	writeRestrictions(`{"default_restrictions": [{"key": "c", "max_value_length": -1}]}`)
+	s.reloader.Reload()
+	r, err = store.GetBaggageRestrictions("foo")
+	require.NoError(t, err)
+	assert.Equal(t, []*baggage.BaggageRestriction{{BaggageKey: "b", MaxValueLength: 20}}, r)
+	metricsFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "baggage_restrictions.reloads", Tags: map[string]string{"result": "ok"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "baggage_restrictions.reloads", Tags: map[string]string{"result": "err"}, Value: 1},
+	)
+}
+
+func TestReloadRestrictionsMissingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restrictions")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "restrictions.json")
+	require.NoError(t, ioutil.WriteFile(file, []byte(`{"default_restrictions": [{"key": "a", "max_value_length": 10}]}`), 0644))
+
+	metricsFactory := metricstest.NewFactory(0)
+	store, err := NewFileStore(Options{RestrictionsFile: file}, metricsFactory, zap.NewNop())
+	require.NoError(t, err)
+	s := store.(*fileStore)
+	defer s.Close()
+
+	require.NoError(t, os.Remove(file))
+	s.reloader.Reload()
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{
+		Name: "baggage_restrictions.read-errors", Value: 1,
+	})
+	r, err := store.GetBaggageRestrictions("foo")
+	require.NoError(t, err)
+	assert.Equal(t, []*baggage.BaggageRestriction{{BaggageKey: "a", MaxValueLength: 10}}, r)
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restrictionstore
+
+import (
+	"flag"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	baggageRestrictionsFile           = "collector.baggage-restrictions-file"
+	baggageRestrictionsReloadInterval = "collector.baggage-restrictions-reload-interval"
+)
+
+// Options holds configuration for the file-backed baggage restriction store.
+type Options struct {
+	// RestrictionsFile is the path for the baggage restrictions file in JSON format
+	RestrictionsFile string
+	// ReloadInterval is the interval at which the restrictions file is checked for changes, 0 disables reloading
+	ReloadInterval time.Duration
+}
+
+// AddFlags adds flags for Options
+func AddFlags(flagSet *flag.FlagSet) {
+	flagSet.String(baggageRestrictionsFile, "", "The path for the baggage restrictions file in JSON format. The baggage restrictions are not served if empty")
+	flagSet.Duration(baggageRestrictionsReloadInterval, 0, "The interval at which the baggage restrictions file is checked for changes and reloaded. Zero value means no reloading")
+}
+
+// InitFromViper initializes Options with properties from viper
+func (opts *Options) InitFromViper(v *viper.Viper) *Options {
+	opts.RestrictionsFile = v.GetString(baggageRestrictionsFile)
+	opts.ReloadInterval = v.GetDuration(baggageRestrictionsReloadInterval)
+	return opts
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restrictionstore
+
+// restriction allows a baggage key with values of at most MaxValueLength.
+type restriction struct {
+	Key            string `json:"key"`
+	MaxValueLength int32  `json:"max_value_length"`
+}
+
+// serviceRestrictions defines the baggage restrictions of a service, they replace the default restrictions.
+type serviceRestrictions struct {
+	Service      string         `json:"service"`
+	Restrictions []*restriction `json:"restrictions"`
+}
+
+// restrictions holds the default baggage restrictions and the service specific baggage restrictions.
+type restrictions struct {
+	DefaultRestrictions []*restriction         `json:"default_restrictions"`
+	ServiceRestrictions []*serviceRestrictions `json:"service_restrictions"`
+}
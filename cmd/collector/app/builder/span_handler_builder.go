@@ -22,8 +22,11 @@ import (
 
 	basicB "github.com/jaegertracing/jaeger/cmd/builder"
 	"github.com/jaegertracing/jaeger/cmd/collector/app"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer"
 	zs "github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer/zipkin"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/zipkin"
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -33,6 +36,12 @@ type SpanHandlerBuilder struct {
 	metricsFactory metrics.Factory
 	collectorOpts  *CollectorOptions
 	spanWriter     spanstore.Writer
+
+	tenancyMgr       *tenancy.Manager
+	tenantSpanWriter app.TenantSpanWriter
+	tailSampler      app.TailSampler
+	rateLimiter      app.RateLimiter
+	sanitizer        sanitizer.SanitizeSpan
 }
 
 // NewSpanHandlerBuilder returns new SpanHandlerBuilder with configured span storage.
@@ -44,33 +53,81 @@ func NewSpanHandlerBuilder(cOpts *CollectorOptions, spanWriter spanstore.Writer,
 		logger:         options.Logger,
 		metricsFactory: options.MetricsFactory,
 		spanWriter:     spanWriter,
+		tenancyMgr:     &tenancy.Manager{},
 	}
 
 	return spanHb, nil
 }
 
-// BuildHandlers builds span handlers (Zipkin, Jaeger)
-func (spanHb *SpanHandlerBuilder) BuildHandlers() (
+// WithTenancy makes the handlers built afterwards require a tenant on every batch of spans
+// and write the spans of each tenant with the writer returned by tenantSpanWriter.
+func (spanHb *SpanHandlerBuilder) WithTenancy(tenancyMgr *tenancy.Manager, tenantSpanWriter app.TenantSpanWriter) *SpanHandlerBuilder {
+	spanHb.tenancyMgr = tenancyMgr
+	spanHb.tenantSpanWriter = tenantSpanWriter
+	return spanHb
+}
+
+// WithTailSampler makes the handlers built afterwards only write the spans of the traces kept by the tail sampler.
+func (spanHb *SpanHandlerBuilder) WithTailSampler(tailSampler app.TailSampler) *SpanHandlerBuilder {
+	spanHb.tailSampler = tailSampler
+	return spanHb
+}
+
+// WithRateLimiter makes the handlers built afterwards reject the batches of spans exceeding the ingestion quotas.
+func (spanHb *SpanHandlerBuilder) WithRateLimiter(rateLimiter app.RateLimiter) *SpanHandlerBuilder {
+	spanHb.rateLimiter = rateLimiter
+	return spanHb
+}
+
+// WithSanitizer makes the handlers built afterwards sanitize every span before it is written to storage.
+func (spanHb *SpanHandlerBuilder) WithSanitizer(sanitizer sanitizer.SanitizeSpan) *SpanHandlerBuilder {
+	spanHb.sanitizer = sanitizer
+	return spanHb
+}
+
+// BuildHandlers builds span handlers (Zipkin, Jaeger) along with the span processor they share,
+// which must be stopped on shutdown. The optional preSave processors are invoked for every span
+// right before it is written to storage.
+func (spanHb *SpanHandlerBuilder) BuildHandlers(preSave ...app.ProcessSpan) (
 	app.ZipkinSpansHandler,
 	app.JaegerBatchesHandler,
 	*app.GRPCHandler,
+	*app.ImportHandler,
+	app.SpanProcessor,
 ) {
 	hostname, _ := os.Hostname()
 	hostMetrics := spanHb.metricsFactory.Namespace(metrics.NSOptions{Name: "", Tags: map[string]string{"host": hostname}})
 
-	spanProcessor := app.NewSpanProcessor(
-		spanHb.spanWriter,
+	opts := []app.Option{
 		app.Options.ServiceMetrics(spanHb.metricsFactory),
 		app.Options.HostMetrics(hostMetrics),
 		app.Options.Logger(spanHb.logger),
 		app.Options.SpanFilter(defaultSpanFilter),
 		app.Options.NumWorkers(spanHb.collectorOpts.NumWorkers),
 		app.Options.QueueSize(spanHb.collectorOpts.QueueSize),
-	)
+		app.Options.PreSave(app.ChainedProcessSpan(preSave...)),
+	}
+	if spanHb.tenancyMgr.Enabled {
+		opts = append(opts, app.Options.TenantSpanWriter(spanHb.tenantSpanWriter))
+	}
+	if spanHb.tailSampler != nil {
+		opts = append(opts, app.Options.TailSampler(spanHb.tailSampler))
+	}
+	if spanHb.rateLimiter != nil {
+		opts = append(opts, app.Options.RateLimiter(spanHb.rateLimiter))
+	}
+	if spanHb.sanitizer != nil {
+		opts = append(opts, app.Options.Sanitizer(spanHb.sanitizer))
+	}
+	spanProcessor := app.NewSpanProcessor(spanHb.spanWriter, opts...)
+
+	zipkinSpansHandler := app.NewZipkinSpanHandler(spanHb.logger, spanProcessor, zs.NewChainedSanitizer(zs.StandardSanitizers...))
 
-	return app.NewZipkinSpanHandler(spanHb.logger, spanProcessor, zs.NewChainedSanitizer(zs.StandardSanitizers...)),
+	return zipkinSpansHandler,
 		app.NewJaegerSpanHandler(spanHb.logger, spanProcessor),
-		app.NewGRPCHandler(spanHb.logger, spanProcessor)
+		app.NewGRPCHandler(spanHb.logger, spanProcessor, spanHb.tenancyMgr),
+		app.NewImportHandler(spanHb.logger, spanProcessor, zipkinSpansHandler, zipkin.DeserializeJSONV2),
+		spanProcessor
 }
 
 func defaultSpanFilter(*model.Span) bool {
@@ -23,8 +23,13 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/cmd/builder"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/ratelimit"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/tailsampling"
 	"github.com/jaegertracing/jaeger/cmd/flags"
+	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/plugin/storage/memory"
 )
 
@@ -44,10 +49,63 @@ func TestNewSpanHandlerBuilder(t *testing.T) {
 	)
 	require.NoError(t, err)
 	assert.NotNil(t, handler)
-	zipkin, jaeger, grpc := handler.BuildHandlers()
+	zipkin, jaeger, grpc, importer, processor := handler.BuildHandlers()
 	assert.NotNil(t, zipkin)
 	assert.NotNil(t, jaeger)
 	assert.NotNil(t, grpc)
+	assert.NotNil(t, importer)
+	assert.NotNil(t, processor)
+	processor.Stop()
+
+	zipkin, jaeger, grpc, importer, processor = handler.BuildHandlers(func(span *model.Span) {})
+	assert.NotNil(t, zipkin)
+	assert.NotNil(t, jaeger)
+	assert.NotNil(t, grpc)
+	assert.NotNil(t, importer)
+	assert.NotNil(t, processor)
+	processor.Stop()
+
+	tenancyMgr := &tenancy.Manager{Enabled: true}
+	tenantStores := memory.NewFactory()
+	require.NoError(t, tenantStores.Initialize(metrics.NullFactory, zap.NewNop()))
+	zipkin, jaeger, grpc, importer, processor = handler.WithTenancy(tenancyMgr, tenantStores.CreateTenantSpanWriter).BuildHandlers()
+	assert.NotNil(t, zipkin)
+	assert.NotNil(t, jaeger)
+	assert.NotNil(t, grpc)
+	assert.NotNil(t, importer)
+	assert.NotNil(t, processor)
+	processor.Stop()
+
+	tailSampler := tailsampling.NewSampler(tailsampling.Options{}, nil, metrics.NullFactory, zap.NewNop())
+	zipkin, jaeger, grpc, importer, processor = handler.WithTailSampler(tailSampler).BuildHandlers()
+	assert.NotNil(t, zipkin)
+	assert.NotNil(t, jaeger)
+	assert.NotNil(t, grpc)
+	assert.NotNil(t, importer)
+	assert.NotNil(t, processor)
+	processor.Stop()
+	assert.NoError(t, tailSampler.Close())
+
+	rateLimiter, err := ratelimit.NewLimiter(ratelimit.Options{QuotaFile: "../ratelimit/fixtures/quotas.json"}, metrics.NullFactory, zap.NewNop())
+	require.NoError(t, err)
+	zipkin, jaeger, grpc, importer, processor = handler.WithRateLimiter(rateLimiter).BuildHandlers()
+	assert.NotNil(t, zipkin)
+	assert.NotNil(t, jaeger)
+	assert.NotNil(t, grpc)
+	assert.NotNil(t, importer)
+	assert.NotNil(t, processor)
+	processor.Stop()
+	assert.NoError(t, rateLimiter.Close())
+
+	redactionSanitizer, err := sanitizer.NewRedactionSanitizer(&sanitizer.RedactionRules{DropKeys: []string{"password"}}, nil)
+	require.NoError(t, err)
+	zipkin, jaeger, grpc, importer, processor = handler.WithSanitizer(sanitizer.NewChainedSanitizer(redactionSanitizer)).BuildHandlers()
+	assert.NotNil(t, zipkin)
+	assert.NotNil(t, jaeger)
+	assert.NotNil(t, grpc)
+	assert.NotNil(t, importer)
+	assert.NotNil(t, processor)
+	processor.Stop()
 }
 
 func TestDefaultSpanFilter(t *testing.T) {
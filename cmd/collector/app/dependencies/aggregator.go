@@ -0,0 +1,295 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencies
+
+import (
+	"sync"
+	"time"
+
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+)
+
+type aggregatorMetrics struct {
+	// SpansReceived is the number of spans observed by the aggregator
+	SpansReceived metrics.Counter `metric:"spans" tags:"result=received"`
+	// SpansDropped is the number of spans of new traces dropped because too many traces were buffered
+	SpansDropped metrics.Counter `metric:"spans" tags:"result=dropped"`
+	// SpansLate is the number of spans received after their trace was already counted
+	SpansLate metrics.Counter `metric:"spans" tags:"result=late"`
+	// TracesCompleted is the number of traces whose dependency links were counted
+	TracesCompleted metrics.Counter `metric:"traces_completed"`
+	// TracesBuffered is the number of traces waiting for the trace timeout
+	TracesBuffered metrics.Gauge `metric:"traces_buffered"`
+	// LinksWritten is the number of dependency links written to the storage
+	LinksWritten metrics.Counter `metric:"links_written"`
+	// FlushErrors is the number of failed writes of the dependency links
+	FlushErrors metrics.Counter `metric:"flush_errors"`
+}
+
+// traceDependencies holds what is needed from the spans of a trace to build its dependency links.
+type traceDependencies struct {
+	spans    []spanNode
+	lastSeen time.Time
+}
+
+type spanNode struct {
+	spanID       model.SpanID
+	parentSpanID model.SpanID
+	service      string
+	client       bool
+	server       bool
+}
+
+type completedTrace struct {
+	traceID     model.TraceID
+	completedAt time.Time
+}
+
+type linkKey struct {
+	parent string
+	child  string
+}
+
+// Aggregator builds the service dependency links from the spans flowing through the collector,
+// as a streaming alternative to the Spark job computing them from the span storage. The spans of
+// a trace are buffered until no new span was received for the trace timeout, then every span whose
+// parent was emitted by a different service counts as one call between the two services. The links
+// counted since the previous flush are periodically written as one time bucket.
+type Aggregator struct {
+	sync.Mutex
+
+	options Options
+	writer  dependencystore.Writer
+	logger  *zap.Logger
+	metrics aggregatorMetrics
+	timeNow func() time.Time
+
+	traces map[model.TraceID]*traceDependencies
+	// completed remembers the recently counted traces, to detect their late spans, and completedQueue
+	// orders them by completion time. At most MaxTraces of them are remembered, the oldest are forgotten
+	// first and their late spans are then counted as new traces.
+	completed      map[model.TraceID]bool
+	completedQueue []completedTrace
+	links          map[linkKey]uint64
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewAggregator creates an Aggregator that writes the dependency links into the writer.
+func NewAggregator(
+	options Options,
+	writer dependencystore.Writer,
+	metricsFactory metrics.Factory,
+	logger *zap.Logger,
+) *Aggregator {
+	a := &Aggregator{
+		options:   options,
+		writer:    writer,
+		logger:    logger,
+		timeNow:   time.Now,
+		traces:    make(map[model.TraceID]*traceDependencies),
+		completed: make(map[model.TraceID]bool),
+		links:     make(map[linkKey]uint64),
+		stop:      make(chan struct{}),
+	}
+	metrics.Init(&a.metrics, metricsFactory.Namespace(metrics.NSOptions{Name: "dependencies_aggregator"}), nil)
+	return a
+}
+
+// HandleSpan records the span in the buffer of its trace. It can be used as the app.ProcessSpan
+// invoked by the span processor before the span is saved.
+func (a *Aggregator) HandleSpan(span *model.Span) {
+	a.metrics.SpansReceived.Inc(1)
+	now := a.timeNow()
+	a.Lock()
+	defer a.Unlock()
+	if a.completed[span.TraceID] {
+		a.metrics.SpansLate.Inc(1)
+		return
+	}
+	trace, ok := a.traces[span.TraceID]
+	if !ok {
+		if len(a.traces) >= a.options.MaxTraces {
+			a.metrics.SpansDropped.Inc(1)
+			return
+		}
+		trace = &traceDependencies{}
+		a.traces[span.TraceID] = trace
+	}
+	trace.lastSeen = now
+	node := spanNode{
+		spanID:       span.SpanID,
+		parentSpanID: span.ParentSpanID(),
+		client:       span.IsRPCClient(),
+		server:       span.IsRPCServer(),
+	}
+	if span.Process != nil {
+		node.service = span.Process.ServiceName
+	}
+	trace.spans = append(trace.spans, node)
+}
+
+// Start starts the periodic flushes of the dependency links.
+func (a *Aggregator) Start() {
+	a.wg.Add(1)
+	go a.runFlushLoop()
+}
+
+// Close stops the aggregator and writes the dependency links of all the buffered traces.
+func (a *Aggregator) Close() error {
+	close(a.stop)
+	a.wg.Wait()
+	now := a.timeNow()
+	a.completeTraces(now, now)
+	return a.writeLinks(now)
+}
+
+func (a *Aggregator) runFlushLoop() {
+	defer a.wg.Done()
+	ticker := time.NewTicker(a.options.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			a.flush(a.timeNow())
+		case <-a.stop:
+			return
+		}
+	}
+}
+
+func (a *Aggregator) flush(now time.Time) {
+	a.completeTraces(now.Add(-a.options.TraceTimeout), now)
+	if err := a.writeLinks(now); err != nil {
+		a.logger.Error("Failed to write dependency links", zap.Error(err))
+	}
+}
+
+// completeTraces counts the dependency links of the traces that did not receive
+// any span since idleSince and forgets the traces completed before the trace timeout.
+func (a *Aggregator) completeTraces(idleSince time.Time, now time.Time) {
+	a.Lock()
+	defer a.Unlock()
+	for len(a.completedQueue) > 0 && now.Sub(a.completedQueue[0].completedAt) >= a.options.TraceTimeout {
+		a.forgetOldestCompleted()
+	}
+	for traceID, trace := range a.traces {
+		if trace.lastSeen.After(idleSince) {
+			continue
+		}
+		for _, link := range trace.links() {
+			a.links[link]++
+		}
+		delete(a.traces, traceID)
+		if len(a.completedQueue) >= a.options.MaxTraces {
+			a.forgetOldestCompleted()
+		}
+		a.completed[traceID] = true
+		a.completedQueue = append(a.completedQueue, completedTrace{traceID: traceID, completedAt: now})
+		a.metrics.TracesCompleted.Inc(1)
+	}
+	a.metrics.TracesBuffered.Update(int64(len(a.traces)))
+}
+
+func (a *Aggregator) forgetOldestCompleted() {
+	delete(a.completed, a.completedQueue[0].traceID)
+	a.completedQueue = a.completedQueue[1:]
+}
+
+// links returns the calls between different services in the trace. Zipkin clients share the ID of a span
+// between its client and server sides: like model/adjuster.SpanIDDeduper, the server side of a shared span
+// is called by its client side, and the children of a shared span are called by its server side.
+func (t *traceDependencies) links() []linkKey {
+	spansByID := make(map[model.SpanID][]*spanNode)
+	for i := range t.spans {
+		span := &t.spans[i]
+		spansByID[span.spanID] = append(spansByID[span.spanID], span)
+	}
+	var links []linkKey
+	for i := range t.spans {
+		span := &t.spans[i]
+		parent := parentSpan(span, spansByID)
+		if parent == nil || parent.service == span.service {
+			continue
+		}
+		links = append(links, linkKey{parent: parent.service, child: span.service})
+	}
+	return links
+}
+
+func parentSpan(span *spanNode, spansByID map[model.SpanID][]*spanNode) *spanNode {
+	if span.server {
+		if client := clientSpan(spansByID[span.spanID]); client != nil {
+			return client
+		}
+	}
+	if span.parentSpanID == model.SpanID(0) {
+		return nil
+	}
+	parents := spansByID[span.parentSpanID]
+	if len(parents) == 0 {
+		return nil
+	}
+	if clientSpan(parents) != nil {
+		for _, parent := range parents {
+			if parent.server {
+				return parent
+			}
+		}
+	}
+	return parents[0]
+}
+
+// clientSpan returns the client side of the spans sharing an ID, if any
+func clientSpan(spans []*spanNode) *spanNode {
+	for _, span := range spans {
+		if span.client {
+			return span
+		}
+	}
+	return nil
+}
+
+// writeLinks writes the links counted since the previous write as the bucket ending at ts.
+func (a *Aggregator) writeLinks(ts time.Time) error {
+	a.Lock()
+	links := a.links
+	a.links = make(map[linkKey]uint64)
+	a.Unlock()
+
+	if len(links) == 0 {
+		return nil
+	}
+	dependencies := make([]model.DependencyLink, 0, len(links))
+	for k, callCount := range links {
+		dependencies = append(dependencies, model.DependencyLink{
+			Parent:    k.parent,
+			Child:     k.child,
+			CallCount: callCount,
+			Source:    model.JaegerDependencyLinkSource,
+		})
+	}
+	if err := a.writer.WriteDependencies(ts, dependencies); err != nil {
+		a.metrics.FlushErrors.Inc(1)
+		return err
+	}
+	a.metrics.LinksWritten.Inc(int64(len(dependencies)))
+	return nil
+}
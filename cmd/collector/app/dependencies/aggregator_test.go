@@ -0,0 +1,243 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencies
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	depsmocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
+)
+
+var testOptions = Options{
+	Enabled:       true,
+	FlushInterval: time.Minute,
+	TraceTimeout:  10 * time.Second,
+	MaxTraces:     10,
+}
+
+func makeSpan(traceID uint64, spanID uint64, parentSpanID uint64, service string) *model.Span {
+	span := &model.Span{
+		TraceID: model.NewTraceID(0, traceID),
+		SpanID:  model.NewSpanID(spanID),
+		Process: model.NewProcess(service, nil),
+	}
+	if parentSpanID != 0 {
+		span.References = []model.SpanRef{model.NewChildOfRef(span.TraceID, model.NewSpanID(parentSpanID))}
+	}
+	return span
+}
+
+func makeKindSpan(traceID uint64, spanID uint64, parentSpanID uint64, service string, kind string) *model.Span {
+	span := makeSpan(traceID, spanID, parentSpanID, service)
+	span.Tags = model.KeyValues{model.String("span.kind", kind)}
+	return span
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) timeNow() time.Time {
+	return c.now
+}
+
+func newTestAggregator(writer *depsmocks.Writer) (*Aggregator, *fakeClock, *metricstest.Factory) {
+	metricsFactory := metricstest.NewFactory(0)
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	a := NewAggregator(testOptions, writer, metricsFactory, zap.NewNop())
+	a.timeNow = clock.timeNow
+	return a, clock, metricsFactory
+}
+
+func sortedLinks(links []model.DependencyLink) []model.DependencyLink {
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].Parent != links[j].Parent {
+			return links[i].Parent < links[j].Parent
+		}
+		return links[i].Child < links[j].Child
+	})
+	return links
+}
+
+func TestAggregatorFlush(t *testing.T) {
+	writer := &depsmocks.Writer{}
+	a, clock, metricsFactory := newTestAggregator(writer)
+
+	var written []model.DependencyLink
+	writer.On("WriteDependencies", time.Unix(1020, 0), mock.AnythingOfType("[]model.DependencyLink")).
+		Run(func(args mock.Arguments) {
+			written = args.Get(1).([]model.DependencyLink)
+		}).Return(nil).Once()
+
+	// the child of trace 1 is received before its parent
+	a.HandleSpan(makeSpan(1, 2, 1, "api"))
+	a.HandleSpan(makeSpan(1, 1, 0, "frontend"))
+	a.HandleSpan(makeSpan(1, 3, 2, "db"))
+	a.HandleSpan(makeSpan(1, 4, 2, "api")) // same service, no link
+	a.HandleSpan(makeSpan(2, 1, 0, "frontend"))
+	a.HandleSpan(makeSpan(2, 2, 1, "api"))
+	// trace 3 is still receiving spans at the time of the flush
+	clock.now = time.Unix(1015, 0)
+	a.HandleSpan(makeSpan(3, 1, 0, "frontend"))
+	a.HandleSpan(makeSpan(3, 2, 1, "api"))
+
+	clock.now = time.Unix(1020, 0)
+	a.flush(clock.now)
+	writer.AssertExpectations(t)
+	assert.Equal(t, []model.DependencyLink{
+		{Parent: "api", Child: "db", CallCount: 1, Source: model.JaegerDependencyLinkSource},
+		{Parent: "frontend", Child: "api", CallCount: 2, Source: model.JaegerDependencyLinkSource},
+	}, sortedLinks(written))
+
+	// span of a trace that was already counted
+	a.HandleSpan(makeSpan(1, 5, 3, "cache"))
+
+	writer.On("WriteDependencies", time.Unix(1030, 0), []model.DependencyLink{
+		{Parent: "frontend", Child: "api", CallCount: 1, Source: model.JaegerDependencyLinkSource},
+	}).Return(nil).Once()
+	clock.now = time.Unix(1030, 0)
+	a.flush(clock.now)
+	writer.AssertExpectations(t)
+
+	// nothing to write
+	clock.now = time.Unix(1040, 0)
+	a.flush(clock.now)
+	assert.Empty(t, a.completed)
+
+	metricsFactory.AssertCounterMetrics(t, []metricstest.ExpectedMetric{
+		{Name: "dependencies_aggregator.spans", Tags: map[string]string{"result": "received"}, Value: 9},
+		{Name: "dependencies_aggregator.spans", Tags: map[string]string{"result": "late"}, Value: 1},
+		{Name: "dependencies_aggregator.traces_completed", Value: 3},
+		{Name: "dependencies_aggregator.links_written", Value: 3},
+	}...)
+}
+
+func TestAggregatorMaxTraces(t *testing.T) {
+	a, _, metricsFactory := newTestAggregator(&depsmocks.Writer{})
+	for i := 1; i <= testOptions.MaxTraces+2; i++ {
+		a.HandleSpan(makeSpan(uint64(i), 1, 0, "frontend"))
+	}
+	// spans of the traces already buffered are still accepted
+	a.HandleSpan(makeSpan(1, 2, 1, "api"))
+	assert.Len(t, a.traces, testOptions.MaxTraces)
+	assert.Len(t, a.traces[model.NewTraceID(0, 1)].spans, 2)
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{
+		Name: "dependencies_aggregator.spans", Tags: map[string]string{"result": "dropped"}, Value: 2,
+	})
+}
+
+func TestAggregatorSharedSpanID(t *testing.T) {
+	writer := &depsmocks.Writer{}
+	a, clock, _ := newTestAggregator(writer)
+	var written []model.DependencyLink
+	writer.On("WriteDependencies", mock.Anything, mock.AnythingOfType("[]model.DependencyLink")).
+		Run(func(args mock.Arguments) {
+			written = args.Get(1).([]model.DependencyLink)
+		}).Return(nil).Once()
+
+	// Zipkin clients give both sides of the call to api the span ID 2
+	a.HandleSpan(makeSpan(1, 1, 0, "frontend"))
+	a.HandleSpan(makeKindSpan(1, 2, 1, "frontend", "client"))
+	a.HandleSpan(makeKindSpan(1, 2, 1, "api", "server"))
+	a.HandleSpan(makeKindSpan(1, 3, 2, "api", "client"))
+	a.HandleSpan(makeKindSpan(1, 3, 2, "db", "server"))
+	clock.now = clock.now.Add(time.Minute)
+	a.flush(clock.now)
+	writer.AssertExpectations(t)
+	assert.Equal(t, []model.DependencyLink{
+		{Parent: "api", Child: "db", CallCount: 1, Source: model.JaegerDependencyLinkSource},
+		{Parent: "frontend", Child: "api", CallCount: 1, Source: model.JaegerDependencyLinkSource},
+	}, sortedLinks(written))
+}
+
+func TestAggregatorMaxCompletedTraces(t *testing.T) {
+	a, clock, metricsFactory := newTestAggregator(&depsmocks.Writer{})
+	for i := 1; i <= 2*testOptions.MaxTraces; i++ {
+		a.HandleSpan(makeSpan(uint64(i), 1, 0, "frontend"))
+		a.completeTraces(clock.now, clock.now)
+	}
+	// none of the completed traces is older than the trace timeout, the oldest ones are forgotten first
+	assert.Len(t, a.completed, testOptions.MaxTraces)
+	assert.Len(t, a.completedQueue, testOptions.MaxTraces)
+	assert.False(t, a.completed[model.NewTraceID(0, uint64(testOptions.MaxTraces))])
+	assert.True(t, a.completed[model.NewTraceID(0, uint64(testOptions.MaxTraces+1))])
+
+	a.HandleSpan(makeSpan(uint64(2*testOptions.MaxTraces), 2, 1, "api"))
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{
+		Name: "dependencies_aggregator.spans", Tags: map[string]string{"result": "late"}, Value: 1,
+	})
+}
+
+func TestAggregatorWriteError(t *testing.T) {
+	writer := &depsmocks.Writer{}
+	a, clock, metricsFactory := newTestAggregator(writer)
+	writer.On("WriteDependencies", mock.Anything, mock.Anything).Return(errors.New("write error"))
+
+	a.HandleSpan(makeSpan(1, 1, 0, "frontend"))
+	a.HandleSpan(makeSpan(1, 2, 1, "api"))
+	clock.now = clock.now.Add(time.Minute)
+	a.flush(clock.now)
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{
+		Name: "dependencies_aggregator.flush_errors", Value: 1,
+	})
+	assert.Empty(t, a.links)
+}
+
+func TestAggregatorClose(t *testing.T) {
+	writer := &depsmocks.Writer{}
+	a, clock, _ := newTestAggregator(writer)
+	writer.On("WriteDependencies", clock.now, []model.DependencyLink{
+		{Parent: "frontend", Child: "api", CallCount: 1, Source: model.JaegerDependencyLinkSource},
+	}).Return(nil).Once()
+
+	a.Start()
+	a.HandleSpan(makeSpan(1, 1, 0, "frontend"))
+	a.HandleSpan(makeSpan(1, 2, 1, "api"))
+	// the buffered traces are counted without waiting for the trace timeout
+	require.NoError(t, a.Close())
+	writer.AssertExpectations(t)
+}
+
+func TestAggregatorFlushLoop(t *testing.T) {
+	writer := &depsmocks.Writer{}
+	options := testOptions
+	options.FlushInterval = time.Millisecond
+	options.TraceTimeout = 0
+	a := NewAggregator(options, writer, metricstest.NewFactory(0), zap.NewNop())
+	written := make(chan struct{})
+	writer.On("WriteDependencies", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		close(written)
+	}).Return(nil).Once()
+
+	a.HandleSpan(makeSpan(1, 1, 0, "frontend"))
+	a.HandleSpan(makeSpan(1, 2, 1, "api"))
+	a.Start()
+	select {
+	case <-written:
+	case <-time.After(5 * time.Second):
+		t.Fatal("dependency links were not flushed")
+	}
+	require.NoError(t, a.Close())
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencies
+
+import (
+	"flag"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	enabled       = "collector.dependencies.enabled"
+	flushInterval = "collector.dependencies.flush-interval"
+	traceTimeout  = "collector.dependencies.trace-timeout"
+	maxTraces     = "collector.dependencies.max-traces"
+
+	defaultFlushInterval = time.Minute
+	defaultTraceTimeout  = 30 * time.Second
+	defaultMaxTraces     = 100000
+)
+
+// Options holds configuration for the dependency aggregator.
+type Options struct {
+	// Enabled turns on the aggregation of the service dependencies from the spans received by the collector.
+	Enabled bool
+
+	// FlushInterval determines how often the aggregated dependency links are written to the storage.
+	// Every flush writes the links of the traces completed since the previous flush as one time bucket.
+	FlushInterval time.Duration
+
+	// TraceTimeout is how long a trace is buffered after its last span was received
+	// before it is considered complete and its dependency links are counted.
+	TraceTimeout time.Duration
+
+	// MaxTraces is the maximum number of traces buffered at the same time, the spans
+	// of new traces are dropped when the limit is reached. It also bounds the number
+	// of completed traces remembered to detect their late spans.
+	MaxTraces int
+}
+
+// AddFlags adds flags for Options
+func AddFlags(flagSet *flag.FlagSet) {
+	flagSet.Bool(enabled, false,
+		"Aggregate the service dependencies from the received spans and write them to the dependencies storage, instead of running the Spark job.",
+	)
+	flagSet.Duration(flushInterval, defaultFlushInterval,
+		"How often the aggregated dependency links are written to the storage.",
+	)
+	flagSet.Duration(traceTimeout, defaultTraceTimeout,
+		"How long a trace is buffered after its last span was received before its dependency links are counted.",
+	)
+	flagSet.Int(maxTraces, defaultMaxTraces,
+		"The maximum number of traces buffered by the dependency aggregator, the spans of new traces are dropped above this limit. "+
+			"It also bounds the number of completed traces remembered to detect their late spans.",
+	)
+}
+
+// InitFromViper initializes Options with properties from viper
+func (opts Options) InitFromViper(v *viper.Viper) Options {
+	opts.Enabled = v.GetBool(enabled)
+	opts.FlushInterval = v.GetDuration(flushInterval)
+	opts.TraceTimeout = v.GetDuration(traceTimeout)
+	opts.MaxTraces = v.GetInt(maxTraces)
+	return opts
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencies
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+func TestOptionsWithFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.dependencies.enabled=true",
+		"--collector.dependencies.flush-interval=5m",
+		"--collector.dependencies.trace-timeout=1m",
+		"--collector.dependencies.max-traces=1000",
+	})
+	opts := Options{}.InitFromViper(v)
+
+	assert.True(t, opts.Enabled)
+	assert.Equal(t, 5*time.Minute, opts.FlushInterval)
+	assert.Equal(t, time.Minute, opts.TraceTimeout)
+	assert.Equal(t, 1000, opts.MaxTraces)
+}
+
+func TestDefaultOptions(t *testing.T) {
+	v, _ := config.Viperize(AddFlags)
+	opts := Options{}.InitFromViper(v)
+
+	assert.False(t, opts.Enabled)
+	assert.Equal(t, defaultFlushInterval, opts.FlushInterval)
+	assert.Equal(t, defaultTraceTimeout, opts.TraceTimeout)
+	assert.Equal(t, defaultMaxTraces, opts.MaxTraces)
+}
@@ -18,7 +18,10 @@ import (
 	"context"
 
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
 )
 
@@ -26,18 +29,27 @@ import (
 type GRPCHandler struct {
 	logger        *zap.Logger
 	spanProcessor SpanProcessor
+	tenancyMgr    *tenancy.Manager
 }
 
 // NewGRPCHandler registers routes for this handler on the given router.
-func NewGRPCHandler(logger *zap.Logger, spanProcessor SpanProcessor) *GRPCHandler {
+func NewGRPCHandler(logger *zap.Logger, spanProcessor SpanProcessor, tenancyMgr *tenancy.Manager) *GRPCHandler {
 	return &GRPCHandler{
 		logger:        logger,
 		spanProcessor: spanProcessor,
+		tenancyMgr:    tenancyMgr,
 	}
 }
 
 // PostSpans implements gRPC CollectorService.
 func (g *GRPCHandler) PostSpans(ctx context.Context, r *api_v2.PostSpansRequest) (*api_v2.PostSpansResponse, error) {
+	var tenant string
+	if g.tenancyMgr.Enabled {
+		var err error
+		if tenant, err = tenancy.GetValidTenant(ctx, g.tenancyMgr); err != nil {
+			return nil, err
+		}
+	}
 	for _, span := range r.GetBatch().Spans {
 		if span.GetProcess() == nil {
 			span.Process = r.Batch.Process
@@ -46,7 +58,11 @@ func (g *GRPCHandler) PostSpans(ctx context.Context, r *api_v2.PostSpansRequest)
 	_, err := g.spanProcessor.ProcessSpans(r.GetBatch().Spans, ProcessSpansOptions{
 		InboundTransport: GRPCTransport,
 		SpanFormat:       ProtoSpanFormat,
+		Tenant:           tenant,
 	})
+	if err == ErrRateLimited {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
 	if err != nil {
 		g.logger.Error("cannot process spans", zap.Error(err))
 		return nil, err
@@ -25,8 +25,12 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
 )
 
@@ -34,26 +38,37 @@ type mockSpanProcessor struct {
 	expectedError error
 	mux           sync.Mutex
 	spans         []*model.Span
+	tenants       []string
 }
 
 func (p *mockSpanProcessor) ProcessSpans(spans []*model.Span, opts ProcessSpansOptions) ([]bool, error) {
 	p.mux.Lock()
 	defer p.mux.Unlock()
 	p.spans = append(p.spans, spans...)
+	p.tenants = append(p.tenants, opts.Tenant)
 	oks := make([]bool, len(spans))
 	return oks, p.expectedError
 }
 
+func (p *mockSpanProcessor) Stop() {}
+
 func (p *mockSpanProcessor) getSpans() []*model.Span {
 	p.mux.Lock()
 	defer p.mux.Unlock()
 	return p.spans
 }
 
+func (p *mockSpanProcessor) getTenants() []string {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	return p.tenants
+}
+
 func (p *mockSpanProcessor) reset() {
 	p.mux.Lock()
 	defer p.mux.Unlock()
 	p.spans = nil
+	p.tenants = nil
 }
 
 func initializeGRPCTestServer(t *testing.T, beforeServe func(s *grpc.Server)) (*grpc.Server, net.Addr) {
@@ -77,7 +92,7 @@ func newClient(t *testing.T, addr net.Addr) (api_v2.CollectorServiceClient, *grp
 func TestPostSpans(t *testing.T) {
 	processor := &mockSpanProcessor{}
 	server, addr := initializeGRPCTestServer(t, func(s *grpc.Server) {
-		handler := NewGRPCHandler(zap.NewNop(), processor)
+		handler := NewGRPCHandler(zap.NewNop(), processor, &tenancy.Manager{})
 		api_v2.RegisterCollectorServiceServer(s, handler)
 	})
 	defer server.Stop()
@@ -109,7 +124,7 @@ func TestPostSpansWithError(t *testing.T) {
 	expectedError := errors.New("test-error")
 	processor := &mockSpanProcessor{expectedError: expectedError}
 	server, addr := initializeGRPCTestServer(t, func(s *grpc.Server) {
-		handler := NewGRPCHandler(zap.NewNop(), processor)
+		handler := NewGRPCHandler(zap.NewNop(), processor, &tenancy.Manager{})
 		api_v2.RegisterCollectorServiceServer(s, handler)
 	})
 	defer server.Stop()
@@ -129,3 +144,61 @@ func TestPostSpansWithError(t *testing.T) {
 	require.Contains(t, err.Error(), expectedError.Error())
 	require.Len(t, processor.getSpans(), 1)
 }
+
+func TestPostSpansRateLimited(t *testing.T) {
+	processor := &mockSpanProcessor{expectedError: ErrRateLimited}
+	server, addr := initializeGRPCTestServer(t, func(s *grpc.Server) {
+		handler := NewGRPCHandler(zap.NewNop(), processor, &tenancy.Manager{})
+		api_v2.RegisterCollectorServiceServer(s, handler)
+	})
+	defer server.Stop()
+	client, conn := newClient(t, addr)
+	defer conn.Close()
+	_, err := client.PostSpans(context.Background(), &api_v2.PostSpansRequest{
+		Batch: model.Batch{
+			Spans: []*model.Span{{OperationName: "fake-operation"}},
+		},
+	})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestPostSpansWithTenant(t *testing.T) {
+	tenancyMgr, err := tenancy.NewManager(&tenancy.Options{Enabled: true, Tenants: []string{"acme"}})
+	require.NoError(t, err)
+	processor := &mockSpanProcessor{}
+	server, addr := initializeGRPCTestServer(t, func(s *grpc.Server) {
+		handler := NewGRPCHandler(zap.NewNop(), processor, tenancyMgr)
+		api_v2.RegisterCollectorServiceServer(s, handler)
+	})
+	defer server.Stop()
+	client, conn := newClient(t, addr)
+	defer conn.Close()
+
+	tests := []struct {
+		name         string
+		tenant       string
+		expectedCode codes.Code
+	}{
+		{name: "accepted tenant", tenant: "acme", expectedCode: codes.OK},
+		{name: "missing tenant", expectedCode: codes.Unauthenticated},
+		{name: "unknown tenant", tenant: "megacorp", expectedCode: codes.PermissionDenied},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer processor.reset()
+			ctx := context.Background()
+			if test.tenant != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, tenancyMgr.Header, test.tenant)
+			}
+			_, err := client.PostSpans(ctx, &api_v2.PostSpansRequest{
+				Batch: model.Batch{Spans: []*model.Span{{OperationName: "test-op"}}},
+			})
+			assert.Equal(t, test.expectedCode, status.Code(err))
+			if test.expectedCode == codes.OK {
+				assert.Equal(t, []string{test.tenant}, processor.getTenants())
+			} else {
+				assert.Empty(t, processor.getSpans())
+			}
+		})
+	}
+}
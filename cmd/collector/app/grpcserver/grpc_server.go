@@ -26,17 +26,21 @@ import (
 	"google.golang.org/grpc/grpclog"
 
 	"github.com/jaegertracing/jaeger/cmd/collector/app"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/baggage"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/baggage/restrictionstore"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
 )
 
 // StartGRPCCollector configures and starts gRPC endpoints exposed by collector.
+// The baggage restrictions are only served if baggageRestrictions is not nil.
 func StartGRPCCollector(
 	port int,
 	server *grpc.Server,
 	handler *app.GRPCHandler,
 	samplingStrategy strategystore.StrategyStore,
+	baggageRestrictions restrictionstore.RestrictionStore,
 	logger *zap.Logger,
 	serveErr func(error),
 ) (net.Addr, error) {
@@ -50,6 +54,9 @@ func StartGRPCCollector(
 
 	api_v2.RegisterCollectorServiceServer(server, handler)
 	api_v2.RegisterSamplingManagerServer(server, sampling.NewGRPCHandler(samplingStrategy))
+	if baggageRestrictions != nil {
+		api_v2.RegisterBaggageRestrictionManagerServer(server, baggage.NewGRPCHandler(baggageRestrictions))
+	}
 	startServer(server, lis, logger, serveErr)
 	return lis.Addr(), nil
 }
@@ -25,21 +25,25 @@ import (
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 
 	"github.com/jaegertracing/jaeger/cmd/collector/app"
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+	"github.com/jaegertracing/jaeger/thrift-gen/baggage"
 	"github.com/jaegertracing/jaeger/thrift-gen/sampling"
 )
 
 // test wrong port number
 func TestFailToListen(t *testing.T) {
 	l, _ := zap.NewDevelopment()
-	handler := app.NewGRPCHandler(l, &mockSpanProcessor{})
+	handler := app.NewGRPCHandler(l, &mockSpanProcessor{}, &tenancy.Manager{})
 	server := grpc.NewServer()
 	const invalidPort = -1
-	addr, err := StartGRPCCollector(invalidPort, server, handler, &mockSamplingStore{}, l, func(e error) {
+	addr, err := StartGRPCCollector(invalidPort, server, handler, &mockSamplingStore{}, nil, l, func(e error) {
 	})
 	assert.Nil(t, addr)
 	assert.EqualError(t, err, "failed to listen on gRPC port: listen tcp: address -1: invalid port")
@@ -61,9 +65,9 @@ func TestFailServe(t *testing.T) {
 
 func TestSpanCollector(t *testing.T) {
 	l, _ := zap.NewDevelopment()
-	handler := app.NewGRPCHandler(l, &mockSpanProcessor{})
+	handler := app.NewGRPCHandler(l, &mockSpanProcessor{}, &tenancy.Manager{})
 	server := grpc.NewServer()
-	addr, err := StartGRPCCollector(0, server, handler, &mockSamplingStore{}, l, func(e error) {
+	addr, err := StartGRPCCollector(0, server, handler, &mockSamplingStore{}, nil, l, func(e error) {
 	})
 	require.NoError(t, err)
 
@@ -76,6 +80,29 @@ func TestSpanCollector(t *testing.T) {
 	response, err := c.PostSpans(context.Background(), &api_v2.PostSpansRequest{})
 	require.NoError(t, err)
 	require.NotNil(t, response)
+
+	// the baggage restrictions are not served without a store
+	_, err = api_v2.NewBaggageRestrictionManagerClient(conn).GetBaggageRestrictions(context.Background(), &api_v2.BaggageRestrictionParameters{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestBaggageRestrictions(t *testing.T) {
+	l, _ := zap.NewDevelopment()
+	handler := app.NewGRPCHandler(l, &mockSpanProcessor{}, &tenancy.Manager{})
+	server := grpc.NewServer()
+	addr, err := StartGRPCCollector(0, server, handler, &mockSamplingStore{}, &mockRestrictionStore{}, l, func(e error) {
+	})
+	require.NoError(t, err)
+
+	conn, err := grpc.Dial(addr.String(), grpc.WithInsecure())
+	//lint:ignore SA5001 don't care about errors
+	defer conn.Close()
+	defer server.Stop()
+	require.NoError(t, err)
+	c := api_v2.NewBaggageRestrictionManagerClient(conn)
+	response, err := c.GetBaggageRestrictions(context.Background(), &api_v2.BaggageRestrictionParameters{ServiceName: "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, []*api_v2.BaggageRestriction{{BaggageKey: "key", MaxValueLength: 10}}, response.BaggageRestrictions)
 }
 
 type mockSamplingStore struct{}
@@ -84,9 +111,17 @@ func (s mockSamplingStore) GetSamplingStrategy(serviceName string) (*sampling.Sa
 	return nil, nil
 }
 
+type mockRestrictionStore struct{}
+
+func (s mockRestrictionStore) GetBaggageRestrictions(serviceName string) ([]*baggage.BaggageRestriction, error) {
+	return []*baggage.BaggageRestriction{{BaggageKey: "key", MaxValueLength: 10}}, nil
+}
+
 type mockSpanProcessor struct {
 }
 
 func (p *mockSpanProcessor) ProcessSpans(spans []*model.Span, _ app.ProcessSpansOptions) ([]bool, error) {
 	return []bool{}, nil
 }
+
+func (p *mockSpanProcessor) Stop() {}
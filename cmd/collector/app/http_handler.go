@@ -23,6 +23,7 @@ import (
 	"github.com/apache/thrift/lib/go/thrift"
 	"github.com/gorilla/mux"
 
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	tJaeger "github.com/jaegertracing/jaeger/thrift-gen/jaeger"
 )
 
@@ -85,11 +86,19 @@ func (aH *APIHandler) saveSpan(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	batches := []*tJaeger.Batch{batch}
-	opts := SubmitBatchOptions{InboundTransport: HTTPTransport}
+	opts := SubmitBatchOptions{InboundTransport: HTTPTransport, Tenant: tenancy.GetTenant(r.Context())}
 	if _, err = aH.jaegerBatchesHandler.SubmitBatches(batches, opts); err != nil {
-		http.Error(w, fmt.Sprintf("Cannot submit Jaeger batch: %v", err), http.StatusInternalServerError)
+		http.Error(w, fmt.Sprintf("Cannot submit Jaeger batch: %v", err), HTTPStatusForError(err))
 		return
 	}
 
 	w.WriteHeader(http.StatusAccepted)
 }
+
+// HTTPStatusForError returns the status code of the response to a request whose spans could not be processed.
+func HTTPStatusForError(err error) int {
+	if err == ErrRateLimited {
+		return http.StatusTooManyRequests
+	}
+	return http.StatusInternalServerError
+}
@@ -92,6 +92,11 @@ func TestThriftFormat(t *testing.T) {
 	assert.NoError(t, err)
 	assert.EqualValues(t, http.StatusInternalServerError, statusCode)
 	assert.EqualValues(t, "Cannot submit Jaeger batch: Bad times ahead\n", resBodyStr)
+
+	handler.jaegerBatchesHandler.(*mockJaegerHandler).err = ErrRateLimited
+	statusCode, _, err = postBytes("application/x-thrift", server.URL+`/api/traces`, someBytes)
+	assert.NoError(t, err)
+	assert.EqualValues(t, http.StatusTooManyRequests, statusCode)
 }
 
 func TestViaClient(t *testing.T) {
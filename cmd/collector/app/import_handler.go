@@ -0,0 +1,136 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/thrift-gen/zipkincore"
+)
+
+var errMissingProcess = errors.New("span without process in a batch without process")
+
+// ZipkinV2Deserializer deserializes Zipkin v2 JSON spans into Zipkin thrift spans
+type ZipkinV2Deserializer func(body []byte) ([]*zipkincore.Span, error)
+
+// ImportHandler accepts traces exported from the query service, either as Zipkin v2 JSON
+// (Content-Type: application/json) or as a protobuf model.Batch (Content-Type: application/x-protobuf).
+// Zipkin spans go through the same sanitizers and conversion as the spans of the Zipkin endpoints.
+type ImportHandler struct {
+	logger              *zap.Logger
+	spanProcessor       SpanProcessor
+	zipkinSpansHandler  ZipkinSpansHandler
+	deserializeZipkinV2 ZipkinV2Deserializer
+}
+
+// NewImportHandler returns a new ImportHandler
+func NewImportHandler(
+	logger *zap.Logger,
+	spanProcessor SpanProcessor,
+	zipkinSpansHandler ZipkinSpansHandler,
+	deserializeZipkinV2 ZipkinV2Deserializer,
+) *ImportHandler {
+	return &ImportHandler{
+		logger:              logger,
+		spanProcessor:       spanProcessor,
+		zipkinSpansHandler:  zipkinSpansHandler,
+		deserializeZipkinV2: deserializeZipkinV2,
+	}
+}
+
+// RegisterRoutes registers routes for this handler on the given router
+func (h *ImportHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/api/import", h.importTraces).Methods(http.MethodPost)
+}
+
+func (h *ImportHandler) importTraces(w http.ResponseWriter, r *http.Request) {
+	bodyBytes, err := ioutil.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		http.Error(w, fmt.Sprintf(UnableToReadBodyErrFormat, err), http.StatusInternalServerError)
+		return
+	}
+
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Cannot parse content type: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	tenant := tenancy.GetTenant(r.Context())
+	var tSpans []*zipkincore.Span
+	var spans []*model.Span
+	switch contentType {
+	case "application/json":
+		tSpans, err = h.deserializeZipkinV2(bodyBytes)
+		if err != nil {
+			http.Error(w, fmt.Sprintf(UnableToReadBodyErrFormat, err), http.StatusBadRequest)
+			return
+		}
+		if len(tSpans) > 0 {
+			_, err = h.zipkinSpansHandler.SubmitZipkinBatch(tSpans, SubmitBatchOptions{
+				InboundTransport: HTTPTransport,
+				Tenant:           tenant,
+			})
+		}
+	case "application/x-protobuf":
+		spans, err = deserializeBatch(bodyBytes)
+		if err != nil {
+			http.Error(w, fmt.Sprintf(UnableToReadBodyErrFormat, err), http.StatusBadRequest)
+			return
+		}
+		if len(spans) > 0 {
+			_, err = h.spanProcessor.ProcessSpans(spans, ProcessSpansOptions{
+				InboundTransport: HTTPTransport,
+				SpanFormat:       ProtoSpanFormat,
+				Tenant:           tenant,
+			})
+		}
+	default:
+		http.Error(w, fmt.Sprintf("Unsupported content type: %v", contentType), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.logger.Error("cannot process imported spans", zap.Error(err))
+		http.Error(w, fmt.Sprintf("Cannot import spans: %v", err), HTTPStatusForError(err))
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func deserializeBatch(data []byte) ([]*model.Span, error) {
+	var batch model.Batch
+	if err := batch.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	for _, span := range batch.Spans {
+		if span.Process == nil {
+			if batch.Process == nil {
+				return nil, errMissingProcess
+			}
+			span.Process = batch.Process
+		}
+	}
+	return batch.Spans, nil
+}
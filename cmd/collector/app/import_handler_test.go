@@ -0,0 +1,195 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	zipkinS "github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer/zipkin"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/thrift-gen/zipkincore"
+)
+
+// deserializeZipkinThriftJSON stands in for the Zipkin v2 JSON deserializer of the zipkin package,
+// which cannot be imported here, by reading the spans directly in their thrift form.
+func deserializeZipkinThriftJSON(body []byte) ([]*zipkincore.Span, error) {
+	var spans []*zipkincore.Span
+	if err := json.Unmarshal(body, &spans); err != nil {
+		return nil, err
+	}
+	return spans, nil
+}
+
+func newTestImportHandler(processor SpanProcessor) *ImportHandler {
+	zipkinSpansHandler := NewZipkinSpanHandler(zap.NewNop(), processor, zipkinS.NewChainedSanitizer(zipkinS.StandardSanitizers...))
+	return NewImportHandler(zap.NewNop(), processor, zipkinSpansHandler, deserializeZipkinThriftJSON)
+}
+
+func initializeImportTestServer(err error) (*httptest.Server, *mockSpanProcessor) {
+	r := mux.NewRouter()
+	processor := &mockSpanProcessor{expectedError: err}
+	newTestImportHandler(processor).RegisterRoutes(r)
+	return httptest.NewServer(r), processor
+}
+
+func postImport(t *testing.T, url, contentType string, body []byte) (int, string) {
+	req, err := http.NewRequest(http.MethodPost, url+"/api/import", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", contentType)
+	res, err := httpClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	resBody := new(bytes.Buffer)
+	resBody.ReadFrom(res.Body)
+	return res.StatusCode, resBody.String()
+}
+
+func TestImportZipkin(t *testing.T) {
+	server, processor := initializeImportTestServer(nil)
+	defer server.Close()
+
+	// the negative duration is fixed by the standard Zipkin sanitizers
+	body := []byte(`[{"trace_id": 1, "id": 2, "name": "get", "timestamp": 1500000000000000, "duration": -10}]`)
+	statusCode, resBody := postImport(t, server.URL, "application/json", body)
+	assert.Equal(t, http.StatusAccepted, statusCode, resBody)
+	spans := processor.getSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, model.NewTraceID(0, 1), spans[0].TraceID)
+	assert.Equal(t, time.Microsecond, spans[0].Duration)
+}
+
+func TestImportZipkinInvalid(t *testing.T) {
+	server, processor := initializeImportTestServer(nil)
+	defer server.Close()
+
+	statusCode, resBody := postImport(t, server.URL, "application/json", []byte(`not json`))
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Contains(t, resBody, "Unable to process request body")
+	assert.Empty(t, processor.getSpans())
+}
+
+func TestImportProtobuf(t *testing.T) {
+	server, processor := initializeImportTestServer(nil)
+	defer server.Close()
+
+	batch := &model.Batch{
+		Process: model.NewProcess("batch-process", nil),
+		Spans: []*model.Span{
+			{TraceID: model.NewTraceID(0, 1), SpanID: model.NewSpanID(1)},
+			{TraceID: model.NewTraceID(0, 1), SpanID: model.NewSpanID(2), Process: model.NewProcess("span-process", nil)},
+		},
+	}
+	data, err := batch.Marshal()
+	require.NoError(t, err)
+	statusCode, resBody := postImport(t, server.URL, "application/x-protobuf", data)
+	assert.Equal(t, http.StatusAccepted, statusCode, resBody)
+	spans := processor.getSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "batch-process", spans[0].Process.ServiceName)
+	assert.Equal(t, "span-process", spans[1].Process.ServiceName)
+}
+
+func TestImportProtobufInvalid(t *testing.T) {
+	server, processor := initializeImportTestServer(nil)
+	defer server.Close()
+
+	noProcess, err := (&model.Batch{Spans: []*model.Span{{SpanID: model.NewSpanID(1)}}}).Marshal()
+	require.NoError(t, err)
+	for _, body := range [][]byte{[]byte("not protobuf"), noProcess} {
+		statusCode, resBody := postImport(t, server.URL, "application/x-protobuf", body)
+		assert.Equal(t, http.StatusBadRequest, statusCode, resBody)
+	}
+	assert.Empty(t, processor.getSpans())
+}
+
+func TestImportEmptyBatch(t *testing.T) {
+	server, processor := initializeImportTestServer(nil)
+	defer server.Close()
+
+	statusCode, _ := postImport(t, server.URL, "application/json", []byte(`[]`))
+	assert.Equal(t, http.StatusAccepted, statusCode)
+	assert.Empty(t, processor.getSpans())
+}
+
+func TestImportProcessorFailure(t *testing.T) {
+	server, _ := initializeImportTestServer(assert.AnError)
+	defer server.Close()
+
+	data, err := (&model.Batch{Process: model.NewProcess("svc", nil), Spans: []*model.Span{{}}}).Marshal()
+	require.NoError(t, err)
+	statusCode, resBody := postImport(t, server.URL, "application/x-protobuf", data)
+	assert.Equal(t, http.StatusInternalServerError, statusCode)
+	assert.Contains(t, resBody, "Cannot import spans")
+}
+
+func TestImportRateLimited(t *testing.T) {
+	server, _ := initializeImportTestServer(ErrRateLimited)
+	defer server.Close()
+
+	data, err := (&model.Batch{Process: model.NewProcess("svc", nil), Spans: []*model.Span{{}}}).Marshal()
+	require.NoError(t, err)
+	statusCode, _ := postImport(t, server.URL, "application/x-protobuf", data)
+	assert.Equal(t, http.StatusTooManyRequests, statusCode)
+}
+
+func TestImportContentTypes(t *testing.T) {
+	server, _ := initializeImportTestServer(nil)
+	defer server.Close()
+
+	statusCode, resBody := postImport(t, server.URL, "application/xml", nil)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Contains(t, resBody, "Unsupported content type: application/xml")
+
+	statusCode, resBody = postImport(t, server.URL, "", nil)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Contains(t, resBody, "Cannot parse content type")
+}
+
+func TestImportWithTenant(t *testing.T) {
+	tenancyMgr, err := tenancy.NewManager(&tenancy.Options{Enabled: true})
+	require.NoError(t, err)
+	r := mux.NewRouter()
+	processor := &mockSpanProcessor{}
+	newTestImportHandler(processor).RegisterRoutes(r)
+	server := httptest.NewServer(tenancy.ExtractTenantHTTPHandler(tenancyMgr, r))
+	defer server.Close()
+
+	data, err := (&model.Batch{Process: model.NewProcess("svc", nil), Spans: []*model.Span{{}}}).Marshal()
+	require.NoError(t, err)
+	statusCode, _ := postImport(t, server.URL, "application/x-protobuf", data)
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+	assert.Empty(t, processor.getSpans())
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/api/import", bytes.NewReader(data))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set(tenancyMgr.Header, "acme")
+	res, err := httpClient.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusAccepted, res.StatusCode)
+	assert.Equal(t, []string{"acme"}, processor.getTenants())
+}
@@ -131,6 +131,8 @@ type SpanCounts struct {
 	ReceivedBySvc metricsBySvc
 	// RejectedBySvc is the number of spans we rejected (usually due to blacklisting) by-service.
 	RejectedBySvc metricsBySvc
+	// RateLimitedBySvc is the number of spans we rejected because they exceeded the ingestion quotas by-service.
+	RateLimitedBySvc metricsBySvc
 }
 
 // NewSpanProcessorMetrics returns a SpanProcessorMetrics
@@ -223,8 +225,9 @@ func newCountsByTransport(factory metrics.Factory, format SpanFormat) SpanCounts
 func newCounts(factory metrics.Factory, transport InboundTransport) SpanCounts {
 	factory = factory.Namespace(metrics.NSOptions{Tags: map[string]string{"transport": string(transport)}})
 	return SpanCounts{
-		RejectedBySvc: newMetricsBySvc(factory, "rejected"),
-		ReceivedBySvc: newMetricsBySvc(factory, "received"),
+		RejectedBySvc:    newMetricsBySvc(factory, "rejected"),
+		ReceivedBySvc:    newMetricsBySvc(factory, "received"),
+		RateLimitedBySvc: newMetricsBySvc(factory, "rate-limited"),
 	}
 }
 
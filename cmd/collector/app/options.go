@@ -20,6 +20,7 @@ import (
 
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer"
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
 const (
@@ -42,6 +43,26 @@ type options struct {
 	queueSize        int
 	reportBusy       bool
 	extraFormatTypes []SpanFormat
+	tenantSpanWriter TenantSpanWriter
+	tailSampler      TailSampler
+	rateLimiter      RateLimiter
+}
+
+// TenantSpanWriter returns the span writer storing the spans of the tenant.
+type TenantSpanWriter func(tenant string) (spanstore.Writer, error)
+
+// TailSampler buffers the spans by trace and decides whether each trace is kept as a whole.
+type TailSampler interface {
+	// Start starts making decisions, the items of the kept traces are handed to the consumer.
+	Start(consumer func(item interface{}))
+	// Add buffers the span of the tenant along with the item to hand to the consumer if its trace is kept.
+	Add(tenant string, span *model.Span, item interface{})
+}
+
+// RateLimiter decides whether the spans fit within the ingestion quotas of their services.
+type RateLimiter interface {
+	// AllowSpans returns true if the spans are admitted, in which case they are counted against the quotas.
+	AllowSpans(spans []*model.Span) bool
 }
 
 // Option is a function that sets some option on StorageBuilder.
@@ -134,6 +155,30 @@ func (options) ExtraFormatTypes(extraFormatTypes []SpanFormat) Option {
 	}
 }
 
+// TenantSpanWriter creates an Option that initializes the tenantSpanWriter function.
+// When set, every batch of spans must belong to a tenant and is written with the writer of that tenant.
+func (options) TenantSpanWriter(tenantSpanWriter TenantSpanWriter) Option {
+	return func(b *options) {
+		b.tenantSpanWriter = tenantSpanWriter
+	}
+}
+
+// TailSampler creates an Option that initializes the tail sampler. When set, the spans
+// only get to the queue once the tail sampler decided to keep their trace.
+func (options) TailSampler(tailSampler TailSampler) Option {
+	return func(b *options) {
+		b.tailSampler = tailSampler
+	}
+}
+
+// RateLimiter creates an Option that initializes the rate limiter. When set, the batches of spans
+// exceeding the ingestion quotas are rejected with ErrRateLimited.
+func (options) RateLimiter(rateLimiter RateLimiter) Option {
+	return func(b *options) {
+		b.rateLimiter = rateLimiter
+	}
+}
+
 func (o options) apply(opts ...Option) options {
 	ret := options{}
 	for _, opt := range opts {
@@ -0,0 +1,265 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/reload"
+)
+
+// maxBuckets is the number of token buckets above which the full buckets are discarded,
+// to bound the memory used when spans are received from many services.
+const maxBuckets = 10000
+
+type bucketKey struct {
+	service   string
+	operation string // empty for the bucket of the whole service
+}
+
+type storedQuotas struct {
+	defaultQuota    *quota
+	serviceQuotas   map[string]quota
+	operationQuotas map[bucketKey]quota
+}
+
+// Limiter applies per-service, and optionally per-operation, ingestion quotas to the spans received
+// by the collector, with a token bucket for each of them. The quotas are read from a JSON file which
+// can be periodically checked for changes; the token buckets start full after every reload.
+type Limiter struct {
+	timeNow  func() time.Time
+	reloader *reload.Reloader
+
+	sync.Mutex
+	quotas  *storedQuotas
+	buckets map[bucketKey]*tokenBucket
+}
+
+// NewLimiter creates a Limiter that applies the quotas read from options.QuotaFile. If options.ReloadInterval
+// is positive, the file is periodically checked for changes and the new quotas are swapped in once they pass validation.
+func NewLimiter(options Options, metricsFactory metrics.Factory, logger *zap.Logger) (*Limiter, error) {
+	l := &Limiter{
+		timeNow: time.Now,
+		buckets: make(map[bucketKey]*tokenBucket),
+	}
+	reloadOptions := reload.Options{
+		Name:   "ingestion quotas",
+		Source: options.QuotaFile,
+		Read: func() ([]byte, error) {
+			return readQuotaFile(options.QuotaFile)
+		},
+		Apply:    l.applyQuotas,
+		Interval: options.ReloadInterval,
+		Logger:   logger,
+	}
+	metrics.Init(&reloadOptions.Metrics, metricsFactory.Namespace(metrics.NSOptions{Name: "ingestion_quotas"}), nil)
+	reloader, err := reload.New(reloadOptions)
+	if err != nil {
+		return nil, err
+	}
+	l.reloader = reloader
+	return l, nil
+}
+
+// AllowSpans returns true if the spans fit within the quotas of their services and operations,
+// in which case they are counted against the quotas. The spans are admitted or rejected together,
+// so that a client retrying a rejected batch does not create duplicates.
+func (l *Limiter) AllowSpans(spans []*model.Span) bool {
+	now := l.timeNow()
+	l.Lock()
+	defer l.Unlock()
+
+	if len(l.buckets) >= maxBuckets {
+		l.discardFullBuckets(now)
+	}
+	counts := make(map[*tokenBucket]float64)
+	for _, span := range spans {
+		var service string
+		if span.Process != nil {
+			service = span.Process.ServiceName
+		}
+		if key, q, ok := l.quotas.quotaFor(service, span.OperationName); ok {
+			counts[l.getBucket(key, q, now)]++
+		}
+	}
+	for b, n := range counts {
+		if !b.canSpend(n) {
+			return false
+		}
+	}
+	for b, n := range counts {
+		b.spend(n)
+	}
+	return true
+}
+
+// Close stops the periodic reloading of the quotas.
+func (l *Limiter) Close() error {
+	return l.reloader.Close()
+}
+
+// getBucket returns the refilled token bucket of the key, creating it if needed.
+func (l *Limiter) getBucket(key bucketKey, q quota, now time.Time) *tokenBucket {
+	if b, ok := l.buckets[key]; ok {
+		b.refill(now)
+		return b
+	}
+	b := newTokenBucket(q, now)
+	l.buckets[key] = b
+	return b
+}
+
+// discardFullBuckets removes the full token buckets, which behave like new ones, so the limits do not change.
+func (l *Limiter) discardFullBuckets(now time.Time) {
+	for key, b := range l.buckets {
+		if b.refill(now); b.isFull() {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// quotaFor returns the bucket key and the quota applied to the spans of the operation of the service.
+// The last return value is false if the spans are not limited.
+func (s *storedQuotas) quotaFor(service, operation string) (bucketKey, quota, bool) {
+	key := bucketKey{service: service, operation: operation}
+	if q, ok := s.operationQuotas[key]; ok {
+		return key, q, true
+	}
+	key.operation = ""
+	if q, ok := s.serviceQuotas[service]; ok {
+		return key, q, true
+	}
+	if s.defaultQuota != nil {
+		return key, *s.defaultQuota, true
+	}
+	return key, quota{}, false
+}
+
+// applyQuotas validates the content of the quota file and replaces the current quotas with it,
+// the token buckets start full with the new quotas.
+func (l *Limiter) applyQuotas(content []byte) error {
+	q, err := loadQuotas(content)
+	if err != nil {
+		return err
+	}
+	l.Lock()
+	l.quotas = parseQuotas(q)
+	l.buckets = make(map[bucketKey]*tokenBucket)
+	l.Unlock()
+	return nil
+}
+
+func readQuotaFile(quotaFile string) ([]byte, error) {
+	bytes, err := ioutil.ReadFile(quotaFile) /* nolint #nosec , this comes from an admin, not user */
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to open ingestion quotas file")
+	}
+	return bytes, nil
+}
+
+func loadQuotas(content []byte) (*quotas, error) {
+	var q quotas
+	if err := json.Unmarshal(content, &q); err != nil {
+		return nil, errors.Wrap(err, "Failed to unmarshal ingestion quotas")
+	}
+	if err := validateQuotas(&q); err != nil {
+		return nil, errors.Wrap(err, "Invalid ingestion quotas")
+	}
+	return &q, nil
+}
+
+func validateQuotas(q *quotas) error {
+	if q.DefaultQuota != nil {
+		if err := validateQuota(*q.DefaultQuota, true); err != nil {
+			return errors.Wrap(err, "default quota")
+		}
+	}
+	services := make(map[string]struct{})
+	for _, sq := range q.ServiceQuotas {
+		if sq.Service == "" {
+			return errors.New("service name must not be empty")
+		}
+		if _, ok := services[sq.Service]; ok {
+			return fmt.Errorf("duplicate quota for service %s", sq.Service)
+		}
+		services[sq.Service] = struct{}{}
+		if err := validateQuota(sq.quota, len(sq.OperationQuotas) == 0); err != nil {
+			return errors.Wrapf(err, "service %s", sq.Service)
+		}
+		operations := make(map[string]struct{})
+		for _, oq := range sq.OperationQuotas {
+			if oq.Operation == "" {
+				return fmt.Errorf("operation name must not be empty in service %s", sq.Service)
+			}
+			if _, ok := operations[oq.Operation]; ok {
+				return fmt.Errorf("duplicate quota for operation %s of service %s", oq.Operation, sq.Service)
+			}
+			operations[oq.Operation] = struct{}{}
+			if err := validateQuota(oq.quota, true); err != nil {
+				return errors.Wrapf(err, "operation %s of service %s", oq.Operation, sq.Service)
+			}
+		}
+	}
+	return nil
+}
+
+// validateQuota checks the rate and burst of the quota, the rate can only be zero when it is optional.
+func validateQuota(q quota, required bool) error {
+	if q.SpansPerSecond < 0 || (required && q.SpansPerSecond == 0) {
+		return fmt.Errorf("spans per second %v must be positive", q.SpansPerSecond)
+	}
+	if q.Burst < 0 {
+		return fmt.Errorf("burst %v must not be negative", q.Burst)
+	}
+	return nil
+}
+
+func parseQuotas(q *quotas) *storedQuotas {
+	stored := &storedQuotas{
+		serviceQuotas:   make(map[string]quota),
+		operationQuotas: make(map[bucketKey]quota),
+	}
+	if q.DefaultQuota != nil {
+		dq := withDefaultBurst(*q.DefaultQuota)
+		stored.defaultQuota = &dq
+	}
+	for _, sq := range q.ServiceQuotas {
+		if sq.SpansPerSecond > 0 {
+			stored.serviceQuotas[sq.Service] = withDefaultBurst(sq.quota)
+		}
+		for _, oq := range sq.OperationQuotas {
+			stored.operationQuotas[bucketKey{service: sq.Service, operation: oq.Operation}] = withDefaultBurst(oq.quota)
+		}
+	}
+	return stored
+}
+
+// withDefaultBurst sets the burst of the quota to one second worth of spans if it is not set.
+func withDefaultBurst(q quota) quota {
+	if q.Burst == 0 {
+		q.Burst = math.Max(q.SpansPerSecond, 1)
+	}
+	return q
+}
@@ -0,0 +1,235 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) timeNow() time.Time {
+	return c.now
+}
+
+func makeSpans(n int, service string, operation string) []*model.Span {
+	spans := make([]*model.Span, n)
+	for i := range spans {
+		spans[i] = &model.Span{OperationName: operation, Process: model.NewProcess(service, nil)}
+	}
+	return spans
+}
+
+func newTestLimiter(t *testing.T, file string) (*Limiter, *fakeClock) {
+	l, err := NewLimiter(Options{QuotaFile: file}, metrics.NullFactory, zap.NewNop())
+	require.NoError(t, err)
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	l.timeNow = clock.timeNow
+	return l, clock
+}
+
+func TestNewLimiterErrors(t *testing.T) {
+	_, err := NewLimiter(Options{QuotaFile: "fileNotFound.json"}, metrics.NullFactory, zap.NewNop())
+	assert.EqualError(t, err, "Failed to open ingestion quotas file: open fileNotFound.json: no such file or directory")
+
+	_, err = NewLimiter(Options{QuotaFile: "fixtures/invalid_quotas.json"}, metrics.NullFactory, zap.NewNop())
+	assert.EqualError(t, err, "Invalid ingestion quotas: service frontend: spans per second -1 must be positive")
+}
+
+func TestLimiterQuotas(t *testing.T) {
+	l, clock := newTestLimiter(t, "fixtures/quotas.json")
+	defer l.Close()
+
+	tests := []struct {
+		name      string
+		service   string
+		operation string
+		allowed   int // number of spans allowed in one second, starting from a full bucket
+	}{
+		{name: "default quota", service: "backend", operation: "get", allowed: 200 + 100},
+		{name: "service quota", service: "frontend", operation: "/", allowed: 10 + 10},
+		{name: "operation quota", service: "frontend", operation: "/health", allowed: 2 + 1},
+		{name: "service with operation quotas only", service: "batch", operation: "export", allowed: 200 + 100},
+		{name: "operation quota without service quota", service: "batch", operation: "import", allowed: 5000 + 1000},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start := clock.now
+			allowed := 0
+			for i := 0; i < 10000; i++ {
+				clock.now = start.Add(time.Duration(i) * time.Second / 10000)
+				if l.AllowSpans(makeSpans(1, test.service, test.operation)) {
+					allowed++
+				}
+			}
+			assert.InDelta(t, test.allowed, allowed, 1)
+			clock.now = start.Add(time.Hour)
+		})
+	}
+}
+
+func TestLimiterBatches(t *testing.T) {
+	l, clock := newTestLimiter(t, "fixtures/quotas.json")
+	defer l.Close()
+
+	frontend := makeSpans(8, "frontend", "/")
+	health := makeSpans(2, "frontend", "/health")
+	assert.True(t, l.AllowSpans(append(frontend, health...)))
+
+	// the batch is rejected as a whole when one of its quotas is exceeded, without using the other quotas
+	assert.False(t, l.AllowSpans(append(makeSpans(2, "frontend", "/"), health[0])))
+	assert.True(t, l.AllowSpans(makeSpans(2, "frontend", "/")))
+	assert.False(t, l.AllowSpans(makeSpans(1, "frontend", "/")))
+
+	// a batch larger than the burst is admitted once the bucket is full
+	clock.now = clock.now.Add(time.Hour)
+	assert.True(t, l.AllowSpans(makeSpans(50, "frontend", "/")))
+	clock.now = clock.now.Add(3 * time.Second)
+	assert.False(t, l.AllowSpans(makeSpans(1, "frontend", "/")))
+}
+
+func TestLimiterUnlimited(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quotas")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "quotas.json")
+	require.NoError(t, ioutil.WriteFile(file, []byte(`{"service_quotas": [{"service": "frontend", "spans_per_second": 1}]}`), 0644))
+
+	l, _ := newTestLimiter(t, file)
+	defer l.Close()
+	assert.True(t, l.AllowSpans(makeSpans(1000, "backend", "get")))
+	assert.True(t, l.AllowSpans([]*model.Span{{}}))
+	assert.True(t, l.AllowSpans(makeSpans(1, "frontend", "get")))
+	assert.False(t, l.AllowSpans(makeSpans(1, "frontend", "get")))
+}
+
+func TestLimiterDiscardsFullBuckets(t *testing.T) {
+	l, clock := newTestLimiter(t, "fixtures/quotas.json")
+	defer l.Close()
+
+	assert.True(t, l.AllowSpans(makeSpans(200, "backend", "get")))
+	for i := 0; len(l.buckets) < maxBuckets; i++ {
+		l.buckets[bucketKey{service: strconv.Itoa(i)}] = newTokenBucket(quota{SpansPerSecond: 1, Burst: 1}, clock.now)
+	}
+	clock.now = clock.now.Add(time.Second)
+	assert.True(t, l.AllowSpans(makeSpans(1, "frontend", "/")))
+	// only the bucket of backend, which is not full yet, is kept along with the new bucket
+	assert.Len(t, l.buckets, 2)
+	assert.False(t, l.AllowSpans(makeSpans(101, "backend", "get")))
+}
+
+func TestValidateQuotas(t *testing.T) {
+	tests := []struct {
+		content string
+		err     string
+	}{
+		{content: `{}`},
+		{content: `[]`, err: "Failed to unmarshal ingestion quotas: json: cannot unmarshal array into Go value of type ratelimit.quotas"},
+		{
+			content: `{"default_quota": {"spans_per_second": 0}}`,
+			err:     "Invalid ingestion quotas: default quota: spans per second 0 must be positive",
+		},
+		{
+			content: `{"default_quota": {"spans_per_second": 1, "burst": -1}}`,
+			err:     "Invalid ingestion quotas: default quota: burst -1 must not be negative",
+		},
+		{
+			content: `{"service_quotas": [{"service": "", "spans_per_second": 1}]}`,
+			err:     "Invalid ingestion quotas: service name must not be empty",
+		},
+		{
+			content: `{"service_quotas": [{"service": "foo", "spans_per_second": 1}, {"service": "foo", "spans_per_second": 1}]}`,
+			err:     "Invalid ingestion quotas: duplicate quota for service foo",
+		},
+		{
+			content: `{"service_quotas": [{"service": "foo"}]}`,
+			err:     "Invalid ingestion quotas: service foo: spans per second 0 must be positive",
+		},
+		{
+			content: `{"service_quotas": [{"service": "foo", "operation_quotas": [{"operation": "", "spans_per_second": 1}]}]}`,
+			err:     "Invalid ingestion quotas: operation name must not be empty in service foo",
+		},
+		{
+			content: `{"service_quotas": [{"service": "foo", "operation_quotas": [{"operation": "a", "spans_per_second": 1}, {"operation": "a", "spans_per_second": 1}]}]}`,
+			err:     "Invalid ingestion quotas: duplicate quota for operation a of service foo",
+		},
+		{
+			content: `{"service_quotas": [{"service": "foo", "operation_quotas": [{"operation": "a"}]}]}`,
+			err:     "Invalid ingestion quotas: operation a of service foo: spans per second 0 must be positive",
+		},
+	}
+	for _, test := range tests {
+		_, err := loadQuotas([]byte(test.content))
+		if test.err == "" {
+			assert.NoError(t, err, test.content)
+		} else {
+			assert.EqualError(t, err, test.err, test.content)
+		}
+	}
+}
+
+func TestReloadQuotas(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quotas")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "quotas.json")
+	writeQuotas := func(content string) {
+		// write to a temporary file and rename it, so that the limiter never observes a partial write
+		tmp := file + ".tmp"
+		require.NoError(t, ioutil.WriteFile(tmp, []byte(content), 0644))
+		require.NoError(t, os.Rename(tmp, file))
+	}
+	writeQuotas(`{"default_quota": {"spans_per_second": 1, "burst": 1}}`)
+
+	metricsFactory := metricstest.NewFactory(0)
+	l, err := NewLimiter(Options{QuotaFile: file, ReloadInterval: time.Hour}, metricsFactory, zap.NewNop())
+	require.NoError(t, err)
+	defer l.Close()
+	assert.True(t, l.AllowSpans(makeSpans(1, "foo", "bar")))
+	assert.False(t, l.AllowSpans(makeSpans(1, "foo", "bar")))
+
+	// the token buckets start full with the new quotas
+	writeQuotas(`{"default_quota": {"spans_per_second": 1, "burst": 2}}`)
+	l.reloader.Reload()
+	assert.True(t, l.AllowSpans(makeSpans(2, "foo", "bar")))
+
+	// the last good quotas are kept when the new content is invalid
+	writeQuotas(`{"default_quota": {"spans_per_second": -1}}`)
+	l.reloader.Reload()
+	assert.False(t, l.AllowSpans(makeSpans(1, "foo", "bar")))
+
+	require.NoError(t, os.Remove(file))
+	l.reloader.Reload()
+	metricsFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "ingestion_quotas.reloads", Tags: map[string]string{"result": "ok"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "ingestion_quotas.reloads", Tags: map[string]string{"result": "err"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "ingestion_quotas.read-errors", Value: 1},
+	)
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"flag"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	quotaFile      = "collector.rate-limit.quota-file"
+	reloadInterval = "collector.rate-limit.reload-interval"
+)
+
+// Options holds configuration for the ingestion rate limiter.
+type Options struct {
+	// QuotaFile is the path for the ingestion quotas file in JSON format
+	QuotaFile string
+	// ReloadInterval is the interval at which the quota file is checked for changes, 0 disables reloading
+	ReloadInterval time.Duration
+}
+
+// AddFlags adds flags for Options
+func AddFlags(flagSet *flag.FlagSet) {
+	flagSet.String(quotaFile, "", "The path for the per-service ingestion quotas file in JSON format. The spans are not rate limited if empty")
+	flagSet.Duration(reloadInterval, 0, "The interval at which the ingestion quotas file is checked for changes and reloaded. Zero value means no reloading")
+}
+
+// InitFromViper initializes Options with properties from viper
+func (opts *Options) InitFromViper(v *viper.Viper) *Options {
+	opts.QuotaFile = v.GetString(quotaFile)
+	opts.ReloadInterval = v.GetDuration(reloadInterval)
+	return opts
+}
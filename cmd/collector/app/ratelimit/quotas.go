@@ -0,0 +1,41 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+// quota allows SpansPerSecond spans on average, in bursts of up to Burst spans.
+type quota struct {
+	SpansPerSecond float64 `json:"spans_per_second"`
+	Burst          float64 `json:"burst"`
+}
+
+// operationQuota defines the quota of the spans of one operation, they are not counted in the quota of the service.
+type operationQuota struct {
+	Operation string `json:"operation"`
+	quota
+}
+
+// serviceQuota defines the quota of the spans of a service, it replaces the default quota. The quota
+// of the service is optional when it only has operation quotas.
+type serviceQuota struct {
+	Service string `json:"service"`
+	quota
+	OperationQuotas []*operationQuota `json:"operation_quotas"`
+}
+
+// quotas holds the default quota, applied to every service without its own quota, and the service specific quotas.
+type quotas struct {
+	DefaultQuota  *quota          `json:"default_quota"`
+	ServiceQuotas []*serviceQuota `json:"service_quotas"`
+}
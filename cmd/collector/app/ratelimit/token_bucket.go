@@ -0,0 +1,61 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// tokenBucket accumulates creditsPerSecond credits up to maxBalance, every admitted span spending one credit.
+type tokenBucket struct {
+	creditsPerSecond float64
+	maxBalance       float64
+	balance          float64
+	lastTick         time.Time
+}
+
+func newTokenBucket(q quota, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		creditsPerSecond: q.SpansPerSecond,
+		maxBalance:       q.Burst,
+		balance:          q.Burst,
+		lastTick:         now,
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.lastTick)
+	if elapsed <= 0 {
+		return
+	}
+	b.lastTick = now
+	b.balance = math.Min(b.maxBalance, b.balance+elapsed.Seconds()*b.creditsPerSecond)
+}
+
+// canSpend returns true if the bucket has enough credits for n spans. A batch larger than
+// the burst is admitted once the bucket is full, so that it does not get rejected forever;
+// the balance then goes negative and the following batches wait for the debt to be repaid.
+func (b *tokenBucket) canSpend(n float64) bool {
+	return b.balance >= math.Min(n, b.maxBalance)
+}
+
+func (b *tokenBucket) spend(n float64) {
+	b.balance -= n
+}
+
+func (b *tokenBucket) isFull() bool {
+	return b.balance >= b.maxBalance
+}
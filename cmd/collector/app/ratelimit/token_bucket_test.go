@@ -0,0 +1,52 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Unix(1000, 0)
+	b := newTokenBucket(quota{SpansPerSecond: 2, Burst: 4}, now)
+	assert.True(t, b.isFull())
+	assert.True(t, b.canSpend(4))
+	b.spend(3)
+	assert.False(t, b.canSpend(2))
+
+	b.refill(now.Add(500 * time.Millisecond))
+	assert.True(t, b.canSpend(2))
+	assert.False(t, b.isFull())
+
+	// the balance never exceeds the burst
+	b.refill(now.Add(time.Hour))
+	assert.True(t, b.isFull())
+	assert.Equal(t, 4.0, b.balance)
+
+	// a batch larger than the burst is admitted by a full bucket and leaves a debt
+	assert.True(t, b.canSpend(10))
+	b.spend(10)
+	b.refill(now.Add(time.Hour + 3*time.Second))
+	assert.False(t, b.canSpend(1))
+	b.refill(now.Add(time.Hour + 4*time.Second))
+	assert.True(t, b.canSpend(1))
+
+	// the clock going backwards does not remove credits
+	b.refill(now)
+	assert.Equal(t, 2.0, b.balance)
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
+	"github.com/jaegertracing/jaeger/model"
+)
+
+const (
+	samplerTypeTag  = "sampler.type"
+	samplerParamTag = "sampler.param"
+)
+
+// HandleRootSpan returns a ProcessSpan that records the throughput of root spans
+// in the aggregator used by adaptive sampling.
+func HandleRootSpan(aggregator strategystore.Aggregator, logger *zap.Logger) ProcessSpan {
+	return func(span *model.Span) {
+		// TODO simply checking parentId to determine if a span is a root span is not sufficient. However,
+		// we can be sure that only a root span will have sampler tags.
+		if span.ParentSpanID() != model.SpanID(0) {
+			return
+		}
+		service := span.Process.ServiceName
+		if service == "" || span.OperationName == "" {
+			return
+		}
+		samplerType, samplerParam, ok := getSamplerParams(span, logger)
+		if !ok {
+			return
+		}
+		aggregator.RecordThroughput(service, span.OperationName, samplerType, samplerParam)
+	}
+}
+
+// getSamplerParams returns the sampler type and parameter of a span sampled by
+// either the probabilistic or the lower bound sampler.
+func getSamplerParams(span *model.Span, logger *zap.Logger) (string, float64, bool) {
+	tags := model.KeyValues(span.Tags)
+	tag, ok := tags.FindByKey(samplerTypeTag)
+	if !ok {
+		return "", 0, false
+	}
+	if tag.VType != model.StringType {
+		logger.Debug("sampler.type tag is not a string", zap.Any("tag", tag))
+		return "", 0, false
+	}
+	samplerType := tag.AsString()
+	if samplerType != samplerTypeProbabilistic && samplerType != samplerTypeLowerBound {
+		return "", 0, false
+	}
+	tag, ok = tags.FindByKey(samplerParamTag)
+	if !ok {
+		return "", 0, false
+	}
+	if tag.VType != model.Float64Type {
+		logger.Debug("sampler.param tag is not a float", zap.Any("tag", tag))
+		return "", 0, false
+	}
+	return samplerType, tag.Float64(), true
+}
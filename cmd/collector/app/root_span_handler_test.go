@@ -0,0 +1,110 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+type mockAggregator struct {
+	callCount   int
+	samplerType string
+	probability float64
+}
+
+func (t *mockAggregator) RecordThroughput(service, operation, samplerType string, probability float64) {
+	t.callCount++
+	t.samplerType = samplerType
+	t.probability = probability
+}
+func (t *mockAggregator) Start()       {}
+func (t *mockAggregator) Close() error { return nil }
+
+func TestHandleRootSpan(t *testing.T) {
+	aggregator := &mockAggregator{}
+	processor := HandleRootSpan(aggregator, zap.NewNop())
+
+	// Testing non-root span
+	span := &model.Span{References: []model.SpanRef{{SpanID: model.SpanID(1), RefType: model.ChildOf}}}
+	processor(span)
+	assert.Equal(t, 0, aggregator.callCount)
+
+	// Testing span with service name but no operation
+	span.References = []model.SpanRef{}
+	span.Process = &model.Process{ServiceName: "service"}
+	processor(span)
+	assert.Equal(t, 0, aggregator.callCount)
+
+	// Testing span with service name and operation but no sampler tags
+	span.OperationName = "GET"
+	processor(span)
+	assert.Equal(t, 0, aggregator.callCount)
+
+	// Testing span with a sampler type that is not used by adaptive sampling
+	span.Tags = model.KeyValues{
+		model.String("sampler.type", "const"),
+		model.Bool("sampler.param", true),
+	}
+	processor(span)
+	assert.Equal(t, 0, aggregator.callCount)
+
+	// Testing span with a non-string sampler type
+	span.Tags = model.KeyValues{
+		model.Int64("sampler.type", 1),
+		model.Float64("sampler.param", 0.001),
+	}
+	processor(span)
+	assert.Equal(t, 0, aggregator.callCount)
+
+	// Testing span with a missing sampler param
+	span.Tags = model.KeyValues{
+		model.String("sampler.type", "probabilistic"),
+	}
+	processor(span)
+	assert.Equal(t, 0, aggregator.callCount)
+
+	// Testing span with a non-float sampler param
+	span.Tags = model.KeyValues{
+		model.String("sampler.type", "probabilistic"),
+		model.String("sampler.param", "0.001"),
+	}
+	processor(span)
+	assert.Equal(t, 0, aggregator.callCount)
+
+	// Testing span with probabilistic sampler tags
+	span.Tags = model.KeyValues{
+		model.String("sampler.type", "probabilistic"),
+		model.Float64("sampler.param", 0.001),
+	}
+	processor(span)
+	assert.Equal(t, 1, aggregator.callCount)
+	assert.Equal(t, "probabilistic", aggregator.samplerType)
+	assert.Equal(t, 0.001, aggregator.probability)
+
+	// Testing span with lowerbound sampler tags
+	span.Tags = model.KeyValues{
+		model.String("sampler.type", "lowerbound"),
+		model.Float64("sampler.param", 0.002),
+	}
+	processor(span)
+	assert.Equal(t, 2, aggregator.callCount)
+	assert.Equal(t, "lowerbound", aggregator.samplerType)
+	assert.Equal(t, 0.002, aggregator.probability)
+}
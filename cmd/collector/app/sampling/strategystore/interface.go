@@ -15,6 +15,8 @@
 package strategystore
 
 import (
+	"io"
+
 	"github.com/jaegertracing/jaeger/thrift-gen/sampling"
 )
 
@@ -23,3 +25,15 @@ type StrategyStore interface {
 	// GetSamplingStrategy retrieves the sampling strategy for the specified service.
 	GetSamplingStrategy(serviceName string) (*sampling.SamplingStrategyResponse, error)
 }
+
+// Aggregator defines an interface used to aggregate operation throughput.
+type Aggregator interface {
+	// Close stops the aggregator from aggregating throughput.
+	io.Closer
+
+	// RecordThroughput records throughput for an operation for aggregation.
+	RecordThroughput(service, operation, samplerType string, probability float64)
+
+	// Start starts aggregating operation throughput.
+	Start()
+}
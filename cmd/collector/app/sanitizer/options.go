@@ -0,0 +1,47 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sanitizer
+
+import (
+	"flag"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	redactionRulesFile   = "collector.sanitizer.redaction-rules-file"
+	redactionHashKeyFile = "collector.sanitizer.redaction-hash-key-file"
+)
+
+// Options holds configuration for the span sanitizers.
+type Options struct {
+	// RedactionRulesFile is the path for the redaction rules file in YAML or JSON format
+	RedactionRulesFile string
+	// RedactionHashKeyFile is the path for the file holding the secret key of the hash redaction action
+	RedactionHashKeyFile string
+}
+
+// AddFlags adds flags for Options
+func AddFlags(flagSet *flag.FlagSet) {
+	flagSet.String(redactionRulesFile, "", "The path for the file with the rules dropping, redacting and truncating span tags, process tags and log fields, in YAML or JSON format. The spans are not redacted if empty")
+	flagSet.String(redactionHashKeyFile, "", "The path for the file holding the secret key of the HMAC-SHA256 hashes of the hash redaction action, required by the redaction rules using it")
+}
+
+// InitFromViper initializes Options with properties from viper
+func (opts *Options) InitFromViper(v *viper.Viper) *Options {
+	opts.RedactionRulesFile = v.GetString(redactionRulesFile)
+	opts.RedactionHashKeyFile = v.GetString(redactionHashKeyFile)
+	return opts
+}
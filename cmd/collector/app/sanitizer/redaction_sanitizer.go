@@ -0,0 +1,254 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sanitizer
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+const (
+	// HashAction replaces the parts of a value matching a value rule with their HMAC-SHA256 hash keyed with the
+	// redaction hash key, a plain hash of low-entropy values like emails or card numbers could be reversed
+	HashAction = "hash"
+	// MaskAction replaces the parts of a value matching a value rule with a mask
+	MaskAction = "mask"
+
+	defaultMask = "****"
+	hashPrefix  = "hmac-sha256:"
+)
+
+// RedactionRules describes which tags and log fields are dropped, redacted or truncated by the redaction sanitizer.
+// The rules apply to span tags, process tags and log fields alike.
+type RedactionRules struct {
+	// DropKeys lists the keys of the tags and log fields that are removed from the spans
+	DropKeys []string `yaml:"drop_keys"`
+	// DropKeyPatterns lists regular expressions, the tags and log fields with a key matching any of them are removed
+	DropKeyPatterns []string `yaml:"drop_key_patterns"`
+	// ValueRules are applied in order to the string values of the remaining tags and log fields. The integer
+	// values are redacted only by the rules listing their key, in their decimal form, and become strings
+	// if redacted. The other values are not redacted.
+	ValueRules []ValueRule `yaml:"value_rules"`
+	// MaxValueLength is the maximum length in bytes of string and binary values, 0 means no limit
+	MaxValueLength int `yaml:"max_value_length"`
+}
+
+// ValueRule redacts the parts of string values matching a regular expression.
+type ValueRule struct {
+	// Name identifies the rule in error messages
+	Name string `yaml:"name"`
+	// Pattern is the regular expression matched against the values
+	Pattern string `yaml:"pattern"`
+	// Keys optionally restricts the rule to the tags and log fields with one of these keys
+	Keys []string `yaml:"keys"`
+	// Action is either "hash" or "mask"
+	Action string `yaml:"action"`
+	// Mask replaces the matches of the "mask" action, defaults to "****"
+	Mask string `yaml:"mask"`
+}
+
+// LoadRedactionRules reads the redaction rules from a file in YAML or JSON format.
+func LoadRedactionRules(file string) (*RedactionRules, error) {
+	var rules RedactionRules
+	if err := config.LoadFile(file, "redaction rules", &rules); err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+// LoadRedactionHashKey reads the secret key of the hash action from a file, ignoring the surrounding whitespace.
+func LoadRedactionHashKey(file string) ([]byte, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to open redaction hash key file")
+	}
+	key := bytes.TrimSpace(content)
+	if len(key) == 0 {
+		return nil, fmt.Errorf("redaction hash key file %s is empty", file)
+	}
+	return key, nil
+}
+
+type valueRule struct {
+	pattern *regexp.Regexp
+	keys    map[string]struct{}
+	replace func(match string) string
+}
+
+// redactionSanitizer drops, redacts and truncates tags and log fields according to the redaction rules
+type redactionSanitizer struct {
+	dropKeys        map[string]struct{}
+	dropKeyPatterns []*regexp.Regexp
+	valueRules      []valueRule
+	maxValueLength  int
+}
+
+// NewRedactionSanitizer creates a sanitizer applying the redaction rules to span tags, process tags and log fields.
+// The hash key is required by the rules with the hash action. It returns an error if the rules are invalid.
+func NewRedactionSanitizer(rules *RedactionRules, hashKey []byte) (SanitizeSpan, error) {
+	s, err := newRedactionSanitizer(rules, hashKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid redaction rules")
+	}
+	return s.Sanitize, nil
+}
+
+func newRedactionSanitizer(rules *RedactionRules, hashKey []byte) (*redactionSanitizer, error) {
+	if rules.MaxValueLength < 0 {
+		return nil, fmt.Errorf("max value length %d must not be negative", rules.MaxValueLength)
+	}
+	s := &redactionSanitizer{
+		dropKeys:       toSet(rules.DropKeys),
+		maxValueLength: rules.MaxValueLength,
+	}
+	for _, p := range rules.DropKeyPatterns {
+		pattern, err := regexp.Compile(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "drop key pattern %q", p)
+		}
+		s.dropKeyPatterns = append(s.dropKeyPatterns, pattern)
+	}
+	for _, r := range rules.ValueRules {
+		if r.Name == "" {
+			return nil, errors.New("value rule name must not be empty")
+		}
+		if r.Pattern == "" {
+			return nil, fmt.Errorf("value rule %s: pattern must not be empty", r.Name)
+		}
+		pattern, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "value rule %s", r.Name)
+		}
+		rule := valueRule{pattern: pattern, keys: toSet(r.Keys)}
+		switch r.Action {
+		case HashAction:
+			if len(hashKey) == 0 {
+				return nil, fmt.Errorf("value rule %s: the hash action requires a redaction hash key", r.Name)
+			}
+			rule.replace = hashValue(hashKey)
+		case MaskAction:
+			mask := r.Mask
+			if mask == "" {
+				mask = defaultMask
+			}
+			rule.replace = func(string) string { return mask }
+		default:
+			return nil, fmt.Errorf("value rule %s: unknown action %q, expected %q or %q", r.Name, r.Action, HashAction, MaskAction)
+		}
+		s.valueRules = append(s.valueRules, rule)
+	}
+	return s, nil
+}
+
+// Sanitize drops, redacts and truncates the span tags, process tags and log fields.
+func (s *redactionSanitizer) Sanitize(span *model.Span) *model.Span {
+	span.Tags = s.sanitizeKV(span.Tags)
+	if span.Process != nil {
+		span.Process.Tags = s.sanitizeKV(span.Process.Tags)
+	}
+	for i := range span.Logs {
+		span.Logs[i].Fields = s.sanitizeKV(span.Logs[i].Fields)
+	}
+	return span
+}
+
+func (s *redactionSanitizer) sanitizeKV(keyValues model.KeyValues) model.KeyValues {
+	kept := keyValues[:0]
+	for _, kv := range keyValues {
+		if s.drop(kv.Key) {
+			continue
+		}
+		switch kv.VType {
+		case model.StringType:
+			kv.VStr = s.truncateString(s.redact(kv.Key, kv.VStr, false))
+		case model.Int64Type:
+			value := strconv.FormatInt(kv.VInt64, 10)
+			if redacted := s.redact(kv.Key, value, true); redacted != value {
+				kv = model.String(kv.Key, s.truncateString(redacted))
+			}
+		case model.BinaryType:
+			if s.maxValueLength > 0 && len(kv.VBinary) > s.maxValueLength {
+				kv.VBinary = kv.VBinary[:s.maxValueLength]
+			}
+		}
+		kept = append(kept, kv)
+	}
+	return kept
+}
+
+func (s *redactionSanitizer) drop(key string) bool {
+	if _, ok := s.dropKeys[key]; ok {
+		return true
+	}
+	for _, pattern := range s.dropKeyPatterns {
+		if pattern.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// redact applies the value rules of the key to the value, only the rules listing the key if keyedOnly is set
+func (s *redactionSanitizer) redact(key string, value string, keyedOnly bool) string {
+	for _, rule := range s.valueRules {
+		if len(rule.keys) > 0 || keyedOnly {
+			if _, ok := rule.keys[key]; !ok {
+				continue
+			}
+		}
+		value = rule.pattern.ReplaceAllStringFunc(value, rule.replace)
+	}
+	return value
+}
+
+// truncateString truncates the value to the max value length without splitting a UTF-8 character.
+func (s *redactionSanitizer) truncateString(value string) string {
+	if s.maxValueLength == 0 || len(value) <= s.maxValueLength {
+		return value
+	}
+	end := s.maxValueLength
+	for end > 0 && !utf8.RuneStart(value[end]) {
+		end--
+	}
+	return value[:end]
+}
+
+func hashValue(key []byte) func(value string) string {
+	return func(value string) string {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(value))
+		return hashPrefix + hex.EncodeToString(mac.Sum(nil))
+	}
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,216 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sanitizer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+var testHashKey = []byte("secret")
+
+func TestLoadRedactionRules(t *testing.T) {
+	yamlRules, err := LoadRedactionRules("fixtures/redaction_rules.yaml")
+	require.NoError(t, err)
+	jsonRules, err := LoadRedactionRules("fixtures/redaction_rules.json")
+	require.NoError(t, err)
+	assert.Equal(t, yamlRules, jsonRules)
+	assert.Equal(t, []string{"password"}, yamlRules.DropKeys)
+	assert.Len(t, yamlRules.ValueRules, 4)
+	assert.Equal(t, 32, yamlRules.MaxValueLength)
+
+	_, err = LoadRedactionRules("fileNotFound.yaml")
+	assert.EqualError(t, err, "Failed to open redaction rules file: open fileNotFound.yaml: no such file or directory")
+
+	rules, err := LoadRedactionRules("fixtures/invalid_redaction_rules.yaml")
+	require.NoError(t, err)
+	_, err = NewRedactionSanitizer(rules, testHashKey)
+	assert.EqualError(t, err, "Invalid redaction rules: value rule email: error parsing regexp: missing closing ]: `[a-z`")
+}
+
+func TestLoadRedactionHashKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "redaction")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	keyFile := filepath.Join(dir, "key")
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte("secret\n"), 0600))
+	key, err := LoadRedactionHashKey(keyFile)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), key)
+
+	emptyFile := filepath.Join(dir, "empty")
+	require.NoError(t, ioutil.WriteFile(emptyFile, []byte(" \n"), 0600))
+	_, err = LoadRedactionHashKey(emptyFile)
+	assert.EqualError(t, err, "redaction hash key file "+emptyFile+" is empty")
+
+	_, err = LoadRedactionHashKey(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestParseRedactionRules(t *testing.T) {
+	var rules RedactionRules
+	err := config.Unmarshal([]byte(`drop_key: [password]`), "redaction rules", &rules)
+	assert.EqualError(t, err, "Failed to unmarshal redaction rules: yaml: unmarshal errors:\n  line 1: field drop_key not found in type sanitizer.RedactionRules")
+}
+
+func TestValidateRedactionRules(t *testing.T) {
+	tests := []struct {
+		rules   RedactionRules
+		hashKey []byte
+		err     string
+	}{
+		{rules: RedactionRules{}},
+		{
+			rules: RedactionRules{MaxValueLength: -1},
+			err:   "Invalid redaction rules: max value length -1 must not be negative",
+		},
+		{
+			rules: RedactionRules{DropKeyPatterns: []string{"("}},
+			err:   "Invalid redaction rules: drop key pattern \"(\": error parsing regexp: missing closing ): `(`",
+		},
+		{
+			rules: RedactionRules{ValueRules: []ValueRule{{Pattern: "a", Action: MaskAction}}},
+			err:   "Invalid redaction rules: value rule name must not be empty",
+		},
+		{
+			rules: RedactionRules{ValueRules: []ValueRule{{Name: "a", Action: MaskAction}}},
+			err:   "Invalid redaction rules: value rule a: pattern must not be empty",
+		},
+		{
+			rules: RedactionRules{ValueRules: []ValueRule{{Name: "a", Pattern: "a", Action: "encrypt"}}},
+			err:   "Invalid redaction rules: value rule a: unknown action \"encrypt\", expected \"hash\" or \"mask\"",
+		},
+		{
+			rules:   RedactionRules{ValueRules: []ValueRule{{Name: "a", Pattern: "a", Action: HashAction}}},
+			hashKey: testHashKey,
+		},
+		{
+			rules: RedactionRules{ValueRules: []ValueRule{{Name: "a", Pattern: "a", Action: HashAction}}},
+			err:   "Invalid redaction rules: value rule a: the hash action requires a redaction hash key",
+		},
+	}
+	for _, test := range tests {
+		_, err := NewRedactionSanitizer(&test.rules, test.hashKey)
+		if test.err == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.EqualError(t, err, test.err)
+		}
+	}
+}
+
+func TestRedactionSanitizer(t *testing.T) {
+	rules, err := LoadRedactionRules("fixtures/redaction_rules.yaml")
+	require.NoError(t, err)
+	sanitize, err := NewRedactionSanitizer(rules, testHashKey)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		input    []model.KeyValue
+		expected []model.KeyValue
+	}{
+		{
+			name:     "drop by key",
+			input:    model.KeyValues{model.String("password", "secret"), model.String("user", "bob")},
+			expected: model.KeyValues{model.String("user", "bob")},
+		},
+		{
+			name:     "drop by key pattern",
+			input:    model.KeyValues{model.String("http.request.header.cookie", "id=1"), model.Int64("http.status_code", 200)},
+			expected: model.KeyValues{model.Int64("http.status_code", 200)},
+		},
+		{
+			name:     "hash",
+			input:    model.KeyValues{model.String("user", "bob@example.com")},
+			expected: model.KeyValues{model.String("user", hashValue(testHashKey)("bob@example.com")[:32])},
+		},
+		{
+			name:     "mask",
+			input:    model.KeyValues{model.String("card", "card 4111 1111 1111 1111")},
+			expected: model.KeyValues{model.String("card", "card ****")},
+		},
+		{
+			name:     "mask restricted to keys",
+			input:    model.KeyValues{model.String("auth", "Bearer abc"), model.String("message", "Bearer abc")},
+			expected: model.KeyValues{model.String("auth", "Bearer ****"), model.String("message", "Bearer abc")},
+		},
+		{
+			name:     "truncate",
+			input:    model.KeyValues{model.Binary("payload", make([]byte, 40)), model.String("sql", "select * from customers where id = 1")},
+			expected: model.KeyValues{model.Binary("payload", make([]byte, 32)), model.String("sql", "select * from customers where id")},
+		},
+		{
+			name:     "truncate without splitting characters",
+			input:    model.KeyValues{model.String("text", "0123456789012345678901234567890é")},
+			expected: model.KeyValues{model.String("text", "0123456789012345678901234567890")},
+		},
+		{
+			name:     "integers redacted by the rules of their key",
+			input:    model.KeyValues{model.Int64("customer.id", 4111111111111111), model.Int64("http.status_code", 200)},
+			expected: model.KeyValues{model.String("customer.id", hashValue(testHashKey)("4111111111111111")[:32]), model.Int64("http.status_code", 200)},
+		},
+		{
+			name:     "other types are kept",
+			input:    model.KeyValues{model.Bool("error", true), model.Float64("ratio", 0.5)},
+			expected: model.KeyValues{model.Bool("error", true), model.Float64("ratio", 0.5)},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			span := sanitize(&model.Span{
+				Tags:    copyKV(test.input),
+				Process: model.NewProcess("frontend", copyKV(test.input)),
+				Logs:    []model.Log{{Fields: copyKV(test.input)}},
+			})
+			assert.Equal(t, test.expected, span.Tags)
+			assert.Equal(t, test.expected, span.Process.Tags)
+			assert.Equal(t, test.expected, span.Logs[0].Fields)
+		})
+	}
+}
+
+func TestRedactionSanitizerChained(t *testing.T) {
+	redact, err := NewRedactionSanitizer(&RedactionRules{DropKeys: []string{"password"}}, nil)
+	require.NoError(t, err)
+	sanitize := NewChainedSanitizer(NewServiceNameSanitizer(&fixedMappingCache{Cache: testCache}), redact)
+	span := sanitize(&model.Span{
+		Tags:    model.KeyValues{model.String("password", "secret")},
+		Process: model.NewProcess("supply", nil),
+	})
+	assert.Equal(t, "rt-supply", span.Process.ServiceName)
+	assert.Empty(t, span.Tags)
+}
+
+func TestHashValue(t *testing.T) {
+	// HMAC-SHA256 test vector of RFC 4231, test case 2
+	assert.Equal(t,
+		"hmac-sha256:5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843",
+		hashValue([]byte("Jefe"))("what do ya want for nothing?"))
+	assert.NotEqual(t, hashValue([]byte("Jefe"))("a"), hashValue([]byte("other"))("a"))
+}
+
+func copyKV(keyValues []model.KeyValue) []model.KeyValue {
+	return append([]model.KeyValue(nil), keyValues...)
+}
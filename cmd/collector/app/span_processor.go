@@ -15,6 +15,8 @@
 package app
 
 import (
+	"errors"
+	"sync"
 	"time"
 
 	tchannel "github.com/uber/tchannel-go"
@@ -23,19 +25,27 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/queue"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
+// ErrRateLimited is returned by ProcessSpans when the spans exceed the ingestion quotas of their services.
+var ErrRateLimited = errors.New("ingestion rate limit exceeded")
+
 // ProcessSpansOptions additional options passed to processor along with the spans.
 type ProcessSpansOptions struct {
 	SpanFormat       SpanFormat
 	InboundTransport InboundTransport
+	// Tenant the spans belong to, only used when the processor is configured with tenant span writers
+	Tenant string
 }
 
 // SpanProcessor handles model spans
 type SpanProcessor interface {
 	// ProcessSpans processes model spans and return with either a list of true/false success or an error
 	ProcessSpans(mSpans []*model.Span, options ProcessSpansOptions) ([]bool, error)
+	// Stop writes the spans left in the queue and halts the span processor
+	Stop()
 }
 
 type spanProcessor struct {
@@ -43,17 +53,24 @@ type spanProcessor struct {
 	metrics         *SpanProcessorMetrics
 	preProcessSpans ProcessSpans
 	filterSpan      FilterSpan             // filter is called before the sanitizer but after preProcessSpans
-	sanitizer       sanitizer.SanitizeSpan // sanitizer is called before processSpan
-	processSpan     ProcessSpan
+	sanitizer       sanitizer.SanitizeSpan // sanitizer is called before preSave
+	preSave         ProcessSpan            // preSave is called before the span is written to storage
+	tailSampler     TailSampler            // tailSampler, if set, buffers the filtered spans before the queue
+	rateLimiter     RateLimiter            // rateLimiter, if set, is called after preProcessSpans
 	logger          *zap.Logger
 	spanWriter      spanstore.Writer
 	reportBusy      bool
 	numWorkers      int
+
+	tenantSpanWriter  TenantSpanWriter
+	tenantWritersLock sync.Mutex
+	tenantWriters     map[string]spanstore.Writer
 }
 
 type queueItem struct {
 	queuedTime time.Time
 	span       *model.Span
+	tenant     string
 }
 
 // NewSpanProcessor returns a SpanProcessor that preProcesses, filters, queues, sanitizes, and processes spans
@@ -70,6 +87,12 @@ func NewSpanProcessor(
 
 	sp.queue.StartLengthReporting(1*time.Second, sp.metrics.QueueLength)
 
+	if sp.tailSampler != nil {
+		sp.tailSampler.Start(func(item interface{}) {
+			sp.queue.Produce(item)
+		})
+	}
+
 	return sp
 }
 
@@ -85,32 +108,33 @@ func newSpanProcessor(spanWriter spanstore.Writer, opts ...Option) *spanProcesso
 	boundedQueue := queue.NewBoundedQueue(options.queueSize, droppedItemHandler)
 
 	sp := spanProcessor{
-		queue:           boundedQueue,
-		metrics:         handlerMetrics,
-		logger:          options.logger,
-		preProcessSpans: options.preProcessSpans,
-		filterSpan:      options.spanFilter,
-		sanitizer:       options.sanitizer,
-		reportBusy:      options.reportBusy,
-		numWorkers:      options.numWorkers,
-		spanWriter:      spanWriter,
+		queue:            boundedQueue,
+		metrics:          handlerMetrics,
+		logger:           options.logger,
+		preProcessSpans:  options.preProcessSpans,
+		filterSpan:       options.spanFilter,
+		sanitizer:        options.sanitizer,
+		reportBusy:       options.reportBusy,
+		numWorkers:       options.numWorkers,
+		spanWriter:       spanWriter,
+		preSave:          options.preSave,
+		tailSampler:      options.tailSampler,
+		rateLimiter:      options.rateLimiter,
+		tenantSpanWriter: options.tenantSpanWriter,
+		tenantWriters:    make(map[string]spanstore.Writer),
 	}
-	sp.processSpan = ChainedProcessSpan(
-		options.preSave,
-		sp.saveSpan,
-	)
 
 	return &sp
 }
 
-// Stop halts the span processor and all its go-routines.
+// Stop halts the span processor and all its go-routines, once the spans left in the queue are written.
 func (sp *spanProcessor) Stop() {
 	sp.queue.Stop()
 }
 
-func (sp *spanProcessor) saveSpan(span *model.Span) {
+func (sp *spanProcessor) saveSpan(span *model.Span, tenant string) {
 	startTime := time.Now()
-	if err := sp.spanWriter.WriteSpan(span); err != nil {
+	if err := sp.writeSpan(span, tenant); err != nil {
 		sp.logger.Error("Failed to save span", zap.Error(err))
 		sp.metrics.SavedErrBySvc.ReportServiceNameForSpan(span)
 	} else {
@@ -121,12 +145,49 @@ func (sp *spanProcessor) saveSpan(span *model.Span) {
 	sp.metrics.SaveLatency.Record(time.Since(startTime))
 }
 
+func (sp *spanProcessor) writeSpan(span *model.Span, tenant string) error {
+	if sp.tenantSpanWriter == nil {
+		return sp.spanWriter.WriteSpan(span)
+	}
+	writer, err := sp.getTenantWriter(tenant)
+	if err != nil {
+		return err
+	}
+	return writer.WriteSpan(span)
+}
+
+// getTenantWriter returns the writer of the tenant, creating it on first use.
+func (sp *spanProcessor) getTenantWriter(tenant string) (spanstore.Writer, error) {
+	sp.tenantWritersLock.Lock()
+	defer sp.tenantWritersLock.Unlock()
+	if writer, ok := sp.tenantWriters[tenant]; ok {
+		return writer, nil
+	}
+	writer, err := sp.tenantSpanWriter(tenant)
+	if err != nil {
+		return nil, err
+	}
+	sp.tenantWriters[tenant] = writer
+	return writer, nil
+}
+
 func (sp *spanProcessor) ProcessSpans(mSpans []*model.Span, options ProcessSpansOptions) ([]bool, error) {
+	if sp.tenantSpanWriter != nil && options.Tenant == "" {
+		return nil, tenancy.ErrMissingTenant
+	}
 	sp.preProcessSpans(mSpans)
 	sp.metrics.BatchSize.Update(int64(len(mSpans)))
+	if sp.rateLimiter != nil && !sp.rateLimiter.AllowSpans(mSpans) {
+		spanCounts := sp.metrics.GetCountsForFormat(options.SpanFormat, options.InboundTransport)
+		for _, span := range mSpans {
+			spanCounts.ReceivedBySvc.ReportServiceNameForSpan(span)
+			spanCounts.RateLimitedBySvc.ReportServiceNameForSpan(span)
+		}
+		return nil, ErrRateLimited
+	}
 	retMe := make([]bool, len(mSpans))
 	for i, mSpan := range mSpans {
-		ok := sp.enqueueSpan(mSpan, options.SpanFormat, options.InboundTransport)
+		ok := sp.enqueueSpan(mSpan, options.SpanFormat, options.InboundTransport, options.Tenant)
 		if !ok && sp.reportBusy {
 			return nil, tchannel.ErrServerBusy
 		}
@@ -136,11 +197,13 @@ func (sp *spanProcessor) ProcessSpans(mSpans []*model.Span, options ProcessSpans
 }
 
 func (sp *spanProcessor) processItemFromQueue(item *queueItem) {
-	sp.processSpan(sp.sanitizer(item.span))
+	span := sp.sanitizer(item.span)
+	sp.preSave(span)
+	sp.saveSpan(span, item.tenant)
 	sp.metrics.InQueueLatency.Record(time.Since(item.queuedTime))
 }
 
-func (sp *spanProcessor) enqueueSpan(span *model.Span, originalFormat SpanFormat, transport InboundTransport) bool {
+func (sp *spanProcessor) enqueueSpan(span *model.Span, originalFormat SpanFormat, transport InboundTransport, tenant string) bool {
 	spanCounts := sp.metrics.GetCountsForFormat(originalFormat, transport)
 	spanCounts.ReceivedBySvc.ReportServiceNameForSpan(span)
 
@@ -155,6 +218,11 @@ func (sp *spanProcessor) enqueueSpan(span *model.Span, originalFormat SpanFormat
 	item := &queueItem{
 		queuedTime: time.Now(),
 		span:       span,
+		tenant:     tenant,
+	}
+	if sp.tailSampler != nil {
+		sp.tailSampler.Add(tenant, span, item)
+		return true // the span is either buffered or dropped along with its trace by the tail sampler
 	}
 	return sp.queue.Produce(item)
 }
@@ -15,6 +15,7 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -27,7 +28,9 @@ import (
 
 	zipkinSanitizer "github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer/zipkin"
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
 	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
 	zc "github.com/jaegertracing/jaeger/thrift-gen/zipkincore"
 )
@@ -299,3 +302,130 @@ func TestSpanProcessorBusy(t *testing.T) {
 	assert.Error(t, err, "expcting busy error")
 	assert.Nil(t, res)
 }
+
+type recordingWriter struct {
+	spans []*model.Span
+}
+
+func (w *recordingWriter) WriteSpan(span *model.Span) error {
+	w.spans = append(w.spans, span)
+	return nil
+}
+
+func TestSpanProcessorTenants(t *testing.T) {
+	defaultWriter := &recordingWriter{}
+	tenantWriters := map[string]*recordingWriter{}
+	var created []string
+	p := newSpanProcessor(defaultWriter,
+		Options.QueueSize(10),
+		Options.TenantSpanWriter(func(tenant string) (spanstore.Writer, error) {
+			created = append(created, tenant)
+			if tenant == "broken" {
+				return nil, errors.New("no storage for tenant")
+			}
+			w := &recordingWriter{}
+			tenantWriters[tenant] = w
+			return w, nil
+		}),
+	)
+
+	_, err := p.ProcessSpans([]*model.Span{{Process: &model.Process{ServiceName: "x"}}}, ProcessSpansOptions{SpanFormat: JaegerSpanFormat})
+	assert.Equal(t, tenancy.ErrMissingTenant, err)
+
+	span := &model.Span{OperationName: "op", Process: &model.Process{ServiceName: "x"}}
+	p.processItemFromQueue(&queueItem{queuedTime: time.Now(), span: span, tenant: "acme"})
+	p.processItemFromQueue(&queueItem{queuedTime: time.Now(), span: span, tenant: "acme"})
+	p.processItemFromQueue(&queueItem{queuedTime: time.Now(), span: span, tenant: "megacorp"})
+	p.processItemFromQueue(&queueItem{queuedTime: time.Now(), span: span, tenant: "broken"})
+
+	assert.Empty(t, defaultWriter.spans)
+	assert.Len(t, tenantWriters["acme"].spans, 2)
+	assert.Len(t, tenantWriters["megacorp"].spans, 1)
+	assert.Equal(t, []string{"acme", "megacorp", "broken"}, created)
+}
+
+type fakeTailSampler struct {
+	consumer func(item interface{})
+	added    []*model.Span
+	tenants  []string
+}
+
+func (s *fakeTailSampler) Start(consumer func(item interface{})) {
+	s.consumer = consumer
+}
+
+func (s *fakeTailSampler) Add(tenant string, span *model.Span, item interface{}) {
+	s.added = append(s.added, span)
+	s.tenants = append(s.tenants, tenant)
+	if span.Process.ServiceName == "keep" {
+		s.consumer(item)
+	}
+}
+
+func TestSpanProcessorTailSampler(t *testing.T) {
+	sampler := &fakeTailSampler{}
+	saved := make(chan *model.Span, 2)
+	p := NewSpanProcessor(&fakeSpanWriter{},
+		Options.TailSampler(sampler),
+		Options.SpanFilter(isSpanAllowed),
+		Options.PreSave(func(span *model.Span) {
+			saved <- span
+		}),
+	).(*spanProcessor)
+	defer p.Stop()
+
+	res, err := p.ProcessSpans([]*model.Span{
+		{OperationName: "a", Process: &model.Process{ServiceName: "keep"}},
+		{OperationName: "b", Process: &model.Process{ServiceName: "drop"}},
+		{OperationName: "c", Process: &model.Process{ServiceName: blackListedService}},
+	}, ProcessSpansOptions{SpanFormat: JaegerSpanFormat, Tenant: "acme"})
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, true, true}, res)
+
+	// filtered out spans do not reach the tail sampler
+	assert.Len(t, sampler.added, 2)
+	assert.Equal(t, []string{"acme", "acme"}, sampler.tenants)
+	select {
+	case span := <-saved:
+		assert.Equal(t, "a", span.OperationName)
+	case <-time.After(time.Second):
+		t.Fatal("the span kept by the tail sampler was not saved")
+	}
+	select {
+	case span := <-saved:
+		t.Fatalf("unexpected span saved: %v", span.OperationName)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+type fakeRateLimiter struct {
+	allow bool
+}
+
+func (l fakeRateLimiter) AllowSpans(spans []*model.Span) bool {
+	return l.allow
+}
+
+func TestSpanProcessorRateLimited(t *testing.T) {
+	serviceMetrics := metricstest.NewFactory(0)
+	spans := []*model.Span{
+		{Process: &model.Process{ServiceName: "noisy"}},
+		{Process: &model.Process{ServiceName: "noisy"}},
+	}
+
+	p := newSpanProcessor(&fakeSpanWriter{}, Options.ServiceMetrics(serviceMetrics), Options.RateLimiter(fakeRateLimiter{allow: false}))
+	res, err := p.ProcessSpans(spans, ProcessSpansOptions{SpanFormat: JaegerSpanFormat, InboundTransport: GRPCTransport})
+	assert.Equal(t, ErrRateLimited, err)
+	assert.Nil(t, res)
+	assert.Equal(t, 0, p.queue.Size())
+	serviceMetrics.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "spans.rate-limited|debug=false|format=jaeger|svc=noisy|transport=grpc", Value: 2},
+		metricstest.ExpectedMetric{Name: "spans.received|debug=false|format=jaeger|svc=noisy|transport=grpc", Value: 2},
+	)
+
+	p = newSpanProcessor(&fakeSpanWriter{}, Options.QueueSize(10), Options.RateLimiter(fakeRateLimiter{allow: true}))
+	res, err = p.ProcessSpans(spans, ProcessSpansOptions{SpanFormat: JaegerSpanFormat})
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, true}, res)
+	assert.Equal(t, 2, p.queue.Size())
+}
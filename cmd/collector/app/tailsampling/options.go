@@ -0,0 +1,117 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsampling
+
+import (
+	"flag"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	prefix            = "collector.tail-sampling."
+	enabled           = prefix + "enabled"
+	decisionWait      = prefix + "decision-wait"
+	maxTraces         = prefix + "max-traces"
+	maxSpans          = prefix + "max-spans"
+	keepErrors        = prefix + "policy.errors"
+	latencyThreshold  = prefix + "policy.latency-threshold"
+	operations        = prefix + "policy.operations"
+	probabilisticRate = prefix + "policy.probabilistic-rate"
+
+	defaultDecisionWait      = 10 * time.Second
+	defaultMaxTraces         = 50000
+	defaultMaxSpans          = 1000000
+	defaultProbabilisticRate = 0.01
+)
+
+// Options holds configuration for the tail-based sampler.
+type Options struct {
+	// Enabled turns on tail-based sampling of the spans received by the collector.
+	Enabled bool
+
+	// DecisionWait is how long the spans of a trace are buffered, counting from its first span,
+	// before the policies decide whether the trace is kept.
+	DecisionWait time.Duration
+
+	// MaxTraces is the maximum number of traces buffered at the same time. When the limit
+	// is reached, the decision on the oldest trace is made before the decision wait elapsed.
+	// It also bounds the number of recent decisions remembered for the late spans.
+	MaxTraces int
+
+	// MaxSpans is the maximum number of spans buffered at the same time, enforced like MaxTraces.
+	MaxSpans int
+
+	// KeepErrors keeps the traces with at least one span tagged with error=true.
+	KeepErrors bool
+
+	// LatencyThreshold keeps the traces lasting longer than the threshold, 0 disables the policy.
+	LatencyThreshold time.Duration
+
+	// Operations keeps the traces with a span of one of the services, given either
+	// as "service" or as "service:operation" to only match one operation.
+	Operations []string
+
+	// ProbabilisticRate is the fraction of the traces kept when no other policy matches.
+	ProbabilisticRate float64
+}
+
+// AddFlags adds flags for Options
+func AddFlags(flagSet *flag.FlagSet) {
+	flagSet.Bool(enabled, false,
+		"Buffer the received spans by trace and only write the traces kept by the tail sampling policies.",
+	)
+	flagSet.Duration(decisionWait, defaultDecisionWait,
+		"How long the spans of a trace are buffered, counting from its first span, before deciding whether the trace is kept.",
+	)
+	flagSet.Int(maxTraces, defaultMaxTraces,
+		"The maximum number of traces buffered by the tail sampler, the oldest trace is decided early above this limit. It also bounds the number of recent decisions remembered for the late spans.",
+	)
+	flagSet.Int(maxSpans, defaultMaxSpans,
+		"The maximum number of spans buffered by the tail sampler, the oldest trace is decided early above this limit.",
+	)
+	flagSet.Bool(keepErrors, true,
+		"Keep the traces with at least one span tagged with error=true.",
+	)
+	flagSet.Duration(latencyThreshold, 0,
+		"Keep the traces lasting longer than this threshold (0 disables the policy).",
+	)
+	flagSet.String(operations, "",
+		"Comma-separated list of services, optionally followed by ':operation', whose traces are always kept.",
+	)
+	flagSet.Float64(probabilisticRate, defaultProbabilisticRate,
+		"The fraction, between 0 and 1, of the traces kept when no other policy matches.",
+	)
+}
+
+// InitFromViper initializes Options with properties from viper
+func (opts Options) InitFromViper(v *viper.Viper) Options {
+	opts.Enabled = v.GetBool(enabled)
+	opts.DecisionWait = v.GetDuration(decisionWait)
+	opts.MaxTraces = v.GetInt(maxTraces)
+	opts.MaxSpans = v.GetInt(maxSpans)
+	opts.KeepErrors = v.GetBool(keepErrors)
+	opts.LatencyThreshold = v.GetDuration(latencyThreshold)
+	opts.Operations = nil
+	for _, op := range strings.Split(v.GetString(operations), ",") {
+		if op = strings.TrimSpace(op); op != "" {
+			opts.Operations = append(opts.Operations, op)
+		}
+	}
+	opts.ProbabilisticRate = v.GetFloat64(probabilisticRate)
+	return opts
+}
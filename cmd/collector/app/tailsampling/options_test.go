@@ -0,0 +1,63 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsampling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+func TestOptionsWithFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.tail-sampling.enabled=true",
+		"--collector.tail-sampling.decision-wait=30s",
+		"--collector.tail-sampling.max-traces=1000",
+		"--collector.tail-sampling.max-spans=5000",
+		"--collector.tail-sampling.policy.errors=false",
+		"--collector.tail-sampling.policy.latency-threshold=2s",
+		"--collector.tail-sampling.policy.operations=checkout, payment:charge,",
+		"--collector.tail-sampling.policy.probabilistic-rate=0.5",
+	})
+	opts := Options{}.InitFromViper(v)
+
+	assert.Equal(t, Options{
+		Enabled:           true,
+		DecisionWait:      30 * time.Second,
+		MaxTraces:         1000,
+		MaxSpans:          5000,
+		KeepErrors:        false,
+		LatencyThreshold:  2 * time.Second,
+		Operations:        []string{"checkout", "payment:charge"},
+		ProbabilisticRate: 0.5,
+	}, opts)
+}
+
+func TestDefaultOptions(t *testing.T) {
+	v, _ := config.Viperize(AddFlags)
+	opts := Options{}.InitFromViper(v)
+
+	assert.Equal(t, Options{
+		DecisionWait:      defaultDecisionWait,
+		MaxTraces:         defaultMaxTraces,
+		MaxSpans:          defaultMaxSpans,
+		KeepErrors:        true,
+		ProbabilisticRate: defaultProbabilisticRate,
+	}, opts)
+}
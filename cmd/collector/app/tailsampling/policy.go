@@ -0,0 +1,157 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsampling
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// Policy decides whether a trace is kept from its buffered spans.
+type Policy interface {
+	// Name identifies the policy in the metrics.
+	Name() string
+	// Keep returns true if the trace made of the spans must be kept.
+	Keep(traceID model.TraceID, spans []*model.Span) bool
+}
+
+// NewPolicies creates the policies enabled in the options, the probabilistic one coming last.
+func NewPolicies(opts Options) []Policy {
+	var policies []Policy
+	if opts.KeepErrors {
+		policies = append(policies, errorPolicy{})
+	}
+	if opts.LatencyThreshold > 0 {
+		policies = append(policies, latencyPolicy{threshold: opts.LatencyThreshold})
+	}
+	if len(opts.Operations) > 0 {
+		policies = append(policies, newOperationPolicy(opts.Operations))
+	}
+	if opts.ProbabilisticRate > 0 {
+		policies = append(policies, newProbabilisticPolicy(opts.ProbabilisticRate))
+	}
+	return policies
+}
+
+// errorPolicy keeps the traces with a span tagged with error=true.
+type errorPolicy struct{}
+
+func (errorPolicy) Name() string {
+	return "error"
+}
+
+func (errorPolicy) Keep(traceID model.TraceID, spans []*model.Span) bool {
+	for _, span := range spans {
+		if errorTag, ok := model.KeyValues(span.Tags).FindByKey("error"); ok {
+			if errorTag.AsString() == "true" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// latencyPolicy keeps the traces lasting longer than the threshold, from the start
+// of their earliest span to the end of their latest one.
+type latencyPolicy struct {
+	threshold time.Duration
+}
+
+func (latencyPolicy) Name() string {
+	return "latency"
+}
+
+func (p latencyPolicy) Keep(traceID model.TraceID, spans []*model.Span) bool {
+	var start, end time.Time
+	for i, span := range spans {
+		spanEnd := span.StartTime.Add(span.Duration)
+		if i == 0 || span.StartTime.Before(start) {
+			start = span.StartTime
+		}
+		if i == 0 || spanEnd.After(end) {
+			end = spanEnd
+		}
+	}
+	return end.Sub(start) > p.threshold
+}
+
+// operationPolicy keeps the traces with a span of one of the services or operations.
+type operationPolicy struct {
+	services   map[string]struct{}
+	operations map[string]map[string]struct{}
+}
+
+func newOperationPolicy(operations []string) operationPolicy {
+	p := operationPolicy{
+		services:   make(map[string]struct{}),
+		operations: make(map[string]map[string]struct{}),
+	}
+	for _, op := range operations {
+		parts := strings.SplitN(op, ":", 2)
+		if len(parts) == 1 {
+			p.services[parts[0]] = struct{}{}
+			continue
+		}
+		if _, ok := p.operations[parts[0]]; !ok {
+			p.operations[parts[0]] = make(map[string]struct{})
+		}
+		p.operations[parts[0]][parts[1]] = struct{}{}
+	}
+	return p
+}
+
+func (operationPolicy) Name() string {
+	return "operation"
+}
+
+func (p operationPolicy) Keep(traceID model.TraceID, spans []*model.Span) bool {
+	for _, span := range spans {
+		if span.Process == nil {
+			continue
+		}
+		if _, ok := p.services[span.Process.ServiceName]; ok {
+			return true
+		}
+		if _, ok := p.operations[span.Process.ServiceName][span.OperationName]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// probabilisticPolicy keeps a fraction of the traces. The decision only depends on the
+// trace ID, like in the probabilistic sampler of the clients, so that collectors receiving
+// different spans of the same trace make the same decision.
+type probabilisticPolicy struct {
+	boundary uint64
+}
+
+const maxRandomNumber = ^(uint64(1) << 63) // i.e. 0x7fffffffffffffff
+
+func newProbabilisticPolicy(rate float64) probabilisticPolicy {
+	rate = math.Max(0.0, math.Min(rate, 1.0))
+	return probabilisticPolicy{boundary: uint64(float64(maxRandomNumber) * rate)}
+}
+
+func (probabilisticPolicy) Name() string {
+	return "probabilistic"
+}
+
+func (p probabilisticPolicy) Keep(traceID model.TraceID, spans []*model.Span) bool {
+	return traceID.Low&maxRandomNumber < p.boundary
+}
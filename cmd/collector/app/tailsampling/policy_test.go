@@ -0,0 +1,117 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsampling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func makeSpan(traceID uint64, service string, operation string, tags ...model.KeyValue) *model.Span {
+	return &model.Span{
+		TraceID:       model.NewTraceID(0, traceID),
+		OperationName: operation,
+		Process:       model.NewProcess(service, nil),
+		Tags:          tags,
+	}
+}
+
+func TestNewPolicies(t *testing.T) {
+	policies := NewPolicies(Options{
+		KeepErrors:        true,
+		LatencyThreshold:  time.Second,
+		Operations:        []string{"svc"},
+		ProbabilisticRate: 0.1,
+	})
+	var names []string
+	for _, p := range policies {
+		names = append(names, p.Name())
+	}
+	assert.Equal(t, []string{"error", "latency", "operation", "probabilistic"}, names)
+	assert.Empty(t, NewPolicies(Options{}))
+}
+
+func TestErrorPolicy(t *testing.T) {
+	tests := []struct {
+		tags     []model.KeyValue
+		expected bool
+	}{
+		{tags: []model.KeyValue{model.Bool("error", true)}, expected: true},
+		{tags: []model.KeyValue{model.String("error", "true")}, expected: true},
+		{tags: []model.KeyValue{model.Bool("error", false)}, expected: false},
+		{tags: []model.KeyValue{model.String("http.status_code", "500")}, expected: false},
+		{expected: false},
+	}
+	for _, test := range tests {
+		spans := []*model.Span{makeSpan(1, "svc", "op"), makeSpan(1, "svc", "op", test.tags...)}
+		assert.Equal(t, test.expected, errorPolicy{}.Keep(spans[0].TraceID, spans), "%v", test.tags)
+	}
+}
+
+func TestLatencyPolicy(t *testing.T) {
+	start := time.Unix(1000, 0)
+	root := makeSpan(1, "svc", "root")
+	root.StartTime = start
+	root.Duration = time.Second
+	child := makeSpan(1, "svc", "child")
+	child.StartTime = start.Add(500 * time.Millisecond)
+	child.Duration = time.Second
+	spans := []*model.Span{child, root}
+
+	assert.True(t, latencyPolicy{threshold: time.Second}.Keep(root.TraceID, spans))
+	assert.False(t, latencyPolicy{threshold: 1500 * time.Millisecond}.Keep(root.TraceID, spans))
+}
+
+func TestOperationPolicy(t *testing.T) {
+	p := newOperationPolicy([]string{"checkout", "payment:charge"})
+	tests := []struct {
+		service   string
+		operation string
+		expected  bool
+	}{
+		{service: "checkout", operation: "any", expected: true},
+		{service: "payment", operation: "charge", expected: true},
+		{service: "payment", operation: "refund", expected: false},
+		{service: "frontend", operation: "charge", expected: false},
+	}
+	for _, test := range tests {
+		span := makeSpan(1, test.service, test.operation)
+		assert.Equal(t, test.expected, p.Keep(span.TraceID, []*model.Span{span}), "%s:%s", test.service, test.operation)
+	}
+	assert.False(t, p.Keep(model.NewTraceID(0, 1), []*model.Span{{}}))
+}
+
+func TestProbabilisticPolicy(t *testing.T) {
+	assert.False(t, newProbabilisticPolicy(0).Keep(model.NewTraceID(0, 1), nil))
+	assert.True(t, newProbabilisticPolicy(2).Keep(model.NewTraceID(0, 1), nil))
+
+	p := newProbabilisticPolicy(0.5)
+	assert.True(t, p.Keep(model.NewTraceID(0, 1), nil))
+	assert.False(t, p.Keep(model.NewTraceID(0, maxRandomNumber-1), nil))
+	// the high bit of the trace ID is ignored, like in the clients
+	assert.True(t, p.Keep(model.NewTraceID(0, 1|1<<63), nil))
+
+	kept := 0
+	for i := uint64(0); i < 1000; i++ {
+		if p.Keep(model.NewTraceID(0, i*(maxRandomNumber/1000)), nil) {
+			kept++
+		}
+	}
+	assert.InDelta(t, 500, kept, 1)
+}
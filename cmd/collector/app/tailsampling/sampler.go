@@ -0,0 +1,281 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsampling
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// maxTickInterval is the maximum delay after the decision wait before a trace is decided.
+const maxTickInterval = time.Second
+
+type samplerMetrics struct {
+	// TracesKept is the number of traces kept by one of the policies
+	TracesKept metrics.Counter `metric:"traces" tags:"decision=kept"`
+	// TracesDropped is the number of traces no policy kept
+	TracesDropped metrics.Counter `metric:"traces" tags:"decision=dropped"`
+	// TracesDecidedEarly is the number of traces decided before the decision wait elapsed, because the buffer was full
+	TracesDecidedEarly metrics.Counter `metric:"traces_decided_early"`
+	// SpansKept is the number of spans of the kept traces, including late spans
+	SpansKept metrics.Counter `metric:"spans" tags:"decision=kept"`
+	// SpansDropped is the number of spans of the dropped traces, including late spans
+	SpansDropped metrics.Counter `metric:"spans" tags:"decision=dropped"`
+	// SpansLate is the number of spans received after the decision on their trace was made
+	SpansLate metrics.Counter `metric:"late_spans"`
+	// TracesBuffered is the number of traces waiting for a decision
+	TracesBuffered metrics.Gauge `metric:"traces_buffered"`
+	// SpansBuffered is the number of spans waiting for the decision on their trace
+	SpansBuffered metrics.Gauge `metric:"spans_buffered"`
+}
+
+// traceKey identifies a trace within its tenant, so that the traces of different tenants
+// sharing the same trace ID are decided separately.
+type traceKey struct {
+	tenant  string
+	traceID model.TraceID
+}
+
+// bufferedTrace holds the spans of a trace waiting for the decision, along with
+// the items handed to the consumer if the trace is kept.
+type bufferedTrace struct {
+	key       traceKey
+	firstSeen time.Time
+	spans     []*model.Span
+	items     []interface{}
+}
+
+type decision struct {
+	key       traceKey
+	keep      bool
+	decidedAt time.Time
+}
+
+// Sampler makes sampling decisions on whole traces after they were received by the collector,
+// instead of relying only on the head sampling done by the clients. The spans of a trace are
+// buffered for the decision wait, counting from its first span, then the trace is kept if
+// any of the policies keeps it. The spans received after the decision follow it.
+// The traces are identified by their tenant and trace ID.
+type Sampler struct {
+	sync.Mutex
+
+	options       Options
+	policies      []Policy
+	logger        *zap.Logger
+	metrics       samplerMetrics
+	policyMetrics []metrics.Counter
+	timeNow       func() time.Time
+	consumer      func(item interface{})
+
+	traces map[traceKey]*list.Element
+	// order holds the buffered traces by time of their first span, oldest first
+	order    *list.List
+	numSpans int
+	// decided remembers the recent decisions, to apply them to late spans
+	decided map[traceKey]*list.Element
+	// decisions holds the remembered decisions by time they were made, oldest first
+	decisions *list.List
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewSampler creates a Sampler deciding with the policies.
+func NewSampler(
+	options Options,
+	policies []Policy,
+	metricsFactory metrics.Factory,
+	logger *zap.Logger,
+) *Sampler {
+	s := &Sampler{
+		options:   options,
+		policies:  policies,
+		logger:    logger,
+		timeNow:   time.Now,
+		traces:    make(map[traceKey]*list.Element),
+		order:     list.New(),
+		decided:   make(map[traceKey]*list.Element),
+		decisions: list.New(),
+		stop:      make(chan struct{}),
+	}
+	factory := metricsFactory.Namespace(metrics.NSOptions{Name: "tail_sampling"})
+	metrics.Init(&s.metrics, factory, nil)
+	for _, p := range policies {
+		s.policyMetrics = append(s.policyMetrics, factory.Counter(metrics.Options{
+			Name: "traces_kept_by_policy",
+			Tags: map[string]string{"policy": p.Name()},
+		}))
+	}
+	return s
+}
+
+// Start starts deciding on the buffered traces. The items of the kept traces are handed to the consumer.
+func (s *Sampler) Start(consumer func(item interface{})) {
+	s.consumer = consumer
+	s.wg.Add(1)
+	go s.runDecisionLoop()
+}
+
+// Add buffers the span of the tenant until the decision on its trace is made, the item
+// is handed to the consumer if the trace is kept. A span received after the decision
+// on its trace follows that decision.
+func (s *Sampler) Add(tenant string, span *model.Span, item interface{}) {
+	key := traceKey{tenant: tenant, traceID: span.TraceID}
+	now := s.timeNow()
+	s.Lock()
+	var kept []interface{}
+	if _, ok := s.traces[key]; !ok {
+		for s.options.MaxTraces > 0 && len(s.traces) >= s.options.MaxTraces {
+			kept = s.decideOldest(now, kept)
+		}
+	}
+	for s.options.MaxSpans > 0 && s.numSpans >= s.options.MaxSpans && s.order.Len() > 0 {
+		kept = s.decideOldest(now, kept)
+	}
+	if elem, ok := s.decided[key]; ok {
+		s.metrics.SpansLate.Inc(1)
+		if elem.Value.(*decision).keep {
+			s.metrics.SpansKept.Inc(1)
+			kept = append(kept, item)
+		} else {
+			s.metrics.SpansDropped.Inc(1)
+		}
+	} else {
+		s.buffer(key, span, item, now)
+	}
+	s.Unlock()
+
+	for _, item := range kept {
+		s.consumer(item)
+	}
+}
+
+// Close stops the sampler and decides on all the buffered traces.
+func (s *Sampler) Close() error {
+	close(s.stop)
+	s.wg.Wait()
+	s.decideExpired(s.timeNow().Add(s.options.DecisionWait))
+	return nil
+}
+
+func (s *Sampler) buffer(key traceKey, span *model.Span, item interface{}, now time.Time) {
+	var trace *bufferedTrace
+	if elem, ok := s.traces[key]; ok {
+		trace = elem.Value.(*bufferedTrace)
+	} else {
+		trace = &bufferedTrace{key: key, firstSeen: now}
+		s.traces[key] = s.order.PushBack(trace)
+	}
+	trace.spans = append(trace.spans, span)
+	trace.items = append(trace.items, item)
+	s.numSpans++
+}
+
+func (s *Sampler) runDecisionLoop() {
+	defer s.wg.Done()
+	interval := s.options.DecisionWait
+	if interval <= 0 || interval > maxTickInterval {
+		interval = maxTickInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.decideExpired(s.timeNow())
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// decideExpired decides on the traces whose decision wait elapsed at the given time
+// and forgets the decisions older than the decision wait.
+func (s *Sampler) decideExpired(now time.Time) {
+	s.Lock()
+	for s.decisions.Len() > 0 {
+		d := s.decisions.Front().Value.(*decision)
+		if now.Sub(d.decidedAt) < s.options.DecisionWait {
+			break
+		}
+		s.forgetOldestDecision()
+	}
+	var kept []interface{}
+	for s.order.Len() > 0 {
+		trace := s.order.Front().Value.(*bufferedTrace)
+		if now.Sub(trace.firstSeen) < s.options.DecisionWait {
+			break
+		}
+		kept = s.decide(s.order.Front(), now, kept)
+	}
+	s.metrics.TracesBuffered.Update(int64(len(s.traces)))
+	s.metrics.SpansBuffered.Update(int64(s.numSpans))
+	s.Unlock()
+
+	for _, item := range kept {
+		s.consumer(item)
+	}
+}
+
+// decideOldest decides on the oldest buffered trace before its decision wait elapsed.
+func (s *Sampler) decideOldest(now time.Time, kept []interface{}) []interface{} {
+	s.metrics.TracesDecidedEarly.Inc(1)
+	return s.decide(s.order.Front(), now, kept)
+}
+
+// decide removes the trace from the buffer and appends its items to kept if a policy keeps it.
+func (s *Sampler) decide(elem *list.Element, now time.Time, kept []interface{}) []interface{} {
+	trace := s.order.Remove(elem).(*bufferedTrace)
+	delete(s.traces, trace.key)
+	s.numSpans -= len(trace.spans)
+
+	keep := false
+	for i, p := range s.policies {
+		if p.Keep(trace.key.traceID, trace.spans) {
+			s.policyMetrics[i].Inc(1)
+			keep = true
+			break
+		}
+	}
+	s.rememberDecision(&decision{key: trace.key, keep: keep, decidedAt: now})
+	if !keep {
+		s.metrics.TracesDropped.Inc(1)
+		s.metrics.SpansDropped.Inc(int64(len(trace.spans)))
+		return kept
+	}
+	s.metrics.TracesKept.Inc(1)
+	s.metrics.SpansKept.Inc(int64(len(trace.spans)))
+	return append(kept, trace.items...)
+}
+
+// rememberDecision remembers the decision for the late spans of the trace. Like the buffered
+// traces, at most MaxTraces decisions are remembered, the oldest ones are forgotten first.
+func (s *Sampler) rememberDecision(d *decision) {
+	s.decided[d.key] = s.decisions.PushBack(d)
+	for s.options.MaxTraces > 0 && s.decisions.Len() > s.options.MaxTraces {
+		s.forgetOldestDecision()
+	}
+}
+
+func (s *Sampler) forgetOldestDecision() {
+	d := s.decisions.Remove(s.decisions.Front()).(*decision)
+	delete(s.decided, d.key)
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsampling
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+var testOptions = Options{
+	Enabled:      true,
+	DecisionWait: 10 * time.Second,
+	MaxTraces:    10,
+	MaxSpans:     100,
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) timeNow() time.Time {
+	return c.now
+}
+
+type itemRecorder struct {
+	sync.Mutex
+	items []interface{}
+}
+
+func (r *itemRecorder) consume(item interface{}) {
+	r.Lock()
+	defer r.Unlock()
+	r.items = append(r.items, item)
+}
+
+func (r *itemRecorder) getItems() []interface{} {
+	r.Lock()
+	defer r.Unlock()
+	return r.items
+}
+
+func newTestSampler(opts Options) (*Sampler, *fakeClock, *itemRecorder, *metricstest.Factory) {
+	metricsFactory := metricstest.NewFactory(0)
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	recorder := &itemRecorder{}
+	s := NewSampler(opts, []Policy{errorPolicy{}, newOperationPolicy([]string{"important"})}, metricsFactory, zap.NewNop())
+	s.timeNow = clock.timeNow
+	s.consumer = recorder.consume // the decisions are triggered by the tests instead of the decision loop
+	return s, clock, recorder, metricsFactory
+}
+
+func TestSamplerDecision(t *testing.T) {
+	s, clock, recorder, metricsFactory := newTestSampler(testOptions)
+	defer s.Close()
+
+	s.Add("", makeSpan(1, "svc", "op"), "1a")
+	s.Add("", makeSpan(2, "svc", "op"), "2a")
+	clock.now = clock.now.Add(5 * time.Second)
+	s.Add("", makeSpan(1, "svc", "op", model.Bool("error", true)), "1b")
+	s.Add("", makeSpan(3, "important", "op"), "3a")
+
+	s.decideExpired(clock.now)
+	assert.Empty(t, recorder.getItems(), "no decision is made before the decision wait")
+
+	clock.now = clock.now.Add(5 * time.Second)
+	s.decideExpired(clock.now)
+	assert.Equal(t, []interface{}{"1a", "1b"}, recorder.getItems())
+
+	// late spans follow the decision on their trace
+	s.Add("", makeSpan(1, "svc", "op"), "1c")
+	s.Add("", makeSpan(2, "svc", "op"), "2b")
+	assert.Equal(t, []interface{}{"1a", "1b", "1c"}, recorder.getItems())
+
+	clock.now = clock.now.Add(5 * time.Second)
+	s.decideExpired(clock.now)
+	assert.Equal(t, []interface{}{"1a", "1b", "1c", "3a"}, recorder.getItems())
+
+	metricsFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "tail_sampling.traces", Tags: map[string]string{"decision": "kept"}, Value: 2},
+		metricstest.ExpectedMetric{Name: "tail_sampling.traces", Tags: map[string]string{"decision": "dropped"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "tail_sampling.spans", Tags: map[string]string{"decision": "kept"}, Value: 4},
+		metricstest.ExpectedMetric{Name: "tail_sampling.spans", Tags: map[string]string{"decision": "dropped"}, Value: 2},
+		metricstest.ExpectedMetric{Name: "tail_sampling.late_spans", Value: 2},
+		metricstest.ExpectedMetric{Name: "tail_sampling.traces_kept_by_policy", Tags: map[string]string{"policy": "error"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "tail_sampling.traces_kept_by_policy", Tags: map[string]string{"policy": "operation"}, Value: 1},
+	)
+	metricsFactory.AssertGaugeMetrics(t,
+		metricstest.ExpectedMetric{Name: "tail_sampling.traces_buffered", Value: 0},
+		metricstest.ExpectedMetric{Name: "tail_sampling.spans_buffered", Value: 0},
+	)
+
+	// the decisions are forgotten after the decision wait
+	clock.now = clock.now.Add(10 * time.Second)
+	s.decideExpired(clock.now)
+	s.Add("", makeSpan(2, "svc", "op", model.Bool("error", true)), "2c")
+	clock.now = clock.now.Add(10 * time.Second)
+	s.decideExpired(clock.now)
+	assert.Equal(t, []interface{}{"1a", "1b", "1c", "3a", "2c"}, recorder.getItems())
+}
+
+func TestSamplerMaxTraces(t *testing.T) {
+	opts := testOptions
+	opts.MaxTraces = 2
+	s, _, recorder, metricsFactory := newTestSampler(opts)
+	defer s.Close()
+
+	s.Add("", makeSpan(1, "important", "op"), "1a")
+	s.Add("", makeSpan(2, "svc", "op"), "2a")
+	s.Add("", makeSpan(2, "svc", "op"), "2b")
+	assert.Empty(t, recorder.getItems())
+
+	s.Add("", makeSpan(3, "svc", "op"), "3a")
+	assert.Equal(t, []interface{}{"1a"}, recorder.getItems())
+	metricsFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "tail_sampling.traces_decided_early", Value: 1},
+	)
+}
+
+func TestSamplerMaxSpans(t *testing.T) {
+	opts := testOptions
+	opts.MaxSpans = 2
+	s, _, recorder, metricsFactory := newTestSampler(opts)
+	defer s.Close()
+
+	s.Add("", makeSpan(1, "important", "op"), "1a")
+	s.Add("", makeSpan(1, "important", "op"), "1b")
+	// the trace of the new span is decided early, the span follows the decision
+	s.Add("", makeSpan(1, "important", "op"), "1c")
+	assert.Equal(t, []interface{}{"1a", "1b", "1c"}, recorder.getItems())
+
+	s.Add("", makeSpan(2, "svc", "op"), "2a")
+	s.Add("", makeSpan(3, "important", "op"), "3a")
+	s.Add("", makeSpan(3, "important", "op"), "3b")
+	assert.Equal(t, []interface{}{"1a", "1b", "1c"}, recorder.getItems())
+	metricsFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "tail_sampling.traces_decided_early", Value: 2},
+		metricstest.ExpectedMetric{Name: "tail_sampling.traces", Tags: map[string]string{"decision": "dropped"}, Value: 1},
+	)
+}
+
+func TestSamplerMaxDecisions(t *testing.T) {
+	opts := testOptions
+	opts.MaxTraces = 2
+	s, _, recorder, _ := newTestSampler(opts)
+	defer s.Close()
+
+	s.Add("", makeSpan(1, "important", "op"), "1a")
+	s.Add("", makeSpan(2, "important", "op"), "2a")
+	s.Add("", makeSpan(3, "important", "op"), "3a")
+	s.Add("", makeSpan(4, "important", "op"), "4a")
+	s.Add("", makeSpan(5, "important", "op"), "5a")
+	assert.Equal(t, []interface{}{"1a", "2a", "3a"}, recorder.getItems())
+
+	// only the two most recent decisions are remembered
+	assert.Len(t, s.decided, 2)
+	_, ok := s.decided[traceKey{traceID: model.NewTraceID(0, 1)}]
+	assert.False(t, ok, "the oldest decision is forgotten")
+
+	// trace 4 is decided early to buffer the late span, which follows the decision on trace 3
+	s.Add("", makeSpan(3, "svc", "op"), "3b")
+	assert.Equal(t, []interface{}{"1a", "2a", "3a", "4a", "3b"}, recorder.getItems())
+}
+
+func TestSamplerTenants(t *testing.T) {
+	s, clock, recorder, _ := newTestSampler(testOptions)
+	defer s.Close()
+
+	s.Add("acme", makeSpan(1, "svc", "op", model.Bool("error", true)), "acme-1a")
+	s.Add("megacorp", makeSpan(1, "svc", "op"), "megacorp-1a")
+	assert.Len(t, s.traces, 2)
+
+	clock.now = clock.now.Add(10 * time.Second)
+	s.decideExpired(clock.now)
+	assert.Equal(t, []interface{}{"acme-1a"}, recorder.getItems())
+
+	// late spans follow the decision on the trace of their tenant
+	s.Add("megacorp", makeSpan(1, "svc", "op"), "megacorp-1b")
+	s.Add("acme", makeSpan(1, "svc", "op"), "acme-1b")
+	assert.Equal(t, []interface{}{"acme-1a", "acme-1b"}, recorder.getItems())
+}
+
+func TestSamplerClose(t *testing.T) {
+	s, _, recorder, _ := newTestSampler(testOptions)
+
+	s.Add("", makeSpan(1, "important", "op"), "1a")
+	s.Add("", makeSpan(2, "svc", "op"), "2a")
+	assert.NoError(t, s.Close())
+	assert.Equal(t, []interface{}{"1a"}, recorder.getItems())
+}
+
+func TestSamplerDecisionLoop(t *testing.T) {
+	recorder := &itemRecorder{}
+	s := NewSampler(Options{DecisionWait: time.Millisecond}, []Policy{errorPolicy{}}, metricstest.NewFactory(0), zap.NewNop())
+	s.Start(recorder.consume)
+	defer s.Close()
+
+	s.Add("", makeSpan(1, "svc", "op", model.Bool("error", true)), "1a")
+	for i := 0; i < 1000 && len(recorder.getItems()) == 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, []interface{}{"1a"}, recorder.getItems())
+}
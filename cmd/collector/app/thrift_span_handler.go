@@ -28,6 +28,8 @@ import (
 // SubmitBatchOptions are passed to Submit methods of the handlers.
 type SubmitBatchOptions struct {
 	InboundTransport InboundTransport
+	// Tenant the spans belong to
+	Tenant string
 }
 
 // ZipkinSpansHandler consumes and handles zipkin spans
@@ -66,6 +68,7 @@ func (jbh *jaegerBatchesHandler) SubmitBatches(batches []*jaeger.Batch, options
 		oks, err := jbh.modelProcessor.ProcessSpans(mSpans, ProcessSpansOptions{
 			InboundTransport: options.InboundTransport,
 			SpanFormat:       JaegerSpanFormat,
+			Tenant:           options.Tenant,
 		})
 		if err != nil {
 			jbh.logger.Error("Collector failed to process span batch", zap.Error(err))
@@ -113,6 +116,7 @@ func (h *zipkinSpanHandler) SubmitZipkinBatch(spans []*zipkincore.Span, options
 	bools, err := h.modelProcessor.ProcessSpans(mSpans, ProcessSpansOptions{
 		InboundTransport: options.InboundTransport,
 		SpanFormat:       ZipkinSpanFormat,
+		Tenant:           options.Tenant,
 	})
 	if err != nil {
 		h.logger.Error("Collector failed to process Zipkin span batch", zap.Error(err))
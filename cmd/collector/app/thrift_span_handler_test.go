@@ -75,6 +75,8 @@ func (s *shouldIErrorProcessor) ProcessSpans(mSpans []*model.Span, _ ProcessSpan
 	return retMe, nil
 }
 
+func (s *shouldIErrorProcessor) Stop() {}
+
 func TestZipkinSpanHandler(t *testing.T) {
 	testChunks := []struct {
 		expectedErr error
@@ -16,6 +16,7 @@ package zipkin
 
 import (
 	"compress/gzip"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -23,15 +24,11 @@ import (
 	"net/http"
 	"strings"
 
-	"github.com/go-openapi/loads"
-	"github.com/go-openapi/strfmt"
-	"github.com/go-openapi/swag"
 	"github.com/gorilla/mux"
 
 	"github.com/jaegertracing/jaeger/cmd/collector/app"
 	"github.com/jaegertracing/jaeger/model/converter/thrift/zipkin"
-	"github.com/jaegertracing/jaeger/swagger-gen/models"
-	"github.com/jaegertracing/jaeger/swagger-gen/restapi"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/swagger-gen/restapi/operations"
 	"github.com/jaegertracing/jaeger/thrift-gen/zipkincore"
 )
@@ -39,17 +36,14 @@ import (
 // APIHandler handles all HTTP calls to the collector
 type APIHandler struct {
 	zipkinSpansHandler app.ZipkinSpansHandler
-	zipkinV2Formats    strfmt.Registry
 }
 
 // NewAPIHandler returns a new APIHandler
 func NewAPIHandler(
 	zipkinSpansHandler app.ZipkinSpansHandler,
 ) *APIHandler {
-	swaggerSpec, _ := loads.Analyzed(restapi.SwaggerJSON, "")
 	return &APIHandler{
 		zipkinSpansHandler: zipkinSpansHandler,
-		zipkinV2Formats:    operations.NewZipkinAPI(swaggerSpec).Formats(),
 	}
 }
 
@@ -100,8 +94,8 @@ func (aH *APIHandler) saveSpans(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := aH.saveThriftSpans(tSpans); err != nil {
-		http.Error(w, fmt.Sprintf("Cannot submit Zipkin batch: %v", err), http.StatusInternalServerError)
+	if err := aH.saveThriftSpans(r.Context(), tSpans); err != nil {
+		http.Error(w, fmt.Sprintf("Cannot submit Zipkin batch: %v", err), app.HTTPStatusForError(err))
 		return
 	}
 
@@ -139,24 +133,14 @@ func (aH *APIHandler) saveSpansV2(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var spans models.ListOfSpans
-	if err = swag.ReadJSON(bodyBytes, &spans); err != nil {
-		http.Error(w, fmt.Sprintf(app.UnableToReadBodyErrFormat, err), http.StatusBadRequest)
-		return
-	}
-	if err = spans.Validate(aH.zipkinV2Formats); err != nil {
-		http.Error(w, fmt.Sprintf(app.UnableToReadBodyErrFormat, err), http.StatusBadRequest)
-		return
-	}
-
-	tSpans, err := spansV2ToThrift(spans)
+	tSpans, err := DeserializeJSONV2(bodyBytes)
 	if err != nil {
 		http.Error(w, fmt.Sprintf(app.UnableToReadBodyErrFormat, err), http.StatusBadRequest)
 		return
 	}
 
-	if err := aH.saveThriftSpans(tSpans); err != nil {
-		http.Error(w, fmt.Sprintf("Cannot submit Zipkin batch: %v", err), http.StatusInternalServerError)
+	if err := aH.saveThriftSpans(r.Context(), tSpans); err != nil {
+		http.Error(w, fmt.Sprintf("Cannot submit Zipkin batch: %v", err), app.HTTPStatusForError(err))
 		return
 	}
 
@@ -171,9 +155,9 @@ func gunzip(r io.ReadCloser) (*gzip.Reader, error) {
 	return gz, nil
 }
 
-func (aH *APIHandler) saveThriftSpans(tSpans []*zipkincore.Span) error {
+func (aH *APIHandler) saveThriftSpans(ctx context.Context, tSpans []*zipkincore.Span) error {
 	if len(tSpans) > 0 {
-		opts := app.SubmitBatchOptions{InboundTransport: app.HTTPTransport}
+		opts := app.SubmitBatchOptions{InboundTransport: app.HTTPTransport, Tenant: tenancy.GetTenant(ctx)}
 		if _, err := aH.zipkinSpansHandler.SubmitZipkinBatch(tSpans, opts); err != nil {
 			return err
 		}
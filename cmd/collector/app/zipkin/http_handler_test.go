@@ -173,6 +173,11 @@ func TestJsonFormat(t *testing.T) {
 		assert.EqualValues(t, test.statusCode, statusCode)
 		assert.EqualValues(t, test.expected, resBodyStr)
 	}
+
+	handler.zipkinSpansHandler.(*mockZipkinHandler).err = app.ErrRateLimited
+	statusCode, _, err = postBytes(server.URL+`/api/v1/spans`, []byte(spanJSON), createHeader("application/json"))
+	require.NoError(t, err)
+	assert.EqualValues(t, http.StatusTooManyRequests, statusCode)
 }
 
 func TestGzipEncoding(t *testing.T) {
@@ -15,11 +15,26 @@
 package zipkin
 
 import (
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/swagger-gen/models"
 	"github.com/jaegertracing/jaeger/thrift-gen/zipkincore"
 )
 
+// DeserializeJSONV2 deserializes and validates zipkin v2 json spans and converts them into zipkin thrift
+func DeserializeJSONV2(body []byte) ([]*zipkincore.Span, error) {
+	var spans models.ListOfSpans
+	if err := swag.ReadJSON(body, &spans); err != nil {
+		return nil, err
+	}
+	if err := spans.Validate(strfmt.Default); err != nil {
+		return nil, err
+	}
+	return spansV2ToThrift(spans)
+}
+
 func spansV2ToThrift(spans models.ListOfSpans) ([]*zipkincore.Span, error) {
 	tSpans := make([]*zipkincore.Span, 0, len(spans))
 	for _, span := range spans {
@@ -145,6 +145,29 @@ func TestErrSpans(t *testing.T) {
 	assert.Equal(t, err.Error(), "strconv.ParseUint: parsing \"z\": invalid syntax")
 }
 
+func TestDeserializeJSONV2(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/zipkin_01.json")
+	require.NoError(t, err)
+	tSpans, err := DeserializeJSONV2(b)
+	require.NoError(t, err)
+	require.Len(t, tSpans, 1)
+	assert.Equal(t, "foo", tSpans[0].Name)
+
+	tests := []struct {
+		body string
+		err  string
+	}{
+		{body: "not good", err: "invalid character 'o' in literal null (expecting 'u')"},
+		{body: "[{}]", err: "validation failure list:\nid in body is required\ntraceId in body is required"},
+		{body: `[{"id":"z", "traceId":"1111111111111111"}]`, err: "strconv.ParseUint: parsing \"z\": invalid syntax"},
+	}
+	for _, test := range tests {
+		tSpans, err := DeserializeJSONV2([]byte(test.body))
+		assert.EqualError(t, err, test.err, test.body)
+		assert.Nil(t, tSpans)
+	}
+}
+
 func loadJSON(t *testing.T, fileName string, i interface{}) {
 	b, err := ioutil.ReadFile(fileName)
 	require.NoError(t, err)
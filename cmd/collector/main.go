@@ -37,10 +37,16 @@ import (
 
 	basicB "github.com/jaegertracing/jaeger/cmd/builder"
 	"github.com/jaegertracing/jaeger/cmd/collector/app"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/baggage"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/baggage/restrictionstore"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/builder"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/dependencies"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/grpcserver"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/ratelimit"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/tailsampling"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/zipkin"
 	"github.com/jaegertracing/jaeger/cmd/docs"
 	"github.com/jaegertracing/jaeger/cmd/env"
@@ -48,10 +54,13 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
 	"github.com/jaegertracing/jaeger/pkg/recoveryhandler"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/pkg/version"
 	ss "github.com/jaegertracing/jaeger/plugin/sampling/strategystore"
 	"github.com/jaegertracing/jaeger/plugin/storage"
 	"github.com/jaegertracing/jaeger/ports"
+	istorage "github.com/jaegertracing/jaeger/storage"
+	bc "github.com/jaegertracing/jaeger/thrift-gen/baggage"
 	jc "github.com/jaegertracing/jaeger/thrift-gen/jaeger"
 	sc "github.com/jaegertracing/jaeger/thrift-gen/sampling"
 	zc "github.com/jaegertracing/jaeger/thrift-gen/zipkincore"
@@ -103,10 +112,55 @@ func main() {
 			if err != nil {
 				logger.Fatal("Unable to set up builder", zap.Error(err))
 			}
+			tenancyOpts := tenancy.Options{}.InitFromViper(v)
+			tenancyMgr, err := tenancy.NewManager(&tenancyOpts)
+			if err != nil {
+				logger.Fatal("Failed to init multi-tenancy", zap.Error(err))
+			}
+			if tenancyMgr.Enabled {
+				if err := storageFactory.CheckMultiTenancy(); err != nil {
+					logger.Fatal("The storage cannot be used with multi-tenancy", zap.Error(err))
+				}
+				handlerBuilder.WithTenancy(tenancyMgr, storageFactory.CreateTenantSpanWriter)
+			}
 
-			zipkinSpansHandler, jaegerBatchesHandler, grpcHandler := handlerBuilder.BuildHandlers()
 			strategyStoreFactory.InitFromViper(v)
-			strategyStore := initSamplingStrategyStore(strategyStoreFactory, metricsFactory, logger)
+			strategyStore, aggregator := initSamplingStrategyStore(strategyStoreFactory, metricsFactory, storageFactory, logger)
+			baggageRestrictionStore := initBaggageRestrictionStore(new(restrictionstore.Options).InitFromViper(v), metricsFactory, logger)
+
+			var preSave []app.ProcessSpan
+			if aggregator != nil {
+				preSave = append(preSave, app.HandleRootSpan(aggregator, logger))
+			}
+			dependenciesOpts := dependencies.Options{}.InitFromViper(v)
+			var dependenciesAggregator *dependencies.Aggregator
+			if dependenciesOpts.Enabled {
+				if tenancyMgr.Enabled {
+					logger.Fatal("The dependencies aggregator cannot be used with multi-tenancy")
+				}
+				dependenciesAggregator = initDependenciesAggregator(dependenciesOpts, storageFactory, metricsFactory, logger)
+				preSave = append(preSave, dependenciesAggregator.HandleSpan)
+			}
+			tailSamplingOpts := tailsampling.Options{}.InitFromViper(v)
+			var tailSampler *tailsampling.Sampler
+			if tailSamplingOpts.Enabled {
+				tailSampler = tailsampling.NewSampler(tailSamplingOpts, tailsampling.NewPolicies(tailSamplingOpts), metricsFactory, logger)
+				handlerBuilder.WithTailSampler(tailSampler)
+			}
+			rateLimitOpts := new(ratelimit.Options).InitFromViper(v)
+			var rateLimiter *ratelimit.Limiter
+			if rateLimitOpts.QuotaFile != "" {
+				rateLimiter, err = ratelimit.NewLimiter(*rateLimitOpts, metricsFactory, logger)
+				if err != nil {
+					logger.Fatal("Failed to load ingestion quotas", zap.Error(err))
+				}
+				handlerBuilder.WithRateLimiter(rateLimiter)
+			}
+			sanitizerOpts := new(sanitizer.Options).InitFromViper(v)
+			if sanitizerOpts.RedactionRulesFile != "" {
+				handlerBuilder.WithSanitizer(initSanitizer(sanitizerOpts, logger))
+			}
+			zipkinSpansHandler, jaegerBatchesHandler, grpcHandler, importHandler, spanProcessor := handlerBuilder.BuildHandlers(preSave...)
 
 			{
 				ch, err := tchannel.NewChannel(serviceName, &tchannel.ChannelOptions{})
@@ -118,6 +172,9 @@ func main() {
 				server.Register(jc.NewTChanCollectorServer(batchHandler))
 				server.Register(zc.NewTChanZipkinCollectorServer(batchHandler))
 				server.Register(sc.NewTChanSamplingManagerServer(sampling.NewHandler(strategyStore)))
+				if baggageRestrictionStore != nil {
+					server.Register(bc.NewTChanBaggageRestrictionManagerServer(baggage.NewHandler(baggageRestrictionStore)))
+				}
 				portStr := ":" + strconv.Itoa(builderOpts.CollectorPort)
 				listener, err := net.Listen("tcp", portStr)
 				if err != nil {
@@ -127,7 +184,7 @@ func main() {
 				ch.Serve(listener)
 			}
 
-			server, err := startGRPCServer(builderOpts, grpcHandler, strategyStore, logger)
+			server, err := startGRPCServer(builderOpts, grpcHandler, strategyStore, baggageRestrictionStore, logger)
 			if err != nil {
 				logger.Fatal("Could not start gRPC collector", zap.Error(err))
 			}
@@ -136,11 +193,12 @@ func main() {
 				r := mux.NewRouter()
 				apiHandler := app.NewAPIHandler(jaegerBatchesHandler)
 				apiHandler.RegisterRoutes(r)
+				importHandler.RegisterRoutes(r)
 				httpPortStr := ":" + strconv.Itoa(builderOpts.CollectorHTTPPort)
 				recoveryHandler := recoveryhandler.NewRecoveryHandler(logger, true)
-				httpHandler := recoveryHandler(r)
+				httpHandler := recoveryHandler(tenancy.ExtractTenantHTTPHandler(tenancyMgr, r))
 
-				go startZipkinHTTPAPI(logger, builderOpts.CollectorZipkinHTTPPort, builderOpts.CollectorZipkinAllowedOrigins, builderOpts.CollectorZipkinAllowedHeaders, zipkinSpansHandler, recoveryHandler)
+				go startZipkinHTTPAPI(logger, builderOpts.CollectorZipkinHTTPPort, builderOpts.CollectorZipkinAllowedOrigins, builderOpts.CollectorZipkinAllowedHeaders, zipkinSpansHandler, tenancyMgr, recoveryHandler)
 
 				logger.Info("Starting jaeger-collector HTTP server", zap.Int("http-port", builderOpts.CollectorHTTPPort))
 				go func() {
@@ -152,6 +210,38 @@ func main() {
 			}
 
 			svc.RunAndThen(func() {
+				if rateLimiter != nil {
+					if err := rateLimiter.Close(); err != nil {
+						logger.Error("Failed to close rate limiter", zap.Error(err))
+					}
+				}
+				if tailSampler != nil {
+					if err := tailSampler.Close(); err != nil {
+						logger.Error("Failed to close tail sampler", zap.Error(err))
+					}
+				}
+				// the tail sampler flushes its buffered traces into the queue, which is drained last
+				spanProcessor.Stop()
+				if aggregator != nil {
+					if err := aggregator.Close(); err != nil {
+						logger.Error("Failed to close throughput aggregator", zap.Error(err))
+					}
+				}
+				if dependenciesAggregator != nil {
+					if err := dependenciesAggregator.Close(); err != nil {
+						logger.Error("Failed to close dependencies aggregator", zap.Error(err))
+					}
+				}
+				if closer, ok := baggageRestrictionStore.(io.Closer); ok {
+					if err := closer.Close(); err != nil {
+						logger.Error("Failed to close baggage restriction store", zap.Error(err))
+					}
+				}
+				if closer, ok := strategyStore.(io.Closer); ok {
+					if err := closer.Close(); err != nil {
+						logger.Error("Failed to close sampling strategy store", zap.Error(err))
+					}
+				}
 				if closer, ok := spanWriter.(io.Closer); ok {
 					server.GracefulStop()
 					err := closer.Close()
@@ -159,6 +249,9 @@ func main() {
 						logger.Error("Failed to close span writer", zap.Error(err))
 					}
 				}
+				if err := storageFactory.Close(); err != nil {
+					logger.Error("Failed to close storage factory", zap.Error(err))
+				}
 			})
 			return nil
 		},
@@ -173,8 +266,14 @@ func main() {
 		command,
 		svc.AddFlags,
 		builder.AddFlags,
+		restrictionstore.AddFlags,
+		dependencies.AddFlags,
+		tailsampling.AddFlags,
+		ratelimit.AddFlags,
+		sanitizer.AddFlags,
 		storageFactory.AddFlags,
 		strategyStoreFactory.AddFlags,
+		tenancy.AddFlags,
 	)
 
 	if err := command.Execute(); err != nil {
@@ -187,6 +286,7 @@ func startGRPCServer(
 	opts *builder.CollectorOptions,
 	handler *app.GRPCHandler,
 	samplingStore strategystore.StrategyStore,
+	baggageRestrictionStore restrictionstore.RestrictionStore,
 	logger *zap.Logger,
 ) (*grpc.Server, error) {
 	var server *grpc.Server
@@ -206,7 +306,7 @@ func startGRPCServer(
 	} else { // server without TLS
 		server = grpc.NewServer()
 	}
-	_, err := grpcserver.StartGRPCCollector(opts.CollectorGRPCPort, server, handler, samplingStore, logger, func(err error) {
+	_, err := grpcserver.StartGRPCCollector(opts.CollectorGRPCPort, server, handler, samplingStore, baggageRestrictionStore, logger, func(err error) {
 		logger.Fatal("gRPC collector failed", zap.Error(err))
 	})
 	if err != nil {
@@ -221,6 +321,7 @@ func startZipkinHTTPAPI(
 	allowedOrigins string,
 	allowedHeaders string,
 	zipkinSpansHandler app.ZipkinSpansHandler,
+	tenancyMgr *tenancy.Manager,
 	recoveryHandler func(http.Handler) http.Handler,
 ) {
 	if zipkinPort != 0 {
@@ -237,7 +338,7 @@ func startZipkinHTTPAPI(
 		httpPortStr := ":" + strconv.Itoa(zipkinPort)
 		logger.Info("Listening for Zipkin HTTP traffic", zap.Int("zipkin.http-port", zipkinPort))
 
-		if err := http.ListenAndServe(httpPortStr, c.Handler(recoveryHandler(r))); err != nil {
+		if err := http.ListenAndServe(httpPortStr, c.Handler(recoveryHandler(tenancy.ExtractTenantHTTPHandler(tenancyMgr, r)))); err != nil {
 			logger.Fatal("Could not launch service", zap.Error(err))
 		}
 	}
@@ -246,14 +347,64 @@ func startZipkinHTTPAPI(
 func initSamplingStrategyStore(
 	samplingStrategyStoreFactory *ss.Factory,
 	metricsFactory metrics.Factory,
+	samplingStoreFactory istorage.SamplingStoreFactory,
 	logger *zap.Logger,
-) strategystore.StrategyStore {
-	if err := samplingStrategyStoreFactory.Initialize(metricsFactory, logger); err != nil {
+) (strategystore.StrategyStore, strategystore.Aggregator) {
+	if err := samplingStrategyStoreFactory.Initialize(metricsFactory, samplingStoreFactory, logger); err != nil {
 		logger.Fatal("Failed to init sampling strategy store factory", zap.Error(err))
 	}
-	strategyStore, err := samplingStrategyStoreFactory.CreateStrategyStore()
+	strategyStore, aggregator, err := samplingStrategyStoreFactory.CreateStrategyStore()
 	if err != nil {
 		logger.Fatal("Failed to create sampling strategy store", zap.Error(err))
 	}
-	return strategyStore
+	return strategyStore, aggregator
+}
+
+func initDependenciesAggregator(
+	opts dependencies.Options,
+	storageFactory istorage.DependencyWriterFactory,
+	metricsFactory metrics.Factory,
+	logger *zap.Logger,
+) *dependencies.Aggregator {
+	dependencyWriter, err := storageFactory.CreateDependencyWriter()
+	if err != nil {
+		logger.Fatal("Failed to create dependency writer", zap.Error(err))
+	}
+	aggregator := dependencies.NewAggregator(opts, dependencyWriter, metricsFactory, logger)
+	aggregator.Start()
+	return aggregator
+}
+
+func initBaggageRestrictionStore(
+	opts *restrictionstore.Options,
+	metricsFactory metrics.Factory,
+	logger *zap.Logger,
+) restrictionstore.RestrictionStore {
+	if opts.RestrictionsFile == "" {
+		return nil
+	}
+	store, err := restrictionstore.NewFileStore(*opts, metricsFactory, logger)
+	if err != nil {
+		logger.Fatal("Failed to create baggage restriction store", zap.Error(err))
+	}
+	return store
+}
+
+func initSanitizer(opts *sanitizer.Options, logger *zap.Logger) sanitizer.SanitizeSpan {
+	rules, err := sanitizer.LoadRedactionRules(opts.RedactionRulesFile)
+	if err != nil {
+		logger.Fatal("Failed to load redaction rules", zap.Error(err))
+	}
+	var hashKey []byte
+	if opts.RedactionHashKeyFile != "" {
+		hashKey, err = sanitizer.LoadRedactionHashKey(opts.RedactionHashKeyFile)
+		if err != nil {
+			logger.Fatal("Failed to load redaction hash key", zap.Error(err))
+		}
+	}
+	redactionSanitizer, err := sanitizer.NewRedactionSanitizer(rules, hashKey)
+	if err != nil {
+		logger.Fatal("Failed to create redaction sanitizer", zap.Error(err))
+	}
+	return sanitizer.NewChainedSanitizer(redactionSanitizer)
 }
@@ -21,6 +21,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
+	"github.com/jaegertracing/jaeger/plugin/sampling/strategystore"
 	"github.com/jaegertracing/jaeger/plugin/storage"
 )
 
@@ -44,6 +45,8 @@ func Command() *cobra.Command {
 	fs := new(pflag.FlagSet)
 	fs.String(storage.SpanStorageTypeEnvVar, "cassandra", fmt.Sprintf("The type of backend %s used for trace storage. Multiple backends can be specified (currently only for writing spans) as comma-separated list, e.g. \"cassandra,kafka\".", storage.AllStorageTypes))
 	fs.String(storage.DependencyStorageTypeEnvVar, "${SPAN_STORAGE}", "The type of backend used for service dependencies storage.")
+	fs.String(storage.SamplingStorageTypeEnvVar, "${SPAN_STORAGE}", "The type of backend used for adaptive sampling storage.")
+	fs.String(strategystore.SamplingTypeEnvVar, "static", "The type of sampling strategy store, either \"static\" or \"adaptive\".")
 	long := fmt.Sprintf(longTemplate, strings.Replace(fs.FlagUsagesWrapped(0), "      --", "", -1))
 	return &cobra.Command{
 		Use:   "env",
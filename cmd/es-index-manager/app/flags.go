@@ -0,0 +1,71 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	archive           = "archive"
+	rolloverMaxAge    = "rollover.max-age"
+	rolloverMaxDocs   = "rollover.max-docs"
+	lookbackUnit      = "lookback.unit"
+	lookbackUnitCount = "lookback.unit-count"
+
+	defaultRolloverMaxAge    = "7d"
+	defaultLookbackUnit      = "days"
+	defaultLookbackUnitCount = 7
+)
+
+// Options holds the configuration of the index manager commands
+type Options struct {
+	Archive           bool
+	Rollover          RolloverConditions
+	LookbackUnit      string
+	LookbackUnitCount int
+}
+
+// AddFlags adds the flags shared by all the index manager commands
+func AddFlags(flagSet *flag.FlagSet) {
+	flagSet.Bool(archive, false, "Handle the archive indices instead of the span and service indices")
+}
+
+// AddRolloverFlags adds the flags of the rollover command
+func AddRolloverFlags(flagSet *flag.FlagSet) {
+	flagSet.String(rolloverMaxAge, defaultRolloverMaxAge,
+		`The maximum age of the write index before rolling over, e.g. "7d" or "12h". Empty to disable the condition`)
+	flagSet.Int64(rolloverMaxDocs, 0, "The maximum number of documents of the write index before rolling over. 0 to disable the condition")
+}
+
+// AddLookbackFlags adds the flags of the lookback command
+func AddLookbackFlags(flagSet *flag.FlagSet) {
+	flagSet.String(lookbackUnit, defaultLookbackUnit,
+		fmt.Sprintf("The unit of the lookback, the indices older than it are removed from the read alias, one of %v", lookbackUnits))
+	flagSet.Int(lookbackUnitCount, defaultLookbackUnitCount, "The number of lookback units")
+}
+
+// InitFromViper initializes Options with properties from viper
+func (o *Options) InitFromViper(v *viper.Viper) *Options {
+	o.Archive = v.GetBool(archive)
+	o.Rollover.MaxAge = v.GetString(rolloverMaxAge)
+	o.Rollover.MaxDocs = v.GetInt64(rolloverMaxDocs)
+	o.LookbackUnit = v.GetString(lookbackUnit)
+	o.LookbackUnitCount = v.GetInt(lookbackUnitCount)
+	return o
+}
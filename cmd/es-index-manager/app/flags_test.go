@@ -0,0 +1,53 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+func TestOptionsWithFlags(t *testing.T) {
+	o := &Options{}
+	v, command := config.Viperize(AddFlags, AddRolloverFlags, AddLookbackFlags)
+	command.ParseFlags([]string{
+		"--archive=true",
+		"--rollover.max-age=1d",
+		"--rollover.max-docs=1000",
+		"--lookback.unit=hours",
+		"--lookback.unit-count=12",
+	})
+	o.InitFromViper(v)
+
+	assert.True(t, o.Archive)
+	assert.Equal(t, RolloverConditions{MaxAge: "1d", MaxDocs: 1000}, o.Rollover)
+	assert.Equal(t, "hours", o.LookbackUnit)
+	assert.Equal(t, 12, o.LookbackUnitCount)
+}
+
+func TestFlagDefaults(t *testing.T) {
+	o := &Options{}
+	v, command := config.Viperize(AddFlags, AddRolloverFlags, AddLookbackFlags)
+	command.ParseFlags([]string{})
+	o.InitFromViper(v)
+
+	assert.False(t, o.Archive)
+	assert.Equal(t, RolloverConditions{MaxAge: defaultRolloverMaxAge}, o.Rollover)
+	assert.Equal(t, defaultLookbackUnit, o.LookbackUnit)
+	assert.Equal(t, defaultLookbackUnitCount, o.LookbackUnitCount)
+}
@@ -0,0 +1,443 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"gopkg.in/olivere/elastic.v5"
+
+	"github.com/jaegertracing/jaeger/pkg/es"
+	"github.com/jaegertracing/jaeger/pkg/retention"
+)
+
+const (
+	spanIndex    = "jaeger-span"
+	serviceIndex = "jaeger-service"
+	archiveIndex = "jaeger-span-archive"
+
+	readAliasSuffix    = "-read"
+	writeAliasSuffix   = "-write"
+	firstRolloverIndex = "-000001"
+
+	indexDateLayout = "2006-01-02"
+)
+
+var lookbackUnits = []string{"seconds", "minutes", "hours", "days", "weeks", "months", "years"}
+
+// dailyIndexRegexp matches the daily indices written when the aliases are not used, e.g. "jaeger-span-2019-06-01"
+var dailyIndexRegexp = regexp.MustCompile(`^jaeger-(span|service|dependencies)-(\d{4}-\d{2}-\d{2})$`)
+
+// rolloverIndexRegexp matches the suffix of the rollover indices, e.g. "-000001" in "jaeger-span-000001"
+var rolloverIndexRegexp = regexp.MustCompile(`^-\d{6}$`)
+
+// retentionIndexRegexp matches the daily span indices of a retention rule, e.g. "jaeger-span-payments-2019-06-01"
+var retentionIndexRegexp = regexp.MustCompile(`^jaeger-span-([a-z0-9][a-z0-9_-]*)-(\d{4}-\d{2}-\d{2})$`)
+
+// managedIndex is a rollover index managed through a read and a write alias
+type managedIndex struct {
+	name     string
+	template string
+}
+
+func (i managedIndex) readAlias() string {
+	return i.name + readAliasSuffix
+}
+
+func (i managedIndex) writeAlias() string {
+	return i.name + writeAliasSuffix
+}
+
+// indexInfo describes an existing index
+type indexInfo struct {
+	name         string
+	aliases      map[string]bool
+	creationDate time.Time
+}
+
+// IndexManager manages the Jaeger indices in Elasticsearch, like esRollover.py and esCleaner.py do.
+type IndexManager struct {
+	client      es.Client
+	logger      *zap.Logger
+	indexPrefix string
+	archive     bool
+	useAliases  bool
+	retention   *retention.Policy
+	timeNow     func() time.Time
+}
+
+// NewIndexManager creates an IndexManager for the indices with the given prefix. It manages the archive
+// indices if archive is true, and the span and service indices otherwise. The retention policy, which
+// may be nil, sets how long the daily span indices of its rules are kept.
+func NewIndexManager(
+	client es.Client,
+	logger *zap.Logger,
+	indexPrefix string,
+	archive, useAliases bool,
+	retentionPolicy *retention.Policy,
+) *IndexManager {
+	if indexPrefix != "" {
+		indexPrefix += "-"
+	}
+	return &IndexManager{
+		client:      client,
+		logger:      logger,
+		indexPrefix: indexPrefix,
+		archive:     archive,
+		useAliases:  useAliases,
+		retention:   retentionPolicy,
+		timeNow:     time.Now,
+	}
+}
+
+func (m *IndexManager) managedIndices() []managedIndex {
+	if m.archive {
+		return []managedIndex{{name: m.indexPrefix + archiveIndex, template: spanIndex}}
+	}
+	return []managedIndex{
+		{name: m.indexPrefix + spanIndex, template: spanIndex},
+		{name: m.indexPrefix + serviceIndex, template: serviceIndex},
+	}
+}
+
+// Init creates the index templates and the first rollover indices, and points the read and write
+// aliases to them unless the aliases already exist.
+func (m *IndexManager) Init(spanMapping, serviceMapping string) error {
+	ctx := context.Background()
+	mappings := map[string]string{spanIndex: spanMapping, serviceIndex: serviceMapping}
+	for _, index := range m.managedIndices() {
+		m.logger.Info("Creating index template", zap.String("template", index.template))
+		if _, err := m.client.IndexPutTemplate(index.template).BodyString(mappings[index.template]).Do(ctx); err != nil {
+			return errors.Wrapf(err, "failed to create index template %s", index.template)
+		}
+
+		firstIndex := index.name + firstRolloverIndex
+		exists, err := m.client.IndexExists(firstIndex).Do(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "failed to check whether index %s exists", firstIndex)
+		}
+		if !exists {
+			m.logger.Info("Creating index", zap.String("index", firstIndex))
+			if _, err := m.client.CreateIndex(firstIndex).Do(ctx); err != nil {
+				return errors.Wrapf(err, "failed to create index %s", firstIndex)
+			}
+		}
+
+		indices, err := m.indices(index.name + "-*")
+		if err != nil {
+			return err
+		}
+		for _, alias := range []string{index.readAlias(), index.writeAlias()} {
+			if len(filterByAlias(indices, alias)) > 0 {
+				m.logger.Info("Alias is not empty, not adding indices to it", zap.String("alias", alias))
+				continue
+			}
+			if err := m.addToAlias(alias, []string{firstIndex}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RolloverConditions are the conditions under which Rollover creates a new write index
+type RolloverConditions struct {
+	// MaxAge is the maximum age of the write index, in the Elasticsearch time units format, e.g. "7d"
+	MaxAge string
+	// MaxDocs is the maximum number of documents of the write index
+	MaxDocs int64
+}
+
+// Rollover points the write aliases to new indices if the conditions are met, and adds the new indices
+// to the read aliases.
+func (m *IndexManager) Rollover(conditions RolloverConditions) error {
+	ctx := context.Background()
+	for _, index := range m.managedIndices() {
+		m.logger.Info("Rolling over",
+			zap.String("alias", index.writeAlias()),
+			zap.String("max_age", conditions.MaxAge),
+			zap.Int64("max_docs", conditions.MaxDocs))
+		rollover := m.client.RolloverIndex(index.writeAlias())
+		if conditions.MaxAge != "" {
+			rollover = rollover.AddMaxIndexAgeCondition(conditions.MaxAge)
+		}
+		if conditions.MaxDocs > 0 {
+			rollover = rollover.AddMaxIndexDocsCondition(conditions.MaxDocs)
+		}
+		response, err := rollover.Do(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "failed to roll over alias %s", index.writeAlias())
+		}
+		if !response.RolledOver {
+			m.logger.Info("Conditions not met, not rolling over", zap.String("alias", index.writeAlias()))
+			continue
+		}
+
+		indices, err := m.indices(index.name + "-*")
+		if err != nil {
+			return err
+		}
+		var newIndices []string
+		for _, info := range filterByAlias(indices, index.writeAlias()) {
+			if !info.aliases[index.readAlias()] {
+				newIndices = append(newIndices, info.name)
+			}
+		}
+		if err := m.addToAlias(index.readAlias(), newIndices); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Lookback removes the indices created more than unitCount units ago from the read aliases, except the
+// ones of the write aliases. It mimics --es.max-span-age for the read aliases.
+func (m *IndexManager) Lookback(unit string, unitCount int) error {
+	createdBefore, err := lookbackTime(m.timeNow(), unit, unitCount)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	for _, index := range m.managedIndices() {
+		indices, err := m.indices(index.name + "-*")
+		if err != nil {
+			return err
+		}
+		var oldIndices []string
+		for _, info := range filterByAlias(indices, index.readAlias()) {
+			if !info.aliases[index.writeAlias()] && info.creationDate.Before(createdBefore) {
+				oldIndices = append(oldIndices, info.name)
+			}
+		}
+		if len(oldIndices) == 0 {
+			m.logger.Info("No indices to remove from alias", zap.String("alias", index.readAlias()))
+			continue
+		}
+		aliasService := m.client.Alias()
+		for _, name := range oldIndices {
+			m.logger.Info("Removing index from alias", zap.String("index", name), zap.String("alias", index.readAlias()))
+			aliasService = aliasService.Remove(name, index.readAlias())
+		}
+		if _, err := aliasService.Do(ctx); err != nil {
+			return errors.Wrapf(err, "failed to remove indices from alias %s", index.readAlias())
+		}
+	}
+	return nil
+}
+
+// Clean deletes the indices older than the given number of days. The daily indices are selected by the
+// date in their name, the rollover indices by their creation date, whether or not Lookback removed them
+// from the read alias. The indices of the write aliases are never deleted. The daily span indices of a
+// retention rule are deleted once older than the TTL of the rule, or than the given number of days if
+// the rule is no longer in the policy.
+func (m *IndexManager) Clean(days int) error {
+	var toDelete []string
+	if m.archive || m.useAliases {
+		createdBefore := m.timeNow().AddDate(0, 0, -days)
+		for _, index := range m.managedIndices() {
+			indices, err := m.indices(index.name + "-*")
+			if err != nil {
+				return err
+			}
+			for _, info := range indices {
+				if !rolloverIndexRegexp.MatchString(strings.TrimPrefix(info.name, index.name)) {
+					// e.g. the archive indices matching the span indices pattern
+					continue
+				}
+				if !info.aliases[index.writeAlias()] && info.creationDate.Before(createdBefore) {
+					toDelete = append(toDelete, info.name)
+				}
+			}
+		}
+	} else {
+		dateBefore := utcDate(m.timeNow().AddDate(0, 0, -days))
+		indices, err := m.indices(m.indexPrefix + "jaeger-*")
+		if err != nil {
+			return err
+		}
+		for _, info := range indices {
+			if date, ok := m.dailyIndexDate(info.name); ok {
+				if date.Before(dateBefore) {
+					toDelete = append(toDelete, info.name)
+				}
+				continue
+			}
+			if ruleName, date, ok := m.retentionIndexDate(info.name); ok {
+				ruleDateBefore := dateBefore
+				if rule, ok := m.ruleByName(ruleName); ok {
+					ruleDateBefore = utcDate(m.timeNow().Add(-rule.TTL))
+				}
+				if date.Before(ruleDateBefore) {
+					toDelete = append(toDelete, info.name)
+				}
+			}
+		}
+	}
+
+	if len(toDelete) == 0 {
+		m.logger.Info("No indices to delete")
+		return nil
+	}
+	for _, name := range toDelete {
+		m.logger.Info("Removing index", zap.String("index", name))
+	}
+	if _, err := m.client.DeleteIndex(toDelete...).Do(context.Background()); err != nil {
+		return errors.Wrap(err, "failed to delete indices")
+	}
+	return nil
+}
+
+// lookbackTime returns the time unitCount units before now
+func lookbackTime(now time.Time, unit string, unitCount int) (time.Time, error) {
+	switch unit {
+	case "seconds":
+		return now.Add(-time.Duration(unitCount) * time.Second), nil
+	case "minutes":
+		return now.Add(-time.Duration(unitCount) * time.Minute), nil
+	case "hours":
+		return now.Add(-time.Duration(unitCount) * time.Hour), nil
+	case "days":
+		return now.AddDate(0, 0, -unitCount), nil
+	case "weeks":
+		return now.AddDate(0, 0, -7*unitCount), nil
+	case "months":
+		return now.AddDate(0, -unitCount, 0), nil
+	case "years":
+		return now.AddDate(-unitCount, 0, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("unknown lookback unit %q, use one of %v", unit, lookbackUnits)
+}
+
+// dailyIndexDate returns the date of a daily index with the prefix of the manager
+func (m *IndexManager) dailyIndexDate(name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, m.indexPrefix) {
+		return time.Time{}, false
+	}
+	match := dailyIndexRegexp.FindStringSubmatch(name[len(m.indexPrefix):])
+	if match == nil {
+		return time.Time{}, false
+	}
+	date, err := time.Parse(indexDateLayout, match[2])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// retentionIndexDate returns the rule name and the date of a daily span index of a retention rule
+// with the prefix of the manager
+func (m *IndexManager) retentionIndexDate(name string) (string, time.Time, bool) {
+	if !strings.HasPrefix(name, m.indexPrefix) {
+		return "", time.Time{}, false
+	}
+	match := retentionIndexRegexp.FindStringSubmatch(name[len(m.indexPrefix):])
+	if match == nil {
+		return "", time.Time{}, false
+	}
+	date, err := time.Parse(indexDateLayout, match[2])
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return match[1], date, true
+}
+
+func (m *IndexManager) ruleByName(name string) (retention.Rule, bool) {
+	for _, rule := range m.retention.Rules() {
+		if rule.Name == name {
+			return rule, true
+		}
+	}
+	return retention.Rule{}, false
+}
+
+// utcDate returns the start of the UTC day of t
+func utcDate(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func (m *IndexManager) addToAlias(alias string, indices []string) error {
+	if len(indices) == 0 {
+		return nil
+	}
+	aliasService := m.client.Alias()
+	for _, name := range indices {
+		m.logger.Info("Adding index to alias", zap.String("index", name), zap.String("alias", alias))
+		aliasService = aliasService.Add(name, alias)
+	}
+	if _, err := aliasService.Do(context.Background()); err != nil {
+		return errors.Wrapf(err, "failed to add indices to alias %s", alias)
+	}
+	return nil
+}
+
+// indices returns the indices matching the pattern, sorted by name
+func (m *IndexManager) indices(pattern string) ([]indexInfo, error) {
+	response, err := m.client.IndexGet(pattern).Do(context.Background())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get indices %s", pattern)
+	}
+	indices := make([]indexInfo, 0, len(response))
+	for name, index := range response {
+		creationDate, err := creationDate(index)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid settings of index %s", name)
+		}
+		aliases := make(map[string]bool, len(index.Aliases))
+		for alias := range index.Aliases {
+			aliases[alias] = true
+		}
+		indices = append(indices, indexInfo{name: name, aliases: aliases, creationDate: creationDate})
+	}
+	sort.Slice(indices, func(i, j int) bool {
+		return indices[i].name < indices[j].name
+	})
+	return indices, nil
+}
+
+func filterByAlias(indices []indexInfo, alias string) []indexInfo {
+	var filtered []indexInfo
+	for _, info := range indices {
+		if info.aliases[alias] {
+			filtered = append(filtered, info)
+		}
+	}
+	return filtered
+}
+
+// creationDate reads the creation date from the index settings, which hold it in milliseconds as a string
+func creationDate(index *elastic.IndicesGetResponse) (time.Time, error) {
+	settings, ok := index.Settings["index"].(map[string]interface{})
+	if !ok {
+		return time.Time{}, errors.New("no index settings")
+	}
+	value, ok := settings["creation_date"].(string)
+	if !ok {
+		return time.Time{}, errors.New("no creation date")
+	}
+	millis, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid creation date %q", value)
+	}
+	return time.Unix(0, millis*int64(time.Millisecond)), nil
+}
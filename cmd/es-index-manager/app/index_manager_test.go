@@ -0,0 +1,697 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gopkg.in/olivere/elastic.v5"
+
+	"github.com/jaegertracing/jaeger/pkg/es"
+	"github.com/jaegertracing/jaeger/pkg/es/mocks"
+	"github.com/jaegertracing/jaeger/pkg/retention"
+)
+
+var testNow = time.Date(2019, 6, 10, 12, 0, 0, 0, time.UTC)
+
+type fakeIndex struct {
+	aliases map[string]bool
+	created time.Time
+}
+
+// fakeClient is an in-memory es.Client supporting the index management calls
+type fakeClient struct {
+	mocks.Client
+	indices    map[string]*fakeIndex
+	templates  map[string]string
+	rollOver   bool
+	conditions map[string]interface{}
+	err        error
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{indices: make(map[string]*fakeIndex), templates: make(map[string]string), rollOver: true}
+}
+
+func (c *fakeClient) addIndex(name string, created time.Time, aliases ...string) {
+	index := &fakeIndex{aliases: make(map[string]bool), created: created}
+	for _, alias := range aliases {
+		index.aliases[alias] = true
+	}
+	c.indices[name] = index
+}
+
+func (c *fakeClient) indexNames() []string {
+	var names []string
+	for name := range c.indices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (c *fakeClient) aliasIndices(alias string) []string {
+	var names []string
+	for _, name := range c.indexNames() {
+		if c.indices[name].aliases[alias] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (c *fakeClient) IndexExists(index string) es.IndicesExistsService {
+	return fakeExistsService(func() (bool, error) {
+		_, ok := c.indices[index]
+		return ok, c.err
+	})
+}
+
+func (c *fakeClient) CreateIndex(index string) es.IndicesCreateService {
+	return fakeCreateService(func() error {
+		if c.err != nil {
+			return c.err
+		}
+		c.addIndex(index, testNow)
+		return nil
+	})
+}
+
+func (c *fakeClient) IndexGet(indices ...string) es.IndicesGetService {
+	return fakeGetService(func() (map[string]*elastic.IndicesGetResponse, error) {
+		if c.err != nil {
+			return nil, c.err
+		}
+		response := make(map[string]*elastic.IndicesGetResponse)
+		for name, index := range c.indices {
+			if !strings.HasPrefix(name, strings.TrimSuffix(indices[0], "*")) {
+				continue
+			}
+			aliases := make(map[string]interface{})
+			for alias := range index.aliases {
+				aliases[alias] = map[string]interface{}{}
+			}
+			created := strconv.FormatInt(index.created.UnixNano()/int64(time.Millisecond), 10)
+			response[name] = &elastic.IndicesGetResponse{
+				Aliases:  aliases,
+				Settings: map[string]interface{}{"index": map[string]interface{}{"creation_date": created}},
+			}
+		}
+		return response, nil
+	})
+}
+
+func (c *fakeClient) DeleteIndex(indices ...string) es.IndicesDeleteService {
+	return fakeDeleteService(func() error {
+		if c.err != nil {
+			return c.err
+		}
+		for _, index := range indices {
+			delete(c.indices, index)
+		}
+		return nil
+	})
+}
+
+func (c *fakeClient) IndexPutTemplate(name string) es.IndicesPutTemplateService {
+	return &fakePutTemplateService{do: func(body string) error {
+		if c.err != nil {
+			return c.err
+		}
+		c.templates[name] = body
+		return nil
+	}}
+}
+
+func (c *fakeClient) RolloverIndex(alias string) es.IndicesRolloverService {
+	return &fakeRolloverService{conditions: make(map[string]interface{}), do: func(conditions map[string]interface{}) (*elastic.IndicesRolloverResponse, error) {
+		if c.err != nil {
+			return nil, c.err
+		}
+		c.conditions = conditions
+		if !c.rollOver {
+			return &elastic.IndicesRolloverResponse{}, nil
+		}
+		oldIndex := c.aliasIndices(alias)[0]
+		counter, _ := strconv.Atoi(oldIndex[len(oldIndex)-6:])
+		newIndex := fmt.Sprintf("%s%06d", oldIndex[:len(oldIndex)-6], counter+1)
+		delete(c.indices[oldIndex].aliases, alias)
+		c.addIndex(newIndex, testNow, alias)
+		return &elastic.IndicesRolloverResponse{OldIndex: oldIndex, NewIndex: newIndex, RolledOver: true}, nil
+	}}
+}
+
+func (c *fakeClient) Alias() es.AliasService {
+	return &fakeAliasService{client: c}
+}
+
+type fakeExistsService func() (bool, error)
+
+func (s fakeExistsService) Do(ctx context.Context) (bool, error) {
+	return s()
+}
+
+type fakeCreateService func() error
+
+func (s fakeCreateService) Body(mapping string) es.IndicesCreateService {
+	return s
+}
+
+func (s fakeCreateService) Do(ctx context.Context) (*elastic.IndicesCreateResult, error) {
+	return &elastic.IndicesCreateResult{}, s()
+}
+
+type fakeGetService func() (map[string]*elastic.IndicesGetResponse, error)
+
+func (s fakeGetService) Do(ctx context.Context) (map[string]*elastic.IndicesGetResponse, error) {
+	return s()
+}
+
+type fakeDeleteService func() error
+
+func (s fakeDeleteService) Do(ctx context.Context) (*elastic.IndicesDeleteResponse, error) {
+	return &elastic.IndicesDeleteResponse{}, s()
+}
+
+type fakePutTemplateService struct {
+	body string
+	do   func(body string) error
+}
+
+func (s *fakePutTemplateService) BodyString(body string) es.IndicesPutTemplateService {
+	s.body = body
+	return s
+}
+
+func (s *fakePutTemplateService) Do(ctx context.Context) (*elastic.IndicesPutTemplateResponse, error) {
+	return &elastic.IndicesPutTemplateResponse{}, s.do(s.body)
+}
+
+type fakeRolloverService struct {
+	conditions map[string]interface{}
+	do         func(conditions map[string]interface{}) (*elastic.IndicesRolloverResponse, error)
+}
+
+func (s *fakeRolloverService) AddMaxIndexAgeCondition(age string) es.IndicesRolloverService {
+	s.conditions["max_age"] = age
+	return s
+}
+
+func (s *fakeRolloverService) AddMaxIndexDocsCondition(docs int64) es.IndicesRolloverService {
+	s.conditions["max_docs"] = docs
+	return s
+}
+
+func (s *fakeRolloverService) Do(ctx context.Context) (*elastic.IndicesRolloverResponse, error) {
+	return s.do(s.conditions)
+}
+
+type fakeAliasService struct {
+	client  *fakeClient
+	actions []func()
+}
+
+func (s *fakeAliasService) Add(indexName string, aliasName string) es.AliasService {
+	s.actions = append(s.actions, func() { s.client.indices[indexName].aliases[aliasName] = true })
+	return s
+}
+
+func (s *fakeAliasService) Remove(indexName string, aliasName string) es.AliasService {
+	s.actions = append(s.actions, func() { delete(s.client.indices[indexName].aliases, aliasName) })
+	return s
+}
+
+func (s *fakeAliasService) Do(ctx context.Context) (*elastic.AliasResult, error) {
+	if s.client.err != nil {
+		return nil, s.client.err
+	}
+	for _, action := range s.actions {
+		action()
+	}
+	return &elastic.AliasResult{}, nil
+}
+
+func newTestIndexManager(client es.Client, indexPrefix string, archive, useAliases bool) *IndexManager {
+	manager := NewIndexManager(client, zap.NewNop(), indexPrefix, archive, useAliases, nil)
+	manager.timeNow = func() time.Time {
+		return testNow
+	}
+	return manager
+}
+
+func TestInit(t *testing.T) {
+	testCases := []struct {
+		name            string
+		indexPrefix     string
+		archive         bool
+		expectedAliases map[string][]string
+	}{
+		{
+			name: "span and service indices",
+			expectedAliases: map[string][]string{
+				"jaeger-span-read":     {"jaeger-span-000001"},
+				"jaeger-span-write":    {"jaeger-span-000001"},
+				"jaeger-service-read":  {"jaeger-service-000001"},
+				"jaeger-service-write": {"jaeger-service-000001"},
+			},
+		},
+		{
+			name:        "prefixed archive indices",
+			indexPrefix: "foo",
+			archive:     true,
+			expectedAliases: map[string][]string{
+				"foo-jaeger-span-archive-read":  {"foo-jaeger-span-archive-000001"},
+				"foo-jaeger-span-archive-write": {"foo-jaeger-span-archive-000001"},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			client := newFakeClient()
+			manager := newTestIndexManager(client, testCase.indexPrefix, testCase.archive, true)
+			require.NoError(t, manager.Init("span mapping", "service mapping"))
+
+			for alias, indices := range testCase.expectedAliases {
+				assert.Equal(t, indices, client.aliasIndices(alias), alias)
+			}
+			assert.Equal(t, "span mapping", client.templates["jaeger-span"])
+			if !testCase.archive {
+				assert.Equal(t, "service mapping", client.templates["jaeger-service"])
+			}
+
+			// running it again does not change the existing aliases
+			require.NoError(t, manager.Rollover(RolloverConditions{}))
+			require.NoError(t, manager.Init("span mapping", "service mapping"))
+			for alias := range testCase.expectedAliases {
+				if strings.HasSuffix(alias, writeAliasSuffix) {
+					assert.Len(t, client.aliasIndices(alias), 1, alias)
+					assert.NotEqual(t, testCase.expectedAliases[alias], client.aliasIndices(alias), alias)
+				} else {
+					assert.Len(t, client.aliasIndices(alias), 2, alias)
+				}
+			}
+		})
+	}
+}
+
+func TestRollover(t *testing.T) {
+	client := newFakeClient()
+	client.addIndex("jaeger-span-000001", testNow, "jaeger-span-read", "jaeger-span-write")
+	client.addIndex("jaeger-service-000001", testNow, "jaeger-service-read", "jaeger-service-write")
+	manager := newTestIndexManager(client, "", false, true)
+
+	client.rollOver = false
+	require.NoError(t, manager.Rollover(RolloverConditions{MaxAge: "2d"}))
+	assert.Equal(t, map[string]interface{}{"max_age": "2d"}, client.conditions)
+	assert.Equal(t, []string{"jaeger-span-000001"}, client.aliasIndices("jaeger-span-read"))
+
+	client.rollOver = true
+	require.NoError(t, manager.Rollover(RolloverConditions{MaxAge: "1d", MaxDocs: 1000}))
+	assert.Equal(t, map[string]interface{}{"max_age": "1d", "max_docs": int64(1000)}, client.conditions)
+	assert.Equal(t, []string{"jaeger-span-000001", "jaeger-span-000002"}, client.aliasIndices("jaeger-span-read"))
+	assert.Equal(t, []string{"jaeger-span-000002"}, client.aliasIndices("jaeger-span-write"))
+	assert.Equal(t, []string{"jaeger-service-000001", "jaeger-service-000002"}, client.aliasIndices("jaeger-service-read"))
+	assert.Equal(t, []string{"jaeger-service-000002"}, client.aliasIndices("jaeger-service-write"))
+}
+
+func TestLookback(t *testing.T) {
+	client := newFakeClient()
+	client.addIndex("foo-jaeger-span-archive-000001", testNow.AddDate(0, 0, -10), "foo-jaeger-span-archive-read")
+	client.addIndex("foo-jaeger-span-archive-000002", testNow.AddDate(0, 0, -8), "foo-jaeger-span-archive-read")
+	client.addIndex("foo-jaeger-span-archive-000003", testNow.AddDate(0, 0, -6), "foo-jaeger-span-archive-read")
+	client.addIndex("foo-jaeger-span-archive-000004", testNow.AddDate(0, 0, -4),
+		"foo-jaeger-span-archive-read", "foo-jaeger-span-archive-write")
+	manager := newTestIndexManager(client, "foo", true, true)
+
+	require.NoError(t, manager.Lookback("weeks", 1))
+	assert.Equal(t, []string{"foo-jaeger-span-archive-000003", "foo-jaeger-span-archive-000004"},
+		client.aliasIndices("foo-jaeger-span-archive-read"))
+	assert.Len(t, client.indices, 4)
+
+	// the write index is never removed from the read alias
+	require.NoError(t, manager.Lookback("hours", 1))
+	assert.Equal(t, []string{"foo-jaeger-span-archive-000004"}, client.aliasIndices("foo-jaeger-span-archive-read"))
+	require.NoError(t, manager.Lookback("hours", 1))
+
+	assert.EqualError(t, manager.Lookback("fortnights", 1),
+		`unknown lookback unit "fortnights", use one of [seconds minutes hours days weeks months years]`)
+}
+
+func TestLookbackTime(t *testing.T) {
+	testCases := []struct {
+		unit     string
+		expected time.Time
+	}{
+		{unit: "seconds", expected: testNow.Add(-2 * time.Second)},
+		{unit: "minutes", expected: testNow.Add(-2 * time.Minute)},
+		{unit: "hours", expected: testNow.Add(-2 * time.Hour)},
+		{unit: "days", expected: time.Date(2019, 6, 8, 12, 0, 0, 0, time.UTC)},
+		{unit: "weeks", expected: time.Date(2019, 5, 27, 12, 0, 0, 0, time.UTC)},
+		{unit: "months", expected: time.Date(2019, 4, 10, 12, 0, 0, 0, time.UTC)},
+		{unit: "years", expected: time.Date(2017, 6, 10, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, testCase := range testCases {
+		actual, err := lookbackTime(testNow, testCase.unit, 2)
+		require.NoError(t, err)
+		assert.Equal(t, testCase.expected, actual, testCase.unit)
+	}
+}
+
+func TestCleanDailyIndices(t *testing.T) {
+	testCases := []struct {
+		name        string
+		indexPrefix string
+		days        int
+		expected    []string
+	}{
+		{
+			name: "no prefix",
+			days: 2,
+			expected: []string{
+				"foo-jaeger-span-2019-06-01",
+				"jaeger-dependencies-2019-06-09",
+				"jaeger-service-2019-06-08",
+				"jaeger-span-2019-06-08",
+				"jaeger-span-archive",
+				"jaeger-span-archive-000001",
+			},
+		},
+		{
+			name:        "prefix",
+			indexPrefix: "foo",
+			days:        0,
+			expected: []string{
+				"jaeger-dependencies-2019-06-09",
+				"jaeger-service-2019-06-07",
+				"jaeger-service-2019-06-08",
+				"jaeger-span-2019-06-07",
+				"jaeger-span-2019-06-08",
+				"jaeger-span-archive",
+				"jaeger-span-archive-000001",
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			client := newFakeClient()
+			for _, name := range []string{
+				"foo-jaeger-span-2019-06-01",
+				"jaeger-dependencies-2019-06-09",
+				"jaeger-service-2019-06-07",
+				"jaeger-service-2019-06-08",
+				"jaeger-span-2019-06-07",
+				"jaeger-span-2019-06-08",
+				"jaeger-span-archive",
+				"jaeger-span-archive-000001",
+			} {
+				client.addIndex(name, testNow.AddDate(-1, 0, 0))
+			}
+			manager := newTestIndexManager(client, testCase.indexPrefix, false, false)
+			require.NoError(t, manager.Clean(testCase.days))
+			assert.Equal(t, testCase.expected, client.indexNames())
+		})
+	}
+}
+
+func TestCleanRetentionIndices(t *testing.T) {
+	policy, err := retention.NewPolicy([]retention.Rule{
+		{Name: "payments", Services: []string{"payments"}, TTL: 72 * time.Hour},
+		{Name: "batch", ServicePatterns: []string{"^batch-"}, TTL: 12 * time.Hour},
+	})
+	require.NoError(t, err)
+	client := newFakeClient()
+	for _, name := range []string{
+		"jaeger-service-2019-06-04",
+		"jaeger-span-2019-06-04",
+		"jaeger-span-2019-06-05",
+		"jaeger-span-batch-2019-06-09",
+		"jaeger-span-batch-2019-06-10",
+		"jaeger-span-old-2019-06-04",
+		"jaeger-span-old-2019-06-05",
+		"jaeger-span-payments-2019-06-06",
+		"jaeger-span-payments-2019-06-07",
+	} {
+		client.addIndex(name, testNow.AddDate(-1, 0, 0))
+	}
+	manager := newTestIndexManager(client, "", false, false)
+	manager.retention = policy
+	require.NoError(t, manager.Clean(5))
+	// the indices of the removed rule "old" are kept for the given number of days
+	assert.Equal(t, []string{
+		"jaeger-span-2019-06-05",
+		"jaeger-span-batch-2019-06-10",
+		"jaeger-span-old-2019-06-05",
+		"jaeger-span-payments-2019-06-07",
+	}, client.indexNames())
+}
+
+func TestCleanRolloverIndices(t *testing.T) {
+	client := newFakeClient()
+	client.addIndex("jaeger-span-000001", testNow.AddDate(0, 0, -10), "jaeger-span-read")
+	client.addIndex("jaeger-span-000002", testNow.AddDate(0, 0, -5), "jaeger-span-read")
+	client.addIndex("jaeger-span-000003", testNow.AddDate(0, 0, -20), "jaeger-span-read", "jaeger-span-write")
+	client.addIndex("jaeger-service-000001", testNow.AddDate(0, 0, -10))
+	client.addIndex("jaeger-service-000002", testNow.AddDate(0, 0, -10), "jaeger-service-read", "jaeger-service-write")
+	client.addIndex("jaeger-span-archive-000001", testNow.AddDate(0, 0, -10), "jaeger-span-archive-read")
+	manager := newTestIndexManager(client, "", false, true)
+
+	require.NoError(t, manager.Clean(7))
+	assert.Equal(t, []string{"jaeger-service-000002", "jaeger-span-000002", "jaeger-span-000003", "jaeger-span-archive-000001"},
+		client.indexNames())
+	require.NoError(t, manager.Clean(7))
+}
+
+func TestLookbackThenClean(t *testing.T) {
+	client := newFakeClient()
+	client.addIndex("jaeger-span-000001", testNow.AddDate(0, 0, -10), "jaeger-span-read")
+	client.addIndex("jaeger-span-000002", testNow.AddDate(0, 0, -5), "jaeger-span-read")
+	client.addIndex("jaeger-span-000003", testNow.AddDate(0, 0, -1), "jaeger-span-read", "jaeger-span-write")
+	client.addIndex("jaeger-service-000001", testNow.AddDate(0, 0, -10), "jaeger-service-read", "jaeger-service-write")
+	manager := newTestIndexManager(client, "", false, true)
+
+	require.NoError(t, manager.Lookback("days", 3))
+	assert.Equal(t, []string{"jaeger-span-000003"}, client.aliasIndices("jaeger-span-read"))
+
+	// the indices removed from the read alias are still deleted once old enough
+	require.NoError(t, manager.Clean(7))
+	assert.Equal(t, []string{"jaeger-service-000001", "jaeger-span-000002", "jaeger-span-000003"}, client.indexNames())
+}
+
+func TestIndexManagerErrors(t *testing.T) {
+	client := newFakeClient()
+	client.addIndex("jaeger-span-000001", testNow, "jaeger-span-read", "jaeger-span-write")
+	client.addIndex("jaeger-span-2019-01-01", testNow)
+	client.err = errors.New("boom")
+	manager := newTestIndexManager(client, "", false, true)
+	dailyManager := newTestIndexManager(client, "", false, false)
+
+	assert.EqualError(t, manager.Init("", ""), "failed to create index template jaeger-span: boom")
+	assert.EqualError(t, manager.Rollover(RolloverConditions{}), "failed to roll over alias jaeger-span-write: boom")
+	assert.EqualError(t, manager.Lookback("days", 1), "failed to get indices jaeger-span-*: boom")
+	assert.EqualError(t, manager.Clean(1), "failed to get indices jaeger-span-*: boom")
+	assert.EqualError(t, dailyManager.Clean(1), "failed to get indices jaeger-*: boom")
+}
+
+func TestIndexManagerServiceErrors(t *testing.T) {
+	boom := errors.New("boom")
+	readIndices := map[string]*elastic.IndicesGetResponse{
+		"jaeger-span-000001": {
+			Aliases:  map[string]interface{}{"jaeger-span-read": map[string]interface{}{}},
+			Settings: map[string]interface{}{"index": map[string]interface{}{"creation_date": "0"}},
+		},
+	}
+	failingGet := func(response map[string]*elastic.IndicesGetResponse, err error) es.IndicesGetService {
+		getService := &mocks.IndicesGetService{}
+		getService.On("Do", mock.Anything).Return(response, err)
+		return getService
+	}
+	failingAlias := func() es.AliasService {
+		aliasService := &mocks.AliasService{}
+		aliasService.On("Add", mock.Anything, mock.Anything).Return(aliasService)
+		aliasService.On("Remove", mock.Anything, mock.Anything).Return(aliasService)
+		aliasService.On("Do", mock.Anything).Return(nil, boom)
+		return aliasService
+	}
+
+	testCases := []struct {
+		name          string
+		setup         func(client *mocks.Client)
+		run           func(manager *IndexManager) error
+		expectedError string
+	}{
+		{
+			name: "index exists",
+			setup: func(client *mocks.Client) {
+				putTemplate := &mocks.IndicesPutTemplateService{}
+				putTemplate.On("BodyString", mock.Anything).Return(putTemplate)
+				putTemplate.On("Do", mock.Anything).Return(&elastic.IndicesPutTemplateResponse{}, nil)
+				client.On("IndexPutTemplate", mock.Anything).Return(putTemplate)
+				exists := &mocks.IndicesExistsService{}
+				exists.On("Do", mock.Anything).Return(false, boom)
+				client.On("IndexExists", mock.Anything).Return(exists)
+			},
+			run: func(manager *IndexManager) error {
+				return manager.Init("", "")
+			},
+			expectedError: "failed to check whether index jaeger-span-000001 exists: boom",
+		},
+		{
+			name: "create index",
+			setup: func(client *mocks.Client) {
+				putTemplate := &mocks.IndicesPutTemplateService{}
+				putTemplate.On("BodyString", mock.Anything).Return(putTemplate)
+				putTemplate.On("Do", mock.Anything).Return(&elastic.IndicesPutTemplateResponse{}, nil)
+				client.On("IndexPutTemplate", mock.Anything).Return(putTemplate)
+				exists := &mocks.IndicesExistsService{}
+				exists.On("Do", mock.Anything).Return(false, nil)
+				client.On("IndexExists", mock.Anything).Return(exists)
+				create := &mocks.IndicesCreateService{}
+				create.On("Do", mock.Anything).Return(nil, boom)
+				client.On("CreateIndex", mock.Anything).Return(create)
+			},
+			run: func(manager *IndexManager) error {
+				return manager.Init("", "")
+			},
+			expectedError: "failed to create index jaeger-span-000001: boom",
+		},
+		{
+			name: "list indices after init",
+			setup: func(client *mocks.Client) {
+				putTemplate := &mocks.IndicesPutTemplateService{}
+				putTemplate.On("BodyString", mock.Anything).Return(putTemplate)
+				putTemplate.On("Do", mock.Anything).Return(&elastic.IndicesPutTemplateResponse{}, nil)
+				client.On("IndexPutTemplate", mock.Anything).Return(putTemplate)
+				exists := &mocks.IndicesExistsService{}
+				exists.On("Do", mock.Anything).Return(true, nil)
+				client.On("IndexExists", mock.Anything).Return(exists)
+				client.On("IndexGet", mock.Anything).Return(failingGet(nil, boom))
+			},
+			run: func(manager *IndexManager) error {
+				return manager.Init("", "")
+			},
+			expectedError: "failed to get indices jaeger-span-*: boom",
+		},
+		{
+			name: "add to alias",
+			setup: func(client *mocks.Client) {
+				putTemplate := &mocks.IndicesPutTemplateService{}
+				putTemplate.On("BodyString", mock.Anything).Return(putTemplate)
+				putTemplate.On("Do", mock.Anything).Return(&elastic.IndicesPutTemplateResponse{}, nil)
+				client.On("IndexPutTemplate", mock.Anything).Return(putTemplate)
+				exists := &mocks.IndicesExistsService{}
+				exists.On("Do", mock.Anything).Return(true, nil)
+				client.On("IndexExists", mock.Anything).Return(exists)
+				client.On("IndexGet", mock.Anything).Return(failingGet(map[string]*elastic.IndicesGetResponse{}, nil))
+				client.On("Alias").Return(failingAlias())
+			},
+			run: func(manager *IndexManager) error {
+				return manager.Init("", "")
+			},
+			expectedError: "failed to add indices to alias jaeger-span-read: boom",
+		},
+		{
+			name: "list indices after rollover",
+			setup: func(client *mocks.Client) {
+				rollover := &mocks.IndicesRolloverService{}
+				rollover.On("Do", mock.Anything).Return(&elastic.IndicesRolloverResponse{RolledOver: true}, nil)
+				client.On("RolloverIndex", mock.Anything).Return(rollover)
+				client.On("IndexGet", mock.Anything).Return(failingGet(nil, boom))
+			},
+			run: func(manager *IndexManager) error {
+				return manager.Rollover(RolloverConditions{})
+			},
+			expectedError: "failed to get indices jaeger-span-*: boom",
+		},
+		{
+			name: "remove from alias",
+			setup: func(client *mocks.Client) {
+				client.On("IndexGet", mock.Anything).Return(failingGet(readIndices, nil))
+				client.On("Alias").Return(failingAlias())
+			},
+			run: func(manager *IndexManager) error {
+				return manager.Lookback("days", 1)
+			},
+			expectedError: "failed to remove indices from alias jaeger-span-read: boom",
+		},
+		{
+			name: "delete indices",
+			setup: func(client *mocks.Client) {
+				client.On("IndexGet", mock.Anything).Return(failingGet(readIndices, nil))
+				deleteService := &mocks.IndicesDeleteService{}
+				deleteService.On("Do", mock.Anything).Return(nil, boom)
+				client.On("DeleteIndex", mock.Anything).Return(deleteService)
+			},
+			run: func(manager *IndexManager) error {
+				return manager.Clean(1)
+			},
+			expectedError: "failed to delete indices: boom",
+		},
+		{
+			name: "no index settings",
+			setup: func(client *mocks.Client) {
+				client.On("IndexGet", mock.Anything).Return(failingGet(map[string]*elastic.IndicesGetResponse{
+					"jaeger-span-000001": {},
+				}, nil))
+			},
+			run: func(manager *IndexManager) error {
+				return manager.Lookback("days", 1)
+			},
+			expectedError: "invalid settings of index jaeger-span-000001: no index settings",
+		},
+		{
+			name: "no creation date",
+			setup: func(client *mocks.Client) {
+				client.On("IndexGet", mock.Anything).Return(failingGet(map[string]*elastic.IndicesGetResponse{
+					"jaeger-span-000001": {Settings: map[string]interface{}{"index": map[string]interface{}{}}},
+				}, nil))
+			},
+			run: func(manager *IndexManager) error {
+				return manager.Lookback("days", 1)
+			},
+			expectedError: "invalid settings of index jaeger-span-000001: no creation date",
+		},
+		{
+			name: "invalid creation date",
+			setup: func(client *mocks.Client) {
+				client.On("IndexGet", mock.Anything).Return(failingGet(map[string]*elastic.IndicesGetResponse{
+					"jaeger-span-000001": {Settings: map[string]interface{}{"index": map[string]interface{}{"creation_date": "yesterday"}}},
+				}, nil))
+			},
+			run: func(manager *IndexManager) error {
+				return manager.Lookback("days", 1)
+			},
+			expectedError: `invalid settings of index jaeger-span-000001: invalid creation date "yesterday"`,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			client := &mocks.Client{}
+			testCase.setup(client)
+			manager := newTestIndexManager(client, "", false, true)
+			assert.EqualError(t, testCase.run(manager), testCase.expectedError)
+		})
+	}
+}
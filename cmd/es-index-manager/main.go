@@ -0,0 +1,136 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/es-index-manager/app"
+	"github.com/jaegertracing/jaeger/cmd/flags"
+	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/retention"
+	"github.com/jaegertracing/jaeger/pkg/version"
+	"github.com/jaegertracing/jaeger/plugin/storage/es"
+)
+
+func main() {
+	command := &cobra.Command{
+		Use:   "jaeger-es-index-manager",
+		Short: "Jaeger es-index-manager manages the Jaeger indices in Elasticsearch.",
+		Long: `Jaeger es-index-manager creates and rolls over the indices used with --es.use-aliases, ` +
+			`and removes old indices from the read aliases or deletes them.`,
+	}
+
+	command.AddCommand(indexCommand(
+		"init",
+		"Creates the index templates, the first indices and their read and write aliases",
+		cobra.NoArgs,
+		func(manager *app.IndexManager, esConfig *es.Options, options *app.Options, args []string) error {
+			primary := esConfig.GetPrimary()
+			spanMapping, serviceMapping := es.GetMappings(primary.NumShards, primary.NumReplicas)
+			return manager.Init(spanMapping, serviceMapping)
+		},
+	))
+	command.AddCommand(indexCommand(
+		"rollover",
+		"Rolls the write aliases over to new indices if the conditions are met",
+		cobra.NoArgs,
+		func(manager *app.IndexManager, esConfig *es.Options, options *app.Options, args []string) error {
+			return manager.Rollover(options.Rollover)
+		},
+		app.AddRolloverFlags,
+	))
+	command.AddCommand(indexCommand(
+		"lookback",
+		"Removes the old indices from the read aliases",
+		cobra.NoArgs,
+		func(manager *app.IndexManager, esConfig *es.Options, options *app.Options, args []string) error {
+			return manager.Lookback(options.LookbackUnit, options.LookbackUnitCount)
+		},
+		app.AddLookbackFlags,
+	))
+	command.AddCommand(indexCommand(
+		"clean NUM_OF_DAYS",
+		"Deletes the indices older than the given number of days, or than the TTL of their --es.retention-policy-file rule",
+		cobra.ExactArgs(1),
+		func(manager *app.IndexManager, esConfig *es.Options, options *app.Options, args []string) error {
+			days, err := strconv.Atoi(args[0])
+			if err != nil || days < 0 {
+				return fmt.Errorf("invalid number of days %q", args[0])
+			}
+			return manager.Clean(days)
+		},
+	))
+	command.AddCommand(version.Command())
+
+	if err := command.Execute(); err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+type indexAction func(manager *app.IndexManager, esConfig *es.Options, options *app.Options, args []string) error
+
+// indexCommand creates a command running the action with an IndexManager. Each command uses its own viper
+// instance since the commands share the Elasticsearch flags.
+func indexCommand(use, short string, positionalArgs cobra.PositionalArgs, action indexAction, inits ...func(*flag.FlagSet)) *cobra.Command {
+	v := viper.New()
+	esConfig := es.NewOptions("es")
+	command := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  positionalArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger, err := new(flags.SharedFlags).InitFromViper(v).NewLogger(zap.NewProductionConfig())
+			if err != nil {
+				return err
+			}
+			esConfig.InitFromViper(v)
+			options := new(app.Options).InitFromViper(v)
+
+			primary := esConfig.GetPrimary()
+			client, err := primary.NewClient(logger, metrics.NullFactory)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			var retentionPolicy *retention.Policy
+			if !options.Archive {
+				if retentionPolicy, err = retention.LoadPolicyFile(primary.RetentionPolicyFile); err != nil {
+					return err
+				}
+			}
+
+			manager := app.NewIndexManager(client, logger, primary.IndexPrefix, options.Archive, primary.UseReadWriteAliases, retentionPolicy)
+			return action(manager, esConfig, options, args)
+		},
+	}
+
+	config.AddFlags(
+		v,
+		command,
+		append([]func(*flag.FlagSet){flags.AddLoggingFlag, esConfig.AddFlags, app.AddFlags}, inits...)...,
+	)
+	return command
+}
@@ -15,40 +15,30 @@
 package builder
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/Shopify/sarama"
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/cmd/ingester/app"
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/consumer"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/deadletter"
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor"
 	kafkaConsumer "github.com/jaegertracing/jaeger/pkg/kafka/consumer"
 	"github.com/jaegertracing/jaeger/plugin/storage/kafka"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
-// CreateConsumer creates a new span consumer for the ingester
-func CreateConsumer(logger *zap.Logger, metricsFactory metrics.Factory, spanWriter spanstore.Writer, options app.Options) (*consumer.Consumer, error) {
-	var unmarshaller kafka.Unmarshaller
-	switch options.Encoding {
-	case kafka.EncodingJSON:
-		unmarshaller = kafka.NewJSONUnmarshaller()
-	case kafka.EncodingProto:
-		unmarshaller = kafka.NewProtobufUnmarshaller()
-	case kafka.EncodingZipkinThrift:
-		unmarshaller = kafka.NewZipkinThriftUnmarshaller()
-	default:
-		return nil, fmt.Errorf(`encoding '%s' not recognised, use one of ("%s")`,
-			options.Encoding, strings.Join(kafka.AllEncodings, "\", \""))
-	}
-
-	spParams := processor.SpanProcessorParams{
-		Writer:       spanWriter,
-		Unmarshaller: unmarshaller,
+// CreateConsumer creates a new span consumer for the ingester. The messages that cannot be ingested
+// are published to the optional dead-letter sink.
+func CreateConsumer(logger *zap.Logger, metricsFactory metrics.Factory, spanWriter spanstore.Writer, options app.Options, deadLetterSink deadletter.Sink) (*consumer.Consumer, error) {
+	spanProcessor, err := CreateSpanProcessor(spanWriter, options.Encoding)
+	if err != nil {
+		return nil, err
 	}
-	spanProcessor := processor.NewSpanProcessor(spParams)
 
 	consumerConfig := kafkaConsumer.Configuration{
 		Brokers:  options.Brokers,
@@ -68,6 +58,7 @@ func CreateConsumer(logger *zap.Logger, metricsFactory metrics.Factory, spanWrit
 		BaseProcessor:  spanProcessor,
 		Logger:         logger,
 		Factory:        metricsFactory,
+		DeadLetterSink: deadLetterSink,
 	}
 	processorFactory, err := consumer.NewProcessorFactory(factoryParams)
 	if err != nil {
@@ -83,3 +74,98 @@ func CreateConsumer(logger *zap.Logger, metricsFactory metrics.Factory, spanWrit
 	}
 	return consumer.New(consumerParams)
 }
+
+// CreateSpanProcessor creates the processor unmarshalling the spans with the given encoding and writing them to storage
+func CreateSpanProcessor(spanWriter spanstore.Writer, encoding string) (*processor.KafkaSpanProcessor, error) {
+	var unmarshaller kafka.Unmarshaller
+	switch encoding {
+	case kafka.EncodingJSON:
+		unmarshaller = kafka.NewJSONUnmarshaller()
+	case kafka.EncodingProto:
+		unmarshaller = kafka.NewProtobufUnmarshaller()
+	case kafka.EncodingZipkinThrift:
+		unmarshaller = kafka.NewZipkinThriftUnmarshaller()
+	default:
+		return nil, fmt.Errorf(`encoding '%s' not recognised, use one of ("%s")`,
+			encoding, strings.Join(kafka.AllEncodings, "\", \""))
+	}
+
+	spParams := processor.SpanProcessorParams{
+		Writer:       spanWriter,
+		Unmarshaller: unmarshaller,
+	}
+	return processor.NewSpanProcessor(spParams), nil
+}
+
+// CreateDeadLetterSink creates the sink publishing to the dead-letter topic or file configured in the options.
+// It returns nil if neither is configured.
+func CreateDeadLetterSink(options app.Options) (deadletter.Sink, error) {
+	if err := validateDeadLetterOptions(options); err != nil {
+		return nil, err
+	}
+	if options.DeadLetterTopic != "" {
+		producer, err := sarama.NewSyncProducer(options.DeadLetterBrokers, newDeadLetterConfig(options))
+		if err != nil {
+			return nil, err
+		}
+		return deadletter.NewKafkaSink(producer, options.DeadLetterTopic), nil
+	}
+	if options.DeadLetterFile != "" {
+		sink, err := deadletter.NewFileSink(options.DeadLetterFile)
+		if err != nil {
+			return nil, err
+		}
+		return sink, nil
+	}
+	return nil, nil
+}
+
+// CreateDeadLetterSource creates the source reading the dead-letter topic or file configured in the options.
+func CreateDeadLetterSource(options app.Options) (deadletter.Source, error) {
+	if err := validateDeadLetterOptions(options); err != nil {
+		return nil, err
+	}
+	if options.DeadLetterTopic != "" {
+		client, err := sarama.NewClient(options.DeadLetterBrokers, newDeadLetterConfig(options))
+		if err != nil {
+			return nil, err
+		}
+		saramaConsumer, err := sarama.NewConsumerFromClient(client)
+		if err != nil {
+			client.Close()
+			return nil, err
+		}
+		offsetManager, err := sarama.NewOffsetManagerFromClient(options.DeadLetterGroupID, client)
+		if err != nil {
+			saramaConsumer.Close()
+			client.Close()
+			return nil, err
+		}
+		return deadletter.NewKafkaSource(saramaConsumer, client, offsetManager, options.DeadLetterTopic), nil
+	}
+	if options.DeadLetterFile != "" {
+		source, err := deadletter.NewFileSource(options.DeadLetterFile)
+		if err != nil {
+			return nil, err
+		}
+		return source, nil
+	}
+	return nil, errors.New("neither a dead-letter topic nor a dead-letter file is configured")
+}
+
+func validateDeadLetterOptions(options app.Options) error {
+	if options.DeadLetterTopic != "" && options.DeadLetterFile != "" {
+		return errors.New("a dead-letter topic and a dead-letter file cannot be used together")
+	}
+	return nil
+}
+
+func newDeadLetterConfig(options app.Options) *sarama.Config {
+	config := sarama.NewConfig()
+	config.ClientID = options.ClientID
+	// message headers, which hold the error metadata, require Kafka 0.11
+	config.Version = sarama.V0_11_0_0
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Return.Successes = true
+	return config
+}
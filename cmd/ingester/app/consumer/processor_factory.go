@@ -21,6 +21,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/consumer/offset"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/deadletter"
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor"
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor/decorator"
 	"github.com/jaegertracing/jaeger/pkg/kafka/consumer"
@@ -34,6 +35,8 @@ type ProcessorFactoryParams struct {
 	SaramaConsumer consumer.Consumer
 	Factory        metrics.Factory
 	Logger         *zap.Logger
+	// DeadLetterSink receives the messages that cannot be ingested, they are dropped if nil
+	DeadLetterSink deadletter.Sink
 }
 
 // ProcessorFactory is a factory for creating startedProcessors
@@ -44,6 +47,7 @@ type ProcessorFactory struct {
 	logger         *zap.Logger
 	baseProcessor  processor.SpanProcessor
 	parallelism    int
+	deadLetterSink deadletter.Sink
 }
 
 // NewProcessorFactory constructs a new ProcessorFactory
@@ -55,6 +59,7 @@ func NewProcessorFactory(params ProcessorFactoryParams) (*ProcessorFactory, erro
 		logger:         params.Logger,
 		baseProcessor:  params.BaseProcessor,
 		parallelism:    params.Parallelism,
+		deadLetterSink: params.DeadLetterSink,
 	}, nil
 }
 
@@ -67,7 +72,14 @@ func (c *ProcessorFactory) new(partition int32, minOffset int64) processor.SpanP
 
 	om := offset.NewManager(minOffset, markOffset, partition, c.metricsFactory)
 
-	retryProcessor := decorator.NewRetryingProcessor(c.metricsFactory, c.baseProcessor)
+	var retryProcessor processor.SpanProcessor
+	if c.deadLetterSink != nil {
+		retryProcessor = decorator.NewRetryingProcessor(
+			c.metricsFactory, c.baseProcessor, decorator.PropagateError(true), decorator.SkipUnmarshalRetries(true))
+		retryProcessor = decorator.NewDeadLetterProcessor(c.metricsFactory, retryProcessor, c.deadLetterSink, c.logger)
+	} else {
+		retryProcessor = decorator.NewRetryingProcessor(c.metricsFactory, c.baseProcessor)
+	}
 	cp := NewCommittingProcessor(retryProcessor, om)
 	spanProcessor := processor.NewDecoratedProcessor(c.metricsFactory, cp)
 	pp := processor.NewParallelProcessor(spanProcessor, c.parallelism, c.logger)
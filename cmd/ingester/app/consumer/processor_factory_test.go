@@ -15,16 +15,21 @@
 package consumer
 
 import (
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
 	kmocks "github.com/jaegertracing/jaeger/cmd/ingester/app/consumer/mocks"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/deadletter"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor"
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor/mocks"
+	umocks "github.com/jaegertracing/jaeger/pkg/kafka/mocks"
 )
 
 func Test_NewFactory(t *testing.T) {
@@ -66,6 +71,59 @@ func Test_new(t *testing.T) {
 	mockConsumer.AssertCalled(t, "MarkPartitionOffset", topic, partition, offset+1, "")
 }
 
+type fakeSink struct {
+	letters []deadletter.Letter
+}
+
+func (s *fakeSink) Publish(letter deadletter.Letter) error {
+	s.letters = append(s.letters, letter)
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	return nil
+}
+
+func Test_newWithDeadLetterSink(t *testing.T) {
+	mockConsumer := &kmocks.Consumer{}
+	mockConsumer.On("MarkPartitionOffset", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	topic := "coelacanth"
+	partition := int32(21)
+	offset := int64(555)
+
+	unmarshaller := &umocks.Unmarshaller{}
+	unmarshaller.On("Unmarshal", []byte("invalid")).Return(nil, errors.New("invalid span"))
+	sink := &fakeSink{}
+
+	pf := ProcessorFactory{
+		topic:          topic,
+		consumer:       mockConsumer,
+		metricsFactory: metrics.NullFactory,
+		logger:         zap.NewNop(),
+		baseProcessor:  processor.NewSpanProcessor(processor.SpanProcessorParams{Unmarshaller: unmarshaller}),
+		parallelism:    1,
+		deadLetterSink: sink,
+	}
+
+	sp := pf.new(partition, offset)
+	msg := &kmocks.Message{}
+	msg.On("Key").Return([]byte(nil))
+	msg.On("Value").Return([]byte("invalid"))
+	msg.On("Topic").Return(topic)
+	msg.On("Partition").Return(partition)
+	msg.On("Offset").Return(offset + 1)
+	sp.Process(msg)
+
+	// the offset of the message is committed once it is in the dead-letter sink
+	time.Sleep(150 * time.Millisecond)
+	mockConsumer.AssertCalled(t, "MarkPartitionOffset", topic, partition, offset+1, "")
+	require.NoError(t, sp.Close())
+	require.Len(t, sink.letters, 1)
+	assert.Equal(t, deadletter.ReasonUnmarshal, sink.letters[0].Reason)
+	assert.Equal(t, offset+1, sink.letters[0].Offset)
+}
+
 type fakeService struct {
 	startCalled bool
 	closeCalled bool
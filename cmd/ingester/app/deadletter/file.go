@@ -0,0 +1,99 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deadletter
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// FileSink appends the dead letters to a local file, one JSON object per line.
+type FileSink struct {
+	mux  sync.Mutex
+	file *os.File
+}
+
+// NewFileSink creates a FileSink appending to the file, which is created if it does not exist.
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open the dead-letter file")
+	}
+	return &FileSink{file: file}, nil
+}
+
+// Publish implements Sink
+func (s *FileSink) Publish(letter Letter) error {
+	line, err := json.Marshal(letter)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	// a single write per line, so that a crash never leaves a partial letter in the middle of the file
+	_, err = s.file.Write(line)
+	return err
+}
+
+// Close implements io.Closer
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+// FileSource reads the dead letters of a file written by FileSink.
+type FileSource struct {
+	file *os.File
+}
+
+// NewFileSource creates a FileSource reading the file.
+func NewFileSource(path string) (*FileSource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open the dead-letter file")
+	}
+	return &FileSource{file: file}, nil
+}
+
+// Read implements Source
+func (s *FileSource) Read(handle func(letter Letter) error) error {
+	reader := bufio.NewReader(s.file)
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF && len(line) == 0 {
+			return nil
+		}
+		if err != nil && err != io.EOF {
+			return errors.Wrap(err, "failed to read the dead-letter file")
+		}
+		var letter Letter
+		if err := json.Unmarshal(line, &letter); err != nil {
+			return errors.Wrapf(err, "invalid dead letter at line %d", lineNumber)
+		}
+		if err := handle(letter); err != nil {
+			return err
+		}
+	}
+}
+
+// Close implements io.Closer
+func (s *FileSource) Close() error {
+	return s.file.Close()
+}
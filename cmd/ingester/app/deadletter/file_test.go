@@ -0,0 +1,118 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deadletter
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var testLetter = Letter{
+	Key:       []byte("key"),
+	Value:     []byte("value"),
+	Topic:     "jaeger-spans",
+	Partition: 3,
+	Offset:    42,
+	Reason:    ReasonWrite,
+	Error:     "storage unavailable",
+	Time:      time.Date(2019, 5, 1, 12, 0, 0, 1000, time.UTC),
+}
+
+func TestFileSinkAndSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dlq")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "dlq.json")
+
+	sink, err := NewFileSink(path)
+	require.NoError(t, err)
+	require.NoError(t, sink.Publish(testLetter))
+	require.NoError(t, sink.Close())
+	// the letters are appended to an existing file
+	sink, err = NewFileSink(path)
+	require.NoError(t, err)
+	require.NoError(t, sink.Publish(Letter{Value: []byte{0, 1, 2}, Reason: ReasonUnmarshal}))
+	require.NoError(t, sink.Close())
+
+	source, err := NewFileSource(path)
+	require.NoError(t, err)
+	defer source.Close()
+	var letters []Letter
+	require.NoError(t, source.Read(func(letter Letter) error {
+		letters = append(letters, letter)
+		return nil
+	}))
+	assert.Equal(t, []Letter{testLetter, {Value: []byte{0, 1, 2}, Reason: ReasonUnmarshal}}, letters)
+}
+
+func TestFileSourceErrors(t *testing.T) {
+	_, err := NewFileSink("/does/not/exist/dlq.json")
+	assert.EqualError(t, err, "failed to open the dead-letter file: open /does/not/exist/dlq.json: no such file or directory")
+	_, err = NewFileSource("/does/not/exist/dlq.json")
+	assert.EqualError(t, err, "failed to open the dead-letter file: open /does/not/exist/dlq.json: no such file or directory")
+
+	dir, err := ioutil.TempDir("", "dlq")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "dlq.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte("{\"value\": \"AA==\"}\n{\"value\": "), 0600))
+
+	source, err := NewFileSource(path)
+	require.NoError(t, err)
+	defer source.Close()
+	count := 0
+	err = source.Read(func(letter Letter) error {
+		count++
+		return nil
+	})
+	assert.EqualError(t, err, "invalid dead letter at line 2: unexpected end of JSON input")
+	assert.Equal(t, 1, count)
+}
+
+func TestReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dlq")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "dlq.json")
+	sink, err := NewFileSink(path)
+	require.NoError(t, err)
+	for _, value := range []string{"a", "b", "c"} {
+		require.NoError(t, sink.Publish(Letter{Value: []byte(value)}))
+	}
+	require.NoError(t, sink.Close())
+
+	source, err := NewFileSource(path)
+	require.NoError(t, err)
+	defer source.Close()
+	var ingested []string
+	result, err := Replay(source, func(letter Letter) error {
+		if string(letter.Value) == "b" {
+			return errors.New("write error")
+		}
+		ingested = append(ingested, string(letter.Value))
+		return nil
+	}, zap.NewNop())
+	assert.EqualError(t, err, "write error")
+	assert.Equal(t, ReplayResult{Replayed: 1}, result)
+	assert.Equal(t, []string{"a"}, ingested)
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deadletter
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/pkg/errors"
+)
+
+// Names of the Kafka headers holding the metadata of the dead letters.
+const (
+	HeaderReason    = "jaeger-dlq-reason"
+	HeaderError     = "jaeger-dlq-error"
+	HeaderTopic     = "jaeger-dlq-topic"
+	HeaderPartition = "jaeger-dlq-partition"
+	HeaderOffset    = "jaeger-dlq-offset"
+	HeaderTime      = "jaeger-dlq-time"
+)
+
+// KafkaSink publishes the dead letters to a Kafka topic, with the error metadata in the message headers.
+type KafkaSink struct {
+	producer sarama.SyncProducer
+	topic    string
+}
+
+// NewKafkaSink creates a KafkaSink publishing with the given producer, which is closed along with the sink.
+// Message headers require the producer to use Kafka version 0.11 or above.
+func NewKafkaSink(producer sarama.SyncProducer, topic string) *KafkaSink {
+	return &KafkaSink{producer: producer, topic: topic}
+}
+
+// Publish implements Sink
+func (s *KafkaSink) Publish(letter Letter) error {
+	msg := &sarama.ProducerMessage{
+		Topic:   s.topic,
+		Value:   sarama.ByteEncoder(letter.Value),
+		Headers: letter.headers(),
+	}
+	if letter.Key != nil {
+		msg.Key = sarama.ByteEncoder(letter.Key)
+	}
+	_, _, err := s.producer.SendMessage(msg)
+	return err
+}
+
+// Close implements io.Closer
+func (s *KafkaSink) Close() error {
+	return s.producer.Close()
+}
+
+func (l Letter) headers() []sarama.RecordHeader {
+	return []sarama.RecordHeader{
+		{Key: []byte(HeaderReason), Value: []byte(l.Reason)},
+		{Key: []byte(HeaderError), Value: []byte(l.Error)},
+		{Key: []byte(HeaderTopic), Value: []byte(l.Topic)},
+		{Key: []byte(HeaderPartition), Value: []byte(strconv.FormatInt(int64(l.Partition), 10))},
+		{Key: []byte(HeaderOffset), Value: []byte(strconv.FormatInt(l.Offset, 10))},
+		{Key: []byte(HeaderTime), Value: []byte(l.Time.Format(time.RFC3339Nano))},
+	}
+}
+
+// letterFromMessage restores a dead letter from a message of the dead-letter topic.
+// The metadata missing from the headers are left empty.
+func letterFromMessage(msg *sarama.ConsumerMessage) Letter {
+	letter := Letter{Key: msg.Key, Value: msg.Value}
+	for _, header := range msg.Headers {
+		value := string(header.Value)
+		switch string(header.Key) {
+		case HeaderReason:
+			letter.Reason = Reason(value)
+		case HeaderError:
+			letter.Error = value
+		case HeaderTopic:
+			letter.Topic = value
+		case HeaderPartition:
+			partition, _ := strconv.ParseInt(value, 10, 32)
+			letter.Partition = int32(partition)
+		case HeaderOffset:
+			letter.Offset, _ = strconv.ParseInt(value, 10, 64)
+		case HeaderTime:
+			letter.Time, _ = time.Parse(time.RFC3339Nano, value)
+		}
+	}
+	return letter
+}
+
+// offsetGetter is the part of sarama.Client used to find the range of offsets to replay.
+type offsetGetter interface {
+	GetOffset(topic string, partition int32, time int64) (int64, error)
+}
+
+// offsetManager is the part of sarama.OffsetManager used to record the progress of the replays.
+type offsetManager interface {
+	ManagePartition(topic string, partition int32) (sarama.PartitionOffsetManager, error)
+	Close() error
+}
+
+// KafkaSource reads the dead letters of a Kafka topic up to the newest message at the time Read is called.
+// It commits the offsets of the letters it has read for a consumer group, so that the next Read resumes
+// after them instead of reading the whole topic again.
+type KafkaSource struct {
+	consumer      sarama.Consumer
+	offsets       offsetGetter
+	offsetManager offsetManager
+	topic         string
+}
+
+// NewKafkaSource creates a KafkaSource consuming the dead-letter topic with the given consumer, and recording
+// its progress with the given offset manager. The partitions without committed offsets are read from the
+// oldest message. The consumer and the offset manager are closed along with the source, as well as offsets
+// if it is an io.Closer, e.g. a sarama.Client.
+func NewKafkaSource(consumer sarama.Consumer, offsets offsetGetter, offsetManager offsetManager, topic string) *KafkaSource {
+	return &KafkaSource{
+		consumer:      consumer,
+		offsets:       offsets,
+		offsetManager: offsetManager,
+		topic:         topic,
+	}
+}
+
+// Read implements Source
+func (s *KafkaSource) Read(handle func(letter Letter) error) error {
+	partitions, err := s.consumer.Partitions(s.topic)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the partitions of the dead-letter topic")
+	}
+	for _, partition := range partitions {
+		if err := s.readPartition(partition, handle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *KafkaSource) readPartition(partition int32, handle func(letter Letter) error) error {
+	pom, err := s.offsetManager.ManagePartition(s.topic, partition)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the committed offset of partition %d", partition)
+	}
+	err = s.replayPartition(partition, pom, handle)
+	// closing waits for the offsets marked so far to be committed
+	if e := pom.Close(); e != nil && err == nil {
+		err = errors.Wrapf(e, "failed to commit the offset of partition %d", partition)
+	}
+	return err
+}
+
+func (s *KafkaSource) replayPartition(partition int32, pom sarama.PartitionOffsetManager, handle func(letter Letter) error) error {
+	oldest, err := s.offsets.GetOffset(s.topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the oldest offset of partition %d", partition)
+	}
+	// the newest offset is the offset of the next message to be published
+	newest, err := s.offsets.GetOffset(s.topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the newest offset of partition %d", partition)
+	}
+	// the committed offset is negative if none was committed yet, and the messages it points to
+	// may have been deleted since
+	next, _ := pom.NextOffset()
+	if next < oldest {
+		next = oldest
+	}
+	if next >= newest {
+		return nil
+	}
+	pc, err := s.consumer.ConsumePartition(s.topic, partition, next)
+	if err != nil {
+		return errors.Wrapf(err, "failed to consume partition %d", partition)
+	}
+	defer pc.Close()
+	for msg := range pc.Messages() {
+		if err := handle(letterFromMessage(msg)); err != nil {
+			return err
+		}
+		pom.MarkOffset(msg.Offset+1, "")
+		if msg.Offset >= newest-1 {
+			return nil
+		}
+	}
+	return fmt.Errorf("partition %d closed before reaching offset %d", partition, newest-1)
+}
+
+// Close implements io.Closer
+func (s *KafkaSource) Close() error {
+	err := s.consumer.Close()
+	// the offset manager must be closed before the client it uses
+	if e := s.offsetManager.Close(); e != nil && err == nil {
+		err = e
+	}
+	if closer, ok := s.offsets.(io.Closer); ok {
+		if e := closer.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
@@ -0,0 +1,249 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deadletter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// capturingProducer records the messages sent through the mock producer.
+type capturingProducer struct {
+	*mocks.SyncProducer
+	messages []*sarama.ProducerMessage
+}
+
+func (p *capturingProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	p.messages = append(p.messages, msg)
+	return p.SyncProducer.SendMessage(msg)
+}
+
+func TestKafkaSink(t *testing.T) {
+	producer := &capturingProducer{SyncProducer: mocks.NewSyncProducer(t, nil)}
+	sink := NewKafkaSink(producer, "jaeger-spans-dlq")
+
+	producer.ExpectSendMessageAndSucceed()
+	require.NoError(t, sink.Publish(testLetter))
+	require.Len(t, producer.messages, 1)
+	msg := producer.messages[0]
+	assert.Equal(t, "jaeger-spans-dlq", msg.Topic)
+	assert.Equal(t, sarama.ByteEncoder("key"), msg.Key)
+	assert.Equal(t, sarama.ByteEncoder("value"), msg.Value)
+	headers := make(map[string]string)
+	for _, header := range msg.Headers {
+		headers[string(header.Key)] = string(header.Value)
+	}
+	assert.Equal(t, map[string]string{
+		HeaderReason:    "write",
+		HeaderError:     "storage unavailable",
+		HeaderTopic:     "jaeger-spans",
+		HeaderPartition: "3",
+		HeaderOffset:    "42",
+		HeaderTime:      "2019-05-01T12:00:00.000001Z",
+	}, headers)
+
+	producer.ExpectSendMessageAndFail(errors.New("broker down"))
+	assert.EqualError(t, sink.Publish(Letter{}), "broker down")
+	assert.Nil(t, producer.messages[1].Key)
+
+	assert.NoError(t, sink.Close())
+}
+
+type fakeOffsets map[int32][2]int64
+
+func (o fakeOffsets) GetOffset(topic string, partition int32, time int64) (int64, error) {
+	if time == sarama.OffsetOldest {
+		return o[partition][0], nil
+	}
+	return o[partition][1], nil
+}
+
+// fakeOffsetManager records the offsets committed for each partition in memory.
+type fakeOffsetManager struct {
+	committed map[int32]int64
+	err       error
+	closed    bool
+}
+
+func newFakeOffsetManager() *fakeOffsetManager {
+	return &fakeOffsetManager{committed: make(map[int32]int64)}
+}
+
+func (m *fakeOffsetManager) ManagePartition(topic string, partition int32) (sarama.PartitionOffsetManager, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	next, ok := m.committed[partition]
+	if !ok {
+		next = sarama.OffsetOldest
+	}
+	return &fakePartitionOffsetManager{manager: m, partition: partition, next: next}, nil
+}
+
+func (m *fakeOffsetManager) Close() error {
+	m.closed = true
+	return nil
+}
+
+type fakePartitionOffsetManager struct {
+	sarama.PartitionOffsetManager
+	manager   *fakeOffsetManager
+	partition int32
+	next      int64
+}
+
+func (pom *fakePartitionOffsetManager) NextOffset() (int64, string) {
+	return pom.next, ""
+}
+
+func (pom *fakePartitionOffsetManager) MarkOffset(offset int64, metadata string) {
+	pom.next = offset
+}
+
+// Close commits the marked offset
+func (pom *fakePartitionOffsetManager) Close() error {
+	if pom.next >= 0 {
+		pom.manager.committed[pom.partition] = pom.next
+	}
+	return nil
+}
+
+func TestKafkaSource(t *testing.T) {
+	consumer := mocks.NewConsumer(t, nil)
+	consumer.SetTopicMetadata(map[string][]int32{"jaeger-spans-dlq": {0, 1}})
+	pc := consumer.ExpectConsumePartition("jaeger-spans-dlq", 0, 1)
+	producer := &capturingProducer{SyncProducer: mocks.NewSyncProducer(t, nil)}
+	producer.ExpectSendMessageAndSucceed()
+	require.NoError(t, NewKafkaSink(producer, "jaeger-spans-dlq").Publish(testLetter))
+	headers := producer.messages[0].Headers
+	pc.YieldMessage(&sarama.ConsumerMessage{Key: []byte("key"), Value: []byte("value"), Headers: toPointers(headers)})
+	pc.YieldMessage(&sarama.ConsumerMessage{Value: []byte("no headers")})
+	// this message was published after the replay started
+	pc.YieldMessage(&sarama.ConsumerMessage{Value: []byte("too late")})
+
+	// the mock yields offsets from 1, partition 1 is empty
+	offsetManager := newFakeOffsetManager()
+	source := NewKafkaSource(consumer, fakeOffsets{0: {1, 3}, 1: {5, 5}}, offsetManager, "jaeger-spans-dlq")
+	var letters []Letter
+	require.NoError(t, source.Read(func(letter Letter) error {
+		letters = append(letters, letter)
+		return nil
+	}))
+	assert.Equal(t, []Letter{testLetter, {Value: []byte("no headers")}}, letters)
+	assert.Equal(t, map[int32]int64{0: 3}, offsetManager.committed)
+
+	// the letters already replayed are not read again
+	letters = nil
+	require.NoError(t, source.Read(func(letter Letter) error {
+		letters = append(letters, letter)
+		return nil
+	}))
+	assert.Empty(t, letters)
+
+	assert.NoError(t, source.Close())
+	assert.True(t, offsetManager.closed)
+}
+
+func TestKafkaSourceResume(t *testing.T) {
+	consumer := mocks.NewConsumer(t, nil)
+	consumer.SetTopicMetadata(map[string][]int32{"jaeger-spans-dlq": {0, 1}})
+	// partition 0 resumes from the committed offset rather than the oldest one
+	pc := consumer.ExpectConsumePartition("jaeger-spans-dlq", 0, 1)
+	pc.YieldMessage(&sarama.ConsumerMessage{Value: []byte("new")})
+
+	offsetManager := newFakeOffsetManager()
+	offsetManager.committed[0] = 1
+	// the letters of partition 1 up to its committed offset and beyond were deleted
+	offsetManager.committed[1] = 2
+	source := NewKafkaSource(consumer, fakeOffsets{0: {0, 2}, 1: {4, 4}}, offsetManager, "jaeger-spans-dlq")
+	var letters []Letter
+	require.NoError(t, source.Read(func(letter Letter) error {
+		letters = append(letters, letter)
+		return nil
+	}))
+	assert.Equal(t, []Letter{{Value: []byte("new")}}, letters)
+	assert.Equal(t, map[int32]int64{0: 2, 1: 2}, offsetManager.committed)
+}
+
+func TestReplayKafkaSource(t *testing.T) {
+	consumer := mocks.NewConsumer(t, nil)
+	consumer.SetTopicMetadata(map[string][]int32{"jaeger-spans-dlq": {0}})
+	pc := consumer.ExpectConsumePartition("jaeger-spans-dlq", 0, 1)
+	for _, value := range []string{"a", "b", "c"} {
+		pc.YieldMessage(&sarama.ConsumerMessage{Value: []byte(value)})
+	}
+
+	offsetManager := newFakeOffsetManager()
+	source := NewKafkaSource(consumer, fakeOffsets{0: {1, 4}}, offsetManager, "jaeger-spans-dlq")
+	result, err := Replay(source, func(letter Letter) error {
+		if string(letter.Value) == "b" {
+			return errors.New("write error")
+		}
+		return nil
+	}, zap.NewNop())
+	assert.EqualError(t, err, "write error")
+	assert.Equal(t, ReplayResult{Replayed: 1}, result)
+	// the failed letter at offset 2 is replayed again by the next replay
+	assert.Equal(t, map[int32]int64{0: 2}, offsetManager.committed)
+}
+
+func TestKafkaSourceErrors(t *testing.T) {
+	consumer := mocks.NewConsumer(t, nil)
+	consumer.SetTopicMetadata(map[string][]int32{"jaeger-spans-dlq": {0}})
+	pc := consumer.ExpectConsumePartition("jaeger-spans-dlq", 0, 1)
+	pc.YieldMessage(&sarama.ConsumerMessage{Value: []byte("value")})
+	offsetManager := newFakeOffsetManager()
+	source := NewKafkaSource(consumer, fakeOffsets{0: {1, 3}}, offsetManager, "jaeger-spans-dlq")
+
+	assert.EqualError(t, source.Read(func(letter Letter) error {
+		return errors.New("handler error")
+	}), "handler error")
+	// the letter that failed to be handled is read again by the next replay
+	assert.Empty(t, offsetManager.committed)
+
+	offsetManager.err = errors.New("no coordinator")
+	assert.EqualError(t, source.Read(nil), "failed to get the committed offset of partition 0: no coordinator")
+
+	source = NewKafkaSource(consumer, fakeOffsets{}, offsetManager, "unknown")
+	assert.EqualError(t, source.Read(nil), "failed to list the partitions of the dead-letter topic: kafka server: Request was for a topic or partition that does not exist on this broker.")
+}
+
+func TestKafkaSourcePartitionClosed(t *testing.T) {
+	consumer := mocks.NewConsumer(t, nil)
+	consumer.SetTopicMetadata(map[string][]int32{"jaeger-spans-dlq": {0}})
+	pc := consumer.ExpectConsumePartition("jaeger-spans-dlq", 0, 1)
+	pc.YieldMessage(&sarama.ConsumerMessage{Value: []byte("value")})
+	source := NewKafkaSource(consumer, fakeOffsets{0: {1, 3}}, newFakeOffsetManager(), "jaeger-spans-dlq")
+
+	err := source.Read(func(letter Letter) error {
+		pc.AsyncClose()
+		return nil
+	})
+	assert.EqualError(t, err, "partition 0 closed before reaching offset 2")
+}
+
+func toPointers(headers []sarama.RecordHeader) []*sarama.RecordHeader {
+	pointers := make([]*sarama.RecordHeader, len(headers))
+	for i := range headers {
+		pointers[i] = &headers[i]
+	}
+	return pointers
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deadletter
+
+import (
+	"io"
+	"time"
+)
+
+// Reason tells why a message was sent to the dead-letter queue.
+type Reason string
+
+const (
+	// ReasonUnmarshal is the reason of the messages that could not be unmarshalled into a span
+	ReasonUnmarshal Reason = "unmarshal"
+	// ReasonWrite is the reason of the messages whose span could not be written to storage after all the retries
+	ReasonWrite Reason = "write"
+)
+
+// Letter is a Kafka message that could not be ingested, along with the error metadata.
+type Letter struct {
+	Key   []byte `json:"key,omitempty"`
+	Value []byte `json:"value"`
+	// Topic, Partition and Offset locate the original message
+	Topic     string    `json:"topic"`
+	Partition int32     `json:"partition"`
+	Offset    int64     `json:"offset"`
+	Reason    Reason    `json:"reason"`
+	Error     string    `json:"error"`
+	Time      time.Time `json:"time"`
+}
+
+// Sink publishes dead letters.
+type Sink interface {
+	Publish(letter Letter) error
+	io.Closer
+}
+
+// Source reads the dead letters published by a Sink.
+type Source interface {
+	// Read calls handle for every dead letter available when Read is called, stopping at the first error
+	// returned by handle.
+	Read(handle func(letter Letter) error) error
+	io.Closer
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deadletter
+
+import (
+	"go.uber.org/zap"
+)
+
+// ReplayResult counts the dead letters processed by Replay.
+type ReplayResult struct {
+	Replayed int
+}
+
+// Replay calls ingest for every dead letter of the source. It stops at the first letter that fails to be
+// ingested and returns the error, so that sources recording their progress, like KafkaSource, do not record
+// it and the next replay resumes from that letter. The letters processed so far are counted in both cases.
+func Replay(source Source, ingest func(letter Letter) error, logger *zap.Logger) (ReplayResult, error) {
+	var result ReplayResult
+	err := source.Read(func(letter Letter) error {
+		if err := ingest(letter); err != nil {
+			logger.Error("Failed to replay dead letter",
+				zap.String("topic", letter.Topic),
+				zap.Int32("partition", letter.Partition),
+				zap.Int64("offset", letter.Offset),
+				zap.String("reason", string(letter.Reason)),
+				zap.Error(err))
+			return err
+		}
+		result.Replayed++
+		return nil
+	})
+	return result, err
+}
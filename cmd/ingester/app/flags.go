@@ -48,6 +48,14 @@ const (
 	SuffixParallelism = ".parallelism"
 	// SuffixHTTPPort is a suffix for the HTTP port
 	SuffixHTTPPort = ".http-port"
+	// SuffixDeadLetterTopic is a suffix for the dead-letter topic flag
+	SuffixDeadLetterTopic = ".dead-letter.topic"
+	// SuffixDeadLetterBrokers is a suffix for the dead-letter brokers flag
+	SuffixDeadLetterBrokers = ".dead-letter.brokers"
+	// SuffixDeadLetterFile is a suffix for the dead-letter file flag
+	SuffixDeadLetterFile = ".dead-letter.file"
+	// SuffixDeadLetterGroupID is a suffix for the dead-letter replay consumer group flag
+	SuffixDeadLetterGroupID = ".dead-letter.group-id"
 
 	// DefaultBroker is the default kafka broker
 	DefaultBroker = "127.0.0.1:9092"
@@ -63,6 +71,8 @@ const (
 	DefaultEncoding = kafka.EncodingProto
 	// DefaultDeadlockInterval is the default deadlock interval
 	DefaultDeadlockInterval = 1 * time.Minute
+	// DefaultDeadLetterGroupID is the default consumer Group ID of the dead-letter replays
+	DefaultDeadLetterGroupID = "jaeger-ingester-dead-letter-replay"
 )
 
 // Options stores the configuration options for the Ingester
@@ -71,6 +81,14 @@ type Options struct {
 	Parallelism      int
 	Encoding         string
 	DeadlockInterval time.Duration
+	// DeadLetterTopic is the Kafka topic receiving the messages that cannot be ingested
+	DeadLetterTopic string
+	// DeadLetterBrokers are the brokers of the dead-letter topic, the consumer brokers by default
+	DeadLetterBrokers []string
+	// DeadLetterFile is the local file receiving the messages that cannot be ingested
+	DeadLetterFile string
+	// DeadLetterGroupID is the consumer group recording the progress of the replays of the dead-letter topic
+	DeadLetterGroupID string
 }
 
 // AddFlags adds flags for Builder
@@ -103,6 +121,22 @@ func AddFlags(flagSet *flag.FlagSet) {
 		ConfigPrefix+SuffixDeadlockInterval,
 		DefaultDeadlockInterval,
 		"Interval to check for deadlocks. If no messages gets processed in given time, ingester app will exit. Value of 0 disables deadlock check.")
+	flagSet.String(
+		ConfigPrefix+SuffixDeadLetterTopic,
+		"",
+		"The name of the kafka topic receiving the messages that cannot be unmarshalled or written to storage after all the retries. They are dropped if neither a dead-letter topic nor a dead-letter file is set")
+	flagSet.String(
+		ConfigPrefix+SuffixDeadLetterBrokers,
+		"",
+		"The comma-separated list of kafka brokers of the dead-letter topic. The consumer brokers are used if empty")
+	flagSet.String(
+		ConfigPrefix+SuffixDeadLetterFile,
+		"",
+		"The path for the local file receiving the messages that cannot be unmarshalled or written to storage after all the retries, as JSON lines. Cannot be used along with a dead-letter topic")
+	flagSet.String(
+		ConfigPrefix+SuffixDeadLetterGroupID,
+		DefaultDeadLetterGroupID,
+		"The consumer group committing the offsets of the dead-letter topic replayed by replay-dlq, so that each replay resumes where the previous one stopped")
 }
 
 // InitFromViper initializes Builder with properties from viper
@@ -115,6 +149,14 @@ func (o *Options) InitFromViper(v *viper.Viper) {
 
 	o.Parallelism = v.GetInt(ConfigPrefix + SuffixParallelism)
 	o.DeadlockInterval = v.GetDuration(ConfigPrefix + SuffixDeadlockInterval)
+
+	o.DeadLetterTopic = v.GetString(ConfigPrefix + SuffixDeadLetterTopic)
+	o.DeadLetterBrokers = o.Brokers
+	if brokers := stripWhiteSpace(v.GetString(ConfigPrefix + SuffixDeadLetterBrokers)); brokers != "" {
+		o.DeadLetterBrokers = strings.Split(brokers, ",")
+	}
+	o.DeadLetterFile = v.GetString(ConfigPrefix + SuffixDeadLetterFile)
+	o.DeadLetterGroupID = v.GetString(ConfigPrefix + SuffixDeadLetterGroupID)
 }
 
 // stripWhiteSpace removes all whitespace characters from a string
@@ -35,6 +35,10 @@ func TestOptionsWithFlags(t *testing.T) {
 		"--kafka.consumer.encoding=json",
 		"--ingester.parallelism=5",
 		"--ingester.deadlockInterval=2m",
+		"--ingester.dead-letter.topic=dlq1",
+		"--ingester.dead-letter.brokers=127.0.0.1:9093",
+		"--ingester.dead-letter.file=/tmp/dlq.json",
+		"--ingester.dead-letter.group-id=dlq-group",
 	})
 	o.InitFromViper(v)
 
@@ -45,6 +49,10 @@ func TestOptionsWithFlags(t *testing.T) {
 	assert.Equal(t, 5, o.Parallelism)
 	assert.Equal(t, 2*time.Minute, o.DeadlockInterval)
 	assert.Equal(t, kafka.EncodingJSON, o.Encoding)
+	assert.Equal(t, "dlq1", o.DeadLetterTopic)
+	assert.Equal(t, []string{"127.0.0.1:9093"}, o.DeadLetterBrokers)
+	assert.Equal(t, "/tmp/dlq.json", o.DeadLetterFile)
+	assert.Equal(t, "dlq-group", o.DeadLetterGroupID)
 }
 
 func TestFlagDefaults(t *testing.T) {
@@ -60,4 +68,8 @@ func TestFlagDefaults(t *testing.T) {
 	assert.Equal(t, DefaultParallelism, o.Parallelism)
 	assert.Equal(t, DefaultEncoding, o.Encoding)
 	assert.Equal(t, DefaultDeadlockInterval, o.DeadlockInterval)
+	assert.Empty(t, o.DeadLetterTopic)
+	assert.Equal(t, []string{DefaultBroker}, o.DeadLetterBrokers)
+	assert.Empty(t, o.DeadLetterFile)
+	assert.Equal(t, DefaultDeadLetterGroupID, o.DeadLetterGroupID)
 }
@@ -0,0 +1,126 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package decorator
+
+import (
+	"io"
+	"time"
+
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/deadletter"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor"
+)
+
+// kafkaMessage is the part of a consumed Kafka message locating it, see consumer.Message
+type kafkaMessage interface {
+	Key() []byte
+	Topic() string
+	Partition() int32
+	Offset() int64
+}
+
+type deadLetterMetrics struct {
+	// PublishedUnmarshal and PublishedWrite count the messages published to the dead-letter sink, by reason
+	PublishedUnmarshal metrics.Counter `metric:"published" tags:"reason=unmarshal"`
+	PublishedWrite     metrics.Counter `metric:"published" tags:"reason=write"`
+	// PublishErrors counts the messages that could not be published to the dead-letter sink
+	PublishErrors metrics.Counter `metric:"publish-errors"`
+}
+
+type deadLetterDecorator struct {
+	processor processor.SpanProcessor
+	sink      deadletter.Sink
+	metrics   deadLetterMetrics
+	logger    *zap.Logger
+	timeNow   func() time.Time
+	options   retryOptions
+	// fatal stops the ingester, it is replaced in tests
+	fatal func(msg string, fields ...zap.Field)
+	io.Closer
+}
+
+// NewDeadLetterProcessor returns a processor that publishes the messages failed by the wrapped processor
+// to the dead-letter sink, along with the error. The wrapped processor is expected to retry the failures
+// that can be retried and to propagate the errors, see PropagateError and SkipUnmarshalRetries.
+// A message is reported as processed once it has been published, so that its offset gets committed.
+//
+// The publications that fail are retried with the exponential backoff set by the backoff and attempts
+// options. If they keep failing the ingester stops: the offset of the message could never be committed,
+// which would hold back the offsets of the whole partition, while a restarted ingester consumes again
+// from the last committed offset.
+func NewDeadLetterProcessor(f metrics.Factory, processor processor.SpanProcessor, sink deadletter.Sink, logger *zap.Logger, opts ...RetryOption) processor.SpanProcessor {
+	options := defaultOpts
+	for _, opt := range opts {
+		opt(&options)
+	}
+	d := &deadLetterDecorator{
+		processor: processor,
+		sink:      sink,
+		logger:    logger,
+		timeNow:   time.Now,
+		options:   options,
+		fatal:     logger.Fatal,
+	}
+	metrics.Init(&d.metrics, f.Namespace(metrics.NSOptions{Name: "dead-letter", Tags: nil}), nil)
+	return d
+}
+
+func (d *deadLetterDecorator) Process(message processor.Message) error {
+	err := d.processor.Process(message)
+	if err == nil {
+		return nil
+	}
+
+	letter := deadletter.Letter{
+		Value:  message.Value(),
+		Reason: deadletter.ReasonWrite,
+		Error:  err.Error(),
+		Time:   d.timeNow(),
+	}
+	if processor.IsUnmarshalError(err) {
+		letter.Reason = deadletter.ReasonUnmarshal
+	}
+	if msg, ok := message.(kafkaMessage); ok {
+		letter.Key = msg.Key()
+		letter.Topic = msg.Topic()
+		letter.Partition = msg.Partition()
+		letter.Offset = msg.Offset()
+	}
+
+	publishErr := d.sink.Publish(letter)
+	for attempts := uint(0); publishErr != nil && d.options.maxAttempts > attempts; attempts++ {
+		d.metrics.PublishErrors.Inc(1)
+		time.Sleep(d.options.computeInterval(attempts))
+		publishErr = d.sink.Publish(letter)
+	}
+	if publishErr != nil {
+		d.metrics.PublishErrors.Inc(1)
+		d.fatal("Failed to publish message to the dead-letter sink",
+			zap.String("topic", letter.Topic),
+			zap.Int32("partition", letter.Partition),
+			zap.Int64("offset", letter.Offset),
+			zap.NamedError("processing_error", err),
+			zap.Error(publishErr))
+		return err
+	}
+	if letter.Reason == deadletter.ReasonUnmarshal {
+		d.metrics.PublishedUnmarshal.Inc(1)
+	} else {
+		d.metrics.PublishedWrite.Inc(1)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package decorator
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+	"go.uber.org/zap"
+
+	cmocks "github.com/jaegertracing/jaeger/cmd/ingester/app/consumer/mocks"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/deadletter"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor/mocks"
+	kmocks "github.com/jaegertracing/jaeger/pkg/kafka/mocks"
+	smocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+type fakeSink struct {
+	letters []deadletter.Letter
+	// err is returned by the first failures calls to Publish
+	err      error
+	failures int
+}
+
+func (s *fakeSink) Publish(letter deadletter.Letter) error {
+	if s.failures > 0 {
+		s.failures--
+		return s.err
+	}
+	s.letters = append(s.letters, letter)
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	return nil
+}
+
+func newKafkaMessage(value []byte) *cmocks.Message {
+	msg := &cmocks.Message{}
+	msg.On("Key").Return([]byte("key"))
+	msg.On("Value").Return(value)
+	msg.On("Topic").Return("jaeger-spans")
+	msg.On("Partition").Return(int32(3))
+	msg.On("Offset").Return(int64(42))
+	return msg
+}
+
+func TestDeadLetterProcessor(t *testing.T) {
+	unmarshaller := &kmocks.Unmarshaller{}
+	writer := &smocks.Writer{}
+	spanProcessor := processor.NewSpanProcessor(processor.SpanProcessorParams{Unmarshaller: unmarshaller, Writer: writer})
+	unmarshaller.On("Unmarshal", []byte("invalid")).Return(nil, errors.New("invalid span"))
+	unmarshaller.On("Unmarshal", []byte("valid")).Return(nil, nil)
+	writer.On("WriteSpan", mock.Anything).Return(errors.New("storage unavailable"))
+
+	sink := &fakeSink{}
+	lf := metricstest.NewFactory(0)
+	now := time.Unix(1000, 0)
+	dp := NewDeadLetterProcessor(lf, spanProcessor, sink, zap.NewNop())
+	dp.(*deadLetterDecorator).timeNow = func() time.Time { return now }
+
+	assert.NoError(t, dp.Process(newKafkaMessage([]byte("invalid"))))
+	assert.NoError(t, dp.Process(newKafkaMessage([]byte("valid"))))
+	assert.Equal(t, []deadletter.Letter{
+		{
+			Key:       []byte("key"),
+			Value:     []byte("invalid"),
+			Topic:     "jaeger-spans",
+			Partition: 3,
+			Offset:    42,
+			Reason:    deadletter.ReasonUnmarshal,
+			Error:     "cannot unmarshall byte array into span: invalid span",
+			Time:      now,
+		},
+		{
+			Key:       []byte("key"),
+			Value:     []byte("valid"),
+			Topic:     "jaeger-spans",
+			Partition: 3,
+			Offset:    42,
+			Reason:    deadletter.ReasonWrite,
+			Error:     "storage unavailable",
+			Time:      now,
+		},
+	}, sink.letters)
+	lf.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "dead-letter.published", Tags: map[string]string{"reason": "unmarshal"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "dead-letter.published", Tags: map[string]string{"reason": "write"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "dead-letter.publish-errors", Value: 0},
+	)
+}
+
+func TestDeadLetterProcessorSuccess(t *testing.T) {
+	mockProcessor := &mocks.SpanProcessor{}
+	msg := &fakeMsg{}
+	mockProcessor.On("Process", msg).Return(nil)
+	sink := &fakeSink{}
+	dp := NewDeadLetterProcessor(metricstest.NewFactory(0), mockProcessor, sink, zap.NewNop())
+
+	assert.NoError(t, dp.Process(msg))
+	assert.Empty(t, sink.letters)
+}
+
+func TestDeadLetterProcessorPublishRetries(t *testing.T) {
+	mockProcessor := &mocks.SpanProcessor{}
+	msg := &fakeMsg{}
+	mockProcessor.On("Process", msg).Return(errors.New("storage unavailable"))
+	lf := metricstest.NewFactory(0)
+	sink := &fakeSink{err: errors.New("sink unavailable"), failures: 2}
+	dp := NewDeadLetterProcessor(lf, mockProcessor, sink, zap.NewNop(),
+		MinBackoffInterval(time.Nanosecond), MaxBackoffInterval(time.Millisecond), MaxAttempts(2))
+	dp.(*deadLetterDecorator).fatal = func(msg string, fields ...zap.Field) {
+		t.Fatal("unexpected fatal error")
+	}
+
+	assert.NoError(t, dp.Process(msg))
+	assert.Len(t, sink.letters, 1)
+	lf.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "dead-letter.published", Tags: map[string]string{"reason": "write"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "dead-letter.publish-errors", Value: 2},
+	)
+}
+
+func TestDeadLetterProcessorPublishError(t *testing.T) {
+	mockProcessor := &mocks.SpanProcessor{}
+	msg := &fakeMsg{}
+	mockProcessor.On("Process", msg).Return(errors.New("storage unavailable"))
+	lf := metricstest.NewFactory(0)
+	sink := &fakeSink{err: errors.New("sink unavailable"), failures: 3}
+	dp := NewDeadLetterProcessor(lf, mockProcessor, sink, zap.NewNop(),
+		MinBackoffInterval(time.Nanosecond), MaxBackoffInterval(time.Millisecond), MaxAttempts(2))
+	var fatal string
+	dp.(*deadLetterDecorator).fatal = func(msg string, fields ...zap.Field) {
+		fatal = msg
+	}
+
+	// the ingester stops once the retries are exhausted, the offset of the message is not committed
+	err := dp.Process(msg)
+	require.Error(t, err)
+	assert.EqualError(t, err, "storage unavailable")
+	assert.Equal(t, "Failed to publish message to the dead-letter sink", fatal)
+	assert.Empty(t, sink.letters)
+	lf.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "dead-letter.publish-errors", Value: 3})
+}
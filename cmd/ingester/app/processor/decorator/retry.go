@@ -39,6 +39,7 @@ type retryOptions struct {
 	minInterval, maxInterval time.Duration
 	maxAttempts              uint
 	propagateError           bool
+	skipUnmarshalRetries     bool
 	rand                     randInt63
 }
 
@@ -89,6 +90,14 @@ func PropagateError(b bool) RetryOption {
 	}
 }
 
+// SkipUnmarshalRetries sets whether to give up right away on the messages that cannot be unmarshalled,
+// since retrying cannot fix them, e.g. to send them to a dead-letter sink without delay
+func SkipUnmarshalRetries(b bool) RetryOption {
+	return func(opt *retryOptions) {
+		opt.skipUnmarshalRetries = b
+	}
+}
+
 // NewRetryingProcessor returns a processor that retries failures using an exponential backoff
 // with jitter.
 func NewRetryingProcessor(f metrics.Factory, processor processor.SpanProcessor, opts ...RetryOption) processor.SpanProcessor {
@@ -113,6 +122,13 @@ func (d *retryDecorator) Process(message processor.Message) error {
 		return nil
 	}
 
+	if d.options.skipUnmarshalRetries && processor.IsUnmarshalError(err) {
+		if d.options.propagateError {
+			return err
+		}
+		return nil
+	}
+
 	for attempts := uint(0); err != nil && d.options.maxAttempts > attempts; attempts++ {
 		time.Sleep(d.computeInterval(attempts))
 		err = d.processor.Process(message)
@@ -130,9 +146,13 @@ func (d *retryDecorator) Process(message processor.Message) error {
 }
 
 func (d *retryDecorator) computeInterval(attempts uint) time.Duration {
-	dur := (1 << attempts) * d.options.minInterval.Nanoseconds()
-	if dur <= 0 || dur > d.options.maxInterval.Nanoseconds() {
-		dur = d.options.maxInterval.Nanoseconds()
+	return d.options.computeInterval(attempts)
+}
+
+func (o retryOptions) computeInterval(attempts uint) time.Duration {
+	dur := (1 << attempts) * o.minInterval.Nanoseconds()
+	if dur <= 0 || dur > o.maxInterval.Nanoseconds() {
+		dur = o.maxInterval.Nanoseconds()
 	}
-	return time.Duration(d.options.rand.Int63n(dur))
+	return time.Duration(o.rand.Int63n(dur))
 }
@@ -23,7 +23,9 @@ import (
 	"github.com/uber/jaeger-lib/metrics"
 	"github.com/uber/jaeger-lib/metrics/metricstest"
 
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor"
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor/mocks"
+	kmocks "github.com/jaegertracing/jaeger/pkg/kafka/mocks"
 )
 
 type fakeMsg struct{}
@@ -88,6 +90,37 @@ func TestNewRetryingProcessorNoErrorPropagation(t *testing.T) {
 	assert.Equal(t, int64(1), c["span-processor.retry-attempts"])
 }
 
+func TestNewRetryingProcessorUnmarshalError(t *testing.T) {
+	unmarshaller := &kmocks.Unmarshaller{}
+	unmarshaller.On("Unmarshal", []byte(nil)).Return(nil, errors.New("invalid span"))
+	spanProcessor := processor.NewSpanProcessor(processor.SpanProcessorParams{Unmarshaller: unmarshaller})
+	opts := []RetryOption{
+		MinBackoffInterval(0),
+		MaxAttempts(2),
+		PropagateError(true),
+		Rand(&fakeRand{})}
+	lf := metricstest.NewFactory(0)
+	rp := NewRetryingProcessor(lf, spanProcessor, append(opts, SkipUnmarshalRetries(true))...)
+
+	assert.EqualError(t, rp.Process(&fakeMsg{}), "cannot unmarshall byte array into span: invalid span")
+	unmarshaller.AssertNumberOfCalls(t, "Unmarshal", 1)
+	c, _ := lf.Snapshot()
+	assert.Equal(t, int64(0), c["span-processor.retry-exhausted"])
+	assert.Equal(t, int64(0), c["span-processor.retry-attempts"])
+
+	rp = NewRetryingProcessor(lf, spanProcessor, PropagateError(false), SkipUnmarshalRetries(true))
+	assert.NoError(t, rp.Process(&fakeMsg{}))
+
+	// the unmarshal errors are retried like any other error by default
+	lf = metricstest.NewFactory(0)
+	rp = NewRetryingProcessor(lf, spanProcessor, opts...)
+	assert.EqualError(t, rp.Process(&fakeMsg{}), "cannot unmarshall byte array into span: invalid span")
+	unmarshaller.AssertNumberOfCalls(t, "Unmarshal", 5)
+	c, _ = lf.Snapshot()
+	assert.Equal(t, int64(1), c["span-processor.retry-exhausted"])
+	assert.Equal(t, int64(2), c["span-processor.retry-attempts"])
+}
+
 type fakeRand struct{}
 
 func (f *fakeRand) Int63n(v int64) int64 {
@@ -61,7 +61,19 @@ func NewSpanProcessor(params SpanProcessorParams) *KafkaSpanProcessor {
 func (s KafkaSpanProcessor) Process(message Message) error {
 	mSpan, err := s.unmarshaller.Unmarshal(message.Value())
 	if err != nil {
-		return errors.Wrap(err, "cannot unmarshall byte array into span")
+		return unmarshalError{errors.Wrap(err, "cannot unmarshall byte array into span")}
 	}
 	return s.writer.WriteSpan(mSpan)
 }
+
+// unmarshalError is returned by Process when the message cannot be unmarshalled, which retrying cannot fix
+type unmarshalError struct {
+	error
+}
+
+// IsUnmarshalError tells whether the error was returned by a KafkaSpanProcessor for a message that
+// cannot be unmarshalled into a span.
+func IsUnmarshalError(err error) bool {
+	_, ok := err.(unmarshalError)
+	return ok
+}
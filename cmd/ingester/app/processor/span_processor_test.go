@@ -53,6 +53,27 @@ func TestSpanProcessor_Process(t *testing.T) {
 	writer.AssertExpectations(t)
 }
 
+func TestSpanProcessor_ProcessWriteError(t *testing.T) {
+	writer := &smocks.Writer{}
+	unmarshallerMock := &umocks.Unmarshaller{}
+	processor := &KafkaSpanProcessor{
+		unmarshaller: unmarshallerMock,
+		writer:       writer,
+	}
+
+	message := &cmocks.Message{}
+	data := []byte("police")
+	span := &model.Span{}
+
+	message.On("Value").Return(data)
+	unmarshallerMock.On("Unmarshal", data).Return(span, nil)
+	writer.On("WriteSpan", span).Return(errors.New("storage unavailable"))
+
+	err := processor.Process(message)
+	assert.EqualError(t, err, "storage unavailable")
+	assert.False(t, IsUnmarshalError(err))
+}
+
 func TestSpanProcessor_ProcessError(t *testing.T) {
 	writer := &smocks.Writer{}
 	unmarshallerMock := &umocks.Unmarshaller{}
@@ -67,7 +88,9 @@ func TestSpanProcessor_ProcessError(t *testing.T) {
 	message.On("Value").Return(data)
 	unmarshallerMock.On("Unmarshal", data).Return(nil, errors.New("moocow"))
 
-	assert.Error(t, processor.Process(message))
+	err := processor.Process(message)
+	assert.EqualError(t, err, "cannot unmarshall byte array into span: moocow")
+	assert.True(t, IsUnmarshalError(err))
 
 	message.AssertExpectations(t)
 	writer.AssertNotCalled(t, "WriteSpan")
@@ -31,6 +31,7 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/flags"
 	"github.com/jaegertracing/jaeger/cmd/ingester/app"
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/builder"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/deadletter"
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/version"
 	"github.com/jaegertracing/jaeger/plugin/storage"
@@ -69,7 +70,11 @@ func main() {
 
 			options := app.Options{}
 			options.InitFromViper(v)
-			consumer, err := builder.CreateConsumer(logger, metricsFactory, spanWriter, options)
+			deadLetterSink, err := builder.CreateDeadLetterSink(options)
+			if err != nil {
+				logger.Fatal("Unable to create dead-letter sink", zap.Error(err))
+			}
+			consumer, err := builder.CreateConsumer(logger, metricsFactory, spanWriter, options, deadLetterSink)
 			if err != nil {
 				logger.Fatal("Unable to create consumer", zap.Error(err))
 			}
@@ -79,12 +84,20 @@ func main() {
 				if err = consumer.Close(); err != nil {
 					logger.Error("Failed to close consumer", zap.Error(err))
 				}
+				if deadLetterSink != nil {
+					if err := deadLetterSink.Close(); err != nil {
+						logger.Error("Failed to close dead-letter sink", zap.Error(err))
+					}
+				}
 				if closer, ok := spanWriter.(io.Closer); ok {
 					err := closer.Close()
 					if err != nil {
 						logger.Error("Failed to close span writer", zap.Error(err))
 					}
 				}
+				if err := storageFactory.Close(); err != nil {
+					logger.Error("Failed to close storage factory", zap.Error(err))
+				}
 			})
 			return nil
 		},
@@ -93,6 +106,7 @@ func main() {
 	command.AddCommand(version.Command())
 	command.AddCommand(env.Command())
 	command.AddCommand(docs.Command(v))
+	command.AddCommand(replayCommand(storageFactory))
 
 	config.AddFlags(
 		v,
@@ -107,3 +121,75 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// replayCommand creates the command writing the dead letters back to storage. It uses its own viper
+// instance so that binding its flags does not interfere with the flags of the root command.
+func replayCommand(storageFactory *storage.Factory) *cobra.Command {
+	v := viper.New()
+	command := &cobra.Command{
+		Use:   "replay-dlq",
+		Short: "Replays the dead letters of the ingester into storage",
+		Long: `Reads the messages the ingester could not ingest from the dead-letter topic or file ` +
+			`and writes them to the configured storage. The dead letters are left in place. The replay stops at the ` +
+			`first letter that cannot be written, the next replay of the dead-letter topic resumes from it.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := flags.TryLoadConfigFile(v); err != nil {
+				return err
+			}
+			logger, err := new(flags.SharedFlags).InitFromViper(v).NewLogger(zap.NewProductionConfig())
+			if err != nil {
+				return err
+			}
+
+			storageFactory.InitFromViper(v)
+			if err := storageFactory.Initialize(metrics.NullFactory, logger); err != nil {
+				logger.Fatal("Failed to init storage factory", zap.Error(err))
+			}
+			defer storageFactory.Close()
+			spanWriter, err := storageFactory.CreateSpanWriter()
+			if err != nil {
+				logger.Fatal("Failed to create span writer", zap.Error(err))
+			}
+			if closer, ok := spanWriter.(io.Closer); ok {
+				defer closer.Close()
+			}
+
+			options := app.Options{}
+			options.InitFromViper(v)
+			source, err := builder.CreateDeadLetterSource(options)
+			if err != nil {
+				logger.Fatal("Unable to create dead-letter source", zap.Error(err))
+			}
+			defer source.Close()
+			spanProcessor, err := builder.CreateSpanProcessor(spanWriter, options.Encoding)
+			if err != nil {
+				logger.Fatal("Unable to create span processor", zap.Error(err))
+			}
+
+			result, err := deadletter.Replay(source, func(letter deadletter.Letter) error {
+				return spanProcessor.Process(letterMessage{letter: letter})
+			}, logger)
+			logger.Info("Replayed dead letters", zap.Int("replayed", result.Replayed))
+			return err
+		},
+	}
+
+	config.AddFlags(
+		v,
+		command,
+		flags.AddConfigFileFlag,
+		flags.AddFlags,
+		storageFactory.AddFlags,
+		app.AddFlags,
+	)
+	return command
+}
+
+// letterMessage exposes a dead letter as a message for the span processor
+type letterMessage struct {
+	letter deadletter.Letter
+}
+
+func (m letterMessage) Value() []byte {
+	return m.letter.Value
+}
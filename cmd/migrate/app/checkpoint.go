@@ -0,0 +1,83 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// checkpoint records the progress of a migration
+type checkpoint struct {
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	// Next is the start of the next window to migrate
+	Next time.Time `json:"next"`
+	// FailedTraceIDs are the traces of the migrated windows that could not be migrated, they are retried on resume
+	FailedTraceIDs []model.TraceID `json:"failedTraceIds,omitempty"`
+	Report         Report          `json:"report"`
+}
+
+// loadCheckpoint reads the checkpoint file, or returns a checkpoint at the start of the migration
+// if there is none
+func (m *Migrator) loadCheckpoint() (checkpoint, error) {
+	initial := checkpoint{StartTime: m.options.StartTime, EndTime: m.options.EndTime, Next: m.options.StartTime}
+	if m.options.CheckpointFile == "" {
+		return initial, nil
+	}
+	data, err := ioutil.ReadFile(filepath.Clean(m.options.CheckpointFile))
+	if os.IsNotExist(err) {
+		return initial, nil
+	}
+	if err != nil {
+		return checkpoint{}, errors.Wrap(err, "failed to read the checkpoint file")
+	}
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return checkpoint{}, errors.Wrap(err, "failed to parse the checkpoint file")
+	}
+	if !cp.StartTime.Equal(m.options.StartTime) || !cp.EndTime.Equal(m.options.EndTime) {
+		return checkpoint{}, fmt.Errorf("the checkpoint file is for the migration from %v to %v", cp.StartTime, cp.EndTime)
+	}
+	return cp, nil
+}
+
+// saveCheckpoint replaces the checkpoint file, if configured
+func (m *Migrator) saveCheckpoint(cp checkpoint) error {
+	if m.options.CheckpointFile == "" {
+		return nil
+	}
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	// write a temporary file first so that an interruption does not leave a partial checkpoint
+	tmpFile := m.options.CheckpointFile + ".tmp"
+	if err := ioutil.WriteFile(tmpFile, data, 0600); err != nil {
+		return errors.Wrap(err, "failed to write the checkpoint file")
+	}
+	if err := os.Rename(tmpFile, m.options.CheckpointFile); err != nil {
+		return errors.Wrap(err, "failed to write the checkpoint file")
+	}
+	return nil
+}
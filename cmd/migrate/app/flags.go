@@ -0,0 +1,110 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	startTime      = "migrate.start-time"
+	endTime        = "migrate.end-time"
+	window         = "migrate.window"
+	services       = "migrate.services"
+	numTraces      = "migrate.num-traces"
+	concurrency    = "migrate.concurrency"
+	checkpointFile = "migrate.checkpoint-file"
+	verify         = "migrate.verify"
+
+	// DefaultWindow is the default duration of the time windows the traces are migrated by
+	DefaultWindow = time.Hour
+	// DefaultNumTraces is the default number of trace IDs looked up per query
+	DefaultNumTraces = 10000
+	// DefaultConcurrency is the default number of traces migrated concurrently
+	DefaultConcurrency = 4
+)
+
+// Options holds the configuration of a migration
+type Options struct {
+	StartTime      time.Time
+	EndTime        time.Time
+	Window         time.Duration
+	Services       []string
+	NumTraces      int
+	Concurrency    int
+	CheckpointFile string
+	Verify         bool
+}
+
+// AddFlags adds flags for Options
+func AddFlags(flagSet *flag.FlagSet) {
+	flagSet.String(startTime, "", "The start of the time range of the traces to migrate, in RFC3339 format (required)")
+	flagSet.String(endTime, "", "The end of the time range of the traces to migrate, in RFC3339 format. Defaults to now")
+	flagSet.Duration(window, DefaultWindow, "The duration of the time windows the traces are migrated by")
+	flagSet.String(services, "", "The comma separated services of the traces to migrate. Defaults to all the services of the source")
+	flagSet.Int(numTraces, DefaultNumTraces,
+		"The number of trace IDs looked up per query, the traces of a service and time window are looked up by as many queries as needed")
+	flagSet.Int(concurrency, DefaultConcurrency, "The number of traces migrated concurrently")
+	flagSet.String(checkpointFile, "",
+		"The file recording the progress of the migration, to resume it after an interruption. Disabled if empty")
+	flagSet.Bool(verify, false, "Read the migrated traces back from the destination and report the mismatches")
+}
+
+// InitFromViper initializes Options with properties from viper
+func (o *Options) InitFromViper(v *viper.Viper) error {
+	var err error
+	if v.GetString(startTime) == "" {
+		return errors.New("the start time of the migration is required")
+	}
+	if o.StartTime, err = time.Parse(time.RFC3339, v.GetString(startTime)); err != nil {
+		return fmt.Errorf("invalid start time: %v", err)
+	}
+	o.EndTime = time.Now()
+	if v.GetString(endTime) != "" {
+		if o.EndTime, err = time.Parse(time.RFC3339, v.GetString(endTime)); err != nil {
+			return fmt.Errorf("invalid end time: %v", err)
+		}
+	}
+	if !o.StartTime.Before(o.EndTime) {
+		return errors.New("the start time of the migration must be before its end time")
+	}
+	o.Window = v.GetDuration(window)
+	if o.Window <= 0 {
+		return errors.New("the migration window must be positive")
+	}
+	o.Services = nil
+	for _, service := range strings.Split(v.GetString(services), ",") {
+		if service = strings.TrimSpace(service); service != "" {
+			o.Services = append(o.Services, service)
+		}
+	}
+	o.NumTraces = v.GetInt(numTraces)
+	if o.NumTraces <= 0 {
+		return errors.New("the number of traces per query must be positive")
+	}
+	o.Concurrency = v.GetInt(concurrency)
+	if o.Concurrency <= 0 {
+		return errors.New("the migration concurrency must be positive")
+	}
+	o.CheckpointFile = v.GetString(checkpointFile)
+	o.Verify = v.GetBool(verify)
+	return nil
+}
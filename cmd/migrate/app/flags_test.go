@@ -0,0 +1,107 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+func TestOptionsWithFlags(t *testing.T) {
+	o := &Options{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--migrate.start-time=2019-06-01T00:00:00Z",
+		"--migrate.end-time=2019-06-02T00:00:00Z",
+		"--migrate.window=10m",
+		"--migrate.services=frontend, backend,",
+		"--migrate.num-traces=100",
+		"--migrate.concurrency=8",
+		"--migrate.checkpoint-file=/tmp/checkpoint.json",
+		"--migrate.verify=true",
+	})
+	require.NoError(t, o.InitFromViper(v))
+
+	assert.Equal(t, time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC), o.StartTime.UTC())
+	assert.Equal(t, time.Date(2019, 6, 2, 0, 0, 0, 0, time.UTC), o.EndTime.UTC())
+	assert.Equal(t, 10*time.Minute, o.Window)
+	assert.Equal(t, []string{"frontend", "backend"}, o.Services)
+	assert.Equal(t, 100, o.NumTraces)
+	assert.Equal(t, 8, o.Concurrency)
+	assert.Equal(t, "/tmp/checkpoint.json", o.CheckpointFile)
+	assert.True(t, o.Verify)
+}
+
+func TestFlagDefaults(t *testing.T) {
+	o := &Options{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{"--migrate.start-time=2019-06-01T00:00:00Z"})
+	require.NoError(t, o.InitFromViper(v))
+
+	assert.WithinDuration(t, time.Now(), o.EndTime, time.Minute)
+	assert.Equal(t, DefaultWindow, o.Window)
+	assert.Empty(t, o.Services)
+	assert.Equal(t, DefaultNumTraces, o.NumTraces)
+	assert.Equal(t, DefaultConcurrency, o.Concurrency)
+	assert.Empty(t, o.CheckpointFile)
+	assert.False(t, o.Verify)
+}
+
+func TestInvalidFlags(t *testing.T) {
+	testCases := []struct {
+		flags         []string
+		expectedError string
+	}{
+		{
+			flags:         []string{},
+			expectedError: "the start time of the migration is required",
+		},
+		{
+			flags:         []string{"--migrate.start-time=yesterday"},
+			expectedError: `invalid start time: parsing time "yesterday" as "2006-01-02T15:04:05Z07:00": cannot parse "yesterday" as "2006"`,
+		},
+		{
+			flags:         []string{"--migrate.start-time=2019-06-01T00:00:00Z", "--migrate.end-time=today"},
+			expectedError: `invalid end time: parsing time "today" as "2006-01-02T15:04:05Z07:00": cannot parse "today" as "2006"`,
+		},
+		{
+			flags:         []string{"--migrate.start-time=2019-06-01T00:00:00Z", "--migrate.end-time=2019-06-01T00:00:00Z"},
+			expectedError: "the start time of the migration must be before its end time",
+		},
+		{
+			flags:         []string{"--migrate.start-time=2019-06-01T00:00:00Z", "--migrate.window=0s"},
+			expectedError: "the migration window must be positive",
+		},
+		{
+			flags:         []string{"--migrate.start-time=2019-06-01T00:00:00Z", "--migrate.num-traces=0"},
+			expectedError: "the number of traces per query must be positive",
+		},
+		{
+			flags:         []string{"--migrate.start-time=2019-06-01T00:00:00Z", "--migrate.concurrency=0"},
+			expectedError: "the migration concurrency must be positive",
+		},
+	}
+	for _, testCase := range testCases {
+		o := &Options{}
+		v, command := config.Viperize(AddFlags)
+		require.NoError(t, command.ParseFlags(testCase.flags))
+		assert.EqualError(t, o.InitFromViper(v), testCase.expectedError)
+	}
+}
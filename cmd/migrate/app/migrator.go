@@ -0,0 +1,256 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// Report counts what a migration did
+type Report struct {
+	// Windows is the number of time windows migrated
+	Windows int `json:"windows"`
+	// Traces is the number of traces written to the destination
+	Traces int `json:"traces"`
+	// Spans is the number of spans written to the destination
+	Spans int `json:"spans"`
+	// Failed is the number of traces that could not be read from the source or written to the destination
+	Failed int `json:"failed"`
+	// Mismatches is the number of traces with a different number of spans in the destination
+	Mismatches int `json:"mismatches"`
+}
+
+// Migrator copies the traces of a source storage to a destination storage
+type Migrator struct {
+	options           Options
+	source            spanstore.Reader
+	destination       spanstore.Writer
+	destinationReader spanstore.Reader
+	logger            *zap.Logger
+}
+
+// NewMigrator creates a Migrator. The destination reader is used to verify the migrated traces
+// if enabled in the options.
+func NewMigrator(
+	options Options,
+	source spanstore.Reader,
+	destination spanstore.Writer,
+	destinationReader spanstore.Reader,
+	logger *zap.Logger,
+) *Migrator {
+	return &Migrator{
+		options:           options,
+		source:            source,
+		destination:       destination,
+		destinationReader: destinationReader,
+		logger:            logger,
+	}
+}
+
+// Run migrates the traces of the configured time range window by window, for every service. It saves its
+// progress after each window to the checkpoint file, if configured, and resumes from it, retrying first the
+// traces that failed. Run stops after the current window when the context is cancelled, the traces of the
+// window that failed because of the cancellation are retried on resume.
+func (m *Migrator) Run(ctx context.Context) (Report, error) {
+	progress, err := m.loadCheckpoint()
+	if err != nil {
+		return Report{}, err
+	}
+	report := progress.Report
+	if progress.Next.After(m.options.StartTime) {
+		m.logger.Info("Resuming migration from checkpoint", zap.Time("window_start", progress.Next))
+	}
+	failed := progress.FailedTraceIDs
+	if len(failed) > 0 {
+		m.logger.Info("Retrying the traces that failed", zap.Int("traces", len(failed)))
+		// the retried traces are counted again
+		report.Failed -= len(failed)
+		failed = m.migrateTraces(ctx, failed, &report)
+		if err := m.saveCheckpoint(checkpoint{
+			StartTime:      m.options.StartTime,
+			EndTime:        m.options.EndTime,
+			Next:           progress.Next,
+			FailedTraceIDs: failed,
+			Report:         report,
+		}); err != nil {
+			return report, err
+		}
+	}
+
+	services := m.options.Services
+	if len(services) == 0 {
+		if services, err = m.source.GetServices(ctx); err != nil {
+			return report, err
+		}
+	}
+
+	var previousWindow map[model.TraceID]bool
+	if progress.Next.After(m.options.StartTime) {
+		// the traces of the previous window spanning the next one have been migrated already
+		previousStart := progress.Next.Add(-m.options.Window)
+		if previousStart.Before(m.options.StartTime) {
+			previousStart = m.options.StartTime
+		}
+		if _, previousWindow, err = m.findTraceIDs(ctx, services, previousStart, progress.Next, nil); err != nil {
+			return report, err
+		}
+	}
+	for start := progress.Next; start.Before(m.options.EndTime); {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		end := start.Add(m.options.Window)
+		if end.After(m.options.EndTime) {
+			end = m.options.EndTime
+		}
+
+		traceIDs, window, err := m.findTraceIDs(ctx, services, start, end, previousWindow)
+		if err != nil {
+			return report, err
+		}
+		failed = append(failed, m.migrateTraces(ctx, traceIDs, &report)...)
+		report.Windows++
+		m.logger.Info("Migrated window",
+			zap.Time("start", start),
+			zap.Time("end", end),
+			zap.Int("traces", len(traceIDs)))
+
+		if err := m.saveCheckpoint(checkpoint{
+			StartTime:      m.options.StartTime,
+			EndTime:        m.options.EndTime,
+			Next:           end,
+			FailedTraceIDs: failed,
+			Report:         report,
+		}); err != nil {
+			return report, err
+		}
+		previousWindow = window
+		start = end
+	}
+	return report, nil
+}
+
+// findTraceIDs returns the IDs of the traces of the services in the window, skipping the ones migrated with
+// the previous window, and the set of all the trace IDs of the window. The IDs of each service are looked up
+// by pages of NumTraces, until the window is exhausted.
+func (m *Migrator) findTraceIDs(
+	ctx context.Context,
+	services []string,
+	start, end time.Time,
+	previousWindow map[model.TraceID]bool,
+) ([]model.TraceID, map[model.TraceID]bool, error) {
+	window := make(map[model.TraceID]bool)
+	var traceIDs []model.TraceID
+	for _, service := range services {
+		query := &spanstore.TraceQueryParameters{
+			ServiceName:  service,
+			StartTimeMin: start,
+			StartTimeMax: end,
+			NumTraces:    m.options.NumTraces,
+		}
+		for {
+			ids, err := m.source.FindTraceIDs(ctx, query)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, id := range ids {
+				if window[id] {
+					continue
+				}
+				window[id] = true
+				if !previousWindow[id] {
+					traceIDs = append(traceIDs, id)
+				}
+			}
+			if query.ContinuationToken = spanstore.NextContinuationToken(query, len(ids)); query.ContinuationToken == nil {
+				break
+			}
+		}
+	}
+	return traceIDs, window, nil
+}
+
+// migrateTraces migrates the traces concurrently, adds the results to the report and returns the IDs
+// of the traces that failed
+func (m *Migrator) migrateTraces(ctx context.Context, traceIDs []model.TraceID, report *Report) []model.TraceID {
+	var failed []model.TraceID
+	var mux sync.Mutex
+	var wg sync.WaitGroup
+	ids := make(chan model.TraceID)
+	for i := 0; i < m.options.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range ids {
+				result := m.migrateTrace(ctx, id)
+				mux.Lock()
+				report.Traces += result.Traces
+				report.Spans += result.Spans
+				report.Failed += result.Failed
+				report.Mismatches += result.Mismatches
+				if result.Failed > 0 {
+					failed = append(failed, id)
+				}
+				mux.Unlock()
+			}
+		}()
+	}
+	for _, id := range traceIDs {
+		ids <- id
+	}
+	close(ids)
+	wg.Wait()
+	return failed
+}
+
+// migrateTrace migrates a trace and returns its contribution to the report
+func (m *Migrator) migrateTrace(ctx context.Context, traceID model.TraceID) Report {
+	trace, err := m.source.GetTrace(ctx, traceID)
+	if err == nil && trace == nil {
+		err = spanstore.ErrTraceNotFound
+	}
+	if err != nil {
+		m.logger.Error("Failed to read trace", zap.Stringer("trace_id", traceID), zap.Error(err))
+		return Report{Failed: 1}
+	}
+	for _, span := range trace.Spans {
+		if err := m.destination.WriteSpan(span); err != nil {
+			m.logger.Error("Failed to write span",
+				zap.Stringer("trace_id", traceID),
+				zap.Stringer("span_id", span.SpanID),
+				zap.Error(err))
+			return Report{Failed: 1}
+		}
+	}
+	result := Report{Traces: 1, Spans: len(trace.Spans)}
+	if m.options.Verify {
+		migrated, err := m.destinationReader.GetTrace(ctx, traceID)
+		if err != nil || migrated == nil || len(migrated.Spans) != len(trace.Spans) {
+			m.logger.Warn("Migrated trace does not match the source",
+				zap.Stringer("trace_id", traceID),
+				zap.Int("spans", len(trace.Spans)),
+				zap.Error(err))
+			result.Mismatches = 1
+		}
+	}
+	return result
+}
@@ -0,0 +1,304 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/plugin/storage/badger"
+	"github.com/jaegertracing/jaeger/plugin/storage/memory"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+var migrationStart = time.Now().Add(-3 * time.Hour).Truncate(time.Hour)
+
+func testSpan(traceID uint64, spanID uint64, service string, startTime time.Time) *model.Span {
+	return &model.Span{
+		TraceID:       model.NewTraceID(0, traceID),
+		SpanID:        model.NewSpanID(spanID),
+		OperationName: "operation",
+		StartTime:     startTime,
+		Duration:      time.Second,
+		Process:       model.NewProcess(service, nil),
+	}
+}
+
+// newSourceStore returns a memory store with traces in the three hours after migrationStart,
+// trace 3 spans the first two hours, trace 5 is out of the migrated range
+func newSourceStore(t *testing.T) *memory.Store {
+	store := memory.NewStore()
+	for _, span := range []*model.Span{
+		testSpan(1, 1, "frontend", migrationStart.Add(10*time.Minute)),
+		testSpan(1, 2, "backend", migrationStart.Add(11*time.Minute)),
+		testSpan(2, 3, "backend", migrationStart.Add(20*time.Minute)),
+		testSpan(3, 4, "frontend", migrationStart.Add(59*time.Minute)),
+		testSpan(3, 5, "backend", migrationStart.Add(61*time.Minute)),
+		testSpan(4, 6, "frontend", migrationStart.Add(150*time.Minute)),
+		testSpan(5, 7, "frontend", migrationStart.Add(-time.Minute)),
+	} {
+		require.NoError(t, store.WriteSpan(span))
+	}
+	return store
+}
+
+func withBadgerStore(t *testing.T, fn func(writer spanstore.Writer, reader spanstore.Reader)) {
+	f := badger.NewFactory()
+	v, _ := config.Viperize(f.AddFlags)
+	f.InitFromViper(v)
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+	defer f.Close()
+	writer, err := f.CreateSpanWriter()
+	require.NoError(t, err)
+	reader, err := f.CreateSpanReader()
+	require.NoError(t, err)
+	fn(writer, reader)
+}
+
+func testOptions() Options {
+	return Options{
+		StartTime:   migrationStart,
+		EndTime:     migrationStart.Add(3 * time.Hour),
+		Window:      time.Hour,
+		NumTraces:   DefaultNumTraces,
+		Concurrency: 2,
+		Verify:      true,
+	}
+}
+
+func TestMigrateMemoryToBadger(t *testing.T) {
+	withBadgerStore(t, func(writer spanstore.Writer, reader spanstore.Reader) {
+		migrator := NewMigrator(testOptions(), newSourceStore(t), writer, reader, zap.NewNop())
+		report, err := migrator.Run(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, Report{Windows: 3, Traces: 4, Spans: 6}, report)
+
+		for traceID, spans := range map[uint64]int{1: 2, 2: 1, 3: 2, 4: 1} {
+			trace, err := reader.GetTrace(context.Background(), model.NewTraceID(0, traceID))
+			require.NoError(t, err)
+			require.NotNil(t, trace, "trace %d", traceID)
+			assert.Len(t, trace.Spans, spans, "trace %d", traceID)
+		}
+		trace, err := reader.GetTrace(context.Background(), model.NewTraceID(0, 5))
+		assert.NoError(t, err)
+		assert.Nil(t, trace)
+	})
+}
+
+func TestMigrateServicesAndPages(t *testing.T) {
+	source := newSourceStore(t)
+	destination := memory.NewStore()
+	options := testOptions()
+	options.Services = []string{"backend"}
+	options.NumTraces = 1
+	report, err := NewMigrator(options, source, destination, destination, zap.NewNop()).Run(context.Background())
+	require.NoError(t, err)
+	// the first window holds two backend traces, they are looked up by two queries
+	assert.Equal(t, Report{Windows: 3, Traces: 3, Spans: 5}, report)
+	for _, traceID := range []uint64{1, 2, 3} {
+		trace, err := destination.GetTrace(context.Background(), model.NewTraceID(0, traceID))
+		require.NoError(t, err)
+		assert.NotNil(t, trace, "trace %d", traceID)
+	}
+}
+
+func TestMigrateCheckpoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jaeger-migrate")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	options := testOptions()
+	options.CheckpointFile = filepath.Join(dir, "checkpoint.json")
+	source := newSourceStore(t)
+	destination := memory.NewStore()
+
+	// the migration is interrupted after the first window
+	ctx, cancel := context.WithCancel(context.Background())
+	writer := &cancellingWriter{Writer: destination, cancel: cancel}
+	report, err := NewMigrator(options, source, writer, destination, zap.NewNop()).Run(ctx)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, Report{Windows: 1, Traces: 3, Spans: 5}, report)
+
+	report, err = NewMigrator(options, source, destination, destination, zap.NewNop()).Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, Report{Windows: 3, Traces: 4, Spans: 6}, report)
+
+	// the migration is complete, running it again does nothing
+	report, err = NewMigrator(options, source, destination, destination, zap.NewNop()).Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, Report{Windows: 3, Traces: 4, Spans: 6}, report)
+
+	options.EndTime = options.EndTime.Add(time.Hour)
+	_, err = NewMigrator(options, source, destination, destination, zap.NewNop()).Run(context.Background())
+	assert.Contains(t, err.Error(), "the checkpoint file is for the migration from")
+
+	require.NoError(t, ioutil.WriteFile(options.CheckpointFile, []byte("{"), 0600))
+	_, err = NewMigrator(options, source, destination, destination, zap.NewNop()).Run(context.Background())
+	assert.Contains(t, err.Error(), "failed to parse the checkpoint file")
+
+	options.CheckpointFile = dir
+	_, err = NewMigrator(options, source, destination, destination, zap.NewNop()).Run(context.Background())
+	assert.Contains(t, err.Error(), "failed to read the checkpoint file")
+
+	options.CheckpointFile = filepath.Join(dir, "missing", "checkpoint.json")
+	_, err = NewMigrator(options, source, destination, destination, zap.NewNop()).Run(context.Background())
+	assert.Contains(t, err.Error(), "failed to write the checkpoint file")
+}
+
+func TestMigrateCheckpointRetriesFailedTraces(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jaeger-migrate")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	options := testOptions()
+	options.CheckpointFile = filepath.Join(dir, "checkpoint.json")
+	options.Concurrency = 1
+	source := &contextReader{Reader: newSourceStore(t)}
+	destination := memory.NewStore()
+
+	// the migration is interrupted while migrating the first trace, the other traces of the window fail
+	ctx, cancel := context.WithCancel(context.Background())
+	writer := &cancellingWriter{Writer: destination, cancel: cancel}
+	report, err := NewMigrator(options, source, writer, destination, zap.NewNop()).Run(ctx)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, report.Windows)
+	assert.Equal(t, 1, report.Traces)
+	assert.Equal(t, 2, report.Failed)
+
+	report, err = NewMigrator(options, source, destination, destination, zap.NewNop()).Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, Report{Windows: 3, Traces: 4, Spans: 6}, report)
+	for traceID, spans := range map[uint64]int{1: 2, 2: 1, 3: 2, 4: 1} {
+		trace, err := destination.GetTrace(context.Background(), model.NewTraceID(0, traceID))
+		require.NoError(t, err)
+		assert.Len(t, trace.Spans, spans, "trace %d", traceID)
+	}
+}
+
+// contextReader fails to read traces once the context is cancelled, like the Cassandra and Elasticsearch readers
+type contextReader struct {
+	spanstore.Reader
+}
+
+func (r *contextReader) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.Reader.GetTrace(ctx, traceID)
+}
+
+// cancellingWriter cancels the context once it has written a span, Run stops after the current window
+type cancellingWriter struct {
+	spanstore.Writer
+	cancel context.CancelFunc
+}
+
+func (w *cancellingWriter) WriteSpan(span *model.Span) error {
+	w.cancel()
+	return w.Writer.WriteSpan(span)
+}
+
+func TestMigrateFailures(t *testing.T) {
+	traceID := model.NewTraceID(0, 1)
+	trace := &model.Trace{Spans: []*model.Span{testSpan(1, 1, "frontend", migrationStart)}}
+	testCases := []struct {
+		name           string
+		setup          func(source *mocks.Reader, writer *mocks.Writer, destination *mocks.Reader)
+		expectedReport Report
+		expectedError  string
+	}{
+		{
+			name: "services",
+			setup: func(source *mocks.Reader, writer *mocks.Writer, destination *mocks.Reader) {
+				source.On("GetServices", mock.Anything).Return(nil, errors.New("no services"))
+			},
+			expectedError: "no services",
+		},
+		{
+			name: "trace IDs",
+			setup: func(source *mocks.Reader, writer *mocks.Writer, destination *mocks.Reader) {
+				source.On("GetServices", mock.Anything).Return([]string{"frontend"}, nil)
+				source.On("FindTraceIDs", mock.Anything, mock.Anything).Return(nil, errors.New("no trace IDs"))
+			},
+			expectedError: "no trace IDs",
+		},
+		{
+			name: "trace",
+			setup: func(source *mocks.Reader, writer *mocks.Writer, destination *mocks.Reader) {
+				source.On("GetServices", mock.Anything).Return([]string{"frontend"}, nil)
+				source.On("FindTraceIDs", mock.Anything, mock.Anything).Return([]model.TraceID{traceID}, nil)
+				source.On("GetTrace", mock.Anything, traceID).Return(nil, errors.New("no trace"))
+			},
+			expectedReport: Report{Windows: 3, Failed: 1},
+		},
+		{
+			name: "missing trace",
+			setup: func(source *mocks.Reader, writer *mocks.Writer, destination *mocks.Reader) {
+				source.On("GetServices", mock.Anything).Return([]string{"frontend"}, nil)
+				source.On("FindTraceIDs", mock.Anything, mock.Anything).Return([]model.TraceID{traceID}, nil)
+				source.On("GetTrace", mock.Anything, traceID).Return(nil, nil)
+			},
+			expectedReport: Report{Windows: 3, Failed: 1},
+		},
+		{
+			name: "write",
+			setup: func(source *mocks.Reader, writer *mocks.Writer, destination *mocks.Reader) {
+				source.On("GetServices", mock.Anything).Return([]string{"frontend"}, nil)
+				source.On("FindTraceIDs", mock.Anything, mock.Anything).Return([]model.TraceID{traceID}, nil)
+				source.On("GetTrace", mock.Anything, traceID).Return(trace, nil)
+				writer.On("WriteSpan", mock.Anything).Return(errors.New("no write"))
+			},
+			expectedReport: Report{Windows: 3, Failed: 1},
+		},
+		{
+			name: "mismatch",
+			setup: func(source *mocks.Reader, writer *mocks.Writer, destination *mocks.Reader) {
+				source.On("GetServices", mock.Anything).Return([]string{"frontend"}, nil)
+				source.On("FindTraceIDs", mock.Anything, mock.Anything).Return([]model.TraceID{traceID}, nil)
+				source.On("GetTrace", mock.Anything, traceID).Return(trace, nil)
+				writer.On("WriteSpan", mock.Anything).Return(nil)
+				destination.On("GetTrace", mock.Anything, traceID).Return(&model.Trace{}, nil)
+			},
+			expectedReport: Report{Windows: 3, Traces: 1, Spans: 1, Mismatches: 1},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			source, writer, destination := &mocks.Reader{}, &mocks.Writer{}, &mocks.Reader{}
+			testCase.setup(source, writer, destination)
+			report, err := NewMigrator(testOptions(), source, writer, destination, zap.NewNop()).Run(context.Background())
+			if testCase.expectedError != "" {
+				assert.EqualError(t, err, testCase.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, testCase.expectedReport, report)
+		})
+	}
+}
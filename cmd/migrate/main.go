@@ -0,0 +1,177 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/flags"
+	"github.com/jaegertracing/jaeger/cmd/migrate/app"
+	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/version"
+	"github.com/jaegertracing/jaeger/plugin/storage"
+)
+
+const (
+	sourceStorageTypeEnvVar      = "SOURCE_STORAGE_TYPE"
+	destinationStorageTypeEnvVar = "DESTINATION_STORAGE_TYPE"
+)
+
+func main() {
+	sourceType, destinationType := os.Getenv(sourceStorageTypeEnvVar), os.Getenv(destinationStorageTypeEnvVar)
+	if sourceType == "" || destinationType == "" {
+		fmt.Printf("%s and %s must be set to the storage types to migrate from and to\n",
+			sourceStorageTypeEnvVar, destinationStorageTypeEnvVar)
+		os.Exit(1)
+	}
+	if sourceType == destinationType {
+		// both storage factories read the same flags
+		fmt.Println("The source and destination storage types must differ")
+		os.Exit(1)
+	}
+	sourceFactory, err := storage.NewFactory(factoryConfig(sourceType))
+	if err != nil {
+		fmt.Printf("Cannot initialize source storage factory: %v\n", err)
+		os.Exit(1)
+	}
+	destinationFactory, err := storage.NewFactory(factoryConfig(destinationType))
+	if err != nil {
+		fmt.Printf("Cannot initialize destination storage factory: %v\n", err)
+		os.Exit(1)
+	}
+
+	v := viper.New()
+	command := &cobra.Command{
+		Use:   "jaeger-migrate",
+		Short: "Jaeger migrate copies the traces of a storage to another one.",
+		Long: `Jaeger migrate copies the traces of the ` + sourceStorageTypeEnvVar + ` storage to the ` +
+			destinationStorageTypeEnvVar + ` storage, time window by time window and service by service.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := flags.TryLoadConfigFile(v); err != nil {
+				return err
+			}
+			logger, err := new(flags.SharedFlags).InitFromViper(v).NewLogger(zap.NewProductionConfig())
+			if err != nil {
+				return err
+			}
+			options := app.Options{}
+			if err := options.InitFromViper(v); err != nil {
+				return err
+			}
+
+			sourceFactory.InitFromViper(v)
+			if err := sourceFactory.Initialize(metrics.NullFactory, logger); err != nil {
+				logger.Fatal("Failed to init source storage factory", zap.Error(err))
+			}
+			destinationFactory.InitFromViper(v)
+			if err := destinationFactory.Initialize(metrics.NullFactory, logger); err != nil {
+				logger.Fatal("Failed to init destination storage factory", zap.Error(err))
+			}
+			source, err := sourceFactory.CreateSpanReader()
+			if err != nil {
+				logger.Fatal("Failed to create source span reader", zap.Error(err))
+			}
+			destination, err := destinationFactory.CreateSpanWriter()
+			if err != nil {
+				logger.Fatal("Failed to create destination span writer", zap.Error(err))
+			}
+			destinationReader, err := destinationFactory.CreateSpanReader()
+			if err != nil {
+				logger.Fatal("Failed to create destination span reader", zap.Error(err))
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			signals := make(chan os.Signal, 1)
+			signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+			go func() {
+				<-signals
+				logger.Info("Stopping the migration after the current window")
+				cancel()
+			}()
+
+			report, err := app.NewMigrator(options, source, destination, destinationReader, logger).Run(ctx)
+			if closer, ok := destination.(io.Closer); ok {
+				if err := closer.Close(); err != nil {
+					logger.Error("Failed to close destination span writer", zap.Error(err))
+				}
+			}
+			logger.Info("Migration report",
+				zap.Int("windows", report.Windows),
+				zap.Int("traces", report.Traces),
+				zap.Int("spans", report.Spans),
+				zap.Int("failed", report.Failed),
+				zap.Int("mismatches", report.Mismatches))
+			if err != nil {
+				return err
+			}
+			if report.Failed > 0 || report.Mismatches > 0 {
+				return errors.New("some traces were not migrated")
+			}
+			return nil
+		},
+	}
+
+	command.AddCommand(version.Command())
+
+	config.AddFlags(
+		v,
+		command,
+		flags.AddConfigFileFlag,
+		flags.AddFlags,
+		addStorageFlags(sourceFactory, destinationFactory),
+		app.AddFlags,
+	)
+
+	if err := command.Execute(); err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+func factoryConfig(storageType string) storage.FactoryConfig {
+	return storage.FactoryConfig{
+		SpanWriterTypes:         []string{storageType},
+		SpanReaderType:          storageType,
+		DependenciesStorageType: storageType,
+	}
+}
+
+// addStorageFlags adds the flags of the storage factories, the flags they have in common only once
+func addStorageFlags(factories ...*storage.Factory) func(flagSet *flag.FlagSet) {
+	return func(flagSet *flag.FlagSet) {
+		for _, factory := range factories {
+			factoryFlags := new(flag.FlagSet)
+			factory.AddFlags(factoryFlags)
+			factoryFlags.VisitAll(func(f *flag.Flag) {
+				if flagSet.Lookup(f.Name) == nil {
+					flagSet.Var(f.Value, f.Name, f.Usage)
+				}
+			})
+		}
+	}
+}
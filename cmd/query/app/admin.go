@@ -0,0 +1,81 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/auth"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+)
+
+// errAdminRequired is returned when a request to an admin endpoint is not made by an admin principal
+var errAdminRequired = errors.New("an admin principal is required")
+
+// adminGuard restricts the admin endpoints of the HTTP and gRPC APIs to the admin principals,
+// and writes an audit log entry for every request made to them.
+type adminGuard struct {
+	principals map[string]bool
+	logger     *zap.Logger
+}
+
+func newAdminGuard(principals []string, logger *zap.Logger) *adminGuard {
+	g := &adminGuard{
+		principals: make(map[string]bool, len(principals)),
+		logger:     logger.Named("audit"),
+	}
+	for _, principal := range principals {
+		g.principals[principal] = true
+	}
+	return g
+}
+
+// authorize returns the principal making the request, and errAdminRequired if it is not an admin.
+// Denied requests are audited.
+func (g *adminGuard) authorize(ctx context.Context, action string, fields ...zap.Field) (string, error) {
+	principal, _ := auth.PrincipalFromContext(ctx)
+	if principal == "" || !g.principals[principal] {
+		g.logger.Warn("Admin request denied", append(g.fields(ctx, principal, action), fields...)...)
+		return principal, errAdminRequired
+	}
+	return principal, nil
+}
+
+// audit records the outcome of an admin request and the traces it deleted.
+func (g *adminGuard) audit(ctx context.Context, principal, action string, traceIDs []model.TraceID, err error, fields ...zap.Field) {
+	ids := make([]string, len(traceIDs))
+	for i, traceID := range traceIDs {
+		ids[i] = traceID.String()
+	}
+	fields = append(g.fields(ctx, principal, action), append(fields, zap.Strings("trace_ids", ids))...)
+	if err != nil {
+		g.logger.Error("Admin request failed", append(fields, zap.Error(err))...)
+		return
+	}
+	g.logger.Info("Admin request succeeded", fields...)
+}
+
+// fields returns the fields identifying the request, including the tenant whose traces it deletes, if any.
+func (g *adminGuard) fields(ctx context.Context, principal, action string) []zap.Field {
+	fields := []zap.Field{zap.String("principal", principal), zap.String("action", action)}
+	if tenant := tenancy.GetTenant(ctx); tenant != "" {
+		fields = append(fields, zap.String("tenant", tenant))
+	}
+	return fields
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/model/converter/json/zipkin"
+	"github.com/jaegertracing/jaeger/swagger-gen/models"
+)
+
+const (
+	formatParam = "format"
+
+	// uiFormat is the default format, the JSON data model of the Jaeger UI
+	uiFormat = "json"
+	// zipkinFormat is the Zipkin v2 JSON data model, as accepted by the Zipkin /api/v2/spans endpoint
+	zipkinFormat = "zipkin"
+	// protobufFormat is a single model.Batch encoded as protobuf, with the process set on every span
+	protobufFormat = "protobuf"
+
+	protobufContentType = "application/x-protobuf"
+)
+
+// parseFormat returns the value of the format parameter, defaulting to uiFormat.
+func (aH *APIHandler) parseFormat(w http.ResponseWriter, r *http.Request) (string, bool) {
+	switch format := r.FormValue(formatParam); format {
+	case "", uiFormat:
+		return uiFormat, true
+	case zipkinFormat, protobufFormat:
+		return format, true
+	default:
+		err := fmt.Errorf("unsupported format '%s', expecting one of '%s', '%s' or '%s'", format, uiFormat, zipkinFormat, protobufFormat)
+		aH.handleError(w, err, http.StatusBadRequest)
+		return "", false
+	}
+}
+
+// writeExport writes the traces in one of the export formats.
+func (aH *APIHandler) writeExport(w http.ResponseWriter, r *http.Request, traces []*model.Trace, format string, adjust bool) {
+	if adjust {
+		for i, trace := range traces {
+			// adjusters always return a usable trace, their errors can only be reported in the UI format
+			traces[i], _ = aH.queryService.Adjust(trace)
+		}
+	}
+	switch format {
+	case zipkinFormat:
+		spans := models.ListOfSpans{}
+		for _, trace := range traces {
+			spans = append(spans, zipkin.FromDomain(trace)...)
+		}
+		aH.writeJSON(w, r, spans)
+	case protobufFormat:
+		batch := &model.Batch{}
+		for _, trace := range traces {
+			batch.Spans = append(batch.Spans, trace.Spans...)
+		}
+		data, err := batch.Marshal()
+		if aH.handleError(w, err, http.StatusInternalServerError) {
+			return
+		}
+		w.Header().Set("Content-Type", protobufContentType)
+		w.Write(data)
+	}
+}
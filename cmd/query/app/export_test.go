@@ -0,0 +1,115 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/model/adjuster"
+	"github.com/jaegertracing/jaeger/swagger-gen/models"
+)
+
+func TestGetTraceZipkinFormat(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+		Return(mockTrace, nil).Once()
+
+	var spans models.ListOfSpans
+	err := getJSON(server.URL+`/api/traces/123456?format=zipkin`, &spans)
+	require.NoError(t, err)
+	require.Len(t, spans, 2)
+	assert.Equal(t, "000000000001e240", *spans[0].TraceID)
+	assert.Equal(t, "0000000000000001", *spans[0].ID)
+	assert.Equal(t, "0000000000000002", *spans[1].ID)
+}
+
+func TestGetTraceProtobufFormat(t *testing.T) {
+	server, readMock, _, _ := initializeTestServerWithHandler(
+		querysvc.QueryServiceOptions{
+			Adjuster: adjuster.Func(func(trace *model.Trace) (*model.Trace, error) {
+				return trace, errAdjustment
+			}),
+		},
+	)
+	defer server.Close()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+		Return(mockTrace, nil).Once()
+
+	resp, err := http.Get(server.URL + `/api/traces/123456?format=protobuf`)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, protobufContentType, resp.Header.Get("Content-Type"))
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	var batch model.Batch
+	require.NoError(t, batch.Unmarshal(body))
+	assert.Nil(t, batch.Process)
+	require.Len(t, batch.Spans, 2)
+	assert.Equal(t, mockTraceID, batch.Spans[0].TraceID)
+	assert.Equal(t, model.NewSpanID(2), batch.Spans[1].SpanID)
+}
+
+func TestSearchZipkinFormat(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+		Return(mockTrace, nil).Twice()
+
+	var spans models.ListOfSpans
+	err := getJSON(server.URL+`/api/traces?traceID=1&traceID=2&format=zipkin`, &spans)
+	require.NoError(t, err)
+	assert.Len(t, spans, 4)
+}
+
+func TestSearchProtobufFormat(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+	readMock.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{mockTrace}, nil).Once()
+
+	resp, err := http.Get(server.URL + `/api/traces?service=service&format=protobuf`)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	var batch model.Batch
+	require.NoError(t, batch.Unmarshal(body))
+	assert.Len(t, batch.Spans, 2)
+}
+
+func TestUnsupportedFormat(t *testing.T) {
+	server, _, _ := initializeTestServer()
+	defer server.Close()
+
+	for _, url := range []string{`/api/traces/123456?format=xml`, `/api/traces?service=service&format=xml`} {
+		var response structuredResponse
+		err := getJSON(server.URL+url, &response)
+		assert.EqualError(t, err,
+			parsedError(400, "unsupported format 'xml', expecting one of 'json', 'zipkin' or 'protobuf'"), url)
+	}
+}
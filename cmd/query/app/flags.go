@@ -16,6 +16,7 @@ package app
 
 import (
 	"flag"
+	"strings"
 
 	"github.com/spf13/viper"
 
@@ -27,6 +28,14 @@ const (
 	queryBasePath    = "query.base-path"
 	queryStaticFiles = "query.static-files"
 	queryUIConfig    = "query.ui-config"
+
+	queryTLS              = "query.tls"
+	queryTLSCert          = "query.tls.cert"
+	queryTLSKey           = "query.tls.key"
+	queryTLSClientCA      = "query.tls.client-ca"
+	queryBearerTokensFile = "query.auth.bearer-tokens-file"
+	queryHtpasswdFile     = "query.auth.htpasswd-file"
+	queryAdminPrincipals  = "query.auth.admin-principals"
 )
 
 // QueryOptions holds configuration for query service
@@ -39,6 +48,20 @@ type QueryOptions struct {
 	StaticAssets string
 	// UIConfig is the path to a configuration file for the UI
 	UIConfig string
+	// TLS defines if the HTTP and gRPC endpoints are served over TLS
+	TLS bool
+	// TLSCert is the path to a TLS certificate file for the server
+	TLSCert string
+	// TLSKey is the path to a TLS key file for the server
+	TLSKey string
+	// TLSClientCA is the path to a CA file used to verify client certificates; enables mutual TLS
+	TLSClientCA string
+	// BearerTokensFile is the path to a file with the bearer tokens accepted by the API
+	BearerTokensFile string
+	// HtpasswdFile is the path to an htpasswd file with the users accepted by the API
+	HtpasswdFile string
+	// AdminPrincipals lists the authenticated principals allowed to call the admin endpoints, e.g. to delete traces
+	AdminPrincipals []string
 }
 
 // AddFlags adds flags for QueryOptions
@@ -47,6 +70,13 @@ func AddFlags(flagSet *flag.FlagSet) {
 	flagSet.String(queryBasePath, "/", "The base path for all HTTP routes, e.g. /jaeger; useful when running behind a reverse proxy")
 	flagSet.String(queryStaticFiles, "", "The directory path override for the static assets for the UI")
 	flagSet.String(queryUIConfig, "", "The path to the UI configuration file in JSON format")
+	flagSet.Bool(queryTLS, false, "Enable TLS for the HTTP and gRPC endpoints")
+	flagSet.String(queryTLSCert, "", "Path to TLS certificate file")
+	flagSet.String(queryTLSKey, "", "Path to TLS key file")
+	flagSet.String(queryTLSClientCA, "", "Path to a TLS CA file used to verify client certificates; when set, clients must present a valid certificate")
+	flagSet.String(queryBearerTokensFile, "", "Path to a file with one bearer token per line, optionally prefixed with '<principal>:'; when set, API requests must carry one of the tokens")
+	flagSet.String(queryHtpasswdFile, "", "Path to an htpasswd file with bcrypt or SHA-1 hashed passwords; when set, API requests must carry the basic auth credentials of one of the users")
+	flagSet.String(queryAdminPrincipals, "", "Comma-separated list of the authenticated principals allowed to delete traces; requires a bearer tokens or htpasswd file")
 }
 
 // InitFromViper initializes QueryOptions with properties from viper
@@ -55,5 +85,17 @@ func (qOpts *QueryOptions) InitFromViper(v *viper.Viper) *QueryOptions {
 	qOpts.BasePath = v.GetString(queryBasePath)
 	qOpts.StaticAssets = v.GetString(queryStaticFiles)
 	qOpts.UIConfig = v.GetString(queryUIConfig)
+	qOpts.TLS = v.GetBool(queryTLS)
+	qOpts.TLSCert = v.GetString(queryTLSCert)
+	qOpts.TLSKey = v.GetString(queryTLSKey)
+	qOpts.TLSClientCA = v.GetString(queryTLSClientCA)
+	qOpts.BearerTokensFile = v.GetString(queryBearerTokensFile)
+	qOpts.HtpasswdFile = v.GetString(queryHtpasswdFile)
+	qOpts.AdminPrincipals = nil
+	for _, principal := range strings.Split(v.GetString(queryAdminPrincipals), ",") {
+		if principal = strings.TrimSpace(principal); principal != "" {
+			qOpts.AdminPrincipals = append(qOpts.AdminPrincipals, principal)
+		}
+	}
 	return qOpts
 }
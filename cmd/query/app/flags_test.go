@@ -29,10 +29,24 @@ func TestQueryBuilderFlags(t *testing.T) {
 		"--query.ui-config=some.json",
 		"--query.base-path=/jaeger",
 		"--query.port=80",
+		"--query.tls=true",
+		"--query.tls.cert=cert.pem",
+		"--query.tls.key=key.pem",
+		"--query.tls.client-ca=ca.pem",
+		"--query.auth.bearer-tokens-file=tokens",
+		"--query.auth.htpasswd-file=htpasswd",
+		"--query.auth.admin-principals=alice, bob,",
 	})
 	qOpts := new(QueryOptions).InitFromViper(v)
 	assert.Equal(t, "/dev/null", qOpts.StaticAssets)
 	assert.Equal(t, "some.json", qOpts.UIConfig)
 	assert.Equal(t, "/jaeger", qOpts.BasePath)
 	assert.Equal(t, 80, qOpts.Port)
+	assert.True(t, qOpts.TLS)
+	assert.Equal(t, "cert.pem", qOpts.TLSCert)
+	assert.Equal(t, "key.pem", qOpts.TLSKey)
+	assert.Equal(t, "ca.pem", qOpts.TLSClientCA)
+	assert.Equal(t, "tokens", qOpts.BearerTokensFile)
+	assert.Equal(t, "htpasswd", qOpts.HtpasswdFile)
+	assert.Equal(t, []string{"alice", "bob"}, qOpts.AdminPrincipals)
 }
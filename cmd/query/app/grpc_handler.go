@@ -18,7 +18,10 @@ import (
 	"context"
 
 	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/model"
@@ -33,6 +36,8 @@ type GRPCHandler struct {
 	queryService *querysvc.QueryService
 	logger       *zap.Logger
 	tracer       opentracing.Tracer
+	// admin denies the admin methods to all principals, unless the server sets the admin principals
+	admin *adminGuard
 }
 
 // NewGRPCHandler returns a GRPCHandler
@@ -41,6 +46,7 @@ func NewGRPCHandler(queryService *querysvc.QueryService, logger *zap.Logger, tra
 		queryService: queryService,
 		logger:       logger,
 		tracer:       tracer,
+		admin:        newAdminGuard(nil, logger),
 	}
 
 	return gH
@@ -77,8 +83,58 @@ func (g *GRPCHandler) ArchiveTrace(ctx context.Context, r *api_v2.ArchiveTraceRe
 
 // FindTraces is the GRPC handler to fetch traces based on TraceQueryParameters.
 func (g *GRPCHandler) FindTraces(r *api_v2.FindTracesRequest, stream api_v2.QueryService_FindTracesServer) error {
-	query := r.GetQuery()
-	queryParams := spanstore.TraceQueryParameters{
+	queryParams := toTraceQueryParameters(r.GetQuery())
+	if r.ContinuationToken != "" {
+		token, err := spanstore.ParseContinuationToken(r.ContinuationToken)
+		if err != nil {
+			g.logger.Error("Invalid continuation token", zap.Error(err))
+			return err
+		}
+		// the pages must be computed against the search window of the first page
+		if err := queryParams.ApplyContinuationToken(token); err != nil {
+			g.logger.Error("Invalid continuation token", zap.Error(err))
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	traces, err := g.queryService.FindTraces(stream.Context(), &queryParams)
+	if err != nil {
+		g.logger.Error("Error fetching traces", zap.Error(err))
+		return toSearchStatusError(err)
+	}
+	for _, trace := range traces {
+		if err := g.sendSpanChunks(trace.Spans, stream.Send); err != nil {
+			return err
+		}
+	}
+	if next := spanstore.NextContinuationToken(&queryParams, len(traces)); next != nil {
+		// trailing chunk without spans, so that clients only reading the spans are not affected
+		if err := stream.Send(&api_v2.SpansResponseChunk{NextContinuationToken: next.String()}); err != nil {
+			g.logger.Error("failed to send response to client", zap.Error(err))
+			return err
+		}
+	}
+	return nil
+}
+
+// FindSpans is the GRPC handler to fetch the individual spans matching TraceQueryParameters.
+func (g *GRPCHandler) FindSpans(r *api_v2.FindSpansRequest, stream api_v2.QueryService_FindSpansServer) error {
+	if r.GetQuery() == nil {
+		return status.Error(codes.InvalidArgument, "missing query")
+	}
+	queryParams := toTraceQueryParameters(r.GetQuery())
+	spans, err := g.queryService.FindSpans(stream.Context(), &queryParams)
+	if err == spanstore.ErrNotSupported {
+		return status.Error(codes.Unimplemented, err.Error())
+	}
+	if err != nil {
+		g.logger.Error("Error fetching spans", zap.Error(err))
+		return toSearchStatusError(err)
+	}
+	return g.sendSpanChunks(spans, stream.Send)
+}
+
+func toTraceQueryParameters(query *api_v2.TraceQueryParameters) spanstore.TraceQueryParameters {
+	return spanstore.TraceQueryParameters{
 		ServiceName:   query.ServiceName,
 		OperationName: query.OperationName,
 		Tags:          query.Tags,
@@ -87,18 +143,33 @@ func (g *GRPCHandler) FindTraces(r *api_v2.FindTracesRequest, stream api_v2.Quer
 		DurationMin:   query.DurationMin,
 		DurationMax:   query.DurationMax,
 		NumTraces:     int(query.SearchDepth),
+		TagFilters:    toTagFilters(query.TagFilters),
 	}
-	traces, err := g.queryService.FindTraces(stream.Context(), &queryParams)
-	if err != nil {
-		g.logger.Error("Error fetching traces", zap.Error(err))
-		return err
+}
+
+func toTagFilters(filters []api_v2.TagFilter) []spanstore.TagFilter {
+	if len(filters) == 0 {
+		return nil
 	}
-	for _, trace := range traces {
-		if err := g.sendSpanChunks(trace.Spans, stream.Send); err != nil {
-			return err
+	tagFilters := make([]spanstore.TagFilter, len(filters))
+	for i, f := range filters {
+		tagFilters[i] = spanstore.TagFilter{
+			Key:      f.Key,
+			Operator: spanstore.TagFilterOperator(f.Operator),
+			Value:    f.Value,
 		}
 	}
-	return nil
+	return tagFilters
+}
+
+// toSearchStatusError reports the tag filters the storage backend cannot evaluate as invalid arguments.
+func toSearchStatusError(err error) error {
+	switch errors.Cause(err) {
+	case spanstore.ErrInvalidTagFilter, spanstore.ErrTagFilterNotSupported:
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return err
+	}
 }
 
 func (g *GRPCHandler) sendSpanChunks(spans []*model.Span, sendFn func(*api_v2.SpansResponseChunk) error) error {
@@ -143,7 +214,7 @@ func (g *GRPCHandler) GetOperations(ctx context.Context, r *api_v2.GetOperations
 func (g *GRPCHandler) GetDependencies(ctx context.Context, r *api_v2.GetDependenciesRequest) (*api_v2.GetDependenciesResponse, error) {
 	startTime := r.StartTime
 	endTime := r.EndTime
-	dependencies, err := g.queryService.GetDependencies(startTime, endTime.Sub(startTime))
+	dependencies, err := g.queryService.GetDependencies(ctx, startTime, endTime.Sub(startTime))
 	if err != nil {
 		g.logger.Error("Error fetching dependencies", zap.Error(err))
 		return nil, err
@@ -151,3 +222,98 @@ func (g *GRPCHandler) GetDependencies(ctx context.Context, r *api_v2.GetDependen
 
 	return &api_v2.GetDependenciesResponse{Dependencies: dependencies}, nil
 }
+
+// CompareTraces is the GRPC handler to compare two traces aligned by service+operation path.
+func (g *GRPCHandler) CompareTraces(ctx context.Context, r *api_v2.CompareTracesRequest) (*api_v2.CompareTracesResponse, error) {
+	diff, err := g.queryService.CompareTraces(ctx, r.TraceIDA, r.TraceIDB)
+	if err == spanstore.ErrTraceNotFound {
+		g.logger.Error("trace not found", zap.Error(err))
+		return nil, err
+	}
+	if err != nil {
+		g.logger.Error("Could not compare traces", zap.Error(err))
+		return nil, err
+	}
+
+	nodes := make([]api_v2.TraceDiffNode, len(diff.Nodes))
+	for i, node := range diff.Nodes {
+		nodes[i] = api_v2.TraceDiffNode{
+			Path:          node.Path,
+			ServiceName:   node.ServiceName,
+			OperationName: node.OperationName,
+			Status:        diffStatusToProto(node.Status),
+			SpanIDA:       node.SpanIDA,
+			SpanIDB:       node.SpanIDB,
+			DurationA:     node.DurationA,
+			DurationB:     node.DurationB,
+			DurationDelta: node.DurationDelta,
+			TagDiffs:      make([]api_v2.TagDiff, len(node.TagDiffs)),
+		}
+		for j, tagDiff := range node.TagDiffs {
+			nodes[i].TagDiffs[j] = api_v2.TagDiff{
+				Key:    tagDiff.Key,
+				ValueA: tagDiff.ValueA,
+				ValueB: tagDiff.ValueB,
+			}
+		}
+	}
+	return &api_v2.CompareTracesResponse{Nodes: nodes}, nil
+}
+
+// DeleteTrace is the GRPC handler to delete a trace, restricted to admin principals.
+func (g *GRPCHandler) DeleteTrace(ctx context.Context, r *api_v2.DeleteTraceRequest) (*api_v2.DeleteTraceResponse, error) {
+	traceIDField := zap.String("trace_id", r.TraceID.String())
+	principal, err := g.admin.authorize(ctx, deleteTraceAction, traceIDField)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	var deleted []model.TraceID
+	err = g.queryService.DeleteTrace(ctx, r.TraceID)
+	if err == nil {
+		deleted = append(deleted, r.TraceID)
+	}
+	g.admin.audit(ctx, principal, deleteTraceAction, deleted, err, traceIDField)
+	if err != nil {
+		return nil, toDeleteStatusError(err)
+	}
+	return &api_v2.DeleteTraceResponse{}, nil
+}
+
+// DeleteServiceTraces is the GRPC handler to delete the traces of a service within a time range,
+// restricted to admin principals.
+func (g *GRPCHandler) DeleteServiceTraces(ctx context.Context, r *api_v2.DeleteServiceTracesRequest) (*api_v2.DeleteServiceTracesResponse, error) {
+	if r.Service == "" || r.StartTimeMin.IsZero() || r.StartTimeMax.IsZero() {
+		return nil, status.Error(codes.InvalidArgument, "service, start_time_min and start_time_max are required")
+	}
+	fields := []zap.Field{zap.String("service", r.Service), zap.Time("start", r.StartTimeMin), zap.Time("end", r.StartTimeMax)}
+	principal, err := g.admin.authorize(ctx, deleteServiceTracesAction, fields...)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	deleted, err := g.queryService.DeleteServiceTraces(ctx, r.Service, r.StartTimeMin, r.StartTimeMax)
+	g.admin.audit(ctx, principal, deleteServiceTracesAction, deleted, err, fields...)
+	if err != nil {
+		return nil, toDeleteStatusError(err)
+	}
+	return &api_v2.DeleteServiceTracesResponse{TraceIDs: deleted}, nil
+}
+
+func toDeleteStatusError(err error) error {
+	if err == querysvc.ErrDeleteNotSupported {
+		return status.Error(codes.Unimplemented, err.Error())
+	}
+	return err
+}
+
+func diffStatusToProto(status querysvc.DiffStatus) api_v2.TraceDiffNode_Status {
+	switch status {
+	case querysvc.DiffStatusAdded:
+		return api_v2.TraceDiffNode_ADDED
+	case querysvc.DiffStatusRemoved:
+		return api_v2.TraceDiffNode_REMOVED
+	default:
+		return api_v2.TraceDiffNode_COMMON
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"testing"
 	"time"
@@ -29,9 +30,11 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/auth"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
 	depsmocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
@@ -334,6 +337,74 @@ func TestSearchSuccessGRPC(t *testing.T) {
 	})
 }
 
+func TestSearchWithContinuationTokenGRPC(t *testing.T) {
+	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
+		endTime := time.Now()
+		server.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.MatchedBy(func(q *spanstore.TraceQueryParameters) bool {
+			return q.ContinuationToken == nil
+		})).Return([]*model.Trace{mockTraceGRPC}, nil).Once()
+		server.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.MatchedBy(func(q *spanstore.TraceQueryParameters) bool {
+			return q.Offset() == 1 && q.StartTimeMax.Equal(endTime) && q.StartTimeMin.Equal(endTime.Add(-10*time.Minute))
+		})).Return([]*model.Trace{}, nil).Once()
+
+		queryParams := &api_v2.TraceQueryParameters{
+			ServiceName:  "service",
+			StartTimeMin: endTime.Add(time.Duration(-10) * time.Minute),
+			StartTimeMax: endTime,
+			SearchDepth:  1,
+		}
+		res, err := client.FindTraces(context.Background(), &api_v2.FindTracesRequest{
+			Query: queryParams,
+		})
+		require.NoError(t, err)
+
+		spanResChunk, err := res.Recv()
+		require.NoError(t, err)
+		assert.Len(t, spanResChunk.Spans, len(mockTraceGRPC.Spans))
+		assert.Empty(t, spanResChunk.NextContinuationToken)
+
+		spanResChunk, err = res.Recv()
+		require.NoError(t, err)
+		assert.Len(t, spanResChunk.Spans, 0)
+		require.NotEmpty(t, spanResChunk.NextContinuationToken)
+
+		// the search window is pinned by the token
+		queryParams.StartTimeMin = time.Now()
+		queryParams.StartTimeMax = time.Now().Add(time.Minute)
+		res, err = client.FindTraces(context.Background(), &api_v2.FindTracesRequest{
+			Query:             queryParams,
+			ContinuationToken: spanResChunk.NextContinuationToken,
+		})
+		require.NoError(t, err)
+		_, err = res.Recv()
+		assert.Equal(t, io.EOF, err)
+
+		// the token cannot be used for another query
+		queryParams.ServiceName = "other"
+		res, err = client.FindTraces(context.Background(), &api_v2.FindTracesRequest{
+			Query:             queryParams,
+			ContinuationToken: spanResChunk.NextContinuationToken,
+		})
+		require.NoError(t, err)
+		_, err = res.Recv()
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestSearchInvalidContinuationTokenGRPC(t *testing.T) {
+	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
+		res, err := client.FindTraces(context.Background(), &api_v2.FindTracesRequest{
+			Query: &api_v2.TraceQueryParameters{
+				ServiceName: "service",
+			},
+			ContinuationToken: "foo",
+		})
+		require.NoError(t, err)
+		_, err = res.Recv()
+		assert.EqualError(t, err, status.Error(2, spanstore.ErrInvalidContinuationToken.Error()).Error())
+	})
+}
+
 func TestSearchSuccess_SpanStreamingGRPC(t *testing.T) {
 	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
 
@@ -389,6 +460,103 @@ func TestSearchFailure_GRPC(t *testing.T) {
 	})
 }
 
+func TestSearchWithTagFiltersGRPC(t *testing.T) {
+	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
+		server.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.MatchedBy(func(q *spanstore.TraceQueryParameters) bool {
+			return len(q.TagFilters) == 1 && q.TagFilters[0] == spanstore.TagFilter{
+				Key:      "http.status_code",
+				Operator: spanstore.TagFilterGreaterThan,
+				Value:    "499",
+			}
+		})).Return(nil, spanstore.ErrTagFilterNotSupported).Once()
+
+		res, err := client.FindTraces(context.Background(), &api_v2.FindTracesRequest{
+			Query: &api_v2.TraceQueryParameters{
+				ServiceName: "service",
+				TagFilters: []api_v2.TagFilter{
+					{Key: "http.status_code", Operator: ">", Value: "499"},
+				},
+			},
+		})
+		require.NoError(t, err)
+		_, err = res.Recv()
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func withSpanFinderServerAndClient(t *testing.T, actualTest func(spanFinder *spanstoremocks.SpanFinder, client *grpcClient)) {
+	spanFinder := &spanstoremocks.SpanFinder{}
+	spanReader := spanFinderReader{Reader: &spanstoremocks.Reader{}, SpanFinder: spanFinder}
+	q := querysvc.NewQueryService(spanReader, &depsmocks.Reader{}, querysvc.QueryServiceOptions{})
+	server, addr := newGRPCServer(t, q, zap.NewNop(), opentracing.NoopTracer{})
+	client := newGRPCClient(t, addr)
+	defer server.Stop()
+	defer client.conn.Close()
+
+	actualTest(spanFinder, client)
+}
+
+func TestFindSpansSuccessGRPC(t *testing.T) {
+	withSpanFinderServerAndClient(t, func(spanFinder *spanstoremocks.SpanFinder, client *grpcClient) {
+		spanFinder.On("FindSpans", mock.AnythingOfType("*context.valueCtx"), mock.MatchedBy(func(q *spanstore.TraceQueryParameters) bool {
+			return q.ServiceName == "service" && q.Tags["error"] == "true" && q.NumTraces == 20
+		})).Return(mockLargeTraceGRPC.Spans, nil).Once()
+
+		res, err := client.FindSpans(context.Background(), &api_v2.FindSpansRequest{
+			Query: &api_v2.TraceQueryParameters{
+				ServiceName:  "service",
+				Tags:         map[string]string{"error": "true"},
+				StartTimeMin: time.Now().Add(time.Duration(-10) * time.Minute),
+				StartTimeMax: time.Now(),
+				SearchDepth:  20,
+			},
+		})
+		require.NoError(t, err)
+
+		spanResChunk, err := res.Recv()
+		require.NoError(t, err)
+		assert.Len(t, spanResChunk.Spans, 10)
+		assert.Equal(t, mockTraceID, spanResChunk.Spans[0].TraceID)
+
+		spanResChunk, err = res.Recv()
+		require.NoError(t, err)
+		assert.Len(t, spanResChunk.Spans, 1)
+
+		_, err = res.Recv()
+		assert.Equal(t, io.EOF, err)
+	})
+}
+
+func TestFindSpansFailureGRPC(t *testing.T) {
+	withSpanFinderServerAndClient(t, func(spanFinder *spanstoremocks.SpanFinder, client *grpcClient) {
+		spanFinder.On("FindSpans", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+			Return(nil, errStorageGRPC).Once()
+
+		res, err := client.FindSpans(context.Background(), &api_v2.FindSpansRequest{
+			Query: &api_v2.TraceQueryParameters{ServiceName: "service"},
+		})
+		require.NoError(t, err)
+		_, err = res.Recv()
+		assert.EqualError(t, err, errStatusStorageGRPC.Error())
+
+		res, err = client.FindSpans(context.Background(), &api_v2.FindSpansRequest{})
+		require.NoError(t, err)
+		_, err = res.Recv()
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestFindSpansNotSupportedGRPC(t *testing.T) {
+	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
+		res, err := client.FindSpans(context.Background(), &api_v2.FindSpansRequest{
+			Query: &api_v2.TraceQueryParameters{ServiceName: "service"},
+		})
+		require.NoError(t, err)
+		_, err = res.Recv()
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+}
+
 func TestGetServicesSuccessGRPC(t *testing.T) {
 	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
 		expectedServices := []string{"trifle", "bling"}
@@ -468,6 +636,74 @@ func TestGetDependenciesFailureGRPC(t *testing.T) {
 	})
 }
 
+func TestCompareTracesSuccessGRPC(t *testing.T) {
+	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
+		traceIDB := model.NewTraceID(0, 2)
+		traceB := &model.Trace{
+			Spans: []*model.Span{
+				{
+					TraceID:       traceIDB,
+					SpanID:        model.NewSpanID(1),
+					OperationName: "op",
+					Duration:      time.Millisecond,
+					Process:       &model.Process{ServiceName: "svc"},
+				},
+			},
+		}
+		server.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mockTraceIDgrpc).
+			Return(mockTraceGRPC, nil).Once()
+		server.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), traceIDB).
+			Return(traceB, nil).Once()
+
+		res, err := client.CompareTraces(context.Background(), &api_v2.CompareTracesRequest{
+			TraceIDA: mockTraceIDgrpc,
+			TraceIDB: traceIDB,
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Nodes, 3)
+		assert.Equal(t, api_v2.TraceDiffNode_REMOVED, res.Nodes[0].Status)
+		assert.Equal(t, model.NewSpanID(1), res.Nodes[0].SpanIDA)
+		assert.Equal(t, api_v2.TraceDiffNode_REMOVED, res.Nodes[1].Status)
+		assert.Equal(t, api_v2.TraceDiffNode_ADDED, res.Nodes[2].Status)
+		assert.Equal(t, "svc::op", res.Nodes[2].Path)
+		assert.Equal(t, time.Millisecond, res.Nodes[2].DurationDelta)
+	})
+}
+
+func TestCompareTracesNotFoundGRPC(t *testing.T) {
+	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
+		server.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+			Return(nil, spanstore.ErrTraceNotFound).Once()
+		server.archiveSpanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+			Return(nil, spanstore.ErrTraceNotFound).Once()
+
+		_, err := client.CompareTraces(context.Background(), &api_v2.CompareTracesRequest{
+			TraceIDA: mockTraceIDgrpc,
+			TraceIDB: mockTraceIDgrpc,
+		})
+		assert.Errorf(t, err, spanstore.ErrTraceNotFound.Error())
+	})
+}
+
+func TestCompareTracesFailureGRPC(t *testing.T) {
+	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
+		server.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+			Return(nil, errStorageGRPC).Once()
+
+		_, err := client.CompareTraces(context.Background(), &api_v2.CompareTracesRequest{
+			TraceIDA: mockTraceIDgrpc,
+			TraceIDB: mockTraceIDgrpc,
+		})
+		assert.EqualError(t, err, errStatusStorageGRPC.Error())
+	})
+}
+
+func TestDiffStatusToProto(t *testing.T) {
+	assert.Equal(t, api_v2.TraceDiffNode_COMMON, diffStatusToProto(querysvc.DiffStatusCommon))
+	assert.Equal(t, api_v2.TraceDiffNode_ADDED, diffStatusToProto(querysvc.DiffStatusAdded))
+	assert.Equal(t, api_v2.TraceDiffNode_REMOVED, diffStatusToProto(querysvc.DiffStatusRemoved))
+}
+
 func TestSendSpanChunksError(t *testing.T) {
 	g := &GRPCHandler{
 		logger: zap.NewNop(),
@@ -482,3 +718,58 @@ func TestSendSpanChunksError(t *testing.T) {
 	})
 	assert.EqualError(t, err, expectedErr.Error())
 }
+
+func newDeleteGRPCHandler(deleter *spanstoremocks.Deleter) *GRPCHandler {
+	options := querysvc.QueryServiceOptions{}
+	if deleter != nil {
+		options.SpanDeleter = deleter
+	}
+	q := querysvc.NewQueryService(&spanstoremocks.Reader{}, &depsmocks.Reader{}, options)
+	handler := NewGRPCHandler(q, zap.NewNop(), opentracing.NoopTracer{})
+	handler.admin = newAdminGuard([]string{"admin"}, zap.NewNop())
+	return handler
+}
+
+func TestDeleteTraceGRPC(t *testing.T) {
+	deleter := &spanstoremocks.Deleter{}
+	handler := newDeleteGRPCHandler(deleter)
+	adminCtx := auth.ContextWithPrincipal(context.Background(), "admin")
+	deleter.On("DeleteTrace", adminCtx, mockTraceIDgrpc).Return(nil).Once()
+	deleter.On("DeleteTrace", adminCtx, mockTraceIDgrpc).Return(errStorageGRPC).Once()
+
+	_, err := handler.DeleteTrace(auth.ContextWithPrincipal(context.Background(), "user"), &api_v2.DeleteTraceRequest{TraceID: mockTraceIDgrpc})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	res, err := handler.DeleteTrace(adminCtx, &api_v2.DeleteTraceRequest{TraceID: mockTraceIDgrpc})
+	assert.NoError(t, err)
+	assert.Equal(t, &api_v2.DeleteTraceResponse{}, res)
+
+	_, err = handler.DeleteTrace(adminCtx, &api_v2.DeleteTraceRequest{TraceID: mockTraceIDgrpc})
+	assert.Equal(t, errStorageGRPC, err)
+
+	_, err = newDeleteGRPCHandler(nil).DeleteTrace(adminCtx, &api_v2.DeleteTraceRequest{TraceID: mockTraceIDgrpc})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestDeleteServiceTracesGRPC(t *testing.T) {
+	deleter := &spanstoremocks.Deleter{}
+	handler := newDeleteGRPCHandler(deleter)
+	adminCtx := auth.ContextWithPrincipal(context.Background(), "admin")
+	start, end := time.Unix(100, 0), time.Unix(200, 0)
+	deleter.On("DeleteServiceTraces", adminCtx, "service", start, end).Return([]model.TraceID{mockTraceIDgrpc}, nil).Once()
+
+	_, err := handler.DeleteServiceTraces(adminCtx, &api_v2.DeleteServiceTracesRequest{Service: "service"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	request := &api_v2.DeleteServiceTracesRequest{Service: "service", StartTimeMin: start, StartTimeMax: end}
+	_, err = handler.DeleteServiceTraces(context.Background(), request)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	res, err := handler.DeleteServiceTraces(adminCtx, request)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.TraceID{mockTraceIDgrpc}, res.TraceIDs)
+
+	deleter.On("DeleteServiceTraces", adminCtx, "service", start, end).Return(nil, errStorageGRPC).Once()
+	_, err = handler.DeleteServiceTraces(adminCtx, request)
+	assert.Equal(t, errStorageGRPC, err)
+}
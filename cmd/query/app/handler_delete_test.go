@@ -0,0 +1,184 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/auth"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/pkg/testutils"
+	storagemocks "github.com/jaegertracing/jaeger/storage/mocks"
+	spanstoremocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+var deleteTestTokens = map[string]string{"admin-token": "admin", "user-token": "user"}
+
+// deleteJSON sends an HTTP DELETE request with the bearer token and parses the response as JSON
+func deleteJSON(url, token string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return execJSON(req, out)
+}
+
+func withDeleteTestServer(t *testing.T, doTest func(s *testServer, deleter *spanstoremocks.Deleter, logBuffer *testutils.Buffer)) {
+	deleter := &spanstoremocks.Deleter{}
+	logger, logBuffer := testutils.NewLogger()
+	withTestServer(t, func(s *testServer) {
+		doTest(s, deleter, logBuffer)
+	}, querysvc.QueryServiceOptions{SpanDeleter: deleter},
+		HandlerOptions.Logger(logger),
+		HandlerOptions.Authenticator(auth.NewBearerTokenAuthenticator(deleteTestTokens)),
+		HandlerOptions.AdminPrincipals([]string{"admin"}))
+}
+
+func TestDeleteTrace(t *testing.T) {
+	withDeleteTestServer(t, func(ts *testServer, deleter *spanstoremocks.Deleter, logBuffer *testutils.Buffer) {
+		deleter.On("DeleteTrace", mock.Anything, mockTraceID).Return(nil).Once()
+		url := ts.server.URL + "/api/traces/" + mockTraceID.String()
+
+		var response structuredResponse
+		err := deleteJSON(url, "", &response)
+		assert.EqualError(t, err, parsedError(http.StatusUnauthorized, "unauthenticated"))
+
+		err = deleteJSON(url, "user-token", &response)
+		assert.EqualError(t, err, parsedError(http.StatusForbidden, "an admin principal is required"))
+		assert.Contains(t, logBuffer.String(), `"msg":"Admin request denied","principal":"user","action":"delete-trace"`)
+
+		err = deleteJSON(url, "admin-token", &response)
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{mockTraceID.String()}, response.Data)
+		assert.Equal(t, 1, response.Total)
+		assert.Contains(t, logBuffer.String(),
+			`"msg":"Admin request succeeded","principal":"admin","action":"delete-trace","trace_id":"1e240","trace_ids":["1e240"]`)
+		deleter.AssertExpectations(t)
+	})
+}
+
+func TestDeleteTraceFailures(t *testing.T) {
+	withDeleteTestServer(t, func(ts *testServer, deleter *spanstoremocks.Deleter, logBuffer *testutils.Buffer) {
+		deleter.On("DeleteTrace", mock.Anything, mockTraceID).Return(errors.New("storage error")).Once()
+
+		var response structuredResponse
+		err := deleteJSON(ts.server.URL+"/api/traces/"+mockTraceID.String(), "admin-token", &response)
+		assert.EqualError(t, err, parsedError(http.StatusInternalServerError, "storage error"))
+		assert.Contains(t, logBuffer.String(),
+			`"msg":"Admin request failed","principal":"admin","action":"delete-trace","trace_id":"1e240","trace_ids":[],"error":"storage error"`)
+
+		err = deleteJSON(ts.server.URL+"/api/traces/badtraceid", "admin-token", &response)
+		assert.Error(t, err)
+	})
+
+	withTestServer(t, func(ts *testServer) {
+		var response structuredResponse
+		err := deleteJSON(ts.server.URL+"/api/traces/"+mockTraceID.String(), "admin-token", &response)
+		assert.EqualError(t, err, parsedError(http.StatusNotImplemented, querysvc.ErrDeleteNotSupported.Error()))
+	}, querysvc.QueryServiceOptions{},
+		HandlerOptions.Authenticator(auth.NewBearerTokenAuthenticator(deleteTestTokens)),
+		HandlerOptions.AdminPrincipals([]string{"admin"}))
+
+	// without admin principals nobody may delete traces
+	withTestServer(t, func(ts *testServer) {
+		var response structuredResponse
+		err := deleteJSON(ts.server.URL+"/api/traces/"+mockTraceID.String(), "", &response)
+		assert.EqualError(t, err, parsedError(http.StatusForbidden, "an admin principal is required"))
+	}, querysvc.QueryServiceOptions{SpanDeleter: &spanstoremocks.Deleter{}})
+}
+
+func TestDeleteTraceTenancy(t *testing.T) {
+	tenancyMgr, err := tenancy.NewManager(&tenancy.Options{Enabled: true, Tenants: []string{"acme"}})
+	require.NoError(t, err)
+	acmeDeleter := &spanstoremocks.Deleter{}
+	acmeDeleter.On("DeleteTrace", mock.Anything, mockTraceID).Return(nil).Once()
+	tenantFactory := &storagemocks.TenantFactory{}
+	tenantFactory.On("CreateTenantSpanDeleter", "acme").Return(acmeDeleter, nil).Once()
+	logger, logBuffer := testutils.NewLogger()
+
+	withTestServer(t, func(ts *testServer) {
+		req, err := http.NewRequest(http.MethodDelete, ts.server.URL+"/api/traces/"+mockTraceID.String(), nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer admin-token")
+		req.Header.Set(tenancyMgr.Header, "acme")
+
+		var response structuredResponse
+		require.NoError(t, execJSON(req, &response))
+		assert.Equal(t, []interface{}{mockTraceID.String()}, response.Data)
+		assert.Contains(t, logBuffer.String(),
+			`"msg":"Admin request succeeded","principal":"admin","action":"delete-trace","tenant":"acme","trace_id":"1e240"`)
+		acmeDeleter.AssertExpectations(t)
+	}, querysvc.QueryServiceOptions{TenantFactory: tenantFactory},
+		HandlerOptions.Logger(logger),
+		HandlerOptions.Tenancy(tenancyMgr),
+		HandlerOptions.Authenticator(auth.NewBearerTokenAuthenticator(deleteTestTokens)),
+		HandlerOptions.AdminPrincipals([]string{"admin"}))
+}
+
+func TestDeleteServiceTraces(t *testing.T) {
+	withDeleteTestServer(t, func(ts *testServer, deleter *spanstoremocks.Deleter, logBuffer *testutils.Buffer) {
+		start := time.Unix(0, 0).Add(1000 * time.Microsecond)
+		end := time.Unix(0, 0).Add(2000 * time.Microsecond)
+		otherTraceID := model.NewTraceID(0, 1)
+		deleter.On("DeleteServiceTraces", mock.Anything, "service", start, end).
+			Return([]model.TraceID{mockTraceID, otherTraceID}, nil).Once()
+
+		var response structuredResponse
+		err := deleteJSON(ts.server.URL+"/api/traces?service=service&start=1000&end=2000", "admin-token", &response)
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{mockTraceID.String(), otherTraceID.String()}, response.Data)
+		assert.Equal(t, 2, response.Total)
+		assert.Contains(t, logBuffer.String(), `"principal":"admin","action":"delete-service-traces","service":"service"`)
+		assert.Contains(t, logBuffer.String(), `"trace_ids":["1e240","1"]`)
+		deleter.AssertExpectations(t)
+	})
+}
+
+func TestDeleteServiceTracesFailures(t *testing.T) {
+	withDeleteTestServer(t, func(ts *testServer, deleter *spanstoremocks.Deleter, logBuffer *testutils.Buffer) {
+		deleter.On("DeleteServiceTraces", mock.Anything, "service", mock.Anything, mock.Anything).
+			Return([]model.TraceID{mockTraceID}, errors.New("storage error")).Once()
+
+		for _, testCase := range []struct {
+			query string
+			token string
+			err   string
+		}{
+			{query: "service=service&start=1000", token: "admin-token", err: parsedError(http.StatusBadRequest, errDeleteParametersRequired.Error())},
+			{query: "service=service&start=x&end=2000", token: "admin-token", err: parsedError(http.StatusBadRequest, `unable to parse start: strconv.ParseInt: parsing \"x\": invalid syntax`)},
+			{query: "service=service&start=1000&end=x", token: "admin-token", err: parsedError(http.StatusBadRequest, `unable to parse end: strconv.ParseInt: parsing \"x\": invalid syntax`)},
+			{query: "service=service&start=1000&end=2000", token: "user-token", err: parsedError(http.StatusForbidden, "an admin principal is required")},
+			{query: "service=service&start=1000&end=2000", token: "admin-token", err: parsedError(http.StatusInternalServerError, "storage error")},
+		} {
+			var response structuredResponse
+			err := deleteJSON(ts.server.URL+"/api/traces?"+testCase.query, testCase.token, &response)
+			assert.EqualError(t, err, testCase.err, testCase.query)
+		}
+		// the traces deleted before the failure are audited
+		assert.Contains(t, logBuffer.String(), `"trace_ids":["1e240"],"error":"storage error"`)
+	})
+}
@@ -19,6 +19,9 @@ import (
 
 	"github.com/opentracing/opentracing-go"
 	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/pkg/auth"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 )
 
 // HandlerOption is a function that sets some option on the APIHandler
@@ -64,3 +67,26 @@ func (handlerOptions) Tracer(tracer opentracing.Tracer) HandlerOption {
 		apiHandler.tracer = tracer
 	}
 }
+
+// Authenticator creates a HandlerOption that requires API requests to pass authentication
+func (handlerOptions) Authenticator(authenticator auth.Authenticator) HandlerOption {
+	return func(apiHandler *APIHandler) {
+		apiHandler.authenticator = authenticator
+	}
+}
+
+// Tenancy creates a HandlerOption that requires API requests to carry an accepted tenant
+// when multi-tenancy is enabled, and passes the tenant on to the query service
+func (handlerOptions) Tenancy(tenancyMgr *tenancy.Manager) HandlerOption {
+	return func(apiHandler *APIHandler) {
+		apiHandler.tenancyMgr = tenancyMgr
+	}
+}
+
+// AdminPrincipals creates a HandlerOption that allows the authenticated principals to call the admin routes,
+// e.g. to delete traces
+func (handlerOptions) AdminPrincipals(principals []string) HandlerOption {
+	return func(apiHandler *APIHandler) {
+		apiHandler.adminPrincipals = principals
+	}
+}
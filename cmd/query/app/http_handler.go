@@ -32,21 +32,31 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/model"
 	uiconv "github.com/jaegertracing/jaeger/model/converter/json"
+	"github.com/jaegertracing/jaeger/model/criticalpath"
 	ui "github.com/jaegertracing/jaeger/model/json"
+	"github.com/jaegertracing/jaeger/pkg/auth"
 	"github.com/jaegertracing/jaeger/pkg/multierror"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
 const (
 	traceIDParam  = "traceID"
+	traceIDAParam = "traceIDA"
+	traceIDBParam = "traceIDB"
 	endTsParam    = "endTs"
 	lookbackParam = "lookback"
 
 	defaultDependencyLookbackDuration = time.Hour * 24
 	defaultTraceQueryLookbackDuration = time.Hour * 24 * 2
 	defaultAPIPrefix                  = "api"
+
+	deleteTraceAction         = "delete-trace"
+	deleteServiceTracesAction = "delete-service-traces"
 )
 
+var errDeleteParametersRequired = fmt.Errorf("parameters '%s', '%s' and '%s' are required", serviceParam, startTimeParam, endTimeParam)
+
 // HTTPHandler handles http requests
 type HTTPHandler interface {
 	RegisterRoutes(router *mux.Router)
@@ -58,6 +68,8 @@ type structuredResponse struct {
 	Limit  int               `json:"limit"`
 	Offset int               `json:"offset"`
 	Errors []structuredError `json:"errors"`
+	// ContinuationToken is set when there are more results to be fetched by repeating the search with it
+	ContinuationToken string `json:"continuationToken,omitempty"`
 }
 
 type structuredError struct {
@@ -73,12 +85,17 @@ func NewRouter() *mux.Router {
 
 // APIHandler implements the query service public API by registering routes at httpPrefix
 type APIHandler struct {
-	queryService *querysvc.QueryService
-	queryParser  queryParser
-	basePath     string
-	apiPrefix    string
-	logger       *zap.Logger
-	tracer       opentracing.Tracer
+	queryService  *querysvc.QueryService
+	queryParser   queryParser
+	basePath      string
+	apiPrefix     string
+	logger        *zap.Logger
+	tracer        opentracing.Tracer
+	authenticator auth.Authenticator
+	tenancyMgr    *tenancy.Manager
+	// adminPrincipals are the principals allowed to call the admin routes, see adminGuard
+	adminPrincipals []string
+	admin           *adminGuard
 }
 
 // NewAPIHandler returns an APIHandler
@@ -103,14 +120,20 @@ func NewAPIHandler(queryService *querysvc.QueryService, options ...HandlerOption
 	if aH.tracer == nil {
 		aH.tracer = opentracing.NoopTracer{}
 	}
+	aH.admin = newAdminGuard(aH.adminPrincipals, aH.logger)
 	return aH
 }
 
 // RegisterRoutes registers routes for this handler on the given router
 func (aH *APIHandler) RegisterRoutes(router *mux.Router) {
 	aH.handleFunc(router, aH.getTrace, "/traces/{%s}", traceIDParam).Methods(http.MethodGet)
+	aH.handleFunc(router, aH.compareTraces, "/traces/{%s}/diff/{%s}", traceIDAParam, traceIDBParam).Methods(http.MethodGet)
+	aH.handleFunc(router, aH.getCriticalPath, "/traces/{%s}/critical-path", traceIDParam).Methods(http.MethodGet)
 	aH.handleFunc(router, aH.archiveTrace, "/archive/{%s}", traceIDParam).Methods(http.MethodPost)
+	aH.handleFunc(router, aH.deleteTrace, "/traces/{%s}", traceIDParam).Methods(http.MethodDelete)
+	aH.handleFunc(router, aH.deleteServiceTraces, "/traces").Methods(http.MethodDelete)
 	aH.handleFunc(router, aH.search, "/traces").Methods(http.MethodGet)
+	aH.handleFunc(router, aH.findSpans, "/spans").Methods(http.MethodGet)
 	aH.handleFunc(router, aH.getServices, "/services").Methods(http.MethodGet)
 	// TODO change the UI to use this endpoint. Requires ?service= parameter.
 	aH.handleFunc(router, aH.getOperations, "/operations").Methods(http.MethodGet)
@@ -126,9 +149,18 @@ func (aH *APIHandler) handleFunc(
 	args ...interface{},
 ) *mux.Route {
 	route = aH.route(route, args...)
+	var handler http.Handler = http.HandlerFunc(f)
+	if aH.tenancyMgr != nil {
+		handler = tenancy.ExtractTenantHTTPHandler(aH.tenancyMgr, handler)
+	}
+	if aH.authenticator != nil {
+		handler = auth.NewHTTPHandler(aH.authenticator, handler, func(w http.ResponseWriter, err error) {
+			aH.handleError(w, err, http.StatusUnauthorized)
+		})
+	}
 	traceMiddleware := nethttp.Middleware(
 		aH.tracer,
-		http.HandlerFunc(f),
+		handler,
 		nethttp.OperationNameFunc(func(r *http.Request) string {
 			return route
 		}))
@@ -186,6 +218,10 @@ func (aH *APIHandler) getOperations(w http.ResponseWriter, r *http.Request) {
 }
 
 func (aH *APIHandler) search(w http.ResponseWriter, r *http.Request) {
+	format, ok := aH.parseFormat(w, r)
+	if !ok {
+		return
+	}
 	tQuery, err := aH.queryParser.parse(r)
 	if aH.handleError(w, err, http.StatusBadRequest) {
 		return
@@ -193,6 +229,7 @@ func (aH *APIHandler) search(w http.ResponseWriter, r *http.Request) {
 
 	var uiErrors []structuredError
 	var tracesFromStorage []*model.Trace
+	var nextToken *spanstore.ContinuationToken
 	if len(tQuery.traceIDs) > 0 {
 		tracesFromStorage, uiErrors, err = aH.tracesByIDs(r.Context(), tQuery.traceIDs)
 		if aH.handleError(w, err, http.StatusInternalServerError) {
@@ -200,9 +237,15 @@ func (aH *APIHandler) search(w http.ResponseWriter, r *http.Request) {
 		}
 	} else {
 		tracesFromStorage, err = aH.queryService.FindTraces(r.Context(), &tQuery.TraceQueryParameters)
-		if aH.handleError(w, err, http.StatusInternalServerError) {
+		if aH.handleError(w, err, searchErrorStatusCode(err)) {
 			return
 		}
+		nextToken = spanstore.NextContinuationToken(&tQuery.TraceQueryParameters, len(tracesFromStorage))
+	}
+
+	if format != uiFormat {
+		aH.writeExport(w, r, tracesFromStorage, format, true)
+		return
 	}
 
 	uiTraces := make([]*ui.Trace, len(tracesFromStorage))
@@ -218,9 +261,60 @@ func (aH *APIHandler) search(w http.ResponseWriter, r *http.Request) {
 		Data:   uiTraces,
 		Errors: uiErrors,
 	}
+	if len(tQuery.traceIDs) == 0 {
+		structuredRes.Limit = tQuery.NumTraces
+		structuredRes.Offset = tQuery.Offset()
+	}
+	if nextToken != nil {
+		structuredRes.ContinuationToken = nextToken.String()
+	}
 	aH.writeJSON(w, r, &structuredRes)
 }
 
+// findSpans implements the REST API GET:/spans.
+// It accepts the same search parameters as GET:/traces but returns the individual matching spans.
+func (aH *APIHandler) findSpans(w http.ResponseWriter, r *http.Request) {
+	tQuery, err := aH.queryParser.parse(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	if tQuery.ServiceName == "" {
+		aH.handleError(w, ErrServiceParameterRequired, http.StatusBadRequest)
+		return
+	}
+
+	spans, err := aH.queryService.FindSpans(r.Context(), &tQuery.TraceQueryParameters)
+	if err == spanstore.ErrNotSupported {
+		aH.handleError(w, err, http.StatusNotImplemented)
+		return
+	}
+	if aH.handleError(w, err, searchErrorStatusCode(err)) {
+		return
+	}
+
+	uiSpans := make([]*ui.Span, len(spans))
+	for i, span := range spans {
+		uiSpans[i] = uiconv.FromDomainEmbedProcess(span)
+	}
+	structuredRes := structuredResponse{
+		Data:  uiSpans,
+		Total: len(uiSpans),
+		Limit: tQuery.NumTraces,
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+// searchErrorStatusCode distinguishes the search errors caused by the request,
+// such as a tag filter the storage backend cannot evaluate, from the storage failures.
+func searchErrorStatusCode(err error) int {
+	switch errors.Cause(err) {
+	case spanstore.ErrInvalidTagFilter, spanstore.ErrTagFilterNotSupported:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 func (aH *APIHandler) tracesByIDs(ctx context.Context, traceIDs []model.TraceID) ([]*model.Trace, []structuredError, error) {
 	var errors []structuredError
 	retMe := make([]*model.Trace, 0, len(traceIDs))
@@ -259,7 +353,7 @@ func (aH *APIHandler) dependencies(w http.ResponseWriter, r *http.Request) {
 	}
 	endTs := time.Unix(0, 0).Add(time.Duration(endTsMillis) * time.Millisecond)
 
-	dependencies, err := aH.queryService.GetDependencies(endTs, lookback)
+	dependencies, err := aH.queryService.GetDependencies(r.Context(), endTs, lookback)
 	if aH.handleError(w, err, http.StatusInternalServerError) {
 		return
 	}
@@ -329,8 +423,13 @@ func (aH *APIHandler) filterDependenciesByService(
 
 // Parses trace ID from URL like /traces/{trace-id}
 func (aH *APIHandler) parseTraceID(w http.ResponseWriter, r *http.Request) (model.TraceID, bool) {
+	return aH.parseTraceIDParam(w, r, traceIDParam)
+}
+
+// Parses trace ID from the named URL path variable
+func (aH *APIHandler) parseTraceIDParam(w http.ResponseWriter, r *http.Request, param string) (model.TraceID, bool) {
 	vars := mux.Vars(r)
-	traceIDVar := vars[traceIDParam]
+	traceIDVar := vars[param]
 	traceID, err := model.TraceIDFromString(traceIDVar)
 	if aH.handleError(w, err, http.StatusBadRequest) {
 		return traceID, false
@@ -340,8 +439,13 @@ func (aH *APIHandler) parseTraceID(w http.ResponseWriter, r *http.Request) (mode
 
 // getTrace implements the REST API /traces/{trace-id}
 // It parses trace ID from the path, fetches the trace from QueryService,
-// formats it in the UI JSON format, and responds to the client.
+// formats it in the UI JSON format, or in the export format requested
+// with the format parameter, and responds to the client.
 func (aH *APIHandler) getTrace(w http.ResponseWriter, r *http.Request) {
+	format, ok := aH.parseFormat(w, r)
+	if !ok {
+		return
+	}
 	traceID, ok := aH.parseTraceID(w, r)
 	if !ok {
 		return
@@ -355,6 +459,11 @@ func (aH *APIHandler) getTrace(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if format != uiFormat {
+		aH.writeExport(w, r, []*model.Trace{trace}, format, shouldAdjust(r))
+		return
+	}
+
 	var uiErrors []structuredError
 	uiTrace, uiErr := aH.convertModelToUI(trace, shouldAdjust(r))
 	if uiErr != nil {
@@ -370,6 +479,140 @@ func (aH *APIHandler) getTrace(w http.ResponseWriter, r *http.Request) {
 	aH.writeJSON(w, r, &structuredRes)
 }
 
+// compareTraces implements the REST API /traces/{trace-id-a}/diff/{trace-id-b}
+// It fetches both traces from QueryService, aligns their span trees by
+// service+operation path, and responds with the differences.
+func (aH *APIHandler) compareTraces(w http.ResponseWriter, r *http.Request) {
+	traceIDA, ok := aH.parseTraceIDParam(w, r, traceIDAParam)
+	if !ok {
+		return
+	}
+	traceIDB, ok := aH.parseTraceIDParam(w, r, traceIDBParam)
+	if !ok {
+		return
+	}
+	diff, err := aH.queryService.CompareTraces(r.Context(), traceIDA, traceIDB)
+	if err == spanstore.ErrTraceNotFound {
+		aH.handleError(w, err, http.StatusNotFound)
+		return
+	}
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+
+	uiDiff := convertTraceDiffToUI(diff)
+	structuredRes := structuredResponse{
+		Data:   []*ui.TraceDiff{uiDiff},
+		Total:  len(uiDiff.Nodes),
+		Errors: []structuredError{},
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+func convertTraceDiffToUI(diff *querysvc.TraceDiff) *ui.TraceDiff {
+	nodes := make([]ui.TraceDiffNode, len(diff.Nodes))
+	for i, node := range diff.Nodes {
+		nodes[i] = ui.TraceDiffNode{
+			Path:          node.Path,
+			ServiceName:   node.ServiceName,
+			OperationName: node.OperationName,
+			Status:        node.Status.String(),
+			DurationA:     model.DurationAsMicroseconds(node.DurationA),
+			DurationB:     model.DurationAsMicroseconds(node.DurationB),
+			DurationDelta: node.DurationDelta.Nanoseconds() / int64(time.Microsecond),
+			TagDiffs:      make([]ui.TagDiff, len(node.TagDiffs)),
+		}
+		if node.Status != querysvc.DiffStatusAdded {
+			nodes[i].SpanIDA = ui.SpanID(node.SpanIDA.String())
+		}
+		if node.Status != querysvc.DiffStatusRemoved {
+			nodes[i].SpanIDB = ui.SpanID(node.SpanIDB.String())
+		}
+		for j, tagDiff := range node.TagDiffs {
+			nodes[i].TagDiffs[j] = ui.TagDiff{
+				Key:    tagDiff.Key,
+				ValueA: convertKeyValueToUI(tagDiff.ValueA),
+				ValueB: convertKeyValueToUI(tagDiff.ValueB),
+			}
+		}
+	}
+	return &ui.TraceDiff{
+		TraceIDA: ui.TraceID(diff.TraceIDA.String()),
+		TraceIDB: ui.TraceID(diff.TraceIDB.String()),
+		Nodes:    nodes,
+	}
+}
+
+func convertKeyValueToUI(kv *model.KeyValue) *ui.KeyValue {
+	if kv == nil {
+		return nil
+	}
+	return &uiconv.KeyValuesFromDomain(model.KeyValues{*kv})[0]
+}
+
+// getCriticalPath implements the REST API /traces/{trace-id}/critical-path
+// It fetches the trace from QueryService, adjusts it unless raw=true is requested,
+// and responds with the chain of span segments that determined its latency.
+func (aH *APIHandler) getCriticalPath(w http.ResponseWriter, r *http.Request) {
+	traceID, ok := aH.parseTraceID(w, r)
+	if !ok {
+		return
+	}
+	trace, err := aH.queryService.GetTrace(r.Context(), traceID)
+	if err == spanstore.ErrTraceNotFound {
+		aH.handleError(w, err, http.StatusNotFound)
+		return
+	}
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+
+	var uiErrors []structuredError
+	if shouldAdjust(r) {
+		if trace, err = aH.queryService.Adjust(trace); err != nil {
+			uiErrors = append(uiErrors, structuredError{
+				Msg:     err.Error(),
+				TraceID: ui.TraceID(traceID.String()),
+			})
+		}
+	}
+	uiPath := convertCriticalPathToUI(traceID, criticalpath.Compute(trace))
+	structuredRes := structuredResponse{
+		Data: []*ui.CriticalPath{
+			uiPath,
+		},
+		Errors: uiErrors,
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+func convertCriticalPathToUI(traceID model.TraceID, path *criticalpath.CriticalPath) *ui.CriticalPath {
+	segments := make([]ui.CriticalPathSegment, len(path.Segments))
+	for i, segment := range path.Segments {
+		segments[i] = ui.CriticalPathSegment{
+			SpanID:    ui.SpanID(segment.Span.SpanID.String()),
+			StartTime: model.TimeAsEpochMicroseconds(segment.StartTime),
+			Duration:  model.DurationAsMicroseconds(segment.Duration),
+		}
+	}
+	spans := make([]ui.CriticalPathSpan, len(path.Spans))
+	for i, span := range path.Spans {
+		spans[i] = ui.CriticalPathSpan{
+			SpanID:        ui.SpanID(span.Span.SpanID.String()),
+			OperationName: span.Span.OperationName,
+			SelfTime:      model.DurationAsMicroseconds(span.SelfTime),
+		}
+		if span.Span.Process != nil {
+			spans[i].ServiceName = span.Span.Process.ServiceName
+		}
+	}
+	return &ui.CriticalPath{
+		TraceID:  ui.TraceID(traceID.String()),
+		Segments: segments,
+		Spans:    spans,
+	}
+}
+
 func shouldAdjust(r *http.Request) bool {
 	raw := r.FormValue("raw")
 	isRaw, _ := strconv.ParseBool(raw)
@@ -401,6 +644,77 @@ func (aH *APIHandler) archiveTrace(w http.ResponseWriter, r *http.Request) {
 	aH.writeJSON(w, r, &structuredRes)
 }
 
+func (aH *APIHandler) deleteTrace(w http.ResponseWriter, r *http.Request) {
+	traceID, ok := aH.parseTraceID(w, r)
+	if !ok {
+		return
+	}
+	traceIDField := zap.String("trace_id", traceID.String())
+	principal, err := aH.admin.authorize(r.Context(), deleteTraceAction, traceIDField)
+	if aH.handleError(w, err, http.StatusForbidden) {
+		return
+	}
+
+	var deleted []model.TraceID
+	err = aH.queryService.DeleteTrace(r.Context(), traceID)
+	if err == nil {
+		deleted = append(deleted, traceID)
+	}
+	aH.admin.audit(r.Context(), principal, deleteTraceAction, deleted, err, traceIDField)
+	if aH.handleError(w, err, deleteErrorStatusCode(err)) {
+		return
+	}
+	aH.writeDeletedTraces(w, r, deleted)
+}
+
+func (aH *APIHandler) deleteServiceTraces(w http.ResponseWriter, r *http.Request) {
+	service := r.FormValue(serviceParam)
+	if service == "" || r.FormValue(startTimeParam) == "" || r.FormValue(endTimeParam) == "" {
+		aH.handleError(w, errDeleteParametersRequired, http.StatusBadRequest)
+		return
+	}
+	startTime, err := aH.queryParser.parseTime(startTimeParam, r)
+	if aH.handleError(w, errors.Wrapf(err, "unable to parse %s", startTimeParam), http.StatusBadRequest) {
+		return
+	}
+	endTime, err := aH.queryParser.parseTime(endTimeParam, r)
+	if aH.handleError(w, errors.Wrapf(err, "unable to parse %s", endTimeParam), http.StatusBadRequest) {
+		return
+	}
+	fields := []zap.Field{zap.String("service", service), zap.Time("start", startTime), zap.Time("end", endTime)}
+	principal, err := aH.admin.authorize(r.Context(), deleteServiceTracesAction, fields...)
+	if aH.handleError(w, err, http.StatusForbidden) {
+		return
+	}
+
+	deleted, err := aH.queryService.DeleteServiceTraces(r.Context(), service, startTime, endTime)
+	aH.admin.audit(r.Context(), principal, deleteServiceTracesAction, deleted, err, fields...)
+	if aH.handleError(w, err, deleteErrorStatusCode(err)) {
+		return
+	}
+	aH.writeDeletedTraces(w, r, deleted)
+}
+
+func deleteErrorStatusCode(err error) int {
+	if err == querysvc.ErrDeleteNotSupported {
+		return http.StatusNotImplemented
+	}
+	return http.StatusInternalServerError
+}
+
+func (aH *APIHandler) writeDeletedTraces(w http.ResponseWriter, r *http.Request, traceIDs []model.TraceID) {
+	data := make([]string, len(traceIDs))
+	for i, traceID := range traceIDs {
+		data[i] = traceID.String()
+	}
+	structuredRes := structuredResponse{
+		Data:   data,
+		Total:  len(data),
+		Errors: []structuredError{},
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
 func (aH *APIHandler) handleError(w http.ResponseWriter, err error, statusCode int) bool {
 	if err == nil {
 		return false
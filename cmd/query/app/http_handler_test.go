@@ -39,7 +39,10 @@ import (
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/model/adjuster"
 	ui "github.com/jaegertracing/jaeger/model/json"
+	"github.com/jaegertracing/jaeger/pkg/auth"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	depsmocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
+	storagemocks "github.com/jaegertracing/jaeger/storage/mocks"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 	spanstoremocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
 )
@@ -114,6 +117,20 @@ func initializeTestServer(options ...HandlerOption) (*httptest.Server, *spanstor
 	return https, sr, dr
 }
 
+type spanFinderReader struct {
+	*spanstoremocks.Reader
+	*spanstoremocks.SpanFinder
+}
+
+func initializeSpanFinderTestServer() (*httptest.Server, *spanstoremocks.SpanFinder) {
+	spanFinder := &spanstoremocks.SpanFinder{}
+	readStorage := spanFinderReader{Reader: &spanstoremocks.Reader{}, SpanFinder: spanFinder}
+	qs := querysvc.NewQueryService(readStorage, &depsmocks.Reader{}, querysvc.QueryServiceOptions{})
+	r := NewRouter()
+	NewAPIHandler(qs).RegisterRoutes(r)
+	return httptest.NewServer(r), spanFinder
+}
+
 type testServer struct {
 	spanReader       *spanstoremocks.Reader
 	dependencyReader *depsmocks.Reader
@@ -326,6 +343,213 @@ func TestGetTraceBadTraceID(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestCompareTraces(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+	traceB := &model.Trace{
+		Spans: []*model.Span{
+			{
+				TraceID:       model.NewTraceID(0, 2),
+				SpanID:        model.NewSpanID(3),
+				OperationName: "op",
+				Duration:      time.Millisecond,
+				Process:       &model.Process{ServiceName: "svc"},
+				Tags:          model.KeyValues{model.String("k", "v")},
+			},
+		},
+	}
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 1)).
+		Return(mockTrace, nil).Once()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 2)).
+		Return(traceB, nil).Once()
+
+	var response struct {
+		Data   []*ui.TraceDiff   `json:"data"`
+		Total  int               `json:"total"`
+		Errors []structuredError `json:"errors"`
+	}
+	err := getJSON(server.URL+`/api/traces/1/diff/2`, &response)
+	require.NoError(t, err)
+	assert.Len(t, response.Errors, 0)
+	require.Len(t, response.Data, 1)
+	diff := response.Data[0]
+	assert.Equal(t, ui.TraceID("1"), diff.TraceIDA)
+	assert.Equal(t, ui.TraceID("2"), diff.TraceIDB)
+	assert.Equal(t, 3, response.Total)
+	require.Len(t, diff.Nodes, 3)
+
+	assert.Equal(t, "::", diff.Nodes[0].Path)
+	assert.Equal(t, "removed", diff.Nodes[0].Status)
+	assert.Equal(t, ui.SpanID("1"), diff.Nodes[0].SpanIDA)
+	assert.Equal(t, ui.SpanID(""), diff.Nodes[0].SpanIDB)
+
+	added := diff.Nodes[2]
+	assert.Equal(t, "svc::op", added.Path)
+	assert.Equal(t, "added", added.Status)
+	assert.Equal(t, ui.SpanID(""), added.SpanIDA)
+	assert.Equal(t, ui.SpanID("3"), added.SpanIDB)
+	assert.Equal(t, uint64(1000), added.DurationB)
+	assert.Equal(t, int64(1000), added.DurationDelta)
+}
+
+func TestCompareTracesTagDiffs(t *testing.T) {
+	diff := &querysvc.TraceDiff{
+		Nodes: []*querysvc.TraceDiffNode{
+			{
+				Path:          "svc::op",
+				Status:        querysvc.DiffStatusCommon,
+				SpanIDA:       model.NewSpanID(1),
+				SpanIDB:       model.NewSpanID(2),
+				DurationA:     3 * time.Millisecond,
+				DurationB:     time.Millisecond,
+				DurationDelta: -2 * time.Millisecond,
+				TagDiffs: []querysvc.TagDiff{
+					{Key: "k", ValueA: &model.KeyValue{Key: "k", VType: model.Int64Type, VInt64: 1}},
+				},
+			},
+		},
+	}
+	uiDiff := convertTraceDiffToUI(diff)
+	require.Len(t, uiDiff.Nodes, 1)
+	node := uiDiff.Nodes[0]
+	assert.Equal(t, int64(-2000), node.DurationDelta)
+	assert.Equal(t, []ui.TagDiff{
+		{Key: "k", ValueA: &ui.KeyValue{Key: "k", Type: ui.Int64Type, Value: int64(1)}},
+	}, node.TagDiffs)
+}
+
+func TestCompareTracesNotFound(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+		Return(nil, spanstore.ErrTraceNotFound).Once()
+
+	var response structuredResponse
+	err := getJSON(server.URL+`/api/traces/1/diff/2`, &response)
+	assert.EqualError(t, err, parsedError(404, "trace not found"))
+}
+
+func TestCompareTracesDBFailure(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+		Return(nil, errStorage).Once()
+
+	var response structuredResponse
+	err := getJSON(server.URL+`/api/traces/1/diff/2`, &response)
+	assert.EqualError(t, err, parsedError(500, errStorageMsg))
+}
+
+func TestCompareTracesBadTraceID(t *testing.T) {
+	server, _, _ := initializeTestServer()
+	defer server.Close()
+
+	for _, path := range []string{`/api/traces/chumbawumba/diff/2`, `/api/traces/1/diff/chumbawumba`} {
+		var response structuredResponse
+		err := getJSON(server.URL+path, &response)
+		assert.Error(t, err, path)
+	}
+}
+
+func TestGetCriticalPath(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+	startTime := time.Unix(100, 0)
+	trace := &model.Trace{
+		Spans: []*model.Span{
+			{
+				TraceID:       mockTraceID,
+				SpanID:        model.NewSpanID(1),
+				OperationName: "root",
+				StartTime:     startTime,
+				Duration:      100 * time.Microsecond,
+				Process:       &model.Process{ServiceName: "frontend"},
+			},
+			{
+				TraceID:       mockTraceID,
+				SpanID:        model.NewSpanID(2),
+				OperationName: "child",
+				References:    []model.SpanRef{model.NewChildOfRef(mockTraceID, model.NewSpanID(1))},
+				StartTime:     startTime.Add(10 * time.Microsecond),
+				Duration:      60 * time.Microsecond,
+				Process:       &model.Process{ServiceName: "backend"},
+			},
+		},
+	}
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mockTraceID).
+		Return(trace, nil).Once()
+
+	var response struct {
+		Data   []*ui.CriticalPath `json:"data"`
+		Errors []structuredError  `json:"errors"`
+	}
+	err := getJSON(server.URL+`/api/traces/`+mockTraceID.String()+`/critical-path`, &response)
+	require.NoError(t, err)
+	assert.Len(t, response.Errors, 0)
+	require.Len(t, response.Data, 1)
+	path := response.Data[0]
+	assert.Equal(t, ui.TraceID("1e240"), path.TraceID)
+	assert.Equal(t, []ui.CriticalPathSegment{
+		{SpanID: "1", StartTime: 100000000, Duration: 10},
+		{SpanID: "2", StartTime: 100000010, Duration: 60},
+		{SpanID: "1", StartTime: 100000070, Duration: 30},
+	}, path.Segments)
+	assert.Equal(t, []ui.CriticalPathSpan{
+		{SpanID: "1", ServiceName: "frontend", OperationName: "root", SelfTime: 40},
+		{SpanID: "2", ServiceName: "backend", OperationName: "child", SelfTime: 60},
+	}, path.Spans)
+}
+
+func TestGetCriticalPathAdjustmentFailure(t *testing.T) {
+	server, readMock, _, _ := initializeTestServerWithHandler(
+		querysvc.QueryServiceOptions{
+			Adjuster: adjuster.Func(func(trace *model.Trace) (*model.Trace, error) {
+				return trace, errAdjustment
+			}),
+		},
+	)
+	defer server.Close()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+		Return(mockTrace, nil).Once()
+
+	var response structuredResponse
+	err := getJSON(server.URL+`/api/traces/123456/critical-path`, &response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Errors, 1)
+	assert.EqualValues(t, errAdjustment.Error(), response.Errors[0].Msg)
+}
+
+func TestGetCriticalPathNotFound(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+		Return(nil, spanstore.ErrTraceNotFound).Once()
+
+	var response structuredResponse
+	err := getJSON(server.URL+`/api/traces/123456/critical-path`, &response)
+	assert.EqualError(t, err, parsedError(404, "trace not found"))
+}
+
+func TestGetCriticalPathDBFailure(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+	readMock.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+		Return(nil, errStorage).Once()
+
+	var response structuredResponse
+	err := getJSON(server.URL+`/api/traces/123456/critical-path`, &response)
+	assert.EqualError(t, err, parsedError(500, errStorageMsg))
+}
+
+func TestGetCriticalPathBadTraceID(t *testing.T) {
+	server, _, _ := initializeTestServer()
+	defer server.Close()
+
+	var response structuredResponse
+	err := getJSON(server.URL+`/api/traces/chumbawumba/critical-path`, &response)
+	assert.Error(t, err)
+}
+
 func TestSearchSuccess(t *testing.T) {
 	server, readMock, _ := initializeTestServer()
 	defer server.Close()
@@ -338,6 +562,83 @@ func TestSearchSuccess(t *testing.T) {
 	assert.Len(t, response.Errors, 0)
 }
 
+func TestSearchWithContinuationToken(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+	readMock.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.MatchedBy(func(q *spanstore.TraceQueryParameters) bool {
+		return q.ContinuationToken == nil
+	})).Return([]*model.Trace{mockTrace}, nil).Once()
+	readMock.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.MatchedBy(func(q *spanstore.TraceQueryParameters) bool {
+		return q.Offset() == 1 && q.StartTimeMax.Equal(time.Unix(0, 2000000000*1000))
+	})).Return([]*model.Trace{}, nil).Once()
+
+	var response structuredResponse
+	err := getJSON(server.URL+`/api/traces?service=service&start=0&end=2000000000&limit=1`, &response)
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Limit)
+	assert.Equal(t, 0, response.Offset)
+	require.NotEmpty(t, response.ContinuationToken)
+
+	// the end of the search window is pinned by the token
+	var nextResponse structuredResponse
+	err = getJSON(server.URL+`/api/traces?service=service&start=0&limit=1&continuationToken=`+response.ContinuationToken, &nextResponse)
+	require.NoError(t, err)
+	assert.Equal(t, 1, nextResponse.Limit)
+	assert.Equal(t, 1, nextResponse.Offset)
+	assert.Empty(t, nextResponse.ContinuationToken)
+	assert.Len(t, nextResponse.Data, 0)
+}
+
+func TestFindSpansSuccess(t *testing.T) {
+	server, spanFinder := initializeSpanFinderTestServer()
+	defer server.Close()
+	spanFinder.On("FindSpans", mock.AnythingOfType("*context.valueCtx"), mock.MatchedBy(func(q *spanstore.TraceQueryParameters) bool {
+		return q.ServiceName == "service" && q.OperationName == "operation" && q.Tags["error"] == "true" && q.NumTraces == 10
+	})).Return(mockTrace.Spans, nil).Once()
+
+	var response struct {
+		Spans  []*ui.Span        `json:"data"`
+		Total  int               `json:"total"`
+		Limit  int               `json:"limit"`
+		Errors []structuredError `json:"errors"`
+	}
+	err := getJSON(server.URL+`/api/spans?service=service&operation=operation&tag=error:true&limit=10`, &response)
+	require.NoError(t, err)
+	assert.Len(t, response.Errors, 0)
+	assert.Equal(t, 2, response.Total)
+	assert.Equal(t, 10, response.Limit)
+	require.Len(t, response.Spans, 2)
+	for i, span := range response.Spans {
+		assert.Equal(t, ui.TraceID(mockTraceID.String()), span.TraceID)
+		assert.Equal(t, ui.SpanID(mockTrace.Spans[i].SpanID.String()), span.SpanID)
+		assert.NotNil(t, span.Process)
+	}
+}
+
+func TestFindSpansFailures(t *testing.T) {
+	server, spanFinder := initializeSpanFinderTestServer()
+	defer server.Close()
+	spanFinder.On("FindSpans", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return(nil, errStorage).Once()
+
+	err := getJSON(server.URL+`/api/spans?service=service`, nil)
+	assert.EqualError(t, err, parsedError(500, errStorageMsg))
+
+	err = getJSON(server.URL+`/api/spans?traceID=1`, nil)
+	assert.EqualError(t, err, parsedError(400, ErrServiceParameterRequired.Error()))
+
+	err = getJSON(server.URL+`/api/spans?service=service&limit=abc`, nil)
+	assert.Error(t, err)
+}
+
+func TestFindSpansNotSupported(t *testing.T) {
+	server, _, _ := initializeTestServer()
+	defer server.Close()
+
+	err := getJSON(server.URL+`/api/spans?service=service`, nil)
+	assert.EqualError(t, err, parsedError(501, spanstore.ErrNotSupported.Error()))
+}
+
 func TestSearchByTraceIDSuccess(t *testing.T) {
 	server, readMock, _ := initializeTestServer()
 	defer server.Close()
@@ -423,6 +724,17 @@ func TestSearchDBFailure(t *testing.T) {
 	assert.EqualError(t, err, parsedError(500, "whatsamattayou"))
 }
 
+func TestSearchTagFilterNotSupported(t *testing.T) {
+	server, readMock, _ := initializeTestServer()
+	defer server.Close()
+	readMock.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return(nil, spanstore.ErrTagFilterNotSupported).Once()
+
+	var response structuredResponse
+	err := getJSON(server.URL+`/api/traces?service=service&tagFilter=http.status_code%3E499`, &response)
+	assert.EqualError(t, err, parsedError(400, spanstore.ErrTagFilterNotSupported.Error()))
+}
+
 func TestSearchFailures(t *testing.T) {
 	tests := []struct {
 		urlStr string
@@ -436,6 +748,10 @@ func TestSearchFailures(t *testing.T) {
 			`/api/traces?service=service&start=0&end=0&operation=operation&maxDuration=10ms&limit=200&minDuration=20ms`,
 			parsedError(400, "'maxDuration' should be greater than 'minDuration'"),
 		},
+		{
+			`/api/traces?service=service&tagFilter=foo`,
+			parsedError(400, `cannot parse tagFilter param: no operator in \"foo\": invalid tag filter`),
+		},
 	}
 	for _, test := range tests {
 		testIndividualSearchFailures(t, test.urlStr, test.errMsg)
@@ -479,6 +795,53 @@ func TestGetServicesStorageFailure(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestGetServicesAuthentication(t *testing.T) {
+	server, readMock, _ := initializeTestServer(
+		HandlerOptions.Authenticator(auth.NewBearerTokenAuthenticator(map[string]string{"secret": "robot"})))
+	defer server.Close()
+	readMock.On("GetServices", mock.AnythingOfType("*context.valueCtx")).Return([]string{"trifle"}, nil).Once()
+
+	var response structuredResponse
+	err := getJSON(server.URL+"/api/services", &response)
+	assert.EqualError(t, err, parsedError(http.StatusUnauthorized, "unauthenticated"))
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/services", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	err = execJSON(req, &response)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"trifle"}, response.Data)
+}
+
+func TestGetServicesTenancy(t *testing.T) {
+	tenancyMgr, err := tenancy.NewManager(&tenancy.Options{Enabled: true, Tenants: []string{"acme"}})
+	require.NoError(t, err)
+	acmeReader := &spanstoremocks.Reader{}
+	tenantFactory := &storagemocks.TenantFactory{}
+	tenantFactory.On("CreateTenantSpanReader", "acme").Return(acmeReader, nil).Once()
+	acmeReader.On("GetServices", mock.AnythingOfType("*context.valueCtx")).Return([]string{"trifle"}, nil).Once()
+	server, readMock, _, _ := initializeTestServerWithOptions(
+		querysvc.QueryServiceOptions{TenantFactory: tenantFactory},
+		HandlerOptions.Tenancy(tenancyMgr))
+	defer server.Close()
+
+	var response structuredResponse
+	err = getJSON(server.URL+"/api/services", &response)
+	assert.EqualError(t, err, "401 error from server: missing tenant\n")
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/services", nil)
+	require.NoError(t, err)
+	req.Header.Set(tenancyMgr.Header, "megacorp")
+	err = execJSON(req, &response)
+	assert.EqualError(t, err, "403 error from server: unknown tenant\n")
+
+	req.Header.Set(tenancyMgr.Header, "acme")
+	err = execJSON(req, &response)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"trifle"}, response.Data)
+	readMock.AssertNotCalled(t, "GetServices", mock.Anything)
+}
+
 func TestGetOperationsSuccess(t *testing.T) {
 	server, readMock, _ := initializeTestServer()
 	defer server.Close()
@@ -34,6 +34,7 @@ const (
 	operationParam   = "operation"
 	tagParam         = "tag"
 	tagsParam        = "tags"
+	tagFilterParam   = "tagFilter"
 	startTimeParam   = "start"
 	limitParam       = "limit"
 	minDurationParam = "minDuration"
@@ -41,6 +42,8 @@ const (
 	serviceParam     = "service"
 	endTimeParam     = "end"
 	prettyPrintParam = "prettyPrint"
+
+	continuationTokenParam = "continuationToken"
 )
 
 var (
@@ -64,7 +67,7 @@ type traceQueryParameters struct {
 // parse takes a request and constructs a model of parameters
 // Trace query syntax:
 //     query ::= param | param '&' query
-//     param ::= service | operation | limit | start | end | minDuration | maxDuration | tag | tags
+//     param ::= service | operation | limit | start | end | minDuration | maxDuration | tag | tags | tagFilter | continuationToken
 //     service ::= 'service=' strValue
 //     operation ::= 'operation=' strValue
 //     limit ::= 'limit=' intValue
@@ -76,6 +79,9 @@ type traceQueryParameters struct {
 //     key := strValue
 //     keyValue := strValue ':' strValue
 //     tags :== 'tags=' jsonMap
+//     tagFilter ::= 'tagFilter=' key op strValue | 'tagFilter=exists(' key ')'
+//     op ::= '=' | '!=' | '=~' | '>' | '<'
+//     continuationToken ::= 'continuationToken=' strValue as returned by the previous page of results
 func (p *queryParser) parse(r *http.Request) (*traceQueryParameters, error) {
 	service := r.FormValue(serviceParam)
 	operation := r.FormValue(operationParam)
@@ -94,6 +100,15 @@ func (p *queryParser) parse(r *http.Request) (*traceQueryParameters, error) {
 		return nil, err
 	}
 
+	var tagFilters []spanstore.TagFilter
+	for _, f := range r.Form[tagFilterParam] {
+		tagFilter, err := spanstore.ParseTagFilter(f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot parse %s param", tagFilterParam)
+		}
+		tagFilters = append(tagFilters, tagFilter)
+	}
+
 	limitParam := r.FormValue(limitParam)
 	limit := defaultQueryLimit
 	if limitParam != "" {
@@ -114,6 +129,13 @@ func (p *queryParser) parse(r *http.Request) (*traceQueryParameters, error) {
 		return nil, err
 	}
 
+	var continuationToken *spanstore.ContinuationToken
+	if token := r.FormValue(continuationTokenParam); token != "" {
+		if continuationToken, err = spanstore.ParseContinuationToken(token); err != nil {
+			return nil, errors.Wrapf(err, "cannot parse %s param", continuationTokenParam)
+		}
+	}
+
 	var traceIDs []model.TraceID
 	for _, id := range r.Form[traceIDParam] {
 		if traceID, err := model.TraceIDFromString(id); err == nil {
@@ -125,17 +147,24 @@ func (p *queryParser) parse(r *http.Request) (*traceQueryParameters, error) {
 
 	traceQuery := &traceQueryParameters{
 		TraceQueryParameters: spanstore.TraceQueryParameters{
-			ServiceName:   service,
-			OperationName: operation,
-			StartTimeMin:  startTime,
-			StartTimeMax:  endTime,
-			Tags:          tags,
-			NumTraces:     limit,
-			DurationMin:   minDuration,
-			DurationMax:   maxDuration,
+			ServiceName:       service,
+			OperationName:     operation,
+			StartTimeMin:      startTime,
+			StartTimeMax:      endTime,
+			Tags:              tags,
+			NumTraces:         limit,
+			TagFilters:        tagFilters,
+			DurationMin:       minDuration,
+			DurationMax:       maxDuration,
 		},
 		traceIDs: traceIDs,
 	}
+	if continuationToken != nil {
+		// the pages must be computed against the search window of the first page
+		if err := traceQuery.ApplyContinuationToken(continuationToken); err != nil {
+			return nil, errors.Wrapf(err, "cannot use %s param", continuationTokenParam)
+		}
+	}
 
 	if err := p.validateQuery(traceQuery); err != nil {
 		return nil, err
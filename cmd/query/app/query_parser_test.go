@@ -31,6 +31,12 @@ var errParseInt = `strconv.ParseInt: parsing "string": invalid syntax`
 func TestParseTraceQuery(t *testing.T) {
 	timeNow := time.Now()
 	const noErr = ""
+	token := spanstore.NextContinuationToken(&spanstore.TraceQueryParameters{
+		ServiceName:  "service",
+		StartTimeMin: time.Unix(500, 0).UTC(),
+		StartTimeMax: time.Unix(1000, 0).UTC(),
+		NumTraces:    20,
+	}, 20)
 	tests := []struct {
 		urlStr        string
 		errMsg        string
@@ -111,6 +117,38 @@ func TestParseTraceQuery(t *testing.T) {
 				},
 			},
 		},
+		// the search window is pinned by the continuation token
+		{"x?service=service&start=0&end=2000000000&limit=20&continuationToken=" + token.String(), noErr,
+			&traceQueryParameters{
+				TraceQueryParameters: spanstore.TraceQueryParameters{
+					ServiceName:       "service",
+					StartTimeMin:      token.StartTimeMin,
+					StartTimeMax:      token.StartTimeMax,
+					NumTraces:         20,
+					Tags:              make(map[string]string),
+					ContinuationToken: token,
+				},
+			},
+		},
+		{"x?service=service&continuationToken=foo", "cannot parse continuationToken param: invalid continuation token", nil},
+		{"x?service=other&continuationToken=" + token.String(), "cannot use continuationToken param: continuation token was issued for another query", nil},
+		{"x?service=service&start=0&end=0&tagFilter=http.status_code%3E499&tagFilter=http.url%3D~.*%2Fapi%2F.*&tagFilter=exists(error)", noErr,
+			&traceQueryParameters{
+				TraceQueryParameters: spanstore.TraceQueryParameters{
+					ServiceName:  "service",
+					StartTimeMin: time.Unix(0, 0),
+					StartTimeMax: time.Unix(0, 0),
+					NumTraces:    100,
+					Tags:         make(map[string]string),
+					TagFilters: []spanstore.TagFilter{
+						{Key: "http.status_code", Operator: spanstore.TagFilterGreaterThan, Value: "499"},
+						{Key: "http.url", Operator: spanstore.TagFilterRegex, Value: ".*/api/.*"},
+						{Key: "error", Operator: spanstore.TagFilterExists},
+					},
+				},
+			},
+		},
+		{"x?service=service&tagFilter=http.status_code%3Ex", `cannot parse tagFilter param: expecting a number in "http.status_code>x": invalid tag filter`, nil},
 		// trace ID in upper/lower case
 		{"x?traceID=1f00&traceID=1E00", noErr,
 			&traceQueryParameters{
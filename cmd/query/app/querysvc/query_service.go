@@ -17,6 +17,7 @@ package querysvc
 import (
 	"context"
 	"errors"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -24,6 +25,7 @@ import (
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/model/adjuster"
 	"github.com/jaegertracing/jaeger/pkg/multierror"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/storage"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
@@ -31,6 +33,9 @@ import (
 
 var (
 	errNoArchiveSpanStorage = errors.New("archive span storage was not configured")
+
+	// ErrDeleteNotSupported is returned when traces are deleted without a span storage that supports deletion
+	ErrDeleteNotSupported = errors.New("the span storage does not support deleting traces")
 )
 
 // QueryServiceOptions has optional members of QueryService
@@ -38,6 +43,12 @@ type QueryServiceOptions struct {
 	ArchiveSpanReader spanstore.Reader
 	ArchiveSpanWriter spanstore.Writer
 	Adjuster          adjuster.Adjuster
+	// TenantFactory, if set, makes every read and deletion use the storage of the tenant carried by
+	// the context, see tenancy.GetTenant. Requests without a tenant fail with tenancy.ErrMissingTenant.
+	TenantFactory storage.TenantFactory
+	// SpanDeleter, if set, deletes traces from the span storage. It is not used with a TenantFactory,
+	// which creates the deleter of each tenant instead.
+	SpanDeleter spanstore.Deleter
 }
 
 // QueryService contains span utils required by the query-service.
@@ -45,6 +56,15 @@ type QueryService struct {
 	spanReader       spanstore.Reader
 	dependencyReader dependencystore.Reader
 	options          QueryServiceOptions
+	tenantReaders    *tenantReaders
+}
+
+// tenantReaders caches the readers and deleters created for each tenant.
+type tenantReaders struct {
+	sync.Mutex
+	spanReaders       map[string]spanstore.Reader
+	dependencyReaders map[string]dependencystore.Reader
+	spanDeleters      map[string]spanstore.Deleter
 }
 
 // NewQueryService returns a new QueryService.
@@ -53,6 +73,11 @@ func NewQueryService(spanReader spanstore.Reader, dependencyReader dependencysto
 		spanReader:       spanReader,
 		dependencyReader: dependencyReader,
 		options:          options,
+		tenantReaders: &tenantReaders{
+			spanReaders:       make(map[string]spanstore.Reader),
+			dependencyReaders: make(map[string]dependencystore.Reader),
+			spanDeleters:      make(map[string]spanstore.Deleter),
+		},
 	}
 
 	if qsvc.options.Adjuster == nil {
@@ -63,7 +88,11 @@ func NewQueryService(spanReader spanstore.Reader, dependencyReader dependencysto
 
 // GetTrace is the queryService implementation of spanstore.Reader.GetTrace
 func (qs QueryService) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
-	trace, err := qs.spanReader.GetTrace(ctx, traceID)
+	spanReader, err := qs.getSpanReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	trace, err := spanReader.GetTrace(ctx, traceID)
 	if err == spanstore.ErrTraceNotFound {
 		if qs.options.ArchiveSpanReader == nil {
 			return nil, err
@@ -75,17 +104,43 @@ func (qs QueryService) GetTrace(ctx context.Context, traceID model.TraceID) (*mo
 
 // GetServices is the queryService implementation of spanstore.Reader.GetServices
 func (qs QueryService) GetServices(ctx context.Context) ([]string, error) {
-	return qs.spanReader.GetServices(ctx)
+	spanReader, err := qs.getSpanReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return spanReader.GetServices(ctx)
 }
 
 // GetOperations is the queryService implementation of spanstore.Reader.GetOperations
 func (qs QueryService) GetOperations(ctx context.Context, service string) ([]string, error) {
-	return qs.spanReader.GetOperations(ctx, service)
+	spanReader, err := qs.getSpanReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return spanReader.GetOperations(ctx, service)
 }
 
 // FindTraces is the queryService implementation of spanstore.Reader.FindTraces
 func (qs QueryService) FindTraces(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
-	return qs.spanReader.FindTraces(ctx, query)
+	spanReader, err := qs.getSpanReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return spanReader.FindTraces(ctx, query)
+}
+
+// FindSpans is the queryService implementation of spanstore.SpanFinder.FindSpans.
+// It returns spanstore.ErrNotSupported if the span storage cannot search for individual spans.
+func (qs QueryService) FindSpans(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Span, error) {
+	spanReader, err := qs.getSpanReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	spanFinder, ok := spanReader.(spanstore.SpanFinder)
+	if !ok {
+		return nil, spanstore.ErrNotSupported
+	}
+	return spanFinder.FindSpans(ctx, query)
 }
 
 // ArchiveTrace is the queryService utility to archive traces.
@@ -108,14 +163,109 @@ func (qs QueryService) ArchiveTrace(ctx context.Context, traceID model.TraceID)
 	return multierror.Wrap(writeErrors)
 }
 
+// DeleteTrace deletes the trace from the span storage.
+func (qs QueryService) DeleteTrace(ctx context.Context, traceID model.TraceID) error {
+	spanDeleter, err := qs.getSpanDeleter(ctx)
+	if err != nil {
+		return err
+	}
+	return spanDeleter.DeleteTrace(ctx, traceID)
+}
+
+// DeleteServiceTraces deletes the traces of the service started within the time range from the span storage,
+// and returns the IDs of the deleted traces.
+func (qs QueryService) DeleteServiceTraces(ctx context.Context, service string, startTimeMin, startTimeMax time.Time) ([]model.TraceID, error) {
+	spanDeleter, err := qs.getSpanDeleter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return spanDeleter.DeleteServiceTraces(ctx, service, startTimeMin, startTimeMax)
+}
+
+// getSpanDeleter returns the span deleter of the tenant carried by the context if multi-tenancy is enabled.
+func (qs QueryService) getSpanDeleter(ctx context.Context) (spanstore.Deleter, error) {
+	if qs.options.TenantFactory == nil {
+		if qs.options.SpanDeleter == nil {
+			return nil, ErrDeleteNotSupported
+		}
+		return qs.options.SpanDeleter, nil
+	}
+	tenant := tenancy.GetTenant(ctx)
+	if tenant == "" {
+		return nil, tenancy.ErrMissingTenant
+	}
+	qs.tenantReaders.Lock()
+	defer qs.tenantReaders.Unlock()
+	if deleter, ok := qs.tenantReaders.spanDeleters[tenant]; ok {
+		return deleter, nil
+	}
+	deleter, err := qs.options.TenantFactory.CreateTenantSpanDeleter(tenant)
+	if err == storage.ErrSpanDeleterNotSupported {
+		return nil, ErrDeleteNotSupported
+	}
+	if err != nil {
+		return nil, err
+	}
+	qs.tenantReaders.spanDeleters[tenant] = deleter
+	return deleter, nil
+}
+
 // Adjust applies adjusters to the trace.
 func (qs QueryService) Adjust(trace *model.Trace) (*model.Trace, error) {
 	return qs.options.Adjuster.Adjust(trace)
 }
 
 // GetDependencies implements dependencystore.Reader.GetDependencies
-func (qs QueryService) GetDependencies(endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
-	return qs.dependencyReader.GetDependencies(endTs, lookback)
+func (qs QueryService) GetDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
+	dependencyReader, err := qs.getDependencyReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dependencyReader.GetDependencies(endTs, lookback)
+}
+
+// getSpanReader returns the span reader of the tenant carried by the context if multi-tenancy is enabled.
+func (qs QueryService) getSpanReader(ctx context.Context) (spanstore.Reader, error) {
+	if qs.options.TenantFactory == nil {
+		return qs.spanReader, nil
+	}
+	tenant := tenancy.GetTenant(ctx)
+	if tenant == "" {
+		return nil, tenancy.ErrMissingTenant
+	}
+	qs.tenantReaders.Lock()
+	defer qs.tenantReaders.Unlock()
+	if reader, ok := qs.tenantReaders.spanReaders[tenant]; ok {
+		return reader, nil
+	}
+	reader, err := qs.options.TenantFactory.CreateTenantSpanReader(tenant)
+	if err != nil {
+		return nil, err
+	}
+	qs.tenantReaders.spanReaders[tenant] = reader
+	return reader, nil
+}
+
+// getDependencyReader returns the dependency reader of the tenant carried by the context if multi-tenancy is enabled.
+func (qs QueryService) getDependencyReader(ctx context.Context) (dependencystore.Reader, error) {
+	if qs.options.TenantFactory == nil {
+		return qs.dependencyReader, nil
+	}
+	tenant := tenancy.GetTenant(ctx)
+	if tenant == "" {
+		return nil, tenancy.ErrMissingTenant
+	}
+	qs.tenantReaders.Lock()
+	defer qs.tenantReaders.Unlock()
+	if reader, ok := qs.tenantReaders.dependencyReaders[tenant]; ok {
+		return reader, nil
+	}
+	reader, err := qs.options.TenantFactory.CreateTenantDependencyReader(tenant)
+	if err != nil {
+		return nil, err
+	}
+	qs.tenantReaders.dependencyReaders[tenant] = reader
+	return reader, nil
 }
 
 // InitArchiveStorage tries to initialize archive storage reader/writer if storage factory supports them.
@@ -147,3 +297,24 @@ func (opts *QueryServiceOptions) InitArchiveStorage(storageFactory storage.Facto
 	opts.ArchiveSpanWriter = writer
 	return true
 }
+
+// InitSpanDeleter initializes the span deleter if the storage factory supports deleting traces,
+// and returns whether it succeeded.
+func (opts *QueryServiceOptions) InitSpanDeleter(storageFactory storage.Factory, logger *zap.Logger) bool {
+	deleterFactory, ok := storageFactory.(storage.DeleterFactory)
+	if !ok {
+		logger.Info("Span deleter not supported by the factory")
+		return false
+	}
+	deleter, err := deleterFactory.CreateSpanDeleter()
+	if err == storage.ErrSpanDeleterNotSupported {
+		logger.Info("Span deleter not created", zap.String("reason", err.Error()))
+		return false
+	}
+	if err != nil {
+		logger.Error("Cannot init span deleter", zap.Error(err))
+		return false
+	}
+	opts.SpanDeleter = deleter
+	return true
+}
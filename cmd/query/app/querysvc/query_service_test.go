@@ -27,9 +27,11 @@ import (
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/model/adjuster"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/storage"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	depsmocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
+	storagemocks "github.com/jaegertracing/jaeger/storage/mocks"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 	spanstoremocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
 )
@@ -178,6 +180,32 @@ func TestFindTraces(t *testing.T) {
 	assert.Len(t, traces, 1)
 }
 
+type spanFinderReader struct {
+	*spanstoremocks.Reader
+	*spanstoremocks.SpanFinder
+}
+
+// Test QueryService.FindSpans() for success.
+func TestFindSpans(t *testing.T) {
+	finderMock := &spanstoremocks.SpanFinder{}
+	readStorage := spanFinderReader{Reader: &spanstoremocks.Reader{}, SpanFinder: finderMock}
+	qs := NewQueryService(readStorage, &depsmocks.Reader{}, QueryServiceOptions{})
+	finderMock.On("FindSpans", mock.Anything, mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return(mockTrace.Spans, nil).Once()
+
+	spans, err := qs.FindSpans(context.Background(), &spanstore.TraceQueryParameters{ServiceName: "service"})
+	assert.NoError(t, err)
+	assert.Equal(t, mockTrace.Spans, spans)
+}
+
+// Test QueryService.FindSpans() when span storage cannot search for spans.
+func TestFindSpansNotSupported(t *testing.T) {
+	qs, _, _ := initializeTestService()
+
+	_, err := qs.FindSpans(context.Background(), &spanstore.TraceQueryParameters{ServiceName: "service"})
+	assert.Equal(t, spanstore.ErrNotSupported, err)
+}
+
 // Test QueryService.ArchiveTrace() with no ArchiveSpanWriter.
 func TestArchiveTraceNoOptions(t *testing.T) {
 	qs, _, _ := initializeTestService()
@@ -232,6 +260,68 @@ func TestArchiveTraceSuccess(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+// Test QueryService.DeleteTrace() and QueryService.DeleteServiceTraces()
+func TestDeleteTraces(t *testing.T) {
+	deleter := &spanstoremocks.Deleter{}
+	qs := NewQueryService(&spanstoremocks.Reader{}, &depsmocks.Reader{}, QueryServiceOptions{SpanDeleter: deleter})
+	start, end := time.Unix(100, 0), time.Unix(200, 0)
+	deleter.On("DeleteTrace", mock.Anything, mockTraceID).Return(nil)
+	deleter.On("DeleteServiceTraces", mock.Anything, "service", start, end).Return([]model.TraceID{mockTraceID}, nil)
+
+	assert.NoError(t, qs.DeleteTrace(context.Background(), mockTraceID))
+	traceIDs, err := qs.DeleteServiceTraces(context.Background(), "service", start, end)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.TraceID{mockTraceID}, traceIDs)
+}
+
+func TestDeleteTracesNotSupported(t *testing.T) {
+	tenantFactory := &storagemocks.TenantFactory{}
+	tenantFactory.On("CreateTenantSpanDeleter", "acme").Return(nil, storage.ErrSpanDeleterNotSupported)
+	for _, options := range []QueryServiceOptions{
+		{},
+		{SpanDeleter: &spanstoremocks.Deleter{}, TenantFactory: tenantFactory},
+	} {
+		qs := NewQueryService(&spanstoremocks.Reader{}, &depsmocks.Reader{}, options)
+		ctx := tenancy.WithTenant(context.Background(), "acme")
+		assert.Equal(t, ErrDeleteNotSupported, qs.DeleteTrace(ctx, mockTraceID))
+		_, err := qs.DeleteServiceTraces(ctx, "service", time.Unix(100, 0), time.Unix(200, 0))
+		assert.Equal(t, ErrDeleteNotSupported, err)
+	}
+}
+
+// Test QueryService deletions with multi-tenancy.
+func TestTenantDeletes(t *testing.T) {
+	acmeDeleter := &spanstoremocks.Deleter{}
+	tenantFactory := &storagemocks.TenantFactory{}
+	tenantFactory.On("CreateTenantSpanDeleter", "acme").Return(acmeDeleter, nil).Once()
+	tenantFactory.On("CreateTenantSpanDeleter", "megacorp").Return(nil, errors.New("no storage")).Once()
+
+	deleter := &spanstoremocks.Deleter{}
+	qs := NewQueryService(&spanstoremocks.Reader{}, &depsmocks.Reader{}, QueryServiceOptions{
+		SpanDeleter:   deleter,
+		TenantFactory: tenantFactory,
+	})
+
+	assert.Equal(t, tenancy.ErrMissingTenant, qs.DeleteTrace(context.Background(), mockTraceID))
+
+	ctx := tenancy.WithTenant(context.Background(), "acme")
+	start, end := time.Unix(100, 0), time.Unix(200, 0)
+	acmeDeleter.On("DeleteTrace", ctx, mockTraceID).Return(nil).Once()
+	acmeDeleter.On("DeleteServiceTraces", ctx, "service", start, end).Return([]model.TraceID{mockTraceID}, nil).Once()
+
+	assert.NoError(t, qs.DeleteTrace(ctx, mockTraceID))
+	traceIDs, err := qs.DeleteServiceTraces(ctx, "service", start, end)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.TraceID{mockTraceID}, traceIDs)
+
+	_, err = qs.DeleteServiceTraces(tenancy.WithTenant(context.Background(), "megacorp"), "service", start, end)
+	assert.EqualError(t, err, "no storage")
+
+	deleter.AssertNotCalled(t, "DeleteTrace", mock.Anything, mock.Anything)
+	acmeDeleter.AssertExpectations(t)
+	tenantFactory.AssertExpectations(t)
+}
+
 // Test QueryService.Adjust()
 func TestTraceAdjustmentFailure(t *testing.T) {
 	qs := initializeTestServiceWithAdjustOption()
@@ -254,11 +344,52 @@ func TestGetDependencies(t *testing.T) {
 	endTs := time.Unix(0, 1476374248550*millisToNanosMultiplier)
 	depsMock.On("GetDependencies", endTs, defaultDependencyLookbackDuration).Return(expectedDependencies, nil).Times(1)
 
-	actualDependencies, err := qs.GetDependencies(time.Unix(0, 1476374248550*millisToNanosMultiplier), defaultDependencyLookbackDuration)
+	actualDependencies, err := qs.GetDependencies(context.Background(), time.Unix(0, 1476374248550*millisToNanosMultiplier), defaultDependencyLookbackDuration)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedDependencies, actualDependencies)
 }
 
+// Test QueryService reads with multi-tenancy.
+func TestTenantReads(t *testing.T) {
+	acmeReader := &spanstoremocks.Reader{}
+	acmeDeps := &depsmocks.Reader{}
+	tenantFactory := &storagemocks.TenantFactory{}
+	tenantFactory.On("CreateTenantSpanReader", "acme").Return(acmeReader, nil).Once()
+	tenantFactory.On("CreateTenantDependencyReader", "acme").Return(acmeDeps, nil).Once()
+	tenantFactory.On("CreateTenantSpanReader", "megacorp").Return(nil, errors.New("no storage")).Once()
+
+	readStorage := &spanstoremocks.Reader{}
+	qs := NewQueryService(readStorage, &depsmocks.Reader{}, QueryServiceOptions{TenantFactory: tenantFactory})
+
+	_, err := qs.GetServices(context.Background())
+	assert.Equal(t, tenancy.ErrMissingTenant, err)
+	_, err = qs.GetDependencies(context.Background(), time.Now(), time.Hour)
+	assert.Equal(t, tenancy.ErrMissingTenant, err)
+
+	ctx := tenancy.WithTenant(context.Background(), "acme")
+	acmeReader.On("GetServices", ctx).Return([]string{"acme-svc"}, nil).Twice()
+	acmeReader.On("GetTrace", ctx, mockTraceID).Return(mockTrace, nil).Once()
+	acmeDeps.On("GetDependencies", mock.Anything, time.Hour).Return([]model.DependencyLink{{Parent: "a", Child: "b"}}, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		services, err := qs.GetServices(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"acme-svc"}, services)
+	}
+	trace, err := qs.GetTrace(ctx, mockTraceID)
+	assert.NoError(t, err)
+	assert.Equal(t, mockTrace, trace)
+	deps, err := qs.GetDependencies(ctx, time.Now(), time.Hour)
+	assert.NoError(t, err)
+	assert.Len(t, deps, 1)
+
+	_, err = qs.FindTraces(tenancy.WithTenant(context.Background(), "megacorp"), &spanstore.TraceQueryParameters{})
+	assert.EqualError(t, err, "no storage")
+
+	readStorage.AssertNotCalled(t, "GetServices", mock.Anything)
+	tenantFactory.AssertExpectations(t)
+}
+
 type fakeStorageFactory1 struct {
 }
 
@@ -318,3 +449,28 @@ func TestInitArchiveStorage(t *testing.T) {
 	assert.Equal(t, reader, opts.ArchiveSpanReader)
 	assert.Equal(t, writer, opts.ArchiveSpanWriter)
 }
+
+func TestInitSpanDeleter(t *testing.T) {
+	logger := zap.NewNop()
+	opts := &QueryServiceOptions{}
+	assert.False(t, opts.InitSpanDeleter(new(fakeStorageFactory1), logger))
+
+	for _, err := range []error{storage.ErrSpanDeleterNotSupported, errors.New("error")} {
+		factory := &struct {
+			storagemocks.Factory
+			storagemocks.DeleterFactory
+		}{}
+		factory.DeleterFactory.On("CreateSpanDeleter").Return(nil, err)
+		assert.False(t, opts.InitSpanDeleter(factory, logger))
+	}
+	assert.Nil(t, opts.SpanDeleter)
+
+	factory := &struct {
+		storagemocks.Factory
+		storagemocks.DeleterFactory
+	}{}
+	deleter := &spanstoremocks.Deleter{}
+	factory.DeleterFactory.On("CreateSpanDeleter").Return(deleter, nil)
+	assert.True(t, opts.InitSpanDeleter(factory, logger))
+	assert.Equal(t, deleter, opts.SpanDeleter)
+}
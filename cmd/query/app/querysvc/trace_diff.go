@@ -0,0 +1,247 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querysvc
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+const (
+	diffPathSeparator       = " > "
+	diffNameSeparator       = "::"
+	diffOccurrenceSeparator = "#"
+)
+
+// DiffStatus describes whether a node of the aligned span trees exists in one or both traces.
+type DiffStatus int
+
+const (
+	// DiffStatusCommon means the node is present in both traces.
+	DiffStatusCommon DiffStatus = iota
+	// DiffStatusAdded means the node is present only in the second trace.
+	DiffStatusAdded
+	// DiffStatusRemoved means the node is present only in the first trace.
+	DiffStatusRemoved
+)
+
+func (s DiffStatus) String() string {
+	switch s {
+	case DiffStatusAdded:
+		return "added"
+	case DiffStatusRemoved:
+		return "removed"
+	default:
+		return "common"
+	}
+}
+
+// TraceDiff is the result of comparing two traces.
+type TraceDiff struct {
+	TraceIDA model.TraceID
+	TraceIDB model.TraceID
+	// Nodes are sorted by Path, so that every parent precedes its children.
+	Nodes []*TraceDiffNode
+}
+
+// TraceDiffNode is a node of the two span trees aligned by service+operation path.
+type TraceDiffNode struct {
+	// Path is the sequence of "service::operation" names from the root span to this node.
+	// Siblings with the same name are disambiguated by start time order, e.g. "svc::op#1".
+	Path          string
+	ServiceName   string
+	OperationName string
+	Status        DiffStatus
+	// SpanIDA and SpanIDB are zero when the node is missing from the respective trace.
+	SpanIDA   model.SpanID
+	SpanIDB   model.SpanID
+	DurationA time.Duration
+	DurationB time.Duration
+	// DurationDelta is DurationB - DurationA.
+	DurationDelta time.Duration
+	TagDiffs      []TagDiff
+}
+
+// TagDiff is a span tag that has different values in the two traces.
+// ValueA or ValueB is nil when the tag is missing from the respective span.
+type TagDiff struct {
+	Key    string
+	ValueA *model.KeyValue
+	ValueB *model.KeyValue
+}
+
+// CompareTraces fetches two traces and aligns their span trees by service+operation path,
+// reporting added and removed spans, duration deltas and differing tags for every node.
+func (qs QueryService) CompareTraces(ctx context.Context, traceIDA, traceIDB model.TraceID) (*TraceDiff, error) {
+	traceA, err := qs.getAdjustedTrace(ctx, traceIDA)
+	if err != nil {
+		return nil, err
+	}
+	traceB, err := qs.getAdjustedTrace(ctx, traceIDB)
+	if err != nil {
+		return nil, err
+	}
+	diff := DiffTraces(traceA, traceB)
+	diff.TraceIDA = traceIDA
+	diff.TraceIDB = traceIDB
+	return diff, nil
+}
+
+func (qs QueryService) getAdjustedTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
+	trace, err := qs.GetTrace(ctx, traceID)
+	if err != nil {
+		return nil, err
+	}
+	// adjusters return a usable trace even when they fail, the error only carries warnings
+	adjusted, _ := qs.Adjust(trace)
+	if adjusted == nil {
+		return trace, nil
+	}
+	return adjusted, nil
+}
+
+// DiffTraces aligns the span trees of two traces by service+operation path.
+func DiffTraces(traceA, traceB *model.Trace) *TraceDiff {
+	nodesA := spanTreePaths(traceA)
+	nodesB := spanTreePaths(traceB)
+
+	paths := make([]string, 0, len(nodesA)+len(nodesB))
+	for path := range nodesA {
+		paths = append(paths, path)
+	}
+	for path := range nodesB {
+		if _, ok := nodesA[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	diff := &TraceDiff{Nodes: make([]*TraceDiffNode, 0, len(paths))}
+	for _, path := range paths {
+		diff.Nodes = append(diff.Nodes, diffNode(path, nodesA[path], nodesB[path]))
+	}
+	return diff
+}
+
+func diffNode(path string, spanA, spanB *model.Span) *TraceDiffNode {
+	node := &TraceDiffNode{Path: path}
+	switch {
+	case spanA == nil:
+		node.Status = DiffStatusAdded
+	case spanB == nil:
+		node.Status = DiffStatusRemoved
+	default:
+		node.Status = DiffStatusCommon
+		node.TagDiffs = diffTags(spanA.Tags, spanB.Tags)
+	}
+	if spanA != nil {
+		node.ServiceName, node.OperationName = serviceName(spanA), spanA.OperationName
+		node.SpanIDA = spanA.SpanID
+		node.DurationA = spanA.Duration
+	}
+	if spanB != nil {
+		node.ServiceName, node.OperationName = serviceName(spanB), spanB.OperationName
+		node.SpanIDB = spanB.SpanID
+		node.DurationB = spanB.Duration
+	}
+	node.DurationDelta = node.DurationB - node.DurationA
+	return node
+}
+
+func diffTags(tagsA, tagsB model.KeyValues) []TagDiff {
+	byKeyA := tagsByKey(tagsA)
+	byKeyB := tagsByKey(tagsB)
+
+	var diffs []TagDiff
+	for key, a := range byKeyA {
+		if b, ok := byKeyB[key]; !ok || !a.Equal(b) {
+			diffs = append(diffs, TagDiff{Key: key, ValueA: a, ValueB: b})
+		}
+	}
+	for key, b := range byKeyB {
+		if _, ok := byKeyA[key]; !ok {
+			diffs = append(diffs, TagDiff{Key: key, ValueB: b})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Key < diffs[j].Key
+	})
+	return diffs
+}
+
+func tagsByKey(tags model.KeyValues) map[string]*model.KeyValue {
+	byKey := make(map[string]*model.KeyValue, len(tags))
+	for i := range tags {
+		byKey[tags[i].Key] = &tags[i]
+	}
+	return byKey
+}
+
+// spanTreePaths builds the span tree of the trace and returns its spans keyed by
+// service+operation path. Spans whose parent is not in the trace are treated as roots.
+func spanTreePaths(trace *model.Trace) map[string]*model.Span {
+	spanIDs := make(map[model.SpanID]struct{}, len(trace.Spans))
+	for _, span := range trace.Spans {
+		spanIDs[span.SpanID] = struct{}{}
+	}
+	var roots []*model.Span
+	children := make(map[model.SpanID][]*model.Span)
+	for _, span := range trace.Spans {
+		parentID := span.ParentSpanID()
+		if _, ok := spanIDs[parentID]; ok && parentID != span.SpanID {
+			children[parentID] = append(children[parentID], span)
+		} else {
+			roots = append(roots, span)
+		}
+	}
+
+	paths := make(map[string]*model.Span, len(trace.Spans))
+	var walk func(prefix string, spans []*model.Span)
+	walk = func(prefix string, spans []*model.Span) {
+		sort.Slice(spans, func(i, j int) bool {
+			if spans[i].StartTime.Equal(spans[j].StartTime) {
+				return spans[i].SpanID < spans[j].SpanID
+			}
+			return spans[i].StartTime.Before(spans[j].StartTime)
+		})
+		occurrences := make(map[string]int)
+		for _, span := range spans {
+			name := serviceName(span) + diffNameSeparator + span.OperationName
+			path := name
+			if prefix != "" {
+				path = prefix + diffPathSeparator + name
+			}
+			if n := occurrences[name]; n > 0 {
+				path += diffOccurrenceSeparator + strconv.Itoa(n)
+			}
+			occurrences[name]++
+			paths[path] = span
+			walk(path, children[span.SpanID])
+		}
+	}
+	walk("", roots)
+	return paths
+}
+
+func serviceName(span *model.Span) string {
+	if span.Process == nil {
+		return ""
+	}
+	return span.Process.ServiceName
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querysvc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+var (
+	diffTraceID   = model.NewTraceID(0, 1)
+	diffStartTime = time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func diffSpan(spanID, parentID uint64, service, operation string, start, duration time.Duration, tags ...model.KeyValue) *model.Span {
+	span := &model.Span{
+		TraceID:       diffTraceID,
+		SpanID:        model.NewSpanID(spanID),
+		OperationName: operation,
+		StartTime:     diffStartTime.Add(start),
+		Duration:      duration,
+		Process:       &model.Process{ServiceName: service},
+		Tags:          tags,
+	}
+	if parentID != 0 {
+		span.References = []model.SpanRef{model.NewChildOfRef(diffTraceID, model.NewSpanID(parentID))}
+	}
+	return span
+}
+
+func TestDiffTraces(t *testing.T) {
+	traceA := &model.Trace{
+		Spans: []*model.Span{
+			diffSpan(1, 0, "frontend", "GET /", 0, 100*time.Millisecond),
+			diffSpan(2, 1, "backend", "query", 10*time.Millisecond, 20*time.Millisecond,
+				model.String("db", "mysql"), model.Int64("rows", 10), model.Bool("cached", false)),
+			diffSpan(3, 1, "backend", "query", 40*time.Millisecond, 30*time.Millisecond),
+			diffSpan(4, 1, "cache", "get", 80*time.Millisecond, time.Millisecond),
+		},
+	}
+	traceB := &model.Trace{
+		Spans: []*model.Span{
+			diffSpan(11, 0, "frontend", "GET /", 0, 150*time.Millisecond),
+			diffSpan(12, 11, "backend", "query", 10*time.Millisecond, 50*time.Millisecond,
+				model.String("db", "mysql"), model.Int64("rows", 20), model.String("user", "x")),
+			diffSpan(13, 12, "mysql", "select", 15*time.Millisecond, 40*time.Millisecond),
+		},
+	}
+
+	diff := DiffTraces(traceA, traceB)
+	paths := make([]string, len(diff.Nodes))
+	for i, node := range diff.Nodes {
+		paths[i] = node.Path
+	}
+	assert.Equal(t, []string{
+		"frontend::GET /",
+		"frontend::GET / > backend::query",
+		"frontend::GET / > backend::query > mysql::select",
+		"frontend::GET / > backend::query#1",
+		"frontend::GET / > cache::get",
+	}, paths)
+
+	root := diff.Nodes[0]
+	assert.Equal(t, DiffStatusCommon, root.Status)
+	assert.Equal(t, "frontend", root.ServiceName)
+	assert.Equal(t, "GET /", root.OperationName)
+	assert.Equal(t, model.NewSpanID(1), root.SpanIDA)
+	assert.Equal(t, model.NewSpanID(11), root.SpanIDB)
+	assert.Equal(t, 50*time.Millisecond, root.DurationDelta)
+	assert.Empty(t, root.TagDiffs)
+
+	query := diff.Nodes[1]
+	assert.Equal(t, DiffStatusCommon, query.Status)
+	assert.Equal(t, 30*time.Millisecond, query.DurationDelta)
+	rowsA, rowsB := model.Int64("rows", 10), model.Int64("rows", 20)
+	cached, user := model.Bool("cached", false), model.String("user", "x")
+	assert.Equal(t, []TagDiff{
+		{Key: "cached", ValueA: &cached},
+		{Key: "rows", ValueA: &rowsA, ValueB: &rowsB},
+		{Key: "user", ValueB: &user},
+	}, query.TagDiffs)
+
+	added := diff.Nodes[2]
+	assert.Equal(t, DiffStatusAdded, added.Status)
+	assert.Equal(t, "mysql", added.ServiceName)
+	assert.Equal(t, model.SpanID(0), added.SpanIDA)
+	assert.Equal(t, model.NewSpanID(13), added.SpanIDB)
+	assert.Equal(t, 40*time.Millisecond, added.DurationDelta)
+
+	for _, removed := range diff.Nodes[3:] {
+		assert.Equal(t, DiffStatusRemoved, removed.Status)
+		assert.Equal(t, model.SpanID(0), removed.SpanIDB)
+		assert.Equal(t, -removed.DurationA, removed.DurationDelta)
+	}
+	assert.Equal(t, model.NewSpanID(3), diff.Nodes[3].SpanIDA)
+}
+
+func TestDiffTracesOrphansAreRoots(t *testing.T) {
+	trace := &model.Trace{
+		Spans: []*model.Span{
+			diffSpan(2, 1, "svc", "orphan", 0, time.Millisecond),
+			diffSpan(3, 2, "svc", "child", 0, time.Millisecond),
+		},
+	}
+	diff := DiffTraces(trace, trace)
+	require.Len(t, diff.Nodes, 2)
+	assert.Equal(t, "svc::orphan", diff.Nodes[0].Path)
+	assert.Equal(t, "svc::orphan > svc::child", diff.Nodes[1].Path)
+	for _, node := range diff.Nodes {
+		assert.Equal(t, DiffStatusCommon, node.Status)
+		assert.Equal(t, time.Duration(0), node.DurationDelta)
+	}
+}
+
+func TestDiffStatusString(t *testing.T) {
+	assert.Equal(t, "common", DiffStatusCommon.String())
+	assert.Equal(t, "added", DiffStatusAdded.String())
+	assert.Equal(t, "removed", DiffStatusRemoved.String())
+}
+
+func TestCompareTraces(t *testing.T) {
+	traceIDA := model.NewTraceID(0, 1)
+	traceIDB := model.NewTraceID(0, 2)
+	qs, readMock, _ := initializeTestService()
+	readMock.On("GetTrace", mock.Anything, traceIDA).
+		Return(&model.Trace{Spans: []*model.Span{diffSpan(1, 0, "svc", "op", 0, time.Millisecond)}}, nil).Once()
+	readMock.On("GetTrace", mock.Anything, traceIDB).
+		Return(&model.Trace{Spans: []*model.Span{diffSpan(2, 0, "svc", "op", 0, 3*time.Millisecond)}}, nil).Once()
+
+	diff, err := qs.CompareTraces(context.Background(), traceIDA, traceIDB)
+	require.NoError(t, err)
+	assert.Equal(t, traceIDA, diff.TraceIDA)
+	assert.Equal(t, traceIDB, diff.TraceIDB)
+	require.Len(t, diff.Nodes, 1)
+	assert.Equal(t, 2*time.Millisecond, diff.Nodes[0].DurationDelta)
+}
+
+func TestCompareTracesNotFound(t *testing.T) {
+	traceIDA := model.NewTraceID(0, 1)
+	traceIDB := model.NewTraceID(0, 2)
+	qs, readMock, _ := initializeTestService()
+	readMock.On("GetTrace", mock.Anything, traceIDA).Return(mockTrace, nil).Once()
+	readMock.On("GetTrace", mock.Anything, traceIDB).Return(nil, spanstore.ErrTraceNotFound).Once()
+
+	_, err := qs.CompareTraces(context.Background(), traceIDA, traceIDB)
+	assert.Equal(t, spanstore.ErrTraceNotFound, err)
+}
@@ -15,21 +15,29 @@
 package app
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"strings"
 
 	"github.com/gorilla/handlers"
+	grpcMiddleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
 	"github.com/soheilhy/cmux"
 	"go.uber.org/zap"
+	"golang.org/x/net/http2"
 	"google.golang.org/grpc"
 
 	"github.com/jaegertracing/jaeger/cmd/flags"
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/pkg/auth"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
 	"github.com/jaegertracing/jaeger/pkg/recoveryhandler"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
 )
 
@@ -42,33 +50,109 @@ type Server struct {
 	tracer opentracing.Tracer // TODO make part of flags.Service
 
 	conn       net.Listener
+	tlsConfig  *tls.Config
 	grpcServer *grpc.Server
 	httpServer *http.Server
 }
 
 // NewServer creates and initializes Server
-func NewServer(svc *flags.Service, querySvc *querysvc.QueryService, options *QueryOptions, tracer opentracing.Tracer) *Server {
+func NewServer(svc *flags.Service, querySvc *querysvc.QueryService, options *QueryOptions, tenancyMgr *tenancy.Manager, tracer opentracing.Tracer) (*Server, error) {
+	tlsConfig, err := createTLSConfig(options)
+	if err != nil {
+		return nil, err
+	}
+	authenticator, err := auth.NewAuthenticatorFromFiles(options.BearerTokensFile, options.HtpasswdFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(options.AdminPrincipals) > 0 && authenticator == nil {
+		return nil, errors.New("admin principals require bearer token or htpasswd authentication")
+	}
 	return &Server{
 		svc:          svc,
 		querySvc:     querySvc,
 		queryOptions: options,
 		tracer:       tracer,
-		grpcServer:   createGRPCServer(querySvc, svc.Logger, tracer),
-		httpServer:   createHTTPServer(querySvc, options, tracer, svc.Logger),
+		tlsConfig:    tlsConfig,
+		grpcServer:   createGRPCServer(querySvc, authenticator, options.AdminPrincipals, tenancyMgr, svc.Logger, tracer),
+		httpServer:   createHTTPServer(querySvc, authenticator, tenancyMgr, options, tracer, svc.Logger),
+	}, nil
+}
+
+func createTLSConfig(options *QueryOptions) (*tls.Config, error) {
+	if !options.TLS {
+		return nil, nil
+	}
+	if options.TLSCert == "" || options.TLSKey == "" {
+		return nil, errors.New("you requested TLS but configuration does not include a path to cert and/or key")
+	}
+	cert, err := tls.LoadX509KeyPair(options.TLSCert, options.TLSKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load TLS keys")
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{http2.NextProtoTLS, "http/1.1"},
 	}
+	if options.TLSClientCA != "" {
+		caPEM, err := ioutil.ReadFile(options.TLSClientCA) /* nolint #nosec , this comes from an admin, not user */
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load TLS client CA")
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(caPEM) {
+			return nil, errors.Errorf("no certificates found in TLS client CA file %s", options.TLSClientCA)
+		}
+		tlsConfig.ClientCAs = clientCAs
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
 }
 
-func createGRPCServer(querySvc *querysvc.QueryService, logger *zap.Logger, tracer opentracing.Tracer) *grpc.Server {
-	srv := grpc.NewServer()
+func createGRPCServer(
+	querySvc *querysvc.QueryService,
+	authenticator auth.Authenticator,
+	adminPrincipals []string,
+	tenancyMgr *tenancy.Manager,
+	logger *zap.Logger,
+	tracer opentracing.Tracer,
+) *grpc.Server {
+	// authentication runs before the tenant is checked
+	var unaryInterceptors []grpc.UnaryServerInterceptor
+	var streamInterceptors []grpc.StreamServerInterceptor
+	if authenticator != nil {
+		unaryInterceptors = append(unaryInterceptors, auth.UnaryServerInterceptor(authenticator))
+		streamInterceptors = append(streamInterceptors, auth.StreamServerInterceptor(authenticator))
+	}
+	if tenancyMgr.Enabled {
+		unaryInterceptors = append(unaryInterceptors, tenancy.NewGuardingUnaryInterceptor(tenancyMgr))
+		streamInterceptors = append(streamInterceptors, tenancy.NewGuardingStreamInterceptor(tenancyMgr))
+	}
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(grpcMiddleware.ChainUnaryServer(unaryInterceptors...)),
+		grpc.StreamInterceptor(grpcMiddleware.ChainStreamServer(streamInterceptors...)))
 	handler := NewGRPCHandler(querySvc, logger, tracer)
+	handler.admin = newAdminGuard(adminPrincipals, logger)
 	api_v2.RegisterQueryServiceServer(srv, handler)
 	return srv
 }
 
-func createHTTPServer(querySvc *querysvc.QueryService, queryOpts *QueryOptions, tracer opentracing.Tracer, logger *zap.Logger) *http.Server {
+func createHTTPServer(
+	querySvc *querysvc.QueryService,
+	authenticator auth.Authenticator,
+	tenancyMgr *tenancy.Manager,
+	queryOpts *QueryOptions,
+	tracer opentracing.Tracer,
+	logger *zap.Logger,
+) *http.Server {
 	apiHandlerOptions := []HandlerOption{
 		HandlerOptions.Logger(logger),
 		HandlerOptions.Tracer(tracer),
+		HandlerOptions.Tenancy(tenancyMgr),
+		HandlerOptions.AdminPrincipals(queryOpts.AdminPrincipals),
+	}
+	if authenticator != nil {
+		apiHandlerOptions = append(apiHandlerOptions, HandlerOptions.Authenticator(authenticator))
 	}
 	apiHandler := NewAPIHandler(
 		querySvc,
@@ -94,6 +178,10 @@ func (s *Server) Start() error {
 	if err != nil {
 		return err
 	}
+	if s.tlsConfig != nil {
+		// TLS is terminated before cmux, so that both HTTP and GRPC are served over it.
+		conn = tls.NewListener(conn, s.tlsConfig)
+	}
 	s.conn = conn
 
 	// cmux server acts as a reverse-proxy between HTTP and GRPC backends.
@@ -102,6 +190,12 @@ func (s *Server) Start() error {
 	grpcListener := cmuxServer.Match(
 		cmux.HTTP2HeaderField("content-type", "application/grpc"),
 		cmux.HTTP2HeaderField("content-type", "application/grpc+proto"))
+	if s.tlsConfig != nil {
+		// Browsers negotiate HTTP/2 over TLS, which http.Server cannot detect behind cmux,
+		// so HTTP/2 connections are served explicitly.
+		http2Listener := cmuxServer.Match(cmux.HTTP2())
+		go s.serveHTTP2(http2Listener)
+	}
 	httpListener := cmuxServer.Match(cmux.Any())
 
 	go func() {
@@ -141,6 +235,20 @@ func (s *Server) Start() error {
 	return nil
 }
 
+func (s *Server) serveHTTP2(listener net.Listener) {
+	h2Server := &http2.Server{}
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go h2Server.ServeConn(conn, &http2.ServeConnOpts{
+			BaseConfig: s.httpServer,
+			Handler:    s.httpServer.Handler,
+		})
+	}
+}
+
 // Close stops http, GRPC servers and closes the port listener.
 func (s *Server) Close() {
 	s.grpcServer.Stop()
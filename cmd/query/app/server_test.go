@@ -15,19 +15,46 @@
 package app
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
+	"golang.org/x/net/http2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/jaegertracing/jaeger/cmd/flags"
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/ports"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+	depsmocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
+	storagemocks "github.com/jaegertracing/jaeger/storage/mocks"
+	spanstoremocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
 )
 
 func TestServerError(t *testing.T) {
@@ -46,7 +73,8 @@ func TestServer(t *testing.T) {
 	querySvc := &querysvc.QueryService{}
 	tracer := opentracing.NoopTracer{}
 
-	server := NewServer(flagsSvc, querySvc, &QueryOptions{Port: ports.QueryAdminHTTP}, tracer)
+	server, err := NewServer(flagsSvc, querySvc, &QueryOptions{Port: ports.QueryAdminHTTP}, &tenancy.Manager{}, tracer)
+	require.NoError(t, err)
 	assert.NoError(t, server.Start())
 
 	// TODO wait for servers to come up and test http and grpc endpoints
@@ -72,7 +100,8 @@ func TestServerGracefulExit(t *testing.T) {
 
 	querySvc := &querysvc.QueryService{}
 	tracer := opentracing.NoopTracer{}
-	server := NewServer(flagsSvc, querySvc, &QueryOptions{Port: ports.QueryAdminHTTP}, tracer)
+	server, err := NewServer(flagsSvc, querySvc, &QueryOptions{Port: ports.QueryAdminHTTP}, &tenancy.Manager{}, tracer)
+	require.NoError(t, err)
 	assert.NoError(t, server.Start())
 
 	// Wait for servers to come up before we can call .Close()
@@ -84,3 +113,260 @@ func TestServerGracefulExit(t *testing.T) {
 			fmt.Sprintf("Error log found on server exit: %v", logEntry))
 	}
 }
+
+// testCerts holds the paths of a self-signed CA and the server and client certificates it issued.
+type testCerts struct {
+	dir        string
+	caFile     string
+	serverCert string
+	serverKey  string
+	clientCert string
+	clientKey  string
+}
+
+func generateTestCerts(t *testing.T) *testCerts {
+	dir, err := ioutil.TempDir("", "query-tls")
+	require.NoError(t, err)
+	certs := &testCerts{
+		dir:        dir,
+		caFile:     filepath.Join(dir, "ca.pem"),
+		serverCert: filepath.Join(dir, "server.pem"),
+		serverKey:  filepath.Join(dir, "server-key.pem"),
+		clientCert: filepath.Join(dir, "client.pem"),
+		clientKey:  filepath.Join(dir, "client-key.pem"),
+	}
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "jaeger-test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	writePEM(t, certs.caFile, "CERTIFICATE", caDER)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	issue := func(serial int64, usage x509.ExtKeyUsage, certFile, keyFile string) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: "localhost"},
+			DNSNames:     []string{"localhost"},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		writePEM(t, certFile, "CERTIFICATE", der)
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+		writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+	}
+	issue(2, x509.ExtKeyUsageServerAuth, certs.serverCert, certs.serverKey)
+	issue(3, x509.ExtKeyUsageClientAuth, certs.clientCert, certs.clientKey)
+	return certs
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	content := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	require.NoError(t, ioutil.WriteFile(path, content, 0600))
+}
+
+func (c *testCerts) clientTLSConfig(t *testing.T, withClientCert bool) *tls.Config {
+	caPEM, err := ioutil.ReadFile(c.caFile)
+	require.NoError(t, err)
+	rootCAs := x509.NewCertPool()
+	require.True(t, rootCAs.AppendCertsFromPEM(caPEM))
+	config := &tls.Config{
+		RootCAs:    rootCAs,
+		ServerName: "localhost",
+	}
+	if withClientCert {
+		cert, err := tls.LoadX509KeyPair(c.clientCert, c.clientKey)
+		require.NoError(t, err)
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config
+}
+
+func freePort(t *testing.T) int {
+	l, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func TestNewServerErrors(t *testing.T) {
+	certs := generateTestCerts(t)
+	defer os.RemoveAll(certs.dir)
+
+	flagsSvc := flags.NewService(ports.QueryAdminHTTP)
+	flagsSvc.Logger = zap.NewNop()
+	testCases := []struct {
+		name    string
+		options *QueryOptions
+		err     string
+	}{
+		{
+			name:    "missing key",
+			options: &QueryOptions{TLS: true, TLSCert: certs.serverCert},
+			err:     "you requested TLS but configuration does not include a path to cert and/or key",
+		},
+		{
+			name:    "invalid cert",
+			options: &QueryOptions{TLS: true, TLSCert: "invalid-file-name", TLSKey: certs.serverKey},
+			err:     "failed to load TLS keys",
+		},
+		{
+			name:    "invalid client CA",
+			options: &QueryOptions{TLS: true, TLSCert: certs.serverCert, TLSKey: certs.serverKey, TLSClientCA: "invalid-file-name"},
+			err:     "failed to load TLS client CA",
+		},
+		{
+			name:    "client CA without certificates",
+			options: &QueryOptions{TLS: true, TLSCert: certs.serverCert, TLSKey: certs.serverKey, TLSClientCA: certs.serverKey},
+			err:     "no certificates found in TLS client CA file",
+		},
+		{
+			name:    "invalid bearer tokens file",
+			options: &QueryOptions{BearerTokensFile: "invalid-file-name"},
+			err:     "failed to read bearer tokens file",
+		},
+		{
+			name:    "invalid htpasswd file",
+			options: &QueryOptions{HtpasswdFile: "invalid-file-name"},
+			err:     "failed to read htpasswd file",
+		},
+		{
+			name:    "admin principals without authentication",
+			options: &QueryOptions{AdminPrincipals: []string{"admin"}},
+			err:     "admin principals require bearer token or htpasswd authentication",
+		},
+	}
+	for _, testCase := range testCases {
+		test := testCase // capture loop var
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewServer(flagsSvc, &querysvc.QueryService{}, test.options, &tenancy.Manager{}, opentracing.NoopTracer{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.err)
+		})
+	}
+}
+
+func TestServerTLSAndAuthentication(t *testing.T) {
+	certs := generateTestCerts(t)
+	defer os.RemoveAll(certs.dir)
+	tokensFile := filepath.Join(certs.dir, "tokens")
+	require.NoError(t, ioutil.WriteFile(tokensFile, []byte("robot:secret\n"), 0600))
+
+	flagsSvc := flags.NewService(ports.QueryAdminHTTP)
+	flagsSvc.Logger = zap.NewNop()
+	spanReader := &spanstoremocks.Reader{}
+	spanReader.On("GetServices", mock.Anything).Return([]string{"trifle"}, nil)
+	querySvc := querysvc.NewQueryService(spanReader, &depsmocks.Reader{}, querysvc.QueryServiceOptions{})
+	port := freePort(t)
+	server, err := NewServer(flagsSvc, querySvc, &QueryOptions{
+		Port:             port,
+		BasePath:         "/",
+		TLS:              true,
+		TLSCert:          certs.serverCert,
+		TLSKey:           certs.serverKey,
+		TLSClientCA:      certs.caFile,
+		BearerTokensFile: tokensFile,
+	}, &tenancy.Manager{}, opentracing.NoopTracer{})
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	defer server.Close()
+	url := fmt.Sprintf("https://localhost:%d/api/services", port)
+
+	t.Run("HTTP/1.1", func(t *testing.T) {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: certs.clientTLSConfig(t, true)}}
+		testHTTPAuthentication(t, client, url)
+	})
+
+	t.Run("HTTP/2", func(t *testing.T) {
+		client := &http.Client{Transport: &http2.Transport{TLSClientConfig: certs.clientTLSConfig(t, true)}}
+		testHTTPAuthentication(t, client, url)
+	})
+
+	t.Run("HTTP without client certificate", func(t *testing.T) {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: certs.clientTLSConfig(t, false)}}
+		_, err := client.Get(url)
+		assert.Error(t, err)
+	})
+
+	t.Run("gRPC", func(t *testing.T) {
+		conn, err := grpc.Dial(
+			fmt.Sprintf("localhost:%d", port),
+			grpc.WithTransportCredentials(credentials.NewTLS(certs.clientTLSConfig(t, true))))
+		require.NoError(t, err)
+		defer conn.Close()
+		client := api_v2.NewQueryServiceClient(conn)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err = client.GetServices(ctx, &api_v2.GetServicesRequest{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		authCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer secret")
+		res, err := client.GetServices(authCtx, &api_v2.GetServicesRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"trifle"}, res.Services)
+	})
+}
+
+func testHTTPAuthentication(t *testing.T, client *http.Client, url string) {
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, `Bearer realm="jaeger"`, resp.Header.Get("WWW-Authenticate"))
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestGRPCServerTenancy(t *testing.T) {
+	tenancyMgr, err := tenancy.NewManager(&tenancy.Options{Enabled: true})
+	require.NoError(t, err)
+	acmeReader := &spanstoremocks.Reader{}
+	acmeReader.On("GetServices", mock.Anything).Return([]string{"trifle"}, nil)
+	tenantFactory := &storagemocks.TenantFactory{}
+	tenantFactory.On("CreateTenantSpanReader", "acme").Return(acmeReader, nil)
+	querySvc := querysvc.NewQueryService(&spanstoremocks.Reader{}, &depsmocks.Reader{}, querysvc.QueryServiceOptions{TenantFactory: tenantFactory})
+
+	grpcServer := createGRPCServer(querySvc, nil, nil, tenancyMgr, zap.NewNop(), opentracing.NoopTracer{})
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	go grpcServer.Serve(lis)
+	defer grpcServer.Stop()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+	client := api_v2.NewQueryServiceClient(conn)
+
+	_, err = client.GetServices(context.Background(), &api_v2.GetServicesRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), tenancyMgr.Header, "acme")
+	res, err := client.GetServices(ctx, &api_v2.GetServicesRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"trifle"}, res.Services)
+}
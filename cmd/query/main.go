@@ -34,6 +34,7 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/query/app"
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/pkg/version"
 	"github.com/jaegertracing/jaeger/plugin/storage"
 	"github.com/jaegertracing/jaeger/ports"
@@ -92,14 +93,32 @@ func main() {
 			if err != nil {
 				logger.Fatal("Failed to create dependency reader", zap.Error(err))
 			}
-			queryServiceOptions := archiveOptions(storageFactory, logger)
+			tenancyOpts := tenancy.Options{}.InitFromViper(v)
+			tenancyMgr, err := tenancy.NewManager(&tenancyOpts)
+			if err != nil {
+				logger.Fatal("Failed to init multi-tenancy", zap.Error(err))
+			}
+			var queryServiceOptions *querysvc.QueryServiceOptions
+			if tenancyMgr.Enabled {
+				if err := storageFactory.CheckMultiTenancy(); err != nil {
+					logger.Fatal("The storage cannot be used with multi-tenancy", zap.Error(err))
+				}
+				// archived traces are not kept apart per tenant, so the archive storage is not used
+				queryServiceOptions = &querysvc.QueryServiceOptions{TenantFactory: storageFactory}
+			} else {
+				queryServiceOptions = archiveOptions(storageFactory, logger)
+				queryServiceOptions.InitSpanDeleter(storageFactory, logger)
+			}
 			queryService := querysvc.NewQueryService(
 				spanReader,
 				dependencyReader,
 				*queryServiceOptions)
 
 			queryOpts := new(app.QueryOptions).InitFromViper(v)
-			server := app.NewServer(svc, queryService, queryOpts, tracer)
+			server, err := app.NewServer(svc, queryService, queryOpts, tenancyMgr, tracer)
+			if err != nil {
+				logger.Fatal("Failed to create server", zap.Error(err))
+			}
 
 			if err := server.Start(); err != nil {
 				logger.Fatal("Could not start servers", zap.Error(err))
@@ -107,6 +126,9 @@ func main() {
 
 			svc.RunAndThen(func() {
 				server.Close()
+				if err := storageFactory.Close(); err != nil {
+					logger.Error("Failed to close storage factory", zap.Error(err))
+				}
 			})
 			return nil
 		},
@@ -122,6 +144,7 @@ func main() {
 		svc.AddFlags,
 		storageFactory.AddFlags,
 		app.AddFlags,
+		tenancy.AddFlags,
 	)
 
 	if error := command.Execute(); error != nil {
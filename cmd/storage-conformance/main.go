@@ -0,0 +1,89 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/flags"
+	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/version"
+	"github.com/jaegertracing/jaeger/plugin/storage/grpc"
+	"github.com/jaegertracing/jaeger/storage/spanstore/spanstoretest"
+)
+
+func main() {
+	v := viper.New()
+	options := &grpc.Options{}
+	command := &cobra.Command{
+		Use:   "jaeger-storage-conformance [TEST...]",
+		Short: "Jaeger storage-conformance checks that a storage plugin behaves like the Jaeger storages.",
+		Long: `Jaeger storage-conformance runs the conformance tests of the Jaeger storages against a storage plugin ` +
+			`and prints a compatibility report. The plugin is restarted before each test, so that each test runs ` +
+			`against an empty storage. The tests are ` + strings.Join(spanstoretest.TestNames(), ", ") + `, all of them run by default.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := flags.TryLoadConfigFile(v); err != nil {
+				return err
+			}
+			logger, err := new(flags.SharedFlags).InitFromViper(v).NewLogger(zap.NewProductionConfig())
+			if err != nil {
+				return err
+			}
+			options.InitFromViper(v)
+			if options.Configuration.PluginBinary == "" {
+				return errors.New("the storage plugin binary must be set with --grpc-storage-plugin.binary")
+			}
+
+			// the usage does not help with a failing plugin
+			cmd.SilenceUsage = true
+
+			logger.Info("Checking the storage plugin", zap.Any("configuration", options.Configuration))
+			suite, err := spanstoretest.NewPluginSuite(options.Configuration.Build)
+			if err != nil {
+				return err
+			}
+			report := suite.Report(args...)
+			if _, err := report.WriteTo(os.Stdout); err != nil {
+				return err
+			}
+			if !report.Compatible() {
+				return errors.New("the storage plugin is not compatible")
+			}
+			return nil
+		},
+	}
+
+	command.AddCommand(version.Command())
+
+	config.AddFlags(
+		v,
+		command,
+		flags.AddConfigFileFlag,
+		flags.AddLoggingFlag,
+		options.AddFlags,
+	)
+
+	if err := command.Execute(); err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+}
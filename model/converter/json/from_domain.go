@@ -189,3 +189,8 @@ func DependenciesFromDomain(dependencyLinks []model.DependencyLink) []json.Depen
 	}
 	return retMe
 }
+
+// KeyValuesFromDomain converts model.KeyValues into []json.KeyValue format with typed values.
+func KeyValuesFromDomain(keyValues model.KeyValues) []json.KeyValue {
+	return fromDomain{}.convertKeyValues(keyValues)
+}
@@ -177,3 +177,17 @@ func TestDependenciesFromDomain(t *testing.T) {
 	actual := DependenciesFromDomain(input)
 	assert.EqualValues(t, expected, actual)
 }
+
+func TestKeyValuesFromDomain(t *testing.T) {
+	input := model.KeyValues{
+		model.String("str", "value"),
+		model.Bool("bool", true),
+		model.Int64("int", 42),
+	}
+	expected := []jModel.KeyValue{
+		{Key: "str", Type: jModel.StringType, Value: "value"},
+		{Key: "bool", Type: jModel.BoolType, Value: true},
+		{Key: "int", Type: jModel.Int64Type, Value: int64(42)},
+	}
+	assert.Equal(t, expected, KeyValuesFromDomain(input))
+}
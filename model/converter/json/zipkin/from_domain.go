@@ -0,0 +1,175 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/opentracing/opentracing-go/ext"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/swagger-gen/models"
+)
+
+const (
+	// DefaultLogFieldKey is the log field key which translates directly into Zipkin's Annotation.Value,
+	// provided it's the only field in the log.
+	// In all other cases the fields are encoded into Annotation.Value as JSON string.
+	DefaultLogFieldKey = "event"
+
+	// IPTagName is the Jaeger process tag name for an IPv4/IPv6 IP address.
+	IPTagName = "ip"
+)
+
+var spanKinds = map[string]string{
+	string(ext.SpanKindRPCClientEnum): models.SpanKindCLIENT,
+	string(ext.SpanKindRPCServerEnum): models.SpanKindSERVER,
+	string(ext.SpanKindProducerEnum):  models.SpanKindPRODUCER,
+	string(ext.SpanKindConsumerEnum):  models.SpanKindCONSUMER,
+}
+
+// FromDomain converts the spans of model.Trace to the Zipkin v2 JSON data model.
+func FromDomain(trace *model.Trace) models.ListOfSpans {
+	spans := make(models.ListOfSpans, 0, len(trace.Spans))
+	for _, span := range trace.Spans {
+		spans = append(spans, FromDomainSpan(span))
+	}
+	return spans
+}
+
+// FromDomainSpan converts model.Span to the Zipkin v2 JSON data model.
+// Zipkin has no notion of process tags, so only the service name and
+// the IP address of the process are preserved, in the local endpoint.
+// Peer tags are converted to the remote endpoint, and the span kind tag to the span kind.
+func FromDomainSpan(span *model.Span) *models.Span {
+	traceID := traceIDToZipkin(span.TraceID)
+	spanID := spanIDToZipkin(span.SpanID)
+	zSpan := &models.Span{
+		TraceID:       &traceID,
+		ID:            &spanID,
+		Name:          span.OperationName,
+		Timestamp:     int64(model.TimeAsEpochMicroseconds(span.StartTime)),
+		Duration:      int64(model.DurationAsMicroseconds(span.Duration)),
+		Debug:         span.Flags.IsDebug(),
+		LocalEndpoint: localEndpointFromDomain(span.Process),
+	}
+	if parentID := span.ParentSpanID(); parentID != 0 {
+		zSpan.ParentID = spanIDToZipkin(parentID)
+	}
+
+	tags := make(models.Tags, len(span.Tags))
+	remote := &models.Endpoint{}
+	for _, tag := range span.Tags {
+		switch tag.Key {
+		case string(ext.SpanKind):
+			if kind, ok := spanKinds[tag.AsString()]; ok {
+				zSpan.Kind = kind
+				continue
+			}
+		case string(ext.PeerService):
+			remote.ServiceName = tag.AsString()
+			continue
+		case string(ext.PeerHostIPv4), string(ext.PeerHostIPv6):
+			if ip := ipFromTag(tag); ip != nil {
+				setEndpointIP(remote, ip)
+				continue
+			}
+		case string(ext.PeerPort):
+			if tag.VType == model.Int64Type {
+				remote.Port = tag.Int64()
+				continue
+			}
+		}
+		tags[tag.Key] = tag.AsString()
+	}
+	if len(tags) > 0 {
+		zSpan.Tags = tags
+	}
+	if *remote != (models.Endpoint{}) {
+		zSpan.RemoteEndpoint = remote
+	}
+
+	for _, log := range span.Logs {
+		zSpan.Annotations = append(zSpan.Annotations, &models.Annotation{
+			Timestamp: int64(model.TimeAsEpochMicroseconds(log.Timestamp)),
+			Value:     annotationValue(log.Fields),
+		})
+	}
+	return zSpan
+}
+
+func localEndpointFromDomain(process *model.Process) *models.Endpoint {
+	if process == nil {
+		return nil
+	}
+	endpoint := &models.Endpoint{ServiceName: process.ServiceName}
+	if tag, ok := model.KeyValues(process.Tags).FindByKey(IPTagName); ok {
+		if ip := ipFromTag(tag); ip != nil {
+			setEndpointIP(endpoint, ip)
+		}
+	}
+	return endpoint
+}
+
+// ipFromTag parses an IP address stored as a string, an int64 (IPv4 only) or bytes.
+func ipFromTag(tag model.KeyValue) net.IP {
+	switch tag.VType {
+	case model.StringType:
+		return net.ParseIP(tag.VStr)
+	case model.Int64Type:
+		ip := uint32(tag.Int64())
+		return net.IPv4(byte(ip>>24), byte(ip>>16), byte(ip>>8), byte(ip))
+	case model.BinaryType:
+		if l := len(tag.Binary()); l == net.IPv4len || l == net.IPv6len {
+			return net.IP(tag.Binary())
+		}
+	}
+	return nil
+}
+
+func setEndpointIP(endpoint *models.Endpoint, ip net.IP) {
+	if ipv4 := ip.To4(); ipv4 != nil {
+		endpoint.IPV4 = strfmt.IPv4(ipv4.String())
+	} else {
+		endpoint.IPV6 = strfmt.IPv6(ip.String())
+	}
+}
+
+func annotationValue(fields []model.KeyValue) string {
+	if len(fields) == 1 && fields[0].Key == DefaultLogFieldKey {
+		return fields[0].AsString()
+	}
+	values := make(map[string]string, len(fields))
+	for _, field := range fields {
+		values[field.Key] = field.AsString()
+	}
+	// encoding a map of strings never fails
+	value, _ := json.Marshal(values)
+	return string(value)
+}
+
+func traceIDToZipkin(traceID model.TraceID) string {
+	if traceID.High == 0 {
+		return fmt.Sprintf("%016x", traceID.Low)
+	}
+	return fmt.Sprintf("%016x%016x", traceID.High, traceID.Low)
+}
+
+func spanIDToZipkin(spanID model.SpanID) string {
+	return fmt.Sprintf("%016x", uint64(spanID))
+}
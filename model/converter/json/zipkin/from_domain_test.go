@@ -0,0 +1,126 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkin
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/swagger-gen/models"
+)
+
+var testStartTime = time.Unix(1500000000, 1000)
+
+func newTestSpan() *model.Span {
+	traceID := model.NewTraceID(1, 2)
+	return &model.Span{
+		TraceID:       traceID,
+		SpanID:        model.NewSpanID(3),
+		OperationName: "get",
+		References:    []model.SpanRef{model.NewChildOfRef(traceID, model.NewSpanID(4))},
+		Flags:         model.Flags(2),
+		StartTime:     testStartTime,
+		Duration:      5 * time.Millisecond,
+		Tags: []model.KeyValue{
+			model.String("span.kind", "client"),
+			model.String("peer.service", "redis"),
+			model.Int64("peer.ipv4", 0x0A000001),
+			model.Int64("peer.port", 6379),
+			model.Bool("error", true),
+			model.String("http.method", "GET"),
+		},
+		Logs: []model.Log{
+			{
+				Timestamp: testStartTime.Add(time.Millisecond),
+				Fields:    []model.KeyValue{model.String("event", "cache miss")},
+			},
+			{
+				Timestamp: testStartTime.Add(2 * time.Millisecond),
+				Fields:    []model.KeyValue{model.String("message", "retry"), model.Int64("attempt", 2)},
+			},
+		},
+		Process: model.NewProcess("frontend", []model.KeyValue{
+			model.String("ip", "192.168.0.1"),
+			model.String("hostname", "host"),
+		}),
+	}
+}
+
+func TestFromDomainSpan(t *testing.T) {
+	zSpan := FromDomainSpan(newTestSpan())
+	require.NoError(t, zSpan.Validate(strfmt.Default))
+
+	assert.Equal(t, "00000000000000010000000000000002", *zSpan.TraceID)
+	assert.Equal(t, "0000000000000003", *zSpan.ID)
+	assert.Equal(t, "0000000000000004", zSpan.ParentID)
+	assert.Equal(t, "get", zSpan.Name)
+	assert.Equal(t, models.SpanKindCLIENT, zSpan.Kind)
+	assert.Equal(t, int64(1500000000000001), zSpan.Timestamp)
+	assert.Equal(t, int64(5000), zSpan.Duration)
+	assert.True(t, zSpan.Debug)
+	assert.Equal(t, &models.Endpoint{ServiceName: "frontend", IPV4: "192.168.0.1"}, zSpan.LocalEndpoint)
+	assert.Equal(t, &models.Endpoint{ServiceName: "redis", IPV4: "10.0.0.1", Port: 6379}, zSpan.RemoteEndpoint)
+	assert.Equal(t, models.Tags{"error": "true", "http.method": "GET"}, zSpan.Tags)
+	require.Len(t, zSpan.Annotations, 2)
+	assert.Equal(t, &models.Annotation{Timestamp: 1500000000001001, Value: "cache miss"}, zSpan.Annotations[0])
+	var fields map[string]string
+	require.NoError(t, json.Unmarshal([]byte(zSpan.Annotations[1].Value), &fields))
+	assert.Equal(t, map[string]string{"message": "retry", "attempt": "2"}, fields)
+}
+
+func TestFromDomainSpanMinimal(t *testing.T) {
+	zSpan := FromDomainSpan(&model.Span{
+		TraceID: model.NewTraceID(0, 0xabc),
+		SpanID:  model.NewSpanID(1),
+		Tags:    []model.KeyValue{model.String("span.kind", "internal")},
+	})
+	require.NoError(t, zSpan.Validate(strfmt.Default))
+	assert.Equal(t, "0000000000000abc", *zSpan.TraceID)
+	assert.Empty(t, zSpan.ParentID)
+	assert.Empty(t, zSpan.Kind)
+	assert.Nil(t, zSpan.LocalEndpoint)
+	assert.Nil(t, zSpan.RemoteEndpoint)
+	assert.Equal(t, models.Tags{"span.kind": "internal"}, zSpan.Tags)
+}
+
+func TestFromDomainEndpointIP(t *testing.T) {
+	testCases := []struct {
+		tag      model.KeyValue
+		expected models.Endpoint
+	}{
+		{tag: model.Int64("ip", 0x7F000001), expected: models.Endpoint{ServiceName: "svc", IPV4: "127.0.0.1"}},
+		{tag: model.String("ip", "::1"), expected: models.Endpoint{ServiceName: "svc", IPV6: "::1"}},
+		{tag: model.Binary("ip", []byte{10, 0, 0, 2}), expected: models.Endpoint{ServiceName: "svc", IPV4: "10.0.0.2"}},
+		{tag: model.String("ip", "not an ip"), expected: models.Endpoint{ServiceName: "svc"}},
+		{tag: model.Binary("ip", []byte{1, 2}), expected: models.Endpoint{ServiceName: "svc"}},
+	}
+	for _, testCase := range testCases {
+		endpoint := localEndpointFromDomain(model.NewProcess("svc", []model.KeyValue{testCase.tag}))
+		assert.Equal(t, testCase.expected, *endpoint, testCase.tag.AsString())
+	}
+}
+
+func TestFromDomain(t *testing.T) {
+	trace := &model.Trace{Spans: []*model.Span{newTestSpan(), newTestSpan()}}
+	zSpans := FromDomain(trace)
+	assert.Len(t, zSpans, 2)
+	assert.NoError(t, zSpans.Validate(strfmt.Default))
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaeger
+
+import (
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+	"github.com/jaegertracing/jaeger/thrift-gen/baggage"
+)
+
+// ConvertBaggageRestrictionsFromDomain converts proto baggage restrictions to their thrift representation.
+func ConvertBaggageRestrictionsFromDomain(r *api_v2.BaggageRestrictionResponse) []*baggage.BaggageRestriction {
+	restrictions := make([]*baggage.BaggageRestriction, len(r.GetBaggageRestrictions()))
+	for i, restriction := range r.GetBaggageRestrictions() {
+		restrictions[i] = &baggage.BaggageRestriction{
+			BaggageKey:     restriction.GetBaggageKey(),
+			MaxValueLength: restriction.GetMaxValueLength(),
+		}
+	}
+	return restrictions
+}
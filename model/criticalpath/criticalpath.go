@@ -0,0 +1,200 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package criticalpath
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// Segment is a time interval during which the given span, and none of its
+// descendants, determined the end-to-end latency of the trace.
+type Segment struct {
+	Span      *model.Span
+	StartTime time.Time
+	Duration  time.Duration
+}
+
+// SpanSelfTime is the total time a span spent on the critical path on its own,
+// i.e. the sum of the durations of all its segments.
+type SpanSelfTime struct {
+	Span     *model.Span
+	SelfTime time.Duration
+}
+
+// CriticalPath is the chain of span segments that determined the end-to-end latency of a trace.
+type CriticalPath struct {
+	// Segments are sorted by start time and do not overlap.
+	Segments []Segment
+	// Spans lists every span on the critical path in the order of its first segment.
+	Spans []SpanSelfTime
+}
+
+type node struct {
+	span     *model.Span
+	children []*node
+}
+
+func (n *node) endTime() time.Time {
+	return n.span.StartTime.Add(n.span.Duration)
+}
+
+// Compute returns the critical path of the trace.
+//
+// The span tree is built from CHILD_OF references, falling back to FOLLOWS_FROM
+// references for spans without a CHILD_OF parent. Spans whose parent is not in the
+// trace are treated as roots, and the path is computed for the longest root span.
+// Starting from the end of a span, the algorithm repeatedly descends into the last
+// finishing child that started before the current point in time, and attributes the
+// gaps between children to the span itself. Children are clipped to the time window
+// of their parent, so FOLLOWS_FROM spans that outlive their parent do not extend the path.
+//
+// The algorithm assumes that all spans have unique IDs, so the trace may need
+// to go through adjuster.SpanIDDeduper first, and that timestamps are free of
+// clock skew, see adjuster.ClockSkew.
+func Compute(trace *model.Trace) *CriticalPath {
+	path := &CriticalPath{}
+	root := buildTree(trace)
+	if root == nil {
+		return path
+	}
+	walk(root, root.span.StartTime, root.endTime(), path)
+	// segments were collected from the end of the trace backwards
+	for i, j := 0, len(path.Segments)-1; i < j; i, j = i+1, j-1 {
+		path.Segments[i], path.Segments[j] = path.Segments[j], path.Segments[i]
+	}
+	path.Spans = selfTimes(path.Segments)
+	return path
+}
+
+// buildTree links spans to their parents and returns the longest root span.
+func buildTree(trace *model.Trace) *node {
+	nodes := make(map[model.SpanID]*node, len(trace.Spans))
+	for _, span := range trace.Spans {
+		if _, ok := nodes[span.SpanID]; !ok {
+			nodes[span.SpanID] = &node{span: span}
+		}
+	}
+	var root *node
+	for _, span := range trace.Spans {
+		n := nodes[span.SpanID]
+		if n.span != span {
+			// duplicate span ID
+			continue
+		}
+		if parentID, ok := parentSpanID(span); ok && parentID != span.SpanID {
+			if parent, ok := nodes[parentID]; ok {
+				parent.children = append(parent.children, n)
+				continue
+			}
+		}
+		if root == nil || isLongerRoot(n, root) {
+			root = n
+		}
+	}
+	return root
+}
+
+func isLongerRoot(n, root *node) bool {
+	if n.span.Duration != root.span.Duration {
+		return n.span.Duration > root.span.Duration
+	}
+	if !n.span.StartTime.Equal(root.span.StartTime) {
+		return n.span.StartTime.Before(root.span.StartTime)
+	}
+	return n.span.SpanID < root.span.SpanID
+}
+
+// parentSpanID returns the span referenced by the first CHILD_OF reference,
+// or by the first FOLLOWS_FROM reference if there is no CHILD_OF one.
+func parentSpanID(span *model.Span) (model.SpanID, bool) {
+	var followsFrom *model.SpanRef
+	for i := range span.References {
+		ref := &span.References[i]
+		if ref.TraceID != span.TraceID {
+			continue
+		}
+		if ref.RefType == model.ChildOf {
+			return ref.SpanID, true
+		}
+		if followsFrom == nil && ref.RefType == model.FollowsFrom {
+			followsFrom = ref
+		}
+	}
+	if followsFrom != nil {
+		return followsFrom.SpanID, true
+	}
+	return 0, false
+}
+
+// walk appends the segments of the critical path of the subtree rooted at n,
+// clipped to [startBound, endBound], in reverse chronological order.
+func walk(n *node, startBound, endBound time.Time, path *CriticalPath) {
+	start := n.span.StartTime
+	if start.Before(startBound) {
+		start = startBound
+	}
+	cursor := n.endTime()
+	if endBound.Before(cursor) {
+		cursor = endBound
+	}
+
+	children := make([]*node, len(n.children))
+	copy(children, n.children)
+	sort.Slice(children, func(i, j int) bool {
+		ei, ej := children[i].endTime(), children[j].endTime()
+		if !ei.Equal(ej) {
+			return ei.After(ej)
+		}
+		return children[i].span.SpanID < children[j].span.SpanID
+	})
+
+	for _, child := range children {
+		if !child.span.StartTime.Before(cursor) || !child.endTime().After(start) {
+			// the child is entirely outside of the remaining window
+			continue
+		}
+		if childEnd := child.endTime(); childEnd.Before(cursor) {
+			path.Segments = append(path.Segments, Segment{Span: n.span, StartTime: childEnd, Duration: cursor.Sub(childEnd)})
+			cursor = childEnd
+		}
+		walk(child, start, cursor, path)
+		cursor = child.span.StartTime
+		if !cursor.After(start) {
+			cursor = start
+			break
+		}
+	}
+	if cursor.After(start) {
+		path.Segments = append(path.Segments, Segment{Span: n.span, StartTime: start, Duration: cursor.Sub(start)})
+	}
+}
+
+func selfTimes(segments []Segment) []SpanSelfTime {
+	var spans []SpanSelfTime
+	index := make(map[*model.Span]int)
+	for _, segment := range segments {
+		i, ok := index[segment.Span]
+		if !ok {
+			i = len(spans)
+			index[segment.Span] = i
+			spans = append(spans, SpanSelfTime{Span: segment.Span})
+		}
+		spans[i].SelfTime += segment.Duration
+	}
+	return spans
+}
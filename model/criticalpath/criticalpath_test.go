@@ -0,0 +1,210 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package criticalpath
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+var (
+	traceID   = model.NewTraceID(0, 42)
+	startTime = time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func newSpan(spanID uint64, start, end int, refs ...model.SpanRef) *model.Span {
+	return &model.Span{
+		TraceID:    traceID,
+		SpanID:     model.NewSpanID(spanID),
+		StartTime:  startTime.Add(time.Duration(start) * time.Millisecond),
+		Duration:   time.Duration(end-start) * time.Millisecond,
+		References: refs,
+	}
+}
+
+func childOf(spanID uint64) model.SpanRef {
+	return model.NewChildOfRef(traceID, model.NewSpanID(spanID))
+}
+
+func followsFrom(spanID uint64) model.SpanRef {
+	return model.NewFollowsFromRef(traceID, model.NewSpanID(spanID))
+}
+
+type segment struct {
+	spanID     uint64
+	start, end int
+}
+
+func toSegments(path *CriticalPath) []segment {
+	var segments []segment
+	for _, s := range path.Segments {
+		start := int(s.StartTime.Sub(startTime) / time.Millisecond)
+		segments = append(segments, segment{
+			spanID: uint64(s.Span.SpanID),
+			start:  start,
+			end:    start + int(s.Duration/time.Millisecond),
+		})
+	}
+	return segments
+}
+
+func toSelfTimes(path *CriticalPath) map[uint64]int {
+	selfTimes := make(map[uint64]int)
+	for _, s := range path.Spans {
+		selfTimes[uint64(s.Span.SpanID)] = int(s.SelfTime / time.Millisecond)
+	}
+	return selfTimes
+}
+
+func TestCompute(t *testing.T) {
+	testCases := []struct {
+		description string
+		spans       []*model.Span
+		segments    []segment
+		selfTimes   map[uint64]int
+	}{
+		{
+			description: "single span",
+			spans:       []*model.Span{newSpan(1, 0, 100)},
+			segments:    []segment{{1, 0, 100}},
+			selfTimes:   map[uint64]int{1: 100},
+		},
+		{
+			description: "sequential and nested children",
+			spans: []*model.Span{
+				newSpan(1, 0, 100),
+				newSpan(2, 10, 40, childOf(1)),
+				newSpan(3, 30, 90, childOf(1)),
+				newSpan(4, 50, 70, childOf(3)),
+				newSpan(5, 80, 120, followsFrom(1)),
+			},
+			segments: []segment{
+				{1, 0, 10},
+				{2, 10, 30},
+				{3, 30, 50},
+				{4, 50, 70},
+				{3, 70, 80},
+				{5, 80, 100},
+			},
+			selfTimes: map[uint64]int{1: 10, 2: 20, 3: 30, 4: 20, 5: 20},
+		},
+		{
+			description: "parallel children, only the last finishing one counts",
+			spans: []*model.Span{
+				newSpan(1, 0, 100),
+				newSpan(2, 10, 60, childOf(1)),
+				newSpan(3, 20, 50, childOf(1)),
+				newSpan(4, 70, 80, childOf(1)),
+			},
+			segments: []segment{
+				{1, 0, 10},
+				{2, 10, 60},
+				{1, 60, 70},
+				{4, 70, 80},
+				{1, 80, 100},
+			},
+			selfTimes: map[uint64]int{1: 40, 2: 50, 4: 10},
+		},
+		{
+			description: "CHILD_OF takes precedence over FOLLOWS_FROM",
+			spans: []*model.Span{
+				newSpan(1, 0, 100),
+				newSpan(2, 10, 50, childOf(1)),
+				newSpan(3, 20, 40, followsFrom(2), childOf(1)),
+			},
+			segments: []segment{
+				{1, 0, 10},
+				{2, 10, 50},
+				{1, 50, 100},
+			},
+			selfTimes: map[uint64]int{1: 60, 2: 40},
+		},
+		{
+			description: "child starting before its parent is clipped",
+			spans: []*model.Span{
+				newSpan(1, 10, 100),
+				newSpan(2, 0, 50, childOf(1)),
+			},
+			segments: []segment{
+				{2, 10, 50},
+				{1, 50, 100},
+			},
+			selfTimes: map[uint64]int{1: 50, 2: 40},
+		},
+		{
+			description: "longest root is chosen, orphans are roots",
+			spans: []*model.Span{
+				newSpan(1, 0, 10),
+				newSpan(2, 0, 30, childOf(99)),
+				newSpan(3, 5, 20, childOf(2)),
+			},
+			segments: []segment{
+				{2, 0, 5},
+				{3, 5, 20},
+				{2, 20, 30},
+			},
+			selfTimes: map[uint64]int{2: 15, 3: 15},
+		},
+		{
+			description: "self-referencing span is a root",
+			spans: []*model.Span{
+				newSpan(1, 0, 10, childOf(1)),
+			},
+			segments:  []segment{{1, 0, 10}},
+			selfTimes: map[uint64]int{1: 10},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase // capture loop var
+		t.Run(testCase.description, func(t *testing.T) {
+			path := Compute(&model.Trace{Spans: testCase.spans})
+			assert.Equal(t, testCase.segments, toSegments(path))
+			assert.Equal(t, testCase.selfTimes, toSelfTimes(path))
+		})
+	}
+}
+
+func TestComputeSpansOrder(t *testing.T) {
+	path := Compute(&model.Trace{
+		Spans: []*model.Span{
+			newSpan(1, 0, 100),
+			newSpan(2, 10, 60, childOf(1)),
+		},
+	})
+	require.Len(t, path.Spans, 2)
+	assert.Equal(t, model.NewSpanID(1), path.Spans[0].Span.SpanID)
+	assert.Equal(t, model.NewSpanID(2), path.Spans[1].Span.SpanID)
+}
+
+func TestComputeEmptyTrace(t *testing.T) {
+	path := Compute(&model.Trace{})
+	assert.Empty(t, path.Segments)
+	assert.Empty(t, path.Spans)
+}
+
+func TestComputeDuplicateSpanIDs(t *testing.T) {
+	path := Compute(&model.Trace{
+		Spans: []*model.Span{
+			newSpan(1, 0, 100),
+			newSpan(1, 0, 200),
+		},
+	})
+	assert.Equal(t, []segment{{1, 0, 100}}, toSegments(path))
+}
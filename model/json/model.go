@@ -108,3 +108,53 @@ type DependencyLink struct {
 	Child     string `json:"child"`
 	CallCount uint64 `json:"callCount"`
 }
+
+// TraceDiff is the result of comparing two traces aligned by service+operation path
+type TraceDiff struct {
+	TraceIDA TraceID         `json:"traceIDA"`
+	TraceIDB TraceID         `json:"traceIDB"`
+	Nodes    []TraceDiffNode `json:"nodes"`
+}
+
+// TraceDiffNode is a node of the aligned span trees of two traces
+type TraceDiffNode struct {
+	Path          string    `json:"path"`
+	ServiceName   string    `json:"serviceName"`
+	OperationName string    `json:"operationName"`
+	Status        string    `json:"status"` // one of "common", "added", "removed"
+	SpanIDA       SpanID    `json:"spanIDA,omitempty"`
+	SpanIDB       SpanID    `json:"spanIDB,omitempty"`
+	DurationA     uint64    `json:"durationA"`     // microseconds
+	DurationB     uint64    `json:"durationB"`     // microseconds
+	DurationDelta int64     `json:"durationDelta"` // microseconds
+	TagDiffs      []TagDiff `json:"tagDiffs"`
+}
+
+// TagDiff is a span tag with different values in the two compared traces
+type TagDiff struct {
+	Key    string    `json:"key"`
+	ValueA *KeyValue `json:"valueA,omitempty"`
+	ValueB *KeyValue `json:"valueB,omitempty"`
+}
+
+// CriticalPath is the chain of span segments that determined the end-to-end latency of a trace
+type CriticalPath struct {
+	TraceID  TraceID               `json:"traceID"`
+	Segments []CriticalPathSegment `json:"segments"`
+	Spans    []CriticalPathSpan    `json:"spans"`
+}
+
+// CriticalPathSegment is a time interval of the critical path attributed to a single span
+type CriticalPathSegment struct {
+	SpanID    SpanID `json:"spanID"`
+	StartTime uint64 `json:"startTime"` // microseconds since Unix epoch
+	Duration  uint64 `json:"duration"`  // microseconds
+}
+
+// CriticalPathSpan is a span on the critical path with its self-time
+type CriticalPathSpan struct {
+	SpanID        SpanID `json:"spanID"`
+	ServiceName   string `json:"serviceName"`
+	OperationName string `json:"operationName"`
+	SelfTime      uint64 `json:"selfTime"` // microseconds
+}
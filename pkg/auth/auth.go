@@ -0,0 +1,102 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUnauthenticated is returned when a request carries no credentials or credentials
+// that none of the configured authenticators accept.
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// Authenticator verifies the credentials carried in the Authorization header of a request.
+type Authenticator interface {
+	// Authenticate verifies the value of the Authorization header and returns the name
+	// of the authenticated principal, or ErrUnauthenticated.
+	Authenticate(authorization string) (string, error)
+
+	// Challenges returns the values of the WWW-Authenticate header sent back
+	// with requests that fail authentication.
+	Challenges() []string
+}
+
+// NewChainedAuthenticator creates an Authenticator that accepts the credentials
+// accepted by any of the given authenticators.
+func NewChainedAuthenticator(authenticators ...Authenticator) Authenticator {
+	return chainedAuthenticator(authenticators)
+}
+
+type chainedAuthenticator []Authenticator
+
+func (c chainedAuthenticator) Authenticate(authorization string) (string, error) {
+	for _, a := range c {
+		if principal, err := a.Authenticate(authorization); err == nil {
+			return principal, nil
+		}
+	}
+	return "", ErrUnauthenticated
+}
+
+func (c chainedAuthenticator) Challenges() []string {
+	var challenges []string
+	for _, a := range c {
+		challenges = append(challenges, a.Challenges()...)
+	}
+	return challenges
+}
+
+// NewAuthenticatorFromFiles creates an Authenticator that accepts the bearer tokens listed
+// in bearerTokensFile and the users listed in htpasswdFile. Empty paths are ignored;
+// if both are empty, no authentication is configured and nil is returned.
+func NewAuthenticatorFromFiles(bearerTokensFile, htpasswdFile string) (Authenticator, error) {
+	var authenticators []Authenticator
+	if bearerTokensFile != "" {
+		tokens, err := LoadBearerTokens(bearerTokensFile)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, NewBearerTokenAuthenticator(tokens))
+	}
+	if htpasswdFile != "" {
+		users, err := LoadHtpasswd(htpasswdFile)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, NewBasicAuthenticator(users))
+	}
+	switch len(authenticators) {
+	case 0:
+		return nil, nil
+	case 1:
+		return authenticators[0], nil
+	default:
+		return NewChainedAuthenticator(authenticators...), nil
+	}
+}
+
+type principalKey struct{}
+
+// ContextWithPrincipal returns a new context carrying the name of the authenticated principal.
+func ContextWithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the name of the authenticated principal stored in the context, if any.
+func PrincipalFromContext(ctx context.Context) (string, bool) {
+	principal, ok := ctx.Value(principalKey{}).(string)
+	return principal, ok
+}
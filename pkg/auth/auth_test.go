@@ -0,0 +1,91 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTempFile(t *testing.T, content string) string {
+	f, err := ioutil.TempFile("", "auth")
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.WriteString(content)
+	require.NoError(t, err)
+	return f.Name()
+}
+
+func TestChainedAuthenticator(t *testing.T) {
+	a := NewChainedAuthenticator(
+		NewBearerTokenAuthenticator(map[string]string{"secret": "robot"}),
+		NewBasicAuthenticator(map[string]string{"jane": shaHash("password")}),
+	)
+	principal, err := a.Authenticate("Bearer secret")
+	require.NoError(t, err)
+	assert.Equal(t, "robot", principal)
+
+	principal, err = a.Authenticate(basicAuthorization("jane", "password"))
+	require.NoError(t, err)
+	assert.Equal(t, "jane", principal)
+
+	_, err = a.Authenticate("Bearer password")
+	assert.Equal(t, ErrUnauthenticated, err)
+
+	assert.Equal(t, []string{`Bearer realm="jaeger"`, `Basic realm="jaeger"`}, a.Challenges())
+}
+
+func TestNewAuthenticatorFromFiles(t *testing.T) {
+	tokensFile := writeTempFile(t, "robot:secret\n")
+	defer os.Remove(tokensFile)
+	htpasswdFile := writeTempFile(t, "jane:"+shaHash("password")+"\n")
+	defer os.Remove(htpasswdFile)
+
+	a, err := NewAuthenticatorFromFiles("", "")
+	require.NoError(t, err)
+	assert.Nil(t, a)
+
+	a, err = NewAuthenticatorFromFiles(tokensFile, "")
+	require.NoError(t, err)
+	assert.IsType(t, &bearerTokenAuthenticator{}, a)
+
+	a, err = NewAuthenticatorFromFiles("", htpasswdFile)
+	require.NoError(t, err)
+	assert.IsType(t, &basicAuthenticator{}, a)
+
+	a, err = NewAuthenticatorFromFiles(tokensFile, htpasswdFile)
+	require.NoError(t, err)
+	assert.IsType(t, chainedAuthenticator{}, a)
+
+	_, err = NewAuthenticatorFromFiles("invalid-file-name", "")
+	assert.Error(t, err)
+
+	_, err = NewAuthenticatorFromFiles("", "invalid-file-name")
+	assert.Error(t, err)
+}
+
+func TestPrincipalFromContext(t *testing.T) {
+	_, ok := PrincipalFromContext(context.Background())
+	assert.False(t, ok)
+
+	principal, ok := PrincipalFromContext(ContextWithPrincipal(context.Background(), "jane"))
+	assert.True(t, ok)
+	assert.Equal(t, "jane", principal)
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1" // #nosec, required by the {SHA} htpasswd format
+	"crypto/subtle"
+	"encoding/base64"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	basicScheme = "Basic"
+	shaPrefix   = "{SHA}"
+)
+
+var bcryptPrefixes = []string{"$2a$", "$2b$", "$2y$"}
+
+type basicAuthenticator struct {
+	// users maps user names to their htpasswd password hashes
+	users map[string]string
+}
+
+// NewBasicAuthenticator creates an Authenticator that accepts "Basic" credentials of the given users,
+// which are mapped to their password hashes in one of the htpasswd formats supported by LoadHtpasswd.
+func NewBasicAuthenticator(users map[string]string) Authenticator {
+	return &basicAuthenticator{users: users}
+}
+
+func (a *basicAuthenticator) Authenticate(authorization string) (string, error) {
+	encoded, ok := parseAuthorization(authorization, basicScheme)
+	if !ok {
+		return "", ErrUnauthenticated
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrUnauthenticated
+	}
+	credentials := string(decoded)
+	i := strings.Index(credentials, ":")
+	if i < 0 {
+		return "", ErrUnauthenticated
+	}
+	user, password := credentials[:i], credentials[i+1:]
+	hash, ok := a.users[user]
+	if !ok || !checkPassword(hash, password) {
+		return "", ErrUnauthenticated
+	}
+	return user, nil
+}
+
+func (a *basicAuthenticator) Challenges() []string {
+	return []string{basicScheme + ` realm="jaeger"`}
+}
+
+func checkPassword(hash, password string) bool {
+	if strings.HasPrefix(hash, shaPrefix) {
+		sum := sha1.Sum([]byte(password))
+		expected := shaPrefix + base64.StdEncoding.EncodeToString(sum[:])
+		return subtle.ConstantTimeCompare([]byte(hash), []byte(expected)) == 1
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// LoadHtpasswd reads users from an htpasswd file with one "<user>:<hash>" entry per line.
+// Passwords must be hashed with bcrypt (htpasswd -B) or SHA-1 (htpasswd -s).
+// Empty lines and lines starting with # are ignored.
+func LoadHtpasswd(path string) (map[string]string, error) {
+	content, err := ioutil.ReadFile(path) /* nolint #nosec , this comes from an admin, not user */
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read htpasswd file")
+	}
+	users := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.Errorf("malformed entry in %s at line %d", path, lineNum)
+		}
+		if !isSupportedHash(parts[1]) {
+			return nil, errors.Errorf("unsupported password hash for user %q in %s, only bcrypt and {SHA} are supported", parts[0], path)
+		}
+		users[parts[0]] = parts[1]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read htpasswd file")
+	}
+	return users, nil
+}
+
+func isSupportedHash(hash string) bool {
+	if strings.HasPrefix(hash, shaPrefix) {
+		return true
+	}
+	for _, prefix := range bcryptPrefixes {
+		if strings.HasPrefix(hash, prefix) {
+			return true
+		}
+	}
+	return false
+}
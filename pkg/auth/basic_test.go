@@ -0,0 +1,108 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func shaHash(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return "{SHA}" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func bcryptHash(t *testing.T, password string) string {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+	return string(hash)
+}
+
+func basicAuthorization(user, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+password))
+}
+
+func TestBasicAuthenticator(t *testing.T) {
+	a := NewBasicAuthenticator(map[string]string{
+		"jane": bcryptHash(t, "password"),
+		"john": shaHash("secret"),
+	})
+	testCases := []struct {
+		name          string
+		authorization string
+		principal     string
+		err           error
+	}{
+		{name: "bcrypt", authorization: basicAuthorization("jane", "password"), principal: "jane"},
+		{name: "sha", authorization: basicAuthorization("john", "secret"), principal: "john"},
+		{name: "wrong bcrypt password", authorization: basicAuthorization("jane", "secret"), err: ErrUnauthenticated},
+		{name: "wrong sha password", authorization: basicAuthorization("john", "password"), err: ErrUnauthenticated},
+		{name: "unknown user", authorization: basicAuthorization("joe", "password"), err: ErrUnauthenticated},
+		{name: "missing colon", authorization: "Basic " + base64.StdEncoding.EncodeToString([]byte("jane")), err: ErrUnauthenticated},
+		{name: "invalid base64", authorization: "Basic !!!", err: ErrUnauthenticated},
+		{name: "bearer", authorization: "Bearer password", err: ErrUnauthenticated},
+		{name: "empty", authorization: "", err: ErrUnauthenticated},
+	}
+	for _, testCase := range testCases {
+		test := testCase // capture loop var
+		t.Run(test.name, func(t *testing.T) {
+			principal, err := a.Authenticate(test.authorization)
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.principal, principal)
+		})
+	}
+	assert.Equal(t, []string{`Basic realm="jaeger"`}, a.Challenges())
+}
+
+func TestLoadHtpasswd(t *testing.T) {
+	bcrypted := bcryptHash(t, "password")
+	path := writeTempFile(t, "# users\njane:"+bcrypted+"\n\njohn:"+shaHash("secret")+"\n")
+	defer os.Remove(path)
+
+	users, err := LoadHtpasswd(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"jane": bcrypted,
+		"john": shaHash("secret"),
+	}, users)
+}
+
+func TestLoadHtpasswdErrors(t *testing.T) {
+	_, err := LoadHtpasswd("invalid-file-name")
+	assert.Contains(t, err.Error(), "failed to read htpasswd file")
+
+	testCases := []struct {
+		content string
+		err     string
+	}{
+		{content: "jane\n", err: "malformed entry in %s at line 1"},
+		{content: "# users\n:" + shaHash("secret") + "\n", err: "malformed entry in %s at line 2"},
+		{content: "jane:$apr1$salt$hash\n", err: `unsupported password hash for user "jane" in %s, only bcrypt and {SHA} are supported`},
+		{content: "jane:password\n", err: `unsupported password hash for user "jane" in %s, only bcrypt and {SHA} are supported`},
+	}
+	for _, test := range testCases {
+		path := writeTempFile(t, test.content)
+		_, err := LoadHtpasswd(path)
+		assert.EqualError(t, err, fmt.Sprintf(test.err, path))
+		os.Remove(path)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/subtle"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const bearerScheme = "Bearer"
+
+type bearerTokenAuthenticator struct {
+	// tokens maps bearer tokens to the names of their principals
+	tokens map[string]string
+}
+
+// NewBearerTokenAuthenticator creates an Authenticator that accepts "Bearer <token>" credentials
+// for the given tokens, which are mapped to the names of their principals.
+func NewBearerTokenAuthenticator(tokens map[string]string) Authenticator {
+	return &bearerTokenAuthenticator{tokens: tokens}
+}
+
+func (a *bearerTokenAuthenticator) Authenticate(authorization string) (string, error) {
+	token, ok := parseAuthorization(authorization, bearerScheme)
+	if !ok || token == "" {
+		return "", ErrUnauthenticated
+	}
+	// compare against every token so that the time taken does not reveal which one matched
+	principal, found := "", false
+	for t, p := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			principal, found = p, true
+		}
+	}
+	if !found {
+		return "", ErrUnauthenticated
+	}
+	return principal, nil
+}
+
+func (a *bearerTokenAuthenticator) Challenges() []string {
+	return []string{bearerScheme + ` realm="jaeger"`}
+}
+
+// LoadBearerTokens reads bearer tokens from a file with one token per line, optionally
+// prefixed with the name of its principal as "<principal>:<token>". Empty lines and
+// lines starting with # are ignored.
+func LoadBearerTokens(path string) (map[string]string, error) {
+	content, err := ioutil.ReadFile(path) /* nolint #nosec , this comes from an admin, not user */
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read bearer tokens file")
+	}
+	tokens := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var principal, token string
+		if i := strings.Index(line, ":"); i >= 0 {
+			principal, token = line[:i], line[i+1:]
+		} else {
+			token = line
+		}
+		if token == "" {
+			return nil, errors.Errorf("empty bearer token in %s at line %d", path, lineNum)
+		}
+		tokens[token] = principal
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read bearer tokens file")
+	}
+	return tokens, nil
+}
+
+// parseAuthorization returns the credentials of an Authorization header value
+// if it uses the given scheme. Schemes are case-insensitive.
+func parseAuthorization(authorization, scheme string) (string, bool) {
+	if len(authorization) <= len(scheme) || !strings.EqualFold(authorization[:len(scheme)], scheme) || authorization[len(scheme)] != ' ' {
+		return "", false
+	}
+	return strings.TrimSpace(authorization[len(scheme)+1:]), true
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBearerTokenAuthenticator(t *testing.T) {
+	a := NewBearerTokenAuthenticator(map[string]string{"secret": "robot", "anonymous": ""})
+	testCases := []struct {
+		authorization string
+		principal     string
+		err           error
+	}{
+		{authorization: "Bearer secret", principal: "robot"},
+		{authorization: "bearer secret", principal: "robot"},
+		{authorization: "Bearer anonymous", principal: ""},
+		{authorization: "Bearer wrong", err: ErrUnauthenticated},
+		{authorization: "Bearer ", err: ErrUnauthenticated},
+		{authorization: "Bearersecret", err: ErrUnauthenticated},
+		{authorization: "Basic secret", err: ErrUnauthenticated},
+		{authorization: "", err: ErrUnauthenticated},
+	}
+	for _, testCase := range testCases {
+		test := testCase // capture loop var
+		t.Run(test.authorization, func(t *testing.T) {
+			principal, err := a.Authenticate(test.authorization)
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.principal, principal)
+		})
+	}
+	assert.Equal(t, []string{`Bearer realm="jaeger"`}, a.Challenges())
+}
+
+func TestLoadBearerTokens(t *testing.T) {
+	path := writeTempFile(t, "# tokens\nrobot:secret\n\n  plain-token  \nci:with:colon\n")
+	defer os.Remove(path)
+
+	tokens, err := LoadBearerTokens(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"secret":      "robot",
+		"plain-token": "",
+		"with:colon":  "ci",
+	}, tokens)
+}
+
+func TestLoadBearerTokensErrors(t *testing.T) {
+	_, err := LoadBearerTokens("invalid-file-name")
+	assert.Contains(t, err.Error(), "failed to read bearer tokens file")
+
+	path := writeTempFile(t, "robot:secret\nci:\n")
+	defer os.Remove(path)
+	_, err = LoadBearerTokens(path)
+	assert.EqualError(t, err, "empty bearer token in "+path+" at line 2")
+}
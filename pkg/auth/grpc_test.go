@@ -0,0 +1,75 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var testAuthenticator = NewBearerTokenAuthenticator(map[string]string{"secret": "robot"})
+
+func incomingContext(authorization string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", authorization))
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor(testAuthenticator)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		principal, _ := PrincipalFromContext(ctx)
+		return principal, nil
+	}
+
+	resp, err := interceptor(incomingContext("Bearer secret"), nil, &grpc.UnaryServerInfo{}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "robot", resp)
+
+	for _, ctx := range []context.Context{incomingContext("Bearer wrong"), context.Background()} {
+		_, err = interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+}
+
+type mockServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *mockServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	interceptor := StreamServerInterceptor(testAuthenticator)
+	var principal string
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		principal, _ = PrincipalFromContext(stream.Context())
+		return nil
+	}
+
+	err := interceptor(nil, &mockServerStream{ctx: incomingContext("Bearer secret")}, &grpc.StreamServerInfo{}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "robot", principal)
+
+	err = interceptor(nil, &mockServerStream{ctx: incomingContext("Bearer wrong")}, &grpc.StreamServerInfo{}, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
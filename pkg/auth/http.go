@@ -0,0 +1,36 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"net/http"
+)
+
+// NewHTTPHandler returns an http.Handler that passes requests accepted by the authenticator
+// on to the handler, with the principal stored in the request context. Other requests are
+// passed to onFailure after the WWW-Authenticate challenges are set on the response.
+func NewHTTPHandler(authenticator Authenticator, handler http.Handler, onFailure func(w http.ResponseWriter, err error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, err := authenticator.Authenticate(r.Header.Get("Authorization"))
+		if err != nil {
+			for _, challenge := range authenticator.Challenges() {
+				w.Header().Add("WWW-Authenticate", challenge)
+			}
+			onFailure(w, err)
+			return
+		}
+		handler.ServeHTTP(w, r.WithContext(ContextWithPrincipal(r.Context(), principal)))
+	})
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// LoadFile reads a configuration file in YAML or JSON format into out, see Unmarshal.
+// The name describes the content of the file in the errors, e.g. "redaction rules".
+func LoadFile(file string, name string, out interface{}) error {
+	content, err := ioutil.ReadFile(file) /* nolint #nosec , this comes from an admin, not user */
+	if err != nil {
+		return errors.Wrapf(err, "Failed to open %s file", name)
+	}
+	return Unmarshal(content, name, out)
+}
+
+// Unmarshal decodes a configuration in YAML or JSON format into out. Unknown fields are rejected,
+// so that a misspelled option is reported instead of being silently ignored.
+func Unmarshal(content []byte, name string, out interface{}) error {
+	// JSON is a subset of YAML, so the same decoder handles both formats
+	if err := yaml.UnmarshalStrict(content, out); err != nil {
+		return errors.Wrapf(err, "Failed to unmarshal %s", name)
+	}
+	return nil
+}
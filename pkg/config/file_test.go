@@ -0,0 +1,59 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	Name  string   `yaml:"name"`
+	Items []string `yaml:"items"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	expected := testConfig{Name: "a", Items: []string{"b", "c"}}
+	for _, content := range []string{"name: a\nitems: [b, c]", `{"name": "a", "items": ["b", "c"]}`} {
+		var cfg testConfig
+		require.NoError(t, Unmarshal([]byte(content), "test config", &cfg), content)
+		assert.Equal(t, expected, cfg)
+	}
+
+	var cfg testConfig
+	err := Unmarshal([]byte(`{"nmae": "a"}`), "test config", &cfg)
+	assert.EqualError(t, err, "Failed to unmarshal test config: yaml: unmarshal errors:\n  line 1: field nmae not found in type config.testConfig")
+}
+
+func TestLoadFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "config.yaml")
+	require.NoError(t, ioutil.WriteFile(file, []byte("name: a"), 0644))
+
+	var cfg testConfig
+	require.NoError(t, LoadFile(file, "test config", &cfg))
+	assert.Equal(t, testConfig{Name: "a"}, cfg)
+
+	err = LoadFile(filepath.Join(dir, "missing.yaml"), "test config", &cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Failed to open test config file")
+}
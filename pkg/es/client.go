@@ -28,6 +28,12 @@ type Client interface {
 	Index() IndexService
 	Search(indices ...string) SearchService
 	MultiSearch() MultiSearchService
+	IndexGet(indices ...string) IndicesGetService
+	DeleteIndex(indices ...string) IndicesDeleteService
+	IndexPutTemplate(name string) IndicesPutTemplateService
+	RolloverIndex(alias string) IndicesRolloverService
+	Alias() AliasService
+	DeleteByQuery(indices ...string) DeleteByQueryService
 	io.Closer
 }
 
@@ -42,6 +48,45 @@ type IndicesCreateService interface {
 	Do(ctx context.Context) (*elastic.IndicesCreateResult, error)
 }
 
+// IndicesGetService is an abstraction for elastic.IndicesGetService
+type IndicesGetService interface {
+	Do(ctx context.Context) (map[string]*elastic.IndicesGetResponse, error)
+}
+
+// IndicesDeleteService is an abstraction for elastic.IndicesDeleteService
+type IndicesDeleteService interface {
+	Do(ctx context.Context) (*elastic.IndicesDeleteResponse, error)
+}
+
+// IndicesPutTemplateService is an abstraction for elastic.IndicesPutTemplateService
+type IndicesPutTemplateService interface {
+	BodyString(body string) IndicesPutTemplateService
+	Do(ctx context.Context) (*elastic.IndicesPutTemplateResponse, error)
+}
+
+// IndicesRolloverService is an abstraction for elastic.IndicesRolloverService
+type IndicesRolloverService interface {
+	AddMaxIndexAgeCondition(age string) IndicesRolloverService
+	AddMaxIndexDocsCondition(docs int64) IndicesRolloverService
+	Do(ctx context.Context) (*elastic.IndicesRolloverResponse, error)
+}
+
+// AliasService is an abstraction for elastic.AliasService
+type AliasService interface {
+	Add(indexName string, aliasName string) AliasService
+	Remove(indexName string, aliasName string) AliasService
+	Do(ctx context.Context) (*elastic.AliasResult, error)
+}
+
+// DeleteByQueryService is an abstraction for elastic.DeleteByQueryService
+type DeleteByQueryService interface {
+	Query(query elastic.Query) DeleteByQueryService
+	IgnoreUnavailable(ignoreUnavailable bool) DeleteByQueryService
+	ProceedOnVersionConflict() DeleteByQueryService
+	Refresh(refresh string) DeleteByQueryService
+	Do(ctx context.Context) (*elastic.BulkIndexByScrollResponse, error)
+}
+
 // IndexService is an abstraction for elastic BulkService
 type IndexService interface {
 	Index(index string) IndexService
@@ -58,6 +103,7 @@ type SearchService interface {
 	Aggregation(name string, aggregation elastic.Aggregation) SearchService
 	IgnoreUnavailable(ignoreUnavailable bool) SearchService
 	Query(query elastic.Query) SearchService
+	Sort(field string, ascending bool) SearchService
 	Do(ctx context.Context) (*elastic.SearchResult, error)
 }
 
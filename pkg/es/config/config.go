@@ -53,6 +53,7 @@ type Configuration struct {
 	BulkFlushInterval   time.Duration
 	IndexPrefix         string
 	TagsFilePath        string
+	RetentionPolicyFile string
 	AllTagsAsFields     bool
 	TagDotReplacement   string
 	Enabled             bool
@@ -78,6 +79,7 @@ type ClientBuilder interface {
 	GetMaxNumSpans() int
 	GetIndexPrefix() string
 	GetTagsFilePath() string
+	GetRetentionPolicyFile() string
 	GetAllTagsAsFields() bool
 	GetTagDotReplacement() string
 	GetUseReadWriteAliases() bool
@@ -220,6 +222,11 @@ func (c *Configuration) GetTagsFilePath() string {
 	return c.TagsFilePath
 }
 
+// GetRetentionPolicyFile returns a path to file containing the retention policy of the services
+func (c *Configuration) GetRetentionPolicyFile() string {
+	return c.RetentionPolicyFile
+}
+
 // GetAllTagsAsFields returns true if all tags should be stored as object fields
 func (c *Configuration) GetAllTagsAsFields() bool {
 	return c.AllTagsAsFields
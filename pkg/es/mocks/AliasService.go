@@ -0,0 +1,82 @@
+// Code generated by mockery v1.0.0
+
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mocks
+
+import context "context"
+import elastic "gopkg.in/olivere/elastic.v5"
+import es "github.com/jaegertracing/jaeger/pkg/es"
+import mock "github.com/stretchr/testify/mock"
+
+// AliasService is an autogenerated mock type for the AliasService type
+type AliasService struct {
+	mock.Mock
+}
+
+// Add provides a mock function with given fields: indexName, aliasName
+func (_m *AliasService) Add(indexName string, aliasName string) es.AliasService {
+	ret := _m.Called(indexName, aliasName)
+
+	var r0 es.AliasService
+	if rf, ok := ret.Get(0).(func(string, string) es.AliasService); ok {
+		r0 = rf(indexName, aliasName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(es.AliasService)
+		}
+	}
+
+	return r0
+}
+
+// Do provides a mock function with given fields: ctx
+func (_m *AliasService) Do(ctx context.Context) (*elastic.AliasResult, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *elastic.AliasResult
+	if rf, ok := ret.Get(0).(func(context.Context) *elastic.AliasResult); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*elastic.AliasResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Remove provides a mock function with given fields: indexName, aliasName
+func (_m *AliasService) Remove(indexName string, aliasName string) es.AliasService {
+	ret := _m.Called(indexName, aliasName)
+
+	var r0 es.AliasService
+	if rf, ok := ret.Get(0).(func(string, string) es.AliasService); ok {
+		r0 = rf(indexName, aliasName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(es.AliasService)
+		}
+	}
+
+	return r0
+}
@@ -24,6 +24,22 @@ type Client struct {
 	mock.Mock
 }
 
+// Alias provides a mock function with given fields:
+func (_m *Client) Alias() es.AliasService {
+	ret := _m.Called()
+
+	var r0 es.AliasService
+	if rf, ok := ret.Get(0).(func() es.AliasService); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(es.AliasService)
+		}
+	}
+
+	return r0
+}
+
 // Close provides a mock function with given fields:
 func (_m *Client) Close() error {
 	ret := _m.Called()
@@ -54,6 +70,50 @@ func (_m *Client) CreateIndex(index string) es.IndicesCreateService {
 	return r0
 }
 
+// DeleteByQuery provides a mock function with given fields: indices
+func (_m *Client) DeleteByQuery(indices ...string) es.DeleteByQueryService {
+	_va := make([]interface{}, len(indices))
+	for _i := range indices {
+		_va[_i] = indices[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 es.DeleteByQueryService
+	if rf, ok := ret.Get(0).(func(...string) es.DeleteByQueryService); ok {
+		r0 = rf(indices...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(es.DeleteByQueryService)
+		}
+	}
+
+	return r0
+}
+
+// DeleteIndex provides a mock function with given fields: indices
+func (_m *Client) DeleteIndex(indices ...string) es.IndicesDeleteService {
+	_va := make([]interface{}, len(indices))
+	for _i := range indices {
+		_va[_i] = indices[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 es.IndicesDeleteService
+	if rf, ok := ret.Get(0).(func(...string) es.IndicesDeleteService); ok {
+		r0 = rf(indices...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(es.IndicesDeleteService)
+		}
+	}
+
+	return r0
+}
+
 // Index provides a mock function with given fields:
 func (_m *Client) Index() es.IndexService {
 	ret := _m.Called()
@@ -86,6 +146,44 @@ func (_m *Client) IndexExists(index string) es.IndicesExistsService {
 	return r0
 }
 
+// IndexGet provides a mock function with given fields: indices
+func (_m *Client) IndexGet(indices ...string) es.IndicesGetService {
+	_va := make([]interface{}, len(indices))
+	for _i := range indices {
+		_va[_i] = indices[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 es.IndicesGetService
+	if rf, ok := ret.Get(0).(func(...string) es.IndicesGetService); ok {
+		r0 = rf(indices...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(es.IndicesGetService)
+		}
+	}
+
+	return r0
+}
+
+// IndexPutTemplate provides a mock function with given fields: name
+func (_m *Client) IndexPutTemplate(name string) es.IndicesPutTemplateService {
+	ret := _m.Called(name)
+
+	var r0 es.IndicesPutTemplateService
+	if rf, ok := ret.Get(0).(func(string) es.IndicesPutTemplateService); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(es.IndicesPutTemplateService)
+		}
+	}
+
+	return r0
+}
+
 // MultiSearch provides a mock function with given fields:
 func (_m *Client) MultiSearch() es.MultiSearchService {
 	ret := _m.Called()
@@ -102,6 +200,22 @@ func (_m *Client) MultiSearch() es.MultiSearchService {
 	return r0
 }
 
+// RolloverIndex provides a mock function with given fields: alias
+func (_m *Client) RolloverIndex(alias string) es.IndicesRolloverService {
+	ret := _m.Called(alias)
+
+	var r0 es.IndicesRolloverService
+	if rf, ok := ret.Get(0).(func(string) es.IndicesRolloverService); ok {
+		r0 = rf(alias)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(es.IndicesRolloverService)
+		}
+	}
+
+	return r0
+}
+
 // Search provides a mock function with given fields: indices
 func (_m *Client) Search(indices ...string) es.SearchService {
 	_va := make([]interface{}, len(indices))
@@ -0,0 +1,82 @@
+// Code generated by mockery v1.0.0
+
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mocks
+
+import context "context"
+import elastic "gopkg.in/olivere/elastic.v5"
+import es "github.com/jaegertracing/jaeger/pkg/es"
+import mock "github.com/stretchr/testify/mock"
+
+// IndicesRolloverService is an autogenerated mock type for the IndicesRolloverService type
+type IndicesRolloverService struct {
+	mock.Mock
+}
+
+// AddMaxIndexAgeCondition provides a mock function with given fields: age
+func (_m *IndicesRolloverService) AddMaxIndexAgeCondition(age string) es.IndicesRolloverService {
+	ret := _m.Called(age)
+
+	var r0 es.IndicesRolloverService
+	if rf, ok := ret.Get(0).(func(string) es.IndicesRolloverService); ok {
+		r0 = rf(age)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(es.IndicesRolloverService)
+		}
+	}
+
+	return r0
+}
+
+// AddMaxIndexDocsCondition provides a mock function with given fields: docs
+func (_m *IndicesRolloverService) AddMaxIndexDocsCondition(docs int64) es.IndicesRolloverService {
+	ret := _m.Called(docs)
+
+	var r0 es.IndicesRolloverService
+	if rf, ok := ret.Get(0).(func(int64) es.IndicesRolloverService); ok {
+		r0 = rf(docs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(es.IndicesRolloverService)
+		}
+	}
+
+	return r0
+}
+
+// Do provides a mock function with given fields: ctx
+func (_m *IndicesRolloverService) Do(ctx context.Context) (*elastic.IndicesRolloverResponse, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *elastic.IndicesRolloverResponse
+	if rf, ok := ret.Get(0).(func(context.Context) *elastic.IndicesRolloverResponse); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*elastic.IndicesRolloverResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -61,6 +61,36 @@ func (c ClientWrapper) MultiSearch() es.MultiSearchService {
 	return WrapESMultiSearchService(c.client.MultiSearch())
 }
 
+// IndexGet calls this function to internal client.
+func (c ClientWrapper) IndexGet(indices ...string) es.IndicesGetService {
+	return WrapESIndicesGetService(c.client.IndexGet(indices...))
+}
+
+// DeleteIndex calls this function to internal client.
+func (c ClientWrapper) DeleteIndex(indices ...string) es.IndicesDeleteService {
+	return WrapESIndicesDeleteService(c.client.DeleteIndex(indices...))
+}
+
+// IndexPutTemplate calls this function to internal client.
+func (c ClientWrapper) IndexPutTemplate(name string) es.IndicesPutTemplateService {
+	return WrapESIndicesPutTemplateService(c.client.IndexPutTemplate(name))
+}
+
+// RolloverIndex calls this function to internal client.
+func (c ClientWrapper) RolloverIndex(alias string) es.IndicesRolloverService {
+	return WrapESIndicesRolloverService(c.client.RolloverIndex(alias))
+}
+
+// Alias calls this function to internal client.
+func (c ClientWrapper) Alias() es.AliasService {
+	return WrapESAliasService(c.client.Alias())
+}
+
+// DeleteByQuery calls this function to internal client.
+func (c ClientWrapper) DeleteByQuery(indices ...string) es.DeleteByQueryService {
+	return WrapESDeleteByQueryService(c.client.DeleteByQuery(indices...))
+}
+
 // Close closes ESClient and flushes all data to the storage.
 func (c ClientWrapper) Close() error {
 	return c.bulkService.Close()
@@ -107,6 +137,116 @@ func (c IndicesCreateServiceWrapper) Do(ctx context.Context) (*elastic.IndicesCr
 
 // ---
 
+// IndicesGetServiceWrapper is a wrapper around elastic.IndicesGetService
+type IndicesGetServiceWrapper struct {
+	indicesGetService *elastic.IndicesGetService
+}
+
+// WrapESIndicesGetService creates an ESIndicesGetService out of *elastic.IndicesGetService.
+func WrapESIndicesGetService(indicesGetService *elastic.IndicesGetService) IndicesGetServiceWrapper {
+	return IndicesGetServiceWrapper{indicesGetService: indicesGetService}
+}
+
+// Do calls this function to internal service.
+func (e IndicesGetServiceWrapper) Do(ctx context.Context) (map[string]*elastic.IndicesGetResponse, error) {
+	return e.indicesGetService.Do(ctx)
+}
+
+// ---
+
+// IndicesDeleteServiceWrapper is a wrapper around elastic.IndicesDeleteService
+type IndicesDeleteServiceWrapper struct {
+	indicesDeleteService *elastic.IndicesDeleteService
+}
+
+// WrapESIndicesDeleteService creates an ESIndicesDeleteService out of *elastic.IndicesDeleteService.
+func WrapESIndicesDeleteService(indicesDeleteService *elastic.IndicesDeleteService) IndicesDeleteServiceWrapper {
+	return IndicesDeleteServiceWrapper{indicesDeleteService: indicesDeleteService}
+}
+
+// Do calls this function to internal service.
+func (e IndicesDeleteServiceWrapper) Do(ctx context.Context) (*elastic.IndicesDeleteResponse, error) {
+	return e.indicesDeleteService.Do(ctx)
+}
+
+// ---
+
+// IndicesPutTemplateServiceWrapper is a wrapper around elastic.IndicesPutTemplateService
+type IndicesPutTemplateServiceWrapper struct {
+	indicesPutTemplateService *elastic.IndicesPutTemplateService
+}
+
+// WrapESIndicesPutTemplateService creates an ESIndicesPutTemplateService out of *elastic.IndicesPutTemplateService.
+func WrapESIndicesPutTemplateService(indicesPutTemplateService *elastic.IndicesPutTemplateService) IndicesPutTemplateServiceWrapper {
+	return IndicesPutTemplateServiceWrapper{indicesPutTemplateService: indicesPutTemplateService}
+}
+
+// BodyString calls this function to internal service.
+func (e IndicesPutTemplateServiceWrapper) BodyString(body string) es.IndicesPutTemplateService {
+	return WrapESIndicesPutTemplateService(e.indicesPutTemplateService.BodyString(body))
+}
+
+// Do calls this function to internal service.
+func (e IndicesPutTemplateServiceWrapper) Do(ctx context.Context) (*elastic.IndicesPutTemplateResponse, error) {
+	return e.indicesPutTemplateService.Do(ctx)
+}
+
+// ---
+
+// IndicesRolloverServiceWrapper is a wrapper around elastic.IndicesRolloverService
+type IndicesRolloverServiceWrapper struct {
+	indicesRolloverService *elastic.IndicesRolloverService
+}
+
+// WrapESIndicesRolloverService creates an ESIndicesRolloverService out of *elastic.IndicesRolloverService.
+func WrapESIndicesRolloverService(indicesRolloverService *elastic.IndicesRolloverService) IndicesRolloverServiceWrapper {
+	return IndicesRolloverServiceWrapper{indicesRolloverService: indicesRolloverService}
+}
+
+// AddMaxIndexAgeCondition calls this function to internal service.
+func (e IndicesRolloverServiceWrapper) AddMaxIndexAgeCondition(age string) es.IndicesRolloverService {
+	return WrapESIndicesRolloverService(e.indicesRolloverService.AddMaxIndexAgeCondition(age))
+}
+
+// AddMaxIndexDocsCondition calls this function to internal service.
+func (e IndicesRolloverServiceWrapper) AddMaxIndexDocsCondition(docs int64) es.IndicesRolloverService {
+	return WrapESIndicesRolloverService(e.indicesRolloverService.AddMaxIndexDocsCondition(docs))
+}
+
+// Do calls this function to internal service.
+func (e IndicesRolloverServiceWrapper) Do(ctx context.Context) (*elastic.IndicesRolloverResponse, error) {
+	return e.indicesRolloverService.Do(ctx)
+}
+
+// ---
+
+// AliasServiceWrapper is a wrapper around elastic.AliasService
+type AliasServiceWrapper struct {
+	aliasService *elastic.AliasService
+}
+
+// WrapESAliasService creates an ESAliasService out of *elastic.AliasService.
+func WrapESAliasService(aliasService *elastic.AliasService) AliasServiceWrapper {
+	return AliasServiceWrapper{aliasService: aliasService}
+}
+
+// Add calls this function to internal service.
+func (e AliasServiceWrapper) Add(indexName string, aliasName string) es.AliasService {
+	return WrapESAliasService(e.aliasService.Add(indexName, aliasName))
+}
+
+// Remove calls this function to internal service.
+func (e AliasServiceWrapper) Remove(indexName string, aliasName string) es.AliasService {
+	return WrapESAliasService(e.aliasService.Remove(indexName, aliasName))
+}
+
+// Do calls this function to internal service.
+func (e AliasServiceWrapper) Do(ctx context.Context) (*elastic.AliasResult, error) {
+	return e.aliasService.Do(ctx)
+}
+
+// ---
+
 // IndexServiceWrapper is a wrapper around elastic.ESIndexService.
 // See wrapper_nolint.go for more functions.
 type IndexServiceWrapper struct {
@@ -171,6 +311,11 @@ func (s SearchServiceWrapper) Query(query elastic.Query) es.SearchService {
 	return WrapESSearchService(s.searchService.Query(query))
 }
 
+// Sort calls this function to internal service.
+func (s SearchServiceWrapper) Sort(field string, ascending bool) es.SearchService {
+	return WrapESSearchService(s.searchService.Sort(field, ascending))
+}
+
 // Do calls this function to internal service.
 func (s SearchServiceWrapper) Do(ctx context.Context) (*elastic.SearchResult, error) {
 	return s.searchService.Do(ctx)
@@ -200,3 +345,40 @@ func (s MultiSearchServiceWrapper) Index(indices ...string) es.MultiSearchServic
 func (s MultiSearchServiceWrapper) Do(ctx context.Context) (*elastic.MultiSearchResult, error) {
 	return s.multiSearchService.Do(ctx)
 }
+
+// ---
+
+// DeleteByQueryServiceWrapper is a wrapper around elastic.DeleteByQueryService
+type DeleteByQueryServiceWrapper struct {
+	deleteByQueryService *elastic.DeleteByQueryService
+}
+
+// WrapESDeleteByQueryService creates an ESDeleteByQueryService out of *elastic.DeleteByQueryService.
+func WrapESDeleteByQueryService(deleteByQueryService *elastic.DeleteByQueryService) DeleteByQueryServiceWrapper {
+	return DeleteByQueryServiceWrapper{deleteByQueryService: deleteByQueryService}
+}
+
+// Query calls this function to internal service.
+func (s DeleteByQueryServiceWrapper) Query(query elastic.Query) es.DeleteByQueryService {
+	return WrapESDeleteByQueryService(s.deleteByQueryService.Query(query))
+}
+
+// IgnoreUnavailable calls this function to internal service.
+func (s DeleteByQueryServiceWrapper) IgnoreUnavailable(ignoreUnavailable bool) es.DeleteByQueryService {
+	return WrapESDeleteByQueryService(s.deleteByQueryService.IgnoreUnavailable(ignoreUnavailable))
+}
+
+// ProceedOnVersionConflict calls this function to internal service.
+func (s DeleteByQueryServiceWrapper) ProceedOnVersionConflict() es.DeleteByQueryService {
+	return WrapESDeleteByQueryService(s.deleteByQueryService.ProceedOnVersionConflict())
+}
+
+// Refresh calls this function to internal service.
+func (s DeleteByQueryServiceWrapper) Refresh(refresh string) es.DeleteByQueryService {
+	return WrapESDeleteByQueryService(s.deleteByQueryService.Refresh(refresh))
+}
+
+// Do calls this function to internal service.
+func (s DeleteByQueryServiceWrapper) Do(ctx context.Context) (*elastic.BulkIndexByScrollResponse, error) {
+	return s.deleteByQueryService.Do(ctx)
+}
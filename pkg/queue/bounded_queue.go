@@ -64,6 +64,7 @@ func (q *BoundedQueue) StartConsumers(num int, consumer func(item interface{}))
 					atomic.AddInt32(&q.size, -1)
 					consumer(item)
 				case <-q.stopCh:
+					q.drain(consumer)
 					return
 				}
 			}
@@ -72,6 +73,19 @@ func (q *BoundedQueue) StartConsumers(num int, consumer func(item interface{}))
 	startWG.Wait()
 }
 
+// drain passes the items left in the queue into the consumer callback.
+func (q *BoundedQueue) drain(consumer func(item interface{})) {
+	for {
+		select {
+		case item := <-q.items:
+			atomic.AddInt32(&q.size, -1)
+			consumer(item)
+		default:
+			return
+		}
+	}
+}
+
 // Produce is used by the producer to submit new item to the queue. Returns false in case of queue overflow.
 func (q *BoundedQueue) Produce(item interface{}) bool {
 	if atomic.LoadInt32(&q.stopped) != 0 {
@@ -91,7 +105,8 @@ func (q *BoundedQueue) Produce(item interface{}) bool {
 }
 
 // Stop stops all consumers, as well as the length reporter if started,
-// and releases the items channel. It blocks until all consumers have stopped.
+// and releases the items channel. It blocks until all consumers have consumed
+// the items left in the queue and stopped.
 func (q *BoundedQueue) Stop() {
 	atomic.StoreInt32(&q.stopped, 1) // disable producer
 	close(q.stopCh)
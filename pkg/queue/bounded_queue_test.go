@@ -155,3 +155,44 @@ func (s *consumerState) assertConsumed(expected map[string]bool) {
 	}
 	assert.Equal(s.t, expected, s.snapshot())
 }
+
+func TestBoundedQueueStopDrainsItems(t *testing.T) {
+	q := NewBoundedQueue(3, nil)
+
+	var startLock sync.Mutex
+
+	startLock.Lock() // block consumers
+	consumerState := newConsumerState(t)
+
+	q.StartConsumers(1, func(item interface{}) {
+		consumerState.record(item.(string))
+
+		startLock.Lock()
+		//lint:ignore SA2001 empty section is ok
+		startLock.Unlock()
+	})
+
+	assert.True(t, q.Produce("a"))
+	consumerState.waitToConsumeOnce()
+	assert.True(t, q.Produce("b"))
+	assert.True(t, q.Produce("c"))
+
+	stopped := make(chan struct{})
+	go func() {
+		q.Stop()
+		close(stopped)
+	}()
+	for atomic.LoadInt32(&q.stopped) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	startLock.Unlock() // unblock consumer once the queue is stopping
+	<-stopped
+
+	// the items left in the queue are consumed before Stop returns
+	consumerState.assertConsumed(map[string]bool{
+		"a": true,
+		"b": true,
+		"c": true,
+	})
+	assert.Equal(t, 0, q.Size())
+}
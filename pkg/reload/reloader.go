@@ -0,0 +1,134 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reload
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+)
+
+// Metrics counts the reloads of a Reloader
+type Metrics struct {
+	// Number of times the content was successfully reloaded
+	ReloadSuccess metrics.Counter `metric:"reloads" tags:"result=ok"`
+
+	// Number of times the reloaded content failed validation; the last good content is kept
+	ReloadFailure metrics.Counter `metric:"reloads" tags:"result=err"`
+
+	// Number of times the content could not be read
+	ReadErrors metrics.Counter `metric:"read-errors"`
+}
+
+// Options configures a Reloader
+type Options struct {
+	// Name describes the content in the logs, e.g. "sampling strategies"
+	Name string
+	// Source is the file or URL of the content, for the logs
+	Source string
+	// Read returns the raw content
+	Read func() ([]byte, error)
+	// Apply parses and validates the content and swaps it in, it must leave the current content
+	// in effect if it returns an error
+	Apply func(content []byte) error
+	// Interval is the period of the reloads, the content is only loaded once if it is not positive
+	Interval time.Duration
+	Metrics  Metrics
+	Logger   *zap.Logger
+}
+
+// Reloader loads a content, like a configuration file, and periodically checks it for changes.
+// A changed content is applied once it passes validation; if it cannot be read or is invalid,
+// the previous content stays in effect.
+type Reloader struct {
+	options Options
+
+	mux sync.Mutex
+	// lastContent is the content seen by the last reload attempt
+	lastContent []byte
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New reads and applies the content, failing if it cannot, and starts the periodic reloads
+// if options.Interval is positive.
+func New(options Options) (*Reloader, error) {
+	r := &Reloader{
+		options: options,
+		stop:    make(chan struct{}),
+	}
+	content, err := options.Read()
+	if err != nil {
+		return nil, err
+	}
+	if err := options.Apply(content); err != nil {
+		return nil, err
+	}
+	r.lastContent = content
+
+	if options.Interval > 0 {
+		r.wg.Add(1)
+		go r.runReloadLoop()
+	}
+	return r, nil
+}
+
+// Close stops the periodic reloads.
+func (r *Reloader) Close() error {
+	close(r.stop)
+	r.wg.Wait()
+	return nil
+}
+
+func (r *Reloader) runReloadLoop() {
+	defer r.wg.Done()
+	ticker := time.NewTicker(r.options.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.Reload()
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// Reload re-reads the content and, if it has changed and is valid, applies it.
+func (r *Reloader) Reload() {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	content, err := r.options.Read()
+	if err != nil {
+		r.options.Metrics.ReadErrors.Inc(1)
+		r.options.Logger.Error("Failed to reload "+r.options.Name+", keeping the previous content", zap.Error(err))
+		return
+	}
+	if bytes.Equal(content, r.lastContent) {
+		return
+	}
+	r.lastContent = content
+	if err := r.options.Apply(content); err != nil {
+		r.options.Metrics.ReloadFailure.Inc(1)
+		r.options.Logger.Error("Failed to reload "+r.options.Name+", keeping the previous content", zap.Error(err))
+		return
+	}
+	r.options.Metrics.ReloadSuccess.Inc(1)
+	r.options.Logger.Info("Reloaded "+r.options.Name, zap.String("source", r.options.Source))
+}
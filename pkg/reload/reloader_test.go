@@ -0,0 +1,132 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reload
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+	"go.uber.org/zap"
+)
+
+// source is a content that can be changed concurrently with the reloads
+type source struct {
+	sync.Mutex
+	content string
+	err     error
+	applied []string
+}
+
+func (s *source) set(content string, err error) {
+	s.Lock()
+	defer s.Unlock()
+	s.content, s.err = content, err
+}
+
+func (s *source) read() ([]byte, error) {
+	s.Lock()
+	defer s.Unlock()
+	return []byte(s.content), s.err
+}
+
+func (s *source) apply(content []byte) error {
+	if string(content) == "invalid" {
+		return errors.New("invalid content")
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.applied = append(s.applied, string(content))
+	return nil
+}
+
+func (s *source) appliedContents() []string {
+	s.Lock()
+	defer s.Unlock()
+	return append([]string(nil), s.applied...)
+}
+
+func newTestReloader(t *testing.T, s *source, interval time.Duration) (*Reloader, *metricstest.Factory) {
+	metricsFactory := metricstest.NewFactory(0)
+	options := Options{
+		Name:     "test content",
+		Source:   "test",
+		Read:     s.read,
+		Apply:    s.apply,
+		Interval: interval,
+		Logger:   zap.NewNop(),
+	}
+	metrics.Init(&options.Metrics, metricsFactory.Namespace(metrics.NSOptions{Name: "test"}), nil)
+	r, err := New(options)
+	require.NoError(t, err)
+	return r, metricsFactory
+}
+
+func TestNewErrors(t *testing.T) {
+	s := &source{err: errors.New("no content")}
+	_, err := New(Options{Read: s.read, Apply: s.apply})
+	assert.EqualError(t, err, "no content")
+
+	s.set("invalid", nil)
+	_, err = New(Options{Read: s.read, Apply: s.apply})
+	assert.EqualError(t, err, "invalid content")
+}
+
+func TestReload(t *testing.T) {
+	s := &source{content: "v1"}
+	r, metricsFactory := newTestReloader(t, s, 0)
+	defer r.Close()
+	assert.Equal(t, []string{"v1"}, s.appliedContents())
+
+	// an unchanged content is not applied again
+	r.Reload()
+	assert.Equal(t, []string{"v1"}, s.appliedContents())
+
+	s.set("v2", nil)
+	r.Reload()
+	assert.Equal(t, []string{"v1", "v2"}, s.appliedContents())
+
+	s.set("invalid", nil)
+	r.Reload()
+	s.set("", errors.New("no content"))
+	r.Reload()
+	assert.Equal(t, []string{"v1", "v2"}, s.appliedContents())
+
+	metricsFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "test.reloads", Tags: map[string]string{"result": "ok"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "test.reloads", Tags: map[string]string{"result": "err"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "test.read-errors", Value: 1},
+	)
+}
+
+func TestPeriodicReload(t *testing.T) {
+	s := &source{content: "v1"}
+	r, metricsFactory := newTestReloader(t, s, time.Millisecond)
+	defer r.Close()
+
+	s.set("v2", nil)
+	for i := 0; i < 1000; i++ {
+		if counters, _ := metricsFactory.Snapshot(); counters["test.reloads|result=ok"] > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, []string{"v1", "v2"}, s.appliedContents())
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"flag"
+
+	"github.com/spf13/viper"
+)
+
+const suffixPolicyFile = ".retention-policy-file"
+
+// AddFlags adds the flag for the retention policy file of a storage backend in the namespace, e.g. "cassandra"
+func AddFlags(flagSet *flag.FlagSet, namespace string) {
+	flagSet.String(
+		namespace+suffixPolicyFile,
+		"",
+		"The path for the file with the rules setting the retention of the spans per service, in YAML or JSON format. "+
+			"All the spans have the default retention if empty",
+	)
+}
+
+// PolicyFileFromViper returns the path for the retention policy file of a storage backend in the namespace
+func PolicyFileFromViper(v *viper.Viper, namespace string) string {
+	return v.GetString(namespace + suffixPolicyFile)
+}
+
+// LoadPolicyFile reads the retention policy from the file, or returns nil if file is empty.
+func LoadPolicyFile(file string) (*Policy, error) {
+	if file == "" {
+		return nil, nil
+	}
+	return LoadPolicy(file)
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+func TestOptions(t *testing.T) {
+	v, command := config.Viperize(func(flagSet *flag.FlagSet) {
+		AddFlags(flagSet, "cassandra")
+	})
+	command.ParseFlags([]string{"--cassandra.retention-policy-file=fixtures/retention_policy.yaml"})
+	file := PolicyFileFromViper(v, "cassandra")
+	assert.Equal(t, "fixtures/retention_policy.yaml", file)
+
+	policy, err := LoadPolicyFile(file)
+	require.NoError(t, err)
+	assert.Len(t, policy.Rules(), 2)
+
+	policy, err = LoadPolicyFile("")
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+// ruleNameRegexp restricts the rule names to the characters allowed in Elasticsearch index names,
+// since the names are part of the per-service index names
+var ruleNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Rule sets the retention of the spans of some services.
+type Rule struct {
+	// Name identifies the rule, it must be lowercase since it is part of the per-service Elasticsearch indices
+	Name string `yaml:"name"`
+	// Services matches the services with any of these names
+	Services []string `yaml:"services"`
+	// ServicePatterns matches the services with a name matching any of these regular expressions
+	ServicePatterns []string `yaml:"service_patterns"`
+	// TTL is how long the spans of the matched services are kept
+	TTL time.Duration `yaml:"ttl"`
+}
+
+// policyFile is the content of the retention policy file
+type policyFile struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+type rule struct {
+	Rule
+	services map[string]struct{}
+	patterns []*regexp.Regexp
+}
+
+func (r *rule) matches(service string) bool {
+	if _, ok := r.services[service]; ok {
+		return true
+	}
+	for _, pattern := range r.patterns {
+		if pattern.MatchString(service) {
+			return true
+		}
+	}
+	return false
+}
+
+// Policy resolves the retention of the spans of each service with the first rule matching the service.
+// The services not matched by any rule keep the default retention of the storage backend.
+// A nil Policy matches no service.
+type Policy struct {
+	rules []rule
+	// matches caches the index of the rule matching each service, or -1
+	matches sync.Map
+}
+
+// LoadPolicy reads the retention policy from a file in YAML or JSON format.
+func LoadPolicy(file string) (*Policy, error) {
+	var f policyFile
+	if err := config.LoadFile(file, "retention policy", &f); err != nil {
+		return nil, err
+	}
+	return f.toPolicy()
+}
+
+// toPolicy validates the rules of the file and creates a Policy applying them.
+func (f *policyFile) toPolicy() (*Policy, error) {
+	policy, err := NewPolicy(f.Rules)
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid retention policy")
+	}
+	return policy, nil
+}
+
+// NewPolicy creates a Policy applying the rules in order.
+func NewPolicy(rules []Rule) (*Policy, error) {
+	p := &Policy{}
+	names := make(map[string]struct{}, len(rules))
+	for _, r := range rules {
+		if !ruleNameRegexp.MatchString(r.Name) {
+			return nil, fmt.Errorf("rule name %q must be made of lowercase letters, digits, underscores and hyphens", r.Name)
+		}
+		if _, ok := names[r.Name]; ok {
+			return nil, fmt.Errorf("duplicate rule %s", r.Name)
+		}
+		names[r.Name] = struct{}{}
+		if len(r.Services) == 0 && len(r.ServicePatterns) == 0 {
+			return nil, fmt.Errorf("rule %s matches no service", r.Name)
+		}
+		if r.TTL <= 0 {
+			return nil, fmt.Errorf("rule %s must have a positive TTL", r.Name)
+		}
+		compiled := rule{Rule: r, services: make(map[string]struct{}, len(r.Services))}
+		for _, service := range r.Services {
+			compiled.services[service] = struct{}{}
+		}
+		for _, pattern := range r.ServicePatterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, errors.Wrapf(err, "rule %s: invalid service pattern", r.Name)
+			}
+			compiled.patterns = append(compiled.patterns, re)
+		}
+		p.rules = append(p.rules, compiled)
+	}
+	return p, nil
+}
+
+// Rules returns the rules of the policy, in order.
+func (p *Policy) Rules() []Rule {
+	if p == nil {
+		return nil
+	}
+	rules := make([]Rule, len(p.rules))
+	for i := range p.rules {
+		rules[i] = p.rules[i].Rule
+	}
+	return rules
+}
+
+// Match returns the first rule matching the service.
+func (p *Policy) Match(service string) (Rule, bool) {
+	if p == nil || len(p.rules) == 0 {
+		return Rule{}, false
+	}
+	if index, ok := p.matches.Load(service); ok {
+		return p.rule(index.(int))
+	}
+	index := -1
+	for i := range p.rules {
+		if p.rules[i].matches(service) {
+			index = i
+			break
+		}
+	}
+	p.matches.Store(service, index)
+	return p.rule(index)
+}
+
+func (p *Policy) rule(index int) (Rule, bool) {
+	if index < 0 {
+		return Rule{}, false
+	}
+	return p.rules[index].Rule, true
+}
+
+// TTL returns the retention of the spans of the service, or defaultTTL if no rule matches the service.
+func (p *Policy) TTL(service string, defaultTTL time.Duration) time.Duration {
+	if r, ok := p.Match(service); ok {
+		return r.TTL
+	}
+	return defaultTTL
+}
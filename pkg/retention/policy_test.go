@@ -0,0 +1,111 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+// parsePolicy creates the retention policy from the content of a policy file, like LoadPolicy
+func parsePolicy(content []byte) (*Policy, error) {
+	var f policyFile
+	if err := config.Unmarshal(content, "retention policy", &f); err != nil {
+		return nil, err
+	}
+	return f.toPolicy()
+}
+
+func TestLoadPolicy(t *testing.T) {
+	for _, file := range []string{"fixtures/retention_policy.yaml", "fixtures/retention_policy.json"} {
+		t.Run(file, func(t *testing.T) {
+			policy, err := LoadPolicy(file)
+			require.NoError(t, err)
+			assert.Equal(t, []Rule{
+				{Name: "payments", Services: []string{"payments", "payments-gateway"}, TTL: 720 * time.Hour},
+				{Name: "batch", ServicePatterns: []string{"^batch-", "-cron$"}, TTL: 24 * time.Hour},
+			}, policy.Rules())
+		})
+	}
+}
+
+func TestLoadPolicyErrors(t *testing.T) {
+	_, err := LoadPolicy("fixtures/missing.yaml")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Failed to open retention policy file")
+
+	testCases := []struct {
+		content string
+		err     string
+	}{
+		{content: "rules: [", err: "Failed to unmarshal retention policy"},
+		{content: "rules:\n- name: a\n  services: [a]\n  ttl: 1h\n  unknown: true", err: "Failed to unmarshal retention policy"},
+		{content: "rules:\n- name: a\n  services: [a]\n  ttl: 1 day", err: "Failed to unmarshal retention policy"},
+		{content: "rules:\n- services: [a]\n  ttl: 1h", err: `Invalid retention policy: rule name "" must be made of lowercase letters`},
+		{content: "rules:\n- name: Payments\n  services: [a]\n  ttl: 1h", err: `rule name "Payments" must be made of lowercase letters`},
+		{content: "rules:\n- name: a\n  services: [a]\n  ttl: 1h\n- name: a\n  services: [b]\n  ttl: 1h", err: "duplicate rule a"},
+		{content: "rules:\n- name: a\n  ttl: 1h", err: "rule a matches no service"},
+		{content: "rules:\n- name: a\n  services: [a]", err: "rule a must have a positive TTL"},
+		{content: "rules:\n- name: a\n  service_patterns: ['(']\n  ttl: 1h", err: "rule a: invalid service pattern"},
+	}
+	for _, test := range testCases {
+		_, err := parsePolicy([]byte(test.content))
+		require.Error(t, err, test.content)
+		assert.Contains(t, err.Error(), test.err, test.content)
+	}
+}
+
+func TestPolicyMatch(t *testing.T) {
+	policy, err := NewPolicy([]Rule{
+		{Name: "payments", Services: []string{"payments"}, TTL: 720 * time.Hour},
+		{Name: "batch", ServicePatterns: []string{"^batch-", "-cron$"}, TTL: 24 * time.Hour},
+		{Name: "shadowed", Services: []string{"batch-export"}, TTL: time.Hour},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		service string
+		rule    string
+		ttl     time.Duration
+	}{
+		{service: "payments", rule: "payments", ttl: 720 * time.Hour},
+		{service: "batch-export", rule: "batch", ttl: 24 * time.Hour},
+		{service: "report-cron", rule: "batch", ttl: 24 * time.Hour},
+		{service: "payments-api", ttl: 72 * time.Hour},
+		{service: "", ttl: 72 * time.Hour},
+	}
+	for _, test := range testCases {
+		// the second lookup is served by the cache
+		for i := 0; i < 2; i++ {
+			rule, ok := policy.Match(test.service)
+			assert.Equal(t, test.rule != "", ok, test.service)
+			assert.Equal(t, test.rule, rule.Name, test.service)
+			assert.Equal(t, test.ttl, policy.TTL(test.service, 72*time.Hour), test.service)
+		}
+	}
+}
+
+func TestNilPolicy(t *testing.T) {
+	var policy *Policy
+	_, ok := policy.Match("payments")
+	assert.False(t, ok)
+	assert.Equal(t, time.Hour, policy.TTL("payments", time.Hour))
+	assert.Nil(t, policy.Rules())
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenancy
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GetValidTenant returns the tenant carried by the gRPC metadata of the incoming context.
+// The error is a gRPC status error with code Unauthenticated if there is no tenant, or
+// PermissionDenied if the tenant is not accepted.
+func GetValidTenant(ctx context.Context, m *Manager) (string, error) {
+	var tenant string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(m.Header); len(values) > 0 {
+			tenant = values[0]
+		}
+	}
+	switch err := m.CheckTenant(tenant); err {
+	case nil:
+		return tenant, nil
+	case ErrMissingTenant:
+		return "", status.Error(codes.Unauthenticated, err.Error())
+	default:
+		return "", status.Error(codes.PermissionDenied, err.Error())
+	}
+}
+
+// NewGuardingUnaryInterceptor returns a gRPC interceptor that stores the tenant taken from
+// the metadata in the context of unary calls, and rejects calls without an accepted tenant.
+func NewGuardingUnaryInterceptor(m *Manager) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		tenant, err := GetValidTenant(ctx, m)
+		if err != nil {
+			return nil, err
+		}
+		return handler(WithTenant(ctx, tenant), req)
+	}
+}
+
+// NewGuardingStreamInterceptor returns a gRPC interceptor that stores the tenant taken from
+// the metadata in the context of streaming calls, and rejects calls without an accepted tenant.
+func NewGuardingStreamInterceptor(m *Manager) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		tenant, err := GetValidTenant(stream.Context(), m)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &tenantedServerStream{ServerStream: stream, ctx: WithTenant(stream.Context(), tenant)})
+	}
+}
+
+type tenantedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tenantedServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenancy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func incomingContext(tenant string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant", tenant))
+}
+
+func TestGetValidTenant(t *testing.T) {
+	m, err := NewManager(&Options{Enabled: true, Tenants: []string{"acme"}})
+	require.NoError(t, err)
+
+	tenant, err := GetValidTenant(incomingContext("acme"), m)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", tenant)
+
+	_, err = GetValidTenant(context.Background(), m)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = GetValidTenant(incomingContext("globex"), m)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestNewGuardingUnaryInterceptor(t *testing.T) {
+	m, err := NewManager(&Options{Enabled: true})
+	require.NoError(t, err)
+	interceptor := NewGuardingUnaryInterceptor(m)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return GetTenant(ctx), nil
+	}
+
+	resp, err := interceptor(incomingContext("acme"), nil, &grpc.UnaryServerInfo{}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", resp)
+
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+type mockServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *mockServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestNewGuardingStreamInterceptor(t *testing.T) {
+	m, err := NewManager(&Options{Enabled: true})
+	require.NoError(t, err)
+	interceptor := NewGuardingStreamInterceptor(m)
+	var tenant string
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		tenant = GetTenant(stream.Context())
+		return nil
+	}
+
+	err = interceptor(nil, &mockServerStream{ctx: incomingContext("acme")}, &grpc.StreamServerInfo{}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", tenant)
+
+	err = interceptor(nil, &mockServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{}, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenancy
+
+import (
+	"net/http"
+)
+
+// ExtractTenantHTTPHandler returns an http.Handler that stores the tenant taken from the tenant
+// header in the request context before passing the request on to the handler. Requests without
+// an accepted tenant are rejected with 401 or 403. The handler is returned as is if multi-tenancy
+// is disabled.
+func ExtractTenantHTTPHandler(m *Manager, handler http.Handler) http.Handler {
+	if !m.Enabled {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant := r.Header.Get(m.Header)
+		switch err := m.CheckTenant(tenant); err {
+		case nil:
+			handler.ServeHTTP(w, r.WithContext(WithTenant(r.Context(), tenant)))
+		case ErrMissingTenant:
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		default:
+			http.Error(w, err.Error(), http.StatusForbidden)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenancy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractTenantHTTPHandler(t *testing.T) {
+	m, err := NewManager(&Options{Enabled: true, Tenants: []string{"acme"}})
+	require.NoError(t, err)
+	handler := ExtractTenantHTTPHandler(m, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(GetTenant(r.Context())))
+	}))
+
+	testCases := []struct {
+		tenant string
+		status int
+		body   string
+	}{
+		{tenant: "acme", status: http.StatusOK, body: "acme"},
+		{tenant: "", status: http.StatusUnauthorized, body: "missing tenant\n"},
+		{tenant: "globex", status: http.StatusForbidden, body: "unknown tenant\n"},
+	}
+	for _, test := range testCases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if test.tenant != "" {
+			req.Header.Set("x-tenant", test.tenant)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, test.status, w.Code, test.tenant)
+		assert.Equal(t, test.body, w.Body.String(), test.tenant)
+	}
+}
+
+func TestExtractTenantHTTPHandlerDisabled(t *testing.T) {
+	m, err := NewManager(&Options{})
+	require.NoError(t, err)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	w := httptest.NewRecorder()
+	ExtractTenantHTTPHandler(m, handler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenancy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+var (
+	// ErrMissingTenant is returned when multi-tenancy is enabled and a request carries no tenant.
+	ErrMissingTenant = errors.New("missing tenant")
+
+	// ErrUnknownTenant is returned when a request carries a tenant that is not accepted.
+	ErrUnknownTenant = errors.New("unknown tenant")
+
+	// tenant names end up in directory, index and keyspace names, so they are kept to a safe alphabet
+	tenantNameRegexp = regexp.MustCompile(`^[a-z0-9_]+$`)
+)
+
+// ValidateName returns an error if the tenant name cannot be used to name per-tenant storage.
+func ValidateName(tenant string) error {
+	if !tenantNameRegexp.MatchString(tenant) {
+		return fmt.Errorf("invalid tenant %q, tenants must only contain lowercase letters, digits and underscores", tenant)
+	}
+	return nil
+}
+
+// Manager validates the tenants carried by requests.
+type Manager struct {
+	Enabled bool
+	Header  string
+	tenants map[string]struct{}
+}
+
+// NewManager creates a Manager from the multi-tenancy options.
+func NewManager(options *Options) (*Manager, error) {
+	header := options.Header
+	if header == "" {
+		header = defaultHeader
+	}
+	m := &Manager{
+		Enabled: options.Enabled,
+		Header:  header,
+	}
+	if len(options.Tenants) > 0 {
+		m.tenants = make(map[string]struct{}, len(options.Tenants))
+		for _, tenant := range options.Tenants {
+			if err := ValidateName(tenant); err != nil {
+				return nil, err
+			}
+			m.tenants[tenant] = struct{}{}
+		}
+	}
+	return m, nil
+}
+
+// CheckTenant returns ErrMissingTenant or ErrUnknownTenant if the tenant is not accepted.
+func (m *Manager) CheckTenant(tenant string) error {
+	if tenant == "" {
+		return ErrMissingTenant
+	}
+	if ValidateName(tenant) != nil {
+		return ErrUnknownTenant
+	}
+	if m.tenants != nil {
+		if _, ok := m.tenants[tenant]; !ok {
+			return ErrUnknownTenant
+		}
+	}
+	return nil
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a new context carrying the tenant.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// GetTenant returns the tenant carried by the context, or an empty string.
+func GetTenant(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenancy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateName(t *testing.T) {
+	for _, tenant := range []string{"acme", "team_1"} {
+		assert.NoError(t, ValidateName(tenant), tenant)
+	}
+	for _, tenant := range []string{"", "Acme", "team-1", "../acme", "acme corp"} {
+		assert.Error(t, ValidateName(tenant), tenant)
+	}
+}
+
+func TestNewManager(t *testing.T) {
+	m, err := NewManager(&Options{Enabled: true})
+	require.NoError(t, err)
+	assert.True(t, m.Enabled)
+	assert.Equal(t, "x-tenant", m.Header)
+
+	_, err = NewManager(&Options{Tenants: []string{"acme", "Globex"}})
+	assert.EqualError(t, err, `invalid tenant "Globex", tenants must only contain lowercase letters, digits and underscores`)
+}
+
+func TestCheckTenant(t *testing.T) {
+	anyTenant, err := NewManager(&Options{Enabled: true})
+	require.NoError(t, err)
+	listed, err := NewManager(&Options{Enabled: true, Tenants: []string{"acme"}})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		manager *Manager
+		tenant  string
+		err     error
+	}{
+		{manager: anyTenant, tenant: "acme"},
+		{manager: anyTenant, tenant: "globex"},
+		{manager: anyTenant, tenant: "", err: ErrMissingTenant},
+		{manager: anyTenant, tenant: "Globex", err: ErrUnknownTenant},
+		{manager: listed, tenant: "acme"},
+		{manager: listed, tenant: "globex", err: ErrUnknownTenant},
+		{manager: listed, tenant: "", err: ErrMissingTenant},
+	}
+	for _, test := range testCases {
+		assert.Equal(t, test.err, test.manager.CheckTenant(test.tenant), test.tenant)
+	}
+}
+
+func TestTenantContext(t *testing.T) {
+	assert.Equal(t, "", GetTenant(context.Background()))
+	assert.Equal(t, "acme", GetTenant(WithTenant(context.Background(), "acme")))
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenancy
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	tenancyEnabled = "multi-tenancy.enabled"
+	tenancyHeader  = "multi-tenancy.header"
+	validTenants   = "multi-tenancy.tenants"
+
+	defaultHeader = "x-tenant"
+)
+
+// Options describes the configuration properties for multi-tenancy
+type Options struct {
+	// Enabled requires every request to carry a tenant and keeps the data of each tenant apart
+	Enabled bool
+	// Header is the name of the HTTP header or gRPC metadata key that carries the tenant
+	Header string
+	// Tenants lists the accepted tenants; any well-formed tenant is accepted if empty
+	Tenants []string
+}
+
+// AddFlags adds flags for Options
+func AddFlags(flagSet *flag.FlagSet) {
+	flagSet.Bool(tenancyEnabled, false, "Enable multi-tenancy; spans are stored and queried per tenant, taken from the tenant header")
+	flagSet.String(tenancyHeader, defaultHeader, "HTTP header or gRPC metadata key carrying the tenant")
+	flagSet.String(validTenants, "", "Comma-separated list of accepted tenants; if empty, any tenant made of lowercase letters, digits and underscores is accepted")
+}
+
+// InitFromViper initializes Options with properties from viper
+func (opts Options) InitFromViper(v *viper.Viper) Options {
+	opts.Enabled = v.GetBool(tenancyEnabled)
+	opts.Header = v.GetString(tenancyHeader)
+	opts.Tenants = nil
+	for _, tenant := range strings.Split(v.GetString(validTenants), ",") {
+		if tenant = strings.TrimSpace(tenant); tenant != "" {
+			opts.Tenants = append(opts.Tenants, tenant)
+		}
+	}
+	return opts
+}
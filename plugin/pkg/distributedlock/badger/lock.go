@@ -0,0 +1,120 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package badger
+
+import (
+	"time"
+
+	"github.com/dgraph-io/badger"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultTTL = 60 * time.Second
+
+	// Lease keys have the first bit unset to never overlap with span keys, see plugin/storage/badger/spanstore
+	leaseKeyPrefix byte = 0x0A
+)
+
+var (
+	errLockOwnership = errors.New("This host does not own the resource lock")
+)
+
+// Lock is a lock based off Badger, shared by all the components using the same Badger instance.
+type Lock struct {
+	store    *badger.DB
+	tenantID string
+}
+
+// NewLock creates a new instance of a locking mechanism based off Badger.
+func NewLock(db *badger.DB, tenantID string) *Lock {
+	return &Lock{
+		store:    db,
+		tenantID: tenantID,
+	}
+}
+
+// Acquire acquires a lease around a given resource. NB. Badger only allows ttl of seconds granularity
+func (l *Lock) Acquire(resource string, ttl time.Duration) (bool, error) {
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+	acquired := false
+	err := l.store.Update(func(txn *badger.Txn) error {
+		owner, err := l.getOwner(txn, resource)
+		if err != nil {
+			return err
+		}
+		if owner != "" && owner != l.tenantID {
+			return nil
+		}
+		// The lock is either free or already owned by this host, in which case the lease is extended
+		acquired = true
+		return txn.SetEntry(&badger.Entry{
+			Key:       leaseKey(resource),
+			Value:     []byte(l.tenantID),
+			ExpiresAt: uint64(time.Now().Add(ttl).Unix()),
+		})
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "Failed to acquire resource lock due to badger error")
+	}
+	return acquired, nil
+}
+
+// Forfeit forfeits an existing lease around a given resource.
+func (l *Lock) Forfeit(resource string) (bool, error) {
+	owned := false
+	err := l.store.Update(func(txn *badger.Txn) error {
+		owner, err := l.getOwner(txn, resource)
+		if err != nil {
+			return err
+		}
+		if owner != l.tenantID {
+			return nil
+		}
+		owned = true
+		return txn.Delete(leaseKey(resource))
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "Failed to forfeit resource lock due to badger error")
+	}
+	if !owned {
+		return false, errors.Wrap(errLockOwnership, "Failed to forfeit resource lock")
+	}
+	return true, nil
+}
+
+// getOwner returns the current owner of the lease around a resource, or an empty string if there is none.
+func (l *Lock) getOwner(txn *badger.Txn, resource string) (string, error) {
+	item, err := txn.Get(leaseKey(resource))
+	if err == badger.ErrKeyNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	owner, err := item.ValueCopy(nil)
+	if err != nil {
+		return "", err
+	}
+	return string(owner), nil
+}
+
+func leaseKey(resource string) []byte {
+	key := make([]byte, 0, len(resource)+1)
+	key = append(key, leaseKeyPrefix)
+	return append(key, resource...)
+}
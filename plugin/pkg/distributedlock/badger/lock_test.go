@@ -0,0 +1,95 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package badger
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	localhost    = "localhost"
+	samplingLock = "sampling_lock"
+)
+
+func TestAcquireAndForfeit(t *testing.T) {
+	runWithBadger(t, func(store *badger.DB, t *testing.T) {
+		lock := NewLock(store, localhost)
+		other := NewLock(store, "otherhost")
+
+		acquired, err := lock.Acquire(samplingLock, 0)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+
+		// the lease is extended when the lock is already held by this host
+		acquired, err = lock.Acquire(samplingLock, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+
+		acquired, err = other.Acquire(samplingLock, time.Minute)
+		require.NoError(t, err)
+		assert.False(t, acquired)
+
+		forfeited, err := other.Forfeit(samplingLock)
+		assert.EqualError(t, err, "Failed to forfeit resource lock: This host does not own the resource lock")
+		assert.False(t, forfeited)
+
+		forfeited, err = lock.Forfeit(samplingLock)
+		require.NoError(t, err)
+		assert.True(t, forfeited)
+
+		acquired, err = other.Acquire(samplingLock, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+	})
+}
+
+func TestAcquireExpiredLease(t *testing.T) {
+	runWithBadger(t, func(store *badger.DB, t *testing.T) {
+		other := NewLock(store, "otherhost")
+		acquired, err := other.Acquire(samplingLock, -time.Hour)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+
+		acquired, err = NewLock(store, localhost).Acquire(samplingLock, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+	})
+}
+
+func runWithBadger(t *testing.T, test func(store *badger.DB, t *testing.T)) {
+	opts := badger.DefaultOptions
+
+	opts.SyncWrites = false
+	dir, _ := ioutil.TempDir("", "badger")
+	opts.Dir = dir
+	opts.ValueDir = dir
+
+	store, err := badger.Open(opts)
+	defer func() {
+		store.Close()
+		os.RemoveAll(dir)
+	}()
+
+	require.NoError(t, err)
+
+	test(store, t)
+}
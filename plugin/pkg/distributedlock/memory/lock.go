@@ -0,0 +1,77 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultTTL = 60 * time.Second
+)
+
+var (
+	errLockOwnership = errors.New("This host does not own the resource lock")
+)
+
+type lease struct {
+	owner     string
+	expiresAt time.Time
+}
+
+// Lock is an in-process lock, suitable for single-node deployments backed by in-memory storage.
+type Lock struct {
+	sync.Mutex
+	tenantID string
+	leases   map[string]lease
+}
+
+// NewLock creates a new instance of an in-memory locking mechanism.
+func NewLock(tenantID string) *Lock {
+	return &Lock{
+		tenantID: tenantID,
+		leases:   make(map[string]lease),
+	}
+}
+
+// Acquire acquires a lease around a given resource. If this host already holds the lease, it is extended.
+func (l *Lock) Acquire(resource string, ttl time.Duration) (bool, error) {
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+	l.Lock()
+	defer l.Unlock()
+	now := time.Now()
+	if current, ok := l.leases[resource]; ok && current.owner != l.tenantID && now.Before(current.expiresAt) {
+		return false, nil
+	}
+	l.leases[resource] = lease{owner: l.tenantID, expiresAt: now.Add(ttl)}
+	return true, nil
+}
+
+// Forfeit forfeits an existing lease around a given resource.
+func (l *Lock) Forfeit(resource string) (bool, error) {
+	l.Lock()
+	defer l.Unlock()
+	current, ok := l.leases[resource]
+	if !ok || current.owner != l.tenantID || !time.Now().Before(current.expiresAt) {
+		return false, errors.Wrap(errLockOwnership, "Failed to forfeit resource lock")
+	}
+	delete(l.leases, resource)
+	return true, nil
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	localhost    = "localhost"
+	samplingLock = "sampling_lock"
+)
+
+func TestAcquire(t *testing.T) {
+	testCases := []struct {
+		caption  string
+		existing *lease
+		acquired bool
+	}{
+		{
+			caption:  "successfully created lock",
+			acquired: true,
+		},
+		{
+			caption:  "lock already exists and belongs to localhost",
+			existing: &lease{owner: localhost, expiresAt: time.Now().Add(time.Minute)},
+			acquired: true,
+		},
+		{
+			caption:  "failed to acquire lock",
+			existing: &lease{owner: "otherhost", expiresAt: time.Now().Add(time.Minute)},
+			acquired: false,
+		},
+		{
+			caption:  "lock belongs to another host but has expired",
+			existing: &lease{owner: "otherhost", expiresAt: time.Now().Add(-time.Minute)},
+			acquired: true,
+		},
+	}
+	for _, tc := range testCases {
+		testCase := tc // capture loop var
+		t.Run(testCase.caption, func(t *testing.T) {
+			lock := NewLock(localhost)
+			if testCase.existing != nil {
+				lock.leases[samplingLock] = *testCase.existing
+			}
+			acquired, err := lock.Acquire(samplingLock, 0)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.acquired, acquired)
+			if testCase.acquired {
+				assert.Equal(t, localhost, lock.leases[samplingLock].owner)
+				assert.True(t, lock.leases[samplingLock].expiresAt.After(time.Now().Add(defaultTTL/2)))
+			}
+		})
+	}
+}
+
+func TestForfeit(t *testing.T) {
+	testCases := []struct {
+		caption        string
+		existing       *lease
+		forfeited      bool
+		expectedErrMsg string
+	}{
+		{
+			caption:        "lock does not exist",
+			forfeited:      false,
+			expectedErrMsg: "Failed to forfeit resource lock: This host does not own the resource lock",
+		},
+		{
+			caption:   "successfully forfeited lock",
+			existing:  &lease{owner: localhost, expiresAt: time.Now().Add(time.Minute)},
+			forfeited: true,
+		},
+		{
+			caption:        "lock belongs to another host",
+			existing:       &lease{owner: "otherhost", expiresAt: time.Now().Add(time.Minute)},
+			forfeited:      false,
+			expectedErrMsg: "Failed to forfeit resource lock: This host does not own the resource lock",
+		},
+		{
+			caption:        "lock has expired",
+			existing:       &lease{owner: localhost, expiresAt: time.Now().Add(-time.Minute)},
+			forfeited:      false,
+			expectedErrMsg: "Failed to forfeit resource lock: This host does not own the resource lock",
+		},
+	}
+	for _, tc := range testCases {
+		testCase := tc // capture loop var
+		t.Run(testCase.caption, func(t *testing.T) {
+			lock := NewLock(localhost)
+			if testCase.existing != nil {
+				lock.leases[samplingLock] = *testCase.existing
+			}
+			forfeited, err := lock.Forfeit(samplingLock)
+			if testCase.expectedErrMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, testCase.expectedErrMsg)
+			}
+			assert.Equal(t, testCase.forfeited, forfeited)
+		})
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adaptive
+
+import (
+	"sync"
+	"time"
+
+	"github.com/uber/jaeger-client-go"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/model"
+	ss "github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
+	"github.com/jaegertracing/jaeger/storage/samplingstore"
+)
+
+const (
+	// maxProbabilities bounds the number of distinct sampling probabilities
+	// remembered for a single operation within one aggregation interval.
+	maxProbabilities = 10
+)
+
+// aggregator counts root spans per service and operation and periodically flushes
+// the accumulated throughput into the sampling store, where it is picked up by the
+// adaptive sampling processor of the leader collector.
+type aggregator struct {
+	sync.Mutex
+
+	operationsCounter   metrics.Counter
+	servicesCounter     metrics.Counter
+	flushErrorsCounter  metrics.Counter
+	currentThroughput   serviceOperationThroughput
+	aggregationInterval time.Duration
+	storage             samplingstore.Store
+	logger              *zap.Logger
+	stop                chan struct{}
+	wg                  sync.WaitGroup
+}
+
+// NewAggregator creates a throughput aggregator that flushes the throughput
+// observed over every aggregationInterval into the storage.
+func NewAggregator(
+	metricsFactory metrics.Factory,
+	aggregationInterval time.Duration,
+	storage samplingstore.Store,
+	logger *zap.Logger,
+) ss.Aggregator {
+	metricsFactory = metricsFactory.Namespace(metrics.NSOptions{Name: "adaptive_sampling_aggregator"})
+	return &aggregator{
+		operationsCounter:   metricsFactory.Counter(metrics.Options{Name: "sampling_operations"}),
+		servicesCounter:     metricsFactory.Counter(metrics.Options{Name: "sampling_services"}),
+		flushErrorsCounter:  metricsFactory.Counter(metrics.Options{Name: "flush_errors"}),
+		currentThroughput:   make(serviceOperationThroughput),
+		aggregationInterval: aggregationInterval,
+		storage:             storage,
+		logger:              logger,
+		stop:                make(chan struct{}),
+	}
+}
+
+func (a *aggregator) runAggregationLoop() {
+	defer a.wg.Done()
+	ticker := time.NewTicker(a.aggregationInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			a.saveThroughput(a.swapThroughput())
+		case <-a.stop:
+			return
+		}
+	}
+}
+
+// swapThroughput replaces the current throughput with an empty one and returns the old one.
+func (a *aggregator) swapThroughput() serviceOperationThroughput {
+	a.Lock()
+	defer a.Unlock()
+	throughput := a.currentThroughput
+	a.currentThroughput = make(serviceOperationThroughput)
+	return throughput
+}
+
+func (a *aggregator) saveThroughput(throughput serviceOperationThroughput) {
+	totalOperations := 0
+	var throughputSlice []*model.Throughput
+	for _, opThroughput := range throughput {
+		totalOperations += len(opThroughput)
+		for _, t := range opThroughput {
+			throughputSlice = append(throughputSlice, t)
+		}
+	}
+	a.operationsCounter.Inc(int64(totalOperations))
+	a.servicesCounter.Inc(int64(len(throughput)))
+	if len(throughputSlice) == 0 {
+		return
+	}
+	if err := a.storage.InsertThroughput(throughputSlice); err != nil {
+		a.flushErrorsCounter.Inc(1)
+		a.logger.Error("failed to save throughput", zap.Error(err))
+	}
+}
+
+// RecordThroughput implements strategystore.Aggregator.
+func (a *aggregator) RecordThroughput(service, operation, samplerType string, probability float64) {
+	a.Lock()
+	defer a.Unlock()
+	if _, ok := a.currentThroughput[service]; !ok {
+		a.currentThroughput[service] = make(map[string]*model.Throughput)
+	}
+	throughput, ok := a.currentThroughput[service][operation]
+	if !ok {
+		throughput = &model.Throughput{
+			Service:       service,
+			Operation:     operation,
+			Probabilities: make(map[string]struct{}),
+		}
+		a.currentThroughput[service][operation] = throughput
+	}
+	probStr := truncateFloat(probability)
+	if len(throughput.Probabilities) != maxProbabilities {
+		throughput.Probabilities[probStr] = struct{}{}
+	}
+	// Only probabilistically sampled root spans increment the throughput counter. Spans sampled
+	// by the lower bound sampler are still recorded with a count of 0, so that the adaptive
+	// sampling processor is made aware of the operation.
+	if samplerType == jaeger.SamplerTypeProbabilistic {
+		throughput.Count++
+	}
+}
+
+// Start implements strategystore.Aggregator.
+func (a *aggregator) Start() {
+	a.wg.Add(1)
+	go a.runAggregationLoop()
+}
+
+// Close implements strategystore.Aggregator.
+func (a *aggregator) Close() error {
+	close(a.stop)
+	a.wg.Wait()
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adaptive
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/model"
+	smocks "github.com/jaegertracing/jaeger/storage/samplingstore/mocks"
+)
+
+func TestAggregator(t *testing.T) {
+	metricsFactory := metricstest.NewFactory(0)
+
+	mockStorage := &smocks.Store{}
+	mockStorage.On("InsertThroughput", mock.AnythingOfType("[]*model.Throughput")).Return(nil)
+
+	a := NewAggregator(metricsFactory, 5*time.Millisecond, mockStorage, zap.NewNop())
+	a.RecordThroughput("A", "GET", "probabilistic", 0.001)
+	a.RecordThroughput("B", "POST", "probabilistic", 0.001)
+	a.RecordThroughput("C", "GET", "probabilistic", 0.001)
+	a.RecordThroughput("A", "POST", "probabilistic", 0.001)
+	a.RecordThroughput("A", "GET", "probabilistic", 0.001)
+	a.RecordThroughput("A", "GET", "lowerbound", 0.001)
+
+	a.Start()
+	defer a.Close()
+	for i := 0; i < 10000; i++ {
+		counters, _ := metricsFactory.Snapshot()
+		if counters["adaptive_sampling_aggregator.sampling_services"] == 3 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	metricsFactory.AssertCounterMetrics(t, []metricstest.ExpectedMetric{
+		{Name: "adaptive_sampling_aggregator.sampling_operations", Value: 4},
+		{Name: "adaptive_sampling_aggregator.sampling_services", Value: 3},
+	}...)
+}
+
+func TestRecordThroughput(t *testing.T) {
+	mockStorage := &smocks.Store{}
+	a := NewAggregator(metricstest.NewFactory(0), time.Minute, mockStorage, zap.NewNop())
+	agg := a.(*aggregator)
+
+	a.RecordThroughput("A", "GET", "lowerbound", 0.001)
+	require.Len(t, agg.currentThroughput["A"], 1)
+	assert.EqualValues(t, 0, agg.currentThroughput["A"]["GET"].Count)
+	assert.Equal(t, map[string]struct{}{"0.001000": {}}, agg.currentThroughput["A"]["GET"].Probabilities)
+
+	a.RecordThroughput("A", "GET", "probabilistic", 0.002)
+	assert.EqualValues(t, 1, agg.currentThroughput["A"]["GET"].Count)
+	assert.Len(t, agg.currentThroughput["A"]["GET"].Probabilities, 2)
+
+	for i := 0; i < 2*maxProbabilities; i++ {
+		a.RecordThroughput("A", "GET", "probabilistic", float64(i)/100)
+	}
+	assert.Len(t, agg.currentThroughput["A"]["GET"].Probabilities, maxProbabilities)
+}
+
+func TestSaveThroughputError(t *testing.T) {
+	metricsFactory := metricstest.NewFactory(0)
+	mockStorage := &smocks.Store{}
+	mockStorage.On("InsertThroughput", mock.AnythingOfType("[]*model.Throughput")).Return(errTestStorage)
+
+	a := NewAggregator(metricsFactory, time.Minute, mockStorage, zap.NewNop()).(*aggregator)
+	a.saveThroughput(serviceOperationThroughput{
+		"A": map[string]*model.Throughput{"GET": {Service: "A", Operation: "GET", Count: 1}},
+	})
+	a.saveThroughput(serviceOperationThroughput{})
+
+	mockStorage.AssertNumberOfCalls(t, "InsertThroughput", 1)
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{
+		Name: "adaptive_sampling_aggregator.flush_errors", Value: 1,
+	})
+}
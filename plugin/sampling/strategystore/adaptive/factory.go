@@ -15,20 +15,35 @@
 package adaptive
 
 import (
+	"errors"
 	"flag"
+	"os"
 
 	"github.com/spf13/viper"
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
+	"github.com/jaegertracing/jaeger/pkg/distributedlock"
+	"github.com/jaegertracing/jaeger/plugin/sampling/internal/leaderelection"
+	"github.com/jaegertracing/jaeger/storage"
+	"github.com/jaegertracing/jaeger/storage/samplingstore"
 )
 
+const (
+	// resourceName is the name of the distributed lock held by the leader collector.
+	resourceName = "sampling_store_leader"
+)
+
+var errNilSamplingStoreFactory = errors.New("sampling store factory is nil, please configure a storage backend that supports adaptive sampling")
+
 // Factory implements strategystore.Factory for an adaptive strategy store.
 type Factory struct {
 	options        Options
 	logger         *zap.Logger
 	metricsFactory metrics.Factory
+	lock           distributedlock.Lock
+	store          samplingstore.Store
 }
 
 // NewFactory creates a new Factory.
@@ -50,14 +65,44 @@ func (f *Factory) InitFromViper(v *viper.Viper) {
 }
 
 // Initialize implements strategystore.Factory
-func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger) error {
+func (f *Factory) Initialize(metricsFactory metrics.Factory, ssFactory storage.SamplingStoreFactory, logger *zap.Logger) error {
+	if ssFactory == nil {
+		return errNilSamplingStoreFactory
+	}
 	f.logger = logger
 	f.metricsFactory = metricsFactory
+	lock, err := ssFactory.CreateLock()
+	if err != nil {
+		return err
+	}
+	store, err := ssFactory.CreateSamplingStore()
+	if err != nil {
+		return err
+	}
+	f.lock = lock
+	f.store = store
 	return nil
 }
 
 // CreateStrategyStore implements strategystore.Factory
-func (f *Factory) CreateStrategyStore() (strategystore.StrategyStore, error) {
-	// TODO
-	return nil, nil
+func (f *Factory) CreateStrategyStore() (strategystore.StrategyStore, strategystore.Aggregator, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, nil, err
+	}
+	participant := leaderelection.NewElectionParticipant(f.lock, resourceName, leaderelection.ElectionParticipantOptions{
+		LeaderLeaseRefreshInterval:   f.options.LeaderLeaseRefreshInterval,
+		FollowerLeaseRefreshInterval: f.options.FollowerLeaseRefreshInterval,
+		Logger:                       f.logger,
+	})
+	p, err := NewProcessor(f.options, hostname, f.store, participant, f.metricsFactory, f.logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := p.(*processor).Start(); err != nil {
+		return nil, nil, err
+	}
+	a := NewAggregator(f.metricsFactory, f.options.CalculationInterval, f.store, f.logger)
+	a.Start()
+	return p, a, nil
 }
@@ -15,16 +15,24 @@
 package adaptive
 
 import (
+	"errors"
+	"io"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/model"
 	ss "github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
 	"github.com/jaegertracing/jaeger/pkg/config"
+	lmocks "github.com/jaegertracing/jaeger/pkg/distributedlock/mocks"
 	"github.com/jaegertracing/jaeger/plugin"
+	storagemocks "github.com/jaegertracing/jaeger/storage/mocks"
+	smocks "github.com/jaegertracing/jaeger/storage/samplingstore/mocks"
 )
 
 var _ ss.Factory = new(Factory)
@@ -61,7 +69,41 @@ func TestFactory(t *testing.T) {
 	assert.Equal(t, time.Second, f.options.LeaderLeaseRefreshInterval)
 	assert.Equal(t, time.Second*2, f.options.FollowerLeaseRefreshInterval)
 
-	assert.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
-	_, err := f.CreateStrategyStore()
-	assert.NoError(t, err)
+	lock := &lmocks.Lock{}
+	lock.On("Acquire", mock.Anything, mock.Anything).Return(false, nil)
+	store := &smocks.Store{}
+	store.On("GetLatestProbabilities").Return(model.ServiceOperationProbabilities{}, nil)
+	store.On("GetThroughput", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
+		Return([]*model.Throughput{}, nil)
+	ssFactory := &storagemocks.SamplingStoreFactory{}
+	ssFactory.On("CreateLock").Return(lock, nil)
+	ssFactory.On("CreateSamplingStore").Return(store, nil)
+
+	require.NoError(t, f.Initialize(metrics.NullFactory, ssFactory, zap.NewNop()))
+	strategyStore, aggregator, err := f.CreateStrategyStore()
+	require.NoError(t, err)
+	require.NotNil(t, strategyStore)
+	require.NotNil(t, aggregator)
+	assert.NoError(t, aggregator.Close())
+	assert.NoError(t, strategyStore.(io.Closer).Close())
+}
+
+func TestFactoryInitializeErrors(t *testing.T) {
+	f := NewFactory()
+	assert.Equal(t, errNilSamplingStoreFactory, f.Initialize(metrics.NullFactory, nil, zap.NewNop()))
+
+	lockErr := &storagemocks.SamplingStoreFactory{}
+	lockErr.On("CreateLock").Return(nil, errors.New("lock error"))
+	assert.EqualError(t, f.Initialize(metrics.NullFactory, lockErr, zap.NewNop()), "lock error")
+
+	storeErr := &storagemocks.SamplingStoreFactory{}
+	storeErr.On("CreateLock").Return(&lmocks.Lock{}, nil)
+	storeErr.On("CreateSamplingStore").Return(nil, errors.New("store error"))
+	assert.EqualError(t, f.Initialize(metrics.NullFactory, storeErr, zap.NewNop()), "store error")
+}
+
+func TestCreateStrategyStoreInvalidOptions(t *testing.T) {
+	f := NewFactory()
+	_, _, err := f.CreateStrategyStore()
+	assert.Equal(t, errNonZero, err)
 }
@@ -30,7 +30,7 @@ type FactoryConfig struct {
 
 // FactoryConfigFromEnv reads the desired sampling type from the SAMPLING_TYPE environment variable. Allowed values:
 //   * `static` - built-in
-//   * `adaptive` - built-in // TODO
+//   * `adaptive` - built-in
 func FactoryConfigFromEnv() FactoryConfig {
 	strategyStoreType := os.Getenv(SamplingTypeEnvVar)
 	if strategyStoreType == "" {
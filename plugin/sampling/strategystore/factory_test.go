@@ -27,6 +27,7 @@ import (
 
 	ss "github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
 	"github.com/jaegertracing/jaeger/plugin"
+	"github.com/jaegertracing/jaeger/storage"
 )
 
 var _ ss.Factory = new(Factory)
@@ -42,23 +43,27 @@ func TestNewFactory(t *testing.T) {
 	mock := new(mockFactory)
 	f.factories[staticStrategyStoreType] = mock
 
-	assert.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
-	_, err = f.CreateStrategyStore()
+	assert.NoError(t, f.Initialize(metrics.NullFactory, nil, zap.NewNop()))
+	_, _, err = f.CreateStrategyStore()
 	assert.NoError(t, err)
 
 	// force the mock to return errors
 	mock.retError = true
-	assert.EqualError(t, f.Initialize(metrics.NullFactory, zap.NewNop()), "error initializing store")
-	_, err = f.CreateStrategyStore()
+	assert.EqualError(t, f.Initialize(metrics.NullFactory, nil, zap.NewNop()), "error initializing store")
+	_, _, err = f.CreateStrategyStore()
 	assert.EqualError(t, err, "error creating store")
 
 	f.StrategyStoreType = "nonsense"
-	_, err = f.CreateStrategyStore()
+	_, _, err = f.CreateStrategyStore()
 	assert.EqualError(t, err, "no nonsense strategy store registered")
 
 	_, err = NewFactory(FactoryConfig{StrategyStoreType: "nonsense"})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "unknown sampling strategy store type")
+
+	f, err = NewFactory(FactoryConfig{StrategyStoreType: adaptiveStrategyStoreType})
+	require.NoError(t, err)
+	assert.NotEmpty(t, f.factories[adaptiveStrategyStoreType])
 }
 
 func TestConfigurable(t *testing.T) {
@@ -97,14 +102,14 @@ func (f *mockFactory) InitFromViper(v *viper.Viper) {
 	f.viper = v
 }
 
-func (f *mockFactory) CreateStrategyStore() (ss.StrategyStore, error) {
+func (f *mockFactory) CreateStrategyStore() (ss.StrategyStore, ss.Aggregator, error) {
 	if f.retError {
-		return nil, errors.New("error creating store")
+		return nil, nil, errors.New("error creating store")
 	}
-	return nil, nil
+	return nil, nil, nil
 }
 
-func (f *mockFactory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger) error {
+func (f *mockFactory) Initialize(metricsFactory metrics.Factory, ssFactory storage.SamplingStoreFactory, logger *zap.Logger) error {
 	if f.retError {
 		return errors.New("error initializing store")
 	}
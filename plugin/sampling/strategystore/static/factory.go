@@ -22,19 +22,22 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
+	"github.com/jaegertracing/jaeger/storage"
 )
 
 // Factory implements strategystore.Factory for a static strategy store.
 type Factory struct {
-	options *Options
-	logger  *zap.Logger
+	options        *Options
+	metricsFactory metrics.Factory
+	logger         *zap.Logger
 }
 
 // NewFactory creates a new Factory.
 func NewFactory() *Factory {
 	return &Factory{
-		options: &Options{},
-		logger:  zap.NewNop(),
+		options:        &Options{},
+		metricsFactory: metrics.NullFactory,
+		logger:         zap.NewNop(),
 	}
 }
 
@@ -49,12 +52,13 @@ func (f *Factory) InitFromViper(v *viper.Viper) {
 }
 
 // Initialize implements strategystore.Factory
-func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger) error {
-	f.logger = logger
+func (f *Factory) Initialize(metricsFactory metrics.Factory, ssFactory storage.SamplingStoreFactory, logger *zap.Logger) error {
+	f.metricsFactory, f.logger = metricsFactory, logger
 	return nil
 }
 
 // CreateStrategyStore implements strategystore.Factory
-func (f *Factory) CreateStrategyStore() (strategystore.StrategyStore, error) {
-	return NewStrategyStore(*f.options, f.logger)
+func (f *Factory) CreateStrategyStore() (strategystore.StrategyStore, strategystore.Aggregator, error) {
+	s, err := NewStrategyStore(*f.options, f.metricsFactory, f.logger)
+	return s, nil, err
 }
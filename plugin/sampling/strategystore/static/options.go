@@ -16,27 +16,34 @@ package static
 
 import (
 	"flag"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 const (
-	samplingStrategiesFile = "sampling.strategies-file"
+	samplingStrategiesFile           = "sampling.strategies-file"
+	samplingStrategiesReloadInterval = "sampling.strategies-reload-interval"
 )
 
 // Options holds configuration for the static sampling strategy store.
 type Options struct {
-	// StrategiesFile is the path for the sampling strategies file in JSON format
+	// StrategiesFile is the path for the sampling strategies file in JSON format, or an http(s) URL serving it
 	StrategiesFile string
+	// ReloadInterval is the interval at which the strategies are checked for changes, 0 disables reloading
+	// of a file and polls a URL at the default interval
+	ReloadInterval time.Duration
 }
 
 // AddFlags adds flags for Options
 func AddFlags(flagSet *flag.FlagSet) {
-	flagSet.String(samplingStrategiesFile, "", "The path for the sampling strategies file in JSON format. See sampling documentation to see format of the file")
+	flagSet.String(samplingStrategiesFile, "", "The path for the sampling strategies file in JSON format, or an http(s) URL serving it. See sampling documentation to see format of the file")
+	flagSet.Duration(samplingStrategiesReloadInterval, 0, "The interval at which the sampling strategies file or URL is checked for changes and reloaded. Zero value means no reloading for a file, and a reload every minute for a URL")
 }
 
 // InitFromViper initializes Options with properties from viper
 func (opts *Options) InitFromViper(v *viper.Viper) *Options {
 	opts.StrategiesFile = v.GetString(samplingStrategiesFile)
+	opts.ReloadInterval = v.GetDuration(samplingStrategiesReloadInterval)
 	return opts
 }
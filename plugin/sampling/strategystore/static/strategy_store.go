@@ -20,45 +20,125 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math"
+	"sync/atomic"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
 	ss "github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
+	"github.com/jaegertracing/jaeger/pkg/reload"
 	"github.com/jaegertracing/jaeger/thrift-gen/sampling"
 )
 
 type strategyStore struct {
-	logger *zap.Logger
+	logger  *zap.Logger
+	metrics storeMetrics
 
+	// storedStrategies holds a *storedStrategies, swapped atomically on every successful reload
+	storedStrategies atomic.Value
+
+	reloader *reload.Reloader
+}
+
+type storedStrategies struct {
 	defaultStrategy   *sampling.SamplingStrategyResponse
 	serviceStrategies map[string]*sampling.SamplingStrategyResponse
 }
 
+// strategiesLoader returns the raw content of the strategies.
+type strategiesLoader func() ([]byte, error)
+
+type storeMetrics struct {
+	// Number of times the strategies were successfully reloaded
+	ReloadSuccess metrics.Counter `metric:"reloads" tags:"result=ok"`
+
+	// Number of times the reloaded strategies failed validation; the last good strategies are kept
+	ReloadFailure metrics.Counter `metric:"reloads" tags:"result=err"`
+
+	// Number of times the strategies could not be read from the file or fetched from the URL
+	FetchErrors metrics.Counter `metric:"fetch-errors"`
+}
+
 // NewStrategyStore creates a strategy store that holds static sampling strategies.
-func NewStrategyStore(options Options, logger *zap.Logger) (ss.StrategyStore, error) {
+// The strategies are read from a local file, or fetched from a URL if options.StrategiesFile
+// is an http(s) URL. If options.ReloadInterval is positive, the strategies are periodically
+// checked for changes and swapped in once they pass validation. A URL is always polled,
+// every minute if no reload interval is set.
+func NewStrategyStore(options Options, metricsFactory metrics.Factory, logger *zap.Logger) (ss.StrategyStore, error) {
 	h := &strategyStore{
-		logger:            logger,
-		serviceStrategies: make(map[string]*sampling.SamplingStrategyResponse),
+		logger: logger,
+	}
+	metrics.Init(&h.metrics, metricsFactory.Namespace(metrics.NSOptions{Name: "sampling_strategies"}), nil)
+	var loader strategiesLoader
+	if isURL(options.StrategiesFile) {
+		loader = newURLLoader(options.StrategiesFile, defaultFetchTimeout).load
+	} else {
+		loader = func() ([]byte, error) {
+			return readStrategiesFile(options.StrategiesFile)
+		}
+	}
+	reloadOptions := reload.Options{
+		Name:   "sampling strategies",
+		Source: options.StrategiesFile,
+		Read:   loader,
+		Apply:  h.applyStrategies,
+		Metrics: reload.Metrics{
+			ReloadSuccess: h.metrics.ReloadSuccess,
+			ReloadFailure: h.metrics.ReloadFailure,
+			ReadErrors:    h.metrics.FetchErrors,
+		},
+		Interval: reloadInterval(options),
+		Logger:   logger,
 	}
-	strategies, err := loadStrategies(options.StrategiesFile)
+	reloader, err := reload.New(reloadOptions)
 	if err != nil {
 		return nil, err
 	}
-	h.parseStrategies(strategies)
+	h.reloader = reloader
 	return h, nil
 }
 
 // GetSamplingStrategy implements StrategyStore#GetSamplingStrategy.
 func (h *strategyStore) GetSamplingStrategy(serviceName string) (*sampling.SamplingStrategyResponse, error) {
-	if strategy, ok := h.serviceStrategies[serviceName]; ok {
+	stored := h.storedStrategies.Load().(*storedStrategies)
+	if strategy, ok := stored.serviceStrategies[serviceName]; ok {
 		return strategy, nil
 	}
-	return h.defaultStrategy, nil
+	return stored.defaultStrategy, nil
+}
+
+// Close stops the periodic reloading of the strategies.
+func (h *strategyStore) Close() error {
+	return h.reloader.Close()
+}
+
+// applyStrategies validates the content of the strategies and replaces the current strategies with them.
+func (h *strategyStore) applyStrategies(content []byte) error {
+	strategies, err := loadStrategies(content)
+	if err != nil {
+		return err
+	}
+	h.storedStrategies.Store(h.parseStrategies(strategies))
+	return nil
+}
+
+// reloadInterval returns the interval at which the strategies are reloaded, or 0 if they are not.
+// The strategies served at a URL are polled even if no reload interval is configured.
+func reloadInterval(options Options) time.Duration {
+	if options.StrategiesFile == "" {
+		return 0
+	}
+	if isURL(options.StrategiesFile) && options.ReloadInterval <= 0 {
+		return defaultURLReloadInterval
+	}
+	return options.ReloadInterval
 }
 
 // TODO good candidate for a global util function
-func loadStrategies(strategiesFile string) (*strategies, error) {
+func readStrategiesFile(strategiesFile string) ([]byte, error) {
 	if strategiesFile == "" {
 		return nil, nil
 	}
@@ -66,25 +146,74 @@ func loadStrategies(strategiesFile string) (*strategies, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to open strategies file")
 	}
+	return bytes, nil
+}
+
+func loadStrategies(content []byte) (*strategies, error) {
+	if content == nil {
+		return nil, nil
+	}
 	var strategies strategies
-	if err := json.Unmarshal(bytes, &strategies); err != nil {
+	if err := json.Unmarshal(content, &strategies); err != nil {
 		return nil, errors.Wrap(err, "Failed to unmarshal strategies")
 	}
+	if err := validateStrategies(&strategies); err != nil {
+		return nil, errors.Wrap(err, "Invalid strategies")
+	}
 	return &strategies, nil
 }
 
-func (h *strategyStore) parseStrategies(strategies *strategies) {
-	h.defaultStrategy = &defaultStrategy
+// validateStrategies checks that the params of all strategies are in range for their type.
+// Strategies of an unknown type are not rejected here, they fall back to the default strategy.
+func validateStrategies(strategies *strategies) error {
+	if strategies.DefaultStrategy != nil {
+		if err := validateStrategy(strategies.DefaultStrategy); err != nil {
+			return errors.Wrap(err, "default strategy")
+		}
+	}
+	for _, s := range strategies.ServiceStrategies {
+		if err := validateStrategy(&s.strategy); err != nil {
+			return errors.Wrapf(err, "service %s", s.Service)
+		}
+		for _, opS := range s.OperationStrategies {
+			if err := validateStrategy(&opS.strategy); err != nil {
+				return errors.Wrapf(err, "service %s, operation %s", s.Service, opS.Operation)
+			}
+		}
+	}
+	return nil
+}
+
+func validateStrategy(strategy *strategy) error {
+	switch strategy.Type {
+	case samplerTypeProbabilistic:
+		if strategy.Param < 0 || strategy.Param > 1 {
+			return fmt.Errorf("sampling probability %v must be between 0 and 1", strategy.Param)
+		}
+	case samplerTypeRateLimiting:
+		if strategy.Param < 0 || strategy.Param > math.MaxInt16 {
+			return fmt.Errorf("max traces per second %v must be between 0 and %d", strategy.Param, math.MaxInt16)
+		}
+	}
+	return nil
+}
+
+func (h *strategyStore) parseStrategies(strategies *strategies) *storedStrategies {
+	stored := &storedStrategies{
+		defaultStrategy:   &defaultStrategy,
+		serviceStrategies: make(map[string]*sampling.SamplingStrategyResponse),
+	}
 	if strategies == nil {
 		h.logger.Info("No sampling strategies provided, using defaults")
-		return
+		return stored
 	}
 	if strategies.DefaultStrategy != nil {
-		h.defaultStrategy = h.parseStrategy(strategies.DefaultStrategy)
+		stored.defaultStrategy = h.parseStrategy(strategies.DefaultStrategy)
 	}
 	for _, s := range strategies.ServiceStrategies {
-		h.serviceStrategies[s.Service] = h.parseServiceStrategies(s)
+		stored.serviceStrategies[s.Service] = h.parseServiceStrategies(s)
 	}
+	return stored
 }
 
 func (h *strategyStore) parseServiceStrategies(strategy *serviceStrategy) *sampling.SamplingStrategyResponse {
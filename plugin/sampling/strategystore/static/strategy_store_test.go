@@ -15,10 +15,16 @@
 package static
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/pkg/testutils"
@@ -26,16 +32,16 @@ import (
 )
 
 func TestStrategyStore(t *testing.T) {
-	_, err := NewStrategyStore(Options{StrategiesFile: "fileNotFound.json"}, zap.NewNop())
+	_, err := NewStrategyStore(Options{StrategiesFile: "fileNotFound.json"}, metrics.NullFactory, zap.NewNop())
 	assert.EqualError(t, err, "Failed to open strategies file: open fileNotFound.json: no such file or directory")
 
-	_, err = NewStrategyStore(Options{StrategiesFile: "fixtures/bad_strategies.json"}, zap.NewNop())
+	_, err = NewStrategyStore(Options{StrategiesFile: "fixtures/bad_strategies.json"}, metrics.NullFactory, zap.NewNop())
 	assert.EqualError(t, err,
 		"Failed to unmarshal strategies: json: cannot unmarshal string into Go value of type static.strategies")
 
 	// Test default strategy
 	logger, buf := testutils.NewLogger()
-	store, err := NewStrategyStore(Options{}, logger)
+	store, err := NewStrategyStore(Options{}, metrics.NullFactory, logger)
 	require.NoError(t, err)
 	assert.Contains(t, buf.String(), "No sampling strategies provided, using defaults")
 	s, err := store.GetSamplingStrategy("foo")
@@ -43,7 +49,7 @@ func TestStrategyStore(t *testing.T) {
 	assert.EqualValues(t, makeResponse(sampling.SamplingStrategyType_PROBABILISTIC, 0.001), *s)
 
 	// Test reading strategies from a file
-	store, err = NewStrategyStore(Options{StrategiesFile: "fixtures/strategies.json"}, logger)
+	store, err = NewStrategyStore(Options{StrategiesFile: "fixtures/strategies.json"}, metrics.NullFactory, logger)
 	require.NoError(t, err)
 	s, err = store.GetSamplingStrategy("foo")
 	require.NoError(t, err)
@@ -56,11 +62,15 @@ func TestStrategyStore(t *testing.T) {
 	s, err = store.GetSamplingStrategy("default")
 	require.NoError(t, err)
 	assert.EqualValues(t, makeResponse(sampling.SamplingStrategyType_PROBABILISTIC, 0.5), *s)
+
+	_, err = NewStrategyStore(Options{StrategiesFile: "fixtures/invalid_strategies.json"}, metrics.NullFactory, zap.NewNop())
+	assert.EqualError(t, err,
+		"Invalid strategies: service foo, operation op1: sampling probability 1.5 must be between 0 and 1")
 }
 
 func TestPerOperationSamplingStrategies(t *testing.T) {
 	logger, buf := testutils.NewLogger()
-	store, err := NewStrategyStore(Options{StrategiesFile: "fixtures/operation_strategies.json"}, logger)
+	store, err := NewStrategyStore(Options{StrategiesFile: "fixtures/operation_strategies.json"}, metrics.NullFactory, logger)
 	assert.Contains(t, buf.String(), "Operation strategies only supports probabilistic sampling at the moment,"+
 		"'op2' defaulting to probabilistic sampling with probability 0.8")
 	assert.Contains(t, buf.String(), "Operation strategies only supports probabilistic sampling at the moment,"+
@@ -104,7 +114,7 @@ func TestPerOperationSamplingStrategies(t *testing.T) {
 
 func TestMissingServiceSamplingStrategyTypes(t *testing.T) {
 	logger, buf := testutils.NewLogger()
-	store, err := NewStrategyStore(Options{StrategiesFile: "fixtures/missing-service-types.json"}, logger)
+	store, err := NewStrategyStore(Options{StrategiesFile: "fixtures/missing-service-types.json"}, metrics.NullFactory, logger)
 	assert.Contains(t, buf.String(), "Failed to parse sampling strategy")
 	require.NoError(t, err)
 
@@ -143,6 +153,113 @@ func TestMissingServiceSamplingStrategyTypes(t *testing.T) {
 	assert.EqualValues(t, makeResponse(sampling.SamplingStrategyType_PROBABILISTIC, 0.5), *s)
 }
 
+func TestValidateStrategies(t *testing.T) {
+	tests := []struct {
+		strategies strategies
+		err        string
+	}{
+		{
+			strategies: strategies{DefaultStrategy: &strategy{Type: "probabilistic", Param: 0.5}},
+		},
+		{
+			strategies: strategies{DefaultStrategy: &strategy{Type: "probabilistic", Param: -0.1}},
+			err:        "default strategy: sampling probability -0.1 must be between 0 and 1",
+		},
+		{
+			strategies: strategies{ServiceStrategies: []*serviceStrategy{
+				{Service: "foo", strategy: strategy{Type: "ratelimiting", Param: 100000}},
+			}},
+			err: "service foo: max traces per second 100000 must be between 0 and 32767",
+		},
+		{
+			strategies: strategies{ServiceStrategies: []*serviceStrategy{
+				{Service: "foo", strategy: strategy{Type: "blah", Param: 100000}},
+			}},
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.err, func(t *testing.T) {
+			err := validateStrategies(&tt.strategies)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestReloadStrategies(t *testing.T) {
+	dir, err := ioutil.TempDir("", "strategies")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "strategies.json")
+	writeStrategies := func(content string) {
+		// write to a temporary file and rename it, so that the store never observes a partial write
+		tmp := file + ".tmp"
+		require.NoError(t, ioutil.WriteFile(tmp, []byte(content), 0644))
+		require.NoError(t, os.Rename(tmp, file))
+	}
+	writeStrategies(`{"default_strategy": {"type": "probabilistic", "param": 0.5}}`)
+
+	metricsFactory := metricstest.NewFactory(0)
+	store, err := NewStrategyStore(Options{StrategiesFile: file, ReloadInterval: time.Hour}, metricsFactory, zap.NewNop())
+	require.NoError(t, err)
+	h := store.(*strategyStore)
+	defer h.Close()
+
+	s, err := store.GetSamplingStrategy("foo")
+	require.NoError(t, err)
+	assert.EqualValues(t, makeResponse(sampling.SamplingStrategyType_PROBABILISTIC, 0.5), *s)
+
+	writeStrategies(`{"default_strategy": {"type": "probabilistic", "param": 0.8}}`)
+	h.reloader.Reload()
+	s, err = store.GetSamplingStrategy("foo")
+	require.NoError(t, err)
+	assert.EqualValues(t, makeResponse(sampling.SamplingStrategyType_PROBABILISTIC, 0.8), *s)
+
+	// the last good strategies are kept when the new content is invalid
+	writeStrategies(`{"default_strategy": {"type": "probabilistic", "param": 2}}`)
+	h.reloader.Reload()
+	s, err = store.GetSamplingStrategy("foo")
+	require.NoError(t, err)
+	assert.EqualValues(t, makeResponse(sampling.SamplingStrategyType_PROBABILISTIC, 0.8), *s)
+
+	writeStrategies(`{"default_strategy": {"type": "ratelimiting", "param": 3}}`)
+	h.reloader.Reload()
+	s, err = store.GetSamplingStrategy("foo")
+	require.NoError(t, err)
+	assert.EqualValues(t, makeResponse(sampling.SamplingStrategyType_RATE_LIMITING, 3), *s)
+	metricsFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "sampling_strategies.reloads", Tags: map[string]string{"result": "ok"}, Value: 2},
+		metricstest.ExpectedMetric{Name: "sampling_strategies.reloads", Tags: map[string]string{"result": "err"}, Value: 1},
+	)
+}
+
+func TestReloadStrategiesMissingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "strategies")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "strategies.json")
+	require.NoError(t, ioutil.WriteFile(file, []byte(`{"default_strategy": {"type": "probabilistic", "param": 0.5}}`), 0644))
+
+	metricsFactory := metricstest.NewFactory(0)
+	store, err := NewStrategyStore(Options{StrategiesFile: file}, metricsFactory, zap.NewNop())
+	require.NoError(t, err)
+	h := store.(*strategyStore)
+	defer h.Close()
+
+	require.NoError(t, os.Remove(file))
+	h.reloader.Reload()
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{
+		Name: "sampling_strategies.fetch-errors", Value: 1,
+	})
+	s, err := store.GetSamplingStrategy("foo")
+	require.NoError(t, err)
+	assert.EqualValues(t, makeResponse(sampling.SamplingStrategyType_PROBABILISTIC, 0.5), *s)
+}
+
 func TestParseStrategy(t *testing.T) {
 	tests := []struct {
 		strategy serviceStrategy
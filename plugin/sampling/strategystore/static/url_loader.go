@@ -0,0 +1,93 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package static
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultFetchTimeout is the timeout for fetching the strategies from a URL.
+	defaultFetchTimeout = 10 * time.Second
+
+	// defaultURLReloadInterval is the interval at which the strategies are fetched again from a URL
+	// when no reload interval is configured.
+	defaultURLReloadInterval = time.Minute
+)
+
+func isURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// urlLoader fetches the strategies from a URL. It remembers the ETag and Last-Modified
+// headers of the last response and sends them back as conditional request headers, so that
+// the server does not need to send the strategies again if they have not changed.
+// It is not safe for concurrent use.
+type urlLoader struct {
+	url    string
+	client *http.Client
+
+	etag         string
+	lastModified string
+	lastContent  []byte
+}
+
+func newURLLoader(url string, timeout time.Duration) *urlLoader {
+	return &urlLoader{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// load returns the content served at the URL, or the previously fetched content
+// if the server reports that it has not been modified.
+func (l *urlLoader) load() ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, l.url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create strategies request")
+	}
+	if l.etag != "" {
+		req.Header.Set("If-None-Match", l.etag)
+	}
+	if l.lastModified != "" {
+		req.Header.Set("If-Modified-Since", l.lastModified)
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to fetch strategies")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && l.lastContent != nil {
+		return l.lastContent, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Failed to fetch strategies: unexpected status code %d", resp.StatusCode)
+	}
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read strategies response")
+	}
+	l.etag = resp.Header.Get("ETag")
+	l.lastModified = resp.Header.Get("Last-Modified")
+	l.lastContent = content
+	return content, nil
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package static
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/thrift-gen/sampling"
+)
+
+const (
+	testETag         = `"v1"`
+	testLastModified = "Wed, 21 Oct 2015 07:28:00 GMT"
+)
+
+// strategiesServer serves strategies with an ETag and Last-Modified headers,
+// replying 304 Not Modified to conditional requests for the current version.
+type strategiesServer struct {
+	sync.Mutex
+	content    string
+	etag       string
+	statusCode int
+	requests   []*http.Request
+}
+
+func (s *strategiesServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+	s.requests = append(s.requests, r)
+	if s.statusCode != 0 {
+		w.WriteHeader(s.statusCode)
+		return
+	}
+	if r.Header.Get("If-None-Match") == s.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", s.etag)
+	w.Header().Set("Last-Modified", testLastModified)
+	w.Write([]byte(s.content))
+}
+
+func (s *strategiesServer) update(content, etag string, statusCode int) {
+	s.Lock()
+	defer s.Unlock()
+	s.content, s.etag, s.statusCode = content, etag, statusCode
+}
+
+func (s *strategiesServer) lastRequest() *http.Request {
+	s.Lock()
+	defer s.Unlock()
+	return s.requests[len(s.requests)-1]
+}
+
+func TestIsURL(t *testing.T) {
+	assert.True(t, isURL("http://localhost/strategies.json"))
+	assert.True(t, isURL("https://localhost/strategies.json"))
+	assert.False(t, isURL("fixtures/strategies.json"))
+	assert.False(t, isURL(""))
+}
+
+func TestReloadInterval(t *testing.T) {
+	assert.Equal(t, time.Duration(0), reloadInterval(Options{}))
+	assert.Equal(t, time.Duration(0), reloadInterval(Options{StrategiesFile: "fixtures/strategies.json"}))
+	assert.Equal(t, time.Second, reloadInterval(Options{StrategiesFile: "fixtures/strategies.json", ReloadInterval: time.Second}))
+	assert.Equal(t, defaultURLReloadInterval, reloadInterval(Options{StrategiesFile: "http://localhost/strategies.json"}))
+	assert.Equal(t, time.Second, reloadInterval(Options{StrategiesFile: "http://localhost/strategies.json", ReloadInterval: time.Second}))
+}
+
+func TestURLLoader(t *testing.T) {
+	handler := &strategiesServer{content: `{"default_strategy": {"type": "probabilistic", "param": 0.5}}`, etag: testETag}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	l := newURLLoader(server.URL, time.Second)
+	content, err := l.load()
+	require.NoError(t, err)
+	assert.Equal(t, handler.content, string(content))
+	assert.Empty(t, handler.lastRequest().Header.Get("If-None-Match"))
+
+	content, err = l.load()
+	require.NoError(t, err)
+	assert.Equal(t, handler.content, string(content))
+	assert.Equal(t, testETag, handler.lastRequest().Header.Get("If-None-Match"))
+	assert.Equal(t, testLastModified, handler.lastRequest().Header.Get("If-Modified-Since"))
+
+	handler.update(`{"default_strategy": {"type": "probabilistic", "param": 0.8}}`, `"v2"`, 0)
+	content, err = l.load()
+	require.NoError(t, err)
+	assert.Equal(t, handler.content, string(content))
+	assert.Equal(t, `"v2"`, l.etag)
+
+	handler.update("", "", http.StatusInternalServerError)
+	_, err = l.load()
+	assert.EqualError(t, err, "Failed to fetch strategies: unexpected status code 500")
+}
+
+func TestURLLoaderErrors(t *testing.T) {
+	_, err := newURLLoader("http://localhost:1/strategies.json", time.Second).load()
+	assert.Contains(t, err.Error(), "Failed to fetch strategies")
+
+	_, err = newURLLoader("http://%zz", time.Second).load()
+	assert.Contains(t, err.Error(), "Failed to create strategies request")
+}
+
+func TestStrategyStoreFromURL(t *testing.T) {
+	handler := &strategiesServer{content: `{"default_strategy": {"type": "probabilistic", "param": 0.5}}`, etag: testETag}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	metricsFactory := metricstest.NewFactory(0)
+	store, err := NewStrategyStore(Options{StrategiesFile: server.URL, ReloadInterval: time.Hour}, metricsFactory, zap.NewNop())
+	require.NoError(t, err)
+	h := store.(*strategyStore)
+	defer h.Close()
+
+	s, err := store.GetSamplingStrategy("foo")
+	require.NoError(t, err)
+	assert.EqualValues(t, makeResponse(sampling.SamplingStrategyType_PROBABILISTIC, 0.5), *s)
+
+	// the last good strategies are kept when the server fails
+	handler.update("", "", http.StatusServiceUnavailable)
+	h.reloader.Reload()
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "sampling_strategies.fetch-errors", Value: 1})
+	s, err = store.GetSamplingStrategy("foo")
+	require.NoError(t, err)
+	assert.EqualValues(t, makeResponse(sampling.SamplingStrategyType_PROBABILISTIC, 0.5), *s)
+
+	handler.update(`{"default_strategy": {"type": "ratelimiting", "param": 7}}`, `"v2"`, 0)
+	h.reloader.Reload()
+	s, err = store.GetSamplingStrategy("foo")
+	require.NoError(t, err)
+	assert.EqualValues(t, makeResponse(sampling.SamplingStrategyType_RATE_LIMITING, 7), *s)
+}
+
+func TestStrategyStoreFromURLError(t *testing.T) {
+	handler := &strategiesServer{statusCode: http.StatusNotFound}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	_, err := NewStrategyStore(Options{StrategiesFile: server.URL}, metricstest.NewFactory(0), zap.NewNop())
+	assert.EqualError(t, err, "Failed to fetch strategies: unexpected status code 404")
+}
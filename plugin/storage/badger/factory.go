@@ -18,6 +18,8 @@ import (
 	"flag"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/dgraph-io/badger"
@@ -25,9 +27,15 @@ import (
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/pkg/distributedlock"
+	"github.com/jaegertracing/jaeger/pkg/retention"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	badgerLock "github.com/jaegertracing/jaeger/plugin/pkg/distributedlock/badger"
 	depStore "github.com/jaegertracing/jaeger/plugin/storage/badger/dependencystore"
+	badgerSamplingStore "github.com/jaegertracing/jaeger/plugin/storage/badger/samplingstore"
 	badgerStore "github.com/jaegertracing/jaeger/plugin/storage/badger/spanstore"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/samplingstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -36,6 +44,9 @@ const (
 	keyLogSpaceAvailableName   = "badger_key_log_bytes_available"
 	lastMaintenanceRunName     = "badger_storage_maintenance_last_run"
 	lastValueLogCleanedName    = "badger_storage_valueloggc_last_run"
+
+	// tenantsDirectory is the subdirectory of the key and value directories holding the tenant databases
+	tenantsDirectory = "tenants"
 )
 
 // Factory implements storage.Factory for Badger backend.
@@ -45,8 +56,19 @@ type Factory struct {
 	cache   *badgerStore.CacheStore
 	logger  *zap.Logger
 
+	// retentionPolicy overrides the span store TTL for the services it matches
+	retentionPolicy *retention.Policy
+
 	tmpDir          string
 	maintenanceDone chan bool
+	// closeOnce closes the storage once, both the span writers and the owner of the factory close it
+	closeOnce sync.Once
+	closeErr  error
+
+	// badgerOptions are the options the primary database was opened with, reused for the tenant databases
+	badgerOptions badger.Options
+	tenantsLock   sync.Mutex
+	tenants       map[string]*tenantStore
 
 	// TODO initialize via reflection; convert comments to tag 'description'.
 	metrics struct {
@@ -61,6 +83,13 @@ type Factory struct {
 	}
 }
 
+// tenantStore holds the database of a tenant, each tenant has its own database
+// so that the data of tenants is kept apart.
+type tenantStore struct {
+	store *badger.DB
+	cache *badgerStore.CacheStore
+}
+
 // NewFactory creates a new Factory.
 func NewFactory() *Factory {
 	return &Factory{
@@ -83,6 +112,12 @@ func (f *Factory) InitFromViper(v *viper.Viper) {
 func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger) error {
 	f.logger = logger
 
+	retentionPolicy, err := retention.LoadPolicyFile(f.Options.primary.RetentionPolicyFile)
+	if err != nil {
+		return err
+	}
+	f.retentionPolicy = retentionPolicy
+
 	opts := badger.DefaultOptions
 
 	if f.Options.primary.Ephemeral {
@@ -106,6 +141,8 @@ func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger)
 		return err
 	}
 	f.store = store
+	f.badgerOptions = opts
+	f.tenants = make(map[string]*tenantStore)
 
 	f.cache = badgerStore.NewCacheStore(f.store, f.Options.primary.SpanStoreTTL, true)
 
@@ -128,7 +165,12 @@ func (f *Factory) CreateSpanReader() (spanstore.Reader, error) {
 
 // CreateSpanWriter implements storage.Factory
 func (f *Factory) CreateSpanWriter() (spanstore.Writer, error) {
-	return badgerStore.NewSpanWriter(f.store, f.cache, f.Options.primary.SpanStoreTTL, f), nil
+	return badgerStore.NewSpanWriter(f.store, f.cache, f.Options.primary.SpanStoreTTL, f.retentionPolicy, f), nil
+}
+
+// CreateSpanDeleter implements storage.DeleterFactory
+func (f *Factory) CreateSpanDeleter() (spanstore.Deleter, error) {
+	return badgerStore.NewSpanDeleter(f.store, badgerStore.NewTraceReader(f.store, f.cache)), nil
 }
 
 // CreateDependencyReader implements storage.Factory
@@ -137,11 +179,116 @@ func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
 	return depStore.NewDependencyStore(sr), nil
 }
 
-// Close Implements io.Closer and closes the underlying storage
+// CreateLock implements storage.SamplingStoreFactory
+func (f *Factory) CreateLock() (distributedlock.Lock, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	return badgerLock.NewLock(f.store, hostname), nil
+}
+
+// CreateSamplingStore implements storage.SamplingStoreFactory
+func (f *Factory) CreateSamplingStore() (samplingstore.Store, error) {
+	return badgerSamplingStore.NewSamplingStore(f.store, f.Options.primary.SpanStoreTTL), nil
+}
+
+// CreateTenantSpanReader implements storage.TenantFactory
+func (f *Factory) CreateTenantSpanReader(tenant string) (spanstore.Reader, error) {
+	ts, err := f.tenantStore(tenant)
+	if err != nil {
+		return nil, err
+	}
+	return badgerStore.NewTraceReader(ts.store, ts.cache), nil
+}
+
+// CreateTenantSpanWriter implements storage.TenantFactory
+func (f *Factory) CreateTenantSpanWriter(tenant string) (spanstore.Writer, error) {
+	ts, err := f.tenantStore(tenant)
+	if err != nil {
+		return nil, err
+	}
+	return badgerStore.NewSpanWriter(ts.store, ts.cache, f.Options.primary.SpanStoreTTL, f.retentionPolicy, f), nil
+}
+
+// CreateTenantDependencyReader implements storage.TenantFactory
+func (f *Factory) CreateTenantDependencyReader(tenant string) (dependencystore.Reader, error) {
+	sr, err := f.CreateTenantSpanReader(tenant)
+	if err != nil {
+		return nil, err
+	}
+	return depStore.NewDependencyStore(sr), nil
+}
+
+// CreateTenantSpanDeleter implements storage.TenantFactory
+func (f *Factory) CreateTenantSpanDeleter(tenant string) (spanstore.Deleter, error) {
+	ts, err := f.tenantStore(tenant)
+	if err != nil {
+		return nil, err
+	}
+	return badgerStore.NewSpanDeleter(ts.store, badgerStore.NewTraceReader(ts.store, ts.cache)), nil
+}
+
+// tenantStore returns the database of the tenant, opening it in the tenant subdirectory
+// of the key and value directories if needed.
+func (f *Factory) tenantStore(tenant string) (*tenantStore, error) {
+	if err := tenancy.ValidateName(tenant); err != nil {
+		return nil, err
+	}
+	f.tenantsLock.Lock()
+	defer f.tenantsLock.Unlock()
+	if ts, ok := f.tenants[tenant]; ok {
+		return ts, nil
+	}
+	opts := f.badgerOptions
+	opts.Dir = filepath.Join(opts.Dir, tenantsDirectory, tenant)
+	opts.ValueDir = filepath.Join(opts.ValueDir, tenantsDirectory, tenant)
+	for _, dir := range []string{opts.Dir, opts.ValueDir} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, err
+		}
+	}
+	store, err := badger.Open(opts)
+	if err != nil {
+		return nil, err
+	}
+	ts := &tenantStore{
+		store: store,
+		cache: badgerStore.NewCacheStore(store, f.Options.primary.SpanStoreTTL, true),
+	}
+	f.tenants[tenant] = ts
+	return ts, nil
+}
+
+// stores returns the primary database and the databases of all tenants.
+func (f *Factory) stores() []*badger.DB {
+	f.tenantsLock.Lock()
+	defer f.tenantsLock.Unlock()
+	stores := make([]*badger.DB, 0, len(f.tenants)+1)
+	stores = append(stores, f.store)
+	for _, ts := range f.tenants {
+		stores = append(stores, ts.store)
+	}
+	return stores
+}
+
+// Close Implements io.Closer and closes the underlying storage, the calls after the first one do nothing
 func (f *Factory) Close() error {
+	f.closeOnce.Do(func() {
+		f.closeErr = f.close()
+	})
+	return f.closeErr
+}
+
+func (f *Factory) close() error {
 	f.maintenanceDone <- true
 
-	err := f.store.Close()
+	var err error
+	for _, store := range f.stores() {
+		if errClose := store.Close(); err == nil {
+			err = errClose
+		}
+	}
 
 	// Remove tmp files if this was ephemeral storage
 	if f.Options.primary.Ephemeral {
@@ -163,15 +310,20 @@ func (f *Factory) maintenance() {
 		case <-f.maintenanceDone:
 			return
 		case t := <-maintenanceTicker.C:
-			var err error
-			// After there's nothing to clean, the err is raised
-			for err == nil {
-				err = f.store.RunValueLogGC(0.5) // 0.5 is selected to rewrite a file if half of it can be discarded
+			cleaned := true
+			for _, store := range f.stores() {
+				var err error
+				// After there's nothing to clean, the err is raised
+				for err == nil {
+					err = store.RunValueLogGC(0.5) // 0.5 is selected to rewrite a file if half of it can be discarded
+				}
+				if err != badger.ErrNoRewrite {
+					f.logger.Error("Failed to run ValueLogGC", zap.Error(err))
+					cleaned = false
+				}
 			}
-			if err == badger.ErrNoRewrite {
+			if cleaned {
 				f.metrics.LastValueLogCleaned.Update(t.UnixNano())
-			} else {
-				f.logger.Error("Failed to run ValueLogGC", zap.Error(err))
 			}
 
 			f.metrics.LastMaintenanceRun.Update(t.UnixNano())
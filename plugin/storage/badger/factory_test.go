@@ -15,6 +15,7 @@
 package badger
 
 import (
+	"context"
 	"expvar"
 	"fmt"
 	"io"
@@ -27,9 +28,14 @@ import (
 	"github.com/uber/jaeger-lib/metrics/metricstest"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/storage"
 )
 
+var _ storage.SamplingStoreFactory = new(Factory)
+var _ storage.TenantFactory = new(Factory)
+
 func TestInitializationErrors(t *testing.T) {
 	f := NewFactory()
 	v, command := config.Viperize(f.AddFlags)
@@ -49,6 +55,17 @@ func TestInitializationErrors(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestInitializationInvalidRetentionPolicy(t *testing.T) {
+	f := NewFactory()
+	v, command := config.Viperize(f.AddFlags)
+	command.ParseFlags([]string{"--badger.retention-policy-file=/not/a/file"})
+	f.InitFromViper(v)
+
+	err := f.Initialize(metrics.NullFactory, zap.NewNop())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Failed to open retention policy file")
+}
+
 func TestForCodecov(t *testing.T) {
 	// These tests are testing our vendor packages and are intended to satisfy Codecov.
 	f := NewFactory()
@@ -65,9 +82,18 @@ func TestForCodecov(t *testing.T) {
 	_, err = f.CreateSpanWriter()
 	assert.NoError(t, err)
 
+	_, err = f.CreateSpanDeleter()
+	assert.NoError(t, err)
+
 	_, err = f.CreateDependencyReader()
 	assert.NoError(t, err)
 
+	_, err = f.CreateLock()
+	assert.NoError(t, err)
+
+	_, err = f.CreateSamplingStore()
+	assert.NoError(t, err)
+
 	// Now, remove the badger directories
 	err = os.RemoveAll(f.tmpDir)
 	assert.NoError(t, err)
@@ -77,6 +103,57 @@ func TestForCodecov(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestTenantStores(t *testing.T) {
+	f := NewFactory()
+	v, _ := config.Viperize(f.AddFlags)
+	f.InitFromViper(v)
+	assert.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+	defer f.Close()
+
+	writer, err := f.CreateTenantSpanWriter("acme")
+	assert.NoError(t, err)
+	span := &model.Span{
+		TraceID:       model.NewTraceID(1, 2),
+		SpanID:        model.NewSpanID(3),
+		OperationName: "operation",
+		StartTime:     time.Now(),
+		Process:       model.NewProcess("service", nil),
+	}
+	assert.NoError(t, writer.WriteSpan(span))
+
+	reader, err := f.CreateTenantSpanReader("acme")
+	assert.NoError(t, err)
+	trace, err := reader.GetTrace(context.Background(), span.TraceID)
+	assert.NoError(t, err)
+	assert.Len(t, trace.Spans, 1)
+
+	otherReader, err := f.CreateTenantSpanReader("globex")
+	assert.NoError(t, err)
+	trace, err = otherReader.GetTrace(context.Background(), span.TraceID)
+	assert.NoError(t, err)
+	assert.Nil(t, trace)
+	primaryReader, err := f.CreateSpanReader()
+	assert.NoError(t, err)
+	trace, err = primaryReader.GetTrace(context.Background(), span.TraceID)
+	assert.NoError(t, err)
+	assert.Nil(t, trace)
+
+	_, err = f.CreateTenantDependencyReader("acme")
+	assert.NoError(t, err)
+	_, err = f.CreateTenantSpanDeleter("acme")
+	assert.NoError(t, err)
+	assert.Len(t, f.stores(), 3)
+
+	_, err = f.CreateTenantSpanReader("../acme")
+	assert.Error(t, err)
+	_, err = f.CreateTenantSpanWriter("")
+	assert.Error(t, err)
+	_, err = f.CreateTenantDependencyReader("Acme")
+	assert.Error(t, err)
+	_, err = f.CreateTenantSpanDeleter("Acme")
+	assert.Error(t, err)
+}
+
 func TestMaintenanceRun(t *testing.T) {
 	// For Codecov - this does not test anything
 	f := NewFactory()
@@ -119,6 +196,11 @@ func TestMaintenanceRun(t *testing.T) {
 
 	err := io.Closer(f).Close()
 	assert.NoError(t, err)
+
+	// the span writers close the factory too, only the first call closes the storage
+	sw, err := f.CreateSpanWriter()
+	assert.NoError(t, err)
+	assert.NoError(t, sw.(io.Closer).Close())
 }
 
 // TestMaintenanceCodecov this test is not intended to test anything, but hopefully increase coverage by triggering a log line
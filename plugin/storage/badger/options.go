@@ -21,6 +21,8 @@ import (
 	"time"
 
 	"github.com/spf13/viper"
+
+	"github.com/jaegertracing/jaeger/pkg/retention"
 )
 
 // Options store storage plugin related configs
@@ -38,6 +40,7 @@ type NamespaceConfig struct {
 	Ephemeral           bool // Setting this to true will ignore ValueDirectory and KeyDirectory
 	SyncWrites          bool
 	MaintenanceInterval time.Duration
+	RetentionPolicyFile string
 }
 
 const (
@@ -118,6 +121,7 @@ func addFlags(flagSet *flag.FlagSet, nsConfig *NamespaceConfig) {
 		nsConfig.MaintenanceInterval,
 		"How often the maintenance thread for values is ran. Format is time.Duration (https://golang.org/pkg/time/#Duration)",
 	)
+	retention.AddFlags(flagSet, nsConfig.namespace)
 }
 
 // InitFromViper initializes Options with properties from viper
@@ -132,6 +136,7 @@ func initFromViper(cfg *NamespaceConfig, v *viper.Viper) {
 	cfg.SyncWrites = v.GetBool(cfg.namespace + suffixSyncWrite)
 	cfg.SpanStoreTTL = v.GetDuration(cfg.namespace + suffixSpanstoreTTL)
 	cfg.MaintenanceInterval = v.GetDuration(cfg.namespace + suffixMaintenanceInterval)
+	cfg.RetentionPolicyFile = retention.PolicyFileFromViper(v, cfg.namespace)
 }
 
 // GetPrimary returns the primary namespace configuration
@@ -43,6 +43,7 @@ func TestParseOptions(t *testing.T) {
 		"--badger.directory-key=/var/lib/badger",
 		"--badger.directory-value=/mnt/slow/badger",
 		"--badger.span-store-ttl=168h",
+		"--badger.retention-policy-file=/etc/jaeger/retention.yaml",
 	})
 	opts.InitFromViper(v)
 
@@ -51,4 +52,5 @@ func TestParseOptions(t *testing.T) {
 	assert.Equal(t, time.Duration(168*time.Hour), opts.GetPrimary().SpanStoreTTL)
 	assert.Equal(t, "/var/lib/badger", opts.GetPrimary().KeyDirectory)
 	assert.Equal(t, "/mnt/slow/badger", opts.GetPrimary().ValueDirectory)
+	assert.Equal(t, "/etc/jaeger/retention.yaml", opts.GetPrimary().RetentionPolicyFile)
 }
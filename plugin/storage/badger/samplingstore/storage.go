@@ -0,0 +1,211 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package samplingstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	"github.com/dgraph-io/badger"
+	"github.com/pkg/errors"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/model"
+)
+
+const (
+	// Sampling keys have the first bit unset to never overlap with span keys, see spanstore/writer.go
+	throughputKeyPrefix    byte = 0x08
+	probabilitiesKeyPrefix byte = 0x09
+
+	sizeOfTimestamp = 8 // timestamps are stored as big endian uint64 nanoseconds
+)
+
+type probabilitiesAndQPS struct {
+	Hostname      string
+	Probabilities model.ServiceOperationProbabilities
+	QPS           model.ServiceOperationQPS
+}
+
+// SamplingStore handles all insertions and queries for sampling data to and from Badger
+type SamplingStore struct {
+	store *badger.DB
+	ttl   time.Duration
+}
+
+// NewSamplingStore creates a new Badger sampling store. Entries expire after the given ttl.
+func NewSamplingStore(db *badger.DB, ttl time.Duration) *SamplingStore {
+	return &SamplingStore{
+		store: db,
+		ttl:   ttl,
+	}
+}
+
+// InsertThroughput implements samplingstore.Store#InsertThroughput.
+func (s *SamplingStore) InsertThroughput(throughput []*model.Throughput) error {
+	val, err := json.Marshal(throughput)
+	if err != nil {
+		return err
+	}
+	return s.insert(createKey(throughputKeyPrefix, time.Now(), nil), val)
+}
+
+// InsertProbabilitiesAndQPS implements samplingstore.Store#InsertProbabilitiesAndQPS.
+func (s *SamplingStore) InsertProbabilitiesAndQPS(
+	hostname string,
+	probabilities model.ServiceOperationProbabilities,
+	qps model.ServiceOperationQPS,
+) error {
+	val, err := json.Marshal(&probabilitiesAndQPS{
+		Hostname:      hostname,
+		Probabilities: probabilities,
+		QPS:           qps,
+	})
+	if err != nil {
+		return err
+	}
+	return s.insert(createKey(probabilitiesKeyPrefix, time.Now(), []byte(hostname)), val)
+}
+
+// GetThroughput implements samplingstore.Store#GetThroughput.
+func (s *SamplingStore) GetThroughput(start, end time.Time) ([]*model.Throughput, error) {
+	var result []*model.Throughput
+	err := s.scan(throughputKeyPrefix, start, end, func(val []byte) error {
+		var throughput []*model.Throughput
+		if err := json.Unmarshal(val, &throughput); err != nil {
+			return err
+		}
+		result = append(result, throughput...)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading throughput from storage")
+	}
+	return result, nil
+}
+
+// GetProbabilitiesAndQPS implements samplingstore.Store#GetProbabilitiesAndQPS.
+func (s *SamplingStore) GetProbabilitiesAndQPS(start, end time.Time) (map[string][]model.ServiceOperationData, error) {
+	hostProbabilitiesAndQPS := make(map[string][]model.ServiceOperationData)
+	err := s.scan(probabilitiesKeyPrefix, start, end, func(val []byte) error {
+		var record probabilitiesAndQPS
+		if err := json.Unmarshal(val, &record); err != nil {
+			return err
+		}
+		hostProbabilitiesAndQPS[record.Hostname] = append(
+			hostProbabilitiesAndQPS[record.Hostname],
+			combineProbabilitiesAndQPS(record.Probabilities, record.QPS),
+		)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading probabilities and qps from storage")
+	}
+	return hostProbabilitiesAndQPS, nil
+}
+
+// GetLatestProbabilities implements samplingstore.Store#GetLatestProbabilities.
+func (s *SamplingStore) GetLatestProbabilities() (model.ServiceOperationProbabilities, error) {
+	var record probabilitiesAndQPS
+	err := s.store.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Reverse = true
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		prefix := []byte{probabilitiesKeyPrefix}
+		// In reverse mode Seek finds the largest key smaller than or equal to the given one
+		it.Seek([]byte{probabilitiesKeyPrefix + 1})
+		if !it.ValidForPrefix(prefix) {
+			return nil
+		}
+		val, err := it.Item().ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(val, &record)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading probabilities from storage")
+	}
+	if record.Probabilities == nil {
+		return model.ServiceOperationProbabilities{}, nil
+	}
+	return record.Probabilities, nil
+}
+
+func (s *SamplingStore) insert(key, val []byte) error {
+	return s.store.Update(func(txn *badger.Txn) error {
+		return txn.SetEntry(&badger.Entry{
+			Key:       key,
+			Value:     val,
+			ExpiresAt: uint64(time.Now().Add(s.ttl).Unix()),
+		})
+	})
+}
+
+// scan calls fn with the value of every entry under the prefix with a timestamp within (start, end].
+func (s *SamplingStore) scan(prefix byte, start, end time.Time, fn func(val []byte) error) error {
+	return s.store.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		prefixKey := []byte{prefix}
+		val := []byte{}
+		for it.Seek(createKey(prefix, start.Add(time.Nanosecond), nil)); it.ValidForPrefix(prefixKey); it.Next() {
+			item := it.Item()
+			ts := binary.BigEndian.Uint64(item.Key()[1 : 1+sizeOfTimestamp])
+			if ts > uint64(end.UnixNano()) {
+				break
+			}
+			var err error
+			val, err = item.ValueCopy(val)
+			if err != nil {
+				return err
+			}
+			if err := fn(val); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// createKey creates a key of the form <prefix><timestamp><suffix>, so that entries are sorted by time.
+func createKey(prefix byte, ts time.Time, suffix []byte) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(prefix)
+	binary.Write(buf, binary.BigEndian, uint64(ts.UnixNano()))
+	buf.Write(suffix)
+	return buf.Bytes()
+}
+
+func combineProbabilitiesAndQPS(
+	probabilities model.ServiceOperationProbabilities,
+	qps model.ServiceOperationQPS,
+) model.ServiceOperationData {
+	probabilitiesAndQPS := make(model.ServiceOperationData)
+	for svc, opProbabilities := range probabilities {
+		probabilitiesAndQPS[svc] = make(map[string]*model.ProbabilityAndQPS)
+		for op, probability := range opProbabilities {
+			probabilitiesAndQPS[svc][op] = &model.ProbabilityAndQPS{
+				Probability: probability,
+				QPS:         qps[svc][op],
+			}
+		}
+	}
+	return probabilitiesAndQPS
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package samplingstore
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/model"
+)
+
+func TestThroughput(t *testing.T) {
+	runWithBadger(t, func(store *badger.DB, t *testing.T) {
+		s := NewSamplingStore(store, time.Hour)
+		start := time.Now().Add(-time.Minute)
+
+		throughput := []*model.Throughput{
+			{Service: "svc", Operation: "op", Count: 10, Probabilities: map[string]struct{}{"0.1": {}}},
+		}
+		require.NoError(t, s.InsertThroughput(throughput))
+		require.NoError(t, s.InsertThroughput([]*model.Throughput{
+			{Service: "svc", Operation: "op2", Count: 1, Probabilities: map[string]struct{}{}},
+		}))
+
+		actual, err := s.GetThroughput(start, time.Now())
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+		assert.Equal(t, throughput[0], actual[0])
+		assert.Equal(t, "op2", actual[1].Operation)
+
+		actual, err = s.GetThroughput(start.Add(-time.Hour), start)
+		require.NoError(t, err)
+		assert.Empty(t, actual)
+	})
+}
+
+func TestProbabilitiesAndQPS(t *testing.T) {
+	runWithBadger(t, func(store *badger.DB, t *testing.T) {
+		s := NewSamplingStore(store, time.Hour)
+
+		latest, err := s.GetLatestProbabilities()
+		require.NoError(t, err)
+		assert.Empty(t, latest)
+
+		start := time.Now().Add(-time.Minute)
+		probabilities := model.ServiceOperationProbabilities{"svc": {"op": 0.5}}
+		qps := model.ServiceOperationQPS{"svc": {"op": 2}}
+		require.NoError(t, s.InsertProbabilitiesAndQPS("host-a", probabilities, qps))
+		newProbabilities := model.ServiceOperationProbabilities{"svc": {"op": 0.2}, "other": {"op": 1}}
+		require.NoError(t, s.InsertProbabilitiesAndQPS("host-b", newProbabilities, qps))
+
+		latest, err = s.GetLatestProbabilities()
+		require.NoError(t, err)
+		assert.Equal(t, newProbabilities, latest)
+
+		actual, err := s.GetProbabilitiesAndQPS(start, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]model.ServiceOperationData{
+			"host-a": {{"svc": {"op": {Probability: 0.5, QPS: 2}}}},
+			"host-b": {{"svc": {"op": {Probability: 0.2, QPS: 2}}, "other": {"op": {Probability: 1, QPS: 0}}}},
+		}, actual)
+	})
+}
+
+func TestExpiredEntries(t *testing.T) {
+	runWithBadger(t, func(store *badger.DB, t *testing.T) {
+		s := NewSamplingStore(store, -time.Hour)
+		start := time.Now().Add(-time.Minute)
+		require.NoError(t, s.InsertThroughput([]*model.Throughput{{Service: "svc", Operation: "op"}}))
+		require.NoError(t, s.InsertProbabilitiesAndQPS("host-a", model.ServiceOperationProbabilities{"svc": {"op": 1}}, nil))
+
+		throughput, err := s.GetThroughput(start, time.Now())
+		require.NoError(t, err)
+		assert.Empty(t, throughput)
+
+		latest, err := s.GetLatestProbabilities()
+		require.NoError(t, err)
+		assert.Empty(t, latest)
+	})
+}
+
+func runWithBadger(t *testing.T, test func(store *badger.DB, t *testing.T)) {
+	opts := badger.DefaultOptions
+
+	opts.SyncWrites = false
+	dir, _ := ioutil.TempDir("", "badger")
+	opts.Dir = dir
+	opts.ValueDir = dir
+
+	store, err := badger.Open(opts)
+	defer func() {
+		store.Close()
+		os.RemoveAll(dir)
+	}()
+
+	require.NoError(t, err)
+
+	test(store, t)
+}
@@ -115,8 +115,13 @@ func (c *CacheStore) loadOperations(service string) {
 
 // Update caches the results of service and service + operation indexes and maintains their TTL
 func (c *CacheStore) Update(service string, operation string) {
+	c.UpdateWithTTL(service, operation, c.ttl)
+}
+
+// UpdateWithTTL is Update with the TTL of the spans of the service instead of the TTL of the store
+func (c *CacheStore) UpdateWithTTL(service string, operation string, ttl time.Duration) {
 	c.cacheLock.Lock()
-	t := time.Now().Add(c.ttl).Unix()
+	t := time.Now().Add(ttl).Unix()
 
 	c.services[service] = t
 	if _, ok := c.operations[service]; !ok {
@@ -0,0 +1,100 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/dgraph-io/badger"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// deleteBatchSize is the number of keys deleted per transaction, badger limits the size of transactions
+const deleteBatchSize = 1000
+
+// SpanDeleter deletes the spans of traces and their index keys from badger
+type SpanDeleter struct {
+	store  *badger.DB
+	reader *TraceReader
+}
+
+// NewSpanDeleter returns a SpanDeleter finding the traces of services with the reader
+func NewSpanDeleter(db *badger.DB, reader *TraceReader) *SpanDeleter {
+	return &SpanDeleter{
+		store:  db,
+		reader: reader,
+	}
+}
+
+// DeleteTrace implements spanstore.Deleter
+func (d *SpanDeleter) DeleteTrace(ctx context.Context, traceID model.TraceID) error {
+	keys, err := d.traceKeys(traceID)
+	if err != nil {
+		return err
+	}
+	for len(keys) > 0 {
+		batch := keys
+		if len(batch) > deleteBatchSize {
+			batch = batch[:deleteBatchSize]
+		}
+		err := d.store.Update(func(txn *badger.Txn) error {
+			for _, key := range batch {
+				if err := txn.Delete(key); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		keys = keys[len(batch):]
+	}
+	return nil
+}
+
+// traceKeys returns the keys of the spans of the trace and of their index entries
+func (d *SpanDeleter) traceKeys(traceID model.TraceID) ([][]byte, error) {
+	var keys [][]byte
+	prefix := createPrimaryKeySeekPrefix(traceID)
+	err := d.store.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			item := it.Item()
+			val, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			span, err := decodeValue(val, item.UserMeta()&encodingTypeBits)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, item.KeyCopy(nil))
+			keys = append(keys, createIndexKeys(span)...)
+		}
+		return nil
+	})
+	return keys, err
+}
+
+// DeleteServiceTraces implements spanstore.Deleter
+func (d *SpanDeleter) DeleteServiceTraces(ctx context.Context, service string, startTimeMin, startTimeMax time.Time) ([]model.TraceID, error) {
+	return spanstore.DeleteFoundTraces(ctx, d.reader, service, startTimeMin, startTimeMax, d.DeleteTrace)
+}
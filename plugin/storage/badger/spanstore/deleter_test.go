@@ -0,0 +1,140 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+func countKeys(t *testing.T, store *badger.DB) int {
+	keys := 0
+	err := store.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			keys++
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return keys
+}
+
+func writeDeleterTestTrace(t *testing.T, sw *SpanWriter, traceID model.TraceID, service string, startTime time.Time) {
+	kv := []model.KeyValue{model.String("key", "value")}
+	for i := 0; i < 3; i++ {
+		err := sw.WriteSpan(&model.Span{
+			TraceID:       traceID,
+			SpanID:        model.SpanID(i + 1),
+			OperationName: "operation",
+			Process: &model.Process{
+				ServiceName: service,
+				Tags:        kv,
+			},
+			StartTime: startTime,
+			Duration:  time.Millisecond,
+			Tags:      kv,
+			Logs: []model.Log{
+				{
+					Timestamp: startTime,
+					Fields:    kv,
+				},
+			},
+		})
+		require.NoError(t, err)
+	}
+}
+
+func TestDeleteTrace(t *testing.T) {
+	runWithBadger(t, func(store *badger.DB, t *testing.T) {
+		cache := NewCacheStore(store, time.Hour, true)
+		sw := NewSpanWriter(store, cache, time.Hour, nil, nil)
+		sr := NewTraceReader(store, cache)
+		sd := NewSpanDeleter(store, sr)
+
+		now := time.Now()
+		kept := model.NewTraceID(1, 1)
+		deleted := model.NewTraceID(1, 2)
+		writeDeleterTestTrace(t, sw, kept, "kept", now)
+		keptKeys := countKeys(t, store)
+		writeDeleterTestTrace(t, sw, deleted, "deleted", now)
+		assert.True(t, countKeys(t, store) > keptKeys)
+
+		require.NoError(t, sd.DeleteTrace(context.Background(), deleted))
+		assert.Equal(t, keptKeys, countKeys(t, store))
+
+		trace, err := sr.GetTrace(context.Background(), deleted)
+		assert.NoError(t, err)
+		assert.Nil(t, trace)
+		trace, err = sr.GetTrace(context.Background(), kept)
+		require.NoError(t, err)
+		assert.Len(t, trace.Spans, 3)
+
+		ids, err := sr.FindTraceIDs(context.Background(), &spanstore.TraceQueryParameters{
+			ServiceName:  "deleted",
+			Tags:         map[string]string{"key": "value"},
+			StartTimeMin: now.Add(-time.Minute),
+			StartTimeMax: now.Add(time.Minute),
+			NumTraces:    10,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, ids)
+
+		// deleting a missing trace is not an error
+		assert.NoError(t, sd.DeleteTrace(context.Background(), model.NewTraceID(1, 3)))
+	})
+}
+
+func TestDeleteServiceTraces(t *testing.T) {
+	runWithBadger(t, func(store *badger.DB, t *testing.T) {
+		cache := NewCacheStore(store, time.Hour, true)
+		sw := NewSpanWriter(store, cache, time.Hour, nil, nil)
+		sr := NewTraceReader(store, cache)
+		sd := NewSpanDeleter(store, sr)
+
+		now := time.Now()
+		writeDeleterTestTrace(t, sw, model.NewTraceID(1, 1), "service", now.Add(-2*time.Hour))
+		writeDeleterTestTrace(t, sw, model.NewTraceID(1, 2), "service", now)
+		writeDeleterTestTrace(t, sw, model.NewTraceID(1, 3), "other", now)
+
+		ids, err := sd.DeleteServiceTraces(context.Background(), "service", now.Add(-time.Hour), now.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, []model.TraceID{model.NewTraceID(1, 2)}, ids)
+
+		for _, testCase := range []struct {
+			traceID model.TraceID
+			found   bool
+		}{
+			{traceID: model.NewTraceID(1, 1), found: true},
+			{traceID: model.NewTraceID(1, 2), found: false},
+			{traceID: model.NewTraceID(1, 3), found: true},
+		} {
+			trace, err := sr.GetTrace(context.Background(), testCase.traceID)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.found, trace != nil, testCase.traceID.String())
+		}
+	})
+}
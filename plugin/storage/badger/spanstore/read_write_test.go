@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
@@ -331,6 +332,241 @@ func TestMenuSeeks(t *testing.T) {
 	})
 }
 
+func TestFindTracesWithContinuationToken(t *testing.T) {
+	runFactoryTest(t, func(tb testing.TB, sw spanstore.Writer, sr spanstore.Reader) {
+		tid := time.Now()
+		traces := 25
+		spans := 3
+		for i := 0; i < traces; i++ {
+			for j := 0; j < spans; j++ {
+				s := model.Span{
+					TraceID: model.TraceID{
+						Low:  uint64(i),
+						High: 1,
+					},
+					SpanID:        model.SpanID(j),
+					OperationName: fmt.Sprintf("operation-%d", j),
+					Process: &model.Process{
+						ServiceName: "service",
+					},
+					StartTime: tid.Add(time.Duration(i*spans+j) * time.Millisecond),
+					Duration:  time.Duration(i + j),
+				}
+				err := sw.WriteSpan(&s)
+				assert.NoError(t, err)
+			}
+		}
+
+		params := &spanstore.TraceQueryParameters{
+			StartTimeMin: tid,
+			StartTimeMax: tid.Add(time.Hour),
+			ServiceName:  "service",
+			NumTraces:    10,
+		}
+		var found []uint64
+		for {
+			tr, err := sr.FindTraces(context.Background(), params)
+			assert.NoError(t, err)
+			for _, trace := range tr {
+				found = append(found, trace.Spans[0].TraceID.Low)
+			}
+			if params.ContinuationToken = spanstore.NextContinuationToken(params, len(tr)); params.ContinuationToken == nil {
+				break
+			}
+		}
+
+		// Every trace is returned exactly once, the most recent first
+		assert.Len(t, found, traces)
+		for i, low := range found {
+			assert.Equal(t, uint64(traces-1-i), low)
+		}
+	})
+}
+
+func TestFindSpans(t *testing.T) {
+	runFactoryTest(t, func(tb testing.TB, sw spanstore.Writer, sr spanstore.Reader) {
+		tid := time.Now()
+		for i := 0; i < 10; i++ {
+			for j := 0; j < 3; j++ {
+				s := model.Span{
+					TraceID: model.TraceID{
+						Low:  uint64(i),
+						High: 1,
+					},
+					SpanID:        model.SpanID(j),
+					OperationName: fmt.Sprintf("operation-%d", j),
+					Process: &model.Process{
+						ServiceName: "service",
+					},
+					Tags: model.KeyValues{
+						model.Bool("error", j == 1),
+					},
+					StartTime: tid.Add(time.Duration(i*3+j) * time.Millisecond),
+					Duration:  time.Duration(i+j) * time.Microsecond,
+				}
+				err := sw.WriteSpan(&s)
+				assert.NoError(t, err)
+			}
+		}
+
+		spanFinder, ok := sr.(spanstore.SpanFinder)
+		require.True(t, ok)
+
+		params := &spanstore.TraceQueryParameters{
+			StartTimeMin:  tid,
+			StartTimeMax:  tid.Add(time.Hour),
+			ServiceName:   "service",
+			OperationName: "operation-1",
+			Tags:          map[string]string{"error": "true"},
+			NumTraces:     4,
+		}
+		spans, err := spanFinder.FindSpans(context.Background(), params)
+		require.NoError(t, err)
+		// Only the matching spans of the traces are returned, the most recent first
+		require.Len(t, spans, 4)
+		for i, span := range spans {
+			assert.Equal(t, uint64(9-i), span.TraceID.Low)
+			assert.Equal(t, model.SpanID(1), span.SpanID)
+		}
+
+		params = &spanstore.TraceQueryParameters{
+			StartTimeMin: tid,
+			StartTimeMax: tid.Add(time.Hour),
+			ServiceName:  "service",
+			DurationMin:  11 * time.Microsecond,
+		}
+		spans, err = spanFinder.FindSpans(context.Background(), params)
+		require.NoError(t, err)
+		require.Len(t, spans, 1)
+		assert.Equal(t, uint64(9), spans[0].TraceID.Low)
+		assert.Equal(t, model.SpanID(2), spans[0].SpanID)
+
+		_, err = spanFinder.FindSpans(context.Background(), &spanstore.TraceQueryParameters{ServiceName: "service"})
+		assert.Equal(t, bss.ErrStartAndEndTimeNotSet, err)
+	})
+}
+
+func TestFindSpansAcrossCandidateTraces(t *testing.T) {
+	runFactoryTest(t, func(tb testing.TB, sw spanstore.Writer, sr spanstore.Reader) {
+		tid := time.Now()
+		writeSpan := func(traceID uint64, spanID uint64, operation string, isError bool, startTime time.Time) {
+			require.NoError(t, sw.WriteSpan(&model.Span{
+				TraceID:       model.TraceID{Low: traceID, High: 1},
+				SpanID:        model.SpanID(spanID),
+				OperationName: operation,
+				Process:       &model.Process{ServiceName: "service"},
+				Tags:          model.KeyValues{model.Bool("error", isError)},
+				StartTime:     startTime,
+				Duration:      time.Microsecond,
+			}))
+		}
+		// the most recent traces match the query through different spans, none of their spans matches it
+		for i := 0; i < 5; i++ {
+			startTime := tid.Add(time.Duration(10+i) * time.Millisecond)
+			writeSpan(uint64(10+i), 1, "checkout", false, startTime)
+			writeSpan(uint64(10+i), 2, "payment", true, startTime)
+		}
+		for i := 0; i < 2; i++ {
+			writeSpan(uint64(i), 1, "checkout", true, tid.Add(time.Duration(i)*time.Millisecond))
+		}
+
+		spans, err := sr.(spanstore.SpanFinder).FindSpans(context.Background(), &spanstore.TraceQueryParameters{
+			StartTimeMin:  tid,
+			StartTimeMax:  tid.Add(time.Hour),
+			ServiceName:   "service",
+			OperationName: "checkout",
+			Tags:          map[string]string{"error": "true"},
+			NumTraces:     2,
+		})
+		require.NoError(t, err)
+		require.Len(t, spans, 2)
+		assert.Equal(t, uint64(1), spans[0].TraceID.Low)
+		assert.Equal(t, uint64(0), spans[1].TraceID.Low)
+	})
+}
+
+func TestFindWithTagFilters(t *testing.T) {
+	runFactoryTest(t, func(tb testing.TB, sw spanstore.Writer, sr spanstore.Reader) {
+		tid := time.Now()
+		for i := 0; i < 10; i++ {
+			for j := 0; j < 2; j++ {
+				s := model.Span{
+					TraceID: model.TraceID{
+						Low:  uint64(i),
+						High: 1,
+					},
+					SpanID:        model.SpanID(j),
+					OperationName: fmt.Sprintf("operation-%d", j),
+					Process: &model.Process{
+						ServiceName: "service",
+					},
+					Tags: model.KeyValues{
+						model.Int64("http.status_code", int64(200+i*50)),
+						model.String("http.method", fmt.Sprintf("method-%d", j)),
+					},
+					StartTime: tid.Add(time.Duration(i*2+j) * time.Millisecond),
+					Duration:  time.Duration(i+j) * time.Microsecond,
+				}
+				err := sw.WriteSpan(&s)
+				assert.NoError(t, err)
+			}
+		}
+
+		params := &spanstore.TraceQueryParameters{
+			StartTimeMin: tid,
+			StartTimeMax: tid.Add(time.Hour),
+			ServiceName:  "service",
+			TagFilters: []spanstore.TagFilter{
+				{Key: "http.status_code", Operator: spanstore.TagFilterGreaterThan, Value: "499"},
+				{Key: "http.method", Operator: spanstore.TagFilterRegex, Value: "method-1"},
+			},
+			NumTraces: 3,
+		}
+		traces, err := sr.FindTraces(context.Background(), params)
+		require.NoError(t, err)
+		// Traces 6..9 have a status code above 499, the most recent ones are returned first
+		require.Len(t, traces, 3)
+		for i, trace := range traces {
+			assert.Equal(t, uint64(9-i), trace.Spans[0].TraceID.Low)
+		}
+
+		params.ContinuationToken = spanstore.NextContinuationToken(params, len(traces))
+		traceIDs, err := sr.FindTraceIDs(context.Background(), params)
+		require.NoError(t, err)
+		assert.Equal(t, []model.TraceID{{High: 1, Low: 6}}, traceIDs)
+
+		spanFinder := sr.(spanstore.SpanFinder)
+		params.ContinuationToken = nil
+		params.TagFilters = []spanstore.TagFilter{
+			{Key: "http.status_code", Operator: spanstore.TagFilterLessThan, Value: "300"},
+			{Key: "http.method", Operator: spanstore.TagFilterNotEqual, Value: "method-1"},
+		}
+		spans, err := spanFinder.FindSpans(context.Background(), params)
+		require.NoError(t, err)
+		require.Len(t, spans, 2)
+		for i, span := range spans {
+			assert.Equal(t, uint64(1-i), span.TraceID.Low)
+			assert.Equal(t, model.SpanID(0), span.SpanID)
+		}
+
+		// without a service the candidate traces are found with a scan of the time range
+		params.ServiceName = ""
+		params.TagFilters = []spanstore.TagFilter{
+			{Key: "http.status_code", Operator: spanstore.TagFilterGreaterThan, Value: "499"},
+		}
+		traces, err = sr.FindTraces(context.Background(), params)
+		require.NoError(t, err)
+		require.Len(t, traces, 3)
+		for i, trace := range traces {
+			assert.Equal(t, uint64(6+i), trace.Spans[0].TraceID.Low)
+		}
+
+		params.TagFilters = []spanstore.TagFilter{{Key: "http.status_code", Operator: spanstore.TagFilterGreaterThan, Value: "x"}}
+		_, err = sr.FindTraces(context.Background(), params)
+		assert.Error(t, err)
+	})
+}
+
 func TestPersist(t *testing.T) {
 	dir, err := ioutil.TempDir("", "badgerTest")
 	assert.NoError(t, err)
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"time"
 
 	"github.com/dgraph-io/badger"
@@ -60,6 +61,8 @@ const (
 	defaultNumTraces = 100
 	sizeOfTraceID    = 16
 	encodingTypeBits = 0x0F
+	// tagFilterBatchSize is the number of candidate traces loaded at once to evaluate the tag filters
+	tagFilterBatchSize = 100
 )
 
 // TraceReader reads traces from the local badger store
@@ -156,12 +159,21 @@ func (r *TraceReader) GetTrace(ctx context.Context, traceID model.TraceID) (*mod
 
 // scanTimeRange returns all the Traces found between startTs and endTs
 func (r *TraceReader) scanTimeRange(startTime time.Time, endTime time.Time) ([]*model.Trace, error) {
-	// We need to do a full table scan
 	traces := make([]*model.Trace, 0)
+	err := r.scanTimeRangeFn(startTime, endTime, func(trace *model.Trace) bool {
+		traces = append(traces, trace)
+		return true
+	})
+	return traces, err
+}
+
+// scanTimeRangeFn passes the Traces found between startTs and endTs to fn, in TraceID order, until fn returns false
+func (r *TraceReader) scanTimeRangeFn(startTime time.Time, endTime time.Time, fn func(trace *model.Trace) bool) error {
+	// We need to do a full table scan
 	startTs := model.TimeAsEpochMicroseconds(startTime)
 	endTs := model.TimeAsEpochMicroseconds(endTime)
 
-	err := r.store.View(func(txn *badger.Txn) error {
+	return r.store.View(func(txn *badger.Txn) error {
 		opts := badger.DefaultIteratorOptions
 		opts.PrefetchValues = true
 		it := txn.NewIterator(opts)
@@ -199,7 +211,9 @@ func (r *TraceReader) scanTimeRange(startTime time.Time, endTime time.Time) ([]*
 					trace := &model.Trace{
 						Spans: spans,
 					}
-					traces = append(traces, trace)
+					if !fn(trace) {
+						return nil
+					}
 
 					spans = make([]*model.Span, 0, cap(spans)) // Use previous cap
 					spans = append(spans, sp)
@@ -211,13 +225,11 @@ func (r *TraceReader) scanTimeRange(startTime time.Time, endTime time.Time) ([]*
 			trace := &model.Trace{
 				Spans: spans,
 			}
-			traces = append(traces, trace)
+			fn(trace)
 		}
 
 		return nil
 	})
-
-	return traces, err
 }
 
 func createPrimaryKeySeekPrefix(traceID model.TraceID) []byte {
@@ -376,6 +388,17 @@ func sortMergeIds(query *spanstore.TraceQueryParameters, ids [][][]byte) []model
 		intersected = mergeIntersected
 	}
 
+	// The same trace can be matched by many of its spans, keep only its most recent match
+	// so that the results can be paged through
+	intersected = dedupeIds(intersected)
+
+	// Skip the results returned by the previous pages
+	if offset := query.Offset(); offset < len(intersected) {
+		intersected = intersected[offset:]
+	} else {
+		intersected = intersected[:0]
+	}
+
 	// Get top query.NumTraces results (note, the slice is now in descending timestamp order)
 	if query.NumTraces < len(intersected) {
 		intersected = intersected[:query.NumTraces]
@@ -395,12 +418,32 @@ func sortMergeIds(query *spanstore.TraceQueryParameters, ids [][][]byte) []model
 	return keys
 }
 
+// dedupeIds removes the repeated TraceIDs from the list, keeping the order of their first occurrence
+func dedupeIds(ids [][]byte) [][]byte {
+	seen := make(map[string]struct{}, len(ids))
+	deduped := ids[:0]
+	for _, id := range ids {
+		if _, exists := seen[string(id)]; !exists {
+			seen[string(id)] = struct{}{}
+			deduped = append(deduped, id)
+		}
+	}
+	return deduped
+}
+
 // FindTraces retrieves traces that match the traceQuery
 func (r *TraceReader) FindTraces(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
+	if query != nil && len(query.TagFilters) > 0 {
+		return r.findTracesWithTagFilters(ctx, query)
+	}
 	keys, err := r.FindTraceIDs(ctx, query)
 	if err != nil {
 		if err == ErrNotSupported && (!query.StartTimeMax.IsZero() && !query.StartTimeMin.IsZero()) {
-			return r.scanTimeRange(query.StartTimeMin, query.StartTimeMax)
+			traces, err := r.scanTimeRange(query.StartTimeMin, query.StartTimeMax)
+			if err != nil {
+				return nil, err
+			}
+			return pageTraces(query, traces), nil
 		}
 
 		return nil, err
@@ -409,8 +452,181 @@ func (r *TraceReader) FindTraces(ctx context.Context, query *spanstore.TraceQuer
 	return r.getTraces(keys)
 }
 
+// FindSpans retrieves the spans that match the traceQuery, newest first. The candidate traces are found
+// with the indexes like in FindTraces and their spans are then filtered with the query parameters.
+func (r *TraceReader) FindSpans(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Span, error) {
+	if query == nil {
+		return nil, ErrMalformedRequestObject
+	}
+	tagMatchers, err := spanstore.NewTagMatchers(query.TagFilters)
+	if err != nil {
+		return nil, err
+	}
+	// The indexes match traces rather than spans: the operation and the tags of the query may be found in
+	// different spans of a candidate trace, none of which then matches. The candidate traces are therefore
+	// paged through until enough spans are found or the candidates are exhausted.
+	// The span search itself is not paged, any continuation token is ignored.
+	traceQuery := *query
+	traceQuery.ContinuationToken = nil
+	setQueryDefaults(&traceQuery)
+	var spans []*model.Span
+	for len(spans) < traceQuery.NumTraces {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		traces, err := r.FindTraces(ctx, &traceQuery)
+		if err != nil {
+			return nil, err
+		}
+		for _, trace := range traces {
+			for _, span := range trace.Spans {
+				if spanMatches(span, &traceQuery, tagMatchers) {
+					spans = append(spans, span)
+				}
+			}
+		}
+		if traceQuery.ContinuationToken = spanstore.NextContinuationToken(&traceQuery, len(traces)); traceQuery.ContinuationToken == nil {
+			break
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		if !spans[i].StartTime.Equal(spans[j].StartTime) {
+			return spans[i].StartTime.After(spans[j].StartTime)
+		}
+		return spans[i].SpanID > spans[j].SpanID
+	})
+	if traceQuery.NumTraces < len(spans) {
+		spans = spans[:traceQuery.NumTraces]
+	}
+	return spans, nil
+}
+
+// findTracesWithTagFilters evaluates the tag filters, which are not backed by an index, on the traces found
+// with the rest of the query. The candidate traces are loaded and matched one batch at a time, and the search
+// stops once the requested page is filled, so that only the matching traces up to the page are kept in memory.
+func (r *TraceReader) findTracesWithTagFilters(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
+	tagMatchers, err := spanstore.NewTagMatchers(query.TagFilters)
+	if err != nil {
+		return nil, err
+	}
+	setQueryDefaults(query)
+	limit := query.Offset() + query.NumTraces
+	var traces []*model.Trace
+	// match keeps the trace if it matches and returns whether more traces are needed
+	match := func(trace *model.Trace) bool {
+		for _, span := range trace.Spans {
+			if spanMatches(span, query, tagMatchers) {
+				traces = append(traces, trace)
+				break
+			}
+		}
+		return len(traces) < limit
+	}
+
+	candidateQuery := *query
+	candidateQuery.TagFilters = nil
+	candidateQuery.ContinuationToken = nil
+	candidateQuery.NumTraces = math.MaxInt32
+	candidateIDs, err := r.FindTraceIDs(ctx, &candidateQuery)
+	if err == ErrNotSupported && !query.StartTimeMax.IsZero() && !query.StartTimeMin.IsZero() {
+		if err := r.scanTimeRangeFn(query.StartTimeMin, query.StartTimeMax, match); err != nil {
+			return nil, err
+		}
+		return pageTraces(query, traces), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for start := 0; start < len(candidateIDs); start += tagFilterBatchSize {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		end := start + tagFilterBatchSize
+		if end > len(candidateIDs) {
+			end = len(candidateIDs)
+		}
+		batch, err := r.getTraces(candidateIDs[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for _, trace := range batch {
+			if !match(trace) {
+				return pageTraces(query, traces), nil
+			}
+		}
+	}
+	return pageTraces(query, traces), nil
+}
+
+// spanMatches checks the span against the query parameters, the tags are matched against the span tags,
+// the process tags and the log fields in the same way as they are indexed by the writer
+func spanMatches(span *model.Span, query *spanstore.TraceQueryParameters, tagMatchers []spanstore.TagMatcher) bool {
+	if query.ServiceName != "" && span.Process.ServiceName != query.ServiceName {
+		return false
+	}
+	if query.OperationName != "" && span.OperationName != query.OperationName {
+		return false
+	}
+	if span.StartTime.Before(query.StartTimeMin) || span.StartTime.After(query.StartTimeMax) {
+		return false
+	}
+	if query.DurationMin != 0 && span.Duration < query.DurationMin {
+		return false
+	}
+	if query.DurationMax != 0 && span.Duration > query.DurationMax {
+		return false
+	}
+	tags := spanstore.FlattenTags(span)
+	for k, v := range query.Tags {
+		if !hasTag(tags, k, v) {
+			return false
+		}
+	}
+	for _, tagMatcher := range tagMatchers {
+		if !tagMatcher(tags) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasTag(tags model.KeyValues, key, value string) bool {
+	for _, kv := range tags {
+		if kv.Key == key && kv.AsString() == value {
+			return true
+		}
+	}
+	return false
+}
+
+// pageTraces returns the page of the full scan results selected by the query, the scan returns
+// the traces in TraceID order
+func pageTraces(query *spanstore.TraceQueryParameters, traces []*model.Trace) []*model.Trace {
+	if offset := query.Offset(); offset < len(traces) {
+		traces = traces[offset:]
+	} else {
+		traces = traces[:0]
+	}
+	if query.NumTraces < len(traces) {
+		traces = traces[:query.NumTraces]
+	}
+	return traces
+}
+
 // FindTraceIDs retrieves only the TraceIDs that match the traceQuery, but not the trace data
 func (r *TraceReader) FindTraceIDs(ctx context.Context, query *spanstore.TraceQueryParameters) ([]model.TraceID, error) {
+	if query != nil && len(query.TagFilters) > 0 {
+		traces, err := r.findTracesWithTagFilters(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		traceIDs := make([]model.TraceID, len(traces))
+		for i, trace := range traces {
+			traceIDs[i] = trace.Spans[0].TraceID
+		}
+		return traceIDs, nil
+	}
+
 	// Validate and set query defaults which were not defined
 	if err := validateQuery(query); err != nil {
 		return nil, err
@@ -15,6 +15,7 @@ package spanstore
 
 import (
 	"context"
+	"encoding/binary"
 	"testing"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/retention"
 )
 
 func TestEncodingTypes(t *testing.T) {
@@ -61,7 +63,7 @@ func TestEncodingTypes(t *testing.T) {
 	// JSON encoding
 	runWithBadger(t, func(store *badger.DB, t *testing.T) {
 		cache := NewCacheStore(store, time.Duration(1*time.Hour), true)
-		sw := NewSpanWriter(store, cache, time.Duration(1*time.Hour), nil)
+		sw := NewSpanWriter(store, cache, time.Duration(1*time.Hour), nil, nil)
 		rw := NewTraceReader(store, cache)
 
 		sw.encodingType = jsonEncoding
@@ -76,7 +78,7 @@ func TestEncodingTypes(t *testing.T) {
 	// Unknown encoding write
 	runWithBadger(t, func(store *badger.DB, t *testing.T) {
 		cache := NewCacheStore(store, time.Duration(1*time.Hour), true)
-		sw := NewSpanWriter(store, cache, time.Duration(1*time.Hour), nil)
+		sw := NewSpanWriter(store, cache, time.Duration(1*time.Hour), nil, nil)
 		// rw := NewTraceReader(store, cache)
 
 		sw.encodingType = 0x04
@@ -87,7 +89,7 @@ func TestEncodingTypes(t *testing.T) {
 	// Unknown encoding reader
 	runWithBadger(t, func(store *badger.DB, t *testing.T) {
 		cache := NewCacheStore(store, time.Duration(1*time.Hour), true)
-		sw := NewSpanWriter(store, cache, time.Duration(1*time.Hour), nil)
+		sw := NewSpanWriter(store, cache, time.Duration(1*time.Hour), nil, nil)
 		rw := NewTraceReader(store, cache)
 
 		err := sw.WriteSpan(&testSpan)
@@ -110,6 +112,60 @@ func TestEncodingTypes(t *testing.T) {
 	})
 }
 
+func TestRetentionPolicy(t *testing.T) {
+	policy, err := retention.NewPolicy([]retention.Rule{
+		{Name: "payments", Services: []string{"payments"}, TTL: 720 * time.Hour},
+	})
+	assert.NoError(t, err)
+
+	runWithBadger(t, func(store *badger.DB, t *testing.T) {
+		cache := NewCacheStore(store, time.Hour, false)
+		sw := NewSpanWriter(store, cache, time.Hour, policy, nil)
+
+		for i, service := range []string{"payments", "batch"} {
+			span := &model.Span{
+				TraceID:       model.NewTraceID(0, uint64(i+1)),
+				SpanID:        model.NewSpanID(1),
+				OperationName: "operation",
+				Process:       model.NewProcess(service, nil),
+				StartTime:     time.Now(),
+				Tags:          model.KeyValues{model.String("key", "value")},
+			}
+			assert.NoError(t, sw.WriteSpan(span))
+		}
+
+		now := time.Now()
+		expirations := make(map[uint64][]time.Time)
+		store.View(func(txn *badger.Txn) error {
+			it := txn.NewIterator(badger.DefaultIteratorOptions)
+			defer it.Close()
+			for it.Rewind(); it.Valid(); it.Next() {
+				key := it.Item().Key()
+				// the trace ID is the last 16 bytes of the index keys, and follows the prefix byte in the span keys
+				traceIDLow := key[len(key)-8:]
+				if key[0] == spanKeyPrefix {
+					traceIDLow = key[9:17]
+				}
+				low := binary.BigEndian.Uint64(traceIDLow)
+				expirations[low] = append(expirations[low], time.Unix(int64(it.Item().ExpiresAt()), 0))
+			}
+			return nil
+		})
+		assert.Len(t, expirations[1], 5)
+		for _, expiresAt := range expirations[1] {
+			assert.WithinDuration(t, now.Add(720*time.Hour), expiresAt, time.Minute)
+		}
+		assert.Len(t, expirations[2], 5)
+		for _, expiresAt := range expirations[2] {
+			assert.WithinDuration(t, now.Add(time.Hour), expiresAt, time.Minute)
+		}
+
+		services, err := cache.GetServices()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"batch", "payments"}, services)
+	})
+}
+
 func TestDecodeErrorReturns(t *testing.T) {
 	garbage := []byte{0x08}
 
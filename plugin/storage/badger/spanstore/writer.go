@@ -26,6 +26,7 @@ import (
 	"github.com/gogo/protobuf/proto"
 
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/retention"
 )
 
 /*
@@ -51,16 +52,19 @@ const (
 type SpanWriter struct {
 	store        *badger.DB
 	ttl          time.Duration
+	retention    *retention.Policy
 	cache        *CacheStore
 	closer       io.Closer
 	encodingType byte
 }
 
-// NewSpanWriter returns a SpawnWriter with cache
-func NewSpanWriter(db *badger.DB, c *CacheStore, ttl time.Duration, storageCloser io.Closer) *SpanWriter {
+// NewSpanWriter returns a SpawnWriter with cache. The spans are kept for ttl, unless the retention
+// policy sets another TTL for their service.
+func NewSpanWriter(db *badger.DB, c *CacheStore, ttl time.Duration, retentionPolicy *retention.Policy, storageCloser io.Closer) *SpanWriter {
 	return &SpanWriter{
 		store:        db,
 		ttl:          ttl,
+		retention:    retentionPolicy,
 		cache:        c,
 		closer:       storageCloser,
 		encodingType: defaultEncoding, // TODO Make configurable
@@ -72,35 +76,17 @@ func (w *SpanWriter) WriteSpan(span *model.Span) error {
 
 	// Avoid doing as much as possible inside the transaction boundary, create entries here
 	entriesToStore := make([]*badger.Entry, 0, len(span.Tags)+4+len(span.Process.Tags)+len(span.Logs)*4)
+	ttl := w.retention.TTL(span.Process.ServiceName, w.ttl)
+	expireTime := uint64(time.Now().Add(ttl).Unix())
 
-	trace, err := w.createTraceEntry(span)
+	trace, err := w.createTraceEntry(span, expireTime)
 	if err != nil {
 		return err
 	}
 
 	entriesToStore = append(entriesToStore, trace)
-	entriesToStore = append(entriesToStore, w.createBadgerEntry(createIndexKey(serviceNameIndexKey, []byte(span.Process.ServiceName), span.StartTime, span.TraceID), nil))
-	entriesToStore = append(entriesToStore, w.createBadgerEntry(createIndexKey(operationNameIndexKey, []byte(span.Process.ServiceName+span.OperationName), span.StartTime, span.TraceID), nil))
-
-	// It doesn't matter if we overwrite Duration index keys, everything is read at Trace level in any case
-	durationValue := make([]byte, 8)
-	binary.BigEndian.PutUint64(durationValue, uint64(model.DurationAsMicroseconds(span.Duration)))
-	entriesToStore = append(entriesToStore, w.createBadgerEntry(createIndexKey(durationIndexKey, durationValue, span.StartTime, span.TraceID), nil))
-
-	for _, kv := range span.Tags {
-		// Convert everything to string since queries are done that way also
-		// KEY: it<serviceName><tagsKey><traceId> VALUE: <tagsValue>
-		entriesToStore = append(entriesToStore, w.createBadgerEntry(createIndexKey(tagIndexKey, []byte(span.Process.ServiceName+kv.Key+kv.AsString()), span.StartTime, span.TraceID), nil))
-	}
-
-	for _, kv := range span.Process.Tags {
-		entriesToStore = append(entriesToStore, w.createBadgerEntry(createIndexKey(tagIndexKey, []byte(span.Process.ServiceName+kv.Key+kv.AsString()), span.StartTime, span.TraceID), nil))
-	}
-
-	for _, log := range span.Logs {
-		for _, kv := range log.Fields {
-			entriesToStore = append(entriesToStore, w.createBadgerEntry(createIndexKey(tagIndexKey, []byte(span.Process.ServiceName+kv.Key+kv.AsString()), span.StartTime, span.TraceID), nil))
-		}
+	for _, indexKey := range createIndexKeys(span) {
+		entriesToStore = append(entriesToStore, w.createBadgerEntry(indexKey, nil, expireTime))
 	}
 
 	err = w.store.Update(func(txn *badger.Txn) error {
@@ -120,11 +106,40 @@ func (w *SpanWriter) WriteSpan(span *model.Span) error {
 	})
 
 	// Do cache refresh here to release the transaction earlier
-	w.cache.Update(span.Process.ServiceName, span.OperationName)
+	w.cache.UpdateWithTTL(span.Process.ServiceName, span.OperationName, ttl)
 
 	return err
 }
 
+// createIndexKeys returns the keys of the secondary indexes of the span
+func createIndexKeys(span *model.Span) [][]byte {
+	indexKeys := make([][]byte, 0, len(span.Tags)+3+len(span.Process.Tags)+len(span.Logs)*4)
+	indexKeys = append(indexKeys, createIndexKey(serviceNameIndexKey, []byte(span.Process.ServiceName), span.StartTime, span.TraceID))
+	indexKeys = append(indexKeys, createIndexKey(operationNameIndexKey, []byte(span.Process.ServiceName+span.OperationName), span.StartTime, span.TraceID))
+
+	// It doesn't matter if we overwrite Duration index keys, everything is read at Trace level in any case
+	durationValue := make([]byte, 8)
+	binary.BigEndian.PutUint64(durationValue, uint64(model.DurationAsMicroseconds(span.Duration)))
+	indexKeys = append(indexKeys, createIndexKey(durationIndexKey, durationValue, span.StartTime, span.TraceID))
+
+	for _, kv := range span.Tags {
+		// Convert everything to string since queries are done that way also
+		// KEY: it<serviceName><tagsKey><traceId> VALUE: <tagsValue>
+		indexKeys = append(indexKeys, createIndexKey(tagIndexKey, []byte(span.Process.ServiceName+kv.Key+kv.AsString()), span.StartTime, span.TraceID))
+	}
+
+	for _, kv := range span.Process.Tags {
+		indexKeys = append(indexKeys, createIndexKey(tagIndexKey, []byte(span.Process.ServiceName+kv.Key+kv.AsString()), span.StartTime, span.TraceID))
+	}
+
+	for _, log := range span.Logs {
+		for _, kv := range log.Fields {
+			indexKeys = append(indexKeys, createIndexKey(tagIndexKey, []byte(span.Process.ServiceName+kv.Key+kv.AsString()), span.StartTime, span.TraceID))
+		}
+	}
+	return indexKeys
+}
+
 func createIndexKey(indexPrefixKey byte, value []byte, startTime time.Time, traceID model.TraceID) []byte {
 	// KEY: indexKey<indexValue><startTime><traceId> (traceId is last 16 bytes of the key)
 	buf := new(bytes.Buffer)
@@ -137,21 +152,21 @@ func createIndexKey(indexPrefixKey byte, value []byte, startTime time.Time, trac
 	return buf.Bytes()
 }
 
-func (w *SpanWriter) createBadgerEntry(key []byte, value []byte) *badger.Entry {
+func (w *SpanWriter) createBadgerEntry(key []byte, value []byte, expireTime uint64) *badger.Entry {
 	return &badger.Entry{
 		Key:       key,
 		Value:     value,
-		ExpiresAt: uint64(time.Now().Add(w.ttl).Unix()),
+		ExpiresAt: expireTime,
 	}
 }
 
-func (w *SpanWriter) createTraceEntry(span *model.Span) (*badger.Entry, error) {
+func (w *SpanWriter) createTraceEntry(span *model.Span, expireTime uint64) (*badger.Entry, error) {
 	pK, pV, err := createTraceKV(span, w.encodingType)
 	if err != nil {
 		return nil, err
 	}
 
-	e := w.createBadgerEntry(pK, pV)
+	e := w.createBadgerEntry(pK, pV, expireTime)
 	e.UserMeta = w.encodingType
 
 	return e, nil
@@ -16,6 +16,8 @@ package cassandra
 
 import (
 	"flag"
+	"os"
+	"sync"
 
 	"github.com/spf13/viper"
 	"github.com/uber/jaeger-lib/metrics"
@@ -23,10 +25,16 @@ import (
 
 	"github.com/jaegertracing/jaeger/pkg/cassandra"
 	"github.com/jaegertracing/jaeger/pkg/cassandra/config"
+	"github.com/jaegertracing/jaeger/pkg/distributedlock"
+	"github.com/jaegertracing/jaeger/pkg/retention"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	cLock "github.com/jaegertracing/jaeger/plugin/pkg/distributedlock/cassandra"
 	cDepStore "github.com/jaegertracing/jaeger/plugin/storage/cassandra/dependencystore"
+	cSamplingStore "github.com/jaegertracing/jaeger/plugin/storage/cassandra/samplingstore"
 	cSpanStore "github.com/jaegertracing/jaeger/plugin/storage/cassandra/spanstore"
 	"github.com/jaegertracing/jaeger/storage"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/samplingstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -47,6 +55,15 @@ type Factory struct {
 	primarySession cassandra.Session
 	archiveConfig  config.SessionBuilder
 	archiveSession cassandra.Session
+
+	// retentionPolicy sets the TTL of the spans of the services it matches, the other spans have the TTL
+	// of the keyspace. It does not apply to the archive storage.
+	retentionPolicy *retention.Policy
+
+	// tenantConfig returns the session builder for the keyspace of the tenant
+	tenantConfig   func(tenant string) config.SessionBuilder
+	tenantsLock    sync.Mutex
+	tenantSessions map[string]cassandra.Session
 }
 
 // NewFactory creates a new Factory.
@@ -65,6 +82,11 @@ func (f *Factory) AddFlags(flagSet *flag.FlagSet) {
 func (f *Factory) InitFromViper(v *viper.Viper) {
 	f.Options.InitFromViper(v)
 	f.primaryConfig = f.Options.GetPrimary()
+	f.tenantConfig = func(tenant string) config.SessionBuilder {
+		cfg := *f.Options.GetPrimary()
+		cfg.Keyspace = tenantKeyspace(cfg.Keyspace, tenant)
+		return &cfg
+	}
 	if cfg := f.Options.Get(archiveStorageConfig); cfg != nil {
 		f.archiveConfig = cfg // this is so stupid - see https://golang.org/doc/faq#nil_error
 	}
@@ -75,6 +97,13 @@ func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger)
 	f.primaryMetricsFactory = metricsFactory.Namespace(metrics.NSOptions{Name: "cassandra", Tags: nil})
 	f.archiveMetricsFactory = metricsFactory.Namespace(metrics.NSOptions{Name: "cassandra-archive", Tags: nil})
 	f.logger = logger
+	f.tenantSessions = make(map[string]cassandra.Session)
+
+	retentionPolicy, err := retention.LoadPolicyFile(f.Options.RetentionPolicyFile)
+	if err != nil {
+		return err
+	}
+	f.retentionPolicy = retentionPolicy
 
 	primarySession, err := f.primaryConfig.NewSession()
 	if err != nil {
@@ -101,7 +130,14 @@ func (f *Factory) CreateSpanReader() (spanstore.Reader, error) {
 
 // CreateSpanWriter implements storage.Factory
 func (f *Factory) CreateSpanWriter() (spanstore.Writer, error) {
-	return cSpanStore.NewSpanWriter(f.primarySession, f.Options.SpanStoreWriteCacheTTL, f.primaryMetricsFactory, f.logger), nil
+	return cSpanStore.NewSpanWriter(f.primarySession, f.Options.SpanStoreWriteCacheTTL, f.primaryMetricsFactory, f.logger,
+		cSpanStore.RetentionPolicy(f.retentionPolicy)), nil
+}
+
+// CreateSpanDeleter implements storage.DeleterFactory
+func (f *Factory) CreateSpanDeleter() (spanstore.Deleter, error) {
+	reader := cSpanStore.NewSpanReader(f.primarySession, f.primaryMetricsFactory, f.logger)
+	return cSpanStore.NewSpanDeleter(f.primarySession, reader, f.logger), nil
 }
 
 // CreateDependencyReader implements storage.Factory
@@ -110,6 +146,12 @@ func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
 	return cDepStore.NewDependencyStore(f.primarySession, f.primaryMetricsFactory, f.logger, version)
 }
 
+// CreateDependencyWriter implements storage.DependencyWriterFactory
+func (f *Factory) CreateDependencyWriter() (dependencystore.Writer, error) {
+	version := cDepStore.GetDependencyVersion(f.primarySession)
+	return cDepStore.NewDependencyStore(f.primarySession, f.primaryMetricsFactory, f.logger, version)
+}
+
 // CreateArchiveSpanReader implements storage.ArchiveFactory
 func (f *Factory) CreateArchiveSpanReader() (spanstore.Reader, error) {
 	if f.archiveSession == nil {
@@ -125,3 +167,96 @@ func (f *Factory) CreateArchiveSpanWriter() (spanstore.Writer, error) {
 	}
 	return cSpanStore.NewSpanWriter(f.archiveSession, f.Options.SpanStoreWriteCacheTTL, f.archiveMetricsFactory, f.logger), nil
 }
+
+// CreateLock implements storage.SamplingStoreFactory
+func (f *Factory) CreateLock() (distributedlock.Lock, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	return cLock.NewLock(f.primarySession, hostname), nil
+}
+
+// CreateSamplingStore implements storage.SamplingStoreFactory
+func (f *Factory) CreateSamplingStore() (samplingstore.Store, error) {
+	return cSamplingStore.New(f.primarySession, f.primaryMetricsFactory, f.logger), nil
+}
+
+// CreateTenantSpanReader implements storage.TenantFactory
+func (f *Factory) CreateTenantSpanReader(tenant string) (spanstore.Reader, error) {
+	session, err := f.tenantSession(tenant)
+	if err != nil {
+		return nil, err
+	}
+	return cSpanStore.NewSpanReader(session, f.primaryMetricsFactory, f.logger), nil
+}
+
+// CreateTenantSpanWriter implements storage.TenantFactory
+func (f *Factory) CreateTenantSpanWriter(tenant string) (spanstore.Writer, error) {
+	session, err := f.tenantSession(tenant)
+	if err != nil {
+		return nil, err
+	}
+	return cSpanStore.NewSpanWriter(session, f.Options.SpanStoreWriteCacheTTL, f.primaryMetricsFactory, f.logger,
+		cSpanStore.RetentionPolicy(f.retentionPolicy)), nil
+}
+
+// CreateTenantDependencyReader implements storage.TenantFactory
+func (f *Factory) CreateTenantDependencyReader(tenant string) (dependencystore.Reader, error) {
+	session, err := f.tenantSession(tenant)
+	if err != nil {
+		return nil, err
+	}
+	version := cDepStore.GetDependencyVersion(session)
+	return cDepStore.NewDependencyStore(session, f.primaryMetricsFactory, f.logger, version)
+}
+
+// CreateTenantSpanDeleter implements storage.TenantFactory
+func (f *Factory) CreateTenantSpanDeleter(tenant string) (spanstore.Deleter, error) {
+	session, err := f.tenantSession(tenant)
+	if err != nil {
+		return nil, err
+	}
+	reader := cSpanStore.NewSpanReader(session, f.primaryMetricsFactory, f.logger)
+	return cSpanStore.NewSpanDeleter(session, reader, f.logger), nil
+}
+
+// tenantSession returns the session bound to the keyspace of the tenant, creating it if needed.
+// The keyspace is not created, it must be initialized with the schema beforehand.
+func (f *Factory) tenantSession(tenant string) (cassandra.Session, error) {
+	if err := tenancy.ValidateName(tenant); err != nil {
+		return nil, err
+	}
+	f.tenantsLock.Lock()
+	defer f.tenantsLock.Unlock()
+	if session, ok := f.tenantSessions[tenant]; ok {
+		return session, nil
+	}
+	session, err := f.tenantConfig(tenant).NewSession()
+	if err != nil {
+		return nil, err
+	}
+	f.tenantSessions[tenant] = session
+	return session, nil
+}
+
+// Close implements io.Closer and closes the primary, archive and tenant sessions
+func (f *Factory) Close() error {
+	if f.primarySession != nil {
+		f.primarySession.Close()
+	}
+	if f.archiveSession != nil {
+		f.archiveSession.Close()
+	}
+	f.tenantsLock.Lock()
+	defer f.tenantsLock.Unlock()
+	for _, session := range f.tenantSessions {
+		session.Close()
+	}
+	return nil
+}
+
+// tenantKeyspace returns the keyspace holding the data of the tenant, e.g. "jaeger_v1_dc1_acme".
+func tenantKeyspace(keyspace, tenant string) string {
+	return keyspace + "_" + tenant
+}
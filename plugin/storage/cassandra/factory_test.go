@@ -20,10 +20,12 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/pkg/cassandra"
+	cConfig "github.com/jaegertracing/jaeger/pkg/cassandra/config"
 	"github.com/jaegertracing/jaeger/pkg/cassandra/mocks"
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
@@ -32,6 +34,8 @@ import (
 
 var _ storage.Factory = new(Factory)
 var _ storage.ArchiveFactory = new(Factory)
+var _ storage.SamplingStoreFactory = new(Factory)
+var _ storage.TenantFactory = new(Factory)
 
 type mockSessionBuilder struct {
 	session *mocks.Session
@@ -81,15 +85,27 @@ func TestCassandraFactory(t *testing.T) {
 	_, err = f.CreateSpanWriter()
 	assert.NoError(t, err)
 
+	_, err = f.CreateSpanDeleter()
+	assert.NoError(t, err)
+
 	_, err = f.CreateDependencyReader()
 	assert.NoError(t, err)
 
+	_, err = f.CreateDependencyWriter()
+	assert.NoError(t, err)
+
 	_, err = f.CreateArchiveSpanReader()
 	assert.EqualError(t, err, "archive storage not configured")
 
 	_, err = f.CreateArchiveSpanWriter()
 	assert.EqualError(t, err, "archive storage not configured")
 
+	_, err = f.CreateLock()
+	assert.NoError(t, err)
+
+	_, err = f.CreateSamplingStore()
+	assert.NoError(t, err)
+
 	f.archiveConfig = &mockSessionBuilder{}
 	assert.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
 
@@ -99,3 +115,96 @@ func TestCassandraFactory(t *testing.T) {
 	_, err = f.CreateArchiveSpanWriter()
 	assert.NoError(t, err)
 }
+
+func TestCassandraFactoryRetentionPolicy(t *testing.T) {
+	f := NewFactory()
+	v, command := config.Viperize(f.AddFlags)
+	command.ParseFlags([]string{"--cassandra.retention-policy-file=../../../pkg/retention/fixtures/retention_policy.yaml"})
+	f.InitFromViper(v)
+	f.primaryConfig = newMockSessionBuilder(&mocks.Session{}, nil)
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+	assert.Len(t, f.retentionPolicy.Rules(), 2)
+
+	command.ParseFlags([]string{"--cassandra.retention-policy-file=/not/a/file"})
+	f.InitFromViper(v)
+	err := f.Initialize(metrics.NullFactory, zap.NewNop())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Failed to open retention policy file")
+}
+
+func TestTenantSessions(t *testing.T) {
+	f := NewFactory()
+	v, command := config.Viperize(f.AddFlags)
+	command.ParseFlags([]string{"--cassandra.keyspace=jaeger_v1_dc1"})
+	f.InitFromViper(v)
+
+	var (
+		session = &mocks.Session{}
+		query   = &mocks.Query{}
+	)
+	session.On("Query", mock.AnythingOfType("string"), mock.Anything).Return(query)
+	query.On("Exec").Return(nil)
+	f.primaryConfig = newMockSessionBuilder(session, nil)
+
+	cfg, ok := f.tenantConfig("acme").(*cConfig.Configuration)
+	require.True(t, ok)
+	assert.Equal(t, "jaeger_v1_dc1_acme", cfg.Keyspace)
+	assert.Equal(t, "jaeger_v1_dc1", f.Options.GetPrimary().Keyspace)
+
+	var keyspaces []string
+	f.tenantConfig = func(tenant string) cConfig.SessionBuilder {
+		keyspaces = append(keyspaces, tenantKeyspace("jaeger_v1_dc1", tenant))
+		if tenant == "broken" {
+			return newMockSessionBuilder(nil, errors.New("made-up error"))
+		}
+		return newMockSessionBuilder(session, nil)
+	}
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+
+	_, err := f.CreateTenantSpanReader("acme")
+	assert.NoError(t, err)
+	_, err = f.CreateTenantSpanWriter("acme")
+	assert.NoError(t, err)
+	_, err = f.CreateTenantDependencyReader("acme")
+	assert.NoError(t, err)
+	_, err = f.CreateTenantSpanDeleter("acme")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"jaeger_v1_dc1_acme"}, keyspaces, "the session of a tenant is reused")
+
+	_, err = f.CreateTenantSpanReader("broken")
+	assert.EqualError(t, err, "made-up error")
+	_, err = f.CreateTenantSpanWriter("broken")
+	assert.EqualError(t, err, "made-up error")
+	_, err = f.CreateTenantDependencyReader("broken")
+	assert.EqualError(t, err, "made-up error")
+	_, err = f.CreateTenantSpanDeleter("broken")
+	assert.EqualError(t, err, "made-up error")
+
+	_, err = f.CreateTenantSpanReader("Acme")
+	assert.Error(t, err)
+}
+
+func TestCassandraFactoryClose(t *testing.T) {
+	f := NewFactory()
+	v, command := config.Viperize(f.AddFlags)
+	command.ParseFlags([]string{"--cassandra-archive.enabled=true"})
+	f.InitFromViper(v)
+
+	primarySession, archiveSession, tenantSession := &mocks.Session{}, &mocks.Session{}, &mocks.Session{}
+	for _, session := range []*mocks.Session{primarySession, archiveSession, tenantSession} {
+		session.On("Close").Once()
+	}
+	f.primaryConfig = newMockSessionBuilder(primarySession, nil)
+	f.archiveConfig = newMockSessionBuilder(archiveSession, nil)
+	f.tenantConfig = func(tenant string) cConfig.SessionBuilder {
+		return newMockSessionBuilder(tenantSession, nil)
+	}
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+	_, err := f.CreateTenantSpanReader("acme")
+	require.NoError(t, err)
+
+	assert.NoError(t, f.Close())
+	primarySession.AssertExpectations(t)
+	archiveSession.AssertExpectations(t)
+	tenantSession.AssertExpectations(t)
+}
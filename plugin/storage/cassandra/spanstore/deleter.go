@@ -0,0 +1,138 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/cassandra"
+	"github.com/jaegertracing/jaeger/plugin/storage/cassandra/spanstore/dbmodel"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+const (
+	deleteSpans = `
+		DELETE
+		FROM traces
+		WHERE trace_id = ?`
+	deleteTag = `
+		DELETE
+		FROM tag_index
+		WHERE service_name = ? AND tag_key = ? AND tag_value = ? AND start_time = ? AND trace_id = ? AND span_id = ?`
+	// service_name_index and service_operation_index rows are keyed by start time only,
+	// the condition keeps rows overwritten by spans of other traces
+	deleteServiceNameIndex = `
+		DELETE
+		FROM service_name_index
+		WHERE service_name = ? AND bucket = ? AND start_time = ?
+		IF trace_id = ?`
+	deleteServiceOperationIndex = `
+		DELETE
+		FROM service_operation_index
+		WHERE service_name = ? AND operation_name = ? AND start_time = ?
+		IF trace_id = ?`
+	deleteDurationIndex = `
+		DELETE
+		FROM duration_index
+		WHERE service_name = ? AND operation_name = ? AND bucket = ? AND duration = ? AND start_time = ? AND trace_id = ?`
+)
+
+// SpanDeleter deletes the spans of traces and their index rows from Cassandra
+type SpanDeleter struct {
+	session cassandra.Session
+	reader  *SpanReader
+	logger  *zap.Logger
+}
+
+// NewSpanDeleter returns a SpanDeleter reading the spans to delete with the reader
+func NewSpanDeleter(session cassandra.Session, reader *SpanReader, logger *zap.Logger) *SpanDeleter {
+	return &SpanDeleter{
+		session: session,
+		reader:  reader,
+		logger:  logger,
+	}
+}
+
+// DeleteTrace implements spanstore.Deleter. The index rows are deleted before the spans,
+// so that a failed deletion can be retried.
+func (d *SpanDeleter) DeleteTrace(ctx context.Context, traceID model.TraceID) error {
+	trace, err := d.reader.GetTrace(ctx, traceID)
+	if err == spanstore.ErrTraceNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, span := range trace.Spans {
+		if err := d.deleteIndexes(span); err != nil {
+			return err
+		}
+	}
+	if err := d.session.Query(deleteSpans, dbmodel.TraceIDFromDomain(traceID)).Exec(); err != nil {
+		return d.logError(traceID, err, "Failed to delete spans")
+	}
+	return nil
+}
+
+func (d *SpanDeleter) deleteIndexes(span *model.Span) error {
+	ds := dbmodel.FromDomain(span)
+	for _, v := range dbmodel.GetAllUniqueTags(span, dbmodel.DefaultTagFilter) {
+		if !shouldIndexTag(v) {
+			continue
+		}
+		query := d.session.Query(deleteTag, v.ServiceName, v.TagKey, v.TagValue, ds.StartTime, ds.TraceID, ds.SpanID)
+		if err := query.Exec(); err != nil {
+			return d.logError(span.TraceID, err, "Failed to delete tag index")
+		}
+	}
+
+	bucketNo := uint64(ds.SpanHash) % defaultNumBuckets
+	query := d.session.Query(deleteServiceNameIndex, ds.Process.ServiceName, bucketNo, ds.StartTime, ds.TraceID)
+	if err := query.Exec(); err != nil {
+		return d.logError(span.TraceID, err, "Failed to delete service name index")
+	}
+
+	query = d.session.Query(deleteServiceOperationIndex, ds.Process.ServiceName, ds.OperationName, ds.StartTime, ds.TraceID)
+	if err := query.Exec(); err != nil {
+		return d.logError(span.TraceID, err, "Failed to delete service operation index")
+	}
+
+	timeBucket := span.StartTime.Round(durationBucketSize)
+	for _, operationName := range []string{"", ds.OperationName} {
+		query := d.session.Query(deleteDurationIndex, ds.Process.ServiceName, operationName, timeBucket, ds.Duration, ds.StartTime, ds.TraceID)
+		if err := query.Exec(); err != nil {
+			return d.logError(span.TraceID, err, "Failed to delete duration index")
+		}
+	}
+	return nil
+}
+
+// DeleteServiceTraces implements spanstore.Deleter
+func (d *SpanDeleter) DeleteServiceTraces(ctx context.Context, service string, startTimeMin, startTimeMax time.Time) ([]model.TraceID, error) {
+	return spanstore.DeleteFoundTraces(ctx, d.reader, service, startTimeMin, startTimeMax, d.DeleteTrace)
+}
+
+func (d *SpanDeleter) logError(traceID model.TraceID, err error, msg string) error {
+	d.logger.
+		With(zap.String("trace_id", traceID.String())).
+		With(zap.Error(err)).
+		Error(msg)
+	return errors.Wrap(err, msg)
+}
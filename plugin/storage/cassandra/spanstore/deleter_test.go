@@ -0,0 +1,165 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/cassandra"
+	"github.com/jaegertracing/jaeger/pkg/cassandra/mocks"
+	"github.com/jaegertracing/jaeger/pkg/testutils"
+	"github.com/jaegertracing/jaeger/plugin/storage/cassandra/spanstore/dbmodel"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+var _ spanstore.Deleter = &SpanDeleter{} // check API conformance
+
+func withSpanDeleter(fn func(session *mocks.Session, deleter *SpanDeleter)) {
+	session := &mocks.Session{}
+	logger, _ := testutils.NewLogger()
+	reader := NewSpanReader(session, metricstest.NewFactory(0), logger)
+	fn(session, NewSpanDeleter(session, reader, logger))
+}
+
+func mockReadTraceQuery(session *mocks.Session, found bool) {
+	iter := &mocks.Iterator{}
+	if found {
+		iter.On("Scan", matchOnceWithSideEffect(func(args []interface{}) {
+			for _, arg := range args {
+				switch v := arg.(type) {
+				case *string:
+					*v = "operation-a"
+				case *[]dbmodel.KeyValue:
+					*v = []dbmodel.KeyValue{{Key: "x", ValueType: "string", ValueString: "y"}}
+				case *dbmodel.Process:
+					*v = dbmodel.Process{ServiceName: "service-a"}
+				}
+			}
+		})).Return(true)
+	}
+	iter.On("Scan", matchEverything()).Return(false)
+	iter.On("Close").Return(nil)
+
+	query := &mocks.Query{}
+	query.On("Consistency", cassandra.One).Return(query)
+	query.On("Iter").Return(iter)
+	session.On("Query", stringMatcher(querySpanByTraceID), matchEverything()).Return(query)
+}
+
+func TestSpanDeleterDeleteTrace(t *testing.T) {
+	testCases := []struct {
+		caption          string
+		tagError         error
+		serviceNameError error
+		serviceOpError   error
+		durationError    error
+		spansError       error
+		expectedError    string
+	}{
+		{
+			caption: "all rows deleted",
+		},
+		{
+			caption:       "tag index",
+			tagError:      errors.New("tag error"),
+			expectedError: "Failed to delete tag index: tag error",
+		},
+		{
+			caption:          "service name index",
+			serviceNameError: errors.New("service name error"),
+			expectedError:    "Failed to delete service name index: service name error",
+		},
+		{
+			caption:        "service operation index",
+			serviceOpError: errors.New("service operation error"),
+			expectedError:  "Failed to delete service operation index: service operation error",
+		},
+		{
+			caption:       "duration index",
+			durationError: errors.New("duration error"),
+			expectedError: "Failed to delete duration index: duration error",
+		},
+		{
+			caption:       "spans",
+			spansError:    errors.New("spans error"),
+			expectedError: "Failed to delete spans: spans error",
+		},
+	}
+	for _, tc := range testCases {
+		testCase := tc // capture loop var
+		t.Run(testCase.caption, func(t *testing.T) {
+			withSpanDeleter(func(session *mocks.Session, deleter *SpanDeleter) {
+				mockReadTraceQuery(session, true)
+				mockDelete := func(statement string, err error) *mocks.Query {
+					query := &mocks.Query{}
+					query.On("Exec").Return(err)
+					session.On("Query", stringMatcher(statement), matchEverything()).Return(query)
+					return query
+				}
+				tagQuery := mockDelete(deleteTag, testCase.tagError)
+				serviceNameQuery := mockDelete(deleteServiceNameIndex, testCase.serviceNameError)
+				serviceOpQuery := mockDelete(deleteServiceOperationIndex, testCase.serviceOpError)
+				durationQuery := mockDelete(deleteDurationIndex, testCase.durationError)
+				spansQuery := mockDelete(deleteSpans, testCase.spansError)
+
+				err := deleter.DeleteTrace(context.Background(), model.NewTraceID(0, 1))
+				if testCase.expectedError != "" {
+					assert.EqualError(t, err, testCase.expectedError)
+					return
+				}
+				assert.NoError(t, err)
+				tagQuery.AssertNumberOfCalls(t, "Exec", 1)
+				serviceNameQuery.AssertNumberOfCalls(t, "Exec", 1)
+				serviceOpQuery.AssertNumberOfCalls(t, "Exec", 1)
+				durationQuery.AssertNumberOfCalls(t, "Exec", 2)
+				spansQuery.AssertNumberOfCalls(t, "Exec", 1)
+			})
+		})
+	}
+}
+
+func TestSpanDeleterDeleteTraceNotFound(t *testing.T) {
+	withSpanDeleter(func(session *mocks.Session, deleter *SpanDeleter) {
+		mockReadTraceQuery(session, false)
+		assert.NoError(t, deleter.DeleteTrace(context.Background(), model.NewTraceID(0, 1)))
+		session.AssertNumberOfCalls(t, "Query", 1)
+	})
+}
+
+func TestSpanDeleterDeleteServiceTraces(t *testing.T) {
+	withSpanDeleter(func(session *mocks.Session, deleter *SpanDeleter) {
+		// the service name index has no rows for the service
+		iter := &mocks.Iterator{}
+		iter.On("Scan", matchEverything()).Return(false)
+		iter.On("Close").Return(nil)
+		query := &mocks.Query{}
+		query.On("Consistency", cassandra.One).Return(query)
+		query.On("PageSize", mock.Anything).Return(query)
+		query.On("Iter").Return(iter)
+		session.On("Query", stringMatcher(queryByServiceName), matchEverything()).Return(query)
+
+		ids, err := deleter.DeleteServiceTraces(context.Background(), "service-a", time.Now().Add(-time.Hour), time.Now())
+		assert.NoError(t, err)
+		assert.Empty(t, ids)
+	})
+}
@@ -212,7 +212,17 @@ func validateQuery(p *spanstore.TraceQueryParameters) error {
 	if p == nil {
 		return ErrMalformedRequestObject
 	}
-	if p.ServiceName == "" && len(p.Tags) > 0 {
+	for _, filter := range p.TagFilters {
+		if err := filter.Validate(); err != nil {
+			return err
+		}
+		// only the exact tag matches are backed by the tag index
+		if filter.Operator != spanstore.TagFilterEqual {
+			return errors.Wrapf(spanstore.ErrTagFilterNotSupported, "%q", filter.String())
+		}
+	}
+	hasTags := len(p.Tags) > 0 || len(p.TagFilters) > 0
+	if p.ServiceName == "" && hasTags {
 		return ErrServiceNameNotSet
 	}
 	if p.StartTimeMin.IsZero() || p.StartTimeMax.IsZero() {
@@ -224,7 +234,7 @@ func validateQuery(p *spanstore.TraceQueryParameters) error {
 	if p.DurationMin != 0 && p.DurationMax != 0 && p.DurationMin > p.DurationMax {
 		return ErrDurationMinGreaterThanMax
 	}
-	if (p.DurationMin != 0 || p.DurationMax != 0) && len(p.Tags) > 0 {
+	if (p.DurationMin != 0 || p.DurationMax != 0) && hasTags {
 		return ErrDurationAndTagQueryNotSupported
 	}
 	return nil
@@ -253,18 +263,28 @@ func (s *SpanReader) FindTraceIDs(ctx context.Context, traceQuery *spanstore.Tra
 	if err := validateQuery(traceQuery); err != nil {
 		return nil, err
 	}
-	if traceQuery.NumTraces == 0 {
-		traceQuery.NumTraces = defaultNumTraces
+	// the caller's query is left unchanged, so that NextContinuationToken sees the requested number of traces
+	numTraces := traceQuery.NumTraces
+	if numTraces == 0 {
+		numTraces = defaultNumTraces
 	}
 
-	dbTraceIDs, err := s.findTraceIDs(ctx, traceQuery)
+	// The traces of the previous pages are looked up as well, and skipped below
+	lookupQuery := *traceQuery
+	lookupQuery.NumTraces = numTraces + traceQuery.Offset()
+	dbTraceIDs, err := s.findTraceIDs(ctx, &lookupQuery)
 	if err != nil {
 		return nil, err
 	}
+	if offset := traceQuery.Offset(); offset < len(dbTraceIDs) {
+		dbTraceIDs = dbTraceIDs[offset:]
+	} else {
+		dbTraceIDs = nil
+	}
 
 	var traceIDs []model.TraceID
-	for t := range dbTraceIDs {
-		if len(traceIDs) >= traceQuery.NumTraces {
+	for _, t := range dbTraceIDs {
+		if len(traceIDs) >= numTraces {
 			break
 		}
 		traceIDs = append(traceIDs, t.ToDomain())
@@ -272,47 +292,73 @@ func (s *SpanReader) FindTraceIDs(ctx context.Context, traceQuery *spanstore.Tra
 	return traceIDs, nil
 }
 
-func (s *SpanReader) findTraceIDs(ctx context.Context, traceQuery *spanstore.TraceQueryParameters) (dbmodel.UniqueTraceIDs, error) {
+// findTraceIDs returns the unique traceIDs matching the query, in the order in which the indices returned them,
+// i.e. the most recent traces first, so that the results can be paged through.
+func (s *SpanReader) findTraceIDs(ctx context.Context, traceQuery *spanstore.TraceQueryParameters) ([]dbmodel.TraceID, error) {
 	if traceQuery.DurationMin != 0 || traceQuery.DurationMax != 0 {
 		return s.queryByDuration(ctx, traceQuery)
 	}
 
+	tags := indexedTags(traceQuery)
 	if traceQuery.OperationName != "" {
 		traceIds, err := s.queryByServiceNameAndOperation(ctx, traceQuery)
 		if err != nil {
 			return nil, err
 		}
-		if len(traceQuery.Tags) > 0 {
-			tagTraceIds, err := s.queryByTagsAndLogs(ctx, traceQuery)
+		if len(tags) > 0 {
+			tagTraceIds, err := s.queryByTagsAndLogs(ctx, traceQuery, tags)
 			if err != nil {
 				return nil, err
 			}
-			return dbmodel.IntersectTraceIDs([]dbmodel.UniqueTraceIDs{
+			return intersectTraceIDs([][]dbmodel.TraceID{
 				traceIds,
 				tagTraceIds,
 			}), nil
 		}
 		return traceIds, nil
 	}
-	if len(traceQuery.Tags) > 0 {
-		return s.queryByTagsAndLogs(ctx, traceQuery)
+	if len(tags) > 0 {
+		return s.queryByTagsAndLogs(ctx, traceQuery, tags)
 	}
 	return s.queryByService(ctx, traceQuery)
 }
 
-func (s *SpanReader) queryByTagsAndLogs(ctx context.Context, tq *spanstore.TraceQueryParameters) (dbmodel.UniqueTraceIDs, error) {
+// indexedTag is a tag key and value looked up in the tag index.
+type indexedTag struct {
+	key   string
+	value string
+}
+
+// indexedTags returns the exact tag matches and the equality tag filters of the query,
+// the other tag filter operators being rejected by validateQuery.
+func indexedTags(tq *spanstore.TraceQueryParameters) []indexedTag {
+	tags := make([]indexedTag, 0, len(tq.Tags)+len(tq.TagFilters))
+	for k, v := range tq.Tags {
+		tags = append(tags, indexedTag{key: k, value: v})
+	}
+	for _, filter := range tq.TagFilters {
+		tags = append(tags, indexedTag{key: filter.Key, value: filter.Value})
+	}
+	return tags
+}
+
+func (s *SpanReader) queryByTagsAndLogs(
+	ctx context.Context,
+	tq *spanstore.TraceQueryParameters,
+	tags []indexedTag,
+) ([]dbmodel.TraceID, error) {
 	span, ctx := startSpanForQuery(ctx, "queryByTagsAndLogs", queryByTag)
 	defer span.Finish()
 
-	results := make([]dbmodel.UniqueTraceIDs, 0, len(tq.Tags))
-	for k, v := range tq.Tags {
+	results := make([][]dbmodel.TraceID, 0, len(tags))
+	for _, tag := range tags {
 		childSpan, _ := opentracing.StartSpanFromContext(ctx, "queryByTag")
-		childSpan.LogFields(otlog.String("tag.key", k), otlog.String("tag.value", v))
+		childSpan.LogFields(otlog.String("tag.key", tag.key), otlog.String("tag.value", tag.value))
 		query := s.session.Query(
 			queryByTag,
 			tq.ServiceName,
-			k,
-			v,
+			tag.key,
+			tag.value,
 			model.TimeAsEpochMicroseconds(tq.StartTimeMin),
 			model.TimeAsEpochMicroseconds(tq.StartTimeMax),
 			tq.NumTraces*limitMultiple,
@@ -324,14 +370,15 @@ func (s *SpanReader) queryByTagsAndLogs(ctx context.Context, tq *spanstore.Trace
 		}
 		results = append(results, t)
 	}
-	return dbmodel.IntersectTraceIDs(results), nil
+	return intersectTraceIDs(results), nil
 }
 
-func (s *SpanReader) queryByDuration(ctx context.Context, traceQuery *spanstore.TraceQueryParameters) (dbmodel.UniqueTraceIDs, error) {
+func (s *SpanReader) queryByDuration(ctx context.Context, traceQuery *spanstore.TraceQueryParameters) ([]dbmodel.TraceID, error) {
 	span, ctx := startSpanForQuery(ctx, "queryByDuration", queryByDuration)
 	defer span.Finish()
 
-	results := dbmodel.UniqueTraceIDs{}
+	var results []dbmodel.TraceID
+	uniqueTraceIDs := dbmodel.UniqueTraceIDs{}
 
 	minDurationMicros := traceQuery.DurationMin.Nanoseconds() / int64(time.Microsecond/time.Nanosecond)
 	maxDurationMicros := (time.Hour * 24).Nanoseconds() / int64(time.Microsecond/time.Nanosecond)
@@ -361,8 +408,12 @@ func (s *SpanReader) queryByDuration(ctx context.Context, traceQuery *spanstore.
 			return nil, err
 		}
 
-		for traceID := range t {
-			results.Add(traceID)
+		for _, traceID := range t {
+			if _, exists := uniqueTraceIDs[traceID]; exists {
+				continue
+			}
+			uniqueTraceIDs.Add(traceID)
+			results = append(results, traceID)
 			if len(results) == traceQuery.NumTraces {
 				break
 			}
@@ -371,7 +422,7 @@ func (s *SpanReader) queryByDuration(ctx context.Context, traceQuery *spanstore.
 	return results, nil
 }
 
-func (s *SpanReader) queryByServiceNameAndOperation(ctx context.Context, tq *spanstore.TraceQueryParameters) (dbmodel.UniqueTraceIDs, error) {
+func (s *SpanReader) queryByServiceNameAndOperation(ctx context.Context, tq *spanstore.TraceQueryParameters) ([]dbmodel.TraceID, error) {
 	//lint:ignore SA4006 failing to re-assign context is worse than unused variable
 	span, ctx := startSpanForQuery(ctx, "queryByServiceNameAndOperation", queryByServiceAndOperationName)
 	defer span.Finish()
@@ -386,7 +437,7 @@ func (s *SpanReader) queryByServiceNameAndOperation(ctx context.Context, tq *spa
 	return s.executeQuery(span, query, s.metrics.queryServiceOperationIndex)
 }
 
-func (s *SpanReader) queryByService(ctx context.Context, tq *spanstore.TraceQueryParameters) (dbmodel.UniqueTraceIDs, error) {
+func (s *SpanReader) queryByService(ctx context.Context, tq *spanstore.TraceQueryParameters) ([]dbmodel.TraceID, error) {
 	//lint:ignore SA4006 failing to re-assign context is worse than unused variable
 	span, ctx := startSpanForQuery(ctx, "queryByService", queryByServiceName)
 	defer span.Finish()
@@ -400,13 +451,18 @@ func (s *SpanReader) queryByService(ctx context.Context, tq *spanstore.TraceQuer
 	return s.executeQuery(span, query, s.metrics.queryServiceNameIndex)
 }
 
-func (s *SpanReader) executeQuery(span opentracing.Span, query cassandra.Query, tableMetrics *casMetrics.Table) (dbmodel.UniqueTraceIDs, error) {
+// executeQuery returns the unique traceIDs returned by the index query, in the order of their first occurrence
+func (s *SpanReader) executeQuery(span opentracing.Span, query cassandra.Query, tableMetrics *casMetrics.Table) ([]dbmodel.TraceID, error) {
 	start := time.Now()
 	i := query.Iter()
-	retMe := dbmodel.UniqueTraceIDs{}
+	var retMe []dbmodel.TraceID
+	uniqueTraceIDs := dbmodel.UniqueTraceIDs{}
 	var traceID dbmodel.TraceID
 	for i.Scan(&traceID) {
-		retMe.Add(traceID)
+		if _, exists := uniqueTraceIDs[traceID]; !exists {
+			uniqueTraceIDs.Add(traceID)
+			retMe = append(retMe, traceID)
+		}
 	}
 	err := i.Close()
 	tableMetrics.Emit(err, time.Since(start))
@@ -419,6 +475,28 @@ func (s *SpanReader) executeQuery(span opentracing.Span, query cassandra.Query,
 	return retMe, nil
 }
 
+// intersectTraceIDs returns the traceIDs of the first list that are present in all the other lists, keeping their order
+func intersectTraceIDs(traceIDsList [][]dbmodel.TraceID) []dbmodel.TraceID {
+	others := make([]dbmodel.UniqueTraceIDs, 0, len(traceIDsList)-1)
+	for _, traceIDs := range traceIDsList[1:] {
+		others = append(others, dbmodel.UniqueTraceIDsFromList(traceIDs))
+	}
+	var retMe []dbmodel.TraceID
+	for _, traceID := range traceIDsList[0] {
+		existsInAll := true
+		for _, other := range others {
+			if _, ok := other[traceID]; !ok {
+				existsInAll = false
+				break
+			}
+		}
+		if existsInAll {
+			retMe = append(retMe, traceID)
+		}
+	}
+	return retMe
+}
+
 func startSpanForQuery(ctx context.Context, name, query string) (opentracing.Span, context.Context) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, name)
 	ottag.DBStatement.Set(span, query)
@@ -374,6 +374,114 @@ func TestSpanReaderFindTraces(t *testing.T) {
 	}
 }
 
+func TestSpanReaderFindTraceIDsWithContinuationToken(t *testing.T) {
+	withSpanReader(func(r *spanReaderTest) {
+		// the index returns the traces most recent first, with a trace matched by several spans
+		indexed := []dbmodel.TraceID{
+			dbmodel.TraceIDFromDomain(model.NewTraceID(0, 3)),
+			dbmodel.TraceIDFromDomain(model.NewTraceID(0, 1)),
+			dbmodel.TraceIDFromDomain(model.NewTraceID(0, 3)),
+			dbmodel.TraceIDFromDomain(model.NewTraceID(0, 2)),
+		}
+		iter := &mocks.Iterator{}
+		iter.On("Scan", mock.MatchedBy(func(args []interface{}) bool {
+			if len(indexed) == 0 {
+				return false
+			}
+			*args[0].(*dbmodel.TraceID) = indexed[0]
+			indexed = indexed[1:]
+			return true
+		})).Return(true)
+		iter.On("Scan", matchEverything()).Return(false)
+		iter.On("Close").Return(nil)
+
+		query := &mocks.Query{}
+		query.On("PageSize", 0).Return(query)
+		query.On("Iter").Return(iter)
+
+		// the traces of the previous page must be looked up as well
+		limitMatcher := mock.MatchedBy(func(v []interface{}) bool {
+			return v[len(v)-1] == (1+1)*limitMultiple
+		})
+		r.session.On("Query", stringMatcher(queryByServiceName), limitMatcher).Return(query)
+
+		traceQuery := &spanstore.TraceQueryParameters{
+			ServiceName:  "service-a",
+			NumTraces:    1,
+			StartTimeMax: time.Now(),
+			StartTimeMin: time.Now().Add(-1 * time.Minute * 30),
+		}
+		traceQuery.ContinuationToken = &spanstore.ContinuationToken{
+			StartTimeMax: traceQuery.StartTimeMax,
+			Offset:       1,
+		}
+		traceIDs, err := r.reader.FindTraceIDs(context.Background(), traceQuery)
+		require.NoError(t, err)
+		assert.Equal(t, []model.TraceID{model.NewTraceID(0, 1)}, traceIDs)
+	})
+}
+
+func TestSpanReaderFindTraceIDsDefaultNumTraces(t *testing.T) {
+	withSpanReader(func(r *spanReaderTest) {
+		iter := &mocks.Iterator{}
+		iter.On("Scan", matchEverything()).Return(false)
+		iter.On("Close").Return(nil)
+
+		query := &mocks.Query{}
+		query.On("PageSize", 0).Return(query)
+		query.On("Iter").Return(iter)
+
+		limitMatcher := mock.MatchedBy(func(v []interface{}) bool {
+			return v[len(v)-1] == defaultNumTraces*limitMultiple
+		})
+		r.session.On("Query", stringMatcher(queryByServiceName), limitMatcher).Return(query)
+
+		traceQuery := &spanstore.TraceQueryParameters{
+			ServiceName:  "service-a",
+			StartTimeMax: time.Now(),
+			StartTimeMin: time.Now().Add(-1 * time.Minute * 30),
+		}
+		traceIDs, err := r.reader.FindTraceIDs(context.Background(), traceQuery)
+		require.NoError(t, err)
+		assert.Empty(t, traceIDs)
+		// the default applies to the lookup only, the caller's query is left unchanged
+		assert.Equal(t, 0, traceQuery.NumTraces)
+	})
+}
+
+func TestSpanReaderFindTraceIDsWithTagFilters(t *testing.T) {
+	withSpanReader(func(r *spanReaderTest) {
+		iter := &mocks.Iterator{}
+		iter.On("Scan", matchEverything()).Return(false)
+		iter.On("Close").Return(nil)
+
+		query := &mocks.Query{}
+		query.On("PageSize", 0).Return(query)
+		query.On("Iter").Return(iter)
+
+		// the equality filters are looked up in the tag index along with the exact tag matches
+		tagMatcher := func(key, value string) interface{} {
+			return mock.MatchedBy(func(v []interface{}) bool {
+				return v[0] == "service-a" && v[1] == key && v[2] == value
+			})
+		}
+		r.session.On("Query", stringMatcher(queryByTag), tagMatcher("http.method", "GET")).Return(query).Once()
+		r.session.On("Query", stringMatcher(queryByTag), tagMatcher("error", "true")).Return(query).Once()
+
+		traceQuery := &spanstore.TraceQueryParameters{
+			ServiceName:  "service-a",
+			Tags:         map[string]string{"http.method": "GET"},
+			TagFilters:   []spanstore.TagFilter{{Key: "error", Operator: spanstore.TagFilterEqual, Value: "true"}},
+			StartTimeMax: time.Now(),
+			StartTimeMin: time.Now().Add(-1 * time.Minute * 30),
+		}
+		traceIDs, err := r.reader.FindTraceIDs(context.Background(), traceQuery)
+		require.NoError(t, err)
+		assert.Empty(t, traceIDs)
+		r.session.AssertExpectations(t)
+	})
+}
+
 func TestTraceQueryParameterValidation(t *testing.T) {
 	tsp := &spanstore.TraceQueryParameters{
 		ServiceName: "",
@@ -401,6 +509,29 @@ func TestTraceQueryParameterValidation(t *testing.T) {
 	err = validateQuery(tsp)
 	assert.EqualError(t, err, ErrDurationAndTagQueryNotSupported.Error())
 
+	tsp.DurationMin = 0
+	tsp.DurationMax = 0
+	tsp.TagFilters = []spanstore.TagFilter{{Key: "michael", Operator: spanstore.TagFilterEqual, Value: "jackson"}}
+	err = validateQuery(tsp)
+	assert.NoError(t, err)
+
+	tsp.TagFilters = []spanstore.TagFilter{{Key: "michael", Operator: spanstore.TagFilterExists}}
+	err = validateQuery(tsp)
+	assert.EqualError(t, err, `"exists(michael)": tag filter operator not supported by the storage backend`)
+
+	tsp.TagFilters = []spanstore.TagFilter{{Key: "michael", Operator: spanstore.TagFilterRegex, Value: "("}}
+	err = validateQuery(tsp)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), spanstore.ErrInvalidTagFilter.Error())
+
+	tsp.Tags = nil
+	tsp.ServiceName = ""
+	tsp.TagFilters = []spanstore.TagFilter{{Key: "michael", Operator: spanstore.TagFilterEqual, Value: "jackson"}}
+	err = validateQuery(tsp)
+	assert.EqualError(t, err, ErrServiceNameNotSet.Error())
+	tsp.ServiceName = "serviceName"
+	tsp.TagFilters = nil
+
 	tsp.StartTimeMin = time.Time{} //time.Unix(0,0) doesn't work because timezones
 	tsp.StartTimeMax = time.Time{}
 	err = validateQuery(tsp)
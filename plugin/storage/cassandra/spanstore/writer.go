@@ -16,6 +16,7 @@ package spanstore
 
 import (
 	"encoding/json"
+	"math"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -27,6 +28,7 @@ import (
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/cassandra"
 	casMetrics "github.com/jaegertracing/jaeger/pkg/cassandra/metrics"
+	"github.com/jaegertracing/jaeger/pkg/retention"
 	"github.com/jaegertracing/jaeger/plugin/storage/cassandra/spanstore/dbmodel"
 )
 
@@ -57,6 +59,10 @@ const (
 		INTO duration_index(service_name, operation_name, bucket, duration, start_time, trace_id)
 		VALUES (?, ?, ?, ?, ?, ?)`
 
+	// usingTTL is appended to the inserts of the spans with a TTL set by the retention policy
+	usingTTL = `
+		USING TTL ?`
+
 	maximumTagKeyOrValueSize = 256
 
 	// DefaultNumBuckets Number of buckets for bucketed keys
@@ -93,6 +99,7 @@ type SpanWriter struct {
 	tagFilter            dbmodel.TagFilter
 	storageMode          storageMode
 	indexFilter          dbmodel.IndexFilter
+	retention            *retention.Policy
 }
 
 // NewSpanWriter returns a SpanWriter
@@ -123,6 +130,7 @@ func NewSpanWriter(
 		tagFilter:       opts.tagFilter,
 		storageMode:     opts.storageMode,
 		indexFilter:     opts.indexFilter,
+		retention:       opts.retention,
 	}
 }
 
@@ -135,34 +143,63 @@ func (s *SpanWriter) Close() error {
 // WriteSpan saves the span into Cassandra
 func (s *SpanWriter) WriteSpan(span *model.Span) error {
 	ds := dbmodel.FromDomain(span)
+	ttl := s.retentionTTL(ds.ServiceName)
 	if s.storageMode&storeFlag == storeFlag {
-		if err := s.writeSpan(span, ds); err != nil {
+		if err := s.writeSpan(span, ds, ttl); err != nil {
 			return err
 		}
 	}
 	if s.storageMode&indexFlag == indexFlag {
-		if err := s.writeIndexes(span, ds); err != nil {
+		if err := s.writeIndexes(span, ds, ttl); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (s *SpanWriter) writeSpan(span *model.Span, ds *dbmodel.Span) error {
+// retentionTTL returns the TTL in seconds set by the retention policy for the spans of the service,
+// or 0 if the spans keep the default TTL of the tables
+func (s *SpanWriter) retentionTTL(serviceName string) int {
+	if rule, ok := s.retention.Match(serviceName); ok {
+		return int(math.Ceil(rule.TTL.Seconds()))
+	}
+	return 0
+}
+
+// withTTL appends the TTL clause to the insert statement if ttl is not 0. Explicitly setting a TTL of 0
+// would make the rows never expire, so the clause is left out to keep the default TTL of the tables.
+func withTTL(insert string, ttl int) string {
+	if ttl == 0 {
+		return insert
+	}
+	return insert + usingTTL
+}
+
+// bindTTL appends ttl to the values bound to an insert statement made with withTTL
+func bindTTL(ttl int, values ...interface{}) []interface{} {
+	if ttl == 0 {
+		return values
+	}
+	return append(values, ttl)
+}
+
+func (s *SpanWriter) writeSpan(span *model.Span, ds *dbmodel.Span, ttl int) error {
 	mainQuery := s.session.Query(
-		insertSpan,
-		ds.TraceID,
-		ds.SpanID,
-		ds.SpanHash,
-		ds.ParentID,
-		ds.OperationName,
-		ds.Flags,
-		ds.StartTime,
-		ds.Duration,
-		ds.Tags,
-		ds.Logs,
-		ds.Refs,
-		ds.Process,
+		withTTL(insertSpan, ttl),
+		bindTTL(ttl,
+			ds.TraceID,
+			ds.SpanID,
+			ds.SpanHash,
+			ds.ParentID,
+			ds.OperationName,
+			ds.Flags,
+			ds.StartTime,
+			ds.Duration,
+			ds.Tags,
+			ds.Logs,
+			ds.Refs,
+			ds.Process,
+		)...,
 	)
 	if err := s.writerMetrics.traces.Exec(mainQuery, s.logger); err != nil {
 		return s.logError(ds, err, "Failed to insert span", s.logger)
@@ -170,42 +207,42 @@ func (s *SpanWriter) writeSpan(span *model.Span, ds *dbmodel.Span) error {
 	return nil
 }
 
-func (s *SpanWriter) writeIndexes(span *model.Span, ds *dbmodel.Span) error {
+func (s *SpanWriter) writeIndexes(span *model.Span, ds *dbmodel.Span, ttl int) error {
 	if err := s.saveServiceNameAndOperationName(ds.ServiceName, ds.OperationName); err != nil {
 		// should this be a soft failure?
 		return s.logError(ds, err, "Failed to insert service name and operation name", s.logger)
 	}
 
-	if err := s.indexByTags(span, ds); err != nil {
+	if err := s.indexByTags(span, ds, ttl); err != nil {
 		return s.logError(ds, err, "Failed to index tags", s.logger)
 	}
 
 	if s.indexFilter(ds, dbmodel.ServiceIndex) {
-		if err := s.indexByService(ds); err != nil {
+		if err := s.indexByService(ds, ttl); err != nil {
 			return s.logError(ds, err, "Failed to index service name", s.logger)
 		}
 	}
 
 	if s.indexFilter(ds, dbmodel.OperationIndex) {
-		if err := s.indexByOperation(ds); err != nil {
+		if err := s.indexByOperation(ds, ttl); err != nil {
 			return s.logError(ds, err, "Failed to index operation name", s.logger)
 		}
 	}
 
 	if s.indexFilter(ds, dbmodel.DurationIndex) {
-		if err := s.indexByDuration(ds, span.StartTime); err != nil {
+		if err := s.indexByDuration(ds, span.StartTime, ttl); err != nil {
 			return s.logError(ds, err, "Failed to index duration", s.logger)
 		}
 	}
 	return nil
 }
 
-func (s *SpanWriter) indexByTags(span *model.Span, ds *dbmodel.Span) error {
+func (s *SpanWriter) indexByTags(span *model.Span, ds *dbmodel.Span, ttl int) error {
 	for _, v := range dbmodel.GetAllUniqueTags(span, s.tagFilter) {
 		// we should introduce retries or just ignore failures imo, retrying each individual tag insertion might be better
 		// we should consider bucketing.
-		if s.shouldIndexTag(v) {
-			insertTagQuery := s.session.Query(insertTag, ds.TraceID, ds.SpanID, v.ServiceName, ds.StartTime, v.TagKey, v.TagValue)
+		if shouldIndexTag(v) {
+			insertTagQuery := s.session.Query(withTTL(insertTag, ttl), bindTTL(ttl, ds.TraceID, ds.SpanID, v.ServiceName, ds.StartTime, v.TagKey, v.TagValue)...)
 			if err := s.writerMetrics.tagIndex.Exec(insertTagQuery, s.logger); err != nil {
 				withTagInfo := s.logger.
 					With(zap.String("tag_key", v.TagKey)).
@@ -220,12 +257,12 @@ func (s *SpanWriter) indexByTags(span *model.Span, ds *dbmodel.Span) error {
 	return nil
 }
 
-func (s *SpanWriter) indexByDuration(span *dbmodel.Span, startTime time.Time) error {
-	query := s.session.Query(durationIndex)
+func (s *SpanWriter) indexByDuration(span *dbmodel.Span, startTime time.Time, ttl int) error {
+	query := s.session.Query(withTTL(durationIndex, ttl))
 	timeBucket := startTime.Round(durationBucketSize)
 	var err error
 	indexByOperationName := func(operationName string) {
-		q1 := query.Bind(span.Process.ServiceName, operationName, timeBucket, span.Duration, span.StartTime, span.TraceID)
+		q1 := query.Bind(bindTTL(ttl, span.Process.ServiceName, operationName, timeBucket, span.Duration, span.StartTime, span.TraceID)...)
 		if err2 := s.writerMetrics.durationIndex.Exec(q1, s.logger); err2 != nil {
 			s.logError(span, err2, "Cannot index duration", s.logger)
 			err = err2
@@ -236,21 +273,21 @@ func (s *SpanWriter) indexByDuration(span *dbmodel.Span, startTime time.Time) er
 	return err
 }
 
-func (s *SpanWriter) indexByService(span *dbmodel.Span) error {
+func (s *SpanWriter) indexByService(span *dbmodel.Span, ttl int) error {
 	bucketNo := uint64(span.SpanHash) % defaultNumBuckets
-	query := s.session.Query(serviceNameIndex)
-	q := query.Bind(span.Process.ServiceName, bucketNo, span.StartTime, span.TraceID)
+	query := s.session.Query(withTTL(serviceNameIndex, ttl))
+	q := query.Bind(bindTTL(ttl, span.Process.ServiceName, bucketNo, span.StartTime, span.TraceID)...)
 	return s.writerMetrics.serviceNameIndex.Exec(q, s.logger)
 }
 
-func (s *SpanWriter) indexByOperation(span *dbmodel.Span) error {
-	query := s.session.Query(serviceOperationIndex)
-	q := query.Bind(span.Process.ServiceName, span.OperationName, span.StartTime, span.TraceID)
+func (s *SpanWriter) indexByOperation(span *dbmodel.Span, ttl int) error {
+	query := s.session.Query(withTTL(serviceOperationIndex, ttl))
+	q := query.Bind(bindTTL(ttl, span.Process.ServiceName, span.OperationName, span.StartTime, span.TraceID)...)
 	return s.writerMetrics.serviceOperationIndex.Exec(q, s.logger)
 }
 
 // shouldIndexTag checks to see if the tag is json or not, if it's UTF8 valid and it's not too large
-func (s *SpanWriter) shouldIndexTag(tag dbmodel.TagInsertion) bool {
+func shouldIndexTag(tag dbmodel.TagInsertion) bool {
 	isJSON := func(s string) bool {
 		var js json.RawMessage
 		// poor man's string-is-a-json check shortcircuits full unmarshalling
@@ -15,6 +15,7 @@
 package spanstore
 
 import (
+	"github.com/jaegertracing/jaeger/pkg/retention"
 	"github.com/jaegertracing/jaeger/plugin/storage/cassandra/spanstore/dbmodel"
 )
 
@@ -26,6 +27,7 @@ type Options struct {
 	tagFilter   dbmodel.TagFilter
 	storageMode storageMode
 	indexFilter dbmodel.IndexFilter
+	retention   *retention.Policy
 }
 
 // TagFilter can be provided to filter any tags that should not be indexed.
@@ -56,6 +58,13 @@ func IndexFilter(indexFilter dbmodel.IndexFilter) Option {
 	}
 }
 
+// RetentionPolicy can be provided to set the TTL of the spans of some services, instead of the default TTL of the tables.
+func RetentionPolicy(policy *retention.Policy) Option {
+	return func(o *Options) {
+		o.retention = policy
+	}
+}
+
 func applyOptions(opts ...Option) Options {
 	o := Options{}
 	for _, opt := range opts {
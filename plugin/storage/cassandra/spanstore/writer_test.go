@@ -21,11 +21,14 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/uber/jaeger-lib/metrics/metricstest"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/cassandra/mocks"
+	"github.com/jaegertracing/jaeger/pkg/retention"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 	"github.com/jaegertracing/jaeger/plugin/storage/cassandra/spanstore/dbmodel"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
@@ -269,7 +272,7 @@ func TestSpanWriterSkippingTags(t *testing.T) {
 				TagKey:      testCase.key,
 				TagValue:    testCase.value,
 			}
-			ok := w.writer.shouldIndexTag(db)
+			ok := shouldIndexTag(db)
 			assert.Equal(t, testCase.insert, ok)
 		})
 	}
@@ -364,3 +367,62 @@ func TestStorageMode_StoreWithoutIndexing(t *testing.T) {
 		w.session.AssertNotCalled(t, "Query", stringMatcher(serviceNameIndex))
 	}, StoreWithoutIndexing())
 }
+
+func TestSpanWriterRetentionPolicy(t *testing.T) {
+	policy, err := retention.NewPolicy([]retention.Rule{
+		{Name: "payments", Services: []string{"payments"}, TTL: 720 * time.Hour},
+	})
+	require.NoError(t, err)
+	testCases := []struct {
+		service string
+		ttl     interface{}
+	}{
+		{service: "payments", ttl: 2592000},
+		{service: "frontend"},
+	}
+	for _, tc := range testCases {
+		testCase := tc // capture loop var
+		t.Run(testCase.service, func(t *testing.T) {
+			withSpanWriter(0, func(w *spanWriterTest) {
+				w.writer.serviceNamesWriter = func(serviceName string) error { return nil }
+				w.writer.operationNamesWriter = func(serviceName, operationName string) error { return nil }
+				var statements []string
+				var values [][]interface{}
+				query := &mocks.Query{}
+				query.On("Bind", matchEverything()).Run(func(args mock.Arguments) {
+					values = append(values, args.Get(0).([]interface{}))
+				}).Return(query)
+				query.On("Exec").Return(nil)
+				w.session.On("Query", mock.AnythingOfType("string"), matchEverything()).Run(func(args mock.Arguments) {
+					statements = append(statements, args.String(0))
+					if boundValues := args.Get(1).([]interface{}); len(boundValues) > 0 {
+						values = append(values, boundValues)
+					}
+				}).Return(query)
+
+				span := &model.Span{
+					TraceID:       model.NewTraceID(0, 1),
+					OperationName: "operation",
+					Tags:          model.KeyValues{model.String("x", "y")},
+					Process:       &model.Process{ServiceName: testCase.service},
+				}
+				require.NoError(t, w.writer.WriteSpan(span))
+
+				// span, tag, service name, service operation and duration inserts, the duration being bound twice
+				assert.Len(t, statements, 5)
+				assert.Len(t, values, 6)
+				for _, statement := range statements {
+					assert.Equal(t, testCase.ttl != nil, strings.HasSuffix(statement, usingTTL), statement)
+				}
+				for i, expectedLen := range []int{12, 6, 4, 4, 6, 6} {
+					if testCase.ttl != nil {
+						assert.Len(t, values[i], expectedLen+1)
+						assert.Equal(t, testCase.ttl, values[i][expectedLen])
+					} else {
+						assert.Len(t, values[i], expectedLen)
+					}
+				}
+			}, RetentionPolicy(policy))
+		})
+	}
+}
@@ -29,6 +29,8 @@ import (
 
 	"github.com/jaegertracing/jaeger/pkg/es"
 	"github.com/jaegertracing/jaeger/pkg/es/config"
+	"github.com/jaegertracing/jaeger/pkg/retention"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	esDepStore "github.com/jaegertracing/jaeger/plugin/storage/es/dependencystore"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/mappings"
 	esSpanStore "github.com/jaegertracing/jaeger/plugin/storage/es/spanstore"
@@ -52,6 +54,8 @@ type Factory struct {
 	primaryClient es.Client
 	archiveConfig config.ClientBuilder
 	archiveClient es.Client
+
+	retentionPolicy *retention.Policy
 }
 
 // NewFactory creates a new Factory.
@@ -77,6 +81,15 @@ func (f *Factory) InitFromViper(v *viper.Viper) {
 func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger) error {
 	f.metricsFactory, f.logger = metricsFactory, logger
 
+	retentionPolicy, err := retention.LoadPolicyFile(f.primaryConfig.GetRetentionPolicyFile())
+	if err != nil {
+		return err
+	}
+	if len(retentionPolicy.Rules()) > 0 && f.primaryConfig.GetUseReadWriteAliases() {
+		return errors.New("retention policies require daily indices and cannot be used with read/write aliases")
+	}
+	f.retentionPolicy = retentionPolicy
+
 	primaryClient, err := f.primaryConfig.NewClient(logger, metricsFactory)
 	if err != nil {
 		return errors.Wrap(err, "failed to create primary Elasticsearch client")
@@ -93,12 +106,21 @@ func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger)
 
 // CreateSpanReader implements storage.Factory
 func (f *Factory) CreateSpanReader() (spanstore.Reader, error) {
-	return createSpanReader(f.metricsFactory, f.logger, f.primaryClient, f.primaryConfig, false)
+	return createSpanReader(f.metricsFactory, f.logger, f.primaryClient, f.primaryConfig, f.primaryConfig.GetIndexPrefix(), f.retentionPolicy, false)
 }
 
 // CreateSpanWriter implements storage.Factory
 func (f *Factory) CreateSpanWriter() (spanstore.Writer, error) {
-	return createSpanWriter(f.metricsFactory, f.logger, f.primaryClient, f.primaryConfig, false)
+	return createSpanWriter(f.metricsFactory, f.logger, f.primaryClient, f.primaryConfig, f.primaryConfig.GetIndexPrefix(), f.retentionPolicy, false)
+}
+
+// CreateSpanDeleter implements storage.DeleterFactory
+func (f *Factory) CreateSpanDeleter() (spanstore.Deleter, error) {
+	reader, err := createSpanReader(f.metricsFactory, f.logger, f.primaryClient, f.primaryConfig, f.primaryConfig.GetIndexPrefix(), f.retentionPolicy, false)
+	if err != nil {
+		return nil, err
+	}
+	return esSpanStore.NewSpanDeleter(f.primaryClient, reader, f.logger), nil
 }
 
 // CreateDependencyReader implements storage.Factory
@@ -106,6 +128,63 @@ func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
 	return esDepStore.NewDependencyStore(f.primaryClient, f.logger, f.primaryConfig.GetIndexPrefix()), nil
 }
 
+// CreateDependencyWriter implements storage.DependencyWriterFactory
+func (f *Factory) CreateDependencyWriter() (dependencystore.Writer, error) {
+	return esDepStore.NewDependencyStore(f.primaryClient, f.logger, f.primaryConfig.GetIndexPrefix()), nil
+}
+
+// CreateTenantSpanReader implements storage.TenantFactory
+func (f *Factory) CreateTenantSpanReader(tenant string) (spanstore.Reader, error) {
+	indexPrefix, err := tenantIndexPrefix(f.primaryConfig.GetIndexPrefix(), tenant)
+	if err != nil {
+		return nil, err
+	}
+	return createSpanReader(f.metricsFactory, f.logger, f.primaryClient, f.primaryConfig, indexPrefix, f.retentionPolicy, false)
+}
+
+// CreateTenantSpanWriter implements storage.TenantFactory
+func (f *Factory) CreateTenantSpanWriter(tenant string) (spanstore.Writer, error) {
+	indexPrefix, err := tenantIndexPrefix(f.primaryConfig.GetIndexPrefix(), tenant)
+	if err != nil {
+		return nil, err
+	}
+	return createSpanWriter(f.metricsFactory, f.logger, f.primaryClient, f.primaryConfig, indexPrefix, f.retentionPolicy, false)
+}
+
+// CreateTenantDependencyReader implements storage.TenantFactory
+func (f *Factory) CreateTenantDependencyReader(tenant string) (dependencystore.Reader, error) {
+	indexPrefix, err := tenantIndexPrefix(f.primaryConfig.GetIndexPrefix(), tenant)
+	if err != nil {
+		return nil, err
+	}
+	return esDepStore.NewDependencyStore(f.primaryClient, f.logger, indexPrefix), nil
+}
+
+// CreateTenantSpanDeleter implements storage.TenantFactory
+func (f *Factory) CreateTenantSpanDeleter(tenant string) (spanstore.Deleter, error) {
+	indexPrefix, err := tenantIndexPrefix(f.primaryConfig.GetIndexPrefix(), tenant)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := createSpanReader(f.metricsFactory, f.logger, f.primaryClient, f.primaryConfig, indexPrefix, f.retentionPolicy, false)
+	if err != nil {
+		return nil, err
+	}
+	return esSpanStore.NewSpanDeleter(f.primaryClient, reader, f.logger), nil
+}
+
+// tenantIndexPrefix returns the prefix of the indices holding the data of the tenant,
+// e.g. "<prefix>-<tenant>-jaeger-span-2019-06-01".
+func tenantIndexPrefix(indexPrefix, tenant string) (string, error) {
+	if err := tenancy.ValidateName(tenant); err != nil {
+		return "", err
+	}
+	if indexPrefix == "" {
+		return tenant, nil
+	}
+	return indexPrefix + "-" + tenant, nil
+}
+
 func loadTagsFromFile(filePath string) ([]string, error) {
 	file, err := os.Open(filepath.Clean(filePath))
 	if err != nil {
@@ -130,7 +209,7 @@ func (f *Factory) CreateArchiveSpanReader() (spanstore.Reader, error) {
 	if !cfg.Enabled {
 		return nil, nil
 	}
-	return createSpanReader(f.metricsFactory, f.logger, f.archiveClient, cfg, true)
+	return createSpanReader(f.metricsFactory, f.logger, f.archiveClient, cfg, cfg.GetIndexPrefix(), nil, true)
 }
 
 // CreateArchiveSpanWriter implements storage.ArchiveFactory
@@ -139,7 +218,7 @@ func (f *Factory) CreateArchiveSpanWriter() (spanstore.Writer, error) {
 	if !cfg.Enabled {
 		return nil, nil
 	}
-	return createSpanWriter(f.metricsFactory, f.logger, f.archiveClient, cfg, true)
+	return createSpanWriter(f.metricsFactory, f.logger, f.archiveClient, cfg, cfg.GetIndexPrefix(), nil, true)
 }
 
 func createSpanReader(
@@ -147,18 +226,21 @@ func createSpanReader(
 	logger *zap.Logger,
 	client es.Client,
 	cfg config.ClientBuilder,
+	indexPrefix string,
+	retentionPolicy *retention.Policy,
 	archive bool,
-) (spanstore.Reader, error) {
+) (*esSpanStore.SpanReader, error) {
 	return esSpanStore.NewSpanReader(esSpanStore.SpanReaderParams{
 		Client:              client,
 		Logger:              logger,
 		MetricsFactory:      mFactory,
 		MaxNumSpans:         cfg.GetMaxNumSpans(),
 		MaxSpanAge:          cfg.GetMaxSpanAge(),
-		IndexPrefix:         cfg.GetIndexPrefix(),
+		IndexPrefix:         indexPrefix,
 		TagDotReplacement:   cfg.GetTagDotReplacement(),
 		UseReadWriteAliases: cfg.GetUseReadWriteAliases(),
 		Archive:             archive,
+		RetentionPolicy:     retentionPolicy,
 	}), nil
 }
 
@@ -167,6 +249,8 @@ func createSpanWriter(
 	logger *zap.Logger,
 	client es.Client,
 	cfg config.ClientBuilder,
+	indexPrefix string,
+	retentionPolicy *retention.Policy,
 	archive bool,
 ) (spanstore.Writer, error) {
 	var tags []string
@@ -183,7 +267,7 @@ func createSpanWriter(
 		Client:              client,
 		Logger:              logger,
 		MetricsFactory:      mFactory,
-		IndexPrefix:         cfg.GetIndexPrefix(),
+		IndexPrefix:         indexPrefix,
 		AllTagsAsFields:     cfg.GetAllTagsAsFields(),
 		TagKeysAsFields:     tags,
 		TagDotReplacement:   cfg.GetTagDotReplacement(),
@@ -191,6 +275,7 @@ func createSpanWriter(
 		UseReadWriteAliases: cfg.GetUseReadWriteAliases(),
 		SpanMapping:         spanMapping,
 		ServiceMapping:      serviceMapping,
+		RetentionPolicy:     retentionPolicy,
 	}), nil
 }
 
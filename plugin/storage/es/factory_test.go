@@ -35,6 +35,7 @@ import (
 )
 
 var _ storage.Factory = new(Factory)
+var _ storage.TenantFactory = new(Factory)
 
 type mockClientBuilder struct {
 	escfg.Configuration
@@ -72,9 +73,15 @@ func TestElasticsearchFactory(t *testing.T) {
 	_, err = f.CreateSpanWriter()
 	assert.NoError(t, err)
 
+	_, err = f.CreateSpanDeleter()
+	assert.NoError(t, err)
+
 	_, err = f.CreateDependencyReader()
 	assert.NoError(t, err)
 
+	_, err = f.CreateDependencyWriter()
+	assert.NoError(t, err)
+
 	_, err = f.CreateArchiveSpanReader()
 	assert.NoError(t, err)
 
@@ -174,3 +181,74 @@ func TestArchiveEnabled(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotNil(t, r)
 }
+
+func TestTenantStores(t *testing.T) {
+	f := NewFactory()
+	f.primaryConfig = &mockClientBuilder{}
+	f.archiveConfig = &mockClientBuilder{}
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+
+	r, err := f.CreateTenantSpanReader("acme")
+	require.NoError(t, err)
+	assert.NotNil(t, r)
+	w, err := f.CreateTenantSpanWriter("acme")
+	require.NoError(t, err)
+	assert.NotNil(t, w)
+	d, err := f.CreateTenantDependencyReader("acme")
+	require.NoError(t, err)
+	assert.NotNil(t, d)
+	del, err := f.CreateTenantSpanDeleter("acme")
+	require.NoError(t, err)
+	assert.NotNil(t, del)
+
+	_, err = f.CreateTenantSpanReader("../acme")
+	assert.Error(t, err)
+	_, err = f.CreateTenantSpanWriter("")
+	assert.Error(t, err)
+	_, err = f.CreateTenantDependencyReader("ACME")
+	assert.Error(t, err)
+	_, err = f.CreateTenantSpanDeleter("ACME")
+	assert.Error(t, err)
+}
+
+func TestElasticsearchFactoryRetentionPolicy(t *testing.T) {
+	f := NewFactory()
+	mockConf := &mockClientBuilder{}
+	mockConf.RetentionPolicyFile = "fixtures/retention_policy_foo.yaml"
+	f.primaryConfig = mockConf
+	f.archiveConfig = &mockClientBuilder{}
+	assert.Error(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+
+	mockConf.RetentionPolicyFile = "../../../pkg/retention/fixtures/retention_policy.yaml"
+	mockConf.UseReadWriteAliases = true
+	assert.EqualError(t, f.Initialize(metrics.NullFactory, zap.NewNop()),
+		"retention policies require daily indices and cannot be used with read/write aliases")
+
+	mockConf.UseReadWriteAliases = false
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+	assert.Len(t, f.retentionPolicy.Rules(), 2)
+	w, err := f.CreateSpanWriter()
+	require.NoError(t, err)
+	assert.NotNil(t, w)
+	r, err := f.CreateSpanReader()
+	require.NoError(t, err)
+	assert.NotNil(t, r)
+}
+
+func TestTenantIndexPrefix(t *testing.T) {
+	testCases := []struct {
+		indexPrefix string
+		tenant      string
+		expected    string
+	}{
+		{indexPrefix: "", tenant: "acme", expected: "acme"},
+		{indexPrefix: "prod", tenant: "acme", expected: "prod-acme"},
+	}
+	for _, testCase := range testCases {
+		prefix, err := tenantIndexPrefix(testCase.indexPrefix, testCase.tenant)
+		require.NoError(t, err)
+		assert.Equal(t, testCase.expected, prefix)
+	}
+	_, err := tenantIndexPrefix("prod", "a-b")
+	assert.Error(t, err)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/jaegertracing/jaeger/pkg/es/config"
+	"github.com/jaegertracing/jaeger/pkg/retention"
 )
 
 const (
@@ -218,6 +219,10 @@ func addFlags(flagSet *flag.FlagSet, nsConfig *namespaceConfig) {
 			nsConfig.namespace+suffixEnabled,
 			nsConfig.Enabled,
 			"Enable extra storage")
+	} else {
+		// the spans of the services with a retention rule are written to daily indices per rule,
+		// e.g. "jaeger-span-payments-2019-06-01", deleted by the index manager after the TTL of the rule
+		retention.AddFlags(flagSet, nsConfig.namespace)
 	}
 }
 
@@ -255,6 +260,7 @@ func initFromViper(cfg *namespaceConfig, v *viper.Viper) {
 	cfg.TagDotReplacement = v.GetString(cfg.namespace + suffixTagDeDotChar)
 	cfg.UseReadWriteAliases = v.GetBool(cfg.namespace + suffixReadAlias)
 	cfg.Enabled = v.GetBool(cfg.namespace + suffixEnabled)
+	cfg.RetentionPolicyFile = retention.PolicyFileFromViper(v, cfg.namespace)
 }
 
 // GetPrimary returns primary configuration.
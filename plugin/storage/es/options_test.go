@@ -58,6 +58,7 @@ func TestOptionsWithFlags(t *testing.T) {
 		"--es.aux.num-replicas=10",
 		"--es.tls=true",
 		"--es.tls.skip-host-verify=true",
+		"--es.retention-policy-file=/foo/retention.yaml",
 	})
 	opts.InitFromViper(v)
 
@@ -69,6 +70,7 @@ func TestOptionsWithFlags(t *testing.T) {
 	assert.True(t, primary.Sniffer)
 	assert.Equal(t, true, primary.TLS.Enabled)
 	assert.Equal(t, true, primary.TLS.SkipHostVerify)
+	assert.Equal(t, "/foo/retention.yaml", primary.RetentionPolicyFile)
 
 	aux := opts.Get("es.aux")
 	assert.Equal(t, []string{"3.3.3.3", "4.4.4.4"}, aux.Servers)
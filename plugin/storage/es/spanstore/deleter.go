@@ -0,0 +1,70 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"gopkg.in/olivere/elastic.v5"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/es"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// SpanDeleter deletes the spans of traces from ElasticSearch. The span documents are the only
+// index of a trace, the service indices are shared by all traces of a service and are kept.
+type SpanDeleter struct {
+	client es.Client
+	reader *SpanReader
+	logger *zap.Logger
+}
+
+// NewSpanDeleter returns a SpanDeleter deleting the spans from the indices read by the reader
+func NewSpanDeleter(client es.Client, reader *SpanReader, logger *zap.Logger) *SpanDeleter {
+	return &SpanDeleter{
+		client: client,
+		reader: reader,
+		logger: logger,
+	}
+}
+
+// DeleteTrace implements spanstore.Deleter
+func (d *SpanDeleter) DeleteTrace(ctx context.Context, traceID model.TraceID) error {
+	currentTime := time.Now()
+	// same range as GetTrace, including traces that straddle two indices
+	indices := d.reader.timeRangeIndices(d.reader.spanIndexPrefix, currentTime.Add(-d.reader.maxSpanAge).Add(-time.Hour), currentTime.Add(time.Hour))
+	response, err := d.client.DeleteByQuery(indices...).
+		Query(elastic.NewTermQuery(traceIDField, traceID.String())).
+		IgnoreUnavailable(true).
+		ProceedOnVersionConflict().
+		// the deleted spans must not be found by the next search
+		Refresh("true").
+		Do(ctx)
+	if err != nil {
+		d.logger.Error("Failed to delete spans", zap.String("trace_id", traceID.String()), zap.Error(err))
+		return errors.Wrap(err, "Failed to delete spans")
+	}
+	d.logger.Debug("Deleted spans", zap.String("trace_id", traceID.String()), zap.Int64("spans", response.Deleted))
+	return nil
+}
+
+// DeleteServiceTraces implements spanstore.Deleter
+func (d *SpanDeleter) DeleteServiceTraces(ctx context.Context, service string, startTimeMin, startTimeMax time.Time) ([]model.TraceID, error) {
+	return spanstore.DeleteFoundTraces(ctx, d.reader, service, startTimeMin, startTimeMax, d.DeleteTrace)
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"gopkg.in/olivere/elastic.v5"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/es/mocks"
+	"github.com/jaegertracing/jaeger/pkg/testutils"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+var _ spanstore.Deleter = &SpanDeleter{} // check API conformance
+
+func TestSpanDeleterDeleteTrace(t *testing.T) {
+	testCases := []struct {
+		caption       string
+		err           error
+		expectedError string
+		expectedLog   string
+	}{
+		{
+			caption: "deleted",
+		},
+		{
+			caption:       "delete error",
+			err:           errors.New("delete error"),
+			expectedError: "Failed to delete spans: delete error",
+			expectedLog:   `"msg":"Failed to delete spans","trace_id":"1","error":"delete error"`,
+		},
+	}
+	for _, tc := range testCases {
+		testCase := tc // capture loop var
+		t.Run(testCase.caption, func(t *testing.T) {
+			client := &mocks.Client{}
+			logger, logBuffer := testutils.NewLogger()
+			reader := NewSpanReader(SpanReaderParams{
+				Client:              client,
+				Logger:              zap.NewNop(),
+				IndexPrefix:         "prefix",
+				UseReadWriteAliases: true,
+			})
+			deleter := NewSpanDeleter(client, reader, logger)
+
+			deleteService := &mocks.DeleteByQueryService{}
+			deleteService.On("Query", elastic.NewTermQuery(traceIDField, "1")).Return(deleteService)
+			deleteService.On("IgnoreUnavailable", true).Return(deleteService)
+			deleteService.On("ProceedOnVersionConflict").Return(deleteService)
+			deleteService.On("Refresh", "true").Return(deleteService)
+			deleteService.On("Do", mock.Anything).Return(&elastic.BulkIndexByScrollResponse{Deleted: 2}, testCase.err)
+			client.On("DeleteByQuery", "prefix-jaeger-span-read").Return(deleteService)
+
+			err := deleter.DeleteTrace(context.Background(), model.NewTraceID(0, 1))
+			if testCase.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, testCase.expectedError)
+			}
+			assert.Contains(t, logBuffer.String(), testCase.expectedLog)
+			deleteService.AssertExpectations(t)
+		})
+	}
+}
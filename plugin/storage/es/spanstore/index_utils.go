@@ -24,6 +24,12 @@ func indexWithDate(indexPrefix string, date time.Time) string {
 	return indexPrefix + spanDate
 }
 
+// returns the name of the daily span index holding the spans of the services with a retention rule,
+// e.g. "jaeger-span-payments-2019-06-01"
+func retentionIndexWithDate(spanIndexPrefix, ruleName string, date time.Time) string {
+	return indexWithDate(spanIndexPrefix+ruleName+indexPrefixSeparator, date)
+}
+
 // returns archive index name
 func archiveIndex(indexPrefix, archiveSuffix string) string {
 	return indexPrefix + archiveSuffix
@@ -31,6 +31,7 @@ import (
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/es"
+	"github.com/jaegertracing/jaeger/pkg/retention"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/spanstore/dbmodel"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
@@ -59,6 +60,9 @@ const (
 
 	defaultDocCount  = 10000 // the default elasticsearch allowed limit
 	defaultNumTraces = 100
+	// maxTraceIDBuckets is the default elasticsearch search.max_buckets limit, which the trace IDs aggregation
+	// must stay under since it returns the buckets of the previous pages as well
+	maxTraceIDBuckets = 10000
 )
 
 var (
@@ -80,6 +84,9 @@ var (
 	// ErrUnableToFindTraceIDAggregation occurs when an aggregation query for TraceIDs fail.
 	ErrUnableToFindTraceIDAggregation = errors.New("Could not find aggregation of traceIDs")
 
+	// ErrTooManyTraces occurs when the traces of a page and of the pages before it cannot be aggregated at once
+	ErrTooManyTraces = fmt.Errorf("Cannot page through more than %d traces, narrow the search", maxTraceIDBuckets)
+
 	defaultMaxDuration = model.DurationAsMicroseconds(time.Hour * 24)
 
 	objectTagFieldList = []string{objectTagsField, objectProcessTagsField}
@@ -114,18 +121,26 @@ type SpanReaderParams struct {
 	TagDotReplacement   string
 	Archive             bool
 	UseReadWriteAliases bool
+	// RetentionPolicy is only supported with daily indices, it is ignored for the archive and with aliases
+	RetentionPolicy *retention.Policy
 }
 
 // NewSpanReader returns a new SpanReader with a metrics.
 func NewSpanReader(p SpanReaderParams) *SpanReader {
 	ctx := context.Background()
+	spanIndexPrefix := indexNames(p.IndexPrefix, spanIndex)
+	if !p.Archive && !p.UseReadWriteAliases && len(p.RetentionPolicy.Rules()) > 0 {
+		// the pattern matches the daily span indices with and without a retention rule,
+		// e.g. "jaeger-span-*2019-06-01" matches "jaeger-span-2019-06-01" and "jaeger-span-payments-2019-06-01"
+		spanIndexPrefix += "*"
+	}
 	return &SpanReader{
 		ctx:                     ctx,
 		client:                  p.Client,
 		logger:                  p.Logger,
 		maxSpanAge:              p.MaxSpanAge,
 		serviceOperationStorage: NewServiceOperationStorage(ctx, p.Client, p.Logger, 0), // the decorator takes care of metrics
-		spanIndexPrefix:         indexNames(p.IndexPrefix, spanIndex),
+		spanIndexPrefix:         spanIndexPrefix,
 		serviceIndexPrefix:      indexNames(p.IndexPrefix, serviceIndex),
 		spanConverter:           dbmodel.NewToDomain(p.TagDotReplacement),
 		timeRangeIndices:        getTimeRangeIndexFn(p.Archive, p.UseReadWriteAliases),
@@ -290,7 +305,13 @@ func (s *SpanReader) FindTraceIDs(ctx context.Context, traceQuery *spanstore.Tra
 		return nil, err
 	}
 	if traceQuery.NumTraces == 0 {
-		traceQuery.NumTraces = defaultNumTraces
+		// default a copy, so that NextContinuationToken sees the caller's query unchanged
+		query := *traceQuery
+		query.NumTraces = defaultNumTraces
+		traceQuery = &query
+	}
+	if traceQuery.Offset()+traceQuery.NumTraces > maxTraceIDBuckets {
+		return nil, ErrTooManyTraces
 	}
 
 	esTraceIDs, err := s.findTraceIDs(ctx, traceQuery)
@@ -301,6 +322,40 @@ func (s *SpanReader) FindTraceIDs(ctx context.Context, traceQuery *spanstore.Tra
 	return convertTraceIDsStringsToModels(esTraceIDs)
 }
 
+// FindSpans retrieves the individual spans that match the traceQuery, the most recent first
+func (s *SpanReader) FindSpans(ctx context.Context, traceQuery *spanstore.TraceQueryParameters) ([]*model.Span, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "FindSpans")
+	defer span.Finish()
+
+	if err := validateQuery(traceQuery); err != nil {
+		return nil, err
+	}
+	numSpans := traceQuery.NumTraces
+	if numSpans == 0 {
+		numSpans = defaultNumTraces
+	}
+	if numSpans > defaultDocCount {
+		numSpans = defaultDocCount
+	}
+
+	jaegerIndices := s.timeRangeIndices(s.spanIndexPrefix, traceQuery.StartTimeMin, traceQuery.StartTimeMax)
+	searchResult, err := s.client.Search(jaegerIndices...).
+		Type(spanType).
+		Size(numSpans).
+		Sort(startTimeField, false).
+		IgnoreUnavailable(true).
+		Query(s.buildFindTraceIDsQuery(traceQuery)).
+		Do(s.ctx)
+	if err != nil {
+		logErrorToSpan(span, err)
+		return nil, errors.Wrap(err, "Search service failed")
+	}
+	if searchResult.Hits == nil {
+		return []*model.Span{}, nil
+	}
+	return s.collectSpans(searchResult.Hits.Hits)
+}
+
 func (s *SpanReader) multiRead(ctx context.Context, traceIDs []model.TraceID, startTime, endTime time.Time) ([]*model.Trace, error) {
 
 	childSpan, _ := opentracing.StartSpanFromContext(ctx, "multiRead")
@@ -413,6 +468,20 @@ func validateQuery(p *spanstore.TraceQueryParameters) error {
 	if p.DurationMin != 0 && p.DurationMax != 0 && p.DurationMin > p.DurationMax {
 		return ErrDurationMinGreaterThanMax
 	}
+	for _, filter := range p.TagFilters {
+		if err := filter.Validate(); err != nil {
+			return err
+		}
+		// the tag values are indexed as keywords, they cannot be compared as numbers
+		if filter.Operator == spanstore.TagFilterGreaterThan || filter.Operator == spanstore.TagFilterLessThan {
+			return errors.Wrapf(spanstore.ErrTagFilterNotSupported, "%q", filter.String())
+		}
+		if filter.Operator == spanstore.TagFilterRegex {
+			if _, err := luceneRegexp(filter.Value); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -472,7 +541,8 @@ func (s *SpanReader) findTraceIDs(ctx context.Context, traceQuery *spanstore.Tra
 	//      },
 	//      "aggs": { "traceIDs" : { "terms" : {"size": 100,"field": "traceID" }}}
 	//  }
-	aggregation := s.buildTraceIDAggregation(traceQuery.NumTraces)
+	// The buckets of the previous pages are fetched as well and skipped below
+	aggregation := s.buildTraceIDAggregation(traceQuery.Offset() + traceQuery.NumTraces)
 	boolQuery := s.buildFindTraceIDsQuery(traceQuery)
 
 	jaegerIndices := s.timeRangeIndices(s.spanIndexPrefix, traceQuery.StartTimeMin, traceQuery.StartTimeMax)
@@ -497,6 +567,11 @@ func (s *SpanReader) findTraceIDs(ctx context.Context, traceQuery *spanstore.Tra
 	}
 
 	traceIDBuckets := bucket.Buckets
+	if offset := traceQuery.Offset(); offset < len(traceIDBuckets) {
+		traceIDBuckets = traceIDBuckets[offset:]
+	} else {
+		traceIDBuckets = nil
+	}
 	return bucketToStringArray(traceIDBuckets)
 }
 
@@ -505,6 +580,7 @@ func (s *SpanReader) buildTraceIDAggregation(numOfTraces int) elastic.Aggregatio
 		Size(numOfTraces).
 		Field(traceIDField).
 		Order(startTimeField, false).
+		OrderByTerm(false). // break the ties so that the results can be paged through
 		SubAggregation(startTimeField, s.buildTraceIDSubAggregation())
 }
 
@@ -542,6 +618,21 @@ func (s *SpanReader) buildFindTraceIDsQuery(traceQuery *spanstore.TraceQueryPara
 		tagQuery := s.buildTagQuery(k, v)
 		boolQuery.Must(tagQuery)
 	}
+
+	// the operators were checked by validateQuery
+	for _, filter := range traceQuery.TagFilters {
+		switch filter.Operator {
+		case spanstore.TagFilterEqual:
+			boolQuery.Must(s.buildTagQuery(filter.Key, filter.Value))
+		case spanstore.TagFilterNotEqual:
+			boolQuery.MustNot(s.buildTagQuery(filter.Key, filter.Value))
+		case spanstore.TagFilterRegex:
+			regex, _ := luceneRegexp(filter.Value)
+			boolQuery.Must(s.buildTagRegexQuery(filter.Key, regex))
+		case spanstore.TagFilterExists:
+			boolQuery.Must(s.buildTagExistsQuery(filter.Key))
+		}
+	}
 	return boolQuery
 }
 
@@ -569,33 +660,56 @@ func (s *SpanReader) buildOperationNameQuery(operationName string) elastic.Query
 }
 
 func (s *SpanReader) buildTagQuery(k string, v string) elastic.Query {
+	return s.buildTagValueQuery(k, func(field string) elastic.Query {
+		return elastic.NewMatchQuery(field, v)
+	})
+}
+
+// buildTagRegexQuery matches the tags whose value matches the regular expression in the Lucene syntax
+func (s *SpanReader) buildTagRegexQuery(k string, regex string) elastic.Query {
+	return s.buildTagValueQuery(k, func(field string) elastic.Query {
+		return elastic.NewRegexpQuery(field, regex)
+	})
+}
+
+func (s *SpanReader) buildTagExistsQuery(k string) elastic.Query {
+	return s.buildTagValueQuery(k, nil)
+}
+
+// buildTagValueQuery looks for the tag in all the tag fields, valueQuery builds the query on the tag value field
+// or is nil if any value matches
+func (s *SpanReader) buildTagValueQuery(k string, valueQuery func(field string) elastic.Query) elastic.Query {
 	objectTagListLen := len(objectTagFieldList)
 	queries := make([]elastic.Query, len(nestedTagFieldList)+objectTagListLen)
 	kd := s.spanConverter.ReplaceDot(k)
 	for i := range objectTagFieldList {
-		queries[i] = s.buildObjectQuery(objectTagFieldList[i], kd, v)
+		queries[i] = s.buildObjectQuery(objectTagFieldList[i], kd, valueQuery)
 	}
 	for i := range nestedTagFieldList {
-		queries[i+objectTagListLen] = s.buildNestedQuery(nestedTagFieldList[i], k, v)
+		queries[i+objectTagListLen] = s.buildNestedQuery(nestedTagFieldList[i], k, valueQuery)
 	}
 
 	// but configuration can change over time
 	return elastic.NewBoolQuery().Should(queries...)
 }
 
-func (s *SpanReader) buildNestedQuery(field string, k string, v string) elastic.Query {
+func (s *SpanReader) buildNestedQuery(field string, k string, valueQuery func(field string) elastic.Query) elastic.Query {
 	keyField := fmt.Sprintf("%s.%s", field, tagKeyField)
-	valueField := fmt.Sprintf("%s.%s", field, tagValueField)
 	keyQuery := elastic.NewMatchQuery(keyField, k)
-	valueQuery := elastic.NewMatchQuery(valueField, v)
-	tagBoolQuery := elastic.NewBoolQuery().Must(keyQuery, valueQuery)
+	tagBoolQuery := elastic.NewBoolQuery().Must(keyQuery)
+	if valueQuery != nil {
+		valueField := fmt.Sprintf("%s.%s", field, tagValueField)
+		tagBoolQuery.Must(valueQuery(valueField))
+	}
 	return elastic.NewNestedQuery(field, tagBoolQuery)
 }
 
-func (s *SpanReader) buildObjectQuery(field string, k string, v string) elastic.Query {
+func (s *SpanReader) buildObjectQuery(field string, k string, valueQuery func(field string) elastic.Query) elastic.Query {
 	keyField := fmt.Sprintf("%s.%s", field, k)
-	keyQuery := elastic.NewMatchQuery(keyField, v)
-	return elastic.NewBoolQuery().Must(keyQuery)
+	if valueQuery == nil {
+		return elastic.NewBoolQuery().Must(elastic.NewExistsQuery(keyField))
+	}
+	return elastic.NewBoolQuery().Must(valueQuery(keyField))
 }
 
 func logErrorToSpan(span opentracing.Span, err error) {
@@ -33,6 +33,7 @@ import (
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/es/mocks"
+	"github.com/jaegertracing/jaeger/pkg/retention"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/spanstore/dbmodel"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
@@ -176,6 +177,36 @@ func TestSpanReaderIndices(t *testing.T) {
 	}
 }
 
+func TestSpanReaderRetentionPolicyIndices(t *testing.T) {
+	policy, err := retention.NewPolicy([]retention.Rule{
+		{Name: "payments", Services: []string{"payments"}, TTL: 720 * time.Hour},
+	})
+	require.NoError(t, err)
+	date := time.Now()
+	dateFormat := date.UTC().Format("2006-01-02")
+	testCases := []struct {
+		params SpanReaderParams
+		index  string
+	}{
+		{params: SpanReaderParams{RetentionPolicy: policy},
+			index: spanIndex + "*" + dateFormat},
+		{params: SpanReaderParams{RetentionPolicy: policy, IndexPrefix: "foo"},
+			index: "foo" + indexPrefixSeparator + spanIndex + "*" + dateFormat},
+		{params: SpanReaderParams{RetentionPolicy: &retention.Policy{}},
+			index: spanIndex + dateFormat},
+		{params: SpanReaderParams{RetentionPolicy: policy, UseReadWriteAliases: true},
+			index: spanIndex + "read"},
+		{params: SpanReaderParams{RetentionPolicy: policy, Archive: true},
+			index: spanIndex + archiveIndexSuffix},
+	}
+	for _, testCase := range testCases {
+		testCase.params.Client, testCase.params.Logger, testCase.params.MetricsFactory = &mocks.Client{}, zap.NewNop(), metrics.NullFactory
+		r := NewSpanReader(testCase.params)
+		actual := r.timeRangeIndices(r.spanIndexPrefix, date, date)
+		assert.Equal(t, []string{testCase.index}, actual)
+	}
+}
+
 func TestSpanReader_GetTrace(t *testing.T) {
 	withSpanReader(func(r *spanReaderTest) {
 		hits := make([]*elastic.SearchHit, 1)
@@ -530,6 +561,124 @@ func TestSpanReader_FindTraces(t *testing.T) {
 	})
 }
 
+func TestSpanReader_FindTraceIDsWithContinuationToken(t *testing.T) {
+	goodAggregations := make(map[string]*json.RawMessage)
+	rawMessage := []byte(`{"buckets": [{"key": "1","doc_count": 16},{"key": "2","doc_count": 16},{"key": "3","doc_count": 16}]}`)
+	goodAggregations[traceIDAggregation] = (*json.RawMessage)(&rawMessage)
+
+	testCases := []struct {
+		offset   int
+		expected []string
+	}{
+		{offset: 0, expected: []string{"1", "2", "3"}},
+		{offset: 2, expected: []string{"3"}},
+		{offset: 3, expected: []string{}},
+	}
+	for _, testCase := range testCases {
+		withSpanReader(func(r *spanReaderTest) {
+			mockSearchService(r).
+				Return(&elastic.SearchResult{Aggregations: elastic.Aggregations(goodAggregations)}, nil)
+
+			traceQuery := &spanstore.TraceQueryParameters{
+				ServiceName:  serviceName,
+				StartTimeMin: time.Now().Add(-1 * time.Hour),
+				StartTimeMax: time.Now(),
+				NumTraces:    3,
+			}
+			if testCase.offset > 0 {
+				traceQuery.ContinuationToken = &spanstore.ContinuationToken{
+					StartTimeMax: traceQuery.StartTimeMax,
+					Offset:       testCase.offset,
+				}
+			}
+
+			traceIDs, err := r.reader.FindTraceIDs(context.Background(), traceQuery)
+			require.NoError(t, err)
+			actual := make([]string, 0, len(traceIDs))
+			for _, traceID := range traceIDs {
+				actual = append(actual, traceID.String())
+			}
+			assert.Equal(t, testCase.expected, actual)
+		})
+	}
+}
+
+func TestSpanReader_FindTraceIDsTooManyTraces(t *testing.T) {
+	withSpanReader(func(r *spanReaderTest) {
+		traceQuery := &spanstore.TraceQueryParameters{
+			ServiceName:  serviceName,
+			StartTimeMin: time.Now().Add(-1 * time.Hour),
+			StartTimeMax: time.Now(),
+			ContinuationToken: &spanstore.ContinuationToken{
+				StartTimeMax: time.Now(),
+				Offset:       maxTraceIDBuckets - defaultNumTraces + 1,
+			},
+		}
+		// the search would exceed the buckets elasticsearch can aggregate, it is not sent
+		_, err := r.reader.FindTraceIDs(context.Background(), traceQuery)
+		assert.Equal(t, ErrTooManyTraces, err)
+		assert.Equal(t, 0, traceQuery.NumTraces)
+	})
+}
+
+func TestSpanReader_FindSpans(t *testing.T) {
+	hits := []*elastic.SearchHit{
+		{Source: (*json.RawMessage)(&exampleESSpan)},
+	}
+	startTimeMax := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	traceQuery := &spanstore.TraceQueryParameters{
+		ServiceName:   serviceName,
+		OperationName: "op",
+		Tags:          map[string]string{"error": "true"},
+		StartTimeMin:  startTimeMax.Add(-time.Hour),
+		StartTimeMax:  startTimeMax,
+		NumTraces:     2,
+	}
+
+	testCases := []struct {
+		searchResult  *elastic.SearchResult
+		searchError   error
+		expectedSpans int
+		expectedError string
+	}{
+		{searchResult: &elastic.SearchResult{Hits: &elastic.SearchHits{Hits: hits}}, expectedSpans: 1},
+		{searchResult: &elastic.SearchResult{}, expectedSpans: 0},
+		{searchError: errors.New("query error occurred"), expectedError: "Search service failed: query error occurred"},
+	}
+	for _, testCase := range testCases {
+		withSpanReader(func(r *spanReaderTest) {
+			searchService := &mocks.SearchService{}
+			searchService.On("Type", stringMatcher(spanType)).Return(searchService)
+			searchService.On("Size", 2).Return(searchService)
+			searchService.On("Sort", startTimeField, false).Return(searchService)
+			searchService.On("IgnoreUnavailable", true).Return(searchService)
+			searchService.On("Query", r.reader.buildFindTraceIDsQuery(traceQuery)).Return(searchService)
+			searchService.On("Do", mock.Anything).Return(testCase.searchResult, testCase.searchError)
+			r.client.On("Search", "jaeger-span-2019-06-01").Return(searchService)
+
+			spans, err := r.reader.FindSpans(context.Background(), traceQuery)
+			if testCase.expectedError != "" {
+				assert.EqualError(t, err, testCase.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, spans, testCase.expectedSpans)
+			if testCase.expectedSpans > 0 {
+				expectedSpans, err := r.reader.collectSpans(hits)
+				require.NoError(t, err)
+				assert.Equal(t, expectedSpans, spans)
+			}
+		})
+	}
+}
+
+func TestSpanReader_FindSpansInvalidQuery(t *testing.T) {
+	withSpanReader(func(r *spanReaderTest) {
+		_, err := r.reader.FindSpans(context.Background(), &spanstore.TraceQueryParameters{ServiceName: serviceName})
+		assert.Equal(t, ErrStartAndEndTimeNotSet, err)
+	})
+}
+
 func TestSpanReader_FindTracesInvalidQuery(t *testing.T) {
 	goodAggregations := make(map[string]*json.RawMessage)
 	rawMessage := []byte(`{"buckets": [{"key": "1","doc_count": 16},{"key": "2","doc_count": 16},{"key": "3","doc_count": 16}]}`)
@@ -787,15 +936,30 @@ func TestTraceQueryParameterValidation(t *testing.T) {
 	tqp.DurationMax = time.Minute
 	err = validateQuery(tqp)
 	assert.EqualError(t, err, ErrDurationMinGreaterThanMax.Error())
+
+	tqp.DurationMin = 0
+	tqp.TagFilters = []spanstore.TagFilter{{Key: "hello", Operator: spanstore.TagFilterRegex, Value: "("}}
+	err = validateQuery(tqp)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), spanstore.ErrInvalidTagFilter.Error())
+
+	tqp.TagFilters = []spanstore.TagFilter{{Key: "http.status_code", Operator: spanstore.TagFilterGreaterThan, Value: "499"}}
+	err = validateQuery(tqp)
+	assert.EqualError(t, err, `"http.status_code>499": tag filter operator not supported by the storage backend`)
+
+	tqp.TagFilters = []spanstore.TagFilter{{Key: "hello", Operator: spanstore.TagFilterRegex, Value: `\bworld`}}
+	err = validateQuery(tqp)
+	assert.EqualError(t, err, `word boundary in regular expression "\\bworld": tag filter operator not supported by the storage backend`)
 }
 
 func TestSpanReader_buildTraceIDAggregation(t *testing.T) {
 	expectedStr := `{ "terms":{
             "field":"traceID",
             "size":123,
-            "order":{
-               "startTime":"desc"
-            }
+            "order":[
+               {"startTime":"desc"},
+               {"_term":"desc"}
+            ]
          },
          "aggregations": {
             "startTime" : { "max": {"field": "startTime"}}
@@ -808,7 +972,10 @@ func TestSpanReader_buildTraceIDAggregation(t *testing.T) {
 		expected := make(map[string]interface{})
 		json.Unmarshal([]byte(expectedStr), &expected)
 		expected["terms"].(map[string]interface{})["size"] = 123
-		expected["terms"].(map[string]interface{})["order"] = []interface{}{map[string]string{"startTime": "desc"}}
+		expected["terms"].(map[string]interface{})["order"] = []interface{}{
+			map[string]string{"startTime": "desc"},
+			map[string]string{"_term": "desc"},
+		}
 		assert.EqualValues(t, expected, actual)
 	})
 }
@@ -844,6 +1011,38 @@ func TestSpanReader_buildFindTraceIDsQuery(t *testing.T) {
 	})
 }
 
+func TestSpanReader_buildFindTraceIDsQueryWithTagFilters(t *testing.T) {
+	withSpanReader(func(r *spanReaderTest) {
+		traceQuery := &spanstore.TraceQueryParameters{
+			StartTimeMin: time.Time{},
+			StartTimeMax: time.Time{}.Add(time.Second),
+			ServiceName:  "s",
+			TagFilters: []spanstore.TagFilter{
+				{Key: "a", Operator: spanstore.TagFilterEqual, Value: "1"},
+				{Key: "b", Operator: spanstore.TagFilterNotEqual, Value: "2"},
+				{Key: "c", Operator: spanstore.TagFilterRegex, Value: "3.*@"},
+				{Key: "d", Operator: spanstore.TagFilterExists},
+			},
+		}
+
+		actualQuery := r.reader.buildFindTraceIDsQuery(traceQuery)
+		actual, err := actualQuery.Source()
+		require.NoError(t, err)
+		expectedQuery := elastic.NewBoolQuery().
+			Must(
+				r.reader.buildStartTimeQuery(time.Time{}, time.Time{}.Add(time.Second)),
+				r.reader.buildServiceNameQuery("s"),
+				r.reader.buildTagQuery("a", "1"),
+				r.reader.buildTagRegexQuery("c", "3[^\n]*\\@"),
+				r.reader.buildTagExistsQuery("d"),
+			).
+			MustNot(r.reader.buildTagQuery("b", "2"))
+		expected, err := expectedQuery.Source()
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+}
+
 func TestSpanReader_buildDurationQuery(t *testing.T) {
 	expectedStr :=
 		`{ "range":
@@ -939,6 +1138,37 @@ func TestSpanReader_buildTagQuery(t *testing.T) {
 	})
 }
 
+func TestSpanReader_buildTagRegexAndExistsQuery(t *testing.T) {
+	withSpanReader(func(r *spanReaderTest) {
+		regexQuery, err := r.reader.buildTagRegexQuery("bat.foo", "sp.*").Source()
+		require.NoError(t, err)
+		existsQuery, err := r.reader.buildTagExistsQuery("bat.foo").Source()
+		require.NoError(t, err)
+
+		var expectedRegex, expectedExists map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(`{"bool":{"should":[
+			{"bool":{"must":{"regexp":{"tag.bat@foo":{"value":"sp.*"}}}}},
+			{"bool":{"must":{"regexp":{"process.tag.bat@foo":{"value":"sp.*"}}}}},
+			{"nested":{"path":"tags","query":{"bool":{"must":[
+				{"match":{"tags.key":{"query":"bat.foo"}}},{"regexp":{"tags.value":{"value":"sp.*"}}}]}}}},
+			{"nested":{"path":"process.tags","query":{"bool":{"must":[
+				{"match":{"process.tags.key":{"query":"bat.foo"}}},{"regexp":{"process.tags.value":{"value":"sp.*"}}}]}}}},
+			{"nested":{"path":"logs.fields","query":{"bool":{"must":[
+				{"match":{"logs.fields.key":{"query":"bat.foo"}}},{"regexp":{"logs.fields.value":{"value":"sp.*"}}}]}}}}
+		]}}`), &expectedRegex))
+		require.NoError(t, json.Unmarshal([]byte(`{"bool":{"should":[
+			{"bool":{"must":{"exists":{"field":"tag.bat@foo"}}}},
+			{"bool":{"must":{"exists":{"field":"process.tag.bat@foo"}}}},
+			{"nested":{"path":"tags","query":{"bool":{"must":{"match":{"tags.key":{"query":"bat.foo"}}}}}}},
+			{"nested":{"path":"process.tags","query":{"bool":{"must":{"match":{"process.tags.key":{"query":"bat.foo"}}}}}}},
+			{"nested":{"path":"logs.fields","query":{"bool":{"must":{"match":{"logs.fields.key":{"query":"bat.foo"}}}}}}}
+		]}}`), &expectedExists))
+
+		assert.EqualValues(t, expectedRegex, regexQuery)
+		assert.EqualValues(t, expectedExists, existsQuery)
+	})
+}
+
 func TestSpanReader_GetEmptyIndex(t *testing.T) {
 	withSpanReader(func(r *spanReaderTest) {
 		mockSearchService(r).
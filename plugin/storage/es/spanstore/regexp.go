@@ -0,0 +1,186 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// luceneRegexp translates a regular expression of a tag filter, in the RE2 syntax, into the Lucene syntax of the
+// regexp query. Both match the tag value as a whole, but Lucene reads some characters RE2 takes literally as
+// operators, e.g. "@" or "~", and lacks some RE2 constructs, e.g. the word boundaries, which are rejected with
+// spanstore.ErrTagFilterNotSupported.
+func luceneRegexp(pattern string) (string, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", errors.Wrapf(spanstore.ErrInvalidTagFilter, "bad regular expression %q: %v", pattern, err)
+	}
+	var sb strings.Builder
+	if err := writeLuceneRegexp(&sb, re, true, true); err != nil {
+		return "", errors.Wrapf(spanstore.ErrTagFilterNotSupported, "%v in regular expression %q", err, pattern)
+	}
+	return sb.String(), nil
+}
+
+// writeLuceneRegexp writes the expression in the Lucene syntax, atStart and atEnd telling whether the expression
+// can only match at the start and at the end of the value, where the RE2 anchors are redundant.
+func writeLuceneRegexp(sb *strings.Builder, re *syntax.Regexp, atStart, atEnd bool) error {
+	switch re.Op {
+	case syntax.OpEmptyMatch:
+		sb.WriteString("()")
+	case syntax.OpBeginText:
+		if !atStart {
+			return errors.New("anchor not at the start")
+		}
+		sb.WriteString("()")
+	case syntax.OpEndText:
+		if !atEnd {
+			return errors.New("anchor not at the end")
+		}
+		sb.WriteString("()")
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			writeLuceneLiteral(sb, r, re.Flags&syntax.FoldCase != 0)
+		}
+	case syntax.OpCharClass:
+		return writeLuceneCharClass(sb, re.Rune)
+	case syntax.OpAnyCharNotNL:
+		// unlike RE2, the Lucene dot matches the new lines
+		sb.WriteString("[^\n]")
+	case syntax.OpAnyChar:
+		sb.WriteString(".")
+	case syntax.OpCapture:
+		sb.WriteString("(")
+		if err := writeLuceneRegexp(sb, re.Sub[0], atStart, atEnd); err != nil {
+			return err
+		}
+		sb.WriteString(")")
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		// the greediness does not change which values match as a whole
+		if err := writeLuceneOperand(sb, re.Sub[0]); err != nil {
+			return err
+		}
+		switch re.Op {
+		case syntax.OpStar:
+			sb.WriteString("*")
+		case syntax.OpPlus:
+			sb.WriteString("+")
+		case syntax.OpQuest:
+			sb.WriteString("?")
+		default:
+			sb.WriteString("{" + strconv.Itoa(re.Min))
+			if re.Max != re.Min {
+				sb.WriteString(",")
+				if re.Max >= 0 {
+					sb.WriteString(strconv.Itoa(re.Max))
+				}
+			}
+			sb.WriteString("}")
+		}
+	case syntax.OpConcat:
+		for i, sub := range re.Sub {
+			if err := writeLuceneRegexp(sb, sub, atStart && i == 0, atEnd && i == len(re.Sub)-1); err != nil {
+				return err
+			}
+		}
+	case syntax.OpAlternate:
+		sb.WriteString("(")
+		for i, sub := range re.Sub {
+			if i > 0 {
+				sb.WriteString("|")
+			}
+			if err := writeLuceneRegexp(sb, sub, atStart, atEnd); err != nil {
+				return err
+			}
+		}
+		sb.WriteString(")")
+	case syntax.OpBeginLine, syntax.OpEndLine:
+		return errors.New("multi-line anchor")
+	case syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		return errors.New("word boundary")
+	default:
+		return errors.Errorf("unsupported construct %q", re.String())
+	}
+	return nil
+}
+
+// writeLuceneOperand writes the operand of a repetition, grouped unless it is a single character or class.
+func writeLuceneOperand(sb *strings.Builder, re *syntax.Regexp) error {
+	switch {
+	case re.Op == syntax.OpCharClass, re.Op == syntax.OpAnyChar, re.Op == syntax.OpAnyCharNotNL,
+		re.Op == syntax.OpLiteral && len(re.Rune) == 1, re.Op == syntax.OpCapture:
+		return writeLuceneRegexp(sb, re, false, false)
+	}
+	sb.WriteString("(")
+	if err := writeLuceneRegexp(sb, re, false, false); err != nil {
+		return err
+	}
+	sb.WriteString(")")
+	return nil
+}
+
+func writeLuceneLiteral(sb *strings.Builder, r rune, foldCase bool) {
+	if foldCase && unicode.SimpleFold(r) != r {
+		sb.WriteString("[")
+		for f := r; ; {
+			writeLuceneRune(sb, f)
+			if f = unicode.SimpleFold(f); f == r {
+				break
+			}
+		}
+		sb.WriteString("]")
+		return
+	}
+	writeLuceneRune(sb, r)
+}
+
+// writeLuceneCharClass writes the class of the rune ranges, the negated classes being already expanded into
+// ranges by the RE2 parser.
+func writeLuceneCharClass(sb *strings.Builder, ranges []rune) error {
+	if len(ranges) == 0 {
+		return errors.New("empty character class")
+	}
+	if len(ranges) == 2 && ranges[0] == 0 && ranges[1] == unicode.MaxRune {
+		sb.WriteString(".")
+		return nil
+	}
+	sb.WriteString("[")
+	for i := 0; i < len(ranges); i += 2 {
+		writeLuceneRune(sb, ranges[i])
+		if ranges[i+1] != ranges[i] {
+			sb.WriteString("-")
+			writeLuceneRune(sb, ranges[i+1])
+		}
+	}
+	sb.WriteString("]")
+	return nil
+}
+
+// writeLuceneRune escapes all the ASCII punctuation, as Lucene reserves more characters than RE2,
+// e.g. "@", "&", "~", "<", ">", "#" and "\"".
+func writeLuceneRune(sb *strings.Builder, r rune) {
+	if r < utf8.RuneSelf && !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9') {
+		sb.WriteByte('\\')
+	}
+	sb.WriteRune(r)
+}
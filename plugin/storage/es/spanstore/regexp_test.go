@@ -0,0 +1,83 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+func TestLuceneRegexp(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		expected string
+	}{
+		{pattern: "abc", expected: "abc"},
+		{pattern: "a.c", expected: "a[^\n]c"},
+		{pattern: "(?s)a.c", expected: "a.c"},
+		{pattern: "user@host", expected: `user\@host`},
+		{pattern: `a&b~c<d>e#f"g`, expected: `a\&b\~c\<d\>e\#f\"g`},
+		{pattern: `\.\*`, expected: `\.\*`},
+		{pattern: "^abc$", expected: "()abc()"},
+		{pattern: "^a|b$", expected: "(()a|b())"},
+		{pattern: "x(ab|cd)*", expected: "x((ab|cd))*"},
+		{pattern: "(?:ab)+?", expected: "(ab)+"},
+		{pattern: "a{2,3}b{2}c{2,}", expected: "a{2,3}b{2}c{2,}"},
+		{pattern: `\d+`, expected: `[0-9]+`},
+		{pattern: "[^a-z]", expected: "[\\\x00-\\`\\{-\U0010ffff]"},
+		{pattern: "[a-c-]", expected: `[\-a-c]`},
+		{pattern: "(?i)k", expected: "[Kk\u212a]"},
+		{pattern: "(?s).*", expected: ".*"},
+		{pattern: "", expected: "()"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.pattern, func(t *testing.T) {
+			actual, err := luceneRegexp(testCase.pattern)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, actual)
+		})
+	}
+}
+
+func TestLuceneRegexpNotSupported(t *testing.T) {
+	for _, pattern := range []string{
+		`\bword`,
+		`a\Bb`,
+		"a^b",
+		"a$b",
+		"(?m)^a",
+		"(^a)*",
+		"[^\x00-\U0010ffff]",
+	} {
+		t.Run(pattern, func(t *testing.T) {
+			require.NotNil(t, regexp.MustCompile(pattern))
+			_, err := luceneRegexp(pattern)
+			require.Error(t, err)
+			assert.Equal(t, spanstore.ErrTagFilterNotSupported, errors.Cause(err))
+		})
+	}
+}
+
+func TestLuceneRegexpInvalid(t *testing.T) {
+	_, err := luceneRegexp("(")
+	require.Error(t, err)
+	assert.Equal(t, spanstore.ErrInvalidTagFilter, errors.Cause(err))
+}
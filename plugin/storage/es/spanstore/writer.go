@@ -27,6 +27,7 @@ import (
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/cache"
 	"github.com/jaegertracing/jaeger/pkg/es"
+	"github.com/jaegertracing/jaeger/pkg/retention"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/spanstore/dbmodel"
 	storageMetrics "github.com/jaegertracing/jaeger/storage/spanstore/metrics"
 )
@@ -55,6 +56,9 @@ type SpanWriter struct {
 	spanServiceIndex spanAndServiceIndexFn
 	spanMapping      string
 	serviceMapping   string
+	// retention routes the spans of the services with a retention rule to the daily span indices of the rule
+	retention       *retention.Policy
+	spanIndexPrefix string
 }
 
 // SpanWriterParams holds constructor parameters for NewSpanWriter
@@ -70,6 +74,8 @@ type SpanWriterParams struct {
 	UseReadWriteAliases bool
 	SpanMapping         string
 	ServiceMapping      string
+	// RetentionPolicy is only supported with daily indices, it is ignored for the archive and with aliases
+	RetentionPolicy *retention.Policy
 }
 
 // NewSpanWriter creates a new SpanWriter for use
@@ -78,6 +84,10 @@ func NewSpanWriter(p SpanWriterParams) *SpanWriter {
 
 	// TODO: Configurable TTL
 	serviceOperationStorage := NewServiceOperationStorage(ctx, p.Client, p.Logger, time.Hour*12)
+	var retentionPolicy *retention.Policy
+	if !p.Archive && !p.UseReadWriteAliases {
+		retentionPolicy = p.RetentionPolicy
+	}
 	return &SpanWriter{
 		ctx:    ctx,
 		client: p.Client,
@@ -87,7 +97,8 @@ func NewSpanWriter(p SpanWriterParams) *SpanWriter {
 		},
 		serviceWriter: serviceOperationStorage.Write,
 		indexCache: cache.NewLRUWithOptions(
-			5,
+			// the span indices of each retention rule for today and yesterday
+			5+2*len(retentionPolicy.Rules()),
 			&cache.Options{
 				TTL: 48 * time.Hour,
 			},
@@ -96,6 +107,8 @@ func NewSpanWriter(p SpanWriterParams) *SpanWriter {
 		serviceMapping:   p.ServiceMapping,
 		spanConverter:    dbmodel.NewFromDomain(p.AllTagsAsFields, p.TagKeysAsFields, p.TagDotReplacement),
 		spanServiceIndex: getSpanAndServiceIndexFn(p.Archive, p.UseReadWriteAliases, p.IndexPrefix),
+		retention:        retentionPolicy,
+		spanIndexPrefix:  indexNames(p.IndexPrefix, spanIndex),
 	}
 }
 
@@ -130,6 +143,9 @@ func getSpanAndServiceIndexFn(archive, useReadWriteAliases bool, prefix string)
 // WriteSpan writes a span and its corresponding service:operation in ElasticSearch
 func (s *SpanWriter) WriteSpan(span *model.Span) error {
 	spanIndexName, serviceIndexName := s.spanServiceIndex(span.StartTime)
+	if rule, ok := s.retention.Match(span.Process.ServiceName); ok {
+		spanIndexName = retentionIndexWithDate(s.spanIndexPrefix, rule.Name, span.StartTime)
+	}
 	jsonSpan := s.spanConverter.FromDomainEmbedProcess(span)
 	if serviceIndexName != "" {
 		if err := s.createIndex(serviceIndexName, s.serviceMapping, jsonSpan); err != nil {
@@ -28,6 +28,7 @@ import (
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/es/mocks"
+	"github.com/jaegertracing/jaeger/pkg/retention"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/spanstore/dbmodel"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
@@ -232,6 +233,59 @@ func TestSpanWriter_WriteSpan(t *testing.T) {
 	}
 }
 
+func TestSpanWriterRetentionPolicy(t *testing.T) {
+	policy, err := retention.NewPolicy([]retention.Rule{
+		{Name: "payments", Services: []string{"payments"}, TTL: 720 * time.Hour},
+	})
+	require.NoError(t, err)
+	date, err := time.Parse(time.RFC3339, "1995-04-21T22:08:41+00:00")
+	require.NoError(t, err)
+	testCases := []struct {
+		params        SpanWriterParams
+		service       string
+		spanIndexName string
+	}{
+		{params: SpanWriterParams{RetentionPolicy: policy},
+			service: "payments", spanIndexName: "jaeger-span-payments-1995-04-21"},
+		{params: SpanWriterParams{RetentionPolicy: policy},
+			service: "frontend", spanIndexName: "jaeger-span-1995-04-21"},
+		{params: SpanWriterParams{RetentionPolicy: policy, IndexPrefix: "foo"},
+			service: "payments", spanIndexName: "foo-jaeger-span-payments-1995-04-21"},
+		{params: SpanWriterParams{RetentionPolicy: policy, UseReadWriteAliases: true},
+			service: "payments", spanIndexName: "jaeger-span-write"},
+		{params: SpanWriterParams{RetentionPolicy: policy, Archive: true},
+			service: "payments", spanIndexName: "jaeger-span-archive"},
+	}
+	for _, testCase := range testCases {
+		client := &mocks.Client{}
+		logger, _ := testutils.NewLogger()
+		testCase.params.Client, testCase.params.Logger, testCase.params.MetricsFactory = client, logger, metricstest.NewFactory(0)
+		w := NewSpanWriter(testCase.params)
+		_, serviceIndexName := w.spanServiceIndex(date)
+		// mark the expected indices as created, writing to any other index would call the unmocked IndexExists
+		writeCache(testCase.spanIndexName, w.indexCache)
+		writeCache(serviceIndexName, w.indexCache)
+
+		indexService := &mocks.IndexService{}
+		indexService.On("Index", stringMatcher(testCase.spanIndexName)).Return(indexService)
+		indexService.On("Index", stringMatcher(serviceIndexName)).Return(indexService)
+		indexService.On("Type", mock.AnythingOfType("string")).Return(indexService)
+		indexService.On("Id", mock.AnythingOfType("string")).Return(indexService)
+		indexService.On("BodyJson", mock.Anything).Return(indexService)
+		indexService.On("Add")
+		client.On("Index").Return(indexService)
+
+		span := &model.Span{
+			TraceID:       model.NewTraceID(0, 1),
+			OperationName: "operation",
+			Process:       &model.Process{ServiceName: testCase.service},
+			StartTime:     date,
+		}
+		require.NoError(t, w.WriteSpan(span))
+		indexService.AssertCalled(t, "Index", testCase.spanIndexName)
+	}
+}
+
 func TestSpanIndexName(t *testing.T) {
 	date, err := time.Parse(time.RFC3339, "1995-04-21T22:08:41+00:00")
 	require.NoError(t, err)
@@ -17,11 +17,15 @@ package storage
 import (
 	"flag"
 	"fmt"
+	"io"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/pkg/distributedlock"
+	"github.com/jaegertracing/jaeger/pkg/multierror"
 	"github.com/jaegertracing/jaeger/plugin"
 	"github.com/jaegertracing/jaeger/plugin/storage/badger"
 	"github.com/jaegertracing/jaeger/plugin/storage/cassandra"
@@ -31,6 +35,7 @@ import (
 	"github.com/jaegertracing/jaeger/plugin/storage/memory"
 	"github.com/jaegertracing/jaeger/storage"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/samplingstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -58,6 +63,7 @@ type Factory struct {
 	FactoryConfig
 	metricsFactory metrics.Factory
 	factories      map[string]storage.Factory
+	routes         []spanstore.Route
 }
 
 // NewFactory creates the meta-factory.
@@ -70,6 +76,9 @@ func NewFactory(config FactoryConfig) (*Factory, error) {
 	for _, storageType := range f.SpanWriterTypes {
 		uniqueTypes[storageType] = struct{}{}
 	}
+	if f.SamplingStorageType != "" {
+		uniqueTypes[f.SamplingStorageType] = struct{}{}
+	}
 	f.factories = make(map[string]storage.Factory)
 	for t := range uniqueTypes {
 		ff, err := f.getFactoryOfType(t)
@@ -108,6 +117,13 @@ func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger)
 			return err
 		}
 	}
+	if f.RoutingRulesFile != "" {
+		routes, err := loadRoutingRules(f.RoutingRulesFile)
+		if err != nil {
+			return err
+		}
+		f.routes = routes
+	}
 	return nil
 }
 
@@ -134,8 +150,24 @@ func (f *Factory) CreateSpanWriter() (spanstore.Writer, error) {
 		}
 		writers = append(writers, writer)
 	}
+	return f.combineSpanWriters(writers)
+}
+
+// combineSpanWriters fans out to all the writers, or routes the spans to them if routing rules are configured,
+// and applies downsampling if enabled. The writers are in the order of SpanWriterTypes.
+func (f *Factory) combineSpanWriters(writers []spanstore.Writer) (spanstore.Writer, error) {
 	var spanWriter spanstore.Writer
-	if len(f.SpanWriterTypes) == 1 {
+	if f.routes != nil {
+		writersByType := make(map[string]spanstore.Writer, len(writers))
+		for i, storageType := range f.SpanWriterTypes {
+			writersByType[storageType] = writers[i]
+		}
+		routingWriter, err := spanstore.NewRoutingWriter(f.routes, writersByType, f.metricsFactory)
+		if err != nil {
+			return nil, errors.Wrap(err, "Invalid routing rules")
+		}
+		spanWriter = routingWriter
+	} else if len(f.SpanWriterTypes) == 1 {
 		spanWriter = writers[0]
 	} else {
 		spanWriter = spanstore.NewCompositeWriter(writers...)
@@ -151,6 +183,35 @@ func (f *Factory) CreateSpanWriter() (spanstore.Writer, error) {
 	}), nil
 }
 
+// CreateSpanDeleter implements storage.DeleterFactory. It deletes the traces from all span writer backends
+// that support deletion.
+func (f *Factory) CreateSpanDeleter() (spanstore.Deleter, error) {
+	var deleters []spanstore.Deleter
+	for _, storageType := range f.SpanWriterTypes {
+		factory, ok := f.factories[storageType]
+		if !ok {
+			return nil, fmt.Errorf("no %s backend registered for span store", storageType)
+		}
+		deleterFactory, ok := factory.(storage.DeleterFactory)
+		if !ok {
+			continue
+		}
+		deleter, err := deleterFactory.CreateSpanDeleter()
+		if err != nil {
+			return nil, err
+		}
+		deleters = append(deleters, deleter)
+	}
+	switch len(deleters) {
+	case 0:
+		return nil, storage.ErrSpanDeleterNotSupported
+	case 1:
+		return deleters[0], nil
+	default:
+		return spanstore.NewCompositeDeleter(deleters...), nil
+	}
+}
+
 // CreateDependencyReader implements storage.Factory
 func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
 	factory, ok := f.factories[f.DependenciesStorageType]
@@ -160,6 +221,19 @@ func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
 	return factory.CreateDependencyReader()
 }
 
+// CreateDependencyWriter implements storage.DependencyWriterFactory
+func (f *Factory) CreateDependencyWriter() (dependencystore.Writer, error) {
+	factory, ok := f.factories[f.DependenciesStorageType]
+	if !ok {
+		return nil, fmt.Errorf("no %s backend registered for dependency store", f.DependenciesStorageType)
+	}
+	dwFactory, ok := factory.(storage.DependencyWriterFactory)
+	if !ok {
+		return nil, storage.ErrDependencyWriterNotSupported
+	}
+	return dwFactory.CreateDependencyWriter()
+}
+
 // AddFlags implements plugin.Configurable
 func (f *Factory) AddFlags(flagSet *flag.FlagSet) {
 	for _, factory := range f.factories {
@@ -168,6 +242,7 @@ func (f *Factory) AddFlags(flagSet *flag.FlagSet) {
 		}
 	}
 	addDownsamplingFlags(flagSet)
+	addRoutingFlags(flagSet)
 }
 
 // addDownsamplingFlags add flags for Downsampling params
@@ -192,6 +267,7 @@ func (f *Factory) InitFromViper(v *viper.Viper) {
 		}
 	}
 	f.initDownsamplingFromViper(v)
+	f.initRoutingFromViper(v)
 }
 
 func (f *Factory) initDownsamplingFromViper(v *viper.Viper) {
@@ -228,3 +304,134 @@ func (f *Factory) CreateArchiveSpanWriter() (spanstore.Writer, error) {
 	}
 	return archive.CreateArchiveSpanWriter()
 }
+
+// CreateLock implements storage.SamplingStoreFactory
+func (f *Factory) CreateLock() (distributedlock.Lock, error) {
+	ssFactory, err := f.getSamplingStoreFactory()
+	if err != nil {
+		return nil, err
+	}
+	return ssFactory.CreateLock()
+}
+
+// CreateSamplingStore implements storage.SamplingStoreFactory
+func (f *Factory) CreateSamplingStore() (samplingstore.Store, error) {
+	ssFactory, err := f.getSamplingStoreFactory()
+	if err != nil {
+		return nil, err
+	}
+	return ssFactory.CreateSamplingStore()
+}
+
+func (f *Factory) getSamplingStoreFactory() (storage.SamplingStoreFactory, error) {
+	factory, ok := f.factories[f.SamplingStorageType]
+	if !ok {
+		return nil, fmt.Errorf("no %s backend registered for sampling store", f.SamplingStorageType)
+	}
+	ssFactory, ok := factory.(storage.SamplingStoreFactory)
+	if !ok {
+		return nil, storage.ErrSamplingStorageNotSupported
+	}
+	return ssFactory, nil
+}
+
+// CheckMultiTenancy returns an error if one of the storage types configured for the span reader,
+// the span writers or the dependencies cannot keep the data of tenants apart, so that the binaries
+// fail at startup instead of failing every read and write once multi-tenancy is enabled.
+func (f *Factory) CheckMultiTenancy() error {
+	storageTypes := append([]string{f.SpanReaderType, f.DependenciesStorageType}, f.SpanWriterTypes...)
+	for _, storageType := range storageTypes {
+		if _, err := f.getTenantFactory(storageType); err != nil {
+			return fmt.Errorf("%s storage: %v", storageType, err)
+		}
+	}
+	return nil
+}
+
+// CreateTenantSpanReader implements storage.TenantFactory
+func (f *Factory) CreateTenantSpanReader(tenant string) (spanstore.Reader, error) {
+	tFactory, err := f.getTenantFactory(f.SpanReaderType)
+	if err != nil {
+		return nil, err
+	}
+	return tFactory.CreateTenantSpanReader(tenant)
+}
+
+// CreateTenantSpanWriter implements storage.TenantFactory
+func (f *Factory) CreateTenantSpanWriter(tenant string) (spanstore.Writer, error) {
+	var writers []spanstore.Writer
+	for _, storageType := range f.SpanWriterTypes {
+		tFactory, err := f.getTenantFactory(storageType)
+		if err != nil {
+			return nil, err
+		}
+		writer, err := tFactory.CreateTenantSpanWriter(tenant)
+		if err != nil {
+			return nil, err
+		}
+		writers = append(writers, writer)
+	}
+	return f.combineSpanWriters(writers)
+}
+
+// CreateTenantDependencyReader implements storage.TenantFactory
+func (f *Factory) CreateTenantDependencyReader(tenant string) (dependencystore.Reader, error) {
+	tFactory, err := f.getTenantFactory(f.DependenciesStorageType)
+	if err != nil {
+		return nil, err
+	}
+	return tFactory.CreateTenantDependencyReader(tenant)
+}
+
+// CreateTenantSpanDeleter implements storage.TenantFactory. It deletes the traces of the tenant
+// from all span writer backends that support deletion.
+func (f *Factory) CreateTenantSpanDeleter(tenant string) (spanstore.Deleter, error) {
+	var deleters []spanstore.Deleter
+	for _, storageType := range f.SpanWriterTypes {
+		tFactory, err := f.getTenantFactory(storageType)
+		if err != nil {
+			return nil, err
+		}
+		deleter, err := tFactory.CreateTenantSpanDeleter(tenant)
+		if err == storage.ErrSpanDeleterNotSupported {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		deleters = append(deleters, deleter)
+	}
+	switch len(deleters) {
+	case 0:
+		return nil, storage.ErrSpanDeleterNotSupported
+	case 1:
+		return deleters[0], nil
+	default:
+		return spanstore.NewCompositeDeleter(deleters...), nil
+	}
+}
+
+// Close implements io.Closer and closes the backend factories that hold resources such as sessions.
+func (f *Factory) Close() error {
+	var errs []error
+	for _, factory := range f.factories {
+		if closer, ok := factory.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return multierror.Wrap(errs)
+}
+
+func (f *Factory) getTenantFactory(storageType string) (storage.TenantFactory, error) {
+	factory, ok := f.factories[storageType]
+	if !ok {
+		return nil, fmt.Errorf("no %s backend registered for span store", storageType)
+	}
+	tFactory, ok := factory.(storage.TenantFactory)
+	if !ok {
+		return nil, storage.ErrMultiTenancyNotSupported
+	}
+	return tFactory, nil
+}
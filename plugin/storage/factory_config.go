@@ -28,6 +28,9 @@ const (
 	// DependencyStorageTypeEnvVar is the name of the env var that defines the type of backend used for dependencies storage.
 	DependencyStorageTypeEnvVar = "DEPENDENCY_STORAGE_TYPE"
 
+	// SamplingStorageTypeEnvVar is the name of the env var that defines the type of backend used for adaptive sampling storage.
+	SamplingStorageTypeEnvVar = "SAMPLING_STORAGE_TYPE"
+
 	spanStorageFlag = "--span-storage.type"
 )
 
@@ -36,12 +39,14 @@ type FactoryConfig struct {
 	SpanWriterTypes         []string
 	SpanReaderType          string
 	DependenciesStorageType string
+	SamplingStorageType     string
 	DownsamplingRatio       float64
 	DownsamplingHashSalt    string
+	RoutingRulesFile        string
 }
 
-// FactoryConfigFromEnvAndCLI reads the desired types of storage backends from SPAN_STORAGE_TYPE and
-// DEPENDENCY_STORAGE_TYPE environment variables. Allowed values:
+// FactoryConfigFromEnvAndCLI reads the desired types of storage backends from SPAN_STORAGE_TYPE,
+// DEPENDENCY_STORAGE_TYPE and SAMPLING_STORAGE_TYPE environment variables. Allowed values:
 //   * `cassandra` - built-in
 //   * `elasticsearch` - built-in
 //   * `memory` - built-in
@@ -71,11 +76,16 @@ func FactoryConfigFromEnvAndCLI(args []string, log io.Writer) FactoryConfig {
 	if depStorageType == "" {
 		depStorageType = spanWriterTypes[0]
 	}
+	samplingStorageType := os.Getenv(SamplingStorageTypeEnvVar)
+	if samplingStorageType == "" {
+		samplingStorageType = spanWriterTypes[0]
+	}
 	// TODO support explicit configuration for readers
 	return FactoryConfig{
 		SpanWriterTypes:         spanWriterTypes,
 		SpanReaderType:          spanWriterTypes[0],
 		DependenciesStorageType: depStorageType,
+		SamplingStorageType:     samplingStorageType,
 	}
 }
 
@@ -28,21 +28,27 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/pkg/config"
+	lockMocks "github.com/jaegertracing/jaeger/pkg/distributedlock/mocks"
 	"github.com/jaegertracing/jaeger/storage"
 	depStoreMocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
 	"github.com/jaegertracing/jaeger/storage/mocks"
+	samplingStoreMocks "github.com/jaegertracing/jaeger/storage/samplingstore/mocks"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 	spanStoreMocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
 )
 
 var _ storage.Factory = new(Factory)
 var _ storage.ArchiveFactory = new(Factory)
+var _ storage.SamplingStoreFactory = new(Factory)
+var _ storage.TenantFactory = new(Factory)
+var _ storage.DeleterFactory = new(Factory)
 
 func defaultCfg() FactoryConfig {
 	return FactoryConfig{
 		SpanWriterTypes:         []string{cassandraStorageType},
 		SpanReaderType:          cassandraStorageType,
 		DependenciesStorageType: cassandraStorageType,
+		SamplingStorageType:     cassandraStorageType,
 		DownsamplingRatio:       1.0,
 		DownsamplingHashSalt:    "",
 	}
@@ -133,6 +139,15 @@ func TestCreate(t *testing.T) {
 	_, err = f.CreateArchiveSpanWriter()
 	assert.EqualError(t, err, "archive storage not supported")
 
+	_, err = f.CreateLock()
+	assert.EqualError(t, err, "sampling storage not supported")
+
+	_, err = f.CreateSamplingStore()
+	assert.EqualError(t, err, "sampling storage not supported")
+
+	_, err = f.CreateDependencyWriter()
+	assert.EqualError(t, err, "dependency writer not supported")
+
 	mock.On("CreateSpanWriter").Return(spanWriter, nil)
 	m := metrics.NullFactory
 	l := zap.NewNop()
@@ -235,6 +250,226 @@ func TestCreateArchive(t *testing.T) {
 	assert.EqualError(t, err, "archive-span-writer-error")
 }
 
+func TestCreateSamplingStore(t *testing.T) {
+	f, err := NewFactory(defaultCfg())
+	require.NoError(t, err)
+	assert.NotEmpty(t, f.factories[cassandraStorageType])
+
+	mock := &struct {
+		mocks.Factory
+		mocks.SamplingStoreFactory
+	}{}
+	f.factories[cassandraStorageType] = mock
+
+	lock := new(lockMocks.Lock)
+	samplingStore := new(samplingStoreMocks.Store)
+
+	mock.SamplingStoreFactory.On("CreateLock").Return(lock, errors.New("lock-error"))
+	mock.SamplingStoreFactory.On("CreateSamplingStore").Return(samplingStore, errors.New("sampling-store-error"))
+
+	l, err := f.CreateLock()
+	assert.Equal(t, lock, l)
+	assert.EqualError(t, err, "lock-error")
+
+	s, err := f.CreateSamplingStore()
+	assert.Equal(t, samplingStore, s)
+	assert.EqualError(t, err, "sampling-store-error")
+}
+
+func TestCreateDependencyWriter(t *testing.T) {
+	f, err := NewFactory(defaultCfg())
+	require.NoError(t, err)
+	assert.NotEmpty(t, f.factories[cassandraStorageType])
+
+	mock := &struct {
+		mocks.Factory
+		mocks.DependencyWriterFactory
+	}{}
+	f.factories[cassandraStorageType] = mock
+
+	depWriter := new(depStoreMocks.Writer)
+	mock.DependencyWriterFactory.On("CreateDependencyWriter").Return(depWriter, errors.New("dep-writer-error"))
+
+	w, err := f.CreateDependencyWriter()
+	assert.Equal(t, depWriter, w)
+	assert.EqualError(t, err, "dep-writer-error")
+}
+
+func TestCreateSpanDeleter(t *testing.T) {
+	cfg := defaultCfg()
+	cfg.SpanWriterTypes = []string{cassandraStorageType, kafkaStorageType, elasticsearchStorageType}
+	f, err := NewFactory(cfg)
+	require.NoError(t, err)
+
+	mock := &struct {
+		mocks.Factory
+		mocks.DeleterFactory
+	}{}
+	mock2 := &struct {
+		mocks.Factory
+		mocks.DeleterFactory
+	}{}
+	f.factories[cassandraStorageType] = mock
+	f.factories[kafkaStorageType] = new(mocks.Factory)
+	f.factories[elasticsearchStorageType] = mock2
+
+	deleter := new(spanStoreMocks.Deleter)
+	deleter2 := new(spanStoreMocks.Deleter)
+	mock.DeleterFactory.On("CreateSpanDeleter").Return(deleter, nil)
+	mock2.DeleterFactory.On("CreateSpanDeleter").Once().Return(nil, errors.New("span-deleter-error"))
+
+	d, err := f.CreateSpanDeleter()
+	assert.Nil(t, d)
+	assert.EqualError(t, err, "span-deleter-error")
+
+	mock2.DeleterFactory.On("CreateSpanDeleter").Return(deleter2, nil)
+	d, err = f.CreateSpanDeleter()
+	assert.NoError(t, err)
+	assert.Equal(t, spanstore.NewCompositeDeleter(deleter, deleter2), d)
+
+	f.SpanWriterTypes = []string{cassandraStorageType, kafkaStorageType}
+	d, err = f.CreateSpanDeleter()
+	assert.NoError(t, err)
+	assert.Equal(t, deleter, d)
+
+	f.SpanWriterTypes = []string{kafkaStorageType}
+	_, err = f.CreateSpanDeleter()
+	assert.Equal(t, storage.ErrSpanDeleterNotSupported, err)
+
+	delete(f.factories, kafkaStorageType)
+	_, err = f.CreateSpanDeleter()
+	assert.EqualError(t, err, "no kafka backend registered for span store")
+}
+
+type closingFactory struct {
+	mocks.Factory
+	err    error
+	closed bool
+}
+
+func (f *closingFactory) Close() error {
+	f.closed = true
+	return f.err
+}
+
+func TestClose(t *testing.T) {
+	cfg := defaultCfg()
+	cfg.SpanWriterTypes = []string{cassandraStorageType, elasticsearchStorageType, kafkaStorageType}
+	f, err := NewFactory(cfg)
+	require.NoError(t, err)
+
+	cFactory := &closingFactory{}
+	esFactory := &closingFactory{err: errors.New("close-error")}
+	f.factories[cassandraStorageType] = cFactory
+	f.factories[elasticsearchStorageType] = esFactory
+	f.factories[kafkaStorageType] = new(mocks.Factory)
+
+	assert.EqualError(t, f.Close(), "close-error")
+	assert.True(t, cFactory.closed)
+	assert.True(t, esFactory.closed)
+}
+
+func TestCreateTenant(t *testing.T) {
+	cfg := defaultCfg()
+	cfg.SpanWriterTypes = append(cfg.SpanWriterTypes, elasticsearchStorageType)
+	f, err := NewFactory(cfg)
+	require.NoError(t, err)
+
+	mock := &struct {
+		mocks.Factory
+		mocks.TenantFactory
+	}{}
+	mock2 := &struct {
+		mocks.Factory
+		mocks.TenantFactory
+	}{}
+	f.factories[cassandraStorageType] = mock
+	f.factories[elasticsearchStorageType] = mock2
+	assert.NoError(t, f.CheckMultiTenancy())
+
+	spanReader := new(spanStoreMocks.Reader)
+	spanWriter := new(spanStoreMocks.Writer)
+	spanWriter2 := new(spanStoreMocks.Writer)
+	depReader := new(depStoreMocks.Reader)
+
+	mock.TenantFactory.On("CreateTenantSpanReader", "acme").Return(spanReader, nil)
+	mock.TenantFactory.On("CreateTenantSpanWriter", "acme").Return(spanWriter, nil)
+	mock2.TenantFactory.On("CreateTenantSpanWriter", "acme").Return(spanWriter2, nil)
+	mock.TenantFactory.On("CreateTenantDependencyReader", "acme").Return(depReader, nil)
+
+	r, err := f.CreateTenantSpanReader("acme")
+	assert.NoError(t, err)
+	assert.Equal(t, spanReader, r)
+
+	w, err := f.CreateTenantSpanWriter("acme")
+	assert.NoError(t, err)
+	assert.Equal(t, spanstore.NewCompositeWriter(spanWriter, spanWriter2), w)
+
+	d, err := f.CreateTenantDependencyReader("acme")
+	assert.NoError(t, err)
+	assert.Equal(t, depReader, d)
+
+	mock2.TenantFactory.On("CreateTenantSpanWriter", "broken").Return(nil, errors.New("span-writer-error"))
+	mock.TenantFactory.On("CreateTenantSpanWriter", "broken").Return(spanWriter, nil)
+	w, err = f.CreateTenantSpanWriter("broken")
+	assert.Nil(t, w)
+	assert.EqualError(t, err, "span-writer-error")
+
+	deleter := new(spanStoreMocks.Deleter)
+	deleter2 := new(spanStoreMocks.Deleter)
+	mock.TenantFactory.On("CreateTenantSpanDeleter", "acme").Return(deleter, nil)
+	mock2.TenantFactory.On("CreateTenantSpanDeleter", "acme").Once().Return(nil, storage.ErrSpanDeleterNotSupported)
+	del, err := f.CreateTenantSpanDeleter("acme")
+	assert.NoError(t, err)
+	assert.Equal(t, deleter, del)
+
+	mock2.TenantFactory.On("CreateTenantSpanDeleter", "acme").Return(deleter2, nil)
+	del, err = f.CreateTenantSpanDeleter("acme")
+	assert.NoError(t, err)
+	assert.Equal(t, spanstore.NewCompositeDeleter(deleter, deleter2), del)
+
+	mock.TenantFactory.On("CreateTenantSpanDeleter", "broken").Return(nil, errors.New("span-deleter-error"))
+	_, err = f.CreateTenantSpanDeleter("broken")
+	assert.EqualError(t, err, "span-deleter-error")
+
+	mock.TenantFactory.On("CreateTenantSpanDeleter", "none").Return(nil, storage.ErrSpanDeleterNotSupported)
+	mock2.TenantFactory.On("CreateTenantSpanDeleter", "none").Return(nil, storage.ErrSpanDeleterNotSupported)
+	_, err = f.CreateTenantSpanDeleter("none")
+	assert.Equal(t, storage.ErrSpanDeleterNotSupported, err)
+}
+
+func TestCreateTenantNotSupported(t *testing.T) {
+	f, err := NewFactory(defaultCfg())
+	require.NoError(t, err)
+	f.factories[cassandraStorageType] = new(mocks.Factory)
+
+	_, err = f.CreateTenantSpanReader("acme")
+	assert.Equal(t, storage.ErrMultiTenancyNotSupported, err)
+	_, err = f.CreateTenantSpanWriter("acme")
+	assert.Equal(t, storage.ErrMultiTenancyNotSupported, err)
+	_, err = f.CreateTenantDependencyReader("acme")
+	assert.Equal(t, storage.ErrMultiTenancyNotSupported, err)
+	_, err = f.CreateTenantSpanDeleter("acme")
+	assert.Equal(t, storage.ErrMultiTenancyNotSupported, err)
+	assert.EqualError(t, f.CheckMultiTenancy(), "cassandra storage: multi-tenancy not supported")
+
+	// a single span writer type without multi-tenancy support is enough to fail the check
+	cfg := defaultCfg()
+	cfg.SpanWriterTypes = append(cfg.SpanWriterTypes, kafkaStorageType)
+	f2, err := NewFactory(cfg)
+	require.NoError(t, err)
+	f2.factories[cassandraStorageType] = &struct {
+		mocks.Factory
+		mocks.TenantFactory
+	}{}
+	f2.factories[kafkaStorageType] = new(mocks.Factory)
+	assert.EqualError(t, f2.CheckMultiTenancy(), "kafka storage: multi-tenancy not supported")
+
+	delete(f.factories, cassandraStorageType)
+	_, err = f.CreateTenantSpanReader("acme")
+	assert.EqualError(t, err, "no cassandra backend registered for span store")
+}
+
 func TestCreateError(t *testing.T) {
 	f, err := NewFactory(defaultCfg())
 	require.NoError(t, err)
@@ -262,6 +497,12 @@ func TestCreateError(t *testing.T) {
 		assert.EqualError(t, err, expectedErr)
 	}
 
+	{
+		w, err := f.CreateDependencyWriter()
+		assert.Nil(t, w)
+		assert.EqualError(t, err, "no cassandra backend registered for dependency store")
+	}
+
 	{
 		r, err := f.CreateArchiveSpanReader()
 		assert.Nil(t, r)
@@ -273,6 +514,12 @@ func TestCreateError(t *testing.T) {
 		assert.Nil(t, w)
 		assert.EqualError(t, err, expectedErr)
 	}
+
+	{
+		l, err := f.CreateLock()
+		assert.Nil(t, l)
+		assert.EqualError(t, err, "no cassandra backend registered for sampling store")
+	}
 }
 
 type configurable struct {
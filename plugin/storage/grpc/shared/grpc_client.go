@@ -106,6 +106,8 @@ func (c *grpcClient) FindTraces(ctx context.Context, query *spanstore.TraceQuery
 			DurationMin:   query.DurationMin,
 			DurationMax:   query.DurationMax,
 			NumTraces:     int32(query.NumTraces),
+			Offset:        int32(query.Offset()),
+			TagFilters:    fromTagFilters(query.TagFilters),
 		},
 	})
 	if err != nil {
@@ -144,6 +146,8 @@ func (c *grpcClient) FindTraceIDs(ctx context.Context, query *spanstore.TraceQue
 			DurationMin:   query.DurationMin,
 			DurationMax:   query.DurationMax,
 			NumTraces:     int32(query.NumTraces),
+			Offset:        int32(query.Offset()),
+			TagFilters:    fromTagFilters(query.TagFilters),
 		},
 	})
 	if err != nil {
@@ -177,3 +181,18 @@ func (c *grpcClient) GetDependencies(endTs time.Time, lookback time.Duration) ([
 
 	return resp.Dependencies, nil
 }
+
+func fromTagFilters(filters []spanstore.TagFilter) []storage_v1.TagFilter {
+	if len(filters) == 0 {
+		return nil
+	}
+	tagFilters := make([]storage_v1.TagFilter, len(filters))
+	for i, f := range filters {
+		tagFilters[i] = storage_v1.TagFilter{
+			Key:      f.Key,
+			Operator: string(f.Operator),
+			Value:    f.Value,
+		}
+	}
+	return tagFilters
+}
@@ -224,6 +224,30 @@ func TestGRPCClientFindTraceIDs(t *testing.T) {
 	})
 }
 
+func TestGRPCClientFindTraceIDsWithTagFilters(t *testing.T) {
+	withGRPCClient(func(r *grpcClientTest) {
+		r.spanReader.On("FindTraceIDs", mock.Anything, &storage_v1.FindTraceIDsRequest{
+			Query: &storage_v1.TraceQueryParameters{
+				TagFilters: []storage_v1.TagFilter{
+					{Key: "http.url", Operator: "=~", Value: ".*/api/.*"},
+					{Key: "error", Operator: "exists"},
+				},
+			},
+		}).Return(&storage_v1.FindTraceIDsResponse{
+			TraceIDs: []model.TraceID{mockTraceID},
+		}, nil)
+
+		s, err := r.client.FindTraceIDs(context.Background(), &spanstore.TraceQueryParameters{
+			TagFilters: []spanstore.TagFilter{
+				{Key: "http.url", Operator: spanstore.TagFilterRegex, Value: ".*/api/.*"},
+				{Key: "error", Operator: spanstore.TagFilterExists},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []model.TraceID{mockTraceID}, s)
+	})
+}
+
 func TestGRPCClientWriteSpan(t *testing.T) {
 	withGRPCClient(func(r *grpcClientTest) {
 		r.spanWriter.On("WriteSpan", mock.Anything, &storage_v1.WriteSpanRequest{
@@ -91,14 +91,16 @@ func (s *grpcServer) GetOperations(ctx context.Context, r *storage_v1.GetOperati
 // FindTraces streams traces that match the traceQuery
 func (s *grpcServer) FindTraces(r *storage_v1.FindTracesRequest, stream storage_v1.SpanReaderPlugin_FindTracesServer) error {
 	traces, err := s.Impl.SpanReader().FindTraces(stream.Context(), &spanstore.TraceQueryParameters{
-		ServiceName:   r.Query.ServiceName,
-		OperationName: r.Query.OperationName,
-		Tags:          r.Query.Tags,
-		StartTimeMin:  r.Query.StartTimeMin,
-		StartTimeMax:  r.Query.StartTimeMax,
-		DurationMin:   r.Query.DurationMin,
-		DurationMax:   r.Query.DurationMax,
-		NumTraces:     int(r.Query.NumTraces),
+		ServiceName:       r.Query.ServiceName,
+		OperationName:     r.Query.OperationName,
+		Tags:              r.Query.Tags,
+		StartTimeMin:      r.Query.StartTimeMin,
+		StartTimeMax:      r.Query.StartTimeMax,
+		DurationMin:       r.Query.DurationMin,
+		DurationMax:       r.Query.DurationMax,
+		NumTraces:         int(r.Query.NumTraces),
+		ContinuationToken: continuationToken(r.Query),
+		TagFilters:        toTagFilters(r.Query.TagFilters),
 	})
 	if err != nil {
 		return err
@@ -114,17 +116,46 @@ func (s *grpcServer) FindTraces(r *storage_v1.FindTracesRequest, stream storage_
 	return nil
 }
 
+// continuationToken restores the position of a paginated search, the start time range of the query is already pinned
+func continuationToken(query *storage_v1.TraceQueryParameters) *spanstore.ContinuationToken {
+	if query.Offset == 0 {
+		return nil
+	}
+	return &spanstore.ContinuationToken{
+		StartTimeMin: query.StartTimeMin,
+		StartTimeMax: query.StartTimeMax,
+		Offset:       int(query.Offset),
+	}
+}
+
+func toTagFilters(filters []storage_v1.TagFilter) []spanstore.TagFilter {
+	if len(filters) == 0 {
+		return nil
+	}
+	tagFilters := make([]spanstore.TagFilter, len(filters))
+	for i, f := range filters {
+		tagFilters[i] = spanstore.TagFilter{
+			Key:      f.Key,
+			Operator: spanstore.TagFilterOperator(f.Operator),
+			Value:    f.Value,
+		}
+	}
+	return tagFilters
+}
+
 // FindTraceIDs retrieves traceIDs that match the traceQuery
 func (s *grpcServer) FindTraceIDs(ctx context.Context, r *storage_v1.FindTraceIDsRequest) (*storage_v1.FindTraceIDsResponse, error) {
 	traceIDs, err := s.Impl.SpanReader().FindTraceIDs(ctx, &spanstore.TraceQueryParameters{
-		ServiceName:   r.Query.ServiceName,
-		OperationName: r.Query.OperationName,
-		Tags:          r.Query.Tags,
-		StartTimeMin:  r.Query.StartTimeMin,
-		StartTimeMax:  r.Query.StartTimeMax,
-		DurationMin:   r.Query.DurationMin,
-		DurationMax:   r.Query.DurationMax,
-		NumTraces:     int(r.Query.NumTraces),
+		ServiceName:       r.Query.ServiceName,
+		OperationName:     r.Query.OperationName,
+		Tags:              r.Query.Tags,
+		StartTimeMin:      r.Query.StartTimeMin,
+		StartTimeMax:      r.Query.StartTimeMax,
+		DurationMin:       r.Query.DurationMin,
+		DurationMax:       r.Query.DurationMax,
+		NumTraces:         int(r.Query.NumTraces),
+		ContinuationToken: continuationToken(r.Query),
+		TagFilters:        toTagFilters(r.Query.TagFilters),
 	})
 	if err != nil {
 		return nil, err
@@ -150,6 +150,47 @@ func TestGRPCServerFindTraces(t *testing.T) {
 	})
 }
 
+func TestGRPCServerFindTraceIDsWithOffset(t *testing.T) {
+	withGRPCServer(func(r *grpcServerTest) {
+		end := time.Now()
+		r.impl.spanReader.On("FindTraceIDs", mock.Anything, &spanstore.TraceQueryParameters{
+			StartTimeMax:      end,
+			NumTraces:         10,
+			ContinuationToken: &spanstore.ContinuationToken{StartTimeMax: end, Offset: 20},
+		}).Return([]model.TraceID{mockTraceID}, nil)
+
+		s, err := r.server.FindTraceIDs(context.Background(), &storage_v1.FindTraceIDsRequest{
+			Query: &storage_v1.TraceQueryParameters{
+				StartTimeMax: end,
+				NumTraces:    10,
+				Offset:       20,
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, &storage_v1.FindTraceIDsResponse{TraceIDs: []model.TraceID{mockTraceID}}, s)
+	})
+}
+
+func TestGRPCServerFindTraceIDsWithTagFilters(t *testing.T) {
+	withGRPCServer(func(r *grpcServerTest) {
+		r.impl.spanReader.On("FindTraceIDs", mock.Anything, &spanstore.TraceQueryParameters{
+			TagFilters: []spanstore.TagFilter{
+				{Key: "http.status_code", Operator: spanstore.TagFilterGreaterThan, Value: "499"},
+			},
+		}).Return([]model.TraceID{mockTraceID}, nil)
+
+		s, err := r.server.FindTraceIDs(context.Background(), &storage_v1.FindTraceIDsRequest{
+			Query: &storage_v1.TraceQueryParameters{
+				TagFilters: []storage_v1.TagFilter{
+					{Key: "http.status_code", Operator: ">", Value: "499"},
+				},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, &storage_v1.FindTraceIDsResponse{TraceIDs: []model.TraceID{mockTraceID}}, s)
+	})
+}
+
 func TestGRPCServerFindTraceIDs(t *testing.T) {
 	withGRPCServer(func(r *grpcServerTest) {
 		r.impl.spanReader.On("FindTraceIDs", mock.Anything, &spanstore.TraceQueryParameters{}).
@@ -26,10 +26,11 @@ import (
 
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 	"github.com/jaegertracing/jaeger/plugin/storage/badger"
+	"github.com/jaegertracing/jaeger/storage/spanstore/spanstoretest"
 )
 
 type BadgerIntegrationStorage struct {
-	StorageIntegration
+	spanstoretest.Suite
 	logger *zap.Logger
 }
 
@@ -53,6 +54,13 @@ func (s *BadgerIntegrationStorage) initialize() error {
 	s.SpanReader = sr
 	s.SpanWriter = sw
 
+	if s.SamplingStore, err = f.CreateSamplingStore(); err != nil {
+		return err
+	}
+	if s.Lock, err = f.CreateLock(); err != nil {
+		return err
+	}
+
 	s.Refresh = s.refresh
 	s.CleanUp = s.cleanUp
 
@@ -91,6 +99,6 @@ func TestBadgerStorage(t *testing.T) {
 	}
 	s := &BadgerIntegrationStorage{}
 	assert.NoError(t, s.initialize())
-	s.IntegrationTestAll(t)
+	s.RunAll(t)
 	defer s.clear()
 }
@@ -30,6 +30,7 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 	"github.com/jaegertracing/jaeger/plugin/storage/cassandra"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/spanstore/spanstoretest"
 )
 
 var (
@@ -37,14 +38,14 @@ var (
 )
 
 type CassandraStorageIntegration struct {
-	StorageIntegration
+	spanstoretest.Suite
 
 	logger *zap.Logger
 }
 
 func newCassandraStorageIntegration() *CassandraStorageIntegration {
 	return &CassandraStorageIntegration{
-		StorageIntegration: StorageIntegration{
+		Suite: spanstoretest.Suite{
 			Refresh: func() error { return nil },
 			CleanUp: func() error { return nil },
 		},
@@ -113,9 +114,11 @@ func (s *CassandraStorageIntegration) initializeDependencyReaderAndWriter(f *cas
 }
 
 // TODO: Only the cassandra storage currently returns the `Source` field. Once
-// all others support the field, we can remove this test and use the existing testGetDependencies.
-func (s *StorageIntegration) testCassandraGetDependencies(t *testing.T) {
-	defer s.cleanUp(t)
+// all others support the field, we can remove this test and use the GetDependencies test of the suite.
+func (s *CassandraStorageIntegration) testCassandraGetDependencies(t *testing.T) {
+	defer func() {
+		require.NoError(t, s.CleanUp())
+	}()
 
 	expected := []model.DependencyLink{
 		{
@@ -132,7 +135,7 @@ func (s *StorageIntegration) testCassandraGetDependencies(t *testing.T) {
 		},
 	}
 	require.NoError(t, s.DependencyWriter.WriteDependencies(time.Now(), expected))
-	s.refresh(t)
+	require.NoError(t, s.Refresh())
 	actual, err := s.DependencyReader.GetDependencies(time.Now(), 5*time.Minute)
 	assert.NoError(t, err)
 	assert.EqualValues(t, expected, actual)
@@ -21,19 +21,18 @@ import (
 	"testing"
 	"time"
 
-	"github.com/stretchr/testify/assert"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 	"gopkg.in/olivere/elastic.v5"
 
-	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/es/wrapper"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 	"github.com/jaegertracing/jaeger/plugin/storage/es"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/dependencystore"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/spanstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore/spanstoretest"
 )
 
 const (
@@ -49,7 +48,7 @@ const (
 )
 
 type ESStorageIntegration struct {
-	StorageIntegration
+	spanstoretest.Suite
 
 	client        *elastic.Client
 	bulkProcessor *elastic.BulkProcessor
@@ -113,6 +112,9 @@ func (s *ESStorageIntegration) initSpanstore(allTagsAsFields, archive bool) {
 		TagDotReplacement: tagKeyDeDotChar,
 		Archive: archive,
 	})
+	if archive {
+		s.ArchiveSpanWriter, s.ArchiveSpanReader = s.SpanWriter, s.SpanReader
+	}
 }
 
 func (s *ESStorageIntegration) esRefresh() error {
@@ -145,9 +147,9 @@ func testElasticsearchStorage(t *testing.T, allTagsAsFields, archive bool) {
 	require.NoError(t, s.initializeES(allTagsAsFields, archive))
 
 	if archive {
-		t.Run("ArchiveTrace", s.testArchiveTrace)
+		s.RunTests(t, "ArchiveTrace")
 	} else {
-		s.IntegrationTestAll(t)
+		s.RunAll(t)
 	}
 }
 
@@ -162,29 +164,3 @@ func TestElasticsearchStorage_AllTagsAsObjectFields(t *testing.T) {
 func TestElasticsearchStorage_Archive(t *testing.T) {
 	testElasticsearchStorage(t, false, true)
 }
-
-func (s *StorageIntegration) testArchiveTrace(t *testing.T) {
-	defer s.cleanUp(t)
-	tId := model.NewTraceID(uint64(11), uint64(22))
-	expected := &model.Span{
-		OperationName:    "archive_span",
-		StartTime: time.Now().Add(-maxSpanAge*5),
-		TraceID: tId,
-		SpanID: model.NewSpanID(55),
-		References: []model.SpanRef{},
-		Process: model.NewProcess("archived_service", model.KeyValues{}),
-	}
-
-	require.NoError(t, s.SpanWriter.WriteSpan(expected))
-	s.refresh(t)
-
-	var actual *model.Trace
-	found := s.waitForCondition(t, func(t *testing.T) bool {
-		var err error
-		actual, err = s.SpanReader.GetTrace(context.Background(), tId)
-		return err == nil && len(actual.Spans) == 1
-	})
-	if !assert.True(t, found) {
-		CompareTraces(t, &model.Trace{Spans: []*model.Span{expected}}, actual)
-	}
-}
@@ -25,10 +25,11 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 	"github.com/jaegertracing/jaeger/plugin/storage/grpc"
+	"github.com/jaegertracing/jaeger/storage/spanstore/spanstoretest"
 )
 
 type GRPCStorageIntegrationTestSuite struct {
-	StorageIntegration
+	spanstoretest.Suite
 	logger *zap.Logger
 }
 
@@ -79,5 +80,5 @@ func TestGRPCStorage(t *testing.T) {
 	}
 	s := &GRPCStorageIntegrationTestSuite{}
 	require.NoError(t, s.initialize())
-	s.IntegrationTestAll(t)
+	s.RunAll(t)
 }
@@ -33,12 +33,13 @@ import (
 	"github.com/jaegertracing/jaeger/plugin/storage/kafka"
 	"github.com/jaegertracing/jaeger/plugin/storage/memory"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore/spanstoretest"
 )
 
 const defaultLocalKafkaBroker = "127.0.0.1:9092"
 
 type KafkaIntegrationTestSuite struct {
-	StorageIntegration
+	spanstoretest.Suite
 	logger *zap.Logger
 }
 
@@ -93,7 +94,7 @@ func (s *KafkaIntegrationTestSuite) initialize() error {
 	options := app.Options{}
 	options.InitFromViper(v)
 	traceStore := memory.NewStore()
-	spanConsumer, err := builder.CreateConsumer(s.logger, metrics.NullFactory, traceStore, options)
+	spanConsumer, err := builder.CreateConsumer(s.logger, metrics.NullFactory, traceStore, options, nil)
 	if err != nil {
 		return err
 	}
@@ -137,5 +138,5 @@ func TestKafkaStorage(t *testing.T) {
 	}
 	s := &KafkaIntegrationTestSuite{}
 	require.NoError(t, s.initialize())
-	t.Run("GetTrace", s.testGetTrace)
+	s.RunTests(t, "GetTrace")
 }
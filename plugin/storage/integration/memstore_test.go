@@ -16,16 +16,19 @@ package integration
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/pkg/testutils"
+	memLock "github.com/jaegertracing/jaeger/plugin/pkg/distributedlock/memory"
 	"github.com/jaegertracing/jaeger/plugin/storage/memory"
+	"github.com/jaegertracing/jaeger/storage/spanstore/spanstoretest"
 )
 
 type MemStorageIntegrationTestSuite struct {
-	StorageIntegration
+	spanstoretest.Suite
 	logger *zap.Logger
 }
 
@@ -38,6 +41,9 @@ func (s *MemStorageIntegrationTestSuite) initialize() error {
 
 	// TODO DependencyWriter is not implemented in memory store
 
+	s.SamplingStore = memory.NewSamplingStore(time.Hour)
+	s.Lock = memLock.NewLock("localhost")
+
 	s.Refresh = s.refresh
 	s.CleanUp = s.cleanUp
 	return nil
@@ -54,5 +60,5 @@ func (s *MemStorageIntegrationTestSuite) cleanUp() error {
 func TestMemoryStorage(t *testing.T) {
 	s := &MemStorageIntegrationTestSuite{}
 	require.NoError(t, s.initialize())
-	s.IntegrationTestAll(t)
+	s.RunAll(t)
 }
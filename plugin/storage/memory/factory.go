@@ -16,12 +16,19 @@ package memory
 
 import (
 	"flag"
+	"os"
+	"sync"
 
 	"github.com/spf13/viper"
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/pkg/distributedlock"
+	"github.com/jaegertracing/jaeger/pkg/retention"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	memLock "github.com/jaegertracing/jaeger/plugin/pkg/distributedlock/memory"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/samplingstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -31,6 +38,12 @@ type Factory struct {
 	metricsFactory metrics.Factory
 	logger         *zap.Logger
 	store          *Store
+	samplingStore  *SamplingStore
+	// retentionPolicy expires the traces of the services it matches, the other traces never expire
+	retentionPolicy *retention.Policy
+
+	tenantsLock  sync.Mutex
+	tenantStores map[string]*Store
 }
 
 // NewFactory creates a new Factory.
@@ -51,7 +64,14 @@ func (f *Factory) InitFromViper(v *viper.Viper) {
 // Initialize implements storage.Factory
 func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger) error {
 	f.metricsFactory, f.logger = metricsFactory, logger
-	f.store = WithConfiguration(f.options.Configuration)
+	retentionPolicy, err := retention.LoadPolicyFile(f.options.RetentionPolicyFile)
+	if err != nil {
+		return err
+	}
+	f.retentionPolicy = retentionPolicy
+	f.store = WithRetentionPolicy(f.options.Configuration, f.retentionPolicy)
+	f.tenantStores = make(map[string]*Store)
+	f.samplingStore = NewSamplingStore(defaultSamplingRetention)
 	logger.Info("Memory storage initialized", zap.Any("configuration", f.store.config))
 	return nil
 }
@@ -70,3 +90,58 @@ func (f *Factory) CreateSpanWriter() (spanstore.Writer, error) {
 func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
 	return f.store, nil
 }
+
+// CreateLock implements storage.SamplingStoreFactory
+func (f *Factory) CreateLock() (distributedlock.Lock, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	return memLock.NewLock(hostname), nil
+}
+
+// CreateSamplingStore implements storage.SamplingStoreFactory
+func (f *Factory) CreateSamplingStore() (samplingstore.Store, error) {
+	return f.samplingStore, nil
+}
+
+// CreateSpanDeleter implements storage.DeleterFactory
+func (f *Factory) CreateSpanDeleter() (spanstore.Deleter, error) {
+	return f.store, nil
+}
+
+// CreateTenantSpanReader implements storage.TenantFactory
+func (f *Factory) CreateTenantSpanReader(tenant string) (spanstore.Reader, error) {
+	return f.tenantStore(tenant)
+}
+
+// CreateTenantSpanWriter implements storage.TenantFactory
+func (f *Factory) CreateTenantSpanWriter(tenant string) (spanstore.Writer, error) {
+	return f.tenantStore(tenant)
+}
+
+// CreateTenantDependencyReader implements storage.TenantFactory
+func (f *Factory) CreateTenantDependencyReader(tenant string) (dependencystore.Reader, error) {
+	return f.tenantStore(tenant)
+}
+
+// CreateTenantSpanDeleter implements storage.TenantFactory
+func (f *Factory) CreateTenantSpanDeleter(tenant string) (spanstore.Deleter, error) {
+	return f.tenantStore(tenant)
+}
+
+// tenantStore returns the store holding the spans of the tenant, each tenant has its own
+// store with the same configuration as the default one.
+func (f *Factory) tenantStore(tenant string) (*Store, error) {
+	if err := tenancy.ValidateName(tenant); err != nil {
+		return nil, err
+	}
+	f.tenantsLock.Lock()
+	defer f.tenantsLock.Unlock()
+	store, ok := f.tenantStores[tenant]
+	if !ok {
+		store = WithRetentionPolicy(f.options.Configuration, f.retentionPolicy)
+		f.tenantStores[tenant] = store
+	}
+	return store, nil
+}
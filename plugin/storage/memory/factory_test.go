@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/pkg/config"
@@ -25,6 +26,8 @@ import (
 )
 
 var _ storage.Factory = new(Factory)
+var _ storage.SamplingStoreFactory = new(Factory)
+var _ storage.TenantFactory = new(Factory)
 
 func TestMemoryStorageFactory(t *testing.T) {
 	f := NewFactory()
@@ -39,6 +42,45 @@ func TestMemoryStorageFactory(t *testing.T) {
 	depReader, err := f.CreateDependencyReader()
 	assert.NoError(t, err)
 	assert.Equal(t, f.store, depReader)
+	lock, err := f.CreateLock()
+	assert.NoError(t, err)
+	assert.NotNil(t, lock)
+	samplingStore, err := f.CreateSamplingStore()
+	assert.NoError(t, err)
+	assert.Equal(t, f.samplingStore, samplingStore)
+	deleter, err := f.CreateSpanDeleter()
+	assert.NoError(t, err)
+	assert.Equal(t, f.store, deleter)
+}
+
+func TestMemoryStorageFactoryTenants(t *testing.T) {
+	f := NewFactory()
+	assert.NoError(t, f.Initialize(nil, zap.NewNop()))
+	reader, err := f.CreateTenantSpanReader("acme")
+	require.NoError(t, err)
+	writer, err := f.CreateTenantSpanWriter("acme")
+	require.NoError(t, err)
+	depReader, err := f.CreateTenantDependencyReader("acme")
+	require.NoError(t, err)
+	deleter, err := f.CreateTenantSpanDeleter("acme")
+	require.NoError(t, err)
+	assert.Equal(t, reader, writer)
+	assert.Equal(t, reader, depReader)
+	assert.Equal(t, reader, deleter)
+	assert.False(t, reader == f.store)
+
+	otherReader, err := f.CreateTenantSpanReader("globex")
+	require.NoError(t, err)
+	assert.False(t, reader == otherReader)
+
+	_, err = f.CreateTenantSpanReader("../acme")
+	assert.Error(t, err)
+	_, err = f.CreateTenantSpanWriter("")
+	assert.Error(t, err)
+	_, err = f.CreateTenantDependencyReader("Acme")
+	assert.Error(t, err)
+	_, err = f.CreateTenantSpanDeleter("Acme")
+	assert.Error(t, err)
 }
 
 func TestWithConfiguration(t *testing.T) {
@@ -48,3 +90,20 @@ func TestWithConfiguration(t *testing.T) {
 	f.InitFromViper(v)
 	assert.Equal(t, f.options.Configuration.MaxTraces, 100)
 }
+
+func TestInitializeWithRetentionPolicy(t *testing.T) {
+	f := NewFactory()
+	v, command := config.Viperize(f.AddFlags)
+	command.ParseFlags([]string{"--memory.retention-policy-file=../../../pkg/retention/fixtures/retention_policy.yaml"})
+	f.InitFromViper(v)
+	require.NoError(t, f.Initialize(nil, zap.NewNop()))
+	assert.Equal(t, f.retentionPolicy, f.store.retention)
+	tenantStore, err := f.tenantStore("acme")
+	require.NoError(t, err)
+	assert.Equal(t, f.retentionPolicy, tenantStore.retention)
+
+	f = NewFactory()
+	command.ParseFlags([]string{"--memory.retention-policy-file=/not/a/file"})
+	f.InitFromViper(v)
+	assert.Error(t, f.Initialize(nil, zap.NewNop()))
+}
@@ -15,6 +15,7 @@
 package memory
 
 import (
+	"container/heap"
 	"context"
 	"errors"
 	"sort"
@@ -24,6 +25,7 @@ import (
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/model/adjuster"
 	"github.com/jaegertracing/jaeger/pkg/memory/config"
+	"github.com/jaegertracing/jaeger/pkg/retention"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -39,6 +41,47 @@ type Store struct {
 	deduper    adjuster.Adjuster
 	config     config.Configuration
 	index      int
+
+	retention *retention.Policy
+	// expirations holds the time when each trace expires, which is when its last span expires. The traces
+	// with a span of a service not matched by the retention policy never expire and are not in the map.
+	expirations map[model.TraceID]*traceExpiration
+	// expirationOrder holds the same expirations as a min-heap, the earliest first
+	expirationOrder expirationHeap
+	timeNow         func() time.Time
+}
+
+type traceExpiration struct {
+	traceID    model.TraceID
+	expiration time.Time
+	index      int // position in expirationOrder
+}
+
+// expirationHeap implements heap.Interface, ordering the trace expirations by time
+type expirationHeap []*traceExpiration
+
+func (h expirationHeap) Len() int { return len(h) }
+
+func (h expirationHeap) Less(i, j int) bool { return h[i].expiration.Before(h[j].expiration) }
+
+func (h expirationHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *expirationHeap) Push(x interface{}) {
+	e := x.(*traceExpiration)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *expirationHeap) Pop() interface{} {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return e
 }
 
 // NewStore creates an unbounded in-memory store
@@ -48,13 +91,22 @@ func NewStore() *Store {
 
 // WithConfiguration creates a new in memory storage based on the given configuration
 func WithConfiguration(configuration config.Configuration) *Store {
+	return WithRetentionPolicy(configuration, nil)
+}
+
+// WithRetentionPolicy creates a new in memory storage based on the given configuration, which removes
+// the traces once the spans of the services matched by the retention policy have expired
+func WithRetentionPolicy(configuration config.Configuration, retentionPolicy *retention.Policy) *Store {
 	return &Store{
-		ids:        make([]*model.TraceID, configuration.MaxTraces),
-		traces:     map[model.TraceID]*model.Trace{},
-		services:   map[string]struct{}{},
-		operations: map[string]map[string]struct{}{},
-		deduper:    adjuster.SpanIDDeduper(),
-		config:     configuration,
+		ids:         make([]*model.TraceID, configuration.MaxTraces),
+		traces:      map[model.TraceID]*model.Trace{},
+		services:    map[string]struct{}{},
+		operations:  map[string]map[string]struct{}{},
+		deduper:     adjuster.SpanIDDeduper(),
+		config:      configuration,
+		retention:   retentionPolicy,
+		expirations: map[model.TraceID]*traceExpiration{},
+		timeNow:     time.Now,
 	}
 }
 
@@ -65,7 +117,11 @@ func (m *Store) GetDependencies(endTs time.Time, lookback time.Duration) ([]mode
 	defer m.Unlock()
 	deps := map[string]*model.DependencyLink{}
 	startTs := endTs.Add(-1 * lookback)
-	for _, orig := range m.traces {
+	now := m.timeNow()
+	for traceID, orig := range m.traces {
+		if m.isExpired(traceID, now) {
+			continue
+		}
 		// SpanIDDeduper never returns an err
 		trace, _ := m.deduper.Adjust(orig)
 		if m.traceIsBetweenStartAndEnd(startTs, endTs, trace) {
@@ -118,12 +174,15 @@ func (m *Store) traceIsBetweenStartAndEnd(startTs, endTs time.Time, trace *model
 func (m *Store) WriteSpan(span *model.Span) error {
 	m.Lock()
 	defer m.Unlock()
+	now := m.timeNow()
+	m.purgeExpired(now)
 	if _, ok := m.operations[span.Process.ServiceName]; !ok {
 		m.operations[span.Process.ServiceName] = map[string]struct{}{}
 	}
 	m.operations[span.Process.ServiceName][span.OperationName] = struct{}{}
 	m.services[span.Process.ServiceName] = struct{}{}
-	if _, ok := m.traces[span.TraceID]; !ok {
+	_, traceExists := m.traces[span.TraceID]
+	if !traceExists {
 		m.traces[span.TraceID] = &model.Trace{}
 
 		// if we have a limit, let's cleanup the oldest traces
@@ -135,6 +194,7 @@ func (m *Store) WriteSpan(span *model.Span) error {
 			// and we need to remove from the map
 			if m.ids[m.index] != nil {
 				delete(m.traces, *m.ids[m.index])
+				m.removeExpiration(*m.ids[m.index])
 			}
 
 			// update the ring with the trace id
@@ -143,16 +203,99 @@ func (m *Store) WriteSpan(span *model.Span) error {
 
 	}
 	m.traces[span.TraceID].Spans = append(m.traces[span.TraceID].Spans, span)
+	m.updateExpiration(span, traceExists, now)
+
+	return nil
+}
+
+// updateExpiration extends the expiration of the trace of the span to the expiration of the span
+func (m *Store) updateExpiration(span *model.Span, traceExists bool, now time.Time) {
+	e, expires := m.expirations[span.TraceID]
+	if traceExists && !expires {
+		// the trace already holds a span which never expires
+		return
+	}
+	rule, ok := m.retention.Match(span.Process.ServiceName)
+	if !ok {
+		m.removeExpiration(span.TraceID)
+		return
+	}
+	spanExpiration := now.Add(rule.TTL)
+	if !expires {
+		e = &traceExpiration{traceID: span.TraceID, expiration: spanExpiration}
+		m.expirations[span.TraceID] = e
+		heap.Push(&m.expirationOrder, e)
+	} else if spanExpiration.After(e.expiration) {
+		e.expiration = spanExpiration
+		heap.Fix(&m.expirationOrder, e.index)
+	}
+}
+
+// removeExpiration removes the expiration of the trace, if any
+func (m *Store) removeExpiration(traceID model.TraceID) {
+	if e, ok := m.expirations[traceID]; ok {
+		heap.Remove(&m.expirationOrder, e.index)
+		delete(m.expirations, traceID)
+	}
+}
+
+// purgeExpired removes the expired traces, if any, earliest expiration first
+func (m *Store) purgeExpired(now time.Time) {
+	for len(m.expirationOrder) > 0 && !now.Before(m.expirationOrder[0].expiration) {
+		m.deleteTrace(m.expirationOrder[0].traceID)
+	}
+}
 
+// isExpired returns true if the trace has expired but has not been purged yet
+func (m *Store) isExpired(traceID model.TraceID, now time.Time) bool {
+	e, ok := m.expirations[traceID]
+	return ok && !now.Before(e.expiration)
+}
+
+// DeleteTrace implements spanstore.Deleter
+func (m *Store) DeleteTrace(ctx context.Context, traceID model.TraceID) error {
+	m.Lock()
+	defer m.Unlock()
+	m.deleteTrace(traceID)
 	return nil
 }
 
+// DeleteServiceTraces implements spanstore.Deleter
+func (m *Store) DeleteServiceTraces(ctx context.Context, service string, startTimeMin, startTimeMax time.Time) ([]model.TraceID, error) {
+	m.Lock()
+	defer m.Unlock()
+	var traceIDs []model.TraceID
+	for traceID, trace := range m.traces {
+		for _, span := range trace.Spans {
+			if span.Process.ServiceName == service && !span.StartTime.Before(startTimeMin) && !span.StartTime.After(startTimeMax) {
+				traceIDs = append(traceIDs, traceID)
+				break
+			}
+		}
+	}
+	for _, traceID := range traceIDs {
+		m.deleteTrace(traceID)
+	}
+	return traceIDs, nil
+}
+
+func (m *Store) deleteTrace(traceID model.TraceID) {
+	delete(m.traces, traceID)
+	m.removeExpiration(traceID)
+	// the trace must not be evicted again if it is written anew
+	for i, id := range m.ids {
+		if id != nil && *id == traceID {
+			m.ids[i] = nil
+		}
+	}
+}
+
 // GetTrace gets a trace
 func (m *Store) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
 	m.RLock()
 	defer m.RUnlock()
 	retMe := m.traces[traceID]
-	if retMe == nil {
+	if retMe == nil || m.isExpired(traceID, m.timeNow()) {
 		return nil, errTraceNotFound
 	}
 	return retMe, nil
@@ -185,35 +328,102 @@ func (m *Store) GetOperations(ctx context.Context, service string) ([]string, er
 
 // FindTraces returns all traces in the query parameters are satisfied by a trace's span
 func (m *Store) FindTraces(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
+	tagMatchers, err := spanstore.NewTagMatchers(query.TagFilters)
+	if err != nil {
+		return nil, err
+	}
 	m.RLock()
 	defer m.RUnlock()
 	var retMe []*model.Trace
-	for _, trace := range m.traces {
-		if m.validTrace(trace, query) {
+	now := m.timeNow()
+	for traceID, trace := range m.traces {
+		if !m.isExpired(traceID, now) && m.validTrace(trace, query, tagMatchers) {
 			retMe = append(retMe, trace)
 		}
 	}
 
-	// Query result order doesn't matter, as the query frontend will sort them anyway.
-	// However, if query.NumTraces < results, then we should return the newest traces.
+	// The query frontend sorts the results anyway, but they need a deterministic order here
+	// to be paged through: the newest traces first, ties broken by trace ID.
+	sort.Slice(retMe, func(i, j int) bool {
+		spanI, spanJ := retMe[i].Spans[0], retMe[j].Spans[0]
+		if !spanI.StartTime.Equal(spanJ.StartTime) {
+			return spanI.StartTime.After(spanJ.StartTime)
+		}
+		if spanI.TraceID.High != spanJ.TraceID.High {
+			return spanI.TraceID.High > spanJ.TraceID.High
+		}
+		return spanI.TraceID.Low > spanJ.TraceID.Low
+	})
+	if offset := query.Offset(); offset > 0 {
+		if offset >= len(retMe) {
+			return nil, nil
+		}
+		retMe = retMe[offset:]
+	}
 	if query.NumTraces > 0 && len(retMe) > query.NumTraces {
-		sort.Slice(retMe, func(i, j int) bool {
-			return retMe[i].Spans[0].StartTime.Before(retMe[j].Spans[0].StartTime)
-		})
-		retMe = retMe[len(retMe)-query.NumTraces:]
+		retMe = retMe[:query.NumTraces]
 	}
 
 	return retMe, nil
 }
 
-// FindTraceIDs is not implemented.
+// FindSpans returns the spans that satisfy the query parameters, newest first
+func (m *Store) FindSpans(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Span, error) {
+	tagMatchers, err := spanstore.NewTagMatchers(query.TagFilters)
+	if err != nil {
+		return nil, err
+	}
+	m.RLock()
+	defer m.RUnlock()
+	var retMe []*model.Span
+	now := m.timeNow()
+	for traceID, trace := range m.traces {
+		if m.isExpired(traceID, now) {
+			continue
+		}
+		for _, span := range trace.Spans {
+			if m.validSpan(span, query, tagMatchers) {
+				retMe = append(retMe, span)
+			}
+		}
+	}
+
+	sort.Slice(retMe, func(i, j int) bool {
+		spanI, spanJ := retMe[i], retMe[j]
+		if !spanI.StartTime.Equal(spanJ.StartTime) {
+			return spanI.StartTime.After(spanJ.StartTime)
+		}
+		if spanI.TraceID.High != spanJ.TraceID.High {
+			return spanI.TraceID.High > spanJ.TraceID.High
+		}
+		if spanI.TraceID.Low != spanJ.TraceID.Low {
+			return spanI.TraceID.Low > spanJ.TraceID.Low
+		}
+		return spanI.SpanID > spanJ.SpanID
+	})
+	if query.NumTraces > 0 && len(retMe) > query.NumTraces {
+		retMe = retMe[:query.NumTraces]
+	}
+
+	return retMe, nil
+}
+
+// FindTraceIDs returns the IDs of the traces that satisfy the query parameters, in the order of FindTraces
 func (m *Store) FindTraceIDs(ctx context.Context, query *spanstore.TraceQueryParameters) ([]model.TraceID, error) {
-	return nil, errors.New("not implemented")
+	traces, err := m.FindTraces(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	var traceIDs []model.TraceID
+	for _, trace := range traces {
+		traceIDs = append(traceIDs, trace.Spans[0].TraceID)
+	}
+	return traceIDs, nil
 }
 
-func (m *Store) validTrace(trace *model.Trace, query *spanstore.TraceQueryParameters) bool {
+func (m *Store) validTrace(trace *model.Trace, query *spanstore.TraceQueryParameters, tagMatchers []spanstore.TagMatcher) bool {
 	for _, span := range trace.Spans {
-		if m.validSpan(span, query) {
+		if m.validSpan(span, query, tagMatchers) {
 			return true
 		}
 	}
@@ -229,7 +439,7 @@ func findKeyValueMatch(kvs model.KeyValues, key, value string) (model.KeyValue,
 	return model.KeyValue{}, false
 }
 
-func (m *Store) validSpan(span *model.Span, query *spanstore.TraceQueryParameters) bool {
+func (m *Store) validSpan(span *model.Span, query *spanstore.TraceQueryParameters, tagMatchers []spanstore.TagMatcher) bool {
 	if query.ServiceName != span.Process.ServiceName {
 		return false
 	}
@@ -248,21 +458,17 @@ func (m *Store) validSpan(span *model.Span, query *spanstore.TraceQueryParameter
 	if !query.StartTimeMax.IsZero() && span.StartTime.After(query.StartTimeMax) {
 		return false
 	}
-	spanKVs := m.flattenTags(span)
+	spanKVs := spanstore.FlattenTags(span)
 	for queryK, queryV := range query.Tags {
 		// (NB): we cannot use the KeyValues.FindKey function because there can be multiple tags with the same key
 		if _, ok := findKeyValueMatch(spanKVs, queryK, queryV); !ok {
 			return false
 		}
 	}
-	return true
-}
-
-func (m *Store) flattenTags(span *model.Span) model.KeyValues {
-	retMe := span.Tags
-	retMe = append(retMe, span.Process.Tags...)
-	for _, l := range span.Logs {
-		retMe = append(retMe, l.Fields...)
+	for _, tagMatcher := range tagMatchers {
+		if !tagMatcher(spanKVs) {
+			return false
+		}
 	}
-	return retMe
+	return true
 }
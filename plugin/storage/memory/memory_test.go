@@ -16,13 +16,16 @@ package memory
 
 import (
 	"context"
+	"sort"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/memory/config"
+	"github.com/jaegertracing/jaeger/pkg/retention"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -270,11 +273,11 @@ func TestStoreFindTracesLimitGetsMostRecent(t *testing.T) {
 			})
 	}
 
-	// Want the two most recent spans, not any two spans
+	// Want the most recent spans, newest first, not any spans
 	var expectedTraces []*model.Trace
-	for _, span := range spans[storeSize-querySize:] {
+	for i := storeSize - 1; i >= storeSize-querySize; i-- {
 		trace := &model.Trace{
-			Spans: []*model.Span{span},
+			Spans: []*model.Span{spans[i]},
 		}
 		expectedTraces = append(expectedTraces, trace)
 	}
@@ -297,6 +300,142 @@ func TestStoreFindTracesLimitGetsMostRecent(t *testing.T) {
 	}
 }
 
+func TestStoreFindTracesWithContinuationToken(t *testing.T) {
+	memStore := NewStore()
+	start := time.Unix(1000, 0)
+	for i := 0; i < 25; i++ {
+		memStore.WriteSpan(&model.Span{
+			TraceID:   model.NewTraceID(1, uint64(i)),
+			SpanID:    model.NewSpanID(1),
+			StartTime: start.Add(time.Duration(i/2) * time.Second), // pairs of traces share the start time
+			Process: &model.Process{
+				ServiceName: "serviceName",
+			},
+		})
+	}
+
+	query := &spanstore.TraceQueryParameters{
+		ServiceName:  "serviceName",
+		StartTimeMax: start.Add(time.Minute),
+		NumTraces:    10,
+	}
+	seen := make(map[model.TraceID]struct{})
+	var pages []int
+	for {
+		traces, err := memStore.FindTraces(context.Background(), query)
+		require.NoError(t, err)
+		pages = append(pages, len(traces))
+		for _, trace := range traces {
+			_, dup := seen[trace.Spans[0].TraceID]
+			assert.False(t, dup, "trace %v returned twice", trace.Spans[0].TraceID)
+			seen[trace.Spans[0].TraceID] = struct{}{}
+		}
+		if query.ContinuationToken = spanstore.NextContinuationToken(query, len(traces)); query.ContinuationToken == nil {
+			break
+		}
+	}
+	assert.Equal(t, []int{10, 10, 5}, pages)
+	assert.Len(t, seen, 25)
+
+	query.ContinuationToken = &spanstore.ContinuationToken{Offset: 30}
+	traces, err := memStore.FindTraces(context.Background(), query)
+	require.NoError(t, err)
+	assert.Empty(t, traces)
+}
+
+func TestStoreFindSpans(t *testing.T) {
+	memStore := NewStore()
+	for _, span := range []*model.Span{testingSpan, childSpan1, childSpan2, childSpan2_1} {
+		require.NoError(t, memStore.WriteSpan(span))
+	}
+
+	testCases := []struct {
+		query    *spanstore.TraceQueryParameters
+		expected []*model.Span
+	}{
+		{
+			query:    &spanstore.TraceQueryParameters{ServiceName: "childService"},
+			expected: []*model.Span{childSpan2_1, childSpan2, childSpan1},
+		},
+		{
+			query:    &spanstore.TraceQueryParameters{ServiceName: "childService", NumTraces: 2},
+			expected: []*model.Span{childSpan2_1, childSpan2},
+		},
+		{
+			query: &spanstore.TraceQueryParameters{
+				ServiceName:   "serviceName",
+				OperationName: "operationName",
+				Tags:          map[string]string{"tagKey": "tagValue", "logKey": "logValue"},
+			},
+			expected: []*model.Span{testingSpan},
+		},
+		{
+			query:    &spanstore.TraceQueryParameters{ServiceName: "serviceName", Tags: map[string]string{"tagKey": "other"}},
+			expected: nil,
+		},
+		{
+			query:    &spanstore.TraceQueryParameters{ServiceName: "childService", StartTimeMin: time.Unix(400, 0)},
+			expected: nil,
+		},
+	}
+	for _, testCase := range testCases {
+		spans, err := memStore.FindSpans(context.Background(), testCase.query)
+		require.NoError(t, err)
+		assert.Equal(t, testCase.expected, spans)
+	}
+}
+
+func TestStoreFindWithTagFilters(t *testing.T) {
+	memStore := NewStore()
+	for _, span := range []*model.Span{testingSpan, childSpan1, childSpan2} {
+		require.NoError(t, memStore.WriteSpan(span))
+	}
+
+	testCases := []struct {
+		filters       []spanstore.TagFilter
+		expectedSpans int
+	}{
+		{
+			filters:       []spanstore.TagFilter{{Key: "tagKey", Operator: spanstore.TagFilterNotEqual, Value: "tagValue"}},
+			expectedSpans: 0,
+		},
+		{
+			filters:       []spanstore.TagFilter{{Key: "tagKey", Operator: spanstore.TagFilterRegex, Value: "tag.*"}},
+			expectedSpans: 2,
+		},
+		{
+			filters: []spanstore.TagFilter{
+				{Key: "logKey", Operator: spanstore.TagFilterExists},
+				{Key: "missing", Operator: spanstore.TagFilterNotEqual, Value: "x"},
+			},
+			expectedSpans: 2,
+		},
+		{
+			filters:       []spanstore.TagFilter{{Key: "tagKey", Operator: spanstore.TagFilterGreaterThan, Value: "1"}},
+			expectedSpans: 0,
+		},
+	}
+	for _, testCase := range testCases {
+		query := &spanstore.TraceQueryParameters{ServiceName: "childService", TagFilters: testCase.filters}
+		spans, err := memStore.FindSpans(context.Background(), query)
+		require.NoError(t, err)
+		assert.Len(t, spans, testCase.expectedSpans)
+
+		traces, err := memStore.FindTraces(context.Background(), query)
+		require.NoError(t, err)
+		assert.Equal(t, testCase.expectedSpans > 0, len(traces) == 1)
+	}
+
+	query := &spanstore.TraceQueryParameters{
+		ServiceName: "childService",
+		TagFilters:  []spanstore.TagFilter{{Key: "tagKey", Operator: spanstore.TagFilterRegex, Value: "("}},
+	}
+	_, err := memStore.FindTraces(context.Background(), query)
+	assert.Error(t, err)
+	_, err = memStore.FindSpans(context.Background(), query)
+	assert.Error(t, err)
+}
+
 func TestStoreGetTrace(t *testing.T) {
 	testStruct := []struct {
 		query      *spanstore.TraceQueryParameters
@@ -377,9 +516,186 @@ func TestStoreGetTrace(t *testing.T) {
 }
 
 func TestStore_FindTraceIDs(t *testing.T) {
+	withPopulatedMemoryStore(func(store *Store) {
+		traceIDs, err := store.FindTraceIDs(context.Background(), &spanstore.TraceQueryParameters{
+			ServiceName:  "serviceName",
+			StartTimeMin: testingSpan.StartTime.Add(-time.Hour),
+			StartTimeMax: testingSpan.StartTime.Add(time.Hour),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []model.TraceID{testingSpan.TraceID}, traceIDs)
+
+		traceIDs, err = store.FindTraceIDs(context.Background(), &spanstore.TraceQueryParameters{
+			ServiceName: "nonExistingService",
+		})
+		require.NoError(t, err)
+		assert.Empty(t, traceIDs)
+
+		_, err = store.FindTraceIDs(context.Background(), &spanstore.TraceQueryParameters{
+			TagFilters: []spanstore.TagFilter{{Key: "k", Operator: spanstore.TagFilterRegex, Value: "("}},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestStoreDeleteTrace(t *testing.T) {
+	withPopulatedMemoryStore(func(store *Store) {
+		assert.NoError(t, store.DeleteTrace(context.Background(), testingSpan.TraceID))
+		_, err := store.GetTrace(context.Background(), testingSpan.TraceID)
+		assert.Equal(t, errTraceNotFound, err)
+
+		assert.NoError(t, store.DeleteTrace(context.Background(), testingSpan.TraceID), "deleting a missing trace is not an error")
+	})
+}
+
+func TestStoreDeleteServiceTraces(t *testing.T) {
 	withMemoryStore(func(store *Store) {
-		traceIDs, err := store.FindTraceIDs(context.Background(), nil)
-		assert.Nil(t, traceIDs)
-		assert.EqualError(t, err, "not implemented")
+		otherTraceID := model.NewTraceID(0, 2)
+		assert.NoError(t, store.WriteSpan(testingSpan))
+		assert.NoError(t, store.WriteSpan(childSpan1))
+		assert.NoError(t, store.WriteSpan(&model.Span{
+			TraceID:   otherTraceID,
+			SpanID:    model.NewSpanID(1),
+			Process:   &model.Process{ServiceName: "childService"},
+			StartTime: time.Unix(3600, 0),
+		}))
+
+		traceIDs, err := store.DeleteServiceTraces(context.Background(), "childService", time.Unix(0, 0), time.Unix(600, 0))
+		require.NoError(t, err)
+		assert.Equal(t, []model.TraceID{traceID}, traceIDs)
+		_, err = store.GetTrace(context.Background(), traceID)
+		assert.Equal(t, errTraceNotFound, err)
+		_, err = store.GetTrace(context.Background(), otherTraceID)
+		assert.NoError(t, err)
+	})
+}
+
+func TestStoreDeleteTraceWithLimit(t *testing.T) {
+	store := WithConfiguration(config.Configuration{MaxTraces: 3})
+	first, second, third := model.NewTraceID(0, 1), model.NewTraceID(0, 2), model.NewTraceID(0, 3)
+	for _, id := range []model.TraceID{first, second} {
+		assert.NoError(t, store.WriteSpan(&model.Span{TraceID: id, Process: &model.Process{}}))
+	}
+	assert.NoError(t, store.DeleteTrace(context.Background(), first))
+	assert.NoError(t, store.WriteSpan(&model.Span{TraceID: first, Process: &model.Process{}}))
+	// the third trace takes the slot of the deleted trace, which must not evict the trace written anew
+	assert.NoError(t, store.WriteSpan(&model.Span{TraceID: third, Process: &model.Process{}}))
+
+	for _, id := range []model.TraceID{first, second, third} {
+		_, err := store.GetTrace(context.Background(), id)
+		assert.NoError(t, err)
+	}
+}
+
+func TestStoreRetentionPolicy(t *testing.T) {
+	policy, err := retention.NewPolicy([]retention.Rule{
+		{Name: "batch", Services: []string{"batch"}, TTL: time.Hour},
+		{Name: "payments", Services: []string{"payments"}, TTL: 3 * time.Hour},
 	})
+	require.NoError(t, err)
+	store := WithRetentionPolicy(config.Configuration{}, policy)
+	now := time.Unix(0, 0)
+	store.timeNow = func() time.Time { return now }
+
+	batchTraceID, mixedTraceID, otherTraceID := model.NewTraceID(0, 1), model.NewTraceID(0, 2), model.NewTraceID(0, 3)
+	// the spans are children of the first span of their trace
+	writeSpan := func(traceID model.TraceID, spanID uint64, service string) {
+		span := &model.Span{
+			TraceID:   traceID,
+			SpanID:    model.NewSpanID(spanID),
+			Process:   &model.Process{ServiceName: service},
+			StartTime: now,
+		}
+		if spanID > 1 {
+			span.References = []model.SpanRef{model.NewChildOfRef(traceID, model.NewSpanID(1))}
+		}
+		assert.NoError(t, store.WriteSpan(span))
+	}
+	writeSpan(batchTraceID, 1, "batch")
+	// the trace is kept until its last span expires
+	writeSpan(mixedTraceID, 1, "payments")
+	writeSpan(mixedTraceID, 2, "batch")
+	// a span of a service not matched by the policy never expires
+	writeSpan(otherTraceID, 1, "batch")
+	writeSpan(otherTraceID, 2, "frontend")
+	writeSpan(otherTraceID, 3, "batch")
+
+	findTraceIDs := func() []model.TraceID {
+		traceIDs, err := store.FindTraceIDs(context.Background(), &spanstore.TraceQueryParameters{ServiceName: "batch"})
+		require.NoError(t, err)
+		return traceIDs
+	}
+	assert.Len(t, findTraceIDs(), 3)
+
+	now = now.Add(time.Hour)
+	_, err = store.GetTrace(context.Background(), batchTraceID)
+	assert.Equal(t, errTraceNotFound, err, "expired traces are not returned before being purged")
+	assert.Len(t, findTraceIDs(), 2)
+	spans, err := store.FindSpans(context.Background(), &spanstore.TraceQueryParameters{ServiceName: "batch"})
+	require.NoError(t, err)
+	assert.Len(t, spans, 3)
+
+	writeSpan(model.NewTraceID(0, 4), 1, "frontend")
+	assert.Len(t, store.traces, 3)
+	require.Len(t, store.expirationOrder, 1)
+	assert.Equal(t, time.Unix(0, 0).Add(3*time.Hour), store.expirationOrder[0].expiration)
+
+	now = now.Add(2 * time.Hour)
+	deps, err := store.GetDependencies(now.Add(time.Second), 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []model.DependencyLink{{Parent: "batch", Child: "frontend", CallCount: 1}}, deps)
+	writeSpan(model.NewTraceID(0, 4), 2, "frontend")
+	assert.Len(t, store.traces, 2)
+	assert.Empty(t, store.expirationOrder)
+	assert.Empty(t, store.expirations)
+	_, err = store.GetTrace(context.Background(), otherTraceID)
+	assert.NoError(t, err)
+}
+
+func TestStoreRetentionPolicyPurgeOrder(t *testing.T) {
+	policy, err := retention.NewPolicy([]retention.Rule{
+		{Name: "short", Services: []string{"short"}, TTL: time.Hour},
+		{Name: "long", Services: []string{"long"}, TTL: 3 * time.Hour},
+	})
+	require.NoError(t, err)
+	store := WithRetentionPolicy(config.Configuration{}, policy)
+	now := time.Unix(0, 0)
+	store.timeNow = func() time.Time { return now }
+
+	writeSpan := func(traceID uint64, service string) {
+		assert.NoError(t, store.WriteSpan(&model.Span{
+			TraceID: model.NewTraceID(0, traceID),
+			Process: &model.Process{ServiceName: service},
+		}))
+	}
+	writeSpan(1, "long")
+	writeSpan(2, "short")
+	writeSpan(3, "short")
+	now = now.Add(30 * time.Minute)
+	// the expiration of trace 3 is extended past the one of trace 1
+	writeSpan(3, "long")
+	writeSpan(4, "short")
+
+	remaining := func() []model.TraceID {
+		var traceIDs []model.TraceID
+		for _, e := range store.expirationOrder {
+			traceIDs = append(traceIDs, e.traceID)
+		}
+		sort.Slice(traceIDs, func(i, j int) bool { return traceIDs[i].Low < traceIDs[j].Low })
+		return traceIDs
+	}
+	for _, step := range []struct {
+		elapsed  time.Duration
+		expected []model.TraceID
+	}{
+		{elapsed: 30 * time.Minute, expected: []model.TraceID{model.NewTraceID(0, 1), model.NewTraceID(0, 3), model.NewTraceID(0, 4)}},
+		{elapsed: 30 * time.Minute, expected: []model.TraceID{model.NewTraceID(0, 1), model.NewTraceID(0, 3)}},
+		{elapsed: 90 * time.Minute, expected: []model.TraceID{model.NewTraceID(0, 3)}},
+		{elapsed: 30 * time.Minute, expected: nil},
+	} {
+		now = now.Add(step.elapsed)
+		store.purgeExpired(now)
+		assert.Equal(t, step.expected, remaining(), now)
+		assert.Len(t, store.traces, len(step.expected))
+	}
 }
@@ -20,21 +20,28 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/jaegertracing/jaeger/pkg/memory/config"
+	"github.com/jaegertracing/jaeger/pkg/retention"
 )
 
-const limit = "memory.max-traces"
+const (
+	namespace = "memory"
+	limit     = namespace + ".max-traces"
+)
 
 // Options stores the configuration entries for this storage
 type Options struct {
-	Configuration config.Configuration
+	Configuration       config.Configuration
+	RetentionPolicyFile string
 }
 
 // AddFlags from this storage to the CLI
 func (opt *Options) AddFlags(flagSet *flag.FlagSet) {
 	flagSet.Int(limit, opt.Configuration.MaxTraces, "The maximum amount of traces to store in memory")
+	retention.AddFlags(flagSet, namespace)
 }
 
 // InitFromViper initializes the options struct with values from Viper
 func (opt *Options) InitFromViper(v *viper.Viper) {
 	opt.Configuration.MaxTraces = v.GetInt(limit)
+	opt.RetentionPolicyFile = retention.PolicyFileFromViper(v, namespace)
 }
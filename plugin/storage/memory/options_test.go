@@ -25,8 +25,9 @@ import (
 func TestOptionsWithFlags(t *testing.T) {
 	opts := &Options{}
 	v, command := config.Viperize(opts.AddFlags)
-	command.ParseFlags([]string{"--memory.max-traces=100"})
+	command.ParseFlags([]string{"--memory.max-traces=100", "--memory.retention-policy-file=/etc/jaeger/retention.yaml"})
 	opts.InitFromViper(v)
 
 	assert.Equal(t, 100, opts.Configuration.MaxTraces)
+	assert.Equal(t, "/etc/jaeger/retention.yaml", opts.RetentionPolicyFile)
 }
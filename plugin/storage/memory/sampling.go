@@ -0,0 +1,156 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/model"
+)
+
+const (
+	// defaultSamplingRetention is how long the sampling store keeps throughput and probabilities.
+	defaultSamplingRetention = time.Hour
+)
+
+type throughputBucket struct {
+	timestamp  time.Time
+	throughput []*model.Throughput
+}
+
+type probabilitiesBucket struct {
+	timestamp           time.Time
+	hostname            string
+	probabilities       model.ServiceOperationProbabilities
+	probabilitiesAndQPS model.ServiceOperationData
+}
+
+// SamplingStore is an in-memory store of adaptive sampling data
+type SamplingStore struct {
+	sync.RWMutex
+	throughput    []throughputBucket
+	probabilities []probabilitiesBucket
+	retention     time.Duration
+}
+
+// NewSamplingStore creates an in-memory sampling store that keeps data for the given retention period
+func NewSamplingStore(retention time.Duration) *SamplingStore {
+	return &SamplingStore{
+		retention: retention,
+	}
+}
+
+// InsertThroughput implements samplingstore.Store#InsertThroughput.
+func (s *SamplingStore) InsertThroughput(throughput []*model.Throughput) error {
+	s.Lock()
+	defer s.Unlock()
+	now := time.Now()
+	s.purge(now)
+	s.throughput = append(s.throughput, throughputBucket{timestamp: now, throughput: throughput})
+	return nil
+}
+
+// InsertProbabilitiesAndQPS implements samplingstore.Store#InsertProbabilitiesAndQPS.
+func (s *SamplingStore) InsertProbabilitiesAndQPS(
+	hostname string,
+	probabilities model.ServiceOperationProbabilities,
+	qps model.ServiceOperationQPS,
+) error {
+	s.Lock()
+	defer s.Unlock()
+	now := time.Now()
+	s.purge(now)
+	s.probabilities = append(s.probabilities, probabilitiesBucket{
+		timestamp:           now,
+		hostname:            hostname,
+		probabilities:       probabilities,
+		probabilitiesAndQPS: combineProbabilitiesAndQPS(probabilities, qps),
+	})
+	return nil
+}
+
+// GetThroughput implements samplingstore.Store#GetThroughput.
+func (s *SamplingStore) GetThroughput(start, end time.Time) ([]*model.Throughput, error) {
+	s.RLock()
+	defer s.RUnlock()
+	var throughput []*model.Throughput
+	for _, b := range s.throughput {
+		if b.timestamp.After(start) && !b.timestamp.After(end) {
+			throughput = append(throughput, b.throughput...)
+		}
+	}
+	return throughput, nil
+}
+
+// GetProbabilitiesAndQPS implements samplingstore.Store#GetProbabilitiesAndQPS.
+func (s *SamplingStore) GetProbabilitiesAndQPS(start, end time.Time) (map[string][]model.ServiceOperationData, error) {
+	s.RLock()
+	defer s.RUnlock()
+	hostProbabilitiesAndQPS := make(map[string][]model.ServiceOperationData)
+	for _, b := range s.probabilities {
+		if b.timestamp.After(start) && !b.timestamp.After(end) {
+			hostProbabilitiesAndQPS[b.hostname] = append(hostProbabilitiesAndQPS[b.hostname], b.probabilitiesAndQPS)
+		}
+	}
+	return hostProbabilitiesAndQPS, nil
+}
+
+// GetLatestProbabilities implements samplingstore.Store#GetLatestProbabilities.
+func (s *SamplingStore) GetLatestProbabilities() (model.ServiceOperationProbabilities, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if len(s.probabilities) == 0 {
+		return model.ServiceOperationProbabilities{}, nil
+	}
+	return s.probabilities[len(s.probabilities)-1].probabilities, nil
+}
+
+// purge drops the buckets that are older than the retention period. Buckets are appended
+// in chronological order, so only a prefix of each slice needs to be removed.
+// Must be called while holding the write lock.
+func (s *SamplingStore) purge(now time.Time) {
+	if s.retention <= 0 {
+		return
+	}
+	cutoff := now.Add(-s.retention)
+	i := 0
+	for i < len(s.throughput) && s.throughput[i].timestamp.Before(cutoff) {
+		i++
+	}
+	s.throughput = s.throughput[i:]
+	i = 0
+	for i < len(s.probabilities) && s.probabilities[i].timestamp.Before(cutoff) {
+		i++
+	}
+	s.probabilities = s.probabilities[i:]
+}
+
+func combineProbabilitiesAndQPS(
+	probabilities model.ServiceOperationProbabilities,
+	qps model.ServiceOperationQPS,
+) model.ServiceOperationData {
+	probabilitiesAndQPS := make(model.ServiceOperationData)
+	for svc, opProbabilities := range probabilities {
+		probabilitiesAndQPS[svc] = make(map[string]*model.ProbabilityAndQPS)
+		for op, probability := range opProbabilities {
+			probabilitiesAndQPS[svc][op] = &model.ProbabilityAndQPS{
+				Probability: probability,
+				QPS:         qps[svc][op],
+			}
+		}
+	}
+	return probabilitiesAndQPS
+}
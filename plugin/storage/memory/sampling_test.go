@@ -0,0 +1,82 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/model"
+)
+
+func TestSamplingStoreThroughput(t *testing.T) {
+	s := NewSamplingStore(time.Hour)
+	start := time.Now().Add(-time.Minute)
+
+	throughput := []*model.Throughput{
+		{Service: "svc", Operation: "op", Count: 10, Probabilities: map[string]struct{}{"0.1": {}}},
+	}
+	require.NoError(t, s.InsertThroughput(throughput))
+
+	actual, err := s.GetThroughput(start, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, throughput, actual)
+
+	actual, err = s.GetThroughput(start.Add(-time.Hour), start)
+	require.NoError(t, err)
+	assert.Empty(t, actual)
+}
+
+func TestSamplingStoreProbabilitiesAndQPS(t *testing.T) {
+	s := NewSamplingStore(time.Hour)
+
+	latest, err := s.GetLatestProbabilities()
+	require.NoError(t, err)
+	assert.Empty(t, latest)
+
+	start := time.Now().Add(-time.Minute)
+	probabilities := model.ServiceOperationProbabilities{"svc": {"op": 0.5}}
+	qps := model.ServiceOperationQPS{"svc": {"op": 2}}
+	require.NoError(t, s.InsertProbabilitiesAndQPS("host-a", probabilities, qps))
+	newProbabilities := model.ServiceOperationProbabilities{"svc": {"op": 0.2}, "other": {"op": 1}}
+	require.NoError(t, s.InsertProbabilitiesAndQPS("host-b", newProbabilities, qps))
+
+	latest, err = s.GetLatestProbabilities()
+	require.NoError(t, err)
+	assert.Equal(t, newProbabilities, latest)
+
+	actual, err := s.GetProbabilitiesAndQPS(start, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]model.ServiceOperationData{
+		"host-a": {{"svc": {"op": {Probability: 0.5, QPS: 2}}}},
+		"host-b": {{"svc": {"op": {Probability: 0.2, QPS: 2}}, "other": {"op": {Probability: 1, QPS: 0}}}},
+	}, actual)
+}
+
+func TestSamplingStoreRetention(t *testing.T) {
+	s := NewSamplingStore(time.Minute)
+	old := time.Now().Add(-2 * time.Minute)
+	s.throughput = append(s.throughput, throughputBucket{timestamp: old})
+	s.probabilities = append(s.probabilities, probabilitiesBucket{timestamp: old, hostname: "host-a"})
+
+	require.NoError(t, s.InsertThroughput([]*model.Throughput{{Service: "svc", Operation: "op"}}))
+	require.NoError(t, s.InsertProbabilitiesAndQPS("host-b", model.ServiceOperationProbabilities{}, model.ServiceOperationQPS{}))
+	assert.Len(t, s.throughput, 1)
+	require.Len(t, s.probabilities, 1)
+	assert.Equal(t, "host-b", s.probabilities[0].hostname)
+}
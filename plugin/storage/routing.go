@@ -0,0 +1,90 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"flag"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+const routingRulesFile = "routing.rules-file"
+
+// routingRules is the content of the routing rules file, see spanstore.Route for the meaning of the fields.
+type routingRules struct {
+	Routes []routingRule `yaml:"routes"`
+}
+
+type routingRule struct {
+	Name     string   `yaml:"name"`
+	Services []string `yaml:"services"`
+	// Tags are tag filters in the syntax of spanstore.ParseTagFilter, e.g. "env=prod"
+	Tags    []string `yaml:"tags"`
+	Debug   *bool    `yaml:"debug"`
+	Sampled *bool    `yaml:"sampled"`
+	// Writers are span storage types listed in SPAN_STORAGE_TYPE
+	Writers []string `yaml:"writers"`
+}
+
+// addRoutingFlags adds flags for span routing
+func addRoutingFlags(flagSet *flag.FlagSet) {
+	flagSet.String(
+		routingRulesFile,
+		"",
+		"The path for the file with the rules routing spans to the span storage types, in YAML or JSON format. Spans are written to all the span storage types if empty.",
+	)
+}
+
+func (f *Factory) initRoutingFromViper(v *viper.Viper) {
+	f.FactoryConfig.RoutingRulesFile = v.GetString(routingRulesFile)
+}
+
+// loadRoutingRules reads the routes from a file in YAML or JSON format.
+func loadRoutingRules(file string) ([]spanstore.Route, error) {
+	var rules routingRules
+	if err := config.LoadFile(file, "routing rules", &rules); err != nil {
+		return nil, err
+	}
+	return rules.toRoutes()
+}
+
+// toRoutes validates the routing rules and converts them into routes.
+func (rules *routingRules) toRoutes() ([]spanstore.Route, error) {
+	if len(rules.Routes) == 0 {
+		return nil, errors.New("Invalid routing rules: no routes")
+	}
+	routes := make([]spanstore.Route, len(rules.Routes))
+	for i, rule := range rules.Routes {
+		routes[i] = spanstore.Route{
+			Name:     rule.Name,
+			Services: rule.Services,
+			Debug:    rule.Debug,
+			Sampled:  rule.Sampled,
+			Writers:  rule.Writers,
+		}
+		for _, tag := range rule.Tags {
+			filter, err := spanstore.ParseTagFilter(tag)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Invalid routing rules: route %s", rule.Name)
+			}
+			routes[i].Tags = append(routes[i].Tags, filter)
+		}
+	}
+	return routes, nil
+}
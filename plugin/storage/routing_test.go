@@ -0,0 +1,151 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/storage/mocks"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+	spanStoreMocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+// parseRoutingRules converts the content of a routing rules file into routes, like loadRoutingRules
+func parseRoutingRules(content []byte) ([]spanstore.Route, error) {
+	var rules routingRules
+	if err := config.Unmarshal(content, "routing rules", &rules); err != nil {
+		return nil, err
+	}
+	return rules.toRoutes()
+}
+
+func TestParseRoutingRules(t *testing.T) {
+	debug := true
+	yamlRoutes, err := parseRoutingRules([]byte(`
+routes:
+  - name: debug
+    debug: true
+    writers: [badger]
+  - name: prod
+    services: [frontend]
+    tags: ["env=prod"]
+    writers: [elasticsearch]
+`))
+	require.NoError(t, err)
+	expected := []spanstore.Route{
+		{Name: "debug", Debug: &debug, Writers: []string{"badger"}},
+		{
+			Name:     "prod",
+			Services: []string{"frontend"},
+			Tags:     []spanstore.TagFilter{{Key: "env", Operator: spanstore.TagFilterEqual, Value: "prod"}},
+			Writers:  []string{"elasticsearch"},
+		},
+	}
+	assert.Equal(t, expected, yamlRoutes)
+
+	jsonRoutes, err := parseRoutingRules([]byte(`{"routes": [
+		{"name": "debug", "debug": true, "writers": ["badger"]},
+		{"name": "prod", "services": ["frontend"], "tags": ["env=prod"], "writers": ["elasticsearch"]}
+	]}`))
+	require.NoError(t, err)
+	assert.Equal(t, expected, jsonRoutes)
+}
+
+func TestParseRoutingRulesErrors(t *testing.T) {
+	tests := []struct {
+		content string
+		err     string
+	}{
+		{content: `{}`, err: "Invalid routing rules: no routes"},
+		{
+			content: `{"route": []}`,
+			err:     "Failed to unmarshal routing rules: yaml: unmarshal errors:\n  line 1: field route not found in type storage.routingRules",
+		},
+		{
+			content: `{"routes": [{"name": "prod", "tags": ["env"], "writers": ["kafka"]}]}`,
+			err:     `Invalid routing rules: route prod: no operator in "env": invalid tag filter`,
+		},
+	}
+	for _, test := range tests {
+		_, err := parseRoutingRules([]byte(test.content))
+		assert.EqualError(t, err, test.err, test.content)
+	}
+}
+
+func TestCreateRoutingWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "routing")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "routing.yaml")
+	require.NoError(t, ioutil.WriteFile(file, []byte(`
+routes:
+  - name: all
+    writers: [kafka]
+  - name: debug
+    debug: true
+    writers: [cassandra]
+`), 0644))
+
+	cfg := defaultCfg()
+	cfg.SpanWriterTypes = []string{cassandraStorageType, kafkaStorageType}
+	f, err := NewFactory(cfg)
+	require.NoError(t, err)
+	mock := new(mocks.Factory)
+	mock2 := new(mocks.Factory)
+	f.factories[cassandraStorageType] = mock
+	f.factories[kafkaStorageType] = mock2
+	spanWriter := new(spanStoreMocks.Writer)
+	spanWriter2 := new(spanStoreMocks.Writer)
+	mock.On("CreateSpanWriter").Return(spanWriter, nil)
+	mock2.On("CreateSpanWriter").Return(spanWriter2, nil)
+	m := metrics.NullFactory
+	l := zap.NewNop()
+	mock.On("Initialize", m, l).Return(nil)
+	mock2.On("Initialize", m, l).Return(nil)
+
+	v, command := config.Viperize(addDownsamplingFlags, addRoutingFlags)
+	require.NoError(t, command.ParseFlags([]string{"--routing.rules-file=" + file}))
+	f.InitFromViper(v)
+	require.NoError(t, f.Initialize(m, l))
+
+	w, err := f.CreateSpanWriter()
+	require.NoError(t, err)
+	assert.IsType(t, &spanstore.RoutingWriter{}, w)
+
+	span := &model.Span{}
+	spanWriter2.On("WriteSpan", span).Return(nil)
+	require.NoError(t, w.WriteSpan(span))
+	spanWriter.AssertNotCalled(t, "WriteSpan", span)
+	spanWriter2.AssertExpectations(t)
+
+	f.routes = []spanstore.Route{{Name: "es", Writers: []string{elasticsearchStorageType}}}
+	_, err = f.CreateSpanWriter()
+	assert.EqualError(t, err, "Invalid routing rules: route es: unknown writer elasticsearch")
+
+	f.RoutingRulesFile = filepath.Join(dir, "missing.yaml")
+	err = f.Initialize(m, l)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Failed to open routing rules file")
+}
@@ -34,6 +34,34 @@ var _ = time.Kitchen
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 
+type TraceDiffNode_Status int32
+
+const (
+	TraceDiffNode_COMMON  TraceDiffNode_Status = 0
+	TraceDiffNode_ADDED   TraceDiffNode_Status = 1
+	TraceDiffNode_REMOVED TraceDiffNode_Status = 2
+)
+
+var TraceDiffNode_Status_name = map[int32]string{
+	0: "COMMON",
+	1: "ADDED",
+	2: "REMOVED",
+}
+
+var TraceDiffNode_Status_value = map[string]int32{
+	"COMMON":  0,
+	"ADDED":   1,
+	"REMOVED": 2,
+}
+
+func (x TraceDiffNode_Status) String() string {
+	return proto.EnumName(TraceDiffNode_Status_name, int32(x))
+}
+
+func (TraceDiffNode_Status) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_26651706f9f8a4f0, []int{16, 0}
+}
+
 type GetTraceRequest struct {
 	TraceID              github_com_jaegertracing_jaeger_model.TraceID `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3,customtype=github.com/jaegertracing/jaeger/model.TraceID" json:"trace_id"`
 	XXX_NoUnkeyedLiteral struct{}                                      `json:"-"`
@@ -75,10 +103,13 @@ func (m *GetTraceRequest) XXX_DiscardUnknown() {
 var xxx_messageInfo_GetTraceRequest proto.InternalMessageInfo
 
 type SpansResponseChunk struct {
-	Spans                []model.Span `protobuf:"bytes,1,rep,name=spans,proto3" json:"spans"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	Spans []model.Span `protobuf:"bytes,1,rep,name=spans,proto3" json:"spans"`
+	// Set on a trailing chunk without spans if there are more FindTraces results,
+	// to be fetched by repeating the request with this continuation token.
+	NextContinuationToken string   `protobuf:"bytes,2,opt,name=next_continuation_token,json=nextContinuationToken,proto3" json:"next_continuation_token,omitempty"`
+	XXX_NoUnkeyedLiteral  struct{} `json:"-"`
+	XXX_unrecognized      []byte   `json:"-"`
+	XXX_sizecache         int32    `json:"-"`
 }
 
 func (m *SpansResponseChunk) Reset()         { *m = SpansResponseChunk{} }
@@ -121,6 +152,13 @@ func (m *SpansResponseChunk) GetSpans() []model.Span {
 	return nil
 }
 
+func (m *SpansResponseChunk) GetNextContinuationToken() string {
+	if m != nil {
+		return m.NextContinuationToken
+	}
+	return ""
+}
+
 type ArchiveTraceRequest struct {
 	TraceID              github_com_jaegertracing_jaeger_model.TraceID `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3,customtype=github.com/jaegertracing/jaeger/model.TraceID" json:"trace_id"`
 	XXX_NoUnkeyedLiteral struct{}                                      `json:"-"`
@@ -209,6 +247,7 @@ type TraceQueryParameters struct {
 	DurationMin          time.Duration     `protobuf:"bytes,6,opt,name=duration_min,json=durationMin,proto3,stdduration" json:"duration_min"`
 	DurationMax          time.Duration     `protobuf:"bytes,7,opt,name=duration_max,json=durationMax,proto3,stdduration" json:"duration_max"`
 	SearchDepth          int32             `protobuf:"varint,8,opt,name=search_depth,json=searchDepth,proto3" json:"search_depth,omitempty"`
+	TagFilters           []TagFilter       `protobuf:"bytes,9,rep,name=tag_filters,json=tagFilters,proto3" json:"tag_filters"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
 	XXX_unrecognized     []byte            `json:"-"`
 	XXX_sizecache        int32             `json:"-"`
@@ -303,18 +342,92 @@ func (m *TraceQueryParameters) GetSearchDepth() int32 {
 	return 0
 }
 
+func (m *TraceQueryParameters) GetTagFilters() []TagFilter {
+	if m != nil {
+		return m.TagFilters
+	}
+	return nil
+}
+
+// TagFilter matches a span tag with an operator, in addition to the exact tag matches.
+type TagFilter struct {
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// One of "=", "!=", "=~" (regular expression), ">", "<" (numeric) or "exists".
+	Operator             string   `protobuf:"bytes,2,opt,name=operator,proto3" json:"operator,omitempty"`
+	Value                string   `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TagFilter) Reset()         { *m = TagFilter{} }
+func (m *TagFilter) String() string { return proto.CompactTextString(m) }
+func (*TagFilter) ProtoMessage()    {}
+func (*TagFilter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_26651706f9f8a4f0, []int{5}
+}
+func (m *TagFilter) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TagFilter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TagFilter.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TagFilter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TagFilter.Merge(m, src)
+}
+func (m *TagFilter) XXX_Size() int {
+	return m.Size()
+}
+func (m *TagFilter) XXX_DiscardUnknown() {
+	xxx_messageInfo_TagFilter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TagFilter proto.InternalMessageInfo
+
+func (m *TagFilter) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *TagFilter) GetOperator() string {
+	if m != nil {
+		return m.Operator
+	}
+	return ""
+}
+
+func (m *TagFilter) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
 type FindTracesRequest struct {
-	Query                *TraceQueryParameters `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
-	XXX_unrecognized     []byte                `json:"-"`
-	XXX_sizecache        int32                 `json:"-"`
+	Query *TraceQueryParameters `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Continuation token of a previous response, to fetch the next page of results.
+	ContinuationToken    string   `protobuf:"bytes,2,opt,name=continuation_token,json=continuationToken,proto3" json:"continuation_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *FindTracesRequest) Reset()         { *m = FindTracesRequest{} }
 func (m *FindTracesRequest) String() string { return proto.CompactTextString(m) }
 func (*FindTracesRequest) ProtoMessage()    {}
 func (*FindTracesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_26651706f9f8a4f0, []int{5}
+	return fileDescriptor_26651706f9f8a4f0, []int{6}
 }
 func (m *FindTracesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -350,6 +463,61 @@ func (m *FindTracesRequest) GetQuery() *TraceQueryParameters {
 	return nil
 }
 
+func (m *FindTracesRequest) GetContinuationToken() string {
+	if m != nil {
+		return m.ContinuationToken
+	}
+	return ""
+}
+
+type FindSpansRequest struct {
+	// Selects the individual spans to return, search_depth limits the number of spans.
+	Query                *TraceQueryParameters `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *FindSpansRequest) Reset()         { *m = FindSpansRequest{} }
+func (m *FindSpansRequest) String() string { return proto.CompactTextString(m) }
+func (*FindSpansRequest) ProtoMessage()    {}
+func (*FindSpansRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_26651706f9f8a4f0, []int{7}
+}
+func (m *FindSpansRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FindSpansRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_FindSpansRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *FindSpansRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FindSpansRequest.Merge(m, src)
+}
+func (m *FindSpansRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *FindSpansRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FindSpansRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FindSpansRequest proto.InternalMessageInfo
+
+func (m *FindSpansRequest) GetQuery() *TraceQueryParameters {
+	if m != nil {
+		return m.Query
+	}
+	return nil
+}
+
 type GetServicesRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -360,7 +528,7 @@ func (m *GetServicesRequest) Reset()         { *m = GetServicesRequest{} }
 func (m *GetServicesRequest) String() string { return proto.CompactTextString(m) }
 func (*GetServicesRequest) ProtoMessage()    {}
 func (*GetServicesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_26651706f9f8a4f0, []int{6}
+	return fileDescriptor_26651706f9f8a4f0, []int{8}
 }
 func (m *GetServicesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -400,7 +568,7 @@ func (m *GetServicesResponse) Reset()         { *m = GetServicesResponse{} }
 func (m *GetServicesResponse) String() string { return proto.CompactTextString(m) }
 func (*GetServicesResponse) ProtoMessage()    {}
 func (*GetServicesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_26651706f9f8a4f0, []int{7}
+	return fileDescriptor_26651706f9f8a4f0, []int{9}
 }
 func (m *GetServicesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -447,7 +615,7 @@ func (m *GetOperationsRequest) Reset()         { *m = GetOperationsRequest{} }
 func (m *GetOperationsRequest) String() string { return proto.CompactTextString(m) }
 func (*GetOperationsRequest) ProtoMessage()    {}
 func (*GetOperationsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_26651706f9f8a4f0, []int{8}
+	return fileDescriptor_26651706f9f8a4f0, []int{10}
 }
 func (m *GetOperationsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -494,7 +662,7 @@ func (m *GetOperationsResponse) Reset()         { *m = GetOperationsResponse{} }
 func (m *GetOperationsResponse) String() string { return proto.CompactTextString(m) }
 func (*GetOperationsResponse) ProtoMessage()    {}
 func (*GetOperationsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_26651706f9f8a4f0, []int{9}
+	return fileDescriptor_26651706f9f8a4f0, []int{11}
 }
 func (m *GetOperationsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -542,7 +710,7 @@ func (m *GetDependenciesRequest) Reset()         { *m = GetDependenciesRequest{}
 func (m *GetDependenciesRequest) String() string { return proto.CompactTextString(m) }
 func (*GetDependenciesRequest) ProtoMessage()    {}
 func (*GetDependenciesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_26651706f9f8a4f0, []int{10}
+	return fileDescriptor_26651706f9f8a4f0, []int{12}
 }
 func (m *GetDependenciesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -596,7 +764,7 @@ func (m *GetDependenciesResponse) Reset()         { *m = GetDependenciesResponse
 func (m *GetDependenciesResponse) String() string { return proto.CompactTextString(m) }
 func (*GetDependenciesResponse) ProtoMessage()    {}
 func (*GetDependenciesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_26651706f9f8a4f0, []int{11}
+	return fileDescriptor_26651706f9f8a4f0, []int{13}
 }
 func (m *GetDependenciesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -632,783 +800,1083 @@ func (m *GetDependenciesResponse) GetDependencies() []model.DependencyLink {
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*GetTraceRequest)(nil), "jaeger.api_v2.GetTraceRequest")
-	golang_proto.RegisterType((*GetTraceRequest)(nil), "jaeger.api_v2.GetTraceRequest")
-	proto.RegisterType((*SpansResponseChunk)(nil), "jaeger.api_v2.SpansResponseChunk")
-	golang_proto.RegisterType((*SpansResponseChunk)(nil), "jaeger.api_v2.SpansResponseChunk")
-	proto.RegisterType((*ArchiveTraceRequest)(nil), "jaeger.api_v2.ArchiveTraceRequest")
-	golang_proto.RegisterType((*ArchiveTraceRequest)(nil), "jaeger.api_v2.ArchiveTraceRequest")
-	proto.RegisterType((*ArchiveTraceResponse)(nil), "jaeger.api_v2.ArchiveTraceResponse")
-	golang_proto.RegisterType((*ArchiveTraceResponse)(nil), "jaeger.api_v2.ArchiveTraceResponse")
-	proto.RegisterType((*TraceQueryParameters)(nil), "jaeger.api_v2.TraceQueryParameters")
-	golang_proto.RegisterType((*TraceQueryParameters)(nil), "jaeger.api_v2.TraceQueryParameters")
-	proto.RegisterMapType((map[string]string)(nil), "jaeger.api_v2.TraceQueryParameters.TagsEntry")
-	golang_proto.RegisterMapType((map[string]string)(nil), "jaeger.api_v2.TraceQueryParameters.TagsEntry")
-	proto.RegisterType((*FindTracesRequest)(nil), "jaeger.api_v2.FindTracesRequest")
-	golang_proto.RegisterType((*FindTracesRequest)(nil), "jaeger.api_v2.FindTracesRequest")
-	proto.RegisterType((*GetServicesRequest)(nil), "jaeger.api_v2.GetServicesRequest")
-	golang_proto.RegisterType((*GetServicesRequest)(nil), "jaeger.api_v2.GetServicesRequest")
-	proto.RegisterType((*GetServicesResponse)(nil), "jaeger.api_v2.GetServicesResponse")
-	golang_proto.RegisterType((*GetServicesResponse)(nil), "jaeger.api_v2.GetServicesResponse")
-	proto.RegisterType((*GetOperationsRequest)(nil), "jaeger.api_v2.GetOperationsRequest")
-	golang_proto.RegisterType((*GetOperationsRequest)(nil), "jaeger.api_v2.GetOperationsRequest")
-	proto.RegisterType((*GetOperationsResponse)(nil), "jaeger.api_v2.GetOperationsResponse")
-	golang_proto.RegisterType((*GetOperationsResponse)(nil), "jaeger.api_v2.GetOperationsResponse")
-	proto.RegisterType((*GetDependenciesRequest)(nil), "jaeger.api_v2.GetDependenciesRequest")
-	golang_proto.RegisterType((*GetDependenciesRequest)(nil), "jaeger.api_v2.GetDependenciesRequest")
-	proto.RegisterType((*GetDependenciesResponse)(nil), "jaeger.api_v2.GetDependenciesResponse")
-	golang_proto.RegisterType((*GetDependenciesResponse)(nil), "jaeger.api_v2.GetDependenciesResponse")
+type CompareTracesRequest struct {
+	TraceIDA             github_com_jaegertracing_jaeger_model.TraceID `protobuf:"bytes,1,opt,name=trace_id_a,json=traceIdA,proto3,customtype=github.com/jaegertracing/jaeger/model.TraceID" json:"trace_id_a"`
+	TraceIDB             github_com_jaegertracing_jaeger_model.TraceID `protobuf:"bytes,2,opt,name=trace_id_b,json=traceIdB,proto3,customtype=github.com/jaegertracing/jaeger/model.TraceID" json:"trace_id_b"`
+	XXX_NoUnkeyedLiteral struct{}                                      `json:"-"`
+	XXX_unrecognized     []byte                                        `json:"-"`
+	XXX_sizecache        int32                                         `json:"-"`
 }
 
-func init() { proto.RegisterFile("api_v2/query.proto", fileDescriptor_26651706f9f8a4f0) }
-func init() { golang_proto.RegisterFile("api_v2/query.proto", fileDescriptor_26651706f9f8a4f0) }
-
-var fileDescriptor_26651706f9f8a4f0 = []byte{
-	// 965 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x55, 0x4f, 0x73, 0xdb, 0x44,
-	0x14, 0x47, 0x4e, 0x1c, 0xdb, 0x4f, 0x76, 0x4b, 0xd7, 0x4e, 0x6b, 0x04, 0xd8, 0x8e, 0x42, 0x21,
-	0xd3, 0x21, 0xda, 0xd4, 0x0c, 0x53, 0xc8, 0x05, 0xe2, 0xa6, 0xf5, 0xa4, 0x03, 0xa5, 0xa8, 0x39,
-	0xc1, 0xc1, 0xb3, 0xb1, 0x16, 0x59, 0x24, 0x5e, 0xa9, 0xd2, 0xda, 0x4d, 0x86, 0xe1, 0xc2, 0x27,
-	0x60, 0xe0, 0xc2, 0x89, 0x2b, 0x27, 0xbe, 0x03, 0xc7, 0x1e, 0x99, 0xe1, 0xc6, 0x21, 0x30, 0x81,
-	0x0f, 0xc2, 0xec, 0x1f, 0x39, 0xb6, 0xec, 0x49, 0x43, 0x0f, 0x3d, 0x69, 0xf7, 0xed, 0x7b, 0xbf,
-	0xf7, 0xef, 0xf7, 0x9e, 0x00, 0x91, 0x28, 0xe8, 0x8d, 0xdb, 0xf8, 0xc9, 0x88, 0xc6, 0x27, 0x4e,
-	0x14, 0x87, 0x3c, 0x44, 0x95, 0xaf, 0x09, 0xf5, 0x69, 0xec, 0xa8, 0x27, 0xcb, 0x1c, 0x86, 0x1e,
-	0x3d, 0x52, 0x6f, 0x56, 0xcd, 0x0f, 0xfd, 0x50, 0x1e, 0xb1, 0x38, 0x69, 0xe9, 0x1b, 0x7e, 0x18,
-	0xfa, 0x47, 0x14, 0x93, 0x28, 0xc0, 0x84, 0xb1, 0x90, 0x13, 0x1e, 0x84, 0x2c, 0xd1, 0xaf, 0x4d,
-	0xfd, 0x2a, 0x6f, 0x07, 0xa3, 0xaf, 0x30, 0x0f, 0x86, 0x34, 0xe1, 0x64, 0x18, 0x69, 0x85, 0x46,
-	0x56, 0xc1, 0x1b, 0xc5, 0x12, 0x41, 0xbf, 0xbf, 0x2b, 0x3f, 0xfd, 0x4d, 0x9f, 0xb2, 0xcd, 0xe4,
-	0x29, 0xf1, 0x7d, 0x1a, 0xe3, 0x30, 0x92, 0x2e, 0xe6, 0xdd, 0xd9, 0x0c, 0xae, 0x76, 0x29, 0xdf,
-	0x8f, 0x49, 0x9f, 0xba, 0xf4, 0xc9, 0x88, 0x26, 0x1c, 0x7d, 0x09, 0x45, 0x2e, 0xee, 0xbd, 0xc0,
-	0xab, 0x1b, 0x2d, 0x63, 0xa3, 0xdc, 0xf9, 0xf8, 0xd9, 0x69, 0xf3, 0x95, 0x3f, 0x4f, 0x9b, 0x9b,
-	0x7e, 0xc0, 0x07, 0xa3, 0x03, 0xa7, 0x1f, 0x0e, 0xb1, 0x4a, 0x5b, 0x28, 0x06, 0xcc, 0xd7, 0x37,
-	0xac, 0x92, 0x97, 0x68, 0x7b, 0xbb, 0x67, 0xa7, 0xcd, 0x82, 0x3e, 0xba, 0x05, 0x89, 0xb8, 0xe7,
-	0xd9, 0xf7, 0x00, 0x3d, 0x8e, 0x08, 0x4b, 0x5c, 0x9a, 0x44, 0x21, 0x4b, 0xe8, 0xdd, 0xc1, 0x88,
-	0x1d, 0x22, 0x0c, 0xf9, 0x44, 0x48, 0xeb, 0x46, 0x6b, 0x69, 0xc3, 0x6c, 0x57, 0x9d, 0x99, 0xa2,
-	0x3a, 0xc2, 0xa2, 0xb3, 0x2c, 0x82, 0x70, 0x95, 0x9e, 0x1d, 0x43, 0x75, 0x27, 0xee, 0x0f, 0x82,
-	0x31, 0x7d, 0x79, 0xa1, 0x5f, 0x87, 0xda, 0xac, 0x4f, 0x95, 0x81, 0xfd, 0xcb, 0x32, 0xd4, 0xa4,
-	0xe4, 0x73, 0x41, 0x8b, 0x47, 0x24, 0x26, 0x43, 0xca, 0x69, 0x9c, 0xa0, 0x35, 0x28, 0x27, 0x34,
-	0x1e, 0x07, 0x7d, 0xda, 0x63, 0x64, 0x48, 0x65, 0x44, 0x25, 0xd7, 0xd4, 0xb2, 0x87, 0x64, 0x48,
-	0xd1, 0x4d, 0xb8, 0x12, 0x46, 0x54, 0xf5, 0x4f, 0x29, 0xe5, 0xa4, 0x52, 0x65, 0x22, 0x95, 0x6a,
-	0x3b, 0xb0, 0xcc, 0x89, 0x9f, 0xd4, 0x97, 0x64, 0x79, 0x36, 0x33, 0xe5, 0x59, 0xe4, 0xdc, 0xd9,
-	0x27, 0x7e, 0x72, 0x8f, 0xf1, 0xf8, 0xc4, 0x95, 0xa6, 0xe8, 0x01, 0x5c, 0x49, 0x38, 0x89, 0x79,
-	0x4f, 0xf0, 0xa9, 0x37, 0x0c, 0x58, 0x7d, 0xb9, 0x65, 0x6c, 0x98, 0x6d, 0xcb, 0x51, 0x7c, 0x72,
-	0x52, 0x3e, 0x39, 0xfb, 0x29, 0xe1, 0x3a, 0x45, 0x51, 0xbc, 0xef, 0xff, 0x6a, 0x1a, 0x6e, 0x59,
-	0xda, 0x8a, 0x97, 0x4f, 0x03, 0x96, 0xc5, 0x22, 0xc7, 0xf5, 0xfc, 0x8b, 0x61, 0x91, 0x63, 0x74,
-	0x1f, 0xca, 0x29, 0x81, 0x65, 0x54, 0x2b, 0x12, 0xe9, 0xb5, 0x39, 0xa4, 0x5d, 0xad, 0xa4, 0x80,
-	0x7e, 0x12, 0x40, 0x66, 0x6a, 0x28, 0x62, 0x9a, 0xc1, 0x21, 0xc7, 0xf5, 0xc2, 0x8b, 0xe0, 0x90,
-	0x63, 0xd5, 0x34, 0x12, 0xf7, 0x07, 0x3d, 0x8f, 0x46, 0x7c, 0x50, 0x2f, 0xb6, 0x8c, 0x8d, 0xbc,
-	0x68, 0x9a, 0x90, 0xed, 0x0a, 0x91, 0x75, 0x07, 0x4a, 0x93, 0xea, 0xa2, 0x57, 0x61, 0xe9, 0x90,
-	0x9e, 0xe8, 0xde, 0x8a, 0x23, 0xaa, 0x41, 0x7e, 0x4c, 0x8e, 0x46, 0x69, 0x2b, 0xd5, 0x65, 0x3b,
-	0xf7, 0x81, 0x61, 0x3f, 0x84, 0x6b, 0xf7, 0x03, 0xe6, 0xc9, 0x7e, 0x25, 0x29, 0x67, 0x3f, 0x84,
-	0xbc, 0xdc, 0x27, 0x12, 0xc2, 0x6c, 0xaf, 0x5f, 0xa2, 0xb9, 0xae, 0xb2, 0xb0, 0x6b, 0x80, 0xba,
-	0x94, 0x3f, 0x56, 0x7c, 0x4a, 0x01, 0xed, 0xdb, 0x50, 0x9d, 0x91, 0x2a, 0x9a, 0x22, 0x0b, 0x8a,
-	0x9a, 0x79, 0x6a, 0xcc, 0x4a, 0xee, 0xe4, 0x6e, 0x6f, 0x41, 0xad, 0x4b, 0xf9, 0x67, 0x29, 0xe7,
-	0x26, 0xb1, 0xd5, 0xa1, 0xa0, 0x75, 0x74, 0x82, 0xe9, 0xd5, 0xbe, 0x03, 0xab, 0x19, 0x0b, 0xed,
-	0xa6, 0x01, 0x30, 0xe1, 0x6e, 0xea, 0x68, 0x4a, 0x62, 0xff, 0x6c, 0xc0, 0xf5, 0x2e, 0xe5, 0xbb,
-	0x34, 0xa2, 0xcc, 0xa3, 0xac, 0x1f, 0x9c, 0x57, 0xe2, 0x2e, 0xc0, 0x39, 0xad, 0x74, 0x39, 0x2e,
-	0x47, 0xa9, 0xd2, 0x84, 0x52, 0xe8, 0x23, 0x28, 0x52, 0xe6, 0x29, 0x88, 0xdc, 0xff, 0x80, 0x28,
-	0x50, 0xe6, 0x09, 0xb9, 0x7d, 0x00, 0x37, 0xe6, 0xe2, 0xd3, 0xb9, 0x75, 0xa1, 0xec, 0x4d, 0xc9,
-	0xf5, 0xb6, 0x7a, 0x33, 0xd3, 0xb1, 0x89, 0xe9, 0xc9, 0x27, 0x01, 0x3b, 0xd4, 0x7b, 0x6b, 0xc6,
-	0xb0, 0xfd, 0x6b, 0x1e, 0xca, 0xb2, 0xa7, 0xba, 0x4b, 0xe8, 0x10, 0x8a, 0xe9, 0x1a, 0x46, 0x8d,
-	0x0c, 0x5e, 0x66, 0x3f, 0x5b, 0x6b, 0x0b, 0xb6, 0xe3, 0xec, 0x3e, 0xb5, 0xad, 0xef, 0xfe, 0xf8,
-	0xf7, 0xc7, 0x5c, 0x0d, 0x21, 0x2c, 0x97, 0x57, 0x82, 0xbf, 0x49, 0xd7, 0xe2, 0xb7, 0x5b, 0x06,
-	0xe2, 0x50, 0x9e, 0x5e, 0x64, 0xc8, 0xce, 0x00, 0x2e, 0xd8, 0xac, 0xd6, 0xfa, 0x85, 0x3a, 0x7a,
-	0x13, 0xbe, 0x2e, 0xdd, 0xae, 0xda, 0x55, 0x4c, 0xd4, 0xf3, 0x94, 0x5f, 0xe4, 0x03, 0x9c, 0x93,
-	0x1f, 0xb5, 0x32, 0x78, 0x73, 0x73, 0x71, 0x99, 0x34, 0x91, 0xf4, 0x57, 0xb6, 0x0b, 0x58, 0x8d,
-	0xe7, 0xb6, 0x71, 0x6b, 0xcb, 0x40, 0x3e, 0x98, 0x53, 0xfc, 0x47, 0x6b, 0xf3, 0xe5, 0xcc, 0x4c,
-	0x8c, 0x65, 0x5f, 0xa4, 0xa2, 0x73, 0xbb, 0x26, 0x7d, 0x99, 0xa8, 0x84, 0xd3, 0xa9, 0x41, 0x21,
-	0x54, 0x66, 0x66, 0x00, 0xad, 0xcf, 0xe3, 0xcc, 0xcd, 0x94, 0xf5, 0xd6, 0xc5, 0x4a, 0xda, 0x5d,
-	0x55, 0xba, 0xab, 0x20, 0x13, 0x9f, 0xcf, 0x0e, 0x7a, 0x2a, 0x7f, 0xd6, 0xd3, 0xd4, 0x44, 0x37,
-	0xe7, 0xd1, 0x16, 0x8c, 0x96, 0xf5, 0xf6, 0xf3, 0xd4, 0xb4, 0xdb, 0x55, 0xe9, 0xf6, 0x2a, 0xaa,
-	0xe0, 0x69, 0xbe, 0x76, 0xc6, 0x3f, 0xec, 0x74, 0x50, 0xbe, 0xbd, 0x74, 0xdb, 0xd9, 0xba, 0x95,
-	0x33, 0x72, 0xf1, 0xfb, 0x00, 0x0f, 0x24, 0x5e, 0x6b, 0xe7, 0xd1, 0x1e, 0x7a, 0x67, 0xc0, 0x79,
-	0x94, 0x6c, 0x63, 0xfc, 0x9c, 0x7f, 0xec, 0xb3, 0xb3, 0x86, 0xf1, 0xfb, 0x59, 0xc3, 0xf8, 0xfb,
-	0xac, 0x61, 0xfc, 0xf6, 0x4f, 0xc3, 0x80, 0x1b, 0x41, 0xe8, 0xcc, 0x28, 0xea, 0xf0, 0xbe, 0x58,
-	0x51, 0xdf, 0x83, 0x15, 0x39, 0xb2, 0xef, 0xfd, 0x17, 0x00, 0x00, 0xff, 0xff, 0xee, 0x7a, 0xc9,
-	0xa7, 0x79, 0x09, 0x00, 0x00,
+func (m *CompareTracesRequest) Reset()         { *m = CompareTracesRequest{} }
+func (m *CompareTracesRequest) String() string { return proto.CompactTextString(m) }
+func (*CompareTracesRequest) ProtoMessage()    {}
+func (*CompareTracesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_26651706f9f8a4f0, []int{14}
+}
+func (m *CompareTracesRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CompareTracesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CompareTracesRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CompareTracesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompareTracesRequest.Merge(m, src)
+}
+func (m *CompareTracesRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *CompareTracesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompareTracesRequest.DiscardUnknown(m)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+var xxx_messageInfo_CompareTracesRequest proto.InternalMessageInfo
 
-// QueryServiceClient is the client API for QueryService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type QueryServiceClient interface {
-	GetTrace(ctx context.Context, in *GetTraceRequest, opts ...grpc.CallOption) (QueryService_GetTraceClient, error)
-	ArchiveTrace(ctx context.Context, in *ArchiveTraceRequest, opts ...grpc.CallOption) (*ArchiveTraceResponse, error)
-	FindTraces(ctx context.Context, in *FindTracesRequest, opts ...grpc.CallOption) (QueryService_FindTracesClient, error)
-	GetServices(ctx context.Context, in *GetServicesRequest, opts ...grpc.CallOption) (*GetServicesResponse, error)
-	GetOperations(ctx context.Context, in *GetOperationsRequest, opts ...grpc.CallOption) (*GetOperationsResponse, error)
-	GetDependencies(ctx context.Context, in *GetDependenciesRequest, opts ...grpc.CallOption) (*GetDependenciesResponse, error)
+type TagDiff struct {
+	Key                  string          `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	ValueA               *model.KeyValue `protobuf:"bytes,2,opt,name=value_a,json=valueA,proto3" json:"value_a,omitempty"`
+	ValueB               *model.KeyValue `protobuf:"bytes,3,opt,name=value_b,json=valueB,proto3" json:"value_b,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
-type queryServiceClient struct {
-	cc *grpc.ClientConn
+func (m *TagDiff) Reset()         { *m = TagDiff{} }
+func (m *TagDiff) String() string { return proto.CompactTextString(m) }
+func (*TagDiff) ProtoMessage()    {}
+func (*TagDiff) Descriptor() ([]byte, []int) {
+	return fileDescriptor_26651706f9f8a4f0, []int{15}
 }
-
-func NewQueryServiceClient(cc *grpc.ClientConn) QueryServiceClient {
-	return &queryServiceClient{cc}
+func (m *TagDiff) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-
-func (c *queryServiceClient) GetTrace(ctx context.Context, in *GetTraceRequest, opts ...grpc.CallOption) (QueryService_GetTraceClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_QueryService_serviceDesc.Streams[0], "/jaeger.api_v2.QueryService/GetTrace", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &queryServiceGetTraceClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+func (m *TagDiff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TagDiff.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return x, nil
 }
-
-type QueryService_GetTraceClient interface {
-	Recv() (*SpansResponseChunk, error)
-	grpc.ClientStream
+func (m *TagDiff) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TagDiff.Merge(m, src)
 }
-
-type queryServiceGetTraceClient struct {
-	grpc.ClientStream
+func (m *TagDiff) XXX_Size() int {
+	return m.Size()
+}
+func (m *TagDiff) XXX_DiscardUnknown() {
+	xxx_messageInfo_TagDiff.DiscardUnknown(m)
 }
 
-func (x *queryServiceGetTraceClient) Recv() (*SpansResponseChunk, error) {
-	m := new(SpansResponseChunk)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+var xxx_messageInfo_TagDiff proto.InternalMessageInfo
+
+func (m *TagDiff) GetKey() string {
+	if m != nil {
+		return m.Key
 	}
-	return m, nil
+	return ""
 }
 
-func (c *queryServiceClient) ArchiveTrace(ctx context.Context, in *ArchiveTraceRequest, opts ...grpc.CallOption) (*ArchiveTraceResponse, error) {
-	out := new(ArchiveTraceResponse)
-	err := c.cc.Invoke(ctx, "/jaeger.api_v2.QueryService/ArchiveTrace", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *TagDiff) GetValueA() *model.KeyValue {
+	if m != nil {
+		return m.ValueA
 	}
-	return out, nil
+	return nil
 }
 
-func (c *queryServiceClient) FindTraces(ctx context.Context, in *FindTracesRequest, opts ...grpc.CallOption) (QueryService_FindTracesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_QueryService_serviceDesc.Streams[1], "/jaeger.api_v2.QueryService/FindTraces", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &queryServiceFindTracesClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+func (m *TagDiff) GetValueB() *model.KeyValue {
+	if m != nil {
+		return m.ValueB
 	}
-	return x, nil
+	return nil
 }
 
-type QueryService_FindTracesClient interface {
-	Recv() (*SpansResponseChunk, error)
-	grpc.ClientStream
+type TraceDiffNode struct {
+	Path                 string                                       `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	ServiceName          string                                       `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	OperationName        string                                       `protobuf:"bytes,3,opt,name=operation_name,json=operationName,proto3" json:"operation_name,omitempty"`
+	Status               TraceDiffNode_Status                         `protobuf:"varint,4,opt,name=status,proto3,enum=jaeger.api_v2.TraceDiffNode_Status" json:"status,omitempty"`
+	SpanIDA              github_com_jaegertracing_jaeger_model.SpanID `protobuf:"bytes,5,opt,name=span_id_a,json=spanIdA,proto3,customtype=github.com/jaegertracing/jaeger/model.SpanID" json:"span_id_a"`
+	SpanIDB              github_com_jaegertracing_jaeger_model.SpanID `protobuf:"bytes,6,opt,name=span_id_b,json=spanIdB,proto3,customtype=github.com/jaegertracing/jaeger/model.SpanID" json:"span_id_b"`
+	DurationA            time.Duration                                `protobuf:"bytes,7,opt,name=duration_a,json=durationA,proto3,stdduration" json:"duration_a"`
+	DurationB            time.Duration                                `protobuf:"bytes,8,opt,name=duration_b,json=durationB,proto3,stdduration" json:"duration_b"`
+	DurationDelta        time.Duration                                `protobuf:"bytes,9,opt,name=duration_delta,json=durationDelta,proto3,stdduration" json:"duration_delta"`
+	TagDiffs             []TagDiff                                    `protobuf:"bytes,10,rep,name=tag_diffs,json=tagDiffs,proto3" json:"tag_diffs"`
+	XXX_NoUnkeyedLiteral struct{}                                     `json:"-"`
+	XXX_unrecognized     []byte                                       `json:"-"`
+	XXX_sizecache        int32                                        `json:"-"`
+}
+
+func (m *TraceDiffNode) Reset()         { *m = TraceDiffNode{} }
+func (m *TraceDiffNode) String() string { return proto.CompactTextString(m) }
+func (*TraceDiffNode) ProtoMessage()    {}
+func (*TraceDiffNode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_26651706f9f8a4f0, []int{16}
+}
+func (m *TraceDiffNode) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-
-type queryServiceFindTracesClient struct {
-	grpc.ClientStream
+func (m *TraceDiffNode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TraceDiffNode.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TraceDiffNode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceDiffNode.Merge(m, src)
+}
+func (m *TraceDiffNode) XXX_Size() int {
+	return m.Size()
+}
+func (m *TraceDiffNode) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceDiffNode.DiscardUnknown(m)
 }
 
-func (x *queryServiceFindTracesClient) Recv() (*SpansResponseChunk, error) {
-	m := new(SpansResponseChunk)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+var xxx_messageInfo_TraceDiffNode proto.InternalMessageInfo
+
+func (m *TraceDiffNode) GetPath() string {
+	if m != nil {
+		return m.Path
 	}
-	return m, nil
+	return ""
 }
 
-func (c *queryServiceClient) GetServices(ctx context.Context, in *GetServicesRequest, opts ...grpc.CallOption) (*GetServicesResponse, error) {
-	out := new(GetServicesResponse)
-	err := c.cc.Invoke(ctx, "/jaeger.api_v2.QueryService/GetServices", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *TraceDiffNode) GetServiceName() string {
+	if m != nil {
+		return m.ServiceName
 	}
-	return out, nil
+	return ""
 }
 
-func (c *queryServiceClient) GetOperations(ctx context.Context, in *GetOperationsRequest, opts ...grpc.CallOption) (*GetOperationsResponse, error) {
-	out := new(GetOperationsResponse)
-	err := c.cc.Invoke(ctx, "/jaeger.api_v2.QueryService/GetOperations", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *TraceDiffNode) GetOperationName() string {
+	if m != nil {
+		return m.OperationName
 	}
-	return out, nil
+	return ""
 }
 
-func (c *queryServiceClient) GetDependencies(ctx context.Context, in *GetDependenciesRequest, opts ...grpc.CallOption) (*GetDependenciesResponse, error) {
-	out := new(GetDependenciesResponse)
-	err := c.cc.Invoke(ctx, "/jaeger.api_v2.QueryService/GetDependencies", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *TraceDiffNode) GetStatus() TraceDiffNode_Status {
+	if m != nil {
+		return m.Status
 	}
-	return out, nil
+	return TraceDiffNode_COMMON
 }
 
-// QueryServiceServer is the server API for QueryService service.
-type QueryServiceServer interface {
-	GetTrace(*GetTraceRequest, QueryService_GetTraceServer) error
-	ArchiveTrace(context.Context, *ArchiveTraceRequest) (*ArchiveTraceResponse, error)
-	FindTraces(*FindTracesRequest, QueryService_FindTracesServer) error
-	GetServices(context.Context, *GetServicesRequest) (*GetServicesResponse, error)
-	GetOperations(context.Context, *GetOperationsRequest) (*GetOperationsResponse, error)
-	GetDependencies(context.Context, *GetDependenciesRequest) (*GetDependenciesResponse, error)
+func (m *TraceDiffNode) GetDurationA() time.Duration {
+	if m != nil {
+		return m.DurationA
+	}
+	return 0
 }
 
-func RegisterQueryServiceServer(s *grpc.Server, srv QueryServiceServer) {
-	s.RegisterService(&_QueryService_serviceDesc, srv)
+func (m *TraceDiffNode) GetDurationB() time.Duration {
+	if m != nil {
+		return m.DurationB
+	}
+	return 0
 }
 
-func _QueryService_GetTrace_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(GetTraceRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func (m *TraceDiffNode) GetDurationDelta() time.Duration {
+	if m != nil {
+		return m.DurationDelta
 	}
-	return srv.(QueryServiceServer).GetTrace(m, &queryServiceGetTraceServer{stream})
+	return 0
 }
 
-type QueryService_GetTraceServer interface {
-	Send(*SpansResponseChunk) error
-	grpc.ServerStream
+func (m *TraceDiffNode) GetTagDiffs() []TagDiff {
+	if m != nil {
+		return m.TagDiffs
+	}
+	return nil
 }
 
-type queryServiceGetTraceServer struct {
-	grpc.ServerStream
+type CompareTracesResponse struct {
+	Nodes                []TraceDiffNode `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
-func (x *queryServiceGetTraceServer) Send(m *SpansResponseChunk) error {
-	return x.ServerStream.SendMsg(m)
+func (m *CompareTracesResponse) Reset()         { *m = CompareTracesResponse{} }
+func (m *CompareTracesResponse) String() string { return proto.CompactTextString(m) }
+func (*CompareTracesResponse) ProtoMessage()    {}
+func (*CompareTracesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_26651706f9f8a4f0, []int{17}
 }
-
-func _QueryService_ArchiveTrace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ArchiveTraceRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(QueryServiceServer).ArchiveTrace(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/jaeger.api_v2.QueryService/ArchiveTrace",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(QueryServiceServer).ArchiveTrace(ctx, req.(*ArchiveTraceRequest))
+func (m *CompareTracesResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CompareTracesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CompareTracesResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return interceptor(ctx, in, info, handler)
+}
+func (m *CompareTracesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompareTracesResponse.Merge(m, src)
+}
+func (m *CompareTracesResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *CompareTracesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompareTracesResponse.DiscardUnknown(m)
 }
 
-func _QueryService_FindTraces_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(FindTracesRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+var xxx_messageInfo_CompareTracesResponse proto.InternalMessageInfo
+
+func (m *CompareTracesResponse) GetNodes() []TraceDiffNode {
+	if m != nil {
+		return m.Nodes
 	}
-	return srv.(QueryServiceServer).FindTraces(m, &queryServiceFindTracesServer{stream})
+	return nil
 }
 
-type QueryService_FindTracesServer interface {
-	Send(*SpansResponseChunk) error
-	grpc.ServerStream
+type DeleteTraceRequest struct {
+	TraceID              github_com_jaegertracing_jaeger_model.TraceID `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3,customtype=github.com/jaegertracing/jaeger/model.TraceID" json:"trace_id"`
+	XXX_NoUnkeyedLiteral struct{}                                      `json:"-"`
+	XXX_unrecognized     []byte                                        `json:"-"`
+	XXX_sizecache        int32                                         `json:"-"`
 }
 
-type queryServiceFindTracesServer struct {
-	grpc.ServerStream
+func (m *DeleteTraceRequest) Reset()         { *m = DeleteTraceRequest{} }
+func (m *DeleteTraceRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteTraceRequest) ProtoMessage()    {}
+func (*DeleteTraceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_26651706f9f8a4f0, []int{18}
+}
+func (m *DeleteTraceRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DeleteTraceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DeleteTraceRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DeleteTraceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteTraceRequest.Merge(m, src)
+}
+func (m *DeleteTraceRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *DeleteTraceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteTraceRequest.DiscardUnknown(m)
 }
 
-func (x *queryServiceFindTracesServer) Send(m *SpansResponseChunk) error {
-	return x.ServerStream.SendMsg(m)
+var xxx_messageInfo_DeleteTraceRequest proto.InternalMessageInfo
+
+type DeleteTraceResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func _QueryService_GetServices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetServicesRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(QueryServiceServer).GetServices(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/jaeger.api_v2.QueryService/GetServices",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(QueryServiceServer).GetServices(ctx, req.(*GetServicesRequest))
+func (m *DeleteTraceResponse) Reset()         { *m = DeleteTraceResponse{} }
+func (m *DeleteTraceResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteTraceResponse) ProtoMessage()    {}
+func (*DeleteTraceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_26651706f9f8a4f0, []int{19}
+}
+func (m *DeleteTraceResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DeleteTraceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DeleteTraceResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return interceptor(ctx, in, info, handler)
+}
+func (m *DeleteTraceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteTraceResponse.Merge(m, src)
+}
+func (m *DeleteTraceResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *DeleteTraceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteTraceResponse.DiscardUnknown(m)
 }
 
-func _QueryService_GetOperations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetOperationsRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(QueryServiceServer).GetOperations(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/jaeger.api_v2.QueryService/GetOperations",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(QueryServiceServer).GetOperations(ctx, req.(*GetOperationsRequest))
-	}
-	return interceptor(ctx, in, info, handler)
+var xxx_messageInfo_DeleteTraceResponse proto.InternalMessageInfo
+
+type DeleteServiceTracesRequest struct {
+	Service string `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	// Traces started within [start_time_min, start_time_max] are deleted.
+	StartTimeMin         time.Time `protobuf:"bytes,2,opt,name=start_time_min,json=startTimeMin,proto3,stdtime" json:"start_time_min"`
+	StartTimeMax         time.Time `protobuf:"bytes,3,opt,name=start_time_max,json=startTimeMax,proto3,stdtime" json:"start_time_max"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
 }
 
-func _QueryService_GetDependencies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetDependenciesRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *DeleteServiceTracesRequest) Reset()         { *m = DeleteServiceTracesRequest{} }
+func (m *DeleteServiceTracesRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteServiceTracesRequest) ProtoMessage()    {}
+func (*DeleteServiceTracesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_26651706f9f8a4f0, []int{20}
+}
+func (m *DeleteServiceTracesRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DeleteServiceTracesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DeleteServiceTracesRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if interceptor == nil {
-		return srv.(QueryServiceServer).GetDependencies(ctx, in)
+}
+func (m *DeleteServiceTracesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteServiceTracesRequest.Merge(m, src)
+}
+func (m *DeleteServiceTracesRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *DeleteServiceTracesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteServiceTracesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteServiceTracesRequest proto.InternalMessageInfo
+
+func (m *DeleteServiceTracesRequest) GetService() string {
+	if m != nil {
+		return m.Service
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/jaeger.api_v2.QueryService/GetDependencies",
+	return ""
+}
+
+func (m *DeleteServiceTracesRequest) GetStartTimeMin() time.Time {
+	if m != nil {
+		return m.StartTimeMin
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(QueryServiceServer).GetDependencies(ctx, req.(*GetDependenciesRequest))
+	return time.Time{}
+}
+
+func (m *DeleteServiceTracesRequest) GetStartTimeMax() time.Time {
+	if m != nil {
+		return m.StartTimeMax
 	}
-	return interceptor(ctx, in, info, handler)
+	return time.Time{}
 }
 
-var _QueryService_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "jaeger.api_v2.QueryService",
-	HandlerType: (*QueryServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "ArchiveTrace",
-			Handler:    _QueryService_ArchiveTrace_Handler,
-		},
-		{
-			MethodName: "GetServices",
-			Handler:    _QueryService_GetServices_Handler,
-		},
-		{
-			MethodName: "GetOperations",
-			Handler:    _QueryService_GetOperations_Handler,
-		},
-		{
-			MethodName: "GetDependencies",
-			Handler:    _QueryService_GetDependencies_Handler,
-		},
-	},
-	Streams: []grpc.StreamDesc{
-		{
-			StreamName:    "GetTrace",
-			Handler:       _QueryService_GetTrace_Handler,
-			ServerStreams: true,
-		},
-		{
-			StreamName:    "FindTraces",
-			Handler:       _QueryService_FindTraces_Handler,
-			ServerStreams: true,
-		},
-	},
-	Metadata: "api_v2/query.proto",
+type DeleteServiceTracesResponse struct {
+	TraceIDs             []github_com_jaegertracing_jaeger_model.TraceID `protobuf:"bytes,1,rep,name=trace_ids,json=traceIds,proto3,customtype=github.com/jaegertracing/jaeger/model.TraceID" json:"trace_ids"`
+	XXX_NoUnkeyedLiteral struct{}                                        `json:"-"`
+	XXX_unrecognized     []byte                                          `json:"-"`
+	XXX_sizecache        int32                                           `json:"-"`
 }
 
-func (m *GetTraceRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *DeleteServiceTracesResponse) Reset()         { *m = DeleteServiceTracesResponse{} }
+func (m *DeleteServiceTracesResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteServiceTracesResponse) ProtoMessage()    {}
+func (*DeleteServiceTracesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_26651706f9f8a4f0, []int{21}
+}
+func (m *DeleteServiceTracesResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DeleteServiceTracesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DeleteServiceTracesResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return dAtA[:n], nil
+}
+func (m *DeleteServiceTracesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteServiceTracesResponse.Merge(m, src)
+}
+func (m *DeleteServiceTracesResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *DeleteServiceTracesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteServiceTracesResponse.DiscardUnknown(m)
 }
 
-func (m *GetTraceRequest) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	dAtA[i] = 0xa
-	i++
-	i = encodeVarintQuery(dAtA, i, uint64(m.TraceID.Size()))
-	n1, err := m.TraceID.MarshalTo(dAtA[i:])
-	if err != nil {
-		return 0, err
-	}
-	i += n1
-	if m.XXX_unrecognized != nil {
-		i += copy(dAtA[i:], m.XXX_unrecognized)
-	}
-	return i, nil
+var xxx_messageInfo_DeleteServiceTracesResponse proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterEnum("jaeger.api_v2.TraceDiffNode_Status", TraceDiffNode_Status_name, TraceDiffNode_Status_value)
+	golang_proto.RegisterEnum("jaeger.api_v2.TraceDiffNode_Status", TraceDiffNode_Status_name, TraceDiffNode_Status_value)
+	proto.RegisterType((*GetTraceRequest)(nil), "jaeger.api_v2.GetTraceRequest")
+	golang_proto.RegisterType((*GetTraceRequest)(nil), "jaeger.api_v2.GetTraceRequest")
+	proto.RegisterType((*SpansResponseChunk)(nil), "jaeger.api_v2.SpansResponseChunk")
+	golang_proto.RegisterType((*SpansResponseChunk)(nil), "jaeger.api_v2.SpansResponseChunk")
+	proto.RegisterType((*ArchiveTraceRequest)(nil), "jaeger.api_v2.ArchiveTraceRequest")
+	golang_proto.RegisterType((*ArchiveTraceRequest)(nil), "jaeger.api_v2.ArchiveTraceRequest")
+	proto.RegisterType((*ArchiveTraceResponse)(nil), "jaeger.api_v2.ArchiveTraceResponse")
+	golang_proto.RegisterType((*ArchiveTraceResponse)(nil), "jaeger.api_v2.ArchiveTraceResponse")
+	proto.RegisterType((*TraceQueryParameters)(nil), "jaeger.api_v2.TraceQueryParameters")
+	golang_proto.RegisterType((*TraceQueryParameters)(nil), "jaeger.api_v2.TraceQueryParameters")
+	proto.RegisterMapType((map[string]string)(nil), "jaeger.api_v2.TraceQueryParameters.TagsEntry")
+	golang_proto.RegisterMapType((map[string]string)(nil), "jaeger.api_v2.TraceQueryParameters.TagsEntry")
+	proto.RegisterType((*TagFilter)(nil), "jaeger.api_v2.TagFilter")
+	golang_proto.RegisterType((*TagFilter)(nil), "jaeger.api_v2.TagFilter")
+	proto.RegisterType((*FindTracesRequest)(nil), "jaeger.api_v2.FindTracesRequest")
+	golang_proto.RegisterType((*FindTracesRequest)(nil), "jaeger.api_v2.FindTracesRequest")
+	proto.RegisterType((*FindSpansRequest)(nil), "jaeger.api_v2.FindSpansRequest")
+	golang_proto.RegisterType((*FindSpansRequest)(nil), "jaeger.api_v2.FindSpansRequest")
+	proto.RegisterType((*GetServicesRequest)(nil), "jaeger.api_v2.GetServicesRequest")
+	golang_proto.RegisterType((*GetServicesRequest)(nil), "jaeger.api_v2.GetServicesRequest")
+	proto.RegisterType((*GetServicesResponse)(nil), "jaeger.api_v2.GetServicesResponse")
+	golang_proto.RegisterType((*GetServicesResponse)(nil), "jaeger.api_v2.GetServicesResponse")
+	proto.RegisterType((*GetOperationsRequest)(nil), "jaeger.api_v2.GetOperationsRequest")
+	golang_proto.RegisterType((*GetOperationsRequest)(nil), "jaeger.api_v2.GetOperationsRequest")
+	proto.RegisterType((*GetOperationsResponse)(nil), "jaeger.api_v2.GetOperationsResponse")
+	golang_proto.RegisterType((*GetOperationsResponse)(nil), "jaeger.api_v2.GetOperationsResponse")
+	proto.RegisterType((*GetDependenciesRequest)(nil), "jaeger.api_v2.GetDependenciesRequest")
+	golang_proto.RegisterType((*GetDependenciesRequest)(nil), "jaeger.api_v2.GetDependenciesRequest")
+	proto.RegisterType((*GetDependenciesResponse)(nil), "jaeger.api_v2.GetDependenciesResponse")
+	golang_proto.RegisterType((*GetDependenciesResponse)(nil), "jaeger.api_v2.GetDependenciesResponse")
+	proto.RegisterType((*CompareTracesRequest)(nil), "jaeger.api_v2.CompareTracesRequest")
+	golang_proto.RegisterType((*CompareTracesRequest)(nil), "jaeger.api_v2.CompareTracesRequest")
+	proto.RegisterType((*TagDiff)(nil), "jaeger.api_v2.TagDiff")
+	golang_proto.RegisterType((*TagDiff)(nil), "jaeger.api_v2.TagDiff")
+	proto.RegisterType((*TraceDiffNode)(nil), "jaeger.api_v2.TraceDiffNode")
+	golang_proto.RegisterType((*TraceDiffNode)(nil), "jaeger.api_v2.TraceDiffNode")
+	proto.RegisterType((*CompareTracesResponse)(nil), "jaeger.api_v2.CompareTracesResponse")
+	golang_proto.RegisterType((*CompareTracesResponse)(nil), "jaeger.api_v2.CompareTracesResponse")
+	proto.RegisterType((*DeleteTraceRequest)(nil), "jaeger.api_v2.DeleteTraceRequest")
+	golang_proto.RegisterType((*DeleteTraceRequest)(nil), "jaeger.api_v2.DeleteTraceRequest")
+	proto.RegisterType((*DeleteTraceResponse)(nil), "jaeger.api_v2.DeleteTraceResponse")
+	golang_proto.RegisterType((*DeleteTraceResponse)(nil), "jaeger.api_v2.DeleteTraceResponse")
+	proto.RegisterType((*DeleteServiceTracesRequest)(nil), "jaeger.api_v2.DeleteServiceTracesRequest")
+	golang_proto.RegisterType((*DeleteServiceTracesRequest)(nil), "jaeger.api_v2.DeleteServiceTracesRequest")
+	proto.RegisterType((*DeleteServiceTracesResponse)(nil), "jaeger.api_v2.DeleteServiceTracesResponse")
+	golang_proto.RegisterType((*DeleteServiceTracesResponse)(nil), "jaeger.api_v2.DeleteServiceTracesResponse")
 }
 
-func (m *SpansResponseChunk) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+func init() { proto.RegisterFile("api_v2/query.proto", fileDescriptor_26651706f9f8a4f0) }
+func init() { golang_proto.RegisterFile("api_v2/query.proto", fileDescriptor_26651706f9f8a4f0) }
+
+var fileDescriptor_26651706f9f8a4f0 = []byte{
+	// 1551 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x57, 0xcb, 0x6f, 0xdb, 0x46,
+	0x1a, 0x0f, 0x25, 0xeb, 0xc1, 0x4f, 0x52, 0xe2, 0x8c, 0xe5, 0x98, 0xab, 0x64, 0x6d, 0x87, 0x79,
+	0xac, 0xd7, 0x88, 0x45, 0xc7, 0x8b, 0xdd, 0x3c, 0xf6, 0x90, 0x95, 0x2c, 0xc7, 0x48, 0x76, 0x1d,
+	0x27, 0x8c, 0x91, 0xc3, 0x06, 0xa8, 0x30, 0x12, 0xc7, 0x34, 0x6b, 0x8b, 0x54, 0xc8, 0x91, 0x63,
+	0xa3, 0x30, 0x0a, 0xf4, 0xd8, 0x53, 0x1f, 0x97, 0x9e, 0x0a, 0xf4, 0x3f, 0xe9, 0x31, 0xa7, 0xa2,
+	0x40, 0x2f, 0x45, 0x0f, 0x69, 0xe1, 0xf6, 0xd0, 0x3f, 0xa3, 0x98, 0x07, 0x65, 0x51, 0x64, 0x1c,
+	0xc5, 0x35, 0x72, 0x22, 0x67, 0xe6, 0xfb, 0x7e, 0xbf, 0x6f, 0xe6, 0x7b, 0xcc, 0x37, 0x80, 0x70,
+	0xd7, 0x69, 0xee, 0x2e, 0x19, 0x2f, 0x7a, 0xc4, 0xdf, 0xaf, 0x76, 0x7d, 0x8f, 0x7a, 0xa8, 0xf4,
+	0x21, 0x26, 0x36, 0xf1, 0xab, 0x62, 0xa9, 0x52, 0xe8, 0x78, 0x16, 0xd9, 0x11, 0x6b, 0x95, 0xb2,
+	0xed, 0xd9, 0x1e, 0xff, 0x35, 0xd8, 0x9f, 0x9c, 0xbd, 0x64, 0x7b, 0x9e, 0xbd, 0x43, 0x0c, 0xdc,
+	0x75, 0x0c, 0xec, 0xba, 0x1e, 0xc5, 0xd4, 0xf1, 0xdc, 0x40, 0xae, 0xce, 0xc8, 0x55, 0x3e, 0x6a,
+	0xf5, 0x36, 0x0d, 0xea, 0x74, 0x48, 0x40, 0x71, 0xa7, 0x2b, 0x05, 0xa6, 0x87, 0x05, 0xac, 0x9e,
+	0xcf, 0x11, 0xe4, 0xfa, 0x0d, 0xfe, 0x69, 0x2f, 0xd8, 0xc4, 0x5d, 0x08, 0x5e, 0x62, 0xdb, 0x26,
+	0xbe, 0xe1, 0x75, 0x39, 0x45, 0x9c, 0x4e, 0x77, 0xe1, 0xdc, 0x2a, 0xa1, 0x1b, 0x3e, 0x6e, 0x13,
+	0x93, 0xbc, 0xe8, 0x91, 0x80, 0xa2, 0xe7, 0x90, 0xa7, 0x6c, 0xdc, 0x74, 0x2c, 0x4d, 0x99, 0x55,
+	0xe6, 0x8a, 0xf5, 0xff, 0xbc, 0x7a, 0x3d, 0x73, 0xe6, 0xa7, 0xd7, 0x33, 0x0b, 0xb6, 0x43, 0xb7,
+	0x7a, 0xad, 0x6a, 0xdb, 0xeb, 0x18, 0x62, 0xdb, 0x4c, 0xd0, 0x71, 0x6d, 0x39, 0x32, 0xc4, 0xe6,
+	0x39, 0xda, 0x83, 0xc6, 0xe1, 0xeb, 0x99, 0x9c, 0xfc, 0x35, 0x73, 0x1c, 0xf1, 0x81, 0xa5, 0x1f,
+	0x00, 0x7a, 0xda, 0xc5, 0x6e, 0x60, 0x92, 0xa0, 0xeb, 0xb9, 0x01, 0x59, 0xde, 0xea, 0xb9, 0xdb,
+	0xc8, 0x80, 0x4c, 0xc0, 0x66, 0x35, 0x65, 0x36, 0x3d, 0x57, 0x58, 0x9a, 0xa8, 0x46, 0x0e, 0xb5,
+	0xca, 0x34, 0xea, 0x63, 0xcc, 0x08, 0x53, 0xc8, 0xa1, 0x7f, 0xc1, 0x94, 0x4b, 0xf6, 0x68, 0xb3,
+	0xed, 0xb9, 0xd4, 0x71, 0x7b, 0x7c, 0x4b, 0x4d, 0xea, 0x6d, 0x13, 0x57, 0x4b, 0xcd, 0x2a, 0x73,
+	0xaa, 0x39, 0xc9, 0x96, 0x97, 0x07, 0x56, 0x37, 0xd8, 0xa2, 0xee, 0xc3, 0x44, 0xcd, 0x6f, 0x6f,
+	0x39, 0xbb, 0xe4, 0xfd, 0x6d, 0xf9, 0x02, 0x94, 0xa3, 0x9c, 0x62, 0xe7, 0xfa, 0xef, 0x63, 0x50,
+	0xe6, 0x33, 0x4f, 0x58, 0x38, 0x3d, 0xc6, 0x3e, 0xee, 0x10, 0x4a, 0xfc, 0x00, 0x5d, 0x86, 0x62,
+	0x40, 0xfc, 0x5d, 0xa7, 0x4d, 0x9a, 0x2e, 0xee, 0x10, 0x6e, 0x91, 0x6a, 0x16, 0xe4, 0xdc, 0x23,
+	0xdc, 0x21, 0xe8, 0x1a, 0x9c, 0xf5, 0xba, 0x44, 0xf8, 0x5d, 0x08, 0x89, 0x6d, 0x97, 0xfa, 0xb3,
+	0x5c, 0xac, 0x06, 0x63, 0x14, 0xdb, 0x81, 0x96, 0xe6, 0xc7, 0xba, 0x30, 0x74, 0xac, 0x49, 0xe4,
+	0xd5, 0x0d, 0x6c, 0x07, 0x2b, 0x2e, 0xf5, 0xf7, 0x4d, 0xae, 0x8a, 0x1e, 0xc2, 0xd9, 0x80, 0x62,
+	0x9f, 0x36, 0x59, 0x1c, 0x36, 0x3b, 0x8e, 0xab, 0x8d, 0xcd, 0x2a, 0x73, 0x85, 0xa5, 0x4a, 0x55,
+	0xc4, 0x61, 0x35, 0x8c, 0xc3, 0xea, 0x46, 0x18, 0xa8, 0xf5, 0x3c, 0x3b, 0xbc, 0xcf, 0x7e, 0x9e,
+	0x51, 0xcc, 0x22, 0xd7, 0x65, 0x2b, 0x6b, 0x8e, 0x3b, 0x8c, 0x85, 0xf7, 0xb4, 0xcc, 0xc9, 0xb0,
+	0xf0, 0x1e, 0xba, 0x0f, 0xc5, 0x30, 0xf0, 0xb9, 0x55, 0x59, 0x8e, 0xf4, 0x97, 0x18, 0x52, 0x43,
+	0x0a, 0x09, 0xa0, 0xaf, 0x18, 0x50, 0x21, 0x54, 0x64, 0x36, 0x45, 0x70, 0xf0, 0x9e, 0x96, 0x3b,
+	0x09, 0x0e, 0xde, 0x13, 0x4e, 0xc3, 0x7e, 0x7b, 0xab, 0x69, 0x91, 0x2e, 0xdd, 0xd2, 0xf2, 0xb3,
+	0xca, 0x5c, 0xc6, 0x2c, 0x88, 0xb9, 0x06, 0x9b, 0x42, 0xf7, 0xa0, 0x40, 0xb1, 0xdd, 0xdc, 0x74,
+	0x76, 0xd8, 0x49, 0x6b, 0x2a, 0x77, 0x8a, 0x36, 0xec, 0x14, 0x6c, 0xdf, 0xe7, 0x02, 0x32, 0xe0,
+	0x81, 0x86, 0x13, 0x41, 0xe5, 0x16, 0xa8, 0x7d, 0xf7, 0xa0, 0x71, 0x48, 0x6f, 0x93, 0x7d, 0x19,
+	0x1c, 0xec, 0x17, 0x95, 0x21, 0xb3, 0x8b, 0x77, 0x7a, 0x61, 0x2c, 0x88, 0xc1, 0xdd, 0xd4, 0x6d,
+	0x45, 0x5f, 0xe7, 0x8a, 0x02, 0x26, 0x41, 0xb1, 0x02, 0x79, 0x11, 0x37, 0x9e, 0x2f, 0x75, 0xfb,
+	0xe3, 0x23, 0xd0, 0xf4, 0x00, 0xa8, 0x7e, 0x00, 0xe7, 0xef, 0x3b, 0xae, 0xc5, 0x23, 0x28, 0x08,
+	0xb3, 0xe8, 0x0e, 0x64, 0x78, 0x65, 0xe4, 0xd0, 0x85, 0xa5, 0x2b, 0x23, 0x84, 0x9b, 0x29, 0x34,
+	0xd0, 0x02, 0xa0, 0x37, 0xa6, 0xf2, 0xf9, 0x76, 0x2c, 0x8d, 0xd7, 0x60, 0x9c, 0xd1, 0xcb, 0x4a,
+	0xf2, 0x67, 0xd9, 0xf5, 0x32, 0xa0, 0x55, 0x42, 0x9f, 0x8a, 0xfc, 0x0a, 0x01, 0xf5, 0x9b, 0x30,
+	0x11, 0x99, 0x15, 0x69, 0xcb, 0x0e, 0x4b, 0x66, 0xa2, 0x28, 0x57, 0xaa, 0xd9, 0x1f, 0xeb, 0x8b,
+	0x50, 0x5e, 0x25, 0x74, 0x3d, 0xcc, 0xc1, 0xbe, 0x6d, 0x1a, 0xe4, 0xa4, 0x8c, 0x3c, 0xf6, 0x70,
+	0xa8, 0xdf, 0x82, 0xc9, 0x21, 0x0d, 0x49, 0x33, 0x0d, 0xd0, 0xcf, 0xe5, 0x90, 0x68, 0x60, 0x46,
+	0xff, 0x5a, 0x81, 0x0b, 0xab, 0x84, 0x36, 0x48, 0x97, 0xb8, 0x16, 0x71, 0xdb, 0xce, 0x91, 0x1f,
+	0x96, 0x01, 0x8e, 0xd2, 0x4c, 0x53, 0xde, 0x21, 0xc5, 0xd4, 0x7e, 0x8a, 0xa1, 0x7b, 0x90, 0x27,
+	0xae, 0x25, 0x20, 0x52, 0xef, 0x00, 0x91, 0x23, 0xae, 0xc5, 0xe6, 0xf5, 0x16, 0x4c, 0xc5, 0xec,
+	0x93, 0x7b, 0x5b, 0x85, 0xa2, 0x35, 0x30, 0x2f, 0xab, 0xfe, 0x5f, 0x87, 0x3c, 0xd6, 0x57, 0xdd,
+	0xff, 0x9f, 0xe3, 0x6e, 0xcb, 0x74, 0x88, 0x28, 0xea, 0x3f, 0x2a, 0x50, 0x5e, 0xf6, 0x3a, 0x5d,
+	0xec, 0x93, 0x68, 0x28, 0x36, 0x01, 0xc2, 0x82, 0xde, 0xc4, 0xb2, 0xa4, 0xd7, 0x4e, 0x5a, 0xd2,
+	0xf3, 0xf2, 0xb7, 0x66, 0xe6, 0x65, 0x4d, 0xaf, 0x45, 0x08, 0x5a, 0x5a, 0xea, 0x74, 0x08, 0xea,
+	0x7d, 0x82, 0xba, 0xfe, 0x31, 0xe4, 0x36, 0xb0, 0xdd, 0x70, 0x36, 0x37, 0x13, 0x12, 0x76, 0x11,
+	0x72, 0x3c, 0x0f, 0x9b, 0x58, 0xfa, 0x66, 0x6a, 0xe8, 0xec, 0xfe, 0x4b, 0xf6, 0x9f, 0x31, 0x01,
+	0x33, 0xcb, 0xe5, 0x6a, 0x47, 0x1a, 0x2d, 0x2d, 0x3d, 0x8a, 0x46, 0x5d, 0xff, 0x26, 0x03, 0x25,
+	0x6e, 0x17, 0xb3, 0xe1, 0x91, 0x67, 0x11, 0x84, 0x60, 0xac, 0x8b, 0xe9, 0x96, 0x34, 0x84, 0xff,
+	0xc7, 0xee, 0xaa, 0xd4, 0x28, 0x77, 0x55, 0x3a, 0xe9, 0xae, 0xfa, 0x37, 0x64, 0x03, 0x8a, 0x69,
+	0x2f, 0xe0, 0x17, 0xcc, 0xd9, 0xe4, 0x04, 0x0e, 0x6d, 0xa9, 0x3e, 0xe5, 0xa2, 0xa6, 0x54, 0x41,
+	0xcf, 0x41, 0x65, 0x8d, 0x81, 0x70, 0x77, 0x86, 0x7b, 0xe3, 0x9e, 0xf4, 0xc6, 0x8d, 0xd1, 0xbc,
+	0xc1, 0x6a, 0x89, 0xb8, 0xc0, 0xc5, 0x5f, 0xcd, 0xcc, 0x31, 0x44, 0xe6, 0xeb, 0x01, 0xf0, 0x96,
+	0x96, 0x3d, 0x0d, 0xf0, 0x7a, 0x08, 0x5e, 0x47, 0x75, 0x80, 0xfe, 0xfd, 0x83, 0xdf, 0xe5, 0xf6,
+	0x51, 0x43, 0xb5, 0x5a, 0x04, 0xa3, 0xa5, 0xe5, 0x4f, 0x80, 0x51, 0x67, 0x77, 0x73, 0x1f, 0xc3,
+	0x22, 0x3b, 0x14, 0x6b, 0xea, 0xe8, 0x38, 0xa5, 0x50, 0xb5, 0xc1, 0x34, 0xd1, 0x1d, 0x50, 0xd9,
+	0x45, 0x67, 0x39, 0x9b, 0x9b, 0x81, 0x06, 0x3c, 0xb9, 0x2f, 0xc4, 0xaf, 0x39, 0xe6, 0x4b, 0x99,
+	0xd5, 0x79, 0x2a, 0x86, 0x81, 0x7e, 0x03, 0xb2, 0xc2, 0xb5, 0x08, 0x20, 0xbb, 0xbc, 0xbe, 0xb6,
+	0xb6, 0xfe, 0x68, 0xfc, 0x0c, 0x52, 0x21, 0x53, 0x6b, 0x34, 0x56, 0x1a, 0xe3, 0x0a, 0x2a, 0x40,
+	0xce, 0x5c, 0x59, 0x5b, 0x7f, 0xb6, 0xd2, 0x18, 0x4f, 0xe9, 0x4f, 0x60, 0x72, 0x28, 0xfd, 0x65,
+	0x85, 0xb9, 0x0d, 0x19, 0xd7, 0xb3, 0xfa, 0xa5, 0xe5, 0xd2, 0x71, 0xb1, 0x14, 0x76, 0x96, 0x5c,
+	0x41, 0x7f, 0x01, 0xa8, 0x41, 0x76, 0x08, 0x7d, 0x8f, 0x0d, 0xe2, 0x24, 0x4c, 0x44, 0x28, 0x65,
+	0x7f, 0xf8, 0x9d, 0x02, 0x15, 0x31, 0x2f, 0xef, 0xa0, 0x68, 0x89, 0x7b, 0xe3, 0x9d, 0x92, 0xd0,
+	0xb2, 0xa5, 0x4e, 0xb1, 0x65, 0x4b, 0x9f, 0xb4, 0x65, 0xd3, 0x0f, 0xe0, 0x62, 0xe2, 0x7e, 0xa4,
+	0xcf, 0x3e, 0x00, 0x35, 0x3c, 0x63, 0xe1, 0xb7, 0x53, 0xa8, 0xa8, 0x41, 0xbf, 0xa2, 0x06, 0x4b,
+	0x9f, 0xab, 0x50, 0xe4, 0x0d, 0x80, 0xa4, 0x47, 0xdb, 0x90, 0x0f, 0xdf, 0x3e, 0x68, 0x7a, 0x28,
+	0x42, 0x86, 0x1e, 0x45, 0x95, 0xcb, 0x09, 0x4f, 0x92, 0xe8, 0x23, 0x46, 0xaf, 0x7c, 0xf2, 0xc3,
+	0x6f, 0x5f, 0xa6, 0xca, 0x08, 0x19, 0x9c, 0x32, 0x30, 0x3e, 0x0a, 0xb7, 0x73, 0xb0, 0xa8, 0x20,
+	0x0a, 0xc5, 0xc1, 0x57, 0x00, 0xd2, 0x87, 0x00, 0x13, 0x9e, 0x25, 0x95, 0x2b, 0xc7, 0xca, 0xc8,
+	0x30, 0xb9, 0xc8, 0x69, 0x27, 0xf5, 0x09, 0x03, 0x8b, 0xe5, 0x01, 0x5e, 0x64, 0x03, 0x1c, 0xf5,
+	0x69, 0x68, 0x76, 0x08, 0x2f, 0xd6, 0xc2, 0x8d, 0xb2, 0x4d, 0xc4, 0xf9, 0x8a, 0x7a, 0xce, 0x10,
+	0xbd, 0xed, 0x5d, 0x65, 0x7e, 0x51, 0x41, 0x16, 0xa8, 0xfd, 0x8e, 0x0c, 0xcd, 0x24, 0xf0, 0x0c,
+	0xf6, 0x6a, 0xa3, 0xd0, 0x9c, 0xe7, 0x34, 0x05, 0x3d, 0x6b, 0xf0, 0x17, 0x9f, 0x60, 0xb1, 0xa1,
+	0x30, 0xd0, 0x92, 0xa1, 0xcb, 0x71, 0xa7, 0x0d, 0x35, 0x71, 0x15, 0xfd, 0x38, 0x11, 0x79, 0x82,
+	0x92, 0x0a, 0xa9, 0x46, 0xd8, 0xc8, 0x21, 0x0f, 0x4a, 0x91, 0xb6, 0x0c, 0x5d, 0x89, 0xe3, 0xc4,
+	0xda, 0xbc, 0xca, 0xd5, 0xe3, 0x85, 0x24, 0xdd, 0x04, 0xa7, 0x2b, 0xa1, 0x82, 0x71, 0xd4, 0xce,
+	0xa1, 0x97, 0xfc, 0x1d, 0x3e, 0xd8, 0x2d, 0xa1, 0x6b, 0x71, 0xb4, 0x84, 0x6e, 0xaf, 0x72, 0xfd,
+	0x6d, 0x62, 0x92, 0x76, 0x92, 0xd3, 0x9e, 0x43, 0x25, 0x63, 0xb0, 0x85, 0x42, 0x9f, 0x2a, 0x50,
+	0x8a, 0xd4, 0xd0, 0xd8, 0x56, 0x93, 0x1a, 0xac, 0xca, 0xd5, 0xe3, 0x85, 0x24, 0x67, 0x95, 0x73,
+	0xce, 0xa1, 0xeb, 0xb1, 0x94, 0x68, 0xe2, 0x03, 0x83, 0xdd, 0x0f, 0x03, 0x33, 0xad, 0x03, 0xd4,
+	0x85, 0xc2, 0x40, 0x25, 0x8c, 0xf9, 0x37, 0x5e, 0x98, 0x2b, 0xfa, 0x71, 0x22, 0xd2, 0x0a, 0x99,
+	0x98, 0xf3, 0x09, 0x89, 0x89, 0x0e, 0xc2, 0xda, 0x1b, 0xa9, 0x49, 0xe8, 0xef, 0x89, 0xb0, 0x49,
+	0x75, 0xb8, 0x32, 0x3f, 0x8a, 0xa8, 0xb4, 0xe4, 0x1c, 0xb7, 0x44, 0x9d, 0xcf, 0x49, 0x4b, 0xea,
+	0xbb, 0x5f, 0xd4, 0xea, 0x28, 0xb3, 0x94, 0xbe, 0x59, 0x5d, 0xf4, 0xff, 0x09, 0xf0, 0x90, 0x23,
+	0xcd, 0xd6, 0x1e, 0x3f, 0x40, 0x7f, 0xdb, 0xa2, 0xb4, 0x1b, 0xdc, 0x35, 0x8c, 0xb7, 0x94, 0xbe,
+	0xf9, 0x94, 0x92, 0x7a, 0x75, 0x38, 0xad, 0x7c, 0x7f, 0x38, 0xad, 0xfc, 0x72, 0x38, 0xad, 0x7c,
+	0xfb, 0xeb, 0xb4, 0x02, 0x53, 0x8e, 0x57, 0x8d, 0x08, 0x4b, 0xe3, 0xfe, 0x9f, 0x15, 0xdf, 0x56,
+	0x96, 0x97, 0xed, 0x7f, 0xfc, 0x31, 0x00, 0x2a, 0x2c, 0xf4, 0xcd, 0xd2, 0x12, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// QueryServiceClient is the client API for QueryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type QueryServiceClient interface {
+	GetTrace(ctx context.Context, in *GetTraceRequest, opts ...grpc.CallOption) (QueryService_GetTraceClient, error)
+	ArchiveTrace(ctx context.Context, in *ArchiveTraceRequest, opts ...grpc.CallOption) (*ArchiveTraceResponse, error)
+	FindTraces(ctx context.Context, in *FindTracesRequest, opts ...grpc.CallOption) (QueryService_FindTracesClient, error)
+	FindSpans(ctx context.Context, in *FindSpansRequest, opts ...grpc.CallOption) (QueryService_FindSpansClient, error)
+	GetServices(ctx context.Context, in *GetServicesRequest, opts ...grpc.CallOption) (*GetServicesResponse, error)
+	GetOperations(ctx context.Context, in *GetOperationsRequest, opts ...grpc.CallOption) (*GetOperationsResponse, error)
+	GetDependencies(ctx context.Context, in *GetDependenciesRequest, opts ...grpc.CallOption) (*GetDependenciesResponse, error)
+	CompareTraces(ctx context.Context, in *CompareTracesRequest, opts ...grpc.CallOption) (*CompareTracesResponse, error)
+	// Deleting traces requires an admin principal.
+	DeleteTrace(ctx context.Context, in *DeleteTraceRequest, opts ...grpc.CallOption) (*DeleteTraceResponse, error)
+	DeleteServiceTraces(ctx context.Context, in *DeleteServiceTracesRequest, opts ...grpc.CallOption) (*DeleteServiceTracesResponse, error)
+}
+
+type queryServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewQueryServiceClient(cc *grpc.ClientConn) QueryServiceClient {
+	return &queryServiceClient{cc}
+}
+
+func (c *queryServiceClient) GetTrace(ctx context.Context, in *GetTraceRequest, opts ...grpc.CallOption) (QueryService_GetTraceClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_QueryService_serviceDesc.Streams[0], "/jaeger.api_v2.QueryService/GetTrace", opts...)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	x := &queryServiceGetTraceClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
 
-func (m *SpansResponseChunk) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Spans) > 0 {
-		for _, msg := range m.Spans {
-			dAtA[i] = 0xa
-			i++
-			i = encodeVarintQuery(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
-			}
-			i += n
-		}
-	}
-	if m.XXX_unrecognized != nil {
-		i += copy(dAtA[i:], m.XXX_unrecognized)
+type QueryService_GetTraceClient interface {
+	Recv() (*SpansResponseChunk, error)
+	grpc.ClientStream
+}
+
+type queryServiceGetTraceClient struct {
+	grpc.ClientStream
+}
+
+func (x *queryServiceGetTraceClient) Recv() (*SpansResponseChunk, error) {
+	m := new(SpansResponseChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
 	}
-	return i, nil
+	return m, nil
 }
 
-func (m *ArchiveTraceRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+func (c *queryServiceClient) ArchiveTrace(ctx context.Context, in *ArchiveTraceRequest, opts ...grpc.CallOption) (*ArchiveTraceResponse, error) {
+	out := new(ArchiveTraceResponse)
+	err := c.cc.Invoke(ctx, "/jaeger.api_v2.QueryService/ArchiveTrace", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return out, nil
 }
 
-func (m *ArchiveTraceRequest) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	dAtA[i] = 0xa
-	i++
-	i = encodeVarintQuery(dAtA, i, uint64(m.TraceID.Size()))
-	n2, err := m.TraceID.MarshalTo(dAtA[i:])
+func (c *queryServiceClient) FindTraces(ctx context.Context, in *FindTracesRequest, opts ...grpc.CallOption) (QueryService_FindTracesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_QueryService_serviceDesc.Streams[1], "/jaeger.api_v2.QueryService/FindTraces", opts...)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	i += n2
-	if m.XXX_unrecognized != nil {
-		i += copy(dAtA[i:], m.XXX_unrecognized)
+	x := &queryServiceFindTracesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
 	}
-	return i, nil
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
 
-func (m *ArchiveTraceResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
+type QueryService_FindTracesClient interface {
+	Recv() (*SpansResponseChunk, error)
+	grpc.ClientStream
+}
+
+type queryServiceFindTracesClient struct {
+	grpc.ClientStream
+}
+
+func (x *queryServiceFindTracesClient) Recv() (*SpansResponseChunk, error) {
+	m := new(SpansResponseChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return m, nil
 }
 
-func (m *ArchiveTraceResponse) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.XXX_unrecognized != nil {
-		i += copy(dAtA[i:], m.XXX_unrecognized)
+func (c *queryServiceClient) FindSpans(ctx context.Context, in *FindSpansRequest, opts ...grpc.CallOption) (QueryService_FindSpansClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_QueryService_serviceDesc.Streams[2], "/jaeger.api_v2.QueryService/FindSpans", opts...)
+	if err != nil {
+		return nil, err
 	}
-	return i, nil
+	x := &queryServiceFindSpansClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
 
-func (m *TraceQueryParameters) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
+type QueryService_FindSpansClient interface {
+	Recv() (*SpansResponseChunk, error)
+	grpc.ClientStream
+}
+
+type queryServiceFindSpansClient struct {
+	grpc.ClientStream
+}
+
+func (x *queryServiceFindSpansClient) Recv() (*SpansResponseChunk, error) {
+	m := new(SpansResponseChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return m, nil
 }
 
-func (m *TraceQueryParameters) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.ServiceName) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintQuery(dAtA, i, uint64(len(m.ServiceName)))
-		i += copy(dAtA[i:], m.ServiceName)
-	}
-	if len(m.OperationName) > 0 {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintQuery(dAtA, i, uint64(len(m.OperationName)))
-		i += copy(dAtA[i:], m.OperationName)
-	}
-	if len(m.Tags) > 0 {
-		for k, _ := range m.Tags {
-			dAtA[i] = 0x1a
-			i++
-			v := m.Tags[k]
-			mapSize := 1 + len(k) + sovQuery(uint64(len(k))) + 1 + len(v) + sovQuery(uint64(len(v)))
-			i = encodeVarintQuery(dAtA, i, uint64(mapSize))
-			dAtA[i] = 0xa
-			i++
-			i = encodeVarintQuery(dAtA, i, uint64(len(k)))
-			i += copy(dAtA[i:], k)
-			dAtA[i] = 0x12
-			i++
-			i = encodeVarintQuery(dAtA, i, uint64(len(v)))
-			i += copy(dAtA[i:], v)
-		}
-	}
-	dAtA[i] = 0x22
-	i++
-	i = encodeVarintQuery(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTimeMin)))
-	n3, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartTimeMin, dAtA[i:])
-	if err != nil {
-		return 0, err
-	}
-	i += n3
-	dAtA[i] = 0x2a
-	i++
-	i = encodeVarintQuery(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTimeMax)))
-	n4, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartTimeMax, dAtA[i:])
-	if err != nil {
-		return 0, err
-	}
-	i += n4
-	dAtA[i] = 0x32
-	i++
-	i = encodeVarintQuery(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdDuration(m.DurationMin)))
-	n5, err := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.DurationMin, dAtA[i:])
-	if err != nil {
-		return 0, err
-	}
-	i += n5
-	dAtA[i] = 0x3a
-	i++
-	i = encodeVarintQuery(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdDuration(m.DurationMax)))
-	n6, err := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.DurationMax, dAtA[i:])
+func (c *queryServiceClient) GetServices(ctx context.Context, in *GetServicesRequest, opts ...grpc.CallOption) (*GetServicesResponse, error) {
+	out := new(GetServicesResponse)
+	err := c.cc.Invoke(ctx, "/jaeger.api_v2.QueryService/GetServices", in, out, opts...)
 	if err != nil {
-		return 0, err
-	}
-	i += n6
-	if m.SearchDepth != 0 {
-		dAtA[i] = 0x40
-		i++
-		i = encodeVarintQuery(dAtA, i, uint64(m.SearchDepth))
-	}
-	if m.XXX_unrecognized != nil {
-		i += copy(dAtA[i:], m.XXX_unrecognized)
+		return nil, err
 	}
-	return i, nil
+	return out, nil
 }
 
-func (m *FindTracesRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+func (c *queryServiceClient) GetOperations(ctx context.Context, in *GetOperationsRequest, opts ...grpc.CallOption) (*GetOperationsResponse, error) {
+	out := new(GetOperationsResponse)
+	err := c.cc.Invoke(ctx, "/jaeger.api_v2.QueryService/GetOperations", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return out, nil
 }
 
-func (m *FindTracesRequest) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Query != nil {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintQuery(dAtA, i, uint64(m.Query.Size()))
-		n7, err := m.Query.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n7
-	}
-	if m.XXX_unrecognized != nil {
-		i += copy(dAtA[i:], m.XXX_unrecognized)
+func (c *queryServiceClient) GetDependencies(ctx context.Context, in *GetDependenciesRequest, opts ...grpc.CallOption) (*GetDependenciesResponse, error) {
+	out := new(GetDependenciesResponse)
+	err := c.cc.Invoke(ctx, "/jaeger.api_v2.QueryService/GetDependencies", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	return i, nil
+	return out, nil
 }
 
-func (m *GetServicesRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+func (c *queryServiceClient) CompareTraces(ctx context.Context, in *CompareTracesRequest, opts ...grpc.CallOption) (*CompareTracesResponse, error) {
+	out := new(CompareTracesResponse)
+	err := c.cc.Invoke(ctx, "/jaeger.api_v2.QueryService/CompareTraces", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return out, nil
 }
 
-func (m *GetServicesRequest) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.XXX_unrecognized != nil {
-		i += copy(dAtA[i:], m.XXX_unrecognized)
+func (c *queryServiceClient) DeleteTrace(ctx context.Context, in *DeleteTraceRequest, opts ...grpc.CallOption) (*DeleteTraceResponse, error) {
+	out := new(DeleteTraceResponse)
+	err := c.cc.Invoke(ctx, "/jaeger.api_v2.QueryService/DeleteTrace", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	return i, nil
+	return out, nil
 }
 
-func (m *GetServicesResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
+func (c *queryServiceClient) DeleteServiceTraces(ctx context.Context, in *DeleteServiceTracesRequest, opts ...grpc.CallOption) (*DeleteServiceTracesResponse, error) {
+	out := new(DeleteServiceTracesResponse)
+	err := c.cc.Invoke(ctx, "/jaeger.api_v2.QueryService/DeleteServiceTraces", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return out, nil
 }
 
-func (m *GetServicesResponse) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Services) > 0 {
-		for _, s := range m.Services {
-			dAtA[i] = 0xa
-			i++
-			l = len(s)
-			for l >= 1<<7 {
-				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
-				l >>= 7
-				i++
-			}
-			dAtA[i] = uint8(l)
-			i++
-			i += copy(dAtA[i:], s)
-		}
-	}
-	if m.XXX_unrecognized != nil {
-		i += copy(dAtA[i:], m.XXX_unrecognized)
-	}
-	return i, nil
+// QueryServiceServer is the server API for QueryService service.
+type QueryServiceServer interface {
+	GetTrace(*GetTraceRequest, QueryService_GetTraceServer) error
+	ArchiveTrace(context.Context, *ArchiveTraceRequest) (*ArchiveTraceResponse, error)
+	FindTraces(*FindTracesRequest, QueryService_FindTracesServer) error
+	FindSpans(*FindSpansRequest, QueryService_FindSpansServer) error
+	GetServices(context.Context, *GetServicesRequest) (*GetServicesResponse, error)
+	GetOperations(context.Context, *GetOperationsRequest) (*GetOperationsResponse, error)
+	GetDependencies(context.Context, *GetDependenciesRequest) (*GetDependenciesResponse, error)
+	CompareTraces(context.Context, *CompareTracesRequest) (*CompareTracesResponse, error)
+	// Deleting traces requires an admin principal.
+	DeleteTrace(context.Context, *DeleteTraceRequest) (*DeleteTraceResponse, error)
+	DeleteServiceTraces(context.Context, *DeleteServiceTracesRequest) (*DeleteServiceTracesResponse, error)
 }
 
-func (m *GetOperationsRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+func RegisterQueryServiceServer(s *grpc.Server, srv QueryServiceServer) {
+	s.RegisterService(&_QueryService_serviceDesc, srv)
 }
 
-func (m *GetOperationsRequest) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Service) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintQuery(dAtA, i, uint64(len(m.Service)))
-		i += copy(dAtA[i:], m.Service)
-	}
-	if m.XXX_unrecognized != nil {
-		i += copy(dAtA[i:], m.XXX_unrecognized)
+func _QueryService_GetTrace_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetTraceRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	return i, nil
+	return srv.(QueryServiceServer).GetTrace(m, &queryServiceGetTraceServer{stream})
 }
 
-func (m *GetOperationsResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+type QueryService_GetTraceServer interface {
+	Send(*SpansResponseChunk) error
+	grpc.ServerStream
 }
 
-func (m *GetOperationsResponse) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Operations) > 0 {
-		for _, s := range m.Operations {
-			dAtA[i] = 0xa
-			i++
-			l = len(s)
-			for l >= 1<<7 {
-				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
-				l >>= 7
-				i++
-			}
-			dAtA[i] = uint8(l)
-			i++
-			i += copy(dAtA[i:], s)
-		}
-	}
-	if m.XXX_unrecognized != nil {
-		i += copy(dAtA[i:], m.XXX_unrecognized)
-	}
-	return i, nil
+type queryServiceGetTraceServer struct {
+	grpc.ServerStream
 }
 
-func (m *GetDependenciesRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
+func (x *queryServiceGetTraceServer) Send(m *SpansResponseChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _QueryService_ArchiveTrace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveTraceRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	if interceptor == nil {
+		return srv.(QueryServiceServer).ArchiveTrace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jaeger.api_v2.QueryService/ArchiveTrace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).ArchiveTrace(ctx, req.(*ArchiveTraceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *GetDependenciesRequest) MarshalTo(dAtA []byte) (int, error) {
+func _QueryService_FindTraces_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FindTracesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(QueryServiceServer).FindTraces(m, &queryServiceFindTracesServer{stream})
+}
+
+type QueryService_FindTracesServer interface {
+	Send(*SpansResponseChunk) error
+	grpc.ServerStream
+}
+
+type queryServiceFindTracesServer struct {
+	grpc.ServerStream
+}
+
+func (x *queryServiceFindTracesServer) Send(m *SpansResponseChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _QueryService_FindSpans_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FindSpansRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(QueryServiceServer).FindSpans(m, &queryServiceFindSpansServer{stream})
+}
+
+type QueryService_FindSpansServer interface {
+	Send(*SpansResponseChunk) error
+	grpc.ServerStream
+}
+
+type queryServiceFindSpansServer struct {
+	grpc.ServerStream
+}
+
+func (x *queryServiceFindSpansServer) Send(m *SpansResponseChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _QueryService_GetServices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetServicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).GetServices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jaeger.api_v2.QueryService/GetServices",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).GetServices(ctx, req.(*GetServicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_GetOperations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOperationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).GetOperations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jaeger.api_v2.QueryService/GetOperations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).GetOperations(ctx, req.(*GetOperationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_GetDependencies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDependenciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).GetDependencies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jaeger.api_v2.QueryService/GetDependencies",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).GetDependencies(ctx, req.(*GetDependenciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_CompareTraces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompareTracesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).CompareTraces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jaeger.api_v2.QueryService/CompareTraces",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).CompareTraces(ctx, req.(*CompareTracesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_DeleteTrace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTraceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).DeleteTrace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jaeger.api_v2.QueryService/DeleteTrace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).DeleteTrace(ctx, req.(*DeleteTraceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_DeleteServiceTraces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteServiceTracesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).DeleteServiceTraces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jaeger.api_v2.QueryService/DeleteServiceTraces",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).DeleteServiceTraces(ctx, req.(*DeleteServiceTracesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _QueryService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "jaeger.api_v2.QueryService",
+	HandlerType: (*QueryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ArchiveTrace",
+			Handler:    _QueryService_ArchiveTrace_Handler,
+		},
+		{
+			MethodName: "GetServices",
+			Handler:    _QueryService_GetServices_Handler,
+		},
+		{
+			MethodName: "GetOperations",
+			Handler:    _QueryService_GetOperations_Handler,
+		},
+		{
+			MethodName: "GetDependencies",
+			Handler:    _QueryService_GetDependencies_Handler,
+		},
+		{
+			MethodName: "CompareTraces",
+			Handler:    _QueryService_CompareTraces_Handler,
+		},
+		{
+			MethodName: "DeleteTrace",
+			Handler:    _QueryService_DeleteTrace_Handler,
+		},
+		{
+			MethodName: "DeleteServiceTraces",
+			Handler:    _QueryService_DeleteServiceTraces_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetTrace",
+			Handler:       _QueryService_GetTrace_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "FindTraces",
+			Handler:       _QueryService_FindTraces_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "FindSpans",
+			Handler:       _QueryService_FindSpans_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api_v2/query.proto",
+}
+
+func (m *GetTraceRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetTraceRequest) MarshalTo(dAtA []byte) (int, error) {
 	var i int
 	_ = i
 	var l int
 	_ = l
 	dAtA[i] = 0xa
 	i++
-	i = encodeVarintQuery(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTime)))
-	n8, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartTime, dAtA[i:])
-	if err != nil {
-		return 0, err
-	}
-	i += n8
-	dAtA[i] = 0x12
-	i++
-	i = encodeVarintQuery(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdTime(m.EndTime)))
-	n9, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.EndTime, dAtA[i:])
+	i = encodeVarintQuery(dAtA, i, uint64(m.TraceID.Size()))
+	n1, err := m.TraceID.MarshalTo(dAtA[i:])
 	if err != nil {
 		return 0, err
 	}
-	i += n9
+	i += n1
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
 	return i, nil
 }
 
-func (m *GetDependenciesResponse) Marshal() (dAtA []byte, err error) {
+func (m *SpansResponseChunk) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
@@ -1418,13 +1886,13 @@ func (m *GetDependenciesResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *GetDependenciesResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *SpansResponseChunk) MarshalTo(dAtA []byte) (int, error) {
 	var i int
 	_ = i
 	var l int
 	_ = l
-	if len(m.Dependencies) > 0 {
-		for _, msg := range m.Dependencies {
+	if len(m.Spans) > 0 {
+		for _, msg := range m.Spans {
 			dAtA[i] = 0xa
 			i++
 			i = encodeVarintQuery(dAtA, i, uint64(msg.Size()))
@@ -1435,246 +1903,2708 @@ func (m *GetDependenciesResponse) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
+	if len(m.NextContinuationToken) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.NextContinuationToken)))
+		i += copy(dAtA[i:], m.NextContinuationToken)
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
 	return i, nil
 }
 
-func encodeVarintQuery(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *ArchiveTraceRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
+	return dAtA[:n], nil
 }
-func (m *GetTraceRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+
+func (m *ArchiveTraceRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = m.TraceID.Size()
-	n += 1 + l + sovQuery(uint64(l))
+	dAtA[i] = 0xa
+	i++
+	i = encodeVarintQuery(dAtA, i, uint64(m.TraceID.Size()))
+	n2, err := m.TraceID.MarshalTo(dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n2
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	return n
+	return i, nil
 }
 
-func (m *SpansResponseChunk) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if len(m.Spans) > 0 {
-		for _, e := range m.Spans {
-			l = e.Size()
-			n += 1 + l + sovQuery(uint64(l))
-		}
+func (m *ArchiveTraceResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *ArchiveTraceResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	return n
+	return i, nil
 }
 
-func (m *ArchiveTraceRequest) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *TraceQueryParameters) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *TraceQueryParameters) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = m.TraceID.Size()
-	n += 1 + l + sovQuery(uint64(l))
+	if len(m.ServiceName) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.ServiceName)))
+		i += copy(dAtA[i:], m.ServiceName)
+	}
+	if len(m.OperationName) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.OperationName)))
+		i += copy(dAtA[i:], m.OperationName)
+	}
+	if len(m.Tags) > 0 {
+		for k, _ := range m.Tags {
+			dAtA[i] = 0x1a
+			i++
+			v := m.Tags[k]
+			mapSize := 1 + len(k) + sovQuery(uint64(len(k))) + 1 + len(v) + sovQuery(uint64(len(v)))
+			i = encodeVarintQuery(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintQuery(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintQuery(dAtA, i, uint64(len(v)))
+			i += copy(dAtA[i:], v)
+		}
+	}
+	dAtA[i] = 0x22
+	i++
+	i = encodeVarintQuery(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTimeMin)))
+	n3, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartTimeMin, dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n3
+	dAtA[i] = 0x2a
+	i++
+	i = encodeVarintQuery(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTimeMax)))
+	n4, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartTimeMax, dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n4
+	dAtA[i] = 0x32
+	i++
+	i = encodeVarintQuery(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdDuration(m.DurationMin)))
+	n5, err := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.DurationMin, dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n5
+	dAtA[i] = 0x3a
+	i++
+	i = encodeVarintQuery(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdDuration(m.DurationMax)))
+	n6, err := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.DurationMax, dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n6
+	if m.SearchDepth != 0 {
+		dAtA[i] = 0x40
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(m.SearchDepth))
+	}
+	if len(m.TagFilters) > 0 {
+		for _, msg := range m.TagFilters {
+			dAtA[i] = 0x4a
+			i++
+			i = encodeVarintQuery(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	return n
+	return i, nil
 }
 
-func (m *ArchiveTraceResponse) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *TagFilter) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *TagFilter) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
+	if len(m.Key) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Key)))
+		i += copy(dAtA[i:], m.Key)
+	}
+	if len(m.Operator) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Operator)))
+		i += copy(dAtA[i:], m.Operator)
+	}
+	if len(m.Value) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Value)))
+		i += copy(dAtA[i:], m.Value)
+	}
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	return n
+	return i, nil
 }
 
-func (m *TraceQueryParameters) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *FindTracesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *FindTracesRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.ServiceName)
-	if l > 0 {
-		n += 1 + l + sovQuery(uint64(l))
-	}
-	l = len(m.OperationName)
-	if l > 0 {
-		n += 1 + l + sovQuery(uint64(l))
-	}
-	if len(m.Tags) > 0 {
-		for k, v := range m.Tags {
-			_ = k
-			_ = v
-			mapEntrySize := 1 + len(k) + sovQuery(uint64(len(k))) + 1 + len(v) + sovQuery(uint64(len(v)))
-			n += mapEntrySize + 1 + sovQuery(uint64(mapEntrySize))
+	if m.Query != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(m.Query.Size()))
+		n7, err := m.Query.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
 		}
+		i += n7
 	}
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTimeMin)
-	n += 1 + l + sovQuery(uint64(l))
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTimeMax)
-	n += 1 + l + sovQuery(uint64(l))
-	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.DurationMin)
-	n += 1 + l + sovQuery(uint64(l))
-	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.DurationMax)
-	n += 1 + l + sovQuery(uint64(l))
-	if m.SearchDepth != 0 {
-		n += 1 + sovQuery(uint64(m.SearchDepth))
+	if len(m.ContinuationToken) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.ContinuationToken)))
+		i += copy(dAtA[i:], m.ContinuationToken)
 	}
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	return n
+	return i, nil
 }
 
-func (m *FindTracesRequest) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *FindSpansRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *FindSpansRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
 	if m.Query != nil {
-		l = m.Query.Size()
-		n += 1 + l + sovQuery(uint64(l))
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(m.Query.Size()))
+		n8, err := m.Query.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n8
 	}
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	return n
+	return i, nil
 }
 
-func (m *GetServicesRequest) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *GetServicesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *GetServicesRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	return n
+	return i, nil
 }
 
-func (m *GetServicesResponse) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *GetServicesResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *GetServicesResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
 	if len(m.Services) > 0 {
 		for _, s := range m.Services {
+			dAtA[i] = 0xa
+			i++
 			l = len(s)
-			n += 1 + l + sovQuery(uint64(l))
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *GetOperationsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetOperationsRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Service) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Service)))
+		i += copy(dAtA[i:], m.Service)
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *GetOperationsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetOperationsResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Operations) > 0 {
+		for _, s := range m.Operations {
+			dAtA[i] = 0xa
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *GetDependenciesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetDependenciesRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	dAtA[i] = 0xa
+	i++
+	i = encodeVarintQuery(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTime)))
+	n9, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartTime, dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n9
+	dAtA[i] = 0x12
+	i++
+	i = encodeVarintQuery(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdTime(m.EndTime)))
+	n10, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.EndTime, dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n10
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *GetDependenciesResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetDependenciesResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Dependencies) > 0 {
+		for _, msg := range m.Dependencies {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintQuery(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *CompareTracesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CompareTracesRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	dAtA[i] = 0xa
+	i++
+	i = encodeVarintQuery(dAtA, i, uint64(m.TraceIDA.Size()))
+	n11, err := m.TraceIDA.MarshalTo(dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n11
+	dAtA[i] = 0x12
+	i++
+	i = encodeVarintQuery(dAtA, i, uint64(m.TraceIDB.Size()))
+	n12, err := m.TraceIDB.MarshalTo(dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n12
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *TagDiff) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TagDiff) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Key) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Key)))
+		i += copy(dAtA[i:], m.Key)
+	}
+	if m.ValueA != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(m.ValueA.Size()))
+		n13, err := m.ValueA.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n13
+	}
+	if m.ValueB != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(m.ValueB.Size()))
+		n14, err := m.ValueB.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n14
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *TraceDiffNode) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TraceDiffNode) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Path) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Path)))
+		i += copy(dAtA[i:], m.Path)
+	}
+	if len(m.ServiceName) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.ServiceName)))
+		i += copy(dAtA[i:], m.ServiceName)
+	}
+	if len(m.OperationName) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.OperationName)))
+		i += copy(dAtA[i:], m.OperationName)
+	}
+	if m.Status != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(m.Status))
+	}
+	dAtA[i] = 0x2a
+	i++
+	i = encodeVarintQuery(dAtA, i, uint64(m.SpanIDA.Size()))
+	n15, err := m.SpanIDA.MarshalTo(dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n15
+	dAtA[i] = 0x32
+	i++
+	i = encodeVarintQuery(dAtA, i, uint64(m.SpanIDB.Size()))
+	n16, err := m.SpanIDB.MarshalTo(dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n16
+	dAtA[i] = 0x3a
+	i++
+	i = encodeVarintQuery(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdDuration(m.DurationA)))
+	n17, err := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.DurationA, dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n17
+	dAtA[i] = 0x42
+	i++
+	i = encodeVarintQuery(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdDuration(m.DurationB)))
+	n18, err := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.DurationB, dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n18
+	dAtA[i] = 0x4a
+	i++
+	i = encodeVarintQuery(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdDuration(m.DurationDelta)))
+	n19, err := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.DurationDelta, dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n19
+	if len(m.TagDiffs) > 0 {
+		for _, msg := range m.TagDiffs {
+			dAtA[i] = 0x52
+			i++
+			i = encodeVarintQuery(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *CompareTracesResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CompareTracesResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Nodes) > 0 {
+		for _, msg := range m.Nodes {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintQuery(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *DeleteTraceRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DeleteTraceRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	dAtA[i] = 0xa
+	i++
+	i = encodeVarintQuery(dAtA, i, uint64(m.TraceID.Size()))
+	n20, err := m.TraceID.MarshalTo(dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n20
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *DeleteTraceResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DeleteTraceResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *DeleteServiceTracesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DeleteServiceTracesRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Service) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Service)))
+		i += copy(dAtA[i:], m.Service)
+	}
+	dAtA[i] = 0x12
+	i++
+	i = encodeVarintQuery(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTimeMin)))
+	n21, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartTimeMin, dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n21
+	dAtA[i] = 0x1a
+	i++
+	i = encodeVarintQuery(dAtA, i, uint64(github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTimeMax)))
+	n22, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartTimeMax, dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n22
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *DeleteServiceTracesResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DeleteServiceTracesResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.TraceIDs) > 0 {
+		for _, msg := range m.TraceIDs {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintQuery(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func encodeVarintQuery(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *GetTraceRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.TraceID.Size()
+	n += 1 + l + sovQuery(uint64(l))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *SpansResponseChunk) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Spans) > 0 {
+		for _, e := range m.Spans {
+			l = e.Size()
+			n += 1 + l + sovQuery(uint64(l))
+		}
+	}
+	l = len(m.NextContinuationToken)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ArchiveTraceRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.TraceID.Size()
+	n += 1 + l + sovQuery(uint64(l))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ArchiveTraceResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *TraceQueryParameters) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ServiceName)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.OperationName)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if len(m.Tags) > 0 {
+		for k, v := range m.Tags {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovQuery(uint64(len(k))) + 1 + len(v) + sovQuery(uint64(len(v)))
+			n += mapEntrySize + 1 + sovQuery(uint64(mapEntrySize))
+		}
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTimeMin)
+	n += 1 + l + sovQuery(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTimeMax)
+	n += 1 + l + sovQuery(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.DurationMin)
+	n += 1 + l + sovQuery(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.DurationMax)
+	n += 1 + l + sovQuery(uint64(l))
+	if m.SearchDepth != 0 {
+		n += 1 + sovQuery(uint64(m.SearchDepth))
+	}
+	if len(m.TagFilters) > 0 {
+		for _, e := range m.TagFilters {
+			l = e.Size()
+			n += 1 + l + sovQuery(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *TagFilter) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.Operator)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *FindTracesRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Query != nil {
+		l = m.Query.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.ContinuationToken)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *FindSpansRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Query != nil {
+		l = m.Query.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *GetServicesRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *GetServicesResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Services) > 0 {
+		for _, s := range m.Services {
+			l = len(s)
+			n += 1 + l + sovQuery(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *GetOperationsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Service)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *GetOperationsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Operations) > 0 {
+		for _, s := range m.Operations {
+			l = len(s)
+			n += 1 + l + sovQuery(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *GetDependenciesRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTime)
+	n += 1 + l + sovQuery(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.EndTime)
+	n += 1 + l + sovQuery(uint64(l))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *GetDependenciesResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Dependencies) > 0 {
+		for _, e := range m.Dependencies {
+			l = e.Size()
+			n += 1 + l + sovQuery(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *CompareTracesRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.TraceIDA.Size()
+	n += 1 + l + sovQuery(uint64(l))
+	l = m.TraceIDB.Size()
+	n += 1 + l + sovQuery(uint64(l))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *TagDiff) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if m.ValueA != nil {
+		l = m.ValueA.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if m.ValueB != nil {
+		l = m.ValueB.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *TraceDiffNode) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.ServiceName)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.OperationName)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if m.Status != 0 {
+		n += 1 + sovQuery(uint64(m.Status))
+	}
+	l = m.SpanIDA.Size()
+	n += 1 + l + sovQuery(uint64(l))
+	l = m.SpanIDB.Size()
+	n += 1 + l + sovQuery(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.DurationA)
+	n += 1 + l + sovQuery(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.DurationB)
+	n += 1 + l + sovQuery(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.DurationDelta)
+	n += 1 + l + sovQuery(uint64(l))
+	if len(m.TagDiffs) > 0 {
+		for _, e := range m.TagDiffs {
+			l = e.Size()
+			n += 1 + l + sovQuery(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *CompareTracesResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Nodes) > 0 {
+		for _, e := range m.Nodes {
+			l = e.Size()
+			n += 1 + l + sovQuery(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *DeleteTraceRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.TraceID.Size()
+	n += 1 + l + sovQuery(uint64(l))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *DeleteTraceResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *DeleteServiceTracesRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Service)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTimeMin)
+	n += 1 + l + sovQuery(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTimeMax)
+	n += 1 + l + sovQuery(uint64(l))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *DeleteServiceTracesResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.TraceIDs) > 0 {
+		for _, e := range m.TraceIDs {
+			l = e.Size()
+			n += 1 + l + sovQuery(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func sovQuery(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozQuery(x uint64) (n int) {
+	return sovQuery(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *GetTraceRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetTraceRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetTraceRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceID", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.TraceID.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SpansResponseChunk) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SpansResponseChunk: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SpansResponseChunk: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Spans", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Spans = append(m.Spans, model.Span{})
+			if err := m.Spans[len(m.Spans)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NextContinuationToken", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NextContinuationToken = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ArchiveTraceRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ArchiveTraceRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ArchiveTraceRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceID", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.TraceID.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ArchiveTraceResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ArchiveTraceResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ArchiveTraceResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TraceQueryParameters) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TraceQueryParameters: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TraceQueryParameters: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ServiceName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OperationName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OperationName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tags", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Tags == nil {
+				m.Tags = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowQuery
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowQuery
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthQuery
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthQuery
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowQuery
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthQuery
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthQuery
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipQuery(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthQuery
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Tags[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTimeMin", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.StartTimeMin, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTimeMax", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.StartTimeMax, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationMin", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.DurationMin, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationMax", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.DurationMax, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SearchDepth", wireType)
+			}
+			m.SearchDepth = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SearchDepth |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TagFilters", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TagFilters = append(m.TagFilters, TagFilter{})
+			if err := m.TagFilters[len(m.TagFilters)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TagFilter) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TagFilter: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TagFilter: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Operator", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Operator = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FindTracesRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FindTracesRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FindTracesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Query == nil {
+				m.Query = &TraceQueryParameters{}
+			}
+			if err := m.Query.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ContinuationToken", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ContinuationToken = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FindSpansRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FindSpansRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FindSpansRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Query == nil {
+				m.Query = &TraceQueryParameters{}
+			}
+			if err := m.Query.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetServicesRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetServicesRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetServicesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetServicesResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetServicesResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetServicesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Services", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Services = append(m.Services, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetOperationsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetOperationsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetOperationsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Service", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Service = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
 		}
 	}
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
-	}
-	return n
-}
 
-func (m *GetOperationsRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Service)
-	if l > 0 {
-		n += 1 + l + sovQuery(uint64(l))
-	}
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
+	return nil
 }
-
-func (m *GetOperationsResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if len(m.Operations) > 0 {
-		for _, s := range m.Operations {
-			l = len(s)
-			n += 1 + l + sovQuery(uint64(l))
+func (m *GetOperationsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
 		}
-	}
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
-	}
-	return n
-}
-
-func (m *GetDependenciesRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTime)
-	n += 1 + l + sovQuery(uint64(l))
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.EndTime)
-	n += 1 + l + sovQuery(uint64(l))
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
-	}
-	return n
-}
-
-func (m *GetDependenciesResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if len(m.Dependencies) > 0 {
-		for _, e := range m.Dependencies {
-			l = e.Size()
-			n += 1 + l + sovQuery(uint64(l))
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetOperationsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetOperationsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Operations", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Operations = append(m.Operations, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
 		}
 	}
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
-	}
-	return n
-}
 
-func sovQuery(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
-}
-func sozQuery(x uint64) (n int) {
-	return sovQuery(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	return nil
 }
-func (m *GetTraceRequest) Unmarshal(dAtA []byte) error {
+func (m *GetDependenciesRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1697,17 +4627,17 @@ func (m *GetTraceRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: GetTraceRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: GetDependenciesRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: GetTraceRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: GetDependenciesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TraceID", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTime", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowQuery
@@ -1717,22 +4647,55 @@ func (m *GetTraceRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthQuery
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthQuery
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.TraceID.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.StartTime, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EndTime", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.EndTime, dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -1761,7 +4724,7 @@ func (m *GetTraceRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SpansResponseChunk) Unmarshal(dAtA []byte) error {
+func (m *GetDependenciesResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1784,15 +4747,15 @@ func (m *SpansResponseChunk) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SpansResponseChunk: wiretype end group for non-group")
+			return fmt.Errorf("proto: GetDependenciesResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SpansResponseChunk: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: GetDependenciesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Spans", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Dependencies", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1819,8 +4782,8 @@ func (m *SpansResponseChunk) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Spans = append(m.Spans, model.Span{})
-			if err := m.Spans[len(m.Spans)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Dependencies = append(m.Dependencies, model.DependencyLink{})
+			if err := m.Dependencies[len(m.Dependencies)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -1849,7 +4812,7 @@ func (m *SpansResponseChunk) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ArchiveTraceRequest) Unmarshal(dAtA []byte) error {
+func (m *CompareTracesRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1872,15 +4835,48 @@ func (m *ArchiveTraceRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ArchiveTraceRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: CompareTracesRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ArchiveTraceRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: CompareTracesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TraceID", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceIDA", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.TraceIDA.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceIDB", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -1907,7 +4903,7 @@ func (m *ArchiveTraceRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.TraceID.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.TraceIDB.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -1936,7 +4932,7 @@ func (m *ArchiveTraceRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ArchiveTraceResponse) Unmarshal(dAtA []byte) error {
+func (m *TagDiff) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1959,12 +4955,116 @@ func (m *ArchiveTraceResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ArchiveTraceResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: TagDiff: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ArchiveTraceResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TagDiff: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ValueA", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ValueA == nil {
+				m.ValueA = &model.KeyValue{}
+			}
+			if err := m.ValueA.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ValueB", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ValueB == nil {
+				m.ValueB = &model.KeyValue{}
+			}
+			if err := m.ValueB.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQuery(dAtA[iNdEx:])
@@ -1990,7 +5090,7 @@ func (m *ArchiveTraceResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TraceQueryParameters) Unmarshal(dAtA []byte) error {
+func (m *TraceDiffNode) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2013,15 +5113,15 @@ func (m *TraceQueryParameters) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TraceQueryParameters: wiretype end group for non-group")
+			return fmt.Errorf("proto: TraceDiffNode: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TraceQueryParameters: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TraceDiffNode: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ServiceName", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2049,11 +5149,11 @@ func (m *TraceQueryParameters) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ServiceName = string(dAtA[iNdEx:postIndex])
+			m.Path = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field OperationName", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceName", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2081,13 +5181,13 @@ func (m *TraceQueryParameters) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.OperationName = string(dAtA[iNdEx:postIndex])
+			m.ServiceName = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Tags", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field OperationName", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowQuery
@@ -2097,124 +5197,81 @@ func (m *TraceQueryParameters) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthQuery
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthQuery
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Tags == nil {
-				m.Tags = make(map[string]string)
+			m.OperationName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
 			}
-			var mapkey string
-			var mapvalue string
-			for iNdEx < postIndex {
-				entryPreIndex := iNdEx
-				var wire uint64
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowQuery
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					wire |= uint64(b&0x7F) << shift
-					if b < 0x80 {
-						break
-					}
+			m.Status = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
 				}
-				fieldNum := int32(wire >> 3)
-				if fieldNum == 1 {
-					var stringLenmapkey uint64
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowQuery
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						stringLenmapkey |= uint64(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					intStringLenmapkey := int(stringLenmapkey)
-					if intStringLenmapkey < 0 {
-						return ErrInvalidLengthQuery
-					}
-					postStringIndexmapkey := iNdEx + intStringLenmapkey
-					if postStringIndexmapkey < 0 {
-						return ErrInvalidLengthQuery
-					}
-					if postStringIndexmapkey > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
-					iNdEx = postStringIndexmapkey
-				} else if fieldNum == 2 {
-					var stringLenmapvalue uint64
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowQuery
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						stringLenmapvalue |= uint64(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					intStringLenmapvalue := int(stringLenmapvalue)
-					if intStringLenmapvalue < 0 {
-						return ErrInvalidLengthQuery
-					}
-					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
-					if postStringIndexmapvalue < 0 {
-						return ErrInvalidLengthQuery
-					}
-					if postStringIndexmapvalue > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
-					iNdEx = postStringIndexmapvalue
-				} else {
-					iNdEx = entryPreIndex
-					skippy, err := skipQuery(dAtA[iNdEx:])
-					if err != nil {
-						return err
-					}
-					if skippy < 0 {
-						return ErrInvalidLengthQuery
-					}
-					if (iNdEx + skippy) > postIndex {
-						return io.ErrUnexpectedEOF
-					}
-					iNdEx += skippy
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Status |= TraceDiffNode_Status(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanIDA", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
 				}
 			}
-			m.Tags[mapkey] = mapvalue
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.SpanIDA.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 4:
+		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StartTimeMin", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanIDB", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowQuery
@@ -2224,28 +5281,28 @@ func (m *TraceQueryParameters) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthQuery
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthQuery
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.StartTimeMin, dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.SpanIDB.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 5:
+		case 7:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StartTimeMax", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationA", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2272,13 +5329,13 @@ func (m *TraceQueryParameters) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.StartTimeMax, dAtA[iNdEx:postIndex]); err != nil {
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.DurationA, dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 6:
+		case 8:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DurationMin", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationB", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2305,13 +5362,13 @@ func (m *TraceQueryParameters) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.DurationMin, dAtA[iNdEx:postIndex]); err != nil {
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.DurationB, dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 7:
+		case 9:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DurationMax", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationDelta", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2338,15 +5395,15 @@ func (m *TraceQueryParameters) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.DurationMax, dAtA[iNdEx:postIndex]); err != nil {
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.DurationDelta, dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 8:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SearchDepth", wireType)
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TagDiffs", wireType)
 			}
-			m.SearchDepth = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowQuery
@@ -2356,11 +5413,26 @@ func (m *TraceQueryParameters) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.SearchDepth |= int32(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TagDiffs = append(m.TagDiffs, TagDiff{})
+			if err := m.TagDiffs[len(m.TagDiffs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQuery(dAtA[iNdEx:])
@@ -2386,7 +5458,7 @@ func (m *TraceQueryParameters) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *FindTracesRequest) Unmarshal(dAtA []byte) error {
+func (m *CompareTracesResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2409,15 +5481,15 @@ func (m *FindTracesRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: FindTracesRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: CompareTracesResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: FindTracesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: CompareTracesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Nodes", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2444,10 +5516,8 @@ func (m *FindTracesRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Query == nil {
-				m.Query = &TraceQueryParameters{}
-			}
-			if err := m.Query.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Nodes = append(m.Nodes, TraceDiffNode{})
+			if err := m.Nodes[len(m.Nodes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -2476,61 +5546,7 @@ func (m *FindTracesRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *GetServicesRequest) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowQuery
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: GetServicesRequest: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: GetServicesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		default:
-			iNdEx = preIndex
-			skippy, err := skipQuery(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
-				return ErrInvalidLengthQuery
-			}
-			if (iNdEx + skippy) < 0 {
-				return ErrInvalidLengthQuery
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *GetServicesResponse) Unmarshal(dAtA []byte) error {
+func (m *DeleteTraceRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2553,17 +5569,17 @@ func (m *GetServicesResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: GetServicesResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: DeleteTraceRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: GetServicesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DeleteTraceRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Services", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceID", wireType)
 			}
-			var stringLen uint64
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowQuery
@@ -2573,23 +5589,24 @@ func (m *GetServicesResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthQuery
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthQuery
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Services = append(m.Services, string(dAtA[iNdEx:postIndex]))
+			if err := m.TraceID.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -2616,7 +5633,7 @@ func (m *GetServicesResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *GetOperationsRequest) Unmarshal(dAtA []byte) error {
+func (m *DeleteTraceResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2639,44 +5656,12 @@ func (m *GetOperationsRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: GetOperationsRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: DeleteTraceResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: GetOperationsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DeleteTraceResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
-		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Service", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowQuery
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthQuery
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthQuery
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Service = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQuery(dAtA[iNdEx:])
@@ -2702,7 +5687,7 @@ func (m *GetOperationsRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *GetOperationsResponse) Unmarshal(dAtA []byte) error {
+func (m *DeleteServiceTracesRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2725,15 +5710,15 @@ func (m *GetOperationsResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: GetOperationsResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: DeleteServiceTracesRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: GetOperationsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DeleteServiceTracesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Operations", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Service", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2761,65 +5746,11 @@ func (m *GetOperationsResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Operations = append(m.Operations, string(dAtA[iNdEx:postIndex]))
+			m.Service = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipQuery(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
-				return ErrInvalidLengthQuery
-			}
-			if (iNdEx + skippy) < 0 {
-				return ErrInvalidLengthQuery
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *GetDependenciesRequest) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowQuery
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: GetDependenciesRequest: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: GetDependenciesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StartTime", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTimeMin", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2846,13 +5777,13 @@ func (m *GetDependenciesRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.StartTime, dAtA[iNdEx:postIndex]); err != nil {
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.StartTimeMin, dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 2:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field EndTime", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTimeMax", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2879,7 +5810,7 @@ func (m *GetDependenciesRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.EndTime, dAtA[iNdEx:postIndex]); err != nil {
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.StartTimeMax, dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -2908,7 +5839,7 @@ func (m *GetDependenciesRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *GetDependenciesResponse) Unmarshal(dAtA []byte) error {
+func (m *DeleteServiceTracesResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2931,17 +5862,17 @@ func (m *GetDependenciesResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: GetDependenciesResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: DeleteServiceTracesResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: GetDependenciesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DeleteServiceTracesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Dependencies", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceIDs", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowQuery
@@ -2951,23 +5882,24 @@ func (m *GetDependenciesResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthQuery
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthQuery
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Dependencies = append(m.Dependencies, model.DependencyLink{})
-			if err := m.Dependencies[len(m.Dependencies)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			var v github_com_jaegertracing_jaeger_model.TraceID
+			m.TraceIDs = append(m.TraceIDs, v)
+			if err := m.TraceIDs[len(m.TraceIDs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
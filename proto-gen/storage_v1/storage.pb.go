@@ -442,17 +442,20 @@ func (m *GetOperationsResponse) GetOperations() []string {
 }
 
 type TraceQueryParameters struct {
-	ServiceName          string            `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
-	OperationName        string            `protobuf:"bytes,2,opt,name=operation_name,json=operationName,proto3" json:"operation_name,omitempty"`
-	Tags                 map[string]string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	StartTimeMin         time.Time         `protobuf:"bytes,4,opt,name=start_time_min,json=startTimeMin,proto3,stdtime" json:"start_time_min"`
-	StartTimeMax         time.Time         `protobuf:"bytes,5,opt,name=start_time_max,json=startTimeMax,proto3,stdtime" json:"start_time_max"`
-	DurationMin          time.Duration     `protobuf:"bytes,6,opt,name=duration_min,json=durationMin,proto3,stdduration" json:"duration_min"`
-	DurationMax          time.Duration     `protobuf:"bytes,7,opt,name=duration_max,json=durationMax,proto3,stdduration" json:"duration_max"`
-	NumTraces            int32             `protobuf:"varint,8,opt,name=num_traces,json=numTraces,proto3" json:"num_traces,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	ServiceName   string            `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	OperationName string            `protobuf:"bytes,2,opt,name=operation_name,json=operationName,proto3" json:"operation_name,omitempty"`
+	Tags          map[string]string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	StartTimeMin  time.Time         `protobuf:"bytes,4,opt,name=start_time_min,json=startTimeMin,proto3,stdtime" json:"start_time_min"`
+	StartTimeMax  time.Time         `protobuf:"bytes,5,opt,name=start_time_max,json=startTimeMax,proto3,stdtime" json:"start_time_max"`
+	DurationMin   time.Duration     `protobuf:"bytes,6,opt,name=duration_min,json=durationMin,proto3,stdduration" json:"duration_min"`
+	DurationMax   time.Duration     `protobuf:"bytes,7,opt,name=duration_max,json=durationMax,proto3,stdduration" json:"duration_max"`
+	NumTraces     int32             `protobuf:"varint,8,opt,name=num_traces,json=numTraces,proto3" json:"num_traces,omitempty"`
+	// number of matching traces returned by the previous pages of a paginated search
+	Offset               int32       `protobuf:"varint,9,opt,name=offset,proto3" json:"offset,omitempty"`
+	TagFilters           []TagFilter `protobuf:"bytes,10,rep,name=tag_filters,json=tagFilters,proto3" json:"tag_filters"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
 }
 
 func (m *TraceQueryParameters) Reset()         { *m = TraceQueryParameters{} }
@@ -544,6 +547,85 @@ func (m *TraceQueryParameters) GetNumTraces() int32 {
 	return 0
 }
 
+func (m *TraceQueryParameters) GetOffset() int32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *TraceQueryParameters) GetTagFilters() []TagFilter {
+	if m != nil {
+		return m.TagFilters
+	}
+	return nil
+}
+
+// TagFilter matches a span tag with an operator, in addition to the exact tag matches.
+type TagFilter struct {
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// One of "=", "!=", "=~" (regular expression), ">", "<" (numeric) or "exists".
+	Operator             string   `protobuf:"bytes,2,opt,name=operator,proto3" json:"operator,omitempty"`
+	Value                string   `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TagFilter) Reset()         { *m = TagFilter{} }
+func (m *TagFilter) String() string { return proto.CompactTextString(m) }
+func (*TagFilter) ProtoMessage()    {}
+func (*TagFilter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{10}
+}
+func (m *TagFilter) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TagFilter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TagFilter.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TagFilter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TagFilter.Merge(m, src)
+}
+func (m *TagFilter) XXX_Size() int {
+	return m.Size()
+}
+func (m *TagFilter) XXX_DiscardUnknown() {
+	xxx_messageInfo_TagFilter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TagFilter proto.InternalMessageInfo
+
+func (m *TagFilter) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *TagFilter) GetOperator() string {
+	if m != nil {
+		return m.Operator
+	}
+	return ""
+}
+
+func (m *TagFilter) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
 type FindTracesRequest struct {
 	Query                *TraceQueryParameters `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
@@ -555,7 +637,7 @@ func (m *FindTracesRequest) Reset()         { *m = FindTracesRequest{} }
 func (m *FindTracesRequest) String() string { return proto.CompactTextString(m) }
 func (*FindTracesRequest) ProtoMessage()    {}
 func (*FindTracesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0d2c4ccf1453ffdb, []int{10}
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{11}
 }
 func (m *FindTracesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -602,7 +684,7 @@ func (m *SpansResponseChunk) Reset()         { *m = SpansResponseChunk{} }
 func (m *SpansResponseChunk) String() string { return proto.CompactTextString(m) }
 func (*SpansResponseChunk) ProtoMessage()    {}
 func (*SpansResponseChunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0d2c4ccf1453ffdb, []int{11}
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{12}
 }
 func (m *SpansResponseChunk) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -649,7 +731,7 @@ func (m *FindTraceIDsRequest) Reset()         { *m = FindTraceIDsRequest{} }
 func (m *FindTraceIDsRequest) String() string { return proto.CompactTextString(m) }
 func (*FindTraceIDsRequest) ProtoMessage()    {}
 func (*FindTraceIDsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0d2c4ccf1453ffdb, []int{12}
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{13}
 }
 func (m *FindTraceIDsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -696,7 +778,7 @@ func (m *FindTraceIDsResponse) Reset()         { *m = FindTraceIDsResponse{} }
 func (m *FindTraceIDsResponse) String() string { return proto.CompactTextString(m) }
 func (*FindTraceIDsResponse) ProtoMessage()    {}
 func (*FindTraceIDsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0d2c4ccf1453ffdb, []int{13}
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{14}
 }
 func (m *FindTraceIDsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -748,6 +830,8 @@ func init() {
 	golang_proto.RegisterType((*TraceQueryParameters)(nil), "jaeger.storage.v1.TraceQueryParameters")
 	proto.RegisterMapType((map[string]string)(nil), "jaeger.storage.v1.TraceQueryParameters.TagsEntry")
 	golang_proto.RegisterMapType((map[string]string)(nil), "jaeger.storage.v1.TraceQueryParameters.TagsEntry")
+	proto.RegisterType((*TagFilter)(nil), "jaeger.storage.v1.TagFilter")
+	golang_proto.RegisterType((*TagFilter)(nil), "jaeger.storage.v1.TagFilter")
 	proto.RegisterType((*FindTracesRequest)(nil), "jaeger.storage.v1.FindTracesRequest")
 	golang_proto.RegisterType((*FindTracesRequest)(nil), "jaeger.storage.v1.FindTracesRequest")
 	proto.RegisterType((*SpansResponseChunk)(nil), "jaeger.storage.v1.SpansResponseChunk")
@@ -762,64 +846,68 @@ func init() { proto.RegisterFile("storage.proto", fileDescriptor_0d2c4ccf1453ffd
 func init() { golang_proto.RegisterFile("storage.proto", fileDescriptor_0d2c4ccf1453ffdb) }
 
 var fileDescriptor_0d2c4ccf1453ffdb = []byte{
-	// 905 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x4f, 0x6f, 0xe3, 0x44,
-	0x14, 0xc7, 0x6d, 0xb2, 0x71, 0x5e, 0xd2, 0xa5, 0x9d, 0x06, 0x30, 0x16, 0x9b, 0x14, 0x43, 0xb7,
-	0x05, 0x09, 0x67, 0x1b, 0x0e, 0x8b, 0x40, 0x08, 0xc8, 0xa6, 0x1b, 0x15, 0xf1, 0x67, 0xf1, 0x46,
-	0xac, 0xc4, 0x22, 0xac, 0x49, 0x3c, 0xb8, 0xde, 0xc6, 0x63, 0xaf, 0x3d, 0x8e, 0x92, 0x3b, 0x1f,
-	0x80, 0x23, 0x27, 0xae, 0x7c, 0x0d, 0x8e, 0x7b, 0xe4, 0xcc, 0xa1, 0xa0, 0x70, 0xe4, 0x4b, 0x20,
-	0xcf, 0x8c, 0xdd, 0xfc, 0xb1, 0xb6, 0xdd, 0x8a, 0x9b, 0xe7, 0xcd, 0xef, 0xfd, 0xde, 0x9b, 0xf7,
-	0xe7, 0x97, 0xc0, 0x56, 0xcc, 0x82, 0x08, 0xbb, 0xc4, 0x0c, 0xa3, 0x80, 0x05, 0x68, 0xe7, 0x09,
-	0x26, 0x2e, 0x89, 0xcc, 0xcc, 0x3a, 0x39, 0xd2, 0x1b, 0x6e, 0xe0, 0x06, 0xfc, 0xb6, 0x9d, 0x7e,
-	0x09, 0xa0, 0xde, 0x72, 0x83, 0xc0, 0x1d, 0x93, 0x36, 0x3f, 0x0d, 0x93, 0x1f, 0xdb, 0xcc, 0xf3,
-	0x49, 0xcc, 0xb0, 0x1f, 0x4a, 0x40, 0x73, 0x15, 0xe0, 0x24, 0x11, 0x66, 0x5e, 0x40, 0xe5, 0x7d,
-	0xcd, 0x0f, 0x1c, 0x32, 0x16, 0x07, 0xe3, 0x57, 0x05, 0x5e, 0xed, 0x13, 0xd6, 0x23, 0x21, 0xa1,
-	0x0e, 0xa1, 0x23, 0x8f, 0xc4, 0x16, 0x79, 0x9a, 0x90, 0x98, 0xa1, 0x7b, 0x00, 0x31, 0xc3, 0x11,
-	0xb3, 0xd3, 0x00, 0x9a, 0xb2, 0xa7, 0x1c, 0xd6, 0x3a, 0xba, 0x29, 0xc8, 0xcd, 0x8c, 0xdc, 0x1c,
-	0x64, 0xd1, 0xbb, 0xea, 0xb3, 0xf3, 0xd6, 0x4b, 0x3f, 0xff, 0xd5, 0x52, 0xac, 0x2a, 0xf7, 0x4b,
-	0x6f, 0xd0, 0x27, 0xa0, 0x12, 0xea, 0x08, 0x8a, 0x8d, 0x17, 0xa0, 0xa8, 0x10, 0xea, 0xa4, 0x76,
-	0x63, 0x08, 0xaf, 0xad, 0xe5, 0x17, 0x87, 0x01, 0x8d, 0x09, 0xea, 0x43, 0xdd, 0x59, 0xb0, 0x6b,
-	0xca, 0xde, 0xe6, 0x61, 0xad, 0x73, 0xcb, 0x94, 0x95, 0xc4, 0xa1, 0x67, 0x4f, 0x3a, 0x66, 0xee,
-	0x3a, 0xfb, 0xc2, 0xa3, 0x67, 0xdd, 0x52, 0x1a, 0xc2, 0x5a, 0x72, 0x34, 0x3e, 0x82, 0xed, 0x47,
-	0x91, 0xc7, 0xc8, 0xc3, 0x10, 0xd3, 0xec, 0xf5, 0x07, 0x50, 0x8a, 0x43, 0x4c, 0xe5, 0xbb, 0x77,
-	0x57, 0x48, 0x39, 0x92, 0x03, 0x8c, 0x5d, 0xd8, 0x59, 0x70, 0x16, 0xa9, 0x19, 0x14, 0x5e, 0xee,
-	0x13, 0x36, 0x88, 0xf0, 0x88, 0x64, 0x84, 0x8f, 0x41, 0x65, 0xe9, 0xd9, 0xf6, 0x1c, 0x4e, 0x5a,
-	0xef, 0x7e, 0x9a, 0xa6, 0xf2, 0xe7, 0x79, 0xeb, 0x3d, 0xd7, 0x63, 0xa7, 0xc9, 0xd0, 0x1c, 0x05,
-	0x7e, 0x5b, 0x84, 0x49, 0x81, 0x1e, 0x75, 0xe5, 0xa9, 0x2d, 0x1a, 0xc6, 0xd9, 0x4e, 0x7a, 0xf3,
-	0xf3, 0x56, 0x45, 0x7e, 0x5a, 0x15, 0xce, 0x78, 0xe2, 0x18, 0x0d, 0x40, 0x7d, 0xc2, 0x1e, 0x92,
-	0x68, 0xe2, 0x8d, 0xf2, 0x0e, 0x1a, 0x47, 0xb0, 0xbb, 0x64, 0x95, 0x75, 0xd3, 0x41, 0x8d, 0xa5,
-	0x8d, 0xd7, 0xac, 0x6a, 0xe5, 0x67, 0xe3, 0x0e, 0x34, 0xfa, 0x84, 0x7d, 0x1d, 0x12, 0x31, 0x32,
-	0xf9, 0x30, 0x68, 0x50, 0x91, 0x18, 0x9e, 0x7c, 0xd5, 0xca, 0x8e, 0xc6, 0x5d, 0x78, 0x65, 0xc5,
-	0x43, 0x86, 0x69, 0x02, 0x04, 0xb9, 0x55, 0x06, 0x5a, 0xb0, 0x18, 0xbf, 0x95, 0xa0, 0xc1, 0x1f,
-	0xf2, 0x4d, 0x42, 0xa2, 0xd9, 0x03, 0x1c, 0x61, 0x9f, 0x30, 0x12, 0xc5, 0xe8, 0x4d, 0xa8, 0x4b,
-	0x72, 0x9b, 0x62, 0x3f, 0x0b, 0x58, 0x93, 0xb6, 0xaf, 0xb0, 0x4f, 0xd0, 0x3e, 0xdc, 0xcc, 0x99,
-	0x04, 0x68, 0x83, 0x83, 0xb6, 0x72, 0x2b, 0x87, 0x1d, 0x43, 0x89, 0x61, 0x37, 0xd6, 0x36, 0xf9,
-	0x64, 0x1c, 0x99, 0x6b, 0x3b, 0x66, 0x16, 0x25, 0x60, 0x0e, 0xb0, 0x1b, 0x1f, 0x53, 0x16, 0xcd,
-	0x2c, 0xee, 0x8e, 0x3e, 0x87, 0x9b, 0x17, 0x9b, 0x60, 0xfb, 0x1e, 0xd5, 0x4a, 0x2f, 0x30, 0xca,
-	0xf5, 0x7c, 0x1b, 0xbe, 0xf4, 0xe8, 0x2a, 0x17, 0x9e, 0x6a, 0xe5, 0xeb, 0x71, 0xe1, 0x29, 0xba,
-	0x0f, 0xf5, 0x6c, 0xb7, 0x79, 0x56, 0x37, 0x38, 0xd3, 0xeb, 0x6b, 0x4c, 0x3d, 0x09, 0x12, 0x44,
-	0xbf, 0xa4, 0x44, 0xb5, 0xcc, 0x31, 0xcd, 0x69, 0x89, 0x07, 0x4f, 0xb5, 0xca, 0x75, 0x78, 0xf0,
-	0x14, 0xdd, 0x02, 0xa0, 0x89, 0x6f, 0xf3, 0xa1, 0x8c, 0x35, 0x75, 0x4f, 0x39, 0x2c, 0x5b, 0x55,
-	0x9a, 0xf8, 0xbc, 0xc8, 0xb1, 0x7e, 0x17, 0xaa, 0x79, 0x65, 0xd1, 0x36, 0x6c, 0x9e, 0x91, 0x99,
-	0xec, 0x6d, 0xfa, 0x89, 0x1a, 0x50, 0x9e, 0xe0, 0x71, 0x92, 0xb5, 0x52, 0x1c, 0x3e, 0xdc, 0xf8,
-	0x40, 0x31, 0x2c, 0xd8, 0xb9, 0xef, 0x51, 0x47, 0xd0, 0x64, 0x13, 0xf9, 0x31, 0x94, 0x9f, 0xa6,
-	0x7d, 0x93, 0x1b, 0x7a, 0x70, 0xc5, 0xe6, 0x5a, 0xc2, 0xcb, 0x38, 0x06, 0x94, 0x6e, 0x6c, 0x3e,
-	0xae, 0xf7, 0x4e, 0x13, 0x7a, 0x86, 0xda, 0x50, 0x4e, 0x97, 0x3a, 0xd3, 0x92, 0xa2, 0xb5, 0x97,
-	0x0a, 0x22, 0x70, 0xc6, 0x00, 0x76, 0xf3, 0xd4, 0x4e, 0x7a, 0xff, 0x57, 0x72, 0x13, 0x68, 0x2c,
-	0xb3, 0xca, 0x95, 0xfa, 0x01, 0xaa, 0x99, 0x86, 0x88, 0x14, 0xeb, 0xdd, 0xcf, 0xae, 0x2b, 0x22,
-	0x6a, 0xce, 0xae, 0x4a, 0x15, 0x89, 0x3b, 0x4f, 0x60, 0x3b, 0x7d, 0x22, 0xd7, 0xb3, 0xe8, 0xc1,
-	0x38, 0x71, 0x3d, 0x8a, 0xbe, 0x85, 0x6a, 0xae, 0x6f, 0xe8, 0xad, 0x82, 0x87, 0xac, 0x4a, 0xa7,
-	0xfe, 0xf6, 0xf3, 0x41, 0xe2, 0x2d, 0x9d, 0x7f, 0x37, 0x45, 0x30, 0x8b, 0x60, 0x27, 0x0f, 0xf6,
-	0x08, 0xd4, 0x4c, 0x37, 0x91, 0x51, 0x40, 0xb3, 0x22, 0xaa, 0xfa, 0x7e, 0x01, 0x66, 0xbd, 0xad,
-	0x77, 0x14, 0xf4, 0x3d, 0xd4, 0x16, 0xa4, 0x10, 0xed, 0x17, 0x73, 0xaf, 0x08, 0xa8, 0x7e, 0xfb,
-	0x32, 0x98, 0xec, 0xcb, 0x10, 0xb6, 0x96, 0x34, 0x10, 0x1d, 0x14, 0x3b, 0xae, 0xe9, 0xaa, 0x7e,
-	0x78, 0x39, 0x50, 0xc6, 0x78, 0x0c, 0x70, 0xb1, 0x04, 0xa8, 0xa8, 0xc6, 0x6b, 0x3b, 0x72, 0xf5,
-	0xf2, 0xd8, 0x50, 0x5f, 0x1c, 0x38, 0x74, 0xfb, 0x79, 0xf4, 0x17, 0x73, 0xae, 0x1f, 0x5c, 0x8a,
-	0x93, 0xdd, 0xfe, 0x49, 0x01, 0x6d, 0xf9, 0x47, 0x7c, 0xa1, 0xeb, 0xa7, 0xfc, 0xd7, 0x72, 0xf1,
-	0x1a, 0xbd, 0x53, 0x5c, 0x97, 0x82, 0xff, 0x29, 0xfa, 0xbb, 0x57, 0x81, 0x8a, 0x34, 0xba, 0x6f,
-	0x3c, 0x9b, 0x37, 0x95, 0x3f, 0xe6, 0x4d, 0xe5, 0xef, 0x79, 0x53, 0xf9, 0xfd, 0x9f, 0xa6, 0xf2,
-	0x1d, 0x48, 0x2f, 0x7b, 0x72, 0x34, 0xbc, 0xc1, 0x95, 0xee, 0xfd, 0xff, 0x02, 0x00, 0x00, 0xff,
-	0xff, 0x7d, 0x2c, 0x50, 0x42, 0x9b, 0x09, 0x00, 0x00,
+	// 963 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x4f, 0x73, 0xdb, 0x44,
+	0x14, 0x47, 0x71, 0x1c, 0xcb, 0xcf, 0x4e, 0x49, 0x36, 0xa6, 0x08, 0x4d, 0x6b, 0x07, 0x41, 0x9a,
+	0xc0, 0x0c, 0x72, 0x63, 0x0e, 0x65, 0x60, 0x18, 0xc0, 0xf9, 0xe3, 0x09, 0x03, 0xb4, 0xa8, 0x19,
+	0x3a, 0x43, 0x19, 0x34, 0xeb, 0x68, 0xa3, 0xa8, 0xb1, 0x56, 0xae, 0x76, 0xe5, 0x49, 0xee, 0xfd,
+	0x00, 0x1c, 0x39, 0xf1, 0x59, 0x38, 0xf6, 0xc8, 0x99, 0x43, 0x60, 0xc2, 0x91, 0x2f, 0xc1, 0x68,
+	0x77, 0x25, 0xff, 0xd3, 0x34, 0x69, 0x86, 0x9b, 0xf7, 0xed, 0xef, 0xfd, 0xde, 0xdb, 0xf7, 0xe7,
+	0x27, 0xc3, 0x32, 0xe3, 0x51, 0x8c, 0x7d, 0x62, 0x0f, 0xe3, 0x88, 0x47, 0x68, 0xf5, 0x19, 0x26,
+	0x3e, 0x89, 0xed, 0xcc, 0x3a, 0xda, 0x36, 0x1b, 0x7e, 0xe4, 0x47, 0xe2, 0xb6, 0x9d, 0xfe, 0x92,
+	0x40, 0xb3, 0xe5, 0x47, 0x91, 0x3f, 0x20, 0x6d, 0x71, 0xea, 0x27, 0xc7, 0x6d, 0x1e, 0x84, 0x84,
+	0x71, 0x1c, 0x0e, 0x15, 0xa0, 0x39, 0x0b, 0xf0, 0x92, 0x18, 0xf3, 0x20, 0xa2, 0xea, 0xbe, 0x16,
+	0x46, 0x1e, 0x19, 0xc8, 0x83, 0xf5, 0x9b, 0x06, 0xb7, 0x7b, 0x84, 0xef, 0x92, 0x21, 0xa1, 0x1e,
+	0xa1, 0x47, 0x01, 0x61, 0x0e, 0x79, 0x9e, 0x10, 0xc6, 0xd1, 0x0e, 0x00, 0xe3, 0x38, 0xe6, 0x6e,
+	0x1a, 0xc0, 0xd0, 0xd6, 0xb5, 0xad, 0x5a, 0xc7, 0xb4, 0x25, 0xb9, 0x9d, 0x91, 0xdb, 0x87, 0x59,
+	0xf4, 0xae, 0xfe, 0xf2, 0xa2, 0xf5, 0xc6, 0x2f, 0x7f, 0xb5, 0x34, 0xa7, 0x2a, 0xfc, 0xd2, 0x1b,
+	0xf4, 0x05, 0xe8, 0x84, 0x7a, 0x92, 0x62, 0xe1, 0x35, 0x28, 0x2a, 0x84, 0x7a, 0xa9, 0xdd, 0xea,
+	0xc3, 0xdb, 0x73, 0xf9, 0xb1, 0x61, 0x44, 0x19, 0x41, 0x3d, 0xa8, 0x7b, 0x13, 0x76, 0x43, 0x5b,
+	0x2f, 0x6d, 0xd5, 0x3a, 0x77, 0x6d, 0x55, 0x49, 0x3c, 0x0c, 0xdc, 0x51, 0xc7, 0xce, 0x5d, 0xcf,
+	0xbf, 0x09, 0xe8, 0x69, 0x77, 0x31, 0x0d, 0xe1, 0x4c, 0x39, 0x5a, 0x9f, 0xc1, 0xca, 0x93, 0x38,
+	0xe0, 0xe4, 0xf1, 0x10, 0xd3, 0xec, 0xf5, 0x9b, 0xb0, 0xc8, 0x86, 0x98, 0xaa, 0x77, 0xaf, 0xcd,
+	0x90, 0x0a, 0xa4, 0x00, 0x58, 0x6b, 0xb0, 0x3a, 0xe1, 0x2c, 0x53, 0xb3, 0x28, 0xbc, 0xd9, 0x23,
+	0xfc, 0x30, 0xc6, 0x47, 0x24, 0x23, 0x7c, 0x0a, 0x3a, 0x4f, 0xcf, 0x6e, 0xe0, 0x09, 0xd2, 0x7a,
+	0xf7, 0xcb, 0x34, 0x95, 0x3f, 0x2f, 0x5a, 0x1f, 0xf9, 0x01, 0x3f, 0x49, 0xfa, 0xf6, 0x51, 0x14,
+	0xb6, 0x65, 0x98, 0x14, 0x18, 0x50, 0x5f, 0x9d, 0xda, 0xb2, 0x61, 0x82, 0xed, 0x60, 0xf7, 0xf2,
+	0xa2, 0x55, 0x51, 0x3f, 0x9d, 0x8a, 0x60, 0x3c, 0xf0, 0xac, 0x06, 0xa0, 0x1e, 0xe1, 0x8f, 0x49,
+	0x3c, 0x0a, 0x8e, 0xf2, 0x0e, 0x5a, 0xdb, 0xb0, 0x36, 0x65, 0x55, 0x75, 0x33, 0x41, 0x67, 0xca,
+	0x26, 0x6a, 0x56, 0x75, 0xf2, 0xb3, 0x75, 0x1f, 0x1a, 0x3d, 0xc2, 0x1f, 0x0e, 0x89, 0x1c, 0x99,
+	0x7c, 0x18, 0x0c, 0xa8, 0x28, 0x8c, 0x48, 0xbe, 0xea, 0x64, 0x47, 0xeb, 0x01, 0xbc, 0x35, 0xe3,
+	0xa1, 0xc2, 0x34, 0x01, 0xa2, 0xdc, 0xaa, 0x02, 0x4d, 0x58, 0xac, 0x17, 0x65, 0x68, 0x88, 0x87,
+	0x7c, 0x9f, 0x90, 0xf8, 0xfc, 0x11, 0x8e, 0x71, 0x48, 0x38, 0x89, 0x19, 0x7a, 0x17, 0xea, 0x8a,
+	0xdc, 0xa5, 0x38, 0xcc, 0x02, 0xd6, 0x94, 0xed, 0x3b, 0x1c, 0x12, 0xb4, 0x01, 0xb7, 0x72, 0x26,
+	0x09, 0x5a, 0x10, 0xa0, 0xe5, 0xdc, 0x2a, 0x60, 0x7b, 0xb0, 0xc8, 0xb1, 0xcf, 0x8c, 0x92, 0x98,
+	0x8c, 0x6d, 0x7b, 0x6e, 0xc7, 0xec, 0xa2, 0x04, 0xec, 0x43, 0xec, 0xb3, 0x3d, 0xca, 0xe3, 0x73,
+	0x47, 0xb8, 0xa3, 0xaf, 0xe1, 0xd6, 0x78, 0x13, 0xdc, 0x30, 0xa0, 0xc6, 0xe2, 0x6b, 0x8c, 0x72,
+	0x3d, 0xdf, 0x86, 0x6f, 0x03, 0x3a, 0xcb, 0x85, 0xcf, 0x8c, 0xf2, 0xcd, 0xb8, 0xf0, 0x19, 0xda,
+	0x87, 0x7a, 0xb6, 0xdb, 0x22, 0xab, 0x25, 0xc1, 0xf4, 0xce, 0x1c, 0xd3, 0xae, 0x02, 0x49, 0xa2,
+	0x5f, 0x53, 0xa2, 0x5a, 0xe6, 0x98, 0xe6, 0x34, 0xc5, 0x83, 0xcf, 0x8c, 0xca, 0x4d, 0x78, 0xf0,
+	0x19, 0xba, 0x0b, 0x40, 0x93, 0xd0, 0x15, 0x43, 0xc9, 0x0c, 0x7d, 0x5d, 0xdb, 0x2a, 0x3b, 0x55,
+	0x9a, 0x84, 0xa2, 0xc8, 0x0c, 0xdd, 0x86, 0xa5, 0xe8, 0xf8, 0x98, 0x11, 0x6e, 0x54, 0xc5, 0x95,
+	0x3a, 0xa1, 0x1d, 0xa8, 0x71, 0xec, 0xbb, 0xc7, 0xc1, 0x20, 0xad, 0xbe, 0x01, 0xa2, 0x59, 0x77,
+	0x8a, 0x9a, 0x85, 0xfd, 0x7d, 0x01, 0x52, 0x5b, 0x0c, 0x3c, 0x33, 0x30, 0xf3, 0x01, 0x54, 0xf3,
+	0xb6, 0xa1, 0x15, 0x28, 0x9d, 0x92, 0x73, 0x35, 0x38, 0xe9, 0x4f, 0xd4, 0x80, 0xf2, 0x08, 0x0f,
+	0x92, 0x6c, 0x4e, 0xe4, 0xe1, 0xd3, 0x85, 0x4f, 0x34, 0xeb, 0xa1, 0x70, 0x94, 0x34, 0x05, 0x8e,
+	0x26, 0xe8, 0x72, 0xa6, 0xa2, 0x58, 0xf9, 0xe6, 0xe7, 0x31, 0x69, 0x69, 0x82, 0xd4, 0x72, 0x60,
+	0x75, 0x3f, 0xa0, 0x9e, 0x7c, 0x74, 0xb6, 0x3f, 0x9f, 0x43, 0xf9, 0x79, 0x3a, 0x65, 0x4a, 0x4f,
+	0x36, 0xaf, 0x39, 0x8a, 0x8e, 0xf4, 0xb2, 0xf6, 0x00, 0xa5, 0xfa, 0x92, 0x2f, 0xd7, 0xce, 0x49,
+	0x42, 0x4f, 0x51, 0x1b, 0xca, 0xa9, 0x04, 0x65, 0xca, 0x57, 0x24, 0x52, 0xaa, 0x52, 0x12, 0x67,
+	0x1d, 0xc2, 0x5a, 0x9e, 0xda, 0xc1, 0xee, 0xff, 0x95, 0xdc, 0x08, 0x1a, 0xd3, 0xac, 0x4a, 0x00,
+	0x7e, 0x86, 0x6a, 0xa6, 0x78, 0x32, 0xc5, 0x7a, 0xf7, 0xab, 0x9b, 0x4a, 0x9e, 0x9e, 0xb3, 0xeb,
+	0x4a, 0xf3, 0x58, 0xe7, 0x19, 0xac, 0xa4, 0x4f, 0x14, 0xea, 0x1b, 0x3f, 0x1a, 0x24, 0x7e, 0x40,
+	0xd1, 0x0f, 0x50, 0xcd, 0xd5, 0x18, 0xbd, 0x57, 0xf0, 0x90, 0x59, 0xa1, 0x37, 0xdf, 0x7f, 0x35,
+	0x48, 0xbe, 0xa5, 0xf3, 0x6f, 0x49, 0x06, 0x73, 0x08, 0xf6, 0xf2, 0x60, 0x4f, 0x40, 0xcf, 0x54,
+	0x1e, 0x59, 0x05, 0x34, 0x33, 0x9f, 0x00, 0x73, 0xa3, 0x00, 0x33, 0xdf, 0xd6, 0xfb, 0x1a, 0xfa,
+	0x09, 0x6a, 0x13, 0xc2, 0x8d, 0x36, 0x8a, 0xb9, 0x67, 0xe4, 0xde, 0xbc, 0x77, 0x15, 0x4c, 0xf5,
+	0xa5, 0x0f, 0xcb, 0x53, 0x8a, 0x8d, 0x36, 0x8b, 0x1d, 0xe7, 0xbe, 0x02, 0xe6, 0xd6, 0xd5, 0x40,
+	0x15, 0xe3, 0x29, 0xc0, 0x78, 0x09, 0x50, 0x51, 0x8d, 0xe7, 0x76, 0xe4, 0xfa, 0xe5, 0x71, 0xa1,
+	0x3e, 0x39, 0x70, 0xe8, 0xde, 0xab, 0xe8, 0xc7, 0x73, 0x6e, 0x6e, 0x5e, 0x89, 0x53, 0xdd, 0x7e,
+	0xa1, 0x81, 0x31, 0xfd, 0x97, 0x63, 0xa2, 0xeb, 0x27, 0xe2, 0xdb, 0x3e, 0x79, 0x8d, 0x3e, 0x28,
+	0xae, 0x4b, 0xc1, 0xbf, 0x2a, 0xf3, 0xc3, 0xeb, 0x40, 0x65, 0x1a, 0xdd, 0x3b, 0x2f, 0x2f, 0x9b,
+	0xda, 0x1f, 0x97, 0x4d, 0xed, 0xef, 0xcb, 0xa6, 0xf6, 0xfb, 0x3f, 0x4d, 0xed, 0x47, 0x50, 0x5e,
+	0xee, 0x68, 0xbb, 0xbf, 0x24, 0x74, 0xf9, 0xe3, 0xff, 0x06, 0x00, 0x68, 0x86, 0x11, 0x48, 0x49,
+	0x0a, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1567,6 +1655,62 @@ func (m *TraceQueryParameters) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintStorage(dAtA, i, uint64(m.NumTraces))
 	}
+	if m.Offset != 0 {
+		dAtA[i] = 0x48
+		i++
+		i = encodeVarintStorage(dAtA, i, uint64(m.Offset))
+	}
+	if len(m.TagFilters) > 0 {
+		for _, msg := range m.TagFilters {
+			dAtA[i] = 0x52
+			i++
+			i = encodeVarintStorage(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *TagFilter) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TagFilter) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Key) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStorage(dAtA, i, uint64(len(m.Key)))
+		i += copy(dAtA[i:], m.Key)
+	}
+	if len(m.Operator) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintStorage(dAtA, i, uint64(len(m.Operator)))
+		i += copy(dAtA[i:], m.Operator)
+	}
+	if len(m.Value) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintStorage(dAtA, i, uint64(len(m.Value)))
+		i += copy(dAtA[i:], m.Value)
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -1883,6 +2027,39 @@ func (m *TraceQueryParameters) Size() (n int) {
 	if m.NumTraces != 0 {
 		n += 1 + sovStorage(uint64(m.NumTraces))
 	}
+	if m.Offset != 0 {
+		n += 1 + sovStorage(uint64(m.Offset))
+	}
+	if len(m.TagFilters) > 0 {
+		for _, e := range m.TagFilters {
+			l = e.Size()
+			n += 1 + l + sovStorage(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *TagFilter) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovStorage(uint64(l))
+	}
+	l = len(m.Operator)
+	if l > 0 {
+		n += 1 + l + sovStorage(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovStorage(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -3092,6 +3269,209 @@ func (m *TraceQueryParameters) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+			}
+			m.Offset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Offset |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TagFilters", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TagFilters = append(m.TagFilters, TagFilter{})
+			if err := m.TagFilters[len(m.TagFilters)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStorage(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TagFilter) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStorage
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TagFilter: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TagFilter: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Operator", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Operator = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStorage(dAtA[iNdEx:])
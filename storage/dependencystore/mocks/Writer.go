@@ -0,0 +1,41 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mocks
+
+import dependencystore "github.com/jaegertracing/jaeger/storage/dependencystore"
+import mock "github.com/stretchr/testify/mock"
+import model "github.com/jaegertracing/jaeger/model"
+import time "time"
+
+// Writer is an autogenerated mock type for the Writer type
+type Writer struct {
+	mock.Mock
+}
+
+// WriteDependencies provides a mock function with given fields: ts, dependencies
+func (_m *Writer) WriteDependencies(ts time.Time, dependencies []model.DependencyLink) error {
+	ret := _m.Called(ts, dependencies)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(time.Time, []model.DependencyLink) error); ok {
+		r0 = rf(ts, dependencies)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+var _ dependencystore.Writer = (*Writer)(nil)
@@ -20,7 +20,9 @@ import (
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/pkg/distributedlock"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/samplingstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -61,3 +63,55 @@ type ArchiveFactory interface {
 	// CreateArchiveSpanWriter creates a spanstore.Writer.
 	CreateArchiveSpanWriter() (spanstore.Writer, error)
 }
+
+// ErrSamplingStorageNotSupported can be returned by the SamplingStoreFactory when the sampling storage is not supported by the backend.
+var ErrSamplingStorageNotSupported = errors.New("sampling storage not supported")
+
+// SamplingStoreFactory is an additional interface that can be implemented by a factory to provide
+// the storage components required by adaptive sampling.
+type SamplingStoreFactory interface {
+	// CreateLock creates a distributedlock.Lock used for leader election.
+	CreateLock() (distributedlock.Lock, error)
+
+	// CreateSamplingStore creates a samplingstore.Store.
+	CreateSamplingStore() (samplingstore.Store, error)
+}
+
+// ErrDependencyWriterNotSupported can be returned by the DependencyWriterFactory when the backend cannot store dependencies.
+var ErrDependencyWriterNotSupported = errors.New("dependency writer not supported")
+
+// DependencyWriterFactory is an additional interface that can be implemented by a factory to store
+// the service dependencies aggregated from the spans.
+type DependencyWriterFactory interface {
+	// CreateDependencyWriter creates a dependencystore.Writer.
+	CreateDependencyWriter() (dependencystore.Writer, error)
+}
+
+// ErrSpanDeleterNotSupported can be returned by the DeleterFactory when the backend cannot delete traces.
+var ErrSpanDeleterNotSupported = errors.New("span deleter not supported")
+
+// DeleterFactory is an additional interface that can be implemented by a factory to delete traces.
+type DeleterFactory interface {
+	// CreateSpanDeleter creates a spanstore.Deleter.
+	CreateSpanDeleter() (spanstore.Deleter, error)
+}
+
+// ErrMultiTenancyNotSupported can be returned by the TenantFactory when the backend cannot keep the data of tenants apart.
+var ErrMultiTenancyNotSupported = errors.New("multi-tenancy not supported")
+
+// TenantFactory is an additional interface that can be implemented by a factory to keep
+// the data of each tenant apart. The components it creates only access the data of the given tenant.
+type TenantFactory interface {
+	// CreateTenantSpanReader creates a spanstore.Reader for the tenant.
+	CreateTenantSpanReader(tenant string) (spanstore.Reader, error)
+
+	// CreateTenantSpanWriter creates a spanstore.Writer for the tenant.
+	CreateTenantSpanWriter(tenant string) (spanstore.Writer, error)
+
+	// CreateTenantDependencyReader creates a dependencystore.Reader for the tenant.
+	CreateTenantDependencyReader(tenant string) (dependencystore.Reader, error)
+
+	// CreateTenantSpanDeleter creates a spanstore.Deleter for the tenant, or returns
+	// ErrSpanDeleterNotSupported if the backend cannot delete traces.
+	CreateTenantSpanDeleter(tenant string) (spanstore.Deleter, error)
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mocks
+
+import dependencystore "github.com/jaegertracing/jaeger/storage/dependencystore"
+import mock "github.com/stretchr/testify/mock"
+import spanstore "github.com/jaegertracing/jaeger/storage/spanstore"
+import storage "github.com/jaegertracing/jaeger/storage"
+
+// TenantFactory is an autogenerated mock type for the TenantFactory type
+type TenantFactory struct {
+	mock.Mock
+}
+
+// CreateTenantDependencyReader provides a mock function with given fields: tenant
+func (_m *TenantFactory) CreateTenantDependencyReader(tenant string) (dependencystore.Reader, error) {
+	ret := _m.Called(tenant)
+
+	var r0 dependencystore.Reader
+	if rf, ok := ret.Get(0).(func(string) dependencystore.Reader); ok {
+		r0 = rf(tenant)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(dependencystore.Reader)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tenant)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateTenantSpanDeleter provides a mock function with given fields: tenant
+func (_m *TenantFactory) CreateTenantSpanDeleter(tenant string) (spanstore.Deleter, error) {
+	ret := _m.Called(tenant)
+
+	var r0 spanstore.Deleter
+	if rf, ok := ret.Get(0).(func(string) spanstore.Deleter); ok {
+		r0 = rf(tenant)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(spanstore.Deleter)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tenant)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateTenantSpanReader provides a mock function with given fields: tenant
+func (_m *TenantFactory) CreateTenantSpanReader(tenant string) (spanstore.Reader, error) {
+	ret := _m.Called(tenant)
+
+	var r0 spanstore.Reader
+	if rf, ok := ret.Get(0).(func(string) spanstore.Reader); ok {
+		r0 = rf(tenant)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(spanstore.Reader)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tenant)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateTenantSpanWriter provides a mock function with given fields: tenant
+func (_m *TenantFactory) CreateTenantSpanWriter(tenant string) (spanstore.Writer, error) {
+	ret := _m.Called(tenant)
+
+	var r0 spanstore.Writer
+	if rf, ok := ret.Get(0).(func(string) spanstore.Writer); ok {
+		r0 = rf(tenant)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(spanstore.Writer)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tenant)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+var _ storage.TenantFactory = (*TenantFactory)(nil)
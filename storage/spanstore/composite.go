@@ -15,6 +15,9 @@
 package spanstore
 
 import (
+	"context"
+	"time"
+
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/multierror"
 )
@@ -41,3 +44,47 @@ func (c *CompositeWriter) WriteSpan(span *model.Span) error {
 	}
 	return multierror.Wrap(errors)
 }
+
+// CompositeDeleter is a Deleter that deletes traces from several underlying Deleters
+type CompositeDeleter struct {
+	deleters []Deleter
+}
+
+// NewCompositeDeleter creates a CompositeDeleter
+func NewCompositeDeleter(deleters ...Deleter) *CompositeDeleter {
+	return &CompositeDeleter{
+		deleters: deleters,
+	}
+}
+
+// DeleteTrace calls DeleteTrace on each deleter. It will sum up failures, it is not transactional
+func (c *CompositeDeleter) DeleteTrace(ctx context.Context, traceID model.TraceID) error {
+	var errors []error
+	for _, deleter := range c.deleters {
+		if err := deleter.DeleteTrace(ctx, traceID); err != nil {
+			errors = append(errors, err)
+		}
+	}
+	return multierror.Wrap(errors)
+}
+
+// DeleteServiceTraces calls DeleteServiceTraces on each deleter and returns the IDs of the traces deleted
+// from any of them. It will sum up failures, it is not transactional
+func (c *CompositeDeleter) DeleteServiceTraces(ctx context.Context, service string, startTimeMin, startTimeMax time.Time) ([]model.TraceID, error) {
+	var errors []error
+	var traceIDs []model.TraceID
+	seen := make(map[model.TraceID]struct{})
+	for _, deleter := range c.deleters {
+		deleted, err := deleter.DeleteServiceTraces(ctx, service, startTimeMin, startTimeMax)
+		if err != nil {
+			errors = append(errors, err)
+		}
+		for _, traceID := range deleted {
+			if _, ok := seen[traceID]; !ok {
+				seen[traceID] = struct{}{}
+				traceIDs = append(traceIDs, traceID)
+			}
+		}
+	}
+	return traceIDs, multierror.Wrap(errors)
+}
@@ -15,14 +15,17 @@
 package spanstore_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/jaegertracing/jaeger/model"
 	. "github.com/jaegertracing/jaeger/storage/spanstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore/mocks"
 )
 
 var errIWillAlwaysFail = errors.New("ErrProneWriteSpanStore will always fail")
@@ -53,3 +56,34 @@ func TestCompositeWriteSpanStoreFirstFailure(t *testing.T) {
 	c := NewCompositeWriter(&errProneWriteSpanStore{}, &noopWriteSpanStore{})
 	assert.Equal(t, errIWillAlwaysFail, c.WriteSpan(nil))
 }
+
+func TestCompositeDeleterDeleteTrace(t *testing.T) {
+	ctx := context.Background()
+	traceID := model.NewTraceID(0, 1)
+	first, second := &mocks.Deleter{}, &mocks.Deleter{}
+	first.On("DeleteTrace", ctx, traceID).Return(nil)
+	second.On("DeleteTrace", ctx, traceID).Return(errIWillAlwaysFail)
+
+	assert.NoError(t, NewCompositeDeleter(first, first).DeleteTrace(ctx, traceID))
+	assert.Equal(t, errIWillAlwaysFail, NewCompositeDeleter(first, second).DeleteTrace(ctx, traceID))
+}
+
+func TestCompositeDeleterDeleteServiceTraces(t *testing.T) {
+	ctx := context.Background()
+	start, end := time.Unix(0, 0), time.Unix(60, 0)
+	first, second, failing := &mocks.Deleter{}, &mocks.Deleter{}, &mocks.Deleter{}
+	first.On("DeleteServiceTraces", ctx, "svc", start, end).
+		Return([]model.TraceID{model.NewTraceID(0, 1), model.NewTraceID(0, 2)}, nil)
+	second.On("DeleteServiceTraces", ctx, "svc", start, end).
+		Return([]model.TraceID{model.NewTraceID(0, 2), model.NewTraceID(0, 3)}, nil)
+	failing.On("DeleteServiceTraces", ctx, "svc", start, end).
+		Return([]model.TraceID{model.NewTraceID(0, 4)}, errIWillAlwaysFail)
+
+	traceIDs, err := NewCompositeDeleter(first, second).DeleteServiceTraces(ctx, "svc", start, end)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.TraceID{model.NewTraceID(0, 1), model.NewTraceID(0, 2), model.NewTraceID(0, 3)}, traceIDs)
+
+	traceIDs, err = NewCompositeDeleter(failing, first).DeleteServiceTraces(ctx, "svc", start, end)
+	assert.Equal(t, errIWillAlwaysFail, err)
+	assert.Equal(t, []model.TraceID{model.NewTraceID(0, 4), model.NewTraceID(0, 1), model.NewTraceID(0, 2)}, traceIDs)
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"time"
+)
+
+var (
+	// ErrInvalidContinuationToken is returned by ParseContinuationToken if the token cannot be decoded.
+	ErrInvalidContinuationToken = errors.New("invalid continuation token")
+	// ErrContinuationTokenMismatch is returned by ApplyContinuationToken if the token was issued for another query.
+	ErrContinuationTokenMismatch = errors.New("continuation token was issued for another query")
+)
+
+// ContinuationToken marks the position reached by the previous pages of FindTraces results.
+//
+// Readers return the traces matching a query in a deterministic order, so a position
+// in the results is simply the number of traces already returned. The token also pins
+// the search window to the one used for the first page, so that neither traces arriving
+// while the results are being walked nor a default start time computed anew for every
+// request shift the pages. Query identifies the other parameters of the query, so that
+// the token is not applied to a different search.
+type ContinuationToken struct {
+	StartTimeMin time.Time `json:"start"`
+	StartTimeMax time.Time `json:"end"`
+	Offset       int       `json:"offset"`
+	Query        string    `json:"query"`
+}
+
+// String encodes the token as an opaque URL-safe string.
+func (t ContinuationToken) String() string {
+	// json.Marshal cannot fail on this struct
+	b, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// ParseContinuationToken decodes a token produced by ContinuationToken.String.
+func ParseContinuationToken(s string) (*ContinuationToken, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidContinuationToken
+	}
+	token := &ContinuationToken{}
+	if err := json.Unmarshal(b, token); err != nil {
+		return nil, ErrInvalidContinuationToken
+	}
+	if token.Offset < 0 || token.StartTimeMax.IsZero() || token.StartTimeMax.Before(token.StartTimeMin) {
+		return nil, ErrInvalidContinuationToken
+	}
+	return token, nil
+}
+
+// NextContinuationToken returns the token for the page following numResults traces returned
+// for the query, or nil if fewer traces than requested were returned, i.e. there are no more pages.
+func NextContinuationToken(query *TraceQueryParameters, numResults int) *ContinuationToken {
+	if query.NumTraces <= 0 || numResults < query.NumTraces {
+		return nil
+	}
+	return &ContinuationToken{
+		StartTimeMin: query.StartTimeMin,
+		StartTimeMax: query.StartTimeMax,
+		Offset:       query.Offset() + numResults,
+		Query:        queryFingerprint(query),
+	}
+}
+
+// ApplyContinuationToken sets the query to select the page following the pages the token was issued for:
+// the search window of the query is replaced with the one pinned by the token. It returns
+// ErrContinuationTokenMismatch if the other parameters of the query differ from the ones of the first page.
+// The number of traces per page may change between pages.
+func (p *TraceQueryParameters) ApplyContinuationToken(token *ContinuationToken) error {
+	if token.Query != queryFingerprint(p) {
+		return ErrContinuationTokenMismatch
+	}
+	p.StartTimeMin = token.StartTimeMin
+	p.StartTimeMax = token.StartTimeMax
+	p.ContinuationToken = token
+	return nil
+}
+
+// queryFingerprint hashes the parameters of the query that select the matching traces, except for the
+// search window which the token pins itself.
+func queryFingerprint(query *TraceQueryParameters) string {
+	tags, tagFilters := query.Tags, query.TagFilters
+	if len(tags) == 0 {
+		tags = nil
+	}
+	if len(tagFilters) == 0 {
+		tagFilters = nil
+	}
+	// json.Marshal sorts the keys of the tags and cannot fail on this struct
+	b, _ := json.Marshal(struct {
+		ServiceName   string
+		OperationName string
+		Tags          map[string]string
+		DurationMin   time.Duration
+		DurationMax   time.Duration
+		TagFilters    []TagFilter
+	}{
+		ServiceName:   query.ServiceName,
+		OperationName: query.OperationName,
+		Tags:          tags,
+		DurationMin:   query.DurationMin,
+		DurationMax:   query.DurationMax,
+		TagFilters:    tagFilters,
+	})
+	h := fnv.New64a()
+	h.Write(b)
+	return fmt.Sprintf("%016x", h.Sum64())
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContinuationTokenRoundTrip(t *testing.T) {
+	token := ContinuationToken{
+		StartTimeMin: time.Date(2019, 5, 1, 9, 0, 0, 1000, time.UTC),
+		StartTimeMax: time.Date(2019, 5, 1, 10, 0, 0, 1000, time.UTC),
+		Offset:       200,
+		Query:        "0123456789abcdef",
+	}
+	parsed, err := ParseContinuationToken(token.String())
+	require.NoError(t, err)
+	assert.True(t, token.StartTimeMin.Equal(parsed.StartTimeMin))
+	assert.True(t, token.StartTimeMax.Equal(parsed.StartTimeMax))
+	assert.Equal(t, token.Offset, parsed.Offset)
+	assert.Equal(t, token.Query, parsed.Query)
+}
+
+func TestParseContinuationTokenErrors(t *testing.T) {
+	for _, s := range []string{
+		"not base64!",
+		ContinuationToken{}.String(),
+		ContinuationToken{StartTimeMax: time.Now(), Offset: -1}.String(),
+		ContinuationToken{StartTimeMin: time.Now(), StartTimeMax: time.Now().Add(-time.Hour)}.String(),
+		"bm90IGpzb24", // "not json"
+	} {
+		_, err := ParseContinuationToken(s)
+		assert.Equal(t, ErrInvalidContinuationToken, err, s)
+	}
+}
+
+func TestNextContinuationToken(t *testing.T) {
+	start, end := time.Now().Add(-time.Hour), time.Now()
+	query := &TraceQueryParameters{ServiceName: "svc", StartTimeMin: start, StartTimeMax: end, NumTraces: 10}
+	assert.Equal(t, 0, query.Offset())
+
+	next := NextContinuationToken(query, 10)
+	require.NotNil(t, next)
+	assert.Equal(t, ContinuationToken{StartTimeMin: start, StartTimeMax: end, Offset: 10, Query: queryFingerprint(query)}, *next)
+
+	query.ContinuationToken = next
+	assert.Equal(t, 10, query.Offset())
+	assert.Equal(t, 20, NextContinuationToken(query, 10).Offset)
+
+	assert.Nil(t, NextContinuationToken(query, 9))
+	assert.Nil(t, NextContinuationToken(&TraceQueryParameters{}, 5))
+}
+
+func TestApplyContinuationToken(t *testing.T) {
+	start, end := time.Now().Add(-time.Hour), time.Now()
+	first := &TraceQueryParameters{
+		ServiceName:  "svc",
+		Tags:         map[string]string{"a": "b"},
+		StartTimeMin: start,
+		StartTimeMax: end,
+		NumTraces:    10,
+	}
+	token := NextContinuationToken(first, 10)
+
+	// the default start time of the next request was computed later
+	next := *first
+	next.StartTimeMin = start.Add(time.Minute)
+	next.StartTimeMax = end.Add(time.Minute)
+	next.NumTraces = 20
+	require.NoError(t, next.ApplyContinuationToken(token))
+	assert.Equal(t, start, next.StartTimeMin)
+	assert.Equal(t, end, next.StartTimeMax)
+	assert.Equal(t, 10, next.Offset())
+
+	other := *first
+	other.Tags = map[string]string{"a": "c"}
+	assert.Equal(t, ErrContinuationTokenMismatch, other.ApplyContinuationToken(token))
+	assert.Nil(t, other.ContinuationToken)
+
+	// empty and missing tags and filters select the same traces
+	noTags := &TraceQueryParameters{ServiceName: "svc", NumTraces: 10}
+	emptyTags := &TraceQueryParameters{ServiceName: "svc", Tags: map[string]string{}, TagFilters: []TagFilter{}}
+	assert.NoError(t, emptyTags.ApplyContinuationToken(NextContinuationToken(noTags, 10)))
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// deleteBatchSize is the number of traces looked up at once by DeleteFoundTraces.
+const deleteBatchSize = 1000
+
+// DeleteFoundTraces deletes with deleteTrace the traces of the service that the reader finds within the time range,
+// until it finds no more, and returns their IDs. It implements Deleter.DeleteServiceTraces on top of the indices
+// of the storage backends.
+func DeleteFoundTraces(
+	ctx context.Context,
+	reader Reader,
+	service string,
+	startTimeMin, startTimeMax time.Time,
+	deleteTrace func(ctx context.Context, traceID model.TraceID) error,
+) ([]model.TraceID, error) {
+	query := &TraceQueryParameters{
+		ServiceName:  service,
+		StartTimeMin: startTimeMin,
+		StartTimeMax: startTimeMax,
+		NumTraces:    deleteBatchSize,
+	}
+	deleted := make(map[model.TraceID]struct{})
+	var traceIDs []model.TraceID
+	for {
+		found, err := reader.FindTraceIDs(ctx, query)
+		if err != nil {
+			return traceIDs, err
+		}
+		var more bool
+		for _, traceID := range found {
+			// the indices of some backends are only eventually consistent
+			if _, ok := deleted[traceID]; ok {
+				continue
+			}
+			if err := deleteTrace(ctx, traceID); err != nil {
+				return traceIDs, err
+			}
+			deleted[traceID] = struct{}{}
+			traceIDs = append(traceIDs, traceID)
+			more = true
+		}
+		if !more || len(found) < deleteBatchSize {
+			return traceIDs, nil
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jaegertracing/jaeger/model"
+	. "github.com/jaegertracing/jaeger/storage/spanstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+func traceIDs(count int, from uint64) []model.TraceID {
+	ids := make([]model.TraceID, count)
+	for i := range ids {
+		ids[i] = model.NewTraceID(0, from+uint64(i))
+	}
+	return ids
+}
+
+func TestDeleteFoundTraces(t *testing.T) {
+	start, end := time.Unix(0, 0), time.Unix(60, 0)
+	matchQuery := mock.MatchedBy(func(query *TraceQueryParameters) bool {
+		return query.ServiceName == "svc" && query.StartTimeMin == start && query.StartTimeMax == end && query.NumTraces == 1000
+	})
+	errFailed := errors.New("failed")
+
+	testCases := []struct {
+		caption     string
+		found       [][]model.TraceID
+		findErr     error
+		deleteErr   error
+		expected    []model.TraceID
+		expectedErr error
+	}{
+		{
+			caption:  "no traces",
+			found:    [][]model.TraceID{nil},
+			expected: nil,
+		},
+		{
+			caption:  "less than a batch",
+			found:    [][]model.TraceID{traceIDs(3, 1)},
+			expected: traceIDs(3, 1),
+		},
+		{
+			caption:  "several batches",
+			found:    [][]model.TraceID{traceIDs(1000, 1), traceIDs(2, 1001)},
+			expected: traceIDs(1002, 1),
+		},
+		{
+			caption:  "index not refreshed yet",
+			found:    [][]model.TraceID{traceIDs(1000, 1), traceIDs(1000, 1)},
+			expected: traceIDs(1000, 1),
+		},
+		{
+			caption:     "find error",
+			found:       [][]model.TraceID{nil},
+			findErr:     errFailed,
+			expectedErr: errFailed,
+		},
+		{
+			caption:     "delete error",
+			found:       [][]model.TraceID{traceIDs(3, 1)},
+			deleteErr:   errFailed,
+			expectedErr: errFailed,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.caption, func(t *testing.T) {
+			reader := &mocks.Reader{}
+			for _, found := range testCase.found {
+				reader.On("FindTraceIDs", mock.Anything, matchQuery).Return(found, testCase.findErr).Once()
+			}
+			deleted, err := DeleteFoundTraces(context.Background(), reader, "svc", start, end,
+				func(ctx context.Context, traceID model.TraceID) error {
+					return testCase.deleteErr
+				})
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expected, deleted)
+			reader.AssertExpectations(t)
+		})
+	}
+}
@@ -30,6 +30,9 @@ type Writer interface {
 var (
 	// ErrTraceNotFound is returned by Reader's GetTrace if no data is found for given trace ID.
 	ErrTraceNotFound = errors.New("trace not found")
+
+	// ErrNotSupported is returned when the storage backend does not support the requested operation.
+	ErrNotSupported = errors.New("operation not supported by the storage backend")
 )
 
 // Reader finds and loads traces and other data from storage.
@@ -41,6 +44,23 @@ type Reader interface {
 	FindTraceIDs(ctx context.Context, query *TraceQueryParameters) ([]model.TraceID, error)
 }
 
+// SpanFinder is an optional interface implemented by readers that can search for individual spans.
+// FindSpans returns the spans matching the query rather than the entire traces they belong to,
+// newest first; query.NumTraces limits the number of returned spans.
+type SpanFinder interface {
+	FindSpans(ctx context.Context, query *TraceQueryParameters) ([]*model.Span, error)
+}
+
+// Deleter is an optional interface implemented by the storage backends that can delete traces, e.g. to
+// honour data-subject requests. The index entries of the deleted spans are deleted along with them.
+type Deleter interface {
+	// DeleteTrace deletes the spans of the trace. Deleting a trace that is not stored is not an error.
+	DeleteTrace(ctx context.Context, traceID model.TraceID) error
+	// DeleteServiceTraces deletes the traces with a span of the service started within the time range,
+	// and returns their IDs.
+	DeleteServiceTraces(ctx context.Context, service string, startTimeMin, startTimeMax time.Time) ([]model.TraceID, error)
+}
+
 // TraceQueryParameters contains parameters of a trace query.
 type TraceQueryParameters struct {
 	ServiceName   string
@@ -51,4 +71,16 @@ type TraceQueryParameters struct {
 	DurationMin   time.Duration
 	DurationMax   time.Duration
 	NumTraces     int
+	// TagFilters are evaluated in addition to the exact matches in Tags, a span must satisfy all of them.
+	TagFilters []TagFilter
+	// ContinuationToken, if set, selects the page of results following the pages it was issued for.
+	ContinuationToken *ContinuationToken
+}
+
+// Offset returns the number of matching traces that were returned by the previous pages.
+func (p *TraceQueryParameters) Offset() int {
+	if p.ContinuationToken == nil {
+		return 0
+	}
+	return p.ContinuationToken.Offset
 }
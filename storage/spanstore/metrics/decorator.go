@@ -29,6 +29,7 @@ type ReadMetricsDecorator struct {
 	spanReader           spanstore.Reader
 	findTracesMetrics    *queryMetrics
 	findTraceIDsMetrics  *queryMetrics
+	findSpansMetrics     *queryMetrics
 	getTraceMetrics      *queryMetrics
 	getServicesMetrics   *queryMetrics
 	getOperationsMetrics *queryMetrics
@@ -59,6 +60,7 @@ func NewReadMetricsDecorator(spanReader spanstore.Reader, metricsFactory metrics
 		spanReader:           spanReader,
 		findTracesMetrics:    buildQueryMetrics("find_traces", metricsFactory),
 		findTraceIDsMetrics:  buildQueryMetrics("find_trace_ids", metricsFactory),
+		findSpansMetrics:     buildQueryMetrics("find_spans", metricsFactory),
 		getTraceMetrics:      buildQueryMetrics("get_trace", metricsFactory),
 		getServicesMetrics:   buildQueryMetrics("get_services", metricsFactory),
 		getOperationsMetrics: buildQueryMetrics("get_operations", metricsFactory),
@@ -88,6 +90,19 @@ func (m *ReadMetricsDecorator) FindTraceIDs(ctx context.Context, traceQuery *spa
 	return retMe, err
 }
 
+// FindSpans implements spanstore.SpanFinder#FindSpans. It returns spanstore.ErrNotSupported
+// if the underlying reader does not implement spanstore.SpanFinder.
+func (m *ReadMetricsDecorator) FindSpans(ctx context.Context, traceQuery *spanstore.TraceQueryParameters) ([]*model.Span, error) {
+	spanFinder, ok := m.spanReader.(spanstore.SpanFinder)
+	if !ok {
+		return nil, spanstore.ErrNotSupported
+	}
+	start := time.Now()
+	retMe, err := spanFinder.FindSpans(ctx, traceQuery)
+	m.findSpansMetrics.emit(err, time.Since(start), len(retMe))
+	return retMe, err
+}
+
 // GetTrace implements spanstore.Reader#GetTrace
 func (m *ReadMetricsDecorator) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
 	start := time.Now()
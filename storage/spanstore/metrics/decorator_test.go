@@ -126,3 +126,46 @@ func TestFailingUnderlyingCalls(t *testing.T) {
 
 	checkExpectedExistingAndNonExistentCounters(t, counters, expecteds, gauges, existingKeys, nonExistentKeys)
 }
+
+type spanFinderReader struct {
+	*mocks.Reader
+	*mocks.SpanFinder
+}
+
+func TestFindSpans(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+
+	mockFinder := &mocks.SpanFinder{}
+	mrs := NewReadMetricsDecorator(spanFinderReader{Reader: &mocks.Reader{}, SpanFinder: mockFinder}, mf)
+	query := &spanstore.TraceQueryParameters{ServiceName: "service"}
+	mockFinder.On("FindSpans", context.Background(), query).Return([]*model.Span{{}, {}}, nil).Once()
+	spans, err := mrs.FindSpans(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Len(t, spans, 2)
+	mockFinder.On("FindSpans", context.Background(), query).Return(nil, errors.New("Failure")).Once()
+	_, err = mrs.FindSpans(context.Background(), query)
+	assert.EqualError(t, err, "Failure")
+
+	counters, gauges := mf.Snapshot()
+	expecteds := map[string]int64{
+		"requests|operation=find_spans|result=ok":  1,
+		"requests|operation=find_spans|result=err": 1,
+	}
+	existingKeys := []string{
+		"latency|operation=find_spans|result=ok.P50",
+		"latency|operation=find_spans|result=err.P50",
+		"responses|operation=find_spans.P50",
+	}
+	checkExpectedExistingAndNonExistentCounters(t, counters, expecteds, gauges, existingKeys, nil)
+}
+
+func TestFindSpansNotSupported(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+
+	mrs := NewReadMetricsDecorator(&mocks.Reader{}, mf)
+	_, err := mrs.FindSpans(context.Background(), &spanstore.TraceQueryParameters{})
+	assert.Equal(t, spanstore.ErrNotSupported, err)
+
+	counters, _ := mf.Snapshot()
+	assert.EqualValues(t, 0, counters["requests|operation=find_spans|result=err"])
+}
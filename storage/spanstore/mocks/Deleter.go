@@ -0,0 +1,65 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mocks
+
+import context "context"
+import mock "github.com/stretchr/testify/mock"
+import model "github.com/jaegertracing/jaeger/model"
+import spanstore "github.com/jaegertracing/jaeger/storage/spanstore"
+import time "time"
+
+// Deleter is an autogenerated mock type for the Deleter type
+type Deleter struct {
+	mock.Mock
+}
+
+// DeleteServiceTraces provides a mock function with given fields: ctx, service, startTimeMin, startTimeMax
+func (_m *Deleter) DeleteServiceTraces(ctx context.Context, service string, startTimeMin time.Time, startTimeMax time.Time) ([]model.TraceID, error) {
+	ret := _m.Called(ctx, service, startTimeMin, startTimeMax)
+
+	var r0 []model.TraceID
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) []model.TraceID); ok {
+		r0 = rf(ctx, service, startTimeMin, startTimeMax)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.TraceID)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, service, startTimeMin, startTimeMax)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteTrace provides a mock function with given fields: ctx, traceID
+func (_m *Deleter) DeleteTrace(ctx context.Context, traceID model.TraceID) error {
+	ret := _m.Called(ctx, traceID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.TraceID) error); ok {
+		r0 = rf(ctx, traceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+var _ spanstore.Deleter = (*Deleter)(nil)
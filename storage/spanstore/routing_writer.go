@@ -0,0 +1,175 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/uber/jaeger-lib/metrics"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/multierror"
+)
+
+// Route selects the writers receiving a span. A span matches a route when it matches all the conditions
+// of the route, a route without conditions matches all the spans.
+type Route struct {
+	// Name identifies the route in the metrics
+	Name string
+	// Services matches the spans of any of these services
+	Services []string
+	// Tags matches the spans satisfying all the tag filters, see FlattenTags
+	Tags []TagFilter
+	// Debug matches the spans with or without the debug flag
+	Debug *bool
+	// Sampled matches the spans with or without the sampled flag
+	Sampled *bool
+	// Writers are the names of the writers receiving the spans matching the route
+	Writers []string
+}
+
+type routingWriterMetrics struct {
+	// SpansUnrouted counts the spans that did not match any route and were not written
+	SpansUnrouted metrics.Counter `metric:"spans_unrouted"`
+}
+
+type route struct {
+	services map[string]struct{}
+	tags     []TagMatcher
+	debug    *bool
+	sampled  *bool
+	writers  []int
+	spans    metrics.Counter
+}
+
+// RoutingWriter is a span Writer that saves each span into the writers of all the routes matched by the span.
+type RoutingWriter struct {
+	routes  []route
+	writers []Writer
+	metrics routingWriterMetrics
+}
+
+// NewRoutingWriter creates a RoutingWriter. The writers of the routes are looked up by name in the writers map.
+// The metrics are emitted under the routing_writer namespace, with a spans counter per route.
+func NewRoutingWriter(routes []Route, writers map[string]Writer, metricsFactory metrics.Factory) (*RoutingWriter, error) {
+	metricsFactory = metricsFactory.Namespace(metrics.NSOptions{Name: "routing_writer"})
+	w := &RoutingWriter{}
+	metrics.Init(&w.metrics, metricsFactory, nil)
+	writerIndexes := make(map[string]int)
+	routeNames := make(map[string]struct{})
+	for _, r := range routes {
+		if r.Name == "" {
+			return nil, errors.New("route name must not be empty")
+		}
+		if _, ok := routeNames[r.Name]; ok {
+			return nil, fmt.Errorf("duplicate route %s", r.Name)
+		}
+		routeNames[r.Name] = struct{}{}
+		if len(r.Writers) == 0 {
+			return nil, fmt.Errorf("route %s has no writers", r.Name)
+		}
+		tags, err := NewTagMatchers(r.Tags)
+		if err != nil {
+			return nil, errors.Wrapf(err, "route %s", r.Name)
+		}
+		compiled := route{
+			tags:    tags,
+			debug:   r.Debug,
+			sampled: r.Sampled,
+			spans:   metricsFactory.Counter(metrics.Options{Name: "spans", Tags: map[string]string{"route": r.Name}}),
+		}
+		if len(r.Services) > 0 {
+			compiled.services = make(map[string]struct{}, len(r.Services))
+			for _, service := range r.Services {
+				compiled.services[service] = struct{}{}
+			}
+		}
+		for _, name := range r.Writers {
+			index, ok := writerIndexes[name]
+			if !ok {
+				writer, ok := writers[name]
+				if !ok {
+					return nil, fmt.Errorf("route %s: unknown writer %s", r.Name, name)
+				}
+				index = len(w.writers)
+				writerIndexes[name] = index
+				w.writers = append(w.writers, writer)
+			}
+			compiled.writers = append(compiled.writers, index)
+		}
+		w.routes = append(w.routes, compiled)
+	}
+	return w, nil
+}
+
+// WriteSpan calls WriteSpan once on each writer of the routes matched by the span.
+// It will sum up failures, it is not transactional.
+func (w *RoutingWriter) WriteSpan(span *model.Span) error {
+	selected := make([]bool, len(w.writers))
+	routed := false
+	var tags model.KeyValues
+	for i := range w.routes {
+		r := &w.routes[i]
+		if len(r.tags) > 0 && tags == nil {
+			tags = FlattenTags(span)
+		}
+		if !r.matches(span, tags) {
+			continue
+		}
+		r.spans.Inc(1)
+		routed = true
+		for _, index := range r.writers {
+			selected[index] = true
+		}
+	}
+	if !routed {
+		w.metrics.SpansUnrouted.Inc(1)
+		return nil
+	}
+	var errs []error
+	for index, writer := range w.writers {
+		if !selected[index] {
+			continue
+		}
+		if err := writer.WriteSpan(span); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return multierror.Wrap(errs)
+}
+
+func (r *route) matches(span *model.Span, tags model.KeyValues) bool {
+	if r.services != nil {
+		if span.Process == nil {
+			return false
+		}
+		if _, ok := r.services[span.Process.ServiceName]; !ok {
+			return false
+		}
+	}
+	if r.debug != nil && span.Flags.IsDebug() != *r.debug {
+		return false
+	}
+	if r.sampled != nil && span.Flags.IsSampled() != *r.sampled {
+		return false
+	}
+	for _, matcher := range r.tags {
+		if !matcher(tags) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+
+	"github.com/jaegertracing/jaeger/model"
+	. "github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+type recordingWriteSpanStore struct {
+	spans []*model.Span
+}
+
+func (r *recordingWriteSpanStore) WriteSpan(span *model.Span) error {
+	r.spans = append(r.spans, span)
+	return nil
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestRoutingWriter(t *testing.T) {
+	badger, kafka, es := &recordingWriteSpanStore{}, &recordingWriteSpanStore{}, &recordingWriteSpanStore{}
+	metricsFactory := metricstest.NewFactory(0)
+	w, err := NewRoutingWriter(
+		[]Route{
+			{Name: "debug", Debug: boolPtr(true), Writers: []string{"badger"}},
+			{Name: "all", Writers: []string{"kafka"}},
+			{Name: "prod", Tags: []TagFilter{{Key: "env", Operator: TagFilterEqual, Value: "prod"}}, Writers: []string{"elasticsearch", "kafka"}},
+		},
+		map[string]Writer{"badger": badger, "kafka": kafka, "elasticsearch": es},
+		metricsFactory,
+	)
+	require.NoError(t, err)
+
+	debugSpan := &model.Span{Process: model.NewProcess("frontend", nil)}
+	debugSpan.Flags.SetDebug()
+	prodSpan := &model.Span{Process: model.NewProcess("frontend", []model.KeyValue{model.String("env", "prod")})}
+	devSpan := &model.Span{Tags: []model.KeyValue{model.String("env", "dev")}}
+	for _, span := range []*model.Span{debugSpan, prodSpan, devSpan} {
+		require.NoError(t, w.WriteSpan(span))
+	}
+
+	assert.Equal(t, []*model.Span{debugSpan}, badger.spans)
+	// kafka receives the prod span only once, although it matches two routes writing to kafka
+	assert.Equal(t, []*model.Span{debugSpan, prodSpan, devSpan}, kafka.spans)
+	assert.Equal(t, []*model.Span{prodSpan}, es.spans)
+	metricsFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "routing_writer.spans", Tags: map[string]string{"route": "debug"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "routing_writer.spans", Tags: map[string]string{"route": "all"}, Value: 3},
+		metricstest.ExpectedMetric{Name: "routing_writer.spans", Tags: map[string]string{"route": "prod"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "routing_writer.spans_unrouted", Value: 0},
+	)
+}
+
+func TestRoutingWriterConditions(t *testing.T) {
+	frontendProd := &model.Span{
+		Flags:   model.SampledFlag,
+		Tags:    []model.KeyValue{model.String("env", "prod")},
+		Process: model.NewProcess("frontend", nil),
+	}
+	tests := []struct {
+		route   Route
+		matches bool
+	}{
+		{route: Route{}, matches: true},
+		{route: Route{Services: []string{"backend", "frontend"}}, matches: true},
+		{route: Route{Services: []string{"backend"}}, matches: false},
+		{route: Route{Debug: boolPtr(false)}, matches: true},
+		{route: Route{Debug: boolPtr(true)}, matches: false},
+		{route: Route{Sampled: boolPtr(true)}, matches: true},
+		{route: Route{Sampled: boolPtr(false)}, matches: false},
+		{route: Route{Tags: []TagFilter{{Key: "env", Operator: TagFilterEqual, Value: "prod"}}}, matches: true},
+		{
+			route: Route{
+				Services: []string{"frontend"},
+				Tags: []TagFilter{
+					{Key: "env", Operator: TagFilterEqual, Value: "prod"},
+					{Key: "error", Operator: TagFilterExists},
+				},
+			},
+			matches: false,
+		},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			writer := &recordingWriteSpanStore{}
+			metricsFactory := metricstest.NewFactory(0)
+			test.route.Name = "route"
+			test.route.Writers = []string{"writer"}
+			w, err := NewRoutingWriter([]Route{test.route}, map[string]Writer{"writer": writer}, metricsFactory)
+			require.NoError(t, err)
+			require.NoError(t, w.WriteSpan(frontendProd))
+			assert.Equal(t, test.matches, len(writer.spans) == 1)
+			unrouted := 1
+			if test.matches {
+				unrouted = 0
+			}
+			metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "routing_writer.spans_unrouted", Value: unrouted})
+		})
+	}
+}
+
+func TestRoutingWriterErrors(t *testing.T) {
+	w, err := NewRoutingWriter(
+		[]Route{{Name: "all", Writers: []string{"a", "b", "c"}}},
+		map[string]Writer{"a": &errProneWriteSpanStore{}, "b": &noopWriteSpanStore{}, "c": &errProneWriteSpanStore{}},
+		metrics.NullFactory,
+	)
+	require.NoError(t, err)
+	assert.EqualError(t, w.WriteSpan(&model.Span{}), fmt.Sprintf("[%s, %s]", errIWillAlwaysFail, errIWillAlwaysFail))
+}
+
+func TestNewRoutingWriterErrors(t *testing.T) {
+	writers := map[string]Writer{"kafka": &noopWriteSpanStore{}}
+	tests := []struct {
+		routes []Route
+		err    string
+	}{
+		{routes: []Route{{Writers: []string{"kafka"}}}, err: "route name must not be empty"},
+		{
+			routes: []Route{{Name: "a", Writers: []string{"kafka"}}, {Name: "a", Writers: []string{"kafka"}}},
+			err:    "duplicate route a",
+		},
+		{routes: []Route{{Name: "a"}}, err: "route a has no writers"},
+		{routes: []Route{{Name: "a", Writers: []string{"cassandra"}}}, err: "route a: unknown writer cassandra"},
+		{
+			routes: []Route{{Name: "a", Tags: []TagFilter{{Key: "env"}}, Writers: []string{"kafka"}}},
+			err:    `route a: unknown operator "": invalid tag filter`,
+		},
+	}
+	for _, test := range tests {
+		_, err := NewRoutingWriter(test.routes, writers, metrics.NullFactory)
+		assert.EqualError(t, err, test.err)
+	}
+}
@@ -12,11 +12,10 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package integration
+package spanstoretest
 
 import (
 	"encoding/json"
-	"testing"
 
 	"github.com/kr/pretty"
 	"github.com/stretchr/testify/assert"
@@ -25,7 +24,8 @@ import (
 	"github.com/jaegertracing/jaeger/model"
 )
 
-func CompareSliceOfTraces(t *testing.T, expected []*model.Trace, actual []*model.Trace) {
+// CompareSliceOfTraces fails the test if the traces are not the same, regardless of their order.
+func CompareSliceOfTraces(t TestingT, expected []*model.Trace, actual []*model.Trace) {
 	require.Equal(t, len(expected), len(actual), "Unequal number of expected vs. actual traces")
 	model.SortTraces(expected)
 	model.SortTraces(actual)
@@ -46,7 +46,8 @@ func CompareSliceOfTraces(t *testing.T, expected []*model.Trace, actual []*model
 	}
 }
 
-func CompareTraces(t *testing.T, expected *model.Trace, actual *model.Trace) {
+// CompareTraces fails the test if the traces are not the same, regardless of the order of their spans.
+func CompareTraces(t TestingT, expected *model.Trace, actual *model.Trace) {
 	if expected.Spans == nil {
 		require.Nil(t, actual.Spans)
 		return
@@ -68,7 +69,7 @@ func CompareTraces(t *testing.T, expected *model.Trace, actual *model.Trace) {
 	}
 }
 
-func checkSize(t *testing.T, expected *model.Trace, actual *model.Trace) {
+func checkSize(t TestingT, expected *model.Trace, actual *model.Trace) {
 	require.True(t, len(expected.Spans) == len(actual.Spans))
 	for i := range expected.Spans {
 		expectedSpan := expected.Spans[i]
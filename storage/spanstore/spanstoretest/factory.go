@@ -0,0 +1,146 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstoretest
+
+import (
+	"io"
+
+	"github.com/jaegertracing/jaeger/plugin/storage/grpc/shared"
+	"github.com/jaegertracing/jaeger/storage"
+)
+
+// NewFactorySuite creates a Suite for the components of the factories returned by newFactory,
+// which must return initialized factories. The Suite cleans the storage up by replacing the factory
+// with a new one, after closing the previous one if it implements io.Closer, so newFactory must return
+// a factory backed by an empty storage.
+//
+// The optional components, i.e. the dependency writer, the archive span reader and writer, the sampling store
+// and the lock, are created if the factory implements the matching interface of the storage package.
+func NewFactorySuite(newFactory func() (storage.Factory, error)) (*Suite, error) {
+	s := &Suite{
+		Refresh: func() error { return nil },
+	}
+	var current storage.Factory
+	s.CleanUp = func() error {
+		if err := closeIfCloser(current); err != nil {
+			return err
+		}
+		factory, err := newFactory()
+		if err != nil {
+			return err
+		}
+		current = factory
+		return s.initFromFactory(factory)
+	}
+	if err := s.CleanUp(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Suite) initFromFactory(f storage.Factory) error {
+	var err error
+	if s.SpanWriter, err = f.CreateSpanWriter(); err != nil {
+		return err
+	}
+	if s.SpanReader, err = f.CreateSpanReader(); err != nil {
+		return err
+	}
+	if s.DependencyReader, err = f.CreateDependencyReader(); err != nil {
+		return err
+	}
+	s.DependencyWriter, s.ArchiveSpanWriter, s.ArchiveSpanReader, s.SamplingStore, s.Lock = nil, nil, nil, nil, nil
+	if dwf, ok := f.(storage.DependencyWriterFactory); ok {
+		s.DependencyWriter, err = dwf.CreateDependencyWriter()
+		if err != nil && err != storage.ErrDependencyWriterNotSupported {
+			return err
+		}
+	}
+	if af, ok := f.(storage.ArchiveFactory); ok {
+		if err := s.initArchive(af); err != nil {
+			return err
+		}
+	}
+	if ssf, ok := f.(storage.SamplingStoreFactory); ok {
+		if err := s.initSampling(ssf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Suite) initArchive(f storage.ArchiveFactory) error {
+	writer, err := f.CreateArchiveSpanWriter()
+	if err == storage.ErrArchiveStorageNotConfigured || err == storage.ErrArchiveStorageNotSupported {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	reader, err := f.CreateArchiveSpanReader()
+	if err != nil {
+		return err
+	}
+	s.ArchiveSpanWriter, s.ArchiveSpanReader = writer, reader
+	return nil
+}
+
+func (s *Suite) initSampling(f storage.SamplingStoreFactory) error {
+	store, err := f.CreateSamplingStore()
+	if err == storage.ErrSamplingStorageNotSupported {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	lock, err := f.CreateLock()
+	if err != nil {
+		return err
+	}
+	s.SamplingStore, s.Lock = store, lock
+	return nil
+}
+
+// NewPluginSuite creates a Suite for the components of the storage plugins returned by newPlugin,
+// the way NewFactorySuite does for factories.
+func NewPluginSuite(newPlugin func() (shared.StoragePlugin, error)) (*Suite, error) {
+	s := &Suite{
+		Refresh: func() error { return nil },
+	}
+	var current shared.StoragePlugin
+	s.CleanUp = func() error {
+		if err := closeIfCloser(current); err != nil {
+			return err
+		}
+		plugin, err := newPlugin()
+		if err != nil {
+			return err
+		}
+		current = plugin
+		s.SpanWriter, s.SpanReader, s.DependencyReader = plugin.SpanWriter(), plugin.SpanReader(), plugin.DependencyReader()
+		return nil
+	}
+	if err := s.CleanUp(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func closeIfCloser(component interface{}) error {
+	if closer, ok := component.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
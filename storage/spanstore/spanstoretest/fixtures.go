@@ -0,0 +1,90 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstoretest
+
+//go:generate esc -pkg spanstoretest -o gen_assets.go -prefix fixtures fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// QueryFixtures and TraceFixtures are under ./fixtures/queries.json and ./fixtures/traces/*.json respectively,
+// and are embedded in gen_assets.go so that the Suite can run from any directory.
+// Each query fixture includes:
+//
+//	Caption: describes the query we are testing
+//	Query: the query we are testing
+//	ExpectedFixture: the trace fixture that we want back from these queries.
+//
+// Queries are not necessarily numbered, but since each query requires a service name,
+// the service name is formatted "query##-service".
+type QueryFixtures struct {
+	Caption          string
+	Query            *spanstore.TraceQueryParameters
+	ExpectedFixtures []string
+}
+
+func getTraceFixture(t TestingT, fixture string) *model.Trace {
+	fileName := fmt.Sprintf("/traces/%s.json", fixture)
+	return getTraceFixtureExact(t, fileName)
+}
+
+func getTraceFixtureExact(t TestingT, fileName string) *model.Trace {
+	var trace model.Trace
+	loadAndParseJSONPB(t, fileName, &trace)
+	return &trace
+}
+
+func loadAndParseJSONPB(t TestingT, path string, object proto.Message) {
+	inStr, err := FSByte(false, path)
+	require.NoError(t, err, "Not expecting error when loading fixture %s", path)
+	err = jsonpb.Unmarshal(bytes.NewReader(correctTime(inStr)), object)
+	require.NoError(t, err, "Not expecting error when unmarshaling fixture %s", path)
+}
+
+func loadAndParseQueryTestCases(t TestingT) []*QueryFixtures {
+	var queries []*QueryFixtures
+	loadAndParseJSON(t, "/queries.json", &queries)
+	return queries
+}
+
+func loadAndParseJSON(t TestingT, path string, object interface{}) {
+	inStr, err := FSByte(false, path)
+	require.NoError(t, err, "Not expecting error when loading fixture %s", path)
+	err = json.Unmarshal(correctTime(inStr), object)
+	require.NoError(t, err, "Not expecting error when unmarshaling fixture %s", path)
+}
+
+// required, because we want to only query on recent traces, so we replace all the dates with recent dates.
+func correctTime(json []byte) []byte {
+	jsonString := string(json)
+	now := time.Now().UTC()
+	today := now.Format("2006-01-02")
+	yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
+	retString := strings.Replace(jsonString, "2017-01-26", today, -1)
+	retString = strings.Replace(retString, "2017-01-25", yesterday, -1)
+	return []byte(retString)
+}
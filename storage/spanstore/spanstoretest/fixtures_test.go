@@ -0,0 +1,52 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstoretest
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAllFixtures(t *testing.T) {
+	dir, err := FS(false).Open("/traces")
+	require.NoError(t, err)
+	files, err := dir.Readdir(0)
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		t.Logf("Parsing %s", file.Name())
+		getTraceFixtureExact(t, path.Join("/traces", file.Name()))
+	}
+	assert.NotEmpty(t, loadAndParseQueryTestCases(t))
+}
+
+// TestEmbeddedFixtures checks that gen_assets.go was regenerated after the fixtures changed.
+func TestEmbeddedFixtures(t *testing.T) {
+	for name, file := range _escData {
+		if file.isDir {
+			continue
+		}
+		local, err := ioutil.ReadFile(file.local)
+		require.NoError(t, err)
+		embedded, err := FSByte(false, name)
+		require.NoError(t, err)
+		assert.True(t, bytes.Equal(local, embedded), "%s differs from the embedded fixture, run go generate", file.local)
+	}
+}
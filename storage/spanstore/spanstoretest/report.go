@@ -0,0 +1,83 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstoretest
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Report is the compatibility report of a storage, it lists the result of each test and sub-test run by the Suite.
+type Report struct {
+	Results []Result
+}
+
+// Count returns the number of tests and sub-tests with the given status.
+func (r Report) Count(status Status) int {
+	var count int
+	for _, result := range r.Results {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Compatible returns true if no test failed.
+func (r Report) Compatible() bool {
+	return r.Count(StatusFailed) == 0
+}
+
+// WriteTo writes the report to w, one test per line followed by the messages of the tests that failed or were skipped.
+func (r Report) WriteTo(w io.Writer) (int64, error) {
+	width := 0
+	for _, result := range r.Results {
+		if len(result.Name) > width {
+			width = len(result.Name)
+		}
+	}
+	cw := &countingWriter{w: w}
+	for _, result := range r.Results {
+		fmt.Fprintf(cw, "%s  %-*s  %s\n", result.Status, width, result.Name, result.Duration.Round(time.Millisecond))
+		if result.Status == StatusPassed {
+			continue
+		}
+		for _, message := range result.Messages {
+			fmt.Fprintf(cw, "      %s\n", strings.Replace(message, "\n", "\n      ", -1))
+		}
+	}
+	fmt.Fprintf(cw, "\n%d passed, %d failed, %d skipped\n",
+		r.Count(StatusPassed), r.Count(StatusFailed), r.Count(StatusSkipped))
+	return cw.n, cw.err
+}
+
+// countingWriter counts the bytes written and keeps the first error, so that WriteTo can ignore the errors of each write.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	w.err = err
+	return n, err
+}
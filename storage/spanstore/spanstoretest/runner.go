@@ -0,0 +1,181 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstoretest
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestingT is the subset of *testing.T used to compare traces.
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+	Fail()
+	FailNow()
+	Logf(format string, args ...interface{})
+}
+
+// T is the subset of *testing.T used by the Suite, so that the Suite can also run outside of go test.
+type T interface {
+	TestingT
+	Skipf(format string, args ...interface{})
+	Run(name string, f func(t T)) bool
+}
+
+// testingT adapts *testing.T to T.
+type testingT struct {
+	*testing.T
+}
+
+// Run implements T
+func (t testingT) Run(name string, f func(t T)) bool {
+	return t.T.Run(name, func(t *testing.T) {
+		f(testingT{T: t})
+	})
+}
+
+// Status is the outcome of a test.
+type Status string
+
+const (
+	// StatusPassed is the status of a test that passed.
+	StatusPassed Status = "PASS"
+	// StatusFailed is the status of a test that failed.
+	StatusFailed Status = "FAIL"
+	// StatusSkipped is the status of a test that was skipped, usually because the storage does not provide the component it tests.
+	StatusSkipped Status = "SKIP"
+)
+
+// Result is the outcome of a test run by Suite.Report.
+type Result struct {
+	// Name is the name of the test, its sub-tests are named after it, e.g. "FindTraces/Tags in one spot - Tags".
+	Name     string
+	Status   Status
+	Duration time.Duration
+	// Messages are the errors and logs of the test.
+	Messages []string
+}
+
+// recorder implements T and records the results of the tests it runs.
+// As with testing.T, FailNow and Skipf stop the goroutine of the test.
+type recorder struct {
+	name    string
+	results *results
+
+	sync.Mutex
+	failed   bool
+	skipped  bool
+	messages []string
+}
+
+type results struct {
+	sync.Mutex
+	list []Result
+}
+
+func (r *recorder) log(format string, args ...interface{}) {
+	r.Lock()
+	defer r.Unlock()
+	r.messages = append(r.messages, strings.TrimSpace(fmt.Sprintf(format, args...)))
+}
+
+// Errorf implements T
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.log(format, args...)
+	r.Fail()
+}
+
+// Fail implements T
+func (r *recorder) Fail() {
+	r.Lock()
+	defer r.Unlock()
+	r.failed = true
+}
+
+// FailNow implements T
+func (r *recorder) FailNow() {
+	r.Fail()
+	runtime.Goexit()
+}
+
+// Logf implements T
+func (r *recorder) Logf(format string, args ...interface{}) {
+	r.log(format, args...)
+}
+
+// Skipf implements T
+func (r *recorder) Skipf(format string, args ...interface{}) {
+	r.log(format, args...)
+	r.Lock()
+	r.skipped = true
+	r.Unlock()
+	runtime.Goexit()
+}
+
+// Run implements T
+func (r *recorder) Run(name string, f func(t T)) bool {
+	if r.name != "" {
+		name = r.name + "/" + name
+	}
+	sub := &recorder{name: name, results: r.results}
+	passed := sub.run(f)
+	if !passed {
+		r.Fail()
+	}
+	return passed
+}
+
+// run runs the test in its own goroutine and records its result before the results of its sub-tests.
+func (r *recorder) run(f func(t T)) bool {
+	r.results.Lock()
+	index := len(r.results.list)
+	r.results.list = append(r.results.list, Result{Name: r.name})
+	r.results.Unlock()
+
+	start := time.Now()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() {
+			if p := recover(); p != nil {
+				r.Errorf("panic: %v", p)
+			}
+		}()
+		f(r)
+	}()
+	<-done
+
+	r.Lock()
+	defer r.Unlock()
+	status := StatusPassed
+	if r.failed {
+		status = StatusFailed
+	} else if r.skipped {
+		status = StatusSkipped
+	}
+	r.results.Lock()
+	defer r.results.Unlock()
+	r.results.list[index] = Result{
+		Name:     r.name,
+		Status:   status,
+		Duration: time.Since(start),
+		Messages: r.messages,
+	}
+	return !r.failed
+}
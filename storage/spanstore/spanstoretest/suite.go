@@ -0,0 +1,455 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package spanstoretest provides the conformance test suite of the storage backends. Both the storage
+// implementations of Jaeger and third-party storage plugins run it, the former from their integration
+// tests and the latter from their own tests or with the jaeger-storage-conformance command.
+package spanstoretest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	samplingModel "github.com/jaegertracing/jaeger/cmd/collector/app/sampling/model"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/distributedlock"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/samplingstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+const (
+	iterations = 30
+)
+
+// Suite runs the conformance tests against the storage components it is given.
+// The tests of the components left nil are skipped.
+type Suite struct {
+	SpanWriter        spanstore.Writer
+	SpanReader        spanstore.Reader
+	DependencyWriter  dependencystore.Writer
+	DependencyReader  dependencystore.Reader
+	ArchiveSpanWriter spanstore.Writer
+	ArchiveSpanReader spanstore.Reader
+	SamplingStore     samplingstore.Store
+	Lock              distributedlock.Lock
+
+	// CleanUp() should ensure that the storage backend is clean before another test.
+	// called either before or after each test, and should be idempotent
+	CleanUp func() error
+
+	// Refresh() should ensure that the storage backend is up to date before being queried.
+	// called between set-up and queries in each test
+	Refresh func() error
+}
+
+type test struct {
+	name string
+	run  func(s *Suite, t T)
+}
+
+var tests = []test{
+	{name: "GetServices", run: (*Suite).testGetServices},
+	{name: "GetOperations", run: (*Suite).testGetOperations},
+	{name: "GetTrace", run: (*Suite).testGetTrace},
+	{name: "GetLargeSpans", run: (*Suite).testGetLargeSpan},
+	{name: "FindTraces", run: (*Suite).testFindTraces},
+	{name: "GetDependencies", run: (*Suite).testGetDependencies},
+	{name: "ArchiveTrace", run: (*Suite).testArchiveTrace},
+	{name: "SamplingStore", run: (*Suite).testSamplingStore},
+	{name: "Lock", run: (*Suite).testLock},
+}
+
+// TestNames returns the names of the tests of the Suite, in the order they run.
+func TestNames() []string {
+	names := make([]string, 0, len(tests))
+	for _, test := range tests {
+		names = append(names, test.name)
+	}
+	return names
+}
+
+// RunAll runs all the tests of the Suite as sub-tests of t.
+func (s *Suite) RunAll(t *testing.T) {
+	s.RunTests(t)
+}
+
+// RunTests runs the tests of the Suite with the given names as sub-tests of t, or all of them if no name is given.
+func (s *Suite) RunTests(t *testing.T, names ...string) {
+	s.run(testingT{T: t}, names)
+}
+
+// Report runs the tests of the Suite with the given names, or all of them if no name is given,
+// and returns their results. It does not need go test, and can check a storage plugin from a command.
+func (s *Suite) Report(names ...string) Report {
+	root := &recorder{results: &results{}}
+	s.run(root, names)
+	return Report{Results: root.results.list}
+}
+
+func (s *Suite) run(t T, names []string) {
+	if len(names) == 0 {
+		names = TestNames()
+	}
+	for _, name := range names {
+		test, ok := findTest(name)
+		t.Run(name, func(t T) {
+			if !ok {
+				t.Errorf("Unknown test %s, the tests are %v", name, TestNames())
+				return
+			}
+			test.run(s, t)
+		})
+	}
+}
+
+func findTest(name string) (test, bool) {
+	for _, test := range tests {
+		if test.name == name {
+			return test, true
+		}
+	}
+	return test{}, false
+}
+
+func (s *Suite) cleanUp(t T) {
+	require.NotNil(t, s.CleanUp, "CleanUp function must be provided")
+	require.NoError(t, s.CleanUp())
+}
+
+func (s *Suite) refresh(t T) {
+	require.NotNil(t, s.Refresh, "Refresh function must be provided")
+	require.NoError(t, s.Refresh())
+}
+
+func (s *Suite) waitForCondition(t T, predicate func(t T) bool) bool {
+	for i := 0; i < iterations; i++ {
+		t.Logf("Waiting for storage backend to update documents, iteration %d out of %d", i+1, iterations)
+		if predicate(t) {
+			return true
+		}
+		time.Sleep(100 * time.Millisecond) // Will wait up to 3 seconds at worst.
+	}
+	return predicate(t)
+}
+
+// === SpanStore Tests ===
+
+func (s *Suite) testGetServices(t T) {
+	defer s.cleanUp(t)
+
+	expected := []string{"example-service-1", "example-service-2", "example-service-3"}
+	s.loadParseAndWriteExampleTrace(t)
+	s.refresh(t)
+
+	var actual []string
+	found := s.waitForCondition(t, func(t T) bool {
+		var err error
+		actual, err = s.SpanReader.GetServices(context.Background())
+		require.NoError(t, err)
+		return assert.ObjectsAreEqualValues(expected, actual)
+	})
+
+	if !assert.True(t, found) {
+		t.Logf("\t Expected: %v", expected)
+		t.Logf("\t Actual  : %v", actual)
+	}
+}
+
+func (s *Suite) testGetLargeSpan(t T) {
+	defer s.cleanUp(t)
+
+	t.Logf("Testing Large Trace over 10K ...")
+	expected := s.loadParseAndWriteLargeTrace(t)
+	expectedTraceID := expected.Spans[0].TraceID
+	s.refresh(t)
+
+	var actual *model.Trace
+	found := s.waitForCondition(t, func(t T) bool {
+		var err error
+		actual, err = s.SpanReader.GetTrace(context.Background(), expectedTraceID)
+		return err == nil && actual != nil && len(actual.Spans) == len(expected.Spans)
+	})
+	if !assert.True(t, found) {
+		CompareTraces(t, expected, actual)
+	}
+}
+
+func (s *Suite) testGetOperations(t T) {
+	defer s.cleanUp(t)
+
+	expected := []string{"example-operation-1", "example-operation-3", "example-operation-4"}
+	s.loadParseAndWriteExampleTrace(t)
+	s.refresh(t)
+
+	var actual []string
+	found := s.waitForCondition(t, func(t T) bool {
+		var err error
+		actual, err = s.SpanReader.GetOperations(context.Background(), "example-service-1")
+		require.NoError(t, err)
+		return assert.ObjectsAreEqualValues(expected, actual)
+	})
+
+	if !assert.True(t, found) {
+		t.Logf("\t Expected: %v", expected)
+		t.Logf("\t Actual  : %v", actual)
+	}
+}
+
+func (s *Suite) testGetTrace(t T) {
+	defer s.cleanUp(t)
+
+	expected := s.loadParseAndWriteExampleTrace(t)
+	expectedTraceID := expected.Spans[0].TraceID
+	s.refresh(t)
+
+	var actual *model.Trace
+	found := s.waitForCondition(t, func(t T) bool {
+		var err error
+		actual, err = s.SpanReader.GetTrace(context.Background(), expectedTraceID)
+		if err != nil {
+			t.Logf("%v", err)
+		}
+		return err == nil && actual != nil && len(actual.Spans) == len(expected.Spans)
+	})
+	if !assert.True(t, found) {
+		CompareTraces(t, expected, actual)
+	}
+}
+
+func (s *Suite) testFindTraces(t T) {
+	defer s.cleanUp(t)
+
+	// Note: all cases include ServiceName + StartTime range
+	queryTestCases := loadAndParseQueryTestCases(t)
+
+	// Each query test case only specifies matching traces, but does not provide counterexamples.
+	// To improve coverage we get all possible traces and store all of them before running queries.
+	allTraceFixtures := make(map[string]*model.Trace)
+	expectedTracesPerTestCase := make([][]*model.Trace, 0, len(queryTestCases))
+	for _, queryTestCase := range queryTestCases {
+		var expected []*model.Trace
+		for _, traceFixture := range queryTestCase.ExpectedFixtures {
+			trace, ok := allTraceFixtures[traceFixture]
+			if !ok {
+				trace = getTraceFixture(t, traceFixture)
+				err := writeTrace(s.SpanWriter, trace)
+				require.NoError(t, err, "Unexpected error when writing trace %s to storage", traceFixture)
+				allTraceFixtures[traceFixture] = trace
+			}
+			expected = append(expected, trace)
+		}
+		expectedTracesPerTestCase = append(expectedTracesPerTestCase, expected)
+	}
+	s.refresh(t)
+	for i, queryTestCase := range queryTestCases {
+		t.Run(queryTestCase.Caption, func(t T) {
+			expected := expectedTracesPerTestCase[i]
+			actual := s.findTracesByQuery(t, queryTestCase.Query, expected)
+			CompareSliceOfTraces(t, expected, actual)
+		})
+	}
+}
+
+func (s *Suite) findTracesByQuery(t T, query *spanstore.TraceQueryParameters, expected []*model.Trace) []*model.Trace {
+	var traces []*model.Trace
+	found := s.waitForCondition(t, func(t T) bool {
+		var err error
+		traces, err = s.SpanReader.FindTraces(context.Background(), query)
+		if err == nil && tracesMatch(t, traces, expected) {
+			return true
+		}
+		t.Logf("FindTraces: expected: %d, actual: %d, match: false", len(expected), len(traces))
+		return false
+	})
+	require.True(t, found)
+	return traces
+}
+
+func writeTrace(writer spanstore.Writer, trace *model.Trace) error {
+	for _, span := range trace.Spans {
+		if err := writer.WriteSpan(span); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Suite) loadParseAndWriteExampleTrace(t T) *model.Trace {
+	trace := getTraceFixture(t, "example_trace")
+	err := writeTrace(s.SpanWriter, trace)
+	require.NoError(t, err, "Not expecting error when writing example_trace to storage")
+	return trace
+}
+
+func (s *Suite) loadParseAndWriteLargeTrace(t T) *model.Trace {
+	trace := getTraceFixture(t, "example_trace")
+	span := trace.Spans[0]
+	spns := make([]*model.Span, 1, 10008)
+	trace.Spans = spns
+	trace.Spans[0] = span
+	for i := 1; i < 10008; i++ {
+		s := new(model.Span)
+		*s = *span
+		s.StartTime = s.StartTime.Add(time.Second * time.Duration(i+1))
+		trace.Spans = append(trace.Spans, s)
+	}
+	err := writeTrace(s.SpanWriter, trace)
+	require.NoError(t, err, "Not expecting error when writing example_trace to storage")
+	return trace
+}
+
+func tracesMatch(t T, actual []*model.Trace, expected []*model.Trace) bool {
+	if !assert.Equal(t, len(expected), len(actual), "Expecting certain number of traces") {
+		return false
+	}
+	return assert.Equal(t, spanCount(expected), spanCount(actual), "Expecting certain number of spans")
+}
+
+func spanCount(traces []*model.Trace) int {
+	var count int
+	for _, trace := range traces {
+		count += len(trace.Spans)
+	}
+	return count
+}
+
+// === DependencyStore Tests ===
+
+func (s *Suite) testGetDependencies(t T) {
+	if s.DependencyReader == nil || s.DependencyWriter == nil {
+		t.Skipf("Skipping GetDependencies test because dependency reader or writer is nil")
+		return
+	}
+
+	defer s.cleanUp(t)
+
+	expected := []model.DependencyLink{
+		{
+			Parent:    "hello",
+			Child:     "world",
+			CallCount: uint64(1),
+		},
+		{
+			Parent:    "world",
+			Child:     "hello",
+			CallCount: uint64(3),
+		},
+	}
+	require.NoError(t, s.DependencyWriter.WriteDependencies(time.Now(), expected))
+	s.refresh(t)
+	actual, err := s.DependencyReader.GetDependencies(time.Now(), 5*time.Minute)
+	assert.NoError(t, err)
+	assert.EqualValues(t, expected, actual)
+}
+
+// === Archive Tests ===
+
+func (s *Suite) testArchiveTrace(t T) {
+	if s.ArchiveSpanReader == nil || s.ArchiveSpanWriter == nil {
+		t.Skipf("Skipping ArchiveTrace test because archive span reader or writer is nil")
+		return
+	}
+
+	defer s.cleanUp(t)
+	tID := model.NewTraceID(uint64(11), uint64(22))
+	// archived traces are read regardless of their age
+	expected := &model.Span{
+		OperationName: "archive_span",
+		StartTime:     time.Now().Add(-15 * 24 * time.Hour),
+		TraceID:       tID,
+		SpanID:        model.NewSpanID(55),
+		References:    []model.SpanRef{},
+		Process:       model.NewProcess("archived_service", model.KeyValues{}),
+	}
+
+	require.NoError(t, s.ArchiveSpanWriter.WriteSpan(expected))
+	s.refresh(t)
+
+	var actual *model.Trace
+	found := s.waitForCondition(t, func(t T) bool {
+		var err error
+		actual, err = s.ArchiveSpanReader.GetTrace(context.Background(), tID)
+		return err == nil && actual != nil && len(actual.Spans) == 1
+	})
+	if !assert.True(t, found) {
+		CompareTraces(t, &model.Trace{Spans: []*model.Span{expected}}, actual)
+	}
+}
+
+// === SamplingStore Tests ===
+
+func (s *Suite) testSamplingStore(t T) {
+	if s.SamplingStore == nil {
+		t.Skipf("Skipping SamplingStore test because sampling store is nil")
+		return
+	}
+
+	defer s.cleanUp(t)
+
+	start := time.Now().Add(-time.Minute)
+	throughput := []*samplingModel.Throughput{
+		{Service: "svc", Operation: "op", Count: 10, Probabilities: map[string]struct{}{"0.100000": {}}},
+	}
+	require.NoError(t, s.SamplingStore.InsertThroughput(throughput))
+	probabilities := samplingModel.ServiceOperationProbabilities{"svc": {"op": 0.1}}
+	qps := samplingModel.ServiceOperationQPS{"svc": {"op": 5}}
+	require.NoError(t, s.SamplingStore.InsertProbabilitiesAndQPS("host", probabilities, qps))
+	s.refresh(t)
+
+	actualThroughput, err := s.SamplingStore.GetThroughput(start, time.Now())
+	require.NoError(t, err)
+	assert.EqualValues(t, throughput, actualThroughput)
+
+	actualProbabilitiesAndQPS, err := s.SamplingStore.GetProbabilitiesAndQPS(start, time.Now())
+	require.NoError(t, err)
+	assert.EqualValues(t, map[string][]samplingModel.ServiceOperationData{
+		"host": {{"svc": {"op": {Probability: 0.1, QPS: 5}}}},
+	}, actualProbabilitiesAndQPS)
+
+	latest, err := s.SamplingStore.GetLatestProbabilities()
+	require.NoError(t, err)
+	assert.EqualValues(t, probabilities, latest)
+}
+
+// === Lock Tests ===
+
+func (s *Suite) testLock(t T) {
+	if s.Lock == nil {
+		t.Skipf("Skipping Lock test because lock is nil")
+		return
+	}
+
+	defer s.cleanUp(t)
+
+	const resource = "integration_test_lock"
+	acquired, err := s.Lock.Acquire(resource, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = s.Lock.Acquire(resource, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired, "the lease should be extended by its owner")
+
+	forfeited, err := s.Lock.Forfeit(resource)
+	require.NoError(t, err)
+	assert.True(t, forfeited)
+
+	_, err = s.Lock.Forfeit(resource)
+	assert.Error(t, err, "a released lease cannot be forfeited again")
+}
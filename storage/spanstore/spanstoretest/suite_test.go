@@ -0,0 +1,204 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstoretest
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/plugin/storage/grpc/shared"
+	"github.com/jaegertracing/jaeger/plugin/storage/memory"
+	"github.com/jaegertracing/jaeger/storage"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+type closingFactory struct {
+	*memory.Factory
+	closed *int
+}
+
+func (f closingFactory) Close() error {
+	*f.closed++
+	return nil
+}
+
+func TestFactorySuite(t *testing.T) {
+	var created, closed int
+	s, err := NewFactorySuite(func() (storage.Factory, error) {
+		created++
+		f := memory.NewFactory()
+		if err := f.Initialize(metrics.NullFactory, zap.NewNop()); err != nil {
+			return nil, err
+		}
+		return closingFactory{Factory: f, closed: &closed}, nil
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, s.SamplingStore)
+	assert.NotNil(t, s.Lock)
+	assert.Nil(t, s.DependencyWriter)
+	assert.Nil(t, s.ArchiveSpanWriter)
+
+	s.RunAll(t)
+	assert.True(t, created > 1)
+	assert.Equal(t, created-1, closed)
+}
+
+func TestFactorySuiteError(t *testing.T) {
+	_, err := NewFactorySuite(func() (storage.Factory, error) {
+		return nil, errors.New("no storage")
+	})
+	assert.EqualError(t, err, "no storage")
+}
+
+type memoryPlugin struct {
+	*memory.Store
+}
+
+func (p memoryPlugin) SpanReader() spanstore.Reader {
+	return p.Store
+}
+
+func (p memoryPlugin) SpanWriter() spanstore.Writer {
+	return p.Store
+}
+
+func (p memoryPlugin) DependencyReader() dependencystore.Reader {
+	return p.Store
+}
+
+func TestPluginSuite(t *testing.T) {
+	s, err := NewPluginSuite(func() (shared.StoragePlugin, error) {
+		return memoryPlugin{Store: memory.NewStore()}, nil
+	})
+	require.NoError(t, err)
+	s.RunTests(t, "GetServices", "GetTrace", "FindTraces")
+}
+
+func TestArchiveTrace(t *testing.T) {
+	archive := memory.NewStore()
+	s := &Suite{
+		ArchiveSpanWriter: archive,
+		ArchiveSpanReader: archive,
+		Refresh:           func() error { return nil },
+		CleanUp:           func() error { return nil },
+	}
+	s.RunTests(t, "ArchiveTrace")
+}
+
+func TestReport(t *testing.T) {
+	reader := &mocks.Reader{}
+	reader.On("GetServices", mock.Anything).Return(nil, errors.New("storage unavailable"))
+	s := &Suite{
+		SpanWriter: memory.NewStore(),
+		SpanReader: reader,
+		Refresh:    func() error { return nil },
+		CleanUp:    func() error { return nil },
+	}
+	report := s.Report("GetServices", "GetDependencies", "Lock", "Unknown")
+	require.Len(t, report.Results, 4)
+	assert.Equal(t, "GetServices", report.Results[0].Name)
+	assert.Equal(t, StatusFailed, report.Results[0].Status)
+	assert.Contains(t, report.Results[0].Messages[len(report.Results[0].Messages)-1], "storage unavailable")
+	assert.Equal(t, StatusSkipped, report.Results[1].Status)
+	assert.Equal(t, StatusSkipped, report.Results[2].Status)
+	assert.Equal(t, StatusFailed, report.Results[3].Status)
+	assert.Contains(t, report.Results[3].Messages[0], "Unknown test Unknown")
+	assert.Equal(t, 0, report.Count(StatusPassed))
+	assert.Equal(t, 2, report.Count(StatusFailed))
+	assert.False(t, report.Compatible())
+}
+
+func TestRecorder(t *testing.T) {
+	root := &recorder{results: &results{}}
+	assert.True(t, root.Run("pass", func(t T) {
+		t.Logf("ok")
+		assert.True(t, t.Run("sub", func(t T) {}))
+	}))
+	assert.False(t, root.Run("fail", func(t T) {
+		t.Errorf("first")
+		t.Logf("continues")
+	}))
+	assert.False(t, root.Run("failNow", func(t T) {
+		t.FailNow()
+		t.Logf("unreachable")
+	}))
+	assert.False(t, root.Run("panic", func(t T) {
+		panic("boom")
+	}))
+	assert.False(t, root.Run("failedSub", func(t T) {
+		t.Run("sub", func(t T) {
+			t.Fail()
+		})
+	}))
+	assert.True(t, root.Run("skip", func(t T) {
+		t.Skipf("skipped %d", 1)
+		t.Errorf("unreachable")
+	}))
+
+	var actual []Result
+	for _, result := range root.results.list {
+		result.Duration = 0
+		actual = append(actual, result)
+	}
+	assert.Equal(t, []Result{
+		{Name: "pass", Status: StatusPassed, Messages: []string{"ok"}},
+		{Name: "pass/sub", Status: StatusPassed},
+		{Name: "fail", Status: StatusFailed, Messages: []string{"first", "continues"}},
+		{Name: "failNow", Status: StatusFailed},
+		{Name: "panic", Status: StatusFailed, Messages: []string{"panic: boom"}},
+		{Name: "failedSub", Status: StatusFailed},
+		{Name: "failedSub/sub", Status: StatusFailed},
+		{Name: "skip", Status: StatusSkipped, Messages: []string{"skipped 1"}},
+	}, actual)
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("closed")
+}
+
+func TestReportWriteTo(t *testing.T) {
+	report := Report{Results: []Result{
+		{Name: "GetServices", Status: StatusPassed, Messages: []string{"hidden"}},
+		{Name: "FindTraces", Status: StatusFailed, Messages: []string{"first\nsecond"}},
+		{Name: "Lock", Status: StatusSkipped, Messages: []string{"no lock"}},
+	}}
+	buf := &bytes.Buffer{}
+	n, err := report.WriteTo(buf)
+	require.NoError(t, err)
+	assert.EqualValues(t, buf.Len(), n)
+	assert.Equal(t, `PASS  GetServices  0s
+FAIL  FindTraces   0s
+      first
+      second
+SKIP  Lock         0s
+      no lock
+
+1 passed, 1 failed, 1 skipped
+`, buf.String())
+
+	_, err = report.WriteTo(failingWriter{})
+	assert.EqualError(t, err, "closed")
+}
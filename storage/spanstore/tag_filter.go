@@ -0,0 +1,197 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// TagFilterOperator is the comparison applied by a TagFilter.
+type TagFilterOperator string
+
+const (
+	// TagFilterEqual matches spans with a tag whose value equals the filter value.
+	TagFilterEqual TagFilterOperator = "="
+	// TagFilterNotEqual matches spans without a tag whose value equals the filter value,
+	// including the spans that do not have the tag at all.
+	TagFilterNotEqual TagFilterOperator = "!="
+	// TagFilterRegex matches spans with a tag whose value as a whole matches the regular expression in the filter value.
+	// The expression is in the RE2 syntax of the regexp package and is anchored at both ends, i.e. "host" does not
+	// match "host-1". The backends that evaluate regular expressions natively translate the expression into their
+	// own syntax, and reject with ErrTagFilterNotSupported the expressions they cannot evaluate the same way.
+	TagFilterRegex TagFilterOperator = "=~"
+	// TagFilterGreaterThan matches spans with a numeric tag whose value is greater than the filter value.
+	TagFilterGreaterThan TagFilterOperator = ">"
+	// TagFilterLessThan matches spans with a numeric tag whose value is less than the filter value.
+	TagFilterLessThan TagFilterOperator = "<"
+	// TagFilterExists matches spans with a tag of the given key, whatever its value.
+	TagFilterExists TagFilterOperator = "exists"
+)
+
+var (
+	// ErrInvalidTagFilter is returned when a tag filter cannot be parsed or has an invalid value.
+	ErrInvalidTagFilter = errors.New("invalid tag filter")
+
+	// ErrTagFilterNotSupported is returned by readers that cannot evaluate a tag filter operator.
+	ErrTagFilterNotSupported = errors.New("tag filter operator not supported by the storage backend")
+
+	// the binary operators in the order they are looked up in a filter expression,
+	// so that "!=" and "=~" take precedence over "="
+	binaryTagFilterOperators = []TagFilterOperator{
+		TagFilterNotEqual,
+		TagFilterRegex,
+		TagFilterEqual,
+		TagFilterGreaterThan,
+		TagFilterLessThan,
+	}
+)
+
+// TagFilter is a predicate on the span tags, the process tags and the log fields of a span.
+type TagFilter struct {
+	Key      string
+	Operator TagFilterOperator
+	Value    string
+}
+
+// String formats the filter in the syntax accepted by ParseTagFilter.
+func (f TagFilter) String() string {
+	if f.Operator == TagFilterExists {
+		return fmt.Sprintf("exists(%s)", f.Key)
+	}
+	return f.Key + string(f.Operator) + f.Value
+}
+
+// ParseTagFilter parses a tag filter expression, one of "key=value", "key!=value", "key=~regex",
+// "key>number", "key<number" or "exists(key)".
+func ParseTagFilter(s string) (TagFilter, error) {
+	if strings.HasPrefix(s, "exists(") && strings.HasSuffix(s, ")") {
+		filter := TagFilter{Key: s[len("exists(") : len(s)-1], Operator: TagFilterExists}
+		return filter, filter.Validate()
+	}
+	for i := range s {
+		for _, op := range binaryTagFilterOperators {
+			if strings.HasPrefix(s[i:], string(op)) {
+				filter := TagFilter{Key: s[:i], Operator: op, Value: s[i+len(op):]}
+				return filter, filter.Validate()
+			}
+		}
+	}
+	return TagFilter{}, errors.Wrapf(ErrInvalidTagFilter, "no operator in %q", s)
+}
+
+// Validate checks that the filter has a key, a known operator and a value suitable for the operator.
+func (f TagFilter) Validate() error {
+	_, err := f.NewMatcher()
+	return err
+}
+
+// TagMatcher evaluates a TagFilter against the tags of a span, see FlattenTags.
+type TagMatcher func(tags model.KeyValues) bool
+
+// NewMatcher validates the filter and returns the function evaluating it.
+func (f TagFilter) NewMatcher() (TagMatcher, error) {
+	if f.Key == "" {
+		return nil, errors.Wrapf(ErrInvalidTagFilter, "empty key in %q", f.String())
+	}
+	switch f.Operator {
+	case TagFilterEqual:
+		return f.anyTag(func(kv model.KeyValue) bool { return kv.AsString() == f.Value }), nil
+	case TagFilterNotEqual:
+		equal := f.anyTag(func(kv model.KeyValue) bool { return kv.AsString() == f.Value })
+		return func(tags model.KeyValues) bool { return !equal(tags) }, nil
+	case TagFilterRegex:
+		// the expression is checked on its own, so that e.g. "a)|(b" cannot escape the anchors
+		if _, err := regexp.Compile(f.Value); err != nil {
+			return nil, errors.Wrapf(ErrInvalidTagFilter, "bad regular expression in %q: %v", f.String(), err)
+		}
+		re := regexp.MustCompile("^(?:" + f.Value + ")$")
+		return f.anyTag(func(kv model.KeyValue) bool { return re.MatchString(kv.AsString()) }), nil
+	case TagFilterGreaterThan, TagFilterLessThan:
+		bound, err := strconv.ParseFloat(f.Value, 64)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidTagFilter, "expecting a number in %q", f.String())
+		}
+		greater := f.Operator == TagFilterGreaterThan
+		return f.anyTag(func(kv model.KeyValue) bool {
+			v, ok := numericValue(kv)
+			if !ok || v == bound {
+				return false
+			}
+			return (v > bound) == greater
+		}), nil
+	case TagFilterExists:
+		return f.anyTag(func(model.KeyValue) bool { return true }), nil
+	}
+	return nil, errors.Wrapf(ErrInvalidTagFilter, "unknown operator %q", f.Operator)
+}
+
+func (f TagFilter) anyTag(predicate func(kv model.KeyValue) bool) TagMatcher {
+	return func(tags model.KeyValues) bool {
+		// (NB): there can be multiple tags with the same key
+		for _, kv := range tags {
+			if kv.Key == f.Key && predicate(kv) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func numericValue(kv model.KeyValue) (float64, bool) {
+	switch kv.VType {
+	case model.Int64Type:
+		return float64(kv.Int64()), true
+	case model.Float64Type:
+		return kv.Float64(), true
+	case model.StringType:
+		v, err := strconv.ParseFloat(kv.VStr, 64)
+		return v, err == nil
+	}
+	return 0, false
+}
+
+// NewTagMatchers returns the matchers of all the filters, or the error of the first invalid one.
+func NewTagMatchers(filters []TagFilter) ([]TagMatcher, error) {
+	matchers := make([]TagMatcher, len(filters))
+	for i, filter := range filters {
+		matcher, err := filter.NewMatcher()
+		if err != nil {
+			return nil, err
+		}
+		matchers[i] = matcher
+	}
+	return matchers, nil
+}
+
+// FlattenTags returns the span tags, the process tags and the log fields of the span,
+// i.e. all the key-values tag filters are evaluated against.
+func FlattenTags(span *model.Span) model.KeyValues {
+	tags := make(model.KeyValues, 0, len(span.Tags))
+	tags = append(tags, span.Tags...)
+	if span.Process != nil {
+		tags = append(tags, span.Process.Tags...)
+	}
+	for _, log := range span.Logs {
+		tags = append(tags, log.Fields...)
+	}
+	return tags
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2019 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestParseTagFilter(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected TagFilter
+	}{
+		{"http.method=GET", TagFilter{Key: "http.method", Operator: TagFilterEqual, Value: "GET"}},
+		{"error!=true", TagFilter{Key: "error", Operator: TagFilterNotEqual, Value: "true"}},
+		{"http.url=~.*/api/.*", TagFilter{Key: "http.url", Operator: TagFilterRegex, Value: ".*/api/.*"}},
+		{"http.status_code>499", TagFilter{Key: "http.status_code", Operator: TagFilterGreaterThan, Value: "499"}},
+		{"retries<2.5", TagFilter{Key: "retries", Operator: TagFilterLessThan, Value: "2.5"}},
+		{"exists(error)", TagFilter{Key: "error", Operator: TagFilterExists}},
+		{"x=a=b", TagFilter{Key: "x", Operator: TagFilterEqual, Value: "a=b"}},
+		{"x=", TagFilter{Key: "x", Operator: TagFilterEqual, Value: ""}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.input, func(t *testing.T) {
+			filter, err := ParseTagFilter(testCase.input)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, filter)
+			assert.Equal(t, testCase.input, filter.String())
+		})
+	}
+}
+
+func TestParseTagFilterErrors(t *testing.T) {
+	for _, input := range []string{
+		"error",
+		"=true",
+		"exists()",
+		"x=~(",
+		"x=~a)|(b",
+		"x>abc",
+		"x<",
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseTagFilter(input)
+			require.Error(t, err)
+			assert.Equal(t, ErrInvalidTagFilter, errors.Cause(err))
+		})
+	}
+}
+
+func TestTagFilterUnknownOperator(t *testing.T) {
+	err := TagFilter{Key: "x", Operator: "~~", Value: "y"}.Validate()
+	assert.EqualError(t, err, `unknown operator "~~": invalid tag filter`)
+}
+
+func TestTagFilterMatcher(t *testing.T) {
+	span := &model.Span{
+		Tags: model.KeyValues{
+			model.String("http.method", "GET"),
+			model.Int64("http.status_code", 503),
+			model.String("retries", "3"),
+		},
+		Process: &model.Process{
+			Tags: model.KeyValues{model.String("hostname", "host-1")},
+		},
+		Logs: []model.Log{
+			{Fields: model.KeyValues{model.Float64("latency", 0.25), model.String("event", "error")}},
+			{Fields: model.KeyValues{model.String("event", "retry")}},
+		},
+	}
+	testCases := []struct {
+		filter   string
+		expected bool
+	}{
+		{"http.method=GET", true},
+		{"http.method=POST", false},
+		{"http.status_code=503", true},
+		{"hostname=host-1", true},
+		{"event=retry", true},
+		{"http.method!=POST", true},
+		{"http.method!=GET", false},
+		{"missing!=x", true},
+		{"hostname=~host-[0-9]+", true},
+		{"hostname=~host", false},
+		{"hostname=~^host-1$", true},
+		{"hostname=~host|x", false},
+		{"event=~err.*", true},
+		{"http.status_code>499", true},
+		{"http.status_code>503", false},
+		{"http.status_code<600", true},
+		{"latency<0.5", true},
+		{"latency>0.5", false},
+		{"retries>2", true},
+		{"http.method>0", false},
+		{"exists(hostname)", true},
+		{"exists(latency)", true},
+		{"exists(missing)", false},
+	}
+	tags := FlattenTags(span)
+	for _, testCase := range testCases {
+		t.Run(testCase.filter, func(t *testing.T) {
+			filter, err := ParseTagFilter(testCase.filter)
+			require.NoError(t, err)
+			matcher, err := filter.NewMatcher()
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, matcher(tags))
+		})
+	}
+}
+
+func TestNewTagMatchers(t *testing.T) {
+	matchers, err := NewTagMatchers([]TagFilter{
+		{Key: "a", Operator: TagFilterEqual, Value: "1"},
+		{Key: "b", Operator: TagFilterExists},
+	})
+	require.NoError(t, err)
+	assert.Len(t, matchers, 2)
+
+	_, err = NewTagMatchers([]TagFilter{
+		{Key: "a", Operator: TagFilterEqual, Value: "1"},
+		{Key: "b", Operator: TagFilterGreaterThan, Value: "x"},
+	})
+	assert.EqualError(t, err, `expecting a number in "b>x": invalid tag filter`)
+}
+
+func TestFlattenTags(t *testing.T) {
+	span := &model.Span{
+		Tags:    make(model.KeyValues, 1, 10),
+		Process: &model.Process{Tags: model.KeyValues{model.String("hostname", "host-1")}},
+		Logs:    []model.Log{{Fields: model.KeyValues{model.String("event", "x")}}},
+	}
+	span.Tags[0] = model.String("a", "b")
+	tags := FlattenTags(span)
+	assert.Equal(t, model.KeyValues{model.String("a", "b"), model.String("hostname", "host-1"), model.String("event", "x")}, tags)
+	// the spare capacity of the span tags is not written to
+	assert.Equal(t, model.KeyValue{}, span.Tags[:2][1])
+}